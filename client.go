@@ -0,0 +1,1475 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package gopcua
+
+import (
+	"context"
+	"crypto/rsa"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wmnsk/gopcua/datatypes"
+	"github.com/wmnsk/gopcua/errors"
+	"github.com/wmnsk/gopcua/services"
+	"github.com/wmnsk/gopcua/status"
+	"github.com/wmnsk/gopcua/uacp"
+	"github.com/wmnsk/gopcua/uasc"
+)
+
+// ErrNotConnected is returned when a request is made on a Client
+// which is not connected or has already been closed.
+var ErrNotConnected = errors.New("client is not connected")
+
+// ErrTimeout is returned when the response does not arrive within the timeout
+// set by WithRequestTimeout.
+var ErrTimeout = errors.New("request timed out")
+
+// ServiceError is returned when the server responds to a request with a bad ServiceResult,
+// including the ServiceFault returned when the request is rejected as a whole.
+// Diagnostics is set if the server returns the ServiceDiagnostics requested by
+// WithReturnDiagnostics or RequestOptions.
+type ServiceError struct {
+	Code        uint32
+	Diagnostics *services.ResolvedDiagnosticInfo
+}
+
+// Error returns the ServiceResult of ServiceError, with the Diagnostics if any.
+func (e *ServiceError) Error() string {
+	if e.Diagnostics != nil {
+		return fmt.Sprintf("service failed with status %v: %v", status.StatusCode(e.Code), e.Diagnostics)
+	}
+	return fmt.Sprintf("service failed with status %v", status.StatusCode(e.Code))
+}
+
+// defaultRequestTimeout is the timeout of the requests used by default.
+const defaultRequestTimeout = 10 * time.Second
+
+// closeTimeout is the timeout of the requests sent by Close to clean up the Session.
+const closeTimeout = time.Second
+
+// Client is a high-level OPC UA client which establishes the UACP Connection,
+// the SecureChannel and the Session on Connect and provides the services on top of them.
+type Client struct {
+	endpoint string
+	cfg      *uasc.Config
+	sessCfg  *uasc.SessionConfig
+
+	// cert and key are the application instance certificate given by the options,
+	// which override the ones in cfg on Connect.
+	cert []byte
+	key  *rsa.PrivateKey
+	// security is the SecurityPolicy, the MessageSecurityMode and the Certificate
+	// of the server given by WithSecurityPolicy, which override the ones in cfg on Connect.
+	security *security
+	// trust and appURI are the TrustStore and the ApplicationUri of the server given by
+	// WithTrustStore, and insecure is set by WithInsecureSkipVerify.
+	trust    *uasc.TrustStore
+	appURI   string
+	insecure bool
+	// identity is the UserIdentityToken given by the options, which overrides the one
+	// in sessCfg on Connect. identityKey is the private key of X509IdentityToken.
+	identity    datatypes.UserIdentityToken
+	identityKey *rsa.PrivateKey
+	// optErr is the error occurred while applying the options, returned on Connect.
+	optErr error
+
+	mu      sync.Mutex
+	handle  uint32
+	conn    net.Conn
+	secChan *uasc.SecureChannel
+	session *uasc.Session
+	waiters *waiters
+
+	// subMu guards the Subscriptions and the state of PublishRequests below,
+	// which are updated by monitor without c.mu.
+	subMu sync.Mutex
+	// publishRequests is the number of PublishRequests kept outstanding.
+	publishRequests int
+	subs            map[uint32]*Subscription
+	acks            []*datatypes.SubscriptionAcknowledgement
+	outstanding     int
+	// deleting is the IDs of the Subscriptions in the DeleteSubscriptionsRequests
+	// in flight, keyed by their RequestHandles.
+	deleting map[uint32][]uint32
+
+	// transport is the function to connect to the endpoint set by WithTransport.
+	transport func(ctx context.Context, endpoint string) (net.Conn, error)
+	// dialer is the Dialer of the TCP connection set by WithDialer.
+	dialer uacp.Dialer
+
+	// timeout is the timeout of each request set by WithRequestTimeout.
+	timeout time.Duration
+	// reqOpts are the default RequestOptions set by WithAuditEntryID and WithReturnDiagnostics.
+	reqOpts RequestOptions
+
+	// nsMu guards the NamespaceArray of the server read on activation of the Session,
+	// and nsIndex is the index of each NamespaceURI in it.
+	nsMu       sync.Mutex
+	namespaces []string
+	nsIndex    map[string]uint16
+	// logger is the Logger set by WithLogger.
+	logger Logger
+
+	// retry is the policy of retrying the requests set by WithRetry.
+	retry *RetryPolicy
+
+	// backoff is the policy of reconnection set by WithReconnect, and
+	// onState is the callback set by WithStateCallback.
+	backoff Backoff
+	onState func(ConnState)
+	// stop is closed by Close to stop reconnecting to the endpoint.
+	stop chan struct{}
+
+	// keepAlive is the interval of the health check set by WithKeepAlive, and
+	// onKeepAlive is the callback set by WithKeepAliveCallback.
+	keepAlive   time.Duration
+	onKeepAlive func(error)
+	// healthy is 1 while connected and the last health check has succeeded.
+	healthy int32
+}
+
+// Option is an option to configure the Client.
+type Option func(*Client)
+
+// WithConfig sets the configuration of SecureChannel used by the Client.
+func WithConfig(cfg *uasc.Config) Option {
+	return func(c *Client) {
+		c.cfg = cfg
+	}
+}
+
+// WithSessionConfig sets the configuration of Session used by the Client.
+func WithSessionConfig(cfg *uasc.SessionConfig) Option {
+	return func(c *Client) {
+		c.sessCfg = cfg
+	}
+}
+
+// WithRequestTimeout sets the timeout of each request, which is 10 seconds by default.
+//
+// The timeout is sent to the server as the TimeoutHint in the RequestHeader, and the request
+// is aborted with ErrTimeout if the response does not arrive in time. A shorter deadline of
+// the context given to the methods takes precedence. If d is 0, the requests never time out.
+func WithRequestTimeout(d time.Duration) Option {
+	return func(c *Client) {
+		c.timeout = d
+	}
+}
+
+// WithTransport makes the Client connect to the endpoint on the net.Conn returned by dial
+// instead of the TCP connection, e.g. the in-process one of uatest.Server in tests.
+// The UACP connection, the SecureChannel and the Session are established on it as usual.
+func WithTransport(dial func(ctx context.Context, endpoint string) (net.Conn, error)) Option {
+	return func(c *Client) {
+		c.transport = dial
+	}
+}
+
+// WithDialer makes the Client establish the TCP connection to the endpoint with d, e.g. the
+// SOCKS5 proxy.Dialer of golang.org/x/net/proxy, before the Hello handshake. The host in
+// the endpoint is passed to d as it is, so that the proxy can resolve it.
+//
+// WithTransport takes precedence over this.
+func WithDialer(d uacp.Dialer) Option {
+	return func(c *Client) {
+		c.dialer = d
+	}
+}
+
+// WithAuditEntryID sets the AuditEntryId in the RequestHeaders of all the requests
+// sent by the Client, which the server records in its audit events.
+func WithAuditEntryID(auditID string) Option {
+	return func(c *Client) {
+		c.reqOpts.AuditEntryID = auditID
+	}
+}
+
+// WithReturnDiagnostics sets the ReturnDiagnostics bitmask in the RequestHeaders of all
+// the requests sent by the Client, e.g. services.ReturnDiagnosticsAll, to ask the server
+// for the DiagnosticInfos in the responses.
+func WithReturnDiagnostics(diag uint32) Option {
+	return func(c *Client) {
+		c.reqOpts.ReturnDiagnostics = diag
+	}
+}
+
+// WithCertificate sets the application instance certificate of the Client and its RSA private key,
+// which are used to secure the SecureChannel. Both should be PEM encoded.
+//
+// The private key may be in either PKCS #1 or PKCS #8 form. An error in loading them,
+// including the mismatch between the certificate and the key, is returned on Connect.
+func WithCertificate(certPEM, keyPEM []byte) Option {
+	return func(c *Client) {
+		c.setCertificate(tls.X509KeyPair(certPEM, keyPEM))
+	}
+}
+
+// WithCertificateFile is the same as WithCertificate but loads the PEM encoded
+// certificate and private key from the files given.
+func WithCertificateFile(certPath, keyPath string) Option {
+	return func(c *Client) {
+		c.setCertificate(tls.LoadX509KeyPair(certPath, keyPath))
+	}
+}
+
+// security is the way to secure the SecureChannel set by WithSecurityPolicy.
+type security struct {
+	policyURI  string
+	mode       uint32
+	serverCert []byte
+}
+
+// WithSecurityPolicy opens the SecureChannel with the SecurityPolicy and the MessageSecurityMode
+// given, e.g. Basic256Sha256 and services.SecModeSign. The serverCert is the DER encoded
+// Certificate of the server which is available in its EndpointDescription.
+//
+// The messages are signed but not encrypted in the mode Sign, while they are both signed and
+// encrypted in SignAndEncrypt. The certificate of the Client is given by WithCertificate, and
+// the serverCert is verified with the TrustStore given by WithTrustStore.
+func WithSecurityPolicy(policyURI string, mode uint32, serverCert []byte) Option {
+	return func(c *Client) {
+		c.security = &security{policyURI: policyURI, mode: mode, serverCert: serverCert}
+	}
+}
+
+// WithTrustStore verifies the Certificate of the server given by WithSecurityPolicy with the
+// TrustStore on Connect, which fails if it is expired, untrusted, or does not contain the
+// serverURI, the ApplicationUri in the EndpointDescription, unless the serverURI is empty.
+//
+// Either WithTrustStore or WithInsecureSkipVerify is required if the SecurityPolicy is not None.
+func WithTrustStore(store *uasc.TrustStore, serverURI string) Option {
+	return func(c *Client) {
+		c.trust, c.appURI = store, serverURI
+	}
+}
+
+// WithInsecureSkipVerify accepts any Certificate of the server given by WithSecurityPolicy.
+// It makes the connection vulnerable to the man-in-the-middle attacks and should be used
+// only for testing.
+func WithInsecureSkipVerify() Option {
+	return func(c *Client) {
+		c.insecure = true
+	}
+}
+
+// WithAnonymous activates the Session with AnonymousIdentityToken, which is the default.
+func WithAnonymous() Option {
+	return func(c *Client) {
+		c.identity = datatypes.NewAnonymousIdentityToken("anonymous")
+	}
+}
+
+// WithUserName activates the Session with UserNameIdentityToken of the user and the password.
+//
+// The password is encrypted with the nonce of the server as the UserTokenPolicy of
+// the Endpoint requires, unless its SecurityPolicy is None.
+func WithUserName(user, password string) Option {
+	return func(c *Client) {
+		c.identity = datatypes.NewUserNameIdentityToken("", user, []byte(password), "")
+	}
+}
+
+// WithX509Identity activates the Session with X509IdentityToken of the user certificate,
+// whose private key signs the Certificate and the nonce of the server as the UserTokenPolicy
+// of the Endpoint requires. Both should be PEM encoded, as in WithCertificate.
+func WithX509Identity(certPEM, keyPEM []byte) Option {
+	return func(c *Client) {
+		cert, key, err := rsaKeyPair(tls.X509KeyPair(certPEM, keyPEM))
+		if err != nil {
+			c.optErr = err
+			return
+		}
+		c.identity = datatypes.NewX509IdentityToken("", string(cert))
+		c.identityKey = key
+	}
+}
+
+func (c *Client) setCertificate(cert tls.Certificate, err error) {
+	der, key, err := rsaKeyPair(cert, err)
+	if err != nil {
+		c.optErr = err
+		return
+	}
+	c.cert, c.key = der, key
+}
+
+// rsaKeyPair returns the DER encoded certificate and the RSA private key of the key pair
+// loaded with err, which is returned as it is.
+func rsaKeyPair(cert tls.Certificate, err error) ([]byte, *rsa.PrivateKey, error) {
+	if err != nil {
+		return nil, nil, err
+	}
+
+	key, ok := cert.PrivateKey.(*rsa.PrivateKey)
+	if !ok {
+		return nil, nil, errors.NewErrUnsupported(cert.PrivateKey, "private key should be RSA")
+	}
+	return cert.Certificate[0], key, nil
+}
+
+// NewClient creates a new Client for the endpoint given.
+//
+// Without options, the Client opens SecureChannel with security mode None
+// and activates the Session with AnonymousIdentityToken.
+func NewClient(endpoint string, opts ...Option) *Client {
+	c := &Client{
+		endpoint: endpoint,
+		cfg:      uasc.NewClientConfigSecurityNone(3333, 3600000),
+		sessCfg: uasc.NewClientSessionConfig(
+			[]string{"en-US"},
+			datatypes.NewAnonymousIdentityToken("anonymous"),
+		),
+		publishRequests: defaultPublishRequests,
+		timeout:         defaultRequestTimeout,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Connect establishes the connection to the endpoint and activates the Session.
+//
+// The ctx is passed to the underlying Conn, SecureChannel and Session,
+// which keep monitoring the incoming messages until ctx is done.
+func (c *Client) Connect(ctx context.Context) error {
+	if err := c.prepare(); err != nil {
+		return err
+	}
+
+	conn, err := c.dial(ctx)
+	if err != nil {
+		return err
+	}
+	if err := c.open(ctx, conn); err != nil {
+		conn.Close()
+		return err
+	}
+	return nil
+}
+
+// dial establishes the UACP connection to the endpoint, on the transport set by
+// WithTransport or with the Dialer set by WithDialer if any.
+func (c *Client) dial(ctx context.Context) (*uacp.Conn, error) {
+	if c.transport == nil {
+		if c.dialer != nil {
+			return uacp.DialWithDialer(ctx, c.endpoint, c.dialer)
+		}
+		return uacp.Dial(ctx, c.endpoint)
+	}
+
+	lowerConn, err := c.transport(ctx, c.endpoint)
+	if err != nil {
+		return nil, err
+	}
+	return uacp.DialConn(ctx, lowerConn, c.endpoint)
+}
+
+// prepare returns the error in applying the options, and sets the security, the certificate,
+// the Logger and the UserIdentityToken given by the options to the configurations.
+//
+// The configurations are copied before being updated, as the ones given by WithConfig and
+// WithSessionConfig may be shared with the other Clients, e.g. the ones in a Pool.
+func (c *Client) prepare() error {
+	if c.optErr != nil {
+		return c.optErr
+	}
+	cfg, sessCfg := *c.cfg, *c.sessCfg
+	c.cfg, c.sessCfg = &cfg, &sessCfg
+	if sec := c.security; sec != nil {
+		c.cfg.SecurityPolicyURI, c.cfg.SecurityMode = sec.policyURI, sec.mode
+		c.cfg.RemoteCertificate, c.cfg.Thumbprint = sec.serverCert, uasc.Thumbprint(sec.serverCert)
+		if sec.policyURI != uasc.SecurityPolicyURINone && c.trust == nil && !c.insecure {
+			return errors.New("server certificate cannot be verified without WithTrustStore or WithInsecureSkipVerify")
+		}
+	}
+	if c.trust != nil {
+		c.cfg.TrustStore, c.cfg.RemoteApplicationURI = c.trust, c.appURI
+	}
+	if c.cert != nil {
+		c.cfg.Certificate, c.cfg.PrivateKey = c.cert, c.key
+	}
+	if c.logger != nil {
+		c.cfg.Logger = c.logger
+	}
+	if c.identity != nil {
+		c.sessCfg.UserIdentityToken = c.identity
+		c.sessCfg.UserTokenPrivateKey = c.identityKey
+	}
+	return nil
+}
+
+func (c *Client) open(ctx context.Context, conn net.Conn) error {
+	// the Configs are updated by the SecureChannel and the Session, which should not be
+	// shared with the other connections.
+	cfg, sessCfg := *c.cfg, *c.sessCfg
+	secChan, err := uasc.OpenSecureChannel(ctx, conn, &cfg, 5*time.Second, 3)
+	if err != nil {
+		return err
+	}
+
+	session, err := uasc.CreateSession(ctx, secChan, &sessCfg, 3, 5*time.Second)
+	if err != nil {
+		secChan.Close()
+		return err
+	}
+	if err := session.Activate(); err != nil {
+		secChan.Close()
+		return err
+	}
+
+	c.mu.Lock()
+	c.stop = make(chan struct{})
+	c.start(ctx, conn, secChan, session)
+	c.updateNamespaces(ctx)
+	c.mu.Unlock()
+
+	c.setState(StateConnected)
+	return nil
+}
+
+// start makes the Client send the requests on session and starts monitoring it.
+//
+// This should be called with c.mu held.
+func (c *Client) start(ctx context.Context, conn net.Conn, secChan *uasc.SecureChannel, session *uasc.Session) {
+	c.conn, c.secChan, c.session = conn, secChan, session
+	c.waiters = newWaiters()
+	c.setHealthy(true)
+	go c.monitor(ctx, session, c.waiters)
+	if c.keepAlive > 0 {
+		go c.checkHealth(ctx, c.stop, session)
+	}
+}
+
+// monitor reads the responses from session and passes them to send()
+// until the session is closed. PublishResponses are handled by the Subscriptions instead.
+//
+// If the session is lost without Close, e.g. the connection is reset, the Client
+// tries to reconnect as the Backoff set by WithReconnect tells.
+func (c *Client) monitor(ctx context.Context, session *uasc.Session, w *waiters) {
+	err := c.receive(session, w)
+	// send() waiting for a response returns err, or ErrNotConnected, and releases c.mu.
+	w.close(err)
+
+	c.mu.Lock()
+	if c.session != session {
+		// closed by Close.
+		c.mu.Unlock()
+		c.closeSubscriptions()
+		c.setState(StateClosed)
+		return
+	}
+	conn, secChan, stop := c.conn, c.secChan, c.stop
+	c.conn, c.secChan, c.session = nil, nil, nil
+	c.mu.Unlock()
+	conn.Close()
+	c.setHealthy(false)
+
+	// the PublishRequests are discarded with the SecureChannel.
+	c.subMu.Lock()
+	c.outstanding = 0
+	c.subMu.Unlock()
+
+	c.setState(StateDisconnected)
+	reconnected := c.backoff != nil && c.reconnect(ctx, stop, session)
+	secChan.Close()
+	if reconnected {
+		return
+	}
+
+	c.mu.Lock()
+	if c.stop == stop {
+		c.stop = nil
+	}
+	c.mu.Unlock()
+	c.closeSubscriptions()
+	c.setState(StateClosed)
+}
+
+// receive reads the responses from session until it fails, and passes them to
+// the waiters of their RequestHandles.
+//
+// The error is returned if the response is rejected by the security checks of the
+// SecureChannel, and nil if the session is lost otherwise.
+func (c *Client) receive(session *uasc.Session, w *waiters) error {
+	buf := make([]byte, 0xffff)
+	for {
+		n, err := session.ReadService(buf)
+		if err != nil {
+			if err == io.ErrShortBuffer {
+				// the response is read again after extending buf.
+				buf = make([]byte, n)
+				continue
+			}
+			if e, ok := err.(*uasc.AbortError); ok {
+				// only the request aborted fails, and the others are still served.
+				if !w.abort(e.RequestHandle, e) {
+					c.log().Debug("dropped abort", "error", e)
+				}
+				continue
+			}
+			if isRejected(err) {
+				return err
+			}
+			return nil
+		}
+
+		// the decoded response refers to the bytes it is decoded from,
+		// so it should not share buf which is overwritten by the next read.
+		b := make([]byte, n)
+		copy(b, buf[:n])
+		res, err := services.Decode(b)
+		if err != nil {
+			continue
+		}
+		switch r := res.(type) {
+		case *services.PublishResponse:
+			c.handlePublish(r)
+			continue
+		case *services.DeleteSubscriptionsResponse:
+			c.handleDeleteSubscriptions(r)
+		}
+		if !w.deliver(res) {
+			// e.g. the late response to the request timed out, or the CancelResponse.
+			c.log().Debug("dropped response", "type", fmt.Sprintf("%T", res))
+		}
+	}
+}
+
+// isRejected reports whether err is returned by the Session as the response is rejected
+// by the security checks of the SecureChannel.
+func isRejected(err error) bool {
+	switch errors.Cause(err) {
+	case uasc.ErrInvalidMessageSignature, uasc.ErrInvalidSequenceNumber, uasc.ErrUnexpectedRequestID:
+		return true
+	}
+	return false
+}
+
+// waiters are the channels of send() waiting for the responses by their RequestHandles.
+// A waiters is created for each connection, and closed when the connection is lost.
+type waiters struct {
+	mu     sync.Mutex
+	chans  map[uint32]chan response
+	closed bool
+}
+
+// response is the response passed to a waiter, or the error if the request is aborted.
+type response struct {
+	res services.Service
+	err error
+}
+
+func newWaiters() *waiters {
+	return &waiters{chans: map[uint32]chan response{}}
+}
+
+// add returns the channel which receives the response to the request with handle,
+// or nil if the connection is already lost.
+func (w *waiters) add(handle uint32) chan response {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return nil
+	}
+	ch := make(chan response, 1)
+	w.chans[handle] = ch
+	return ch
+}
+
+// remove removes the channel for handle.
+func (w *waiters) remove(handle uint32) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	delete(w.chans, handle)
+}
+
+// deliver passes res to the waiter of its RequestHandle without blocking.
+// It returns false if no one is waiting for res.
+func (w *waiters) deliver(res services.Service) bool {
+	r, ok := res.(interface {
+		Header() *services.ResponseHeader
+	})
+	if !ok {
+		return false
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.pass(r.Header().RequestHandle, response{res: res})
+}
+
+// abort passes err to the waiter of the request with handle without blocking.
+// It returns false if no one is waiting for it.
+func (w *waiters) abort(handle uint32, err error) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.pass(handle, response{err: err})
+}
+
+// pass passes r to the waiter of handle, which is removed.
+//
+// This should be called with w.mu held.
+func (w *waiters) pass(handle uint32, r response) bool {
+	ch, ok := w.chans[handle]
+	if !ok {
+		return false
+	}
+	delete(w.chans, handle)
+	ch <- r
+	return true
+}
+
+// close passes err to all the waiters and closes their channels, which return
+// ErrNotConnected if err is nil.
+func (w *waiters) close(err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.closed = true
+	for handle, ch := range w.chans {
+		if err != nil {
+			ch <- response{err: err}
+		}
+		close(ch)
+		delete(w.chans, handle)
+	}
+}
+
+// Close closes the Session, SecureChannel and the underlying connection.
+// If the Client is reconnecting to the endpoint, it stops reconnecting.
+//
+// The Subscriptions created by the Client are deleted on the server first, then
+// the Session is closed after the CloseSessionResponse arrives and the SecureChannel
+// is closed, so that nothing is left on the server until its lifetime expires.
+// Each step is given up after a short timeout, and the connection is closed anyway.
+// The errors in the steps are returned together.
+func (c *Client) Close() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	reconnecting := c.stop != nil && c.session == nil
+	if c.stop != nil {
+		close(c.stop)
+		c.stop = nil
+	}
+	if c.session == nil {
+		if reconnecting {
+			return nil
+		}
+		return ErrNotConnected
+	}
+
+	var errs closeError
+	if ids := c.subscriptionIDs(); len(ids) > 0 {
+		ctx, cancel := context.WithTimeout(context.Background(), closeTimeout)
+		if _, err := c.deleteSubscriptions(ctx, ids); err != nil {
+			errs = append(errs, errors.Errorf("failed to delete subscriptions: %s", err))
+		}
+		cancel()
+	}
+
+	// the writes should not block closing when the server stops reading.
+	c.conn.SetWriteDeadline(time.Now().Add(closeTimeout))
+	ctx, cancel := context.WithTimeout(context.Background(), closeTimeout)
+	if err := c.session.CloseWithContext(ctx); err != nil {
+		errs = append(errs, errors.Errorf("failed to close session: %s", err))
+	}
+	cancel()
+
+	c.conn.SetWriteDeadline(time.Now().Add(closeTimeout))
+	if err := c.secChan.Close(); err != nil {
+		errs = append(errs, errors.Errorf("failed to close secure channel: %s", err))
+	}
+
+	if err := c.conn.Close(); err != nil {
+		errs = append(errs, err)
+	}
+
+	c.conn, c.secChan, c.session = nil, nil, nil
+	c.setHealthy(false)
+	if len(errs) == 0 {
+		return nil
+	}
+	return errs
+}
+
+// closeError is the errors occurred in the steps of Close.
+type closeError []error
+
+// Error returns the errors joined in a string.
+func (e closeError) Error() string {
+	s := make([]string, len(e))
+	for i, err := range e {
+		s[i] = err.Error()
+	}
+	return strings.Join(s, "; ")
+}
+
+// requestHeader returns a new RequestHeader for the active Session.
+//
+// This should be called with c.mu held.
+func (c *Client) requestHeader() *services.RequestHeader {
+	c.handle++
+	return services.NewRequestHeader(
+		c.session.AuthenticationToken(), time.Now(), c.handle, c.reqOpts.ReturnDiagnostics,
+		timeoutHint(c.timeout), c.reqOpts.AuditEntryID, services.NewNullAdditionalHeader(), nil,
+	)
+}
+
+// RequestOptions are the fields of the RequestHeader set on the requests.
+//
+// The DiagnosticInfos requested by ReturnDiagnostics are returned in the ServiceDiagnostics
+// of the ResponseHeader and the DiagnosticInfos of the response, which can be resolved with
+// ResponseHeader.ResolveDiagnosticInfo. The error returned on a failed service also has them.
+type RequestOptions struct {
+	AuditEntryID      string
+	ReturnDiagnostics uint32
+}
+
+type requestOptionsKey struct{}
+
+// ContextWithRequestOptions returns a copy of ctx with opts, which are set on the request
+// sent by the XWithContext methods of the Client with it, instead of the defaults set by
+// WithAuditEntryID and WithReturnDiagnostics. The zero fields in opts keep the defaults.
+func ContextWithRequestOptions(ctx context.Context, opts RequestOptions) context.Context {
+	return context.WithValue(ctx, requestOptionsKey{}, opts)
+}
+
+// applyRequestOptions sets the RequestOptions in ctx on the RequestHeader h.
+func applyRequestOptions(ctx context.Context, h *services.RequestHeader) {
+	opts, ok := ctx.Value(requestOptionsKey{}).(RequestOptions)
+	if !ok {
+		return
+	}
+	if opts.AuditEntryID != "" {
+		h.AuditEntryID = datatypes.NewString(opts.AuditEntryID)
+	}
+	if opts.ReturnDiagnostics != 0 {
+		h.ReturnDiagnostics = opts.ReturnDiagnostics
+	}
+}
+
+// timeoutHint returns d in milliseconds as the TimeoutHint, where 0 means no timeout.
+func timeoutHint(d time.Duration) uint32 {
+	if d <= 0 {
+		return 0
+	}
+	if ms := d / time.Millisecond; ms < math.MaxUint32 {
+		return uint32(ms)
+	}
+	return math.MaxUint32
+}
+
+// send sends req with sendOnce, and sends it again with a new RequestHandle while it fails
+// with an error retryable by the RetryPolicy set by WithRetry.
+//
+// This should be called with c.mu held. It is released while waiting to retry, so that
+// the other requests and Close are not blocked, and ErrNotConnected is returned if the
+// Client is closed or disconnected in the meantime.
+func (c *Client) send(ctx context.Context, req services.Service, handle uint32) (services.Service, error) {
+	res, err := c.sendOnce(ctx, req, handle)
+	if c.retry == nil {
+		return res, err
+	}
+
+	r, ok := req.(interface {
+		Header() *services.RequestHeader
+	})
+	if !ok {
+		return res, err
+	}
+
+	start := time.Now()
+	for attempt := 0; err != nil && c.retry.retryable(err); attempt++ {
+		wait, ok := c.retry.wait(attempt, time.Since(start))
+		if !ok {
+			break
+		}
+		c.log().Debug("retrying request", "handle", r.Header().RequestHandle, "attempt", attempt, "error", err)
+
+		if err := c.waitRetry(ctx, wait); err != nil {
+			return nil, err
+		}
+
+		c.handle++
+		r.Header().RequestHandle = c.handle
+		r.Header().Timestamp = time.Now()
+		res, err = c.sendOnce(ctx, req, c.handle)
+	}
+	return res, err
+}
+
+// waitRetry waits for d without holding c.mu, and returns ctx.Err() if ctx is done or
+// ErrNotConnected if the Client is closed or disconnected before d elapses.
+//
+// This should be called with c.mu held, which is held again when it returns.
+func (c *Client) waitRetry(ctx context.Context, d time.Duration) error {
+	stop := c.stop
+	c.mu.Unlock()
+
+	var err error
+	t := time.NewTimer(d)
+	select {
+	case <-ctx.Done():
+		err = ctx.Err()
+	case <-stop:
+		err = ErrNotConnected
+	case <-t.C:
+	}
+	t.Stop()
+
+	c.mu.Lock()
+	if err == nil && c.session == nil {
+		err = ErrNotConnected
+	}
+	return err
+}
+
+// sendOnce sends req and waits for the response which has the same RequestHandle.
+//
+// If ctx is done before the response arrives, send issues a CancelRequest for
+// the in-flight request and returns ctx.Err(). The late response, if any, is
+// discarded by monitor as no one is waiting for it.
+//
+// The request is aborted in the same way with ErrTimeout if the response does not arrive
+// within the timeout of the Client. The TimeoutHint of req is set to the earlier of
+// the timeout and the deadline of ctx.
+//
+// If the server aborts the response, the *uasc.AbortError is returned while the
+// connection is kept for the other requests. If the response is rejected by the security
+// checks of the SecureChannel, e.g. with uasc.ErrInvalidMessageSignature, the error is
+// returned to all the requests in flight as the connection is closed.
+//
+// This should be called with c.mu held.
+func (c *Client) sendOnce(ctx context.Context, req services.Service, handle uint32) (services.Service, error) {
+	tctx := ctx
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		tctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+	if r, ok := req.(interface {
+		Header() *services.RequestHeader
+	}); ok {
+		applyRequestOptions(ctx, r.Header())
+		if d, ok := tctx.Deadline(); ok {
+			// at least 1ms so that the request about to expire is not taken as no timeout.
+			r.Header().TimeoutHint = timeoutHint(time.Until(d))
+			if r.Header().TimeoutHint == 0 {
+				r.Header().TimeoutHint = 1
+			}
+		}
+	}
+
+	b, err := req.Serialize()
+	if err != nil {
+		return nil, err
+	}
+	resChan := c.waiters.add(handle)
+	if resChan == nil {
+		return nil, ErrNotConnected
+	}
+	defer c.waiters.remove(handle)
+
+	c.log().Debug("sending request", "handle", handle, "message", dumpMessage{req})
+	if _, err := c.session.WriteService(b); err != nil {
+		return nil, err
+	}
+
+	select {
+	case <-tctx.Done():
+		c.cancel(handle)
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		return nil, ErrTimeout
+	case resp, ok := <-resChan:
+		if !ok {
+			return nil, ErrNotConnected
+		}
+		if resp.err != nil {
+			return nil, resp.err
+		}
+		res := resp.res
+		r := res.(interface {
+			Header() *services.ResponseHeader
+		})
+		c.log().Debug("received response", "handle", handle, "message", dumpMessage{res})
+
+		_, fault := res.(*services.ServiceFault)
+		if h := r.Header(); h.ServiceResult != 0 || fault {
+			e := &ServiceError{Code: h.ServiceResult}
+			if e.Code == 0 {
+				// the ServiceFault should have a bad ServiceResult.
+				e.Code = status.BadUnknownResponse
+			}
+			if d := h.ServiceDiagnostics; d != nil && d.EncodingMask != 0 {
+				e.Diagnostics = h.ResolveDiagnosticInfo(d)
+			}
+			return nil, e
+		}
+		return res, nil
+	}
+}
+
+// cancel asks the server to cancel the request with the handle given.
+// It does not wait for the CancelResponse and the errors are ignored
+// as it is only a best-effort attempt.
+//
+// This should be called with c.mu held.
+func (c *Client) cancel(handle uint32) {
+	b, err := services.NewCancelRequest(c.requestHeader(), handle).Serialize()
+	if err != nil {
+		return
+	}
+	c.session.WriteService(b)
+}
+
+// Read sends a ReadRequest for the nodes given and returns the ReadResponse.
+//
+// maxAge is the maximum age in milliseconds of the values the server may return
+// from its cache. 0 makes the server read the values from the data source, and
+// math.MaxInt32 or greater lets it return any value cached.
+func (c *Client) Read(maxAge float64, tsRet services.TimestampsToReturn, nodes ...*datatypes.ReadValueID) (*services.ReadResponse, error) {
+	return c.ReadWithContext(context.Background(), maxAge, tsRet, nodes...)
+}
+
+// ReadWithContext is the same as Read but returns ctx.Err() if ctx is done
+// before the ReadResponse arrives.
+func (c *Client) ReadWithContext(ctx context.Context, maxAge float64, tsRet services.TimestampsToReturn, nodes ...*datatypes.ReadValueID) (*services.ReadResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.session == nil {
+		return nil, ErrNotConnected
+	}
+
+	h := c.requestHeader()
+	res, err := c.send(ctx, services.NewReadRequest(h, maxAge, tsRet, nodes...), h.RequestHandle)
+	if err != nil {
+		return nil, err
+	}
+
+	r, ok := res.(*services.ReadResponse)
+	if !ok {
+		return nil, errors.NewErrInvalidType(res, "read", "should be ReadResponse")
+	}
+	return r, nil
+}
+
+// Write sends a WriteRequest for the nodes given and returns the WriteResponse.
+//
+// The values are written to the Attribute of each WriteValue, which can be other
+// than the Value, e.g. the DisplayName. An error is returned without sending the
+// WriteRequest if a value is not of the DataType of the Attribute.
+func (c *Client) Write(nodes ...*datatypes.WriteValue) (*services.WriteResponse, error) {
+	return c.WriteWithContext(context.Background(), nodes...)
+}
+
+// WriteWithContext is the same as Write but returns ctx.Err() if ctx is done
+// before the WriteResponse arrives.
+func (c *Client) WriteWithContext(ctx context.Context, nodes ...*datatypes.WriteValue) (*services.WriteResponse, error) {
+	for _, w := range nodes {
+		if w.Value == nil || !w.Value.HasValue() {
+			continue
+		}
+		if err := w.AttributeID.CheckValue(w.Value.Value); err != nil {
+			return nil, err
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.session == nil {
+		return nil, ErrNotConnected
+	}
+
+	h := c.requestHeader()
+	res, err := c.send(ctx, services.NewWriteRequest(h, nodes...), h.RequestHandle)
+	if err != nil {
+		return nil, err
+	}
+
+	r, ok := res.(*services.WriteResponse)
+	if !ok {
+		return nil, errors.NewErrInvalidType(res, "write", "should be WriteResponse")
+	}
+	return r, nil
+}
+
+// WriteValue writes v to the Value attribute of node and returns the StatusCode for it.
+//
+// The error is only returned if the WriteRequest fails as a whole. The StatusCode
+// should be checked to know if the value is actually written.
+// The v can be created from a Go value with NewVariant.
+func (c *Client) WriteValue(node *datatypes.NodeID, v *datatypes.Variant) (uint32, error) {
+	return c.WriteAttribute(node, datatypes.AttributeIDValue, v)
+}
+
+// WriteAttribute writes v to the Attribute attr of node and returns the StatusCode for it,
+// e.g. a LocalizedText to the DisplayName. It fails without writing if v is not of the
+// DataType of attr.
+//
+// As with WriteValue, the error is only returned if the WriteRequest fails as a whole.
+func (c *Client) WriteAttribute(node *datatypes.NodeID, attr datatypes.AttributeID, v *datatypes.Variant) (uint32, error) {
+	codes, err := c.write([]*datatypes.WriteValue{newWriteValue(node, attr, v)})
+	if err != nil {
+		return 0, err
+	}
+	return codes[0], nil
+}
+
+// WriteValues writes the values to the Value attribute of the nodes in a single WriteRequest
+// and returns the StatusCode for each of the nodes.
+//
+// As with WriteValue, the error is only returned if the WriteRequest fails as a whole.
+func (c *Client) WriteValues(values map[*datatypes.NodeID]*datatypes.Variant) (map[*datatypes.NodeID]uint32, error) {
+	nodes := make([]*datatypes.NodeID, 0, len(values))
+	wvs := make([]*datatypes.WriteValue, 0, len(values))
+	for node, v := range values {
+		nodes = append(nodes, node)
+		wvs = append(wvs, newWriteValue(node, datatypes.AttributeIDValue, v))
+	}
+
+	codes, err := c.write(wvs)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make(map[*datatypes.NodeID]uint32, len(nodes))
+	for i, node := range nodes {
+		results[node] = codes[i]
+	}
+	return results, nil
+}
+
+// newWriteValue returns the WriteValue to write v to the Attribute attr of node.
+func newWriteValue(node *datatypes.NodeID, attr datatypes.AttributeID, v *datatypes.Variant) *datatypes.WriteValue {
+	return datatypes.NewWriteValue(
+		node, attr, "",
+		datatypes.NewDataValue(
+			true, false, false, false, false, false,
+			v, 0, time.Time{}, 0, time.Time{}, 0,
+		),
+	)
+}
+
+// write writes the WriteValues and returns the StatusCodes in the same order.
+func (c *Client) write(wvs []*datatypes.WriteValue) ([]uint32, error) {
+	res, err := c.Write(wvs...)
+	if err != nil {
+		return nil, err
+	}
+	if res.Results == nil || len(res.Results.Values) != len(wvs) {
+		return nil, errors.New("write returned unexpected number of results")
+	}
+	return res.Results.Values, nil
+}
+
+// Call calls the Method of objectID with the input arguments given and returns the output arguments.
+//
+// A *ServiceError with the StatusCode of the Method is returned if it is Bad, wrapped with
+// the StatusCode of the input argument rejected by the server if any. The output arguments
+// are returned if it is Uncertain as well as Good.
+func (c *Client) Call(objectID, methodID *datatypes.NodeID, inputs []*datatypes.Variant) ([]*datatypes.Variant, error) {
+	return c.CallWithContext(context.Background(), objectID, methodID, inputs)
+}
+
+// CallWithContext is the same as Call but returns ctx.Err() if ctx is done
+// before the CallResponse arrives.
+func (c *Client) CallWithContext(ctx context.Context, objectID, methodID *datatypes.NodeID, inputs []*datatypes.Variant) ([]*datatypes.Variant, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.session == nil {
+		return nil, ErrNotConnected
+	}
+
+	h := c.requestHeader()
+	res, err := c.send(ctx, services.NewCallRequest(h, datatypes.NewCallMethodRequest(objectID, methodID, inputs...)), h.RequestHandle)
+	if err != nil {
+		return nil, err
+	}
+
+	r, ok := res.(*services.CallResponse)
+	if !ok {
+		return nil, errors.NewErrInvalidType(res, "call", "should be CallResponse")
+	}
+	if r.Results == nil || len(r.Results.CallMethodResults) != 1 {
+		return nil, errors.New("call returned unexpected number of results")
+	}
+
+	result := r.Results.CallMethodResults[0]
+	if status.StatusCode(result.StatusCode).IsBad() {
+		err := &ServiceError{Code: result.StatusCode}
+		if result.InputArgumentResults != nil {
+			for i, code := range result.InputArgumentResults.Values {
+				if status.StatusCode(code).IsBad() {
+					return nil, errors.Wrapf(err, "input argument %d is rejected with status %v", i, status.StatusCode(code))
+				}
+			}
+		}
+		return nil, err
+	}
+	if result.OutputArguments == nil {
+		return nil, nil
+	}
+	return result.OutputArguments.Variants, nil
+}
+
+// RegisterNodes registers the nodes which are accessed repeatedly and returns the NodeIDs
+// to be used instead of them, in the same order, until they are unregistered.
+//
+// The NodeIDs returned are opaque to the Client and should be passed to Read or Write as they are.
+// The server may return the same NodeIDs as given if it has no optimization for them.
+func (c *Client) RegisterNodes(nodes []*datatypes.NodeID) ([]*datatypes.NodeID, error) {
+	return c.RegisterNodesWithContext(context.Background(), nodes)
+}
+
+// RegisterNodesWithContext is the same as RegisterNodes but returns ctx.Err() if ctx is done
+// before the RegisterNodesResponse arrives.
+func (c *Client) RegisterNodesWithContext(ctx context.Context, nodes []*datatypes.NodeID) ([]*datatypes.NodeID, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.session == nil {
+		return nil, ErrNotConnected
+	}
+
+	h := c.requestHeader()
+	res, err := c.send(ctx, services.NewRegisterNodesRequest(h, nodes...), h.RequestHandle)
+	if err != nil {
+		return nil, err
+	}
+
+	r, ok := res.(*services.RegisterNodesResponse)
+	if !ok {
+		return nil, errors.NewErrInvalidType(res, "register nodes", "should be RegisterNodesResponse")
+	}
+	if r.RegisteredNodeIDs == nil || len(r.RegisteredNodeIDs.NodeIDs) != len(nodes) {
+		return nil, errors.New("register nodes returned unexpected number of nodes")
+	}
+	return r.RegisteredNodeIDs.NodeIDs, nil
+}
+
+// UnregisterNodes unregisters the NodeIDs returned by RegisterNodes.
+//
+// Unregistering the nodes which are not registered has no effect, and
+// no request is sent if nodes is empty.
+func (c *Client) UnregisterNodes(nodes []*datatypes.NodeID) error {
+	return c.UnregisterNodesWithContext(context.Background(), nodes)
+}
+
+// UnregisterNodesWithContext is the same as UnregisterNodes but returns ctx.Err() if ctx is done
+// before the UnregisterNodesResponse arrives.
+func (c *Client) UnregisterNodesWithContext(ctx context.Context, nodes []*datatypes.NodeID) error {
+	if len(nodes) == 0 {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.session == nil {
+		return ErrNotConnected
+	}
+
+	h := c.requestHeader()
+	res, err := c.send(ctx, services.NewUnregisterNodesRequest(h, nodes...), h.RequestHandle)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := res.(*services.UnregisterNodesResponse); !ok {
+		return errors.NewErrInvalidType(res, "unregister nodes", "should be UnregisterNodesResponse")
+	}
+	return nil
+}
+
+// TranslateBrowsePaths translates the BrowsePaths into the NodeIDs of their targets
+// and returns the BrowsePathResults in the same order as paths.
+//
+// The StatusCode of each BrowsePathResult should be checked as a path which cannot be
+// followed does not fail the others.
+func (c *Client) TranslateBrowsePaths(paths []*datatypes.BrowsePath) ([]*datatypes.BrowsePathResult, error) {
+	return c.TranslateBrowsePathsWithContext(context.Background(), paths)
+}
+
+// TranslateBrowsePathsWithContext is the same as TranslateBrowsePaths but returns ctx.Err()
+// if ctx is done before the TranslateBrowsePathsToNodeIdsResponse arrives.
+func (c *Client) TranslateBrowsePathsWithContext(ctx context.Context, paths []*datatypes.BrowsePath) ([]*datatypes.BrowsePathResult, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.session == nil {
+		return nil, ErrNotConnected
+	}
+
+	h := c.requestHeader()
+	res, err := c.send(ctx, services.NewTranslateBrowsePathsToNodeIdsRequest(h, paths...), h.RequestHandle)
+	if err != nil {
+		return nil, err
+	}
+
+	r, ok := res.(*services.TranslateBrowsePathsToNodeIdsResponse)
+	if !ok {
+		return nil, errors.NewErrInvalidType(res, "translate browse paths", "should be TranslateBrowsePathsToNodeIdsResponse")
+	}
+	if r.Results == nil || len(r.Results.BrowsePathResults) != len(paths) {
+		return nil, errors.New("translate browse paths returned unexpected number of results")
+	}
+	return r.Results.BrowsePathResults, nil
+}
+
+// ResolveBrowsePath returns the NodeID of the Node which is reached by following path
+// from start, e.g. "/2:Device/2:Temperature". See datatypes.ParseRelativePath for the format.
+//
+// If the path leads to multiple Nodes, the first one is returned. An error is returned
+// if no Node is found or the Node is in another server.
+func (c *Client) ResolveBrowsePath(start *datatypes.NodeID, path string) (*datatypes.NodeID, error) {
+	rp, err := datatypes.ParseRelativePath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := c.TranslateBrowsePaths([]*datatypes.BrowsePath{datatypes.NewBrowsePath(start, rp)})
+	if err != nil {
+		return nil, err
+	}
+
+	r := results[0]
+	if r.StatusCode != 0 {
+		return nil, errors.Errorf("browse path %s cannot be resolved with status %v", path, status.StatusCode(r.StatusCode))
+	}
+	if r.Targets == nil || len(r.Targets.BrowsePathTargets) == 0 {
+		return nil, errors.Errorf("browse path %s has no target", path)
+	}
+
+	t := r.Targets.BrowsePathTargets[0]
+	if t.TargetID.HasServerIndex() && t.TargetID.ServerIndex != 0 {
+		return nil, errors.Errorf("target of browse path %s is in server %d", path, t.TargetID.ServerIndex)
+	}
+	return c.ToNodeID(t.TargetID)
+}
+
+// Browse sends a BrowseRequest for the nodes given and returns the BrowseResponse.
+//
+// The null ViewDescription is used if view is nil.
+func (c *Client) Browse(view *datatypes.ViewDescription, maxRefs uint32, nodes ...*datatypes.BrowseDescription) (*services.BrowseResponse, error) {
+	return c.BrowseWithContext(context.Background(), view, maxRefs, nodes...)
+}
+
+// BrowseWithContext is the same as Browse but returns ctx.Err() if ctx is done
+// before the BrowseResponse arrives.
+func (c *Client) BrowseWithContext(ctx context.Context, view *datatypes.ViewDescription, maxRefs uint32, nodes ...*datatypes.BrowseDescription) (*services.BrowseResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.session == nil {
+		return nil, ErrNotConnected
+	}
+	if view == nil {
+		view = datatypes.NewNullViewDescription()
+	}
+
+	h := c.requestHeader()
+	res, err := c.send(ctx, services.NewBrowseRequest(h, view, maxRefs, nodes...), h.RequestHandle)
+	if err != nil {
+		return nil, err
+	}
+
+	r, ok := res.(*services.BrowseResponse)
+	if !ok {
+		return nil, errors.NewErrInvalidType(res, "browse", "should be BrowseResponse")
+	}
+	return r, nil
+}
+
+// BrowseNext sends a BrowseNextRequest for the continuation points given and returns the BrowseNextResponse.
+//
+// If release is true, the continuation points are released and no References are returned.
+func (c *Client) BrowseNext(release bool, cps ...[]byte) (*services.BrowseNextResponse, error) {
+	return c.BrowseNextWithContext(context.Background(), release, cps...)
+}
+
+// BrowseNextWithContext is the same as BrowseNext but returns ctx.Err() if ctx is done
+// before the BrowseNextResponse arrives.
+func (c *Client) BrowseNextWithContext(ctx context.Context, release bool, cps ...[]byte) (*services.BrowseNextResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.session == nil {
+		return nil, ErrNotConnected
+	}
+
+	h := c.requestHeader()
+	res, err := c.send(ctx, services.NewBrowseNextRequest(h, release, cps...), h.RequestHandle)
+	if err != nil {
+		return nil, err
+	}
+
+	r, ok := res.(*services.BrowseNextResponse)
+	if !ok {
+		return nil, errors.NewErrInvalidType(res, "browse next", "should be BrowseNextResponse")
+	}
+	return r, nil
+}
+
+// release asks the server to release the continuation points given.
+// Like cancel, it does not wait for the BrowseNextResponse and the errors
+// are ignored.
+func (c *Client) release(cps ...[]byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.session == nil {
+		return
+	}
+
+	b, err := services.NewBrowseNextRequest(c.requestHeader(), true, cps...).Serialize()
+	if err != nil {
+		return
+	}
+	c.session.WriteService(b)
+}
+
+// BrowseChildren returns all the References from node to its children, which are
+// the forward HierarchicalReferences and their subtypes.
+func (c *Client) BrowseChildren(node *datatypes.NodeID) ([]*datatypes.ReferenceDescription, error) {
+	return c.BrowseChildrenWithContext(context.Background(), node)
+}
+
+// BrowseChildrenWithContext is the same as BrowseChildren but stops when ctx is done.
+func (c *Client) BrowseChildrenWithContext(ctx context.Context, node *datatypes.NodeID) ([]*datatypes.ReferenceDescription, error) {
+	return c.BrowseAllWithContext(ctx, node, datatypes.NewBrowseChildrenDescription(node))
+}
+
+// BrowseAll browses node with the filters in desc and returns all the References,
+// following the continuation points with BrowseNext until the server has no more.
+//
+// The NodeID in desc is ignored and node is browsed instead. If desc is nil,
+// the forward HierarchicalReferences and their subtypes are browsed.
+func (c *Client) BrowseAll(node *datatypes.NodeID, desc *datatypes.BrowseDescription) ([]*datatypes.ReferenceDescription, error) {
+	return c.BrowseAllWithContext(context.Background(), node, desc)
+}
+
+// BrowseAllWithContext is the same as BrowseAll but stops when ctx is done.
+// The continuation point held by the server is released before returning ctx.Err().
+func (c *Client) BrowseAllWithContext(ctx context.Context, node *datatypes.NodeID, desc *datatypes.BrowseDescription) ([]*datatypes.ReferenceDescription, error) {
+	d := datatypes.NewBrowseChildrenDescription(node)
+	if desc != nil {
+		copied := *desc
+		copied.NodeID = node
+		d = &copied
+	}
+
+	res, err := c.BrowseWithContext(ctx, nil, 0, d)
+	if err != nil {
+		return nil, err
+	}
+	result, err := browseResult(res.Results)
+	if err != nil {
+		return nil, err
+	}
+
+	var refs []*datatypes.ReferenceDescription
+	for {
+		if result.References != nil {
+			refs = append(refs, result.References.ReferenceDescriptions...)
+		}
+
+		var cp []byte
+		if result.ContinuationPoint != nil {
+			cp = result.ContinuationPoint.Get()
+		}
+		if len(cp) == 0 {
+			return refs, nil
+		}
+		if err := ctx.Err(); err != nil {
+			c.release(cp)
+			return nil, err
+		}
+
+		next, err := c.BrowseNextWithContext(ctx, false, cp)
+		if err != nil {
+			if ctx.Err() != nil {
+				c.release(cp)
+			}
+			return nil, err
+		}
+		if result, err = browseResult(next.Results); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// browseResult returns the only BrowseResult in results, or an error
+// if there is not exactly one or it has a bad StatusCode.
+func browseResult(results *datatypes.BrowseResultArray) (*datatypes.BrowseResult, error) {
+	if results == nil || len(results.BrowseResults) != 1 {
+		return nil, errors.New("browse returned unexpected number of results")
+	}
+
+	r := results.BrowseResults[0]
+	if r.StatusCode != 0 {
+		return nil, errors.Errorf("browse failed with status %v", status.StatusCode(r.StatusCode))
+	}
+	return r, nil
+}
+
+// ReadExpandedNodeID reads the attribute of node given and returns its value as ExpandedNodeID.
+//
+// An error is returned if the value read is not an ExpandedNodeID.
+func (c *Client) ReadExpandedNodeID(node *datatypes.NodeID, attr datatypes.AttributeID) (*datatypes.ExpandedNodeID, error) {
+	v, err := c.readAttribute(context.Background(), node, attr)
+	if err != nil {
+		return nil, err
+	}
+
+	e, ok := v.Value.(*datatypes.ExpandedNodeID)
+	if !ok {
+		return nil, errors.Errorf("read returned %T, not ExpandedNodeID", v.Value)
+	}
+	return e, nil
+}
+
+// readAttribute reads the attribute of node given and returns its value.
+//
+// An error is returned if the attribute cannot be read or has no value.
+func (c *Client) readAttribute(ctx context.Context, node *datatypes.NodeID, attr datatypes.AttributeID) (*datatypes.Variant, error) {
+	return c.readValueID(ctx, datatypes.NewReadValueID(node, attr, "", 0, ""))
+}
+
+// readValueID reads the attribute identified by rv and returns its value.
+func (c *Client) readValueID(ctx context.Context, rv *datatypes.ReadValueID) (*datatypes.Variant, error) {
+	res, err := c.ReadWithContext(ctx, 0, services.TimestampsToReturnNeither, rv)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.Results == nil || len(res.Results.DataValues) != 1 {
+		return nil, errors.New("read returned unexpected number of results")
+	}
+	dv := res.Results.DataValues[0]
+	if code := dv.StatusCode(); code != 0 {
+		return nil, errors.Errorf("read failed with status %v", status.StatusCode(code))
+	}
+	if !dv.HasValue() || dv.Value == nil {
+		return nil, errors.New("read returned no value")
+	}
+	return dv.Value, nil
+}
@@ -0,0 +1,1636 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package gopcua
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/binary"
+	"encoding/pem"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/wmnsk/gopcua/datatypes"
+	"github.com/wmnsk/gopcua/errors"
+	"github.com/wmnsk/gopcua/id"
+	"github.com/wmnsk/gopcua/services"
+	"github.com/wmnsk/gopcua/status"
+	"github.com/wmnsk/gopcua/uacp"
+	"github.com/wmnsk/gopcua/uasc"
+)
+
+var (
+	endpoint  = "opc.tcp://127.0.0.1:4841/foo/bar"
+	policyURI = "http://opcfoundation.org/UA/SecurityPolicy#None"
+)
+
+// setUpClient connects a Client created with opts to a server which responds
+// to each request with the response returned by handle. The server does not
+// respond if handle returns nil.
+func setUpClient(ctx context.Context, handle func(services.Service) services.Service, opts ...Option) (*Client, error) {
+	srvCfg := uasc.NewServerConfig(policyURI, nil, nil, 1111, services.SecModeNone, 2222, 3600000)
+	return setUpClientWithConfig(ctx, srvCfg, handle, opts...)
+}
+
+// setUpClientWithConfig is the same as setUpClient but the SecureChannel of the server
+// is configured with srvCfg, and the Client is created with opts.
+func setUpClientWithConfig(ctx context.Context, srvCfg *uasc.Config, handle func(services.Service) services.Service, opts ...Option) (*Client, error) {
+	return setUpClientWithConn(ctx, nil, srvCfg, handle, opts...)
+}
+
+// setUpClientWithConn is the same as setUpClientWithConfig but the connection of the server
+// is wrapped with wrap, if it is not nil.
+func setUpClientWithConn(ctx context.Context, wrap func(net.Conn) net.Conn, srvCfg *uasc.Config, handle func(services.Service) services.Service, opts ...Option) (*Client, error) {
+	ln, err := uacp.Listen(endpoint, 0xffff)
+	if err != nil {
+		return nil, err
+	}
+	defer ln.Close()
+
+	errChan := make(chan error, 1)
+	go func() {
+		srvConn, err := ln.Accept(ctx)
+		if err != nil {
+			errChan <- err
+			return
+		}
+
+		var transport net.Conn = srvConn
+		if wrap != nil {
+			transport = wrap(srvConn)
+		}
+		srvChan, err := uasc.ListenAndAcceptSecureChannel(ctx, transport, srvCfg)
+		if err != nil {
+			errChan <- err
+			return
+		}
+		srvSession, err := uasc.ListenAndAcceptSession(ctx, srvChan, uasc.NewServerSessionConfig(srvChan))
+		if err != nil {
+			errChan <- err
+			return
+		}
+
+		buf := make([]byte, 0xffff)
+		for {
+			n, err := srvSession.ReadService(buf)
+			if err != nil {
+				return
+			}
+			srv, err := services.Decode(buf[:n])
+			if err != nil {
+				continue
+			}
+			res := handleNamespaces(srv)
+			if res == nil {
+				res = handle(srv)
+			}
+			if res == nil {
+				continue
+			}
+
+			b, err := res.Serialize()
+			if err != nil {
+				return
+			}
+			if _, err := srvSession.WriteService(b); err != nil {
+				return
+			}
+		}
+	}()
+
+	c := NewClient(endpoint, opts...)
+	connErr := make(chan error, 1)
+	go func() {
+		connErr <- c.Connect(ctx)
+	}()
+
+	select {
+	case err := <-connErr:
+		if err != nil {
+			return nil, err
+		}
+		return c, nil
+	case err := <-errChan:
+		return nil, err
+	case <-time.After(10 * time.Second):
+		return nil, errors.New("timed out")
+	}
+}
+
+// handleRead returns a handler which responds to each ReadRequest
+// with the DataValue returned by read.
+func handleRead(read func(*services.ReadRequest) *datatypes.DataValue) func(services.Service) services.Service {
+	return func(srv services.Service) services.Service {
+		req, ok := srv.(*services.ReadRequest)
+		if !ok {
+			return nil
+		}
+		return services.NewReadResponse(newResponseHeader(req.RequestHandle), nil, read(req))
+	}
+}
+
+func newResponseHeader(handle uint32) *services.ResponseHeader {
+	return services.NewResponseHeader(
+		time.Now(), handle, 0, services.NewNullDiagnosticInfo(),
+		[]string{}, services.NewNullAdditionalHeader(), nil,
+	)
+}
+
+// testNamespaces is the NamespaceArray of the servers in the tests.
+var testNamespaces = []string{"http://opcfoundation.org/UA/", "urn:gopcua:test"}
+
+// handleNamespaces responds to the ReadRequest of the NamespaceArray sent by the Client
+// on activation of the Session, so that the handlers in the tests do not see it.
+// nil is returned for the other services.
+func handleNamespaces(srv services.Service) services.Service {
+	req, ok := srv.(*services.ReadRequest)
+	if !ok || req.NodesToRead == nil || len(req.NodesToRead.ReadValueIDs) != 1 {
+		return nil
+	}
+	if !req.NodesToRead.ReadValueIDs[0].NodeID.Equal(datatypes.NewNumericNodeID(0, id.Server_NamespaceArray)) {
+		return nil
+	}
+
+	values := make([]datatypes.Data, len(testNamespaces))
+	for i, ns := range testNamespaces {
+		values[i] = datatypes.NewString(ns)
+	}
+	return services.NewReadResponse(newResponseHeader(req.RequestHandle), nil, datatypes.NewDataValue(
+		true, false, false, false, false, false,
+		datatypes.NewArrayVariant(id.String, values...), 0, time.Time{}, 0, time.Time{}, 0,
+	))
+}
+
+func newValue(v datatypes.Data) *datatypes.DataValue {
+	return datatypes.NewDataValue(
+		true, false, false, false, false, false,
+		datatypes.NewVariant(v), 0, time.Time{}, 0, time.Time{}, 0,
+	)
+}
+
+func TestClientReadExpandedNodeID(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	want := datatypes.NewStringExpandedNodeIDWithURI("http://example.com/ns", "foobar")
+	c, err := setUpClient(ctx, handleRead(func(req *services.ReadRequest) *datatypes.DataValue {
+		return newValue(want)
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	got, err := c.ReadExpandedNodeID(datatypes.NewNumericNodeID(0, 2256), datatypes.AttributeIDValue)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestClientReadExpandedNodeIDTypeMismatch(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	c, err := setUpClient(ctx, handleRead(func(req *services.ReadRequest) *datatypes.DataValue {
+		return newValue(datatypes.NewFloat(1.5))
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	if _, err := c.ReadExpandedNodeID(datatypes.NewNumericNodeID(0, 2256), datatypes.AttributeIDValue); err == nil {
+		t.Error("expected error for Float value, got nil")
+	}
+}
+
+func TestClientReadWithContextTimeout(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// the server never responds and only reports the read and the canceled request.
+	read := make(chan uint32, 1)
+	canceled := make(chan uint32, 1)
+	c, err := setUpClient(ctx, func(srv services.Service) services.Service {
+		switch req := srv.(type) {
+		case *services.ReadRequest:
+			read <- req.RequestHandle
+		case *services.CancelRequest:
+			canceled <- req.RequestHandle
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	rctx, rcancel := context.WithTimeout(ctx, 100*time.Millisecond)
+	defer rcancel()
+
+	node := datatypes.NewReadValueID(datatypes.NewNumericNodeID(0, 2256), datatypes.AttributeIDValue, "", 0, "")
+	if _, err := c.ReadWithContext(rctx, 0, services.TimestampsToReturnNeither, node); err != context.DeadlineExceeded {
+		t.Fatalf("got error %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	select {
+	case h := <-canceled:
+		if want := <-read; h != want {
+			t.Errorf("canceled request %d, want %d", h, want)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("CancelRequest was not sent")
+	}
+}
+
+func TestClientUnexpectedResponses(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// the server responds to the first PublishRequest with a response no one waits for,
+	// e.g. the late response to the request timed out, and to the second with a data change.
+	const subID = 7
+	var published int
+	c, err := setUpClient(ctx, func(srv services.Service) services.Service {
+		switch req := srv.(type) {
+		case *services.CreateSubscriptionRequest:
+			return services.NewCreateSubscriptionResponse(newResponseHeader(req.RequestHandle), subID, 100, 60, 20)
+		case *services.PublishRequest:
+			published++
+			switch published {
+			case 1:
+				return services.NewCancelResponse(newResponseHeader(req.RequestHandle+1000), 0)
+			case 2:
+				return newDataChangeResponse(req.RequestHandle, subID, 1, datatypes.NewFloat(10))
+			}
+		}
+		return nil
+	}, WithPublishRequests(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	sub, err := c.CreateSubscription(100, 60, 20, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-sub.Notifs():
+	case <-time.After(5 * time.Second):
+		t.Fatal("notification was not delivered after the unexpected response")
+	}
+}
+
+// abortConn is a net.Conn of the server which replaces the ReadResponse sent with
+// the abort chunk for each token in abort, as if the server failed to send the rest
+// of the chunks.
+type abortConn struct {
+	net.Conn
+	abort chan struct{}
+}
+
+func (c *abortConn) Write(b []byte) (int, error) {
+	if len(b) < 24 || string(b[:4]) != "MSGF" {
+		return c.Conn.Write(b)
+	}
+	typeID, err := datatypes.DecodeExpandedNodeID(b[24:])
+	if err != nil || typeID.NodeID.IntID() != id.ReadResponse_Encoding_DefaultBinary {
+		return c.Conn.Write(b)
+	}
+	select {
+	case <-c.abort:
+	default:
+		return c.Conn.Write(b)
+	}
+
+	reason, err := datatypes.NewString("foo").Serialize()
+	if err != nil {
+		return 0, err
+	}
+	abort := append([]byte{}, b[:24]...)
+	abort[3] = uasc.ChunkTypeError[0]
+	abort = append(abort, 0, 0, 0, 0)
+	binary.LittleEndian.PutUint32(abort[24:28], status.BadRequestTooLarge)
+	abort = append(abort, reason...)
+	binary.LittleEndian.PutUint32(abort[4:8], uint32(len(abort)))
+	if _, err := c.Conn.Write(abort); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+func TestClientAbortedResponse(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	abort := make(chan struct{}, 1)
+	wrap := func(conn net.Conn) net.Conn {
+		return &abortConn{Conn: conn, abort: abort}
+	}
+	srvCfg := uasc.NewServerConfig(policyURI, nil, nil, 1111, services.SecModeNone, 2222, 3600000)
+	c, err := setUpClientWithConn(ctx, wrap, srvCfg, handleRead(func(*services.ReadRequest) *datatypes.DataValue {
+		return newValue(datatypes.NewFloat(10))
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	abort <- struct{}{}
+	node := datatypes.NewReadValueID(datatypes.NewNumericNodeID(0, 2256), datatypes.AttributeIDValue, "", 0, "")
+	_, err = c.ReadWithContext(ctx, 0, services.TimestampsToReturnNeither, node)
+	e, ok := errors.Cause(err).(*uasc.AbortError)
+	if !ok {
+		t.Fatalf("got error %v want AbortError", err)
+	}
+	if e.StatusCode != status.BadRequestTooLarge || e.Reason != "foo" || e.RequestHandle == 0 {
+		t.Errorf("got %v", e)
+	}
+
+	// the connection is kept for the other requests.
+	res, err := c.ReadWithContext(ctx, 0, services.TimestampsToReturnNeither, node)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(res.Results.DataValues[0], newValue(datatypes.NewFloat(10))); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestClientRequestTimeout(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// the server never responds to the ReadRequest and reports its TimeoutHint.
+	hints := make(chan uint32, 1)
+	canceled := make(chan uint32, 1)
+	c, err := setUpClient(ctx, func(srv services.Service) services.Service {
+		switch req := srv.(type) {
+		case *services.ReadRequest:
+			hints <- req.TimeoutHint
+		case *services.CancelRequest:
+			canceled <- req.RequestHandle
+		}
+		return nil
+	}, WithRequestTimeout(100*time.Millisecond))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	node := datatypes.NewReadValueID(datatypes.NewNumericNodeID(0, 2256), datatypes.AttributeIDValue, "", 0, "")
+	start := time.Now()
+	if _, err := c.Read(0, services.TimestampsToReturnNeither, node); err != ErrTimeout {
+		t.Fatalf("got error %v, want %v", err, ErrTimeout)
+	}
+	if d := time.Since(start); d > 5*time.Second {
+		t.Errorf("request was aborted after %v", d)
+	}
+
+	if h := <-hints; h == 0 || h > 100 {
+		t.Errorf("got TimeoutHint %d, want 1-100", h)
+	}
+	select {
+	case <-canceled:
+	case <-time.After(5 * time.Second):
+		t.Fatal("CancelRequest was not sent")
+	}
+}
+
+func TestClientServiceFault(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	c, err := setUpClient(ctx, func(srv services.Service) services.Service {
+		req, ok := srv.(*services.ReadRequest)
+		if !ok {
+			return nil
+		}
+		diag := services.NewDiagnosticInfo(false, false, true, false, false, false, false, 0, 0, 0, 0, nil, 0, nil)
+		h := services.NewResponseHeader(time.Now(), req.RequestHandle, status.BadTooManyOperations, diag, []string{"too many nodes"}, services.NewNullAdditionalHeader(), nil)
+		return services.NewServiceFault(h)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	node := datatypes.NewReadValueID(datatypes.NewNumericNodeID(0, 2256), datatypes.AttributeIDValue, "", 0, "")
+	_, err = c.Read(0, services.TimestampsToReturnNeither, node)
+	e, ok := errors.Cause(err).(*ServiceError)
+	if !ok {
+		t.Fatalf("got %v want ServiceError", err)
+	}
+	if e.Code != status.BadTooManyOperations {
+		t.Errorf("got status %v want BadTooManyOperations", status.StatusCode(e.Code))
+	}
+	if e.Diagnostics == nil || e.Diagnostics.LocalizedText != "too many nodes" {
+		t.Errorf("got Diagnostics %v want the LocalizedText", e.Diagnostics)
+	}
+}
+
+func TestClientRequestOptions(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// the server reports the fields in the RequestHeader, and returns the ServiceDiagnostics
+	// with the LocalizedText only if it is requested.
+	headers := make(chan *services.RequestHeader, 1)
+	c, err := setUpClient(ctx, func(srv services.Service) services.Service {
+		req, ok := srv.(*services.ReadRequest)
+		if !ok {
+			return nil
+		}
+		headers <- req.RequestHeader
+
+		diag := services.NewNullDiagnosticInfo()
+		if req.RequestsServiceLevelLocalizedText() {
+			diag = services.NewDiagnosticInfo(false, false, true, false, false, false, false, 0, 0, 0, 0, nil, 0, nil)
+		}
+		h := services.NewResponseHeader(time.Now(), req.RequestHandle, 0, diag, []string{"read 1 node"}, services.NewNullAdditionalHeader(), nil)
+		return services.NewReadResponse(h, nil, newValue(datatypes.NewInt32(1)))
+	}, WithAuditEntryID("audit-1"), WithReturnDiagnostics(services.ReturnDiagnosticsOperationLevel))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	node := datatypes.NewReadValueID(datatypes.NewNumericNodeID(0, 2256), datatypes.AttributeIDValue, "", 0, "")
+	cases := []struct {
+		name  string
+		ctx   context.Context
+		audit string
+		diag  uint32
+		text  string
+	}{
+		{
+			name:  "default",
+			ctx:   context.Background(),
+			audit: "audit-1",
+			diag:  services.ReturnDiagnosticsOperationLevel,
+		},
+		{
+			name:  "per-call",
+			ctx:   ContextWithRequestOptions(context.Background(), RequestOptions{AuditEntryID: "audit-2", ReturnDiagnostics: services.ReturnDiagnosticsAll}),
+			audit: "audit-2",
+			diag:  services.ReturnDiagnosticsAll,
+			text:  "read 1 node",
+		},
+		{
+			// the zero fields keep the defaults.
+			name:  "diagnostics-only",
+			ctx:   ContextWithRequestOptions(context.Background(), RequestOptions{ReturnDiagnostics: services.ReturnDiagnosticsServiceLevel}),
+			audit: "audit-1",
+			diag:  services.ReturnDiagnosticsServiceLevel,
+			text:  "read 1 node",
+		},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			res, err := c.ReadWithContext(tc.ctx, 0, services.TimestampsToReturnNeither, node)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			h := <-headers
+			if got := h.AuditEntryID.Get(); got != tc.audit {
+				t.Errorf("got AuditEntryId %q, want %q", got, tc.audit)
+			}
+			if h.ReturnDiagnostics != tc.diag {
+				t.Errorf("got ReturnDiagnostics 0x%x, want 0x%x", h.ReturnDiagnostics, tc.diag)
+			}
+
+			d := res.ResponseHeader.ResolveDiagnosticInfo(res.ResponseHeader.ServiceDiagnostics)
+			if d.LocalizedText != tc.text {
+				t.Errorf("got LocalizedText %q, want %q", d.LocalizedText, tc.text)
+			}
+		})
+	}
+}
+
+func TestClientWrite(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	c, err := setUpClient(ctx, func(srv services.Service) services.Service {
+		req, ok := srv.(*services.WriteRequest)
+		if !ok {
+			return nil
+		}
+		return services.NewWriteResponse(newResponseHeader(req.RequestHandle), nil, 0)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	res, err := c.WriteWithContext(ctx, datatypes.NewWriteValue(
+		datatypes.NewNumericNodeID(2, 1000), datatypes.AttributeIDValue, "", newValue(datatypes.NewFloat(1.5)),
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(res.Results.Values, []uint32{0}); diff != "" {
+		t.Error(diff)
+	}
+}
+
+// handleWriteValues returns a handler which responds to each WriteRequest with
+// Good for the nodes in namespace 2 and BadNodeIdUnknown for the others.
+func handleWriteValues(t *testing.T) func(services.Service) services.Service {
+	return func(srv services.Service) services.Service {
+		req, ok := srv.(*services.WriteRequest)
+		if !ok {
+			return nil
+		}
+
+		var codes []uint32
+		for _, w := range req.NodesToWrite.WriteValues {
+			if w.AttributeID != datatypes.AttributeIDValue {
+				t.Errorf("got AttributeID %d, want Value", w.AttributeID)
+			}
+			code := uint32(0)
+			if w.NodeID.Namespace() != 2 {
+				code = 0x80340000
+			}
+			codes = append(codes, code)
+		}
+		return services.NewWriteResponse(newResponseHeader(req.RequestHandle), nil, codes...)
+	}
+}
+
+func TestClientWriteValue(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	c, err := setUpClient(ctx, handleWriteValues(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	code, err := c.WriteValue(datatypes.NewNumericNodeID(2, 1000), datatypes.NewVariant(datatypes.NewFloat(1.5)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code != 0 {
+		t.Errorf("got status 0x%08X, want Good", code)
+	}
+}
+
+func TestClientWriteAttribute(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	reqs := make(chan *services.WriteRequest, 1)
+	c, err := setUpClient(ctx, func(srv services.Service) services.Service {
+		req, ok := srv.(*services.WriteRequest)
+		if !ok {
+			return nil
+		}
+		reqs <- req
+		return services.NewWriteResponse(newResponseHeader(req.RequestHandle), nil, 0)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	node := datatypes.NewNumericNodeID(2, 1000)
+	name := datatypes.NewLocalizedText("en-US", "Temperature")
+	code, err := c.WriteAttribute(node, datatypes.AttributeIDDisplayName, datatypes.NewVariant(name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code != 0 {
+		t.Errorf("got status 0x%08X, want Good", code)
+	}
+
+	req := <-reqs
+	if got, want := len(req.NodesToWrite.WriteValues), 1; got != want {
+		t.Fatalf("got %d WriteValues want %d", got, want)
+	}
+	w := req.NodesToWrite.WriteValues[0]
+	if w.AttributeID != datatypes.AttributeIDDisplayName {
+		t.Errorf("got AttributeID %s want DisplayName", w.AttributeID)
+	}
+	if !w.NodeID.Equal(node) {
+		t.Errorf("got NodeID %s want %s", w.NodeID, node)
+	}
+	got, ok := w.Value.Value.LocalizedText()
+	if !ok {
+		t.Fatalf("got %T want LocalizedText", w.Value.Value.Value)
+	}
+	if diff := cmp.Diff(got, name); diff != "" {
+		t.Error(diff)
+	}
+
+	// the value of the wrong type is not sent.
+	if _, err := c.WriteAttribute(node, datatypes.AttributeIDDisplayName, datatypes.NewVariant(datatypes.NewString("Temperature"))); err == nil {
+		t.Error("got nil error for String DisplayName")
+	}
+	select {
+	case <-reqs:
+		t.Error("WriteRequest sent for String DisplayName")
+	default:
+	}
+}
+
+func TestClientWriteValues(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	c, err := setUpClient(ctx, handleWriteValues(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	good := datatypes.NewNumericNodeID(2, 1000)
+	bad := datatypes.NewNumericNodeID(3, 1000)
+	got, err := c.WriteValues(map[*datatypes.NodeID]*datatypes.Variant{
+		good: datatypes.NewVariant(datatypes.NewFloat(1.5)),
+		bad:  datatypes.NewVariant(datatypes.NewFloat(2.5)),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[*datatypes.NodeID]uint32{good: 0, bad: 0x80340000}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Error(diff)
+	}
+}
+
+// handleAdd returns a handler of a Method which takes two Floats and returns the sum of them.
+func handleAdd(srv services.Service) services.Service {
+	req, ok := srv.(*services.CallRequest)
+	if !ok {
+		return nil
+	}
+
+	m := req.MethodsToCall.CallMethodRequests[0]
+	args := m.InputArguments.Variants
+	if len(args) != 2 {
+		return services.NewCallResponse(newResponseHeader(req.RequestHandle), nil,
+			services.NewCallMethodResult(0x80770000, nil, nil),
+		)
+	}
+
+	var sum float32
+	codes := make([]uint32, len(args))
+	for i, arg := range args {
+		f, ok := arg.Float()
+		if !ok {
+			codes[i] = 0x80740000
+		}
+		sum += f
+	}
+	for _, code := range codes {
+		if code != 0 {
+			return services.NewCallResponse(newResponseHeader(req.RequestHandle), nil,
+				services.NewCallMethodResult(0x80ab0000, codes, nil),
+			)
+		}
+	}
+	return services.NewCallResponse(newResponseHeader(req.RequestHandle), nil,
+		services.NewCallMethodResult(0, codes, nil, datatypes.NewVariant(datatypes.NewFloat(sum))),
+	)
+}
+
+func TestClientCall(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	c, err := setUpClient(ctx, handleAdd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	obj, method := datatypes.NewNumericNodeID(2, 1000), datatypes.NewNumericNodeID(2, 1001)
+	got, err := c.Call(obj, method, []*datatypes.Variant{
+		datatypes.NewVariant(datatypes.NewFloat(1.5)),
+		datatypes.NewVariant(datatypes.NewFloat(2)),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(got, []*datatypes.Variant{datatypes.NewVariant(datatypes.NewFloat(3.5))}); diff != "" {
+		t.Error(diff)
+	}
+
+	// the StatusCode of the Method is returned as ServiceError.
+	_, err = c.Call(obj, method, []*datatypes.Variant{
+		datatypes.NewVariant(datatypes.NewFloat(1.5)),
+		datatypes.NewVariant(datatypes.NewBoolean(true)),
+	})
+	if e, ok := errors.Cause(err).(*ServiceError); !ok || e.Code != 0x80ab0000 {
+		t.Errorf("got error %v for invalid argument, want ServiceError with %v", err, status.StatusCode(0x80ab0000))
+	}
+
+	_, err = c.Call(obj, method, nil)
+	if e, ok := errors.Cause(err).(*ServiceError); !ok || e.Code != 0x80770000 {
+		t.Errorf("got error %v for missing arguments, want ServiceError with %v", err, status.StatusCode(0x80770000))
+	}
+}
+
+func TestClientCallUncertain(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// the Method succeeds with the output arguments which may be stale.
+	c, err := setUpClient(ctx, func(srv services.Service) services.Service {
+		req, ok := srv.(*services.CallRequest)
+		if !ok {
+			return nil
+		}
+		return services.NewCallResponse(newResponseHeader(req.RequestHandle), nil,
+			services.NewCallMethodResult(status.UncertainLastUsableValue, nil, nil, datatypes.NewVariant(datatypes.NewFloat(1.5))),
+		)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	got, err := c.Call(datatypes.NewNumericNodeID(2, 1000), datatypes.NewNumericNodeID(2, 1001), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(got, []*datatypes.Variant{datatypes.NewVariant(datatypes.NewFloat(1.5))}); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestClientRegisterNodes(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// the server gives an opaque alias to each node, and forgets it on unregistration.
+	var mu sync.Mutex
+	registered := map[string]bool{}
+	c, err := setUpClient(ctx, func(srv services.Service) services.Service {
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch req := srv.(type) {
+		case *services.RegisterNodesRequest:
+			var aliases []*datatypes.NodeID
+			for _, n := range req.NodesToRegister.NodeIDs {
+				alias := datatypes.NewOpaqueNodeID(1, []byte(n.String()))
+				registered[alias.String()] = true
+				aliases = append(aliases, alias)
+			}
+			return services.NewRegisterNodesResponse(newResponseHeader(req.RequestHandle), aliases...)
+		case *services.UnregisterNodesRequest:
+			for _, n := range req.NodesToUnregister.NodeIDs {
+				delete(registered, n.String())
+			}
+			return services.NewUnregisterNodesResponse(newResponseHeader(req.RequestHandle))
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	nodes := []*datatypes.NodeID{datatypes.NewNumericNodeID(2, 1000), datatypes.NewStringNodeID(2, "foo")}
+	aliases, err := c.RegisterNodes(nodes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*datatypes.NodeID{
+		datatypes.NewOpaqueNodeID(1, []byte(nodes[0].String())),
+		datatypes.NewOpaqueNodeID(1, []byte(nodes[1].String())),
+	}
+	if diff := cmp.Diff(aliases, want); diff != "" {
+		t.Error(diff)
+	}
+
+	// unregistering twice should succeed both times.
+	for i := 0; i < 2; i++ {
+		if err := c.UnregisterNodes(aliases); err != nil {
+			t.Fatalf("unregister #%d: %v", i, err)
+		}
+	}
+	if err := c.UnregisterNodes(nil); err != nil {
+		t.Fatal(err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(registered) != 0 {
+		t.Errorf("nodes are still registered: %v", registered)
+	}
+}
+
+func TestClientRegisterNodesWithContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// the server reports the TimeoutHints, which are given by the deadline of the context.
+	hints := make(chan uint32, 3)
+	c, err := setUpClient(ctx, func(srv services.Service) services.Service {
+		switch req := srv.(type) {
+		case *services.RegisterNodesRequest:
+			hints <- req.TimeoutHint
+			return services.NewRegisterNodesResponse(newResponseHeader(req.RequestHandle), req.NodesToRegister.NodeIDs...)
+		case *services.UnregisterNodesRequest:
+			hints <- req.TimeoutHint
+			return services.NewUnregisterNodesResponse(newResponseHeader(req.RequestHandle))
+		case *services.TranslateBrowsePathsToNodeIdsRequest:
+			hints <- req.TimeoutHint
+			return services.NewTranslateBrowsePathsToNodeIdsResponse(newResponseHeader(req.RequestHandle), nil,
+				datatypes.NewBrowsePathResult(uint32(status.BadNoMatch)))
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	rctx, rcancel := context.WithTimeout(ctx, time.Second)
+	defer rcancel()
+
+	nodes := []*datatypes.NodeID{datatypes.NewNumericNodeID(2, 1000)}
+	if _, err := c.RegisterNodesWithContext(rctx, nodes); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.UnregisterNodesWithContext(rctx, nodes); err != nil {
+		t.Fatal(err)
+	}
+	path := datatypes.NewBrowsePath(datatypes.NewFourByteNodeID(0, 85), datatypes.NewRelativePath())
+	if _, err := c.TranslateBrowsePathsWithContext(rctx, []*datatypes.BrowsePath{path}); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if h := <-hints; h == 0 || h > 1000 {
+			t.Errorf("request #%d: got TimeoutHint %d want (0, 1000]", i, h)
+		}
+	}
+}
+
+func TestClientResolveBrowsePath(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// the server knows the two levels of Nodes under the Objects folder.
+	objects := datatypes.NewFourByteNodeID(0, 85)
+	children := map[string]*datatypes.NodeID{
+		objects.String() + "/2:Device":                                     datatypes.NewStringNodeID(2, "Device"),
+		datatypes.NewStringNodeID(2, "Device").String() + "/2:Temperature": datatypes.NewStringNodeID(2, "Device.Temperature"),
+	}
+	c, err := setUpClient(ctx, func(srv services.Service) services.Service {
+		req, ok := srv.(*services.TranslateBrowsePathsToNodeIdsRequest)
+		if !ok {
+			return nil
+		}
+
+		var results []*datatypes.BrowsePathResult
+		for _, p := range req.BrowsePaths.BrowsePaths {
+			node := p.StartingNode
+			for _, e := range p.RelativePath.Elements {
+				if node == nil {
+					break
+				}
+				if e.ReferenceTypeID.String() != datatypes.NewFourByteNodeID(0, 33).String() {
+					node = nil
+					break
+				}
+				node = children[fmt.Sprintf("%s/%d:%s", node, e.TargetName.NamespaceIndex, e.TargetName.Name.Get())]
+			}
+			if node == nil {
+				results = append(results, datatypes.NewBrowsePathResult(0x806f0000))
+				continue
+			}
+			results = append(results, datatypes.NewBrowsePathResult(
+				0, datatypes.NewBrowsePathTarget(&datatypes.ExpandedNodeID{NodeID: node}, 0xffffffff),
+			))
+		}
+		return services.NewTranslateBrowsePathsToNodeIdsResponse(newResponseHeader(req.RequestHandle), nil, results...)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	got, err := c.ResolveBrowsePath(objects, "/2:Device/2:Temperature")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(got, datatypes.NewStringNodeID(2, "Device.Temperature")); diff != "" {
+		t.Error(diff)
+	}
+
+	if _, err := c.ResolveBrowsePath(objects, "/2:Device/2:Pressure"); err == nil {
+		t.Error("expected error for unknown path, got nil")
+	}
+}
+
+func TestClientBrowse(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	want := datatypes.NewReferenceDescription(
+		datatypes.NewTwoByteNodeID(35), true,
+		datatypes.NewFourByteExpandedNodeID(0, 2253),
+		datatypes.NewQualifiedName(0, "Server"),
+		datatypes.NewLocalizedText("", "Server"),
+		datatypes.NodeClassObject,
+		datatypes.NewFourByteExpandedNodeID(0, 2004),
+	)
+	c, err := setUpClient(ctx, func(srv services.Service) services.Service {
+		req, ok := srv.(*services.BrowseRequest)
+		if !ok {
+			return nil
+		}
+		return services.NewBrowseResponse(newResponseHeader(req.RequestHandle), nil, datatypes.NewBrowseResult(0, nil, want))
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	res, err := c.BrowseWithContext(ctx, nil, 0, datatypes.NewBrowseDescription(
+		datatypes.NewTwoByteNodeID(85), datatypes.BrowseDirectionForward,
+		datatypes.NewTwoByteNodeID(33), true, 0, 0x3f,
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := res.Results.BrowseResults[0].References.ReferenceDescriptions; len(got) != 1 {
+		t.Fatalf("got %d references, want 1", len(got))
+	} else if diff := cmp.Diff(got[0], want); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestClientBrowseAll(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	refs := []*datatypes.ReferenceDescription{
+		datatypes.NewReferenceDescription(
+			datatypes.NewTwoByteNodeID(35), true,
+			datatypes.NewFourByteExpandedNodeID(0, 2253),
+			datatypes.NewQualifiedName(0, "Server"),
+			datatypes.NewLocalizedText("", "Server"),
+			datatypes.NodeClassObject,
+			datatypes.NewFourByteExpandedNodeID(0, 2004),
+		),
+		datatypes.NewReferenceDescription(
+			datatypes.NewTwoByteNodeID(35), true,
+			datatypes.NewTwoByteExpandedNodeID(87),
+			datatypes.NewQualifiedName(0, "Views"),
+			datatypes.NewLocalizedText("", "Views"),
+			datatypes.NodeClassObject,
+			datatypes.NewTwoByteExpandedNodeID(61),
+		),
+	}
+
+	// the server returns the references in two pages.
+	cp := []byte{0xde, 0xad, 0xbe, 0xef}
+	c, err := setUpClient(ctx, func(srv services.Service) services.Service {
+		switch req := srv.(type) {
+		case *services.BrowseRequest:
+			return services.NewBrowseResponse(newResponseHeader(req.RequestHandle), nil, datatypes.NewBrowseResult(0, cp, refs[0]))
+		case *services.BrowseNextRequest:
+			if diff := cmp.Diff(req.ContinuationPoints.ByteStrings[0].Get(), cp); diff != "" {
+				t.Error(diff)
+			}
+			return services.NewBrowseNextResponse(newResponseHeader(req.RequestHandle), nil, datatypes.NewBrowseResult(0, nil, refs[1]))
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	got, err := c.BrowseAllWithContext(ctx, datatypes.NewTwoByteNodeID(85), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(got, refs); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestClientBrowseChildren(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	descs := make(chan *datatypes.BrowseDescription, 1)
+	c, err := setUpClient(ctx, func(srv services.Service) services.Service {
+		switch req := srv.(type) {
+		case *services.BrowseRequest:
+			descs <- req.NodesToBrowse.BrowseDescriptions[0]
+			return services.NewBrowseResponse(newResponseHeader(req.RequestHandle), nil, datatypes.NewBrowseResult(0, nil))
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	node := datatypes.NewTwoByteNodeID(85)
+	if _, err := c.BrowseChildrenWithContext(ctx, node); err != nil {
+		t.Fatal(err)
+	}
+
+	want := datatypes.NewBrowseDescription(
+		node, datatypes.BrowseDirectionForward,
+		datatypes.NewTwoByteNodeID(id.HierarchicalReferences), true,
+		0, uint32(datatypes.BrowseResultMaskAll),
+	)
+	if diff := cmp.Diff(<-descs, want); diff != "" {
+		t.Error(diff)
+	}
+}
+
+// stopContext is a context which never gets done but reports context.Canceled
+// once stop is closed. It lets BrowseAll notice the cancellation between the pages
+// deterministically instead of in the middle of a request.
+type stopContext struct {
+	context.Context
+	stop chan struct{}
+}
+
+func (c *stopContext) Err() error {
+	select {
+	case <-c.stop:
+		return context.Canceled
+	default:
+		return c.Context.Err()
+	}
+}
+
+func TestClientBrowseAllReleasesOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// the context is canceled when the first page is returned and
+	// the server only reports the released continuation points.
+	bctx := &stopContext{Context: context.Background(), stop: make(chan struct{})}
+	cp := []byte{0xde, 0xad, 0xbe, 0xef}
+	released := make(chan []byte, 1)
+	c, err := setUpClient(ctx, func(srv services.Service) services.Service {
+		switch req := srv.(type) {
+		case *services.BrowseRequest:
+			close(bctx.stop)
+			return services.NewBrowseResponse(newResponseHeader(req.RequestHandle), nil, datatypes.NewBrowseResult(0, cp))
+		case *services.BrowseNextRequest:
+			if req.ReleaseContinuationPoints.Value == 1 {
+				released <- req.ContinuationPoints.ByteStrings[0].Get()
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	if _, err := c.BrowseAllWithContext(bctx, datatypes.NewTwoByteNodeID(85), nil); err != context.Canceled {
+		t.Fatalf("got error %v, want %v", err, context.Canceled)
+	}
+
+	select {
+	case got := <-released:
+		if diff := cmp.Diff(got, cp); diff != "" {
+			t.Error(diff)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("continuation point was not released")
+	}
+}
+
+// newCertificate generates a 2048 bits RSA key and a self-signed certificate of it,
+// and returns the certificate in DER and both of them in PEM.
+func newCertificate(t *testing.T, name string) (der, certPEM, keyPEM []byte) {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err = x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certPEM = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM = pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	return der, certPEM, keyPEM
+}
+
+func TestClientWithCertificate(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	_, cliCertPEM, cliKeyPEM := newCertificate(t, "client")
+	srvCert, _, srvKeyPEM := newCertificate(t, "server")
+	block, _ := pem.Decode(srvKeyPEM)
+	srvKey, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srvCfg := uasc.NewServerConfig(
+		"http://opcfoundation.org/UA/SecurityPolicy#Basic256Sha256",
+		srvCert, nil, 1111, services.SecModeSignAndEncrypt, 2222, 3600000,
+	)
+	srvCfg.PrivateKey = srvKey
+
+	c, err := setUpClientWithConfig(ctx, srvCfg, handleRead(func(*services.ReadRequest) *datatypes.DataValue {
+		return newValue(datatypes.NewFloat(1.5))
+	}),
+		WithConfig(uasc.NewClientConfigSignAndEncryptBasic256Sha256(nil, nil, srvCert, 3333, 3600000)),
+		WithCertificate(cliCertPEM, cliKeyPEM),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	// the thumbprint of the certificate supplied to the client is kept by the SecureChannel
+	// accepted, not in the Config shared by the server.
+	if srvCfg.Thumbprint != nil {
+		t.Errorf("got Config updated by the SecureChannel: %x", srvCfg.Thumbprint)
+	}
+
+	node := datatypes.NewReadValueID(datatypes.NewNumericNodeID(0, 2256), datatypes.AttributeIDValue, "", 0, "")
+	if _, err := c.ReadWithContext(ctx, 0, services.TimestampsToReturnNeither, node); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// tamperConn is a net.Conn of the server which flips the last byte of the MSG chunk
+// written for each token in tamper, as if it is tampered on the way.
+type tamperConn struct {
+	net.Conn
+	tamper chan struct{}
+}
+
+func (c *tamperConn) Write(b []byte) (int, error) {
+	if len(b) < 3 || string(b[:3]) != uasc.MessageTypeMessage {
+		return c.Conn.Write(b)
+	}
+	select {
+	case <-c.tamper:
+	default:
+		return c.Conn.Write(b)
+	}
+
+	tampered := append([]byte{}, b...)
+	tampered[len(tampered)-1] ^= 0xff
+	return c.Conn.Write(tampered)
+}
+
+func TestClientTamperedResponse(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	_, cliCertPEM, cliKeyPEM := newCertificate(t, "client")
+	srvCert, _, srvKeyPEM := newCertificate(t, "server")
+	block, _ := pem.Decode(srvKeyPEM)
+	srvKey, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srvCfg := uasc.NewServerConfig(
+		"http://opcfoundation.org/UA/SecurityPolicy#Basic256Sha256",
+		srvCert, nil, 1111, services.SecModeSignAndEncrypt, 2222, 3600000,
+	)
+	srvCfg.PrivateKey = srvKey
+
+	tamper := make(chan struct{}, 1)
+	wrap := func(conn net.Conn) net.Conn {
+		return &tamperConn{Conn: conn, tamper: tamper}
+	}
+	c, err := setUpClientWithConn(ctx, wrap, srvCfg, handleRead(func(*services.ReadRequest) *datatypes.DataValue {
+		return newValue(datatypes.NewFloat(1.5))
+	}),
+		WithConfig(uasc.NewClientConfigSignAndEncryptBasic256Sha256(nil, nil, srvCert, 3333, 3600000)),
+		WithCertificate(cliCertPEM, cliKeyPEM),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	tamper <- struct{}{}
+	node := datatypes.NewReadValueID(datatypes.NewNumericNodeID(0, 2256), datatypes.AttributeIDValue, "", 0, "")
+	_, err = c.ReadWithContext(ctx, 0, services.TimestampsToReturnNeither, node)
+	if got, want := errors.Cause(err), uasc.ErrInvalidMessageSignature; got != want {
+		t.Errorf("got error %v want %v", got, want)
+	}
+}
+
+// replayConn is a net.Conn of the server which writes the last MSG chunk again instead
+// of the next one for each token in replay, as if it is replayed on the way.
+type replayConn struct {
+	net.Conn
+	replay chan struct{}
+	last   []byte
+}
+
+func (c *replayConn) Write(b []byte) (int, error) {
+	if len(b) < 3 || string(b[:3]) != uasc.MessageTypeMessage {
+		return c.Conn.Write(b)
+	}
+	select {
+	case <-c.replay:
+		if _, err := c.Conn.Write(c.last); err != nil {
+			return 0, err
+		}
+		return len(b), nil
+	default:
+	}
+	c.last = append([]byte{}, b...)
+	return c.Conn.Write(b)
+}
+
+func TestClientReplayedResponse(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	replay := make(chan struct{}, 1)
+	wrap := func(conn net.Conn) net.Conn {
+		return &replayConn{Conn: conn, replay: replay}
+	}
+	srvCfg := uasc.NewServerConfig(policyURI, nil, nil, 1111, services.SecModeNone, 2222, 3600000)
+	c, err := setUpClientWithConn(ctx, wrap, srvCfg, handleRead(func(*services.ReadRequest) *datatypes.DataValue {
+		return newValue(datatypes.NewFloat(10))
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	node := datatypes.NewReadValueID(datatypes.NewNumericNodeID(0, 2256), datatypes.AttributeIDValue, "", 0, "")
+	if _, err := c.ReadWithContext(ctx, 0, services.TimestampsToReturnNeither, node); err != nil {
+		t.Fatal(err)
+	}
+
+	replay <- struct{}{}
+	_, err = c.ReadWithContext(ctx, 0, services.TimestampsToReturnNeither, node)
+	if got, want := errors.Cause(err), uasc.ErrInvalidSequenceNumber; got != want {
+		t.Errorf("got error %v want %v", got, want)
+	}
+}
+
+// recordingDialer dials the TCP connections directly, recording the addresses.
+type recordingDialer struct {
+	mu    sync.Mutex
+	addrs []string
+	err   error
+}
+
+func (d *recordingDialer) Dial(network, addr string) (net.Conn, error) {
+	d.mu.Lock()
+	d.addrs = append(d.addrs, network+" "+addr)
+	d.mu.Unlock()
+	if d.err != nil {
+		return nil, d.err
+	}
+	return net.Dial(network, addr)
+}
+
+func TestClientWithDialer(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	t.Run("dialed", func(t *testing.T) {
+		d := &recordingDialer{}
+		c, err := setUpClient(ctx, func(services.Service) services.Service { return nil }, WithDialer(d))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer c.Close()
+
+		d.mu.Lock()
+		defer d.mu.Unlock()
+		if diff := cmp.Diff(d.addrs, []string{"tcp 127.0.0.1:4841"}); diff != "" {
+			t.Error(diff)
+		}
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		want := errors.New("proxy refused")
+		c := NewClient("opc.tcp://plc.example.com/foo", WithDialer(&recordingDialer{err: want}))
+		if err := c.Connect(ctx); err != want {
+			t.Fatalf("got error %v want %v", err, want)
+		}
+	})
+}
+
+func TestClientWithSecurityPolicy(t *testing.T) {
+	_, cliCertPEM, cliKeyPEM := newCertificate(t, "client")
+	srvCert, _, srvKeyPEM := newCertificate(t, "server")
+	block, _ := pem.Decode(srvKeyPEM)
+	srvKey, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherCert, _, _ := newCertificate(t, "other")
+	trusted, err := uasc.NewTrustStore(srvCert)
+	if err != nil {
+		t.Fatal(err)
+	}
+	untrusted, err := uasc.NewTrustStore(otherCert)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name    string
+		cliMode uint32
+		srvMode uint32
+		verify  Option
+		ok      bool
+	}{
+		{"sign", services.SecModeSign, services.SecModeSign, WithTrustStore(trusted, ""), true},
+		{"sign-and-encrypt", services.SecModeSignAndEncrypt, services.SecModeSignAndEncrypt, WithTrustStore(trusted, ""), true},
+		{"insecure-skip-verify", services.SecModeSign, services.SecModeSign, WithInsecureSkipVerify(), true},
+		// the server accepts only the mode the client advertises.
+		{"mode-mismatch", services.SecModeSign, services.SecModeSignAndEncrypt, WithTrustStore(trusted, ""), false},
+		{"untrusted", services.SecModeSign, services.SecModeSign, WithTrustStore(untrusted, ""), false},
+		{"uri-mismatch", services.SecModeSign, services.SecModeSign, WithTrustStore(trusted, "urn:gopcua:server"), false},
+		// the certificate of the server should be verified in either way.
+		{"no-verification", services.SecModeSign, services.SecModeSign, WithAnonymous(), false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			srvCfg := uasc.NewServerConfig(
+				"http://opcfoundation.org/UA/SecurityPolicy#Basic256Sha256",
+				srvCert, nil, 1111, tc.srvMode, 2222, 3600000,
+			)
+			srvCfg.PrivateKey = srvKey
+
+			c, err := setUpClientWithConfig(ctx, srvCfg, handleRead(func(*services.ReadRequest) *datatypes.DataValue {
+				return newValue(datatypes.NewFloat(1.5))
+			}),
+				WithSecurityPolicy("http://opcfoundation.org/UA/SecurityPolicy#Basic256Sha256", tc.cliMode, srvCert),
+				WithCertificate(cliCertPEM, cliKeyPEM),
+				tc.verify,
+			)
+			if !tc.ok {
+				if err == nil {
+					c.Close()
+					t.Fatal("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer c.Close()
+
+			if got := c.cfg.SecurityMode; got != tc.cliMode {
+				t.Errorf("got SecurityMode %d want %d", got, tc.cliMode)
+			}
+			node := datatypes.NewReadValueID(datatypes.NewNumericNodeID(0, 2256), datatypes.AttributeIDValue, "", 0, "")
+			if _, err := c.ReadWithContext(ctx, 0, services.TimestampsToReturnNeither, node); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestWithCertificateFile(t *testing.T) {
+	cert, certPEM, keyPEM := newCertificate(t, "client")
+
+	dir, err := ioutil.TempDir("", "gopcua")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	certPath, keyPath := filepath.Join(dir, "cert.pem"), filepath.Join(dir, "key.pem")
+	if err := ioutil.WriteFile(certPath, certPEM, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(keyPath, keyPEM, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	c := NewClient(endpoint, WithCertificateFile(certPath, keyPath))
+	if c.optErr != nil {
+		t.Fatal(c.optErr)
+	}
+	if diff := cmp.Diff(c.cert, cert); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestWithCertificateMismatch(t *testing.T) {
+	_, certPEM, _ := newCertificate(t, "client")
+	_, _, keyPEM := newCertificate(t, "other")
+
+	c := NewClient(endpoint, WithCertificate(certPEM, keyPEM))
+	if err := c.Connect(context.Background()); err == nil {
+		t.Fatal("expected error for mismatched certificate and key, got nil")
+	}
+}
+
+func TestWithUserName(t *testing.T) {
+	c := NewClient(endpoint, WithUserName("user", "pass"))
+	if err := c.prepare(); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(c.sessCfg.UserIdentityToken, datatypes.NewUserNameIdentityToken("", "user", []byte("pass"), "")); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestWithX509Identity(t *testing.T) {
+	der, certPEM, keyPEM := newCertificate(t, "user")
+
+	c := NewClient(endpoint, WithX509Identity(certPEM, keyPEM))
+	if err := c.prepare(); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(c.sessCfg.UserIdentityToken, datatypes.NewX509IdentityToken("", string(der))); diff != "" {
+		t.Error(diff)
+	}
+	if c.sessCfg.UserTokenPrivateKey == nil {
+		t.Error("private key of the user certificate is not set")
+	}
+}
+
+// recordConn is a net.Conn of the server which records the types of the services
+// received, and drops the services sent for which drop returns true as if the server
+// did not respond.
+//
+// The services received are not dropped, as the SecureChannel rejects the chunks after
+// the gap in the SequenceNumbers.
+type recordConn struct {
+	net.Conn
+	drop     func(services.Service) bool
+	received chan string
+	// eof is closed when the connection is closed by the client.
+	eof chan struct{}
+}
+
+func newRecordConn(conn net.Conn, drop func(services.Service) bool) *recordConn {
+	return &recordConn{
+		Conn:     conn,
+		drop:     drop,
+		received: make(chan string, 100),
+		eof:      make(chan struct{}),
+	}
+}
+
+func (c *recordConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	if err != nil {
+		select {
+		case <-c.eof:
+		default:
+			close(c.eof)
+		}
+		return n, err
+	}
+
+	if m, err := uasc.Decode(b[:n]); err == nil && m.Service != nil {
+		c.received <- fmt.Sprintf("%T", m.Service)
+	}
+	return n, nil
+}
+
+func (c *recordConn) Write(b []byte) (int, error) {
+	if m, err := uasc.Decode(b); err == nil && m.Service != nil && c.drop != nil && c.drop(m.Service) {
+		return len(b), nil
+	}
+	return c.Conn.Write(b)
+}
+
+// closingServices returns the services received which are sent by Close.
+func (c *recordConn) closingServices() []string {
+	var got []string
+	for {
+		select {
+		case s := <-c.received:
+			switch s {
+			case "*services.DeleteSubscriptionsRequest", "*services.CloseSessionRequest", "*services.CloseSecureChannelRequest":
+				got = append(got, s)
+			}
+		default:
+			return got
+		}
+	}
+}
+
+func TestClientClose(t *testing.T) {
+	cases := []struct {
+		name string
+		drop func(services.Service) bool
+		want []string
+		err  bool
+	}{
+		{
+			name: "ordered",
+			want: []string{
+				"*services.DeleteSubscriptionsRequest",
+				"*services.CloseSessionRequest",
+				"*services.CloseSecureChannelRequest",
+			},
+		},
+		{
+			// the server does not respond to CloseSessionRequest.
+			name: "close-session-timeout",
+			drop: func(srv services.Service) bool {
+				_, ok := srv.(*services.CloseSessionResponse)
+				return ok
+			},
+			want: []string{
+				"*services.DeleteSubscriptionsRequest",
+				"*services.CloseSessionRequest",
+				"*services.CloseSecureChannelRequest",
+			},
+			err: true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			var rec *recordConn
+			wrap := func(conn net.Conn) net.Conn {
+				rec = newRecordConn(conn, c.drop)
+				return rec
+			}
+			srvCfg := uasc.NewServerConfig(policyURI, nil, nil, 1111, services.SecModeNone, 2222, 3600000)
+			cli, err := setUpClientWithConn(ctx, wrap, srvCfg, func(srv services.Service) services.Service {
+				switch req := srv.(type) {
+				case *services.CreateSubscriptionRequest:
+					return services.NewCreateSubscriptionResponse(newResponseHeader(req.RequestHandle), 7, 100, 60, 20)
+				case *services.DeleteSubscriptionsRequest:
+					return services.NewDeleteSubscriptionsResponse(newResponseHeader(req.RequestHandle), nil, 0)
+				}
+				return nil
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := cli.CreateSubscription(100, 60, 20, 0, 0); err != nil {
+				t.Fatal(err)
+			}
+
+			start := time.Now()
+			err = cli.Close()
+			if (err != nil) != c.err {
+				t.Errorf("got error %v, want error %v", err, c.err)
+			}
+			if d := time.Since(start); d > 2*closeTimeout {
+				t.Errorf("Close took %v", d)
+			}
+
+			// the socket is closed even if a step fails.
+			select {
+			case <-rec.eof:
+			case <-time.After(5 * time.Second):
+				t.Fatal("the connection is not closed")
+			}
+			if diff := cmp.Diff(rec.closingServices(), c.want); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
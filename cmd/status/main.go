@@ -29,6 +29,7 @@ func main() {
 	b.WriteString("package status\n// StatusCode definitions, generated automatically by cmd/status.\nconst(")
 
 	// loop over each row
+	var names []string
 	reader := csv.NewReader(file)
 	for {
 		record, err := reader.Read()
@@ -38,19 +39,25 @@ func main() {
 			}
 			// if the error is raised due to wrong number of fields in some lines, just ignore it and write.
 			// this is caused because the fields in StatusCode.csv are not quoted, and the last field contains comma.
-			if perr, ok := err.(*csv.ParseError); ok {
-				if perr.Err == csv.ErrFieldCount {
-					b.WriteString(fmt.Sprintf("%s = %s\n", record[0], record[1]))
-					continue
-				}
+			perr, ok := err.(*csv.ParseError)
+			if !ok || perr.Err != csv.ErrFieldCount {
+				panic(err)
 			}
-			panic(err)
 		}
 		b.WriteString(fmt.Sprintf("%s = %s\n", record[0], record[1]))
+		names = append(names, record[0])
 	}
 
 	// close const(...) bracket
-	b.Write([]byte(")"))
+	b.Write([]byte(")\n"))
+
+	// write the table of the names
+	b.WriteString("\n// names is the symbolic names of the StatusCodes, generated automatically by cmd/status.\n")
+	b.WriteString("var names = map[StatusCode]string{\n")
+	for _, name := range names {
+		b.WriteString(fmt.Sprintf("%s: %q,\n", name, name))
+	}
+	b.Write([]byte("}"))
 
 	// format file
 	fmt, err := format.Source(b.Bytes())
@@ -0,0 +1,224 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package datatypes
+
+import (
+	"encoding/binary"
+
+	"github.com/wmnsk/gopcua/errors"
+)
+
+// AddNodesItem is a Node to be added by AddNodes, with the Reference from its parent.
+//
+// NodeAttributes is the NodeAttributes of the NodeClass, such as VariableAttributes,
+// encoded as an ExtensionObject. RequestedNewNodeID is the null ExpandedNodeID to let
+// the Server assign the NodeID.
+//
+// Specification: Part 4, 5.7.2.2
+type AddNodesItem struct {
+	ParentNodeID       *ExpandedNodeID
+	ReferenceTypeID    *NodeID
+	RequestedNewNodeID *ExpandedNodeID
+	BrowseName         *QualifiedName
+	NodeClass          NodeClass
+	NodeAttributes     *ExtensionObject
+	TypeDefinition     *ExpandedNodeID
+}
+
+// NewAddNodesItem creates a new AddNodesItem of the NodeClass of attrs.
+//
+// If requested is nil, the NodeID is assigned by the Server. The typeDef should be nil
+// for the NodeClasses other than Object and Variable.
+func NewAddNodesItem(parent *ExpandedNodeID, refType *NodeID, requested *ExpandedNodeID, browseName *QualifiedName, attrs NodeClassAttributes, typeDef *ExpandedNodeID) *AddNodesItem {
+	if requested == nil {
+		requested = NewTwoByteExpandedNodeID(0)
+	}
+	if typeDef == nil {
+		typeDef = NewTwoByteExpandedNodeID(0)
+	}
+	a := &AddNodesItem{
+		ParentNodeID:       parent,
+		ReferenceTypeID:    refType,
+		RequestedNewNodeID: requested,
+		BrowseName:         browseName,
+		NodeClass:          NodeClassUnspecified,
+		NodeAttributes:     NewNullExtensionObject(),
+		TypeDefinition:     typeDef,
+	}
+	if attrs != nil {
+		a.NodeClass = attrs.NodeClass()
+		a.NodeAttributes = NewExtensionObject(ExtensionObjectBinary, attrs)
+	}
+
+	return a
+}
+
+// DecodeAddNodesItem decodes given bytes into AddNodesItem.
+func DecodeAddNodesItem(b []byte) (*AddNodesItem, error) {
+	a := &AddNodesItem{}
+	if err := a.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// DecodeFromBytes decodes given bytes into AddNodesItem.
+func (a *AddNodesItem) DecodeFromBytes(b []byte) error {
+	dec := Decoder{b: b}
+	a.ParentNodeID = &ExpandedNodeID{}
+	if err := dec.ReadData(a.ParentNodeID); err != nil {
+		return err
+	}
+	a.ReferenceTypeID = &NodeID{}
+	if err := dec.ReadData(a.ReferenceTypeID); err != nil {
+		return err
+	}
+	a.RequestedNewNodeID = &ExpandedNodeID{}
+	if err := dec.ReadData(a.RequestedNewNodeID); err != nil {
+		return err
+	}
+	a.BrowseName = &QualifiedName{}
+	if err := dec.ReadData(a.BrowseName); err != nil {
+		return err
+	}
+	class, err := dec.ReadUint32()
+	if err != nil {
+		return errors.NewErrTooShortToDecode(a, "should contain NodeClass")
+	}
+	a.NodeClass = NodeClass(class)
+	a.NodeAttributes = &ExtensionObject{}
+	if err := dec.ReadData(a.NodeAttributes); err != nil {
+		return err
+	}
+	a.TypeDefinition = &ExpandedNodeID{}
+	return dec.ReadData(a.TypeDefinition)
+}
+
+// Serialize serializes AddNodesItem into bytes.
+func (a *AddNodesItem) Serialize() ([]byte, error) {
+	b := make([]byte, a.Len())
+	if err := a.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes AddNodesItem into bytes.
+func (a *AddNodesItem) SerializeTo(b []byte) error {
+	if len(b) < a.Len() {
+		return errors.NewErrInvalidLength(a, "bytes should be longer")
+	}
+
+	// b is long enough that enc never reallocates.
+	enc := Encoder{buf: b[:0]}
+	for _, v := range []Encodable{a.ParentNodeID, a.ReferenceTypeID, a.RequestedNewNodeID, a.BrowseName} {
+		if err := enc.WriteData(v); err != nil {
+			return err
+		}
+	}
+	enc.WriteUint32(uint32(a.NodeClass))
+	if err := enc.WriteData(a.NodeAttributes); err != nil {
+		return err
+	}
+	return enc.WriteData(a.TypeDefinition)
+}
+
+// Len returns the actual length of AddNodesItem in int.
+func (a *AddNodesItem) Len() int {
+	return a.ParentNodeID.Len() + a.ReferenceTypeID.Len() + a.RequestedNewNodeID.Len() +
+		a.BrowseName.Len() + 4 + a.NodeAttributes.Len() + a.TypeDefinition.Len()
+}
+
+// AddNodesItemArray represents an array of AddNodesItems.
+// It does not correspond to a certain type from the specification
+// but makes encoding and decoding easier.
+type AddNodesItemArray struct {
+	ArraySize int32
+	Items     []*AddNodesItem
+}
+
+// NewAddNodesItemArray creates a new AddNodesItemArray from multiple AddNodesItems.
+func NewAddNodesItemArray(items []*AddNodesItem) *AddNodesItemArray {
+	if items == nil {
+		return &AddNodesItemArray{
+			ArraySize: 0,
+		}
+	}
+
+	return &AddNodesItemArray{
+		ArraySize: int32(len(items)),
+		Items:     items,
+	}
+}
+
+// DecodeAddNodesItemArray decodes given bytes into AddNodesItemArray.
+func DecodeAddNodesItemArray(b []byte) (*AddNodesItemArray, error) {
+	a := &AddNodesItemArray{}
+	if err := a.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// DecodeFromBytes decodes given bytes into AddNodesItemArray.
+func (a *AddNodesItemArray) DecodeFromBytes(b []byte) error {
+	if len(b) < 4 {
+		return errors.NewErrTooShortToDecode(a, "should be longer than 4 bytes")
+	}
+	a.ArraySize = int32(binary.LittleEndian.Uint32(b[:4]))
+	if a.ArraySize <= 0 {
+		return nil
+	}
+
+	offset := 4
+	for i := 1; i <= int(a.ArraySize); i++ {
+		item, err := DecodeAddNodesItem(b[offset:])
+		if err != nil {
+			return err
+		}
+		a.Items = append(a.Items, item)
+		offset += item.Len()
+	}
+
+	return nil
+}
+
+// Serialize serializes AddNodesItemArray into bytes.
+func (a *AddNodesItemArray) Serialize() ([]byte, error) {
+	b := make([]byte, a.Len())
+	if err := a.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes AddNodesItemArray into bytes.
+func (a *AddNodesItemArray) SerializeTo(b []byte) error {
+	offset := 4
+	binary.LittleEndian.PutUint32(b[:4], uint32(a.ArraySize))
+
+	for _, item := range a.Items {
+		if err := item.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += item.Len()
+	}
+
+	return nil
+}
+
+// Len returns the actual length in int.
+func (a *AddNodesItemArray) Len() int {
+	l := 4
+	for _, item := range a.Items {
+		l += item.Len()
+	}
+
+	return l
+}
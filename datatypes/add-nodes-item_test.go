@@ -0,0 +1,113 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package datatypes
+
+import (
+	"testing"
+
+	"github.com/wmnsk/gopcua/utils/codectest"
+)
+
+func TestAddNodesItem(t *testing.T) {
+	cases := []codectest.Case{
+		{
+			Name: "variable",
+			Struct: NewAddNodesItem(
+				NewFourByteExpandedNodeID(0, 85), NewFourByteNodeID(0, 47), nil,
+				NewQualifiedName(2, "Temperature"),
+				NewVariableAttributes(nil, nil, NewVariant(NewInt32(42)), NewTwoByteNodeID(6), -1, 3),
+				NewFourByteExpandedNodeID(0, 63),
+			),
+			Bytes: []byte{
+				// ParentNodeID
+				0x01, 0x00, 0x55, 0x00,
+				// ReferenceTypeID
+				0x01, 0x00, 0x2f, 0x00,
+				// RequestedNewNodeID
+				0x00, 0x00,
+				// BrowseName
+				0x02, 0x00, 0x0b, 0x00, 0x00, 0x00, 0x54, 0x65,
+				0x6d, 0x70, 0x65, 0x72, 0x61, 0x74, 0x75, 0x72, 0x65,
+				// NodeClass
+				0x02, 0x00, 0x00, 0x00,
+				// NodeAttributes: TypeID
+				0x01, 0x00, 0x65, 0x01,
+				// EncodingMask
+				0x01,
+				// Length
+				0x28, 0x00, 0x00, 0x00,
+				// VariableAttributes
+				0x11, 0x00, 0x29, 0x00, 0x00, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x06, 0x2a,
+				0x00, 0x00, 0x00, 0x00, 0x06, 0xff, 0xff, 0xff,
+				0xff, 0x00, 0x00, 0x00, 0x00, 0x03, 0x03, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				// TypeDefinition
+				0x01, 0x00, 0x3f, 0x00,
+			},
+		},
+		{
+			Name: "no-attributes",
+			Struct: NewAddNodesItem(
+				NewFourByteExpandedNodeID(0, 85), NewFourByteNodeID(0, 35),
+				NewStringExpandedNodeID(2, "foo"), NewQualifiedName(2, "foo"), nil, nil,
+			),
+			Bytes: []byte{
+				// ParentNodeID
+				0x01, 0x00, 0x55, 0x00,
+				// ReferenceTypeID
+				0x01, 0x00, 0x23, 0x00,
+				// RequestedNewNodeID
+				0x03, 0x02, 0x00, 0x03, 0x00, 0x00, 0x00, 0x66, 0x6f, 0x6f,
+				// BrowseName
+				0x02, 0x00, 0x03, 0x00, 0x00, 0x00, 0x66, 0x6f, 0x6f,
+				// NodeClass
+				0x00, 0x00, 0x00, 0x00,
+				// NodeAttributes
+				0x00, 0x00, 0x00,
+				// TypeDefinition
+				0x00, 0x00,
+			},
+		},
+	}
+	codectest.Run(t, cases, func(b []byte) (codectest.S, error) {
+		return DecodeAddNodesItem(b)
+	})
+}
+
+func TestAddNodesItemArray(t *testing.T) {
+	cases := []codectest.Case{
+		{
+			Name: "normal",
+			Struct: NewAddNodesItemArray([]*AddNodesItem{
+				NewAddNodesItem(
+					NewFourByteExpandedNodeID(0, 85), NewFourByteNodeID(0, 35), nil,
+					NewQualifiedName(2, "foo"), nil, nil,
+				),
+			}),
+			Bytes: []byte{
+				// ArraySize
+				0x01, 0x00, 0x00, 0x00,
+				// ParentNodeID
+				0x01, 0x00, 0x55, 0x00,
+				// ReferenceTypeID
+				0x01, 0x00, 0x23, 0x00,
+				// RequestedNewNodeID
+				0x00, 0x00,
+				// BrowseName
+				0x02, 0x00, 0x03, 0x00, 0x00, 0x00, 0x66, 0x6f, 0x6f,
+				// NodeClass
+				0x00, 0x00, 0x00, 0x00,
+				// NodeAttributes
+				0x00, 0x00, 0x00,
+				// TypeDefinition
+				0x00, 0x00,
+			},
+		},
+	}
+	codectest.Run(t, cases, func(b []byte) (codectest.S, error) {
+		return DecodeAddNodesItemArray(b)
+	})
+}
@@ -0,0 +1,172 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package datatypes
+
+import (
+	"encoding/binary"
+
+	"github.com/wmnsk/gopcua/errors"
+)
+
+// AddNodesResult is the result of AddNodes for a single AddNodesItem.
+//
+// AddedNodeID is the NodeID assigned to the Node, which is the null NodeID
+// if the Node is not added.
+//
+// Specification: Part 4, 5.7.2.2
+type AddNodesResult struct {
+	StatusCode  uint32
+	AddedNodeID *NodeID
+}
+
+// NewAddNodesResult creates a new AddNodesResult.
+func NewAddNodesResult(code uint32, node *NodeID) *AddNodesResult {
+	return &AddNodesResult{
+		StatusCode:  code,
+		AddedNodeID: node,
+	}
+}
+
+// DecodeAddNodesResult decodes given bytes into AddNodesResult.
+func DecodeAddNodesResult(b []byte) (*AddNodesResult, error) {
+	r := &AddNodesResult{}
+	if err := r.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// DecodeFromBytes decodes given bytes into AddNodesResult.
+func (r *AddNodesResult) DecodeFromBytes(b []byte) error {
+	if len(b) < 4 {
+		return errors.NewErrTooShortToDecode(r, "should be longer than 4 bytes")
+	}
+	r.StatusCode = binary.LittleEndian.Uint32(b[:4])
+
+	r.AddedNodeID = &NodeID{}
+	return r.AddedNodeID.DecodeFromBytes(b[4:])
+}
+
+// Serialize serializes AddNodesResult into bytes.
+func (r *AddNodesResult) Serialize() ([]byte, error) {
+	b := make([]byte, r.Len())
+	if err := r.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes AddNodesResult into bytes.
+func (r *AddNodesResult) SerializeTo(b []byte) error {
+	binary.LittleEndian.PutUint32(b[:4], r.StatusCode)
+
+	if r.AddedNodeID != nil {
+		return r.AddedNodeID.SerializeTo(b[4:])
+	}
+
+	return nil
+}
+
+// Len returns the actual length of AddNodesResult in int.
+func (r *AddNodesResult) Len() int {
+	l := 4
+	if r.AddedNodeID != nil {
+		l += r.AddedNodeID.Len()
+	}
+
+	return l
+}
+
+// AddNodesResultArray represents an array of AddNodesResults.
+// It does not correspond to a certain type from the specification
+// but makes encoding and decoding easier.
+type AddNodesResultArray struct {
+	ArraySize int32
+	Results   []*AddNodesResult
+}
+
+// NewAddNodesResultArray creates a new AddNodesResultArray from multiple AddNodesResults.
+func NewAddNodesResultArray(results []*AddNodesResult) *AddNodesResultArray {
+	if results == nil {
+		return &AddNodesResultArray{
+			ArraySize: 0,
+		}
+	}
+
+	return &AddNodesResultArray{
+		ArraySize: int32(len(results)),
+		Results:   results,
+	}
+}
+
+// DecodeAddNodesResultArray decodes given bytes into AddNodesResultArray.
+func DecodeAddNodesResultArray(b []byte) (*AddNodesResultArray, error) {
+	a := &AddNodesResultArray{}
+	if err := a.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// DecodeFromBytes decodes given bytes into AddNodesResultArray.
+func (a *AddNodesResultArray) DecodeFromBytes(b []byte) error {
+	if len(b) < 4 {
+		return errors.NewErrTooShortToDecode(a, "should be longer than 4 bytes")
+	}
+	a.ArraySize = int32(binary.LittleEndian.Uint32(b[:4]))
+	if a.ArraySize <= 0 {
+		return nil
+	}
+
+	offset := 4
+	for i := 1; i <= int(a.ArraySize); i++ {
+		r, err := DecodeAddNodesResult(b[offset:])
+		if err != nil {
+			return err
+		}
+		a.Results = append(a.Results, r)
+		offset += r.Len()
+	}
+
+	return nil
+}
+
+// Serialize serializes AddNodesResultArray into bytes.
+func (a *AddNodesResultArray) Serialize() ([]byte, error) {
+	b := make([]byte, a.Len())
+	if err := a.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes AddNodesResultArray into bytes.
+func (a *AddNodesResultArray) SerializeTo(b []byte) error {
+	offset := 4
+	binary.LittleEndian.PutUint32(b[:4], uint32(a.ArraySize))
+
+	for _, r := range a.Results {
+		if err := r.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += r.Len()
+	}
+
+	return nil
+}
+
+// Len returns the actual length in int.
+func (a *AddNodesResultArray) Len() int {
+	l := 4
+	for _, r := range a.Results {
+		l += r.Len()
+	}
+
+	return l
+}
@@ -0,0 +1,61 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package datatypes
+
+import (
+	"testing"
+
+	"github.com/wmnsk/gopcua/utils/codectest"
+)
+
+func TestAddNodesResult(t *testing.T) {
+	cases := []codectest.Case{
+		{
+			Name:   "added",
+			Struct: NewAddNodesResult(0, NewStringNodeID(2, "foo")),
+			Bytes: []byte{
+				// StatusCode
+				0x00, 0x00, 0x00, 0x00,
+				// AddedNodeID
+				0x03, 0x02, 0x00, 0x03, 0x00, 0x00, 0x00, 0x66, 0x6f, 0x6f,
+			},
+		},
+		{
+			Name:   "failed",
+			Struct: NewAddNodesResult(0x805e0000, NewTwoByteNodeID(0)),
+			Bytes: []byte{
+				// StatusCode
+				0x00, 0x00, 0x5e, 0x80,
+				// AddedNodeID
+				0x00, 0x00,
+			},
+		},
+	}
+	codectest.Run(t, cases, func(b []byte) (codectest.S, error) {
+		return DecodeAddNodesResult(b)
+	})
+}
+
+func TestAddNodesResultArray(t *testing.T) {
+	cases := []codectest.Case{
+		{
+			Name: "normal",
+			Struct: NewAddNodesResultArray([]*AddNodesResult{
+				NewAddNodesResult(0, NewFourByteNodeID(2, 1000)),
+				NewAddNodesResult(0x805e0000, NewTwoByteNodeID(0)),
+			}),
+			Bytes: []byte{
+				// ArraySize
+				0x02, 0x00, 0x00, 0x00,
+				// AddNodesResults
+				0x00, 0x00, 0x00, 0x00, 0x01, 0x02, 0xe8, 0x03,
+				0x00, 0x00, 0x5e, 0x80, 0x00, 0x00,
+			},
+		},
+	}
+	codectest.Run(t, cases, func(b []byte) (codectest.S, error) {
+		return DecodeAddNodesResultArray(b)
+	})
+}
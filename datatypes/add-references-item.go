@@ -0,0 +1,209 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package datatypes
+
+import (
+	"encoding/binary"
+
+	"github.com/wmnsk/gopcua/errors"
+)
+
+// AddReferencesItem is a Reference to be added by AddReferences.
+//
+// The TargetNodeID is an ExpandedNodeID as the target may be in another server,
+// of which the URI is given in TargetServerURI. TargetNodeClass should be the
+// NodeClass of the target if it is in another server.
+//
+// Specification: Part 4, 5.7.3.2
+type AddReferencesItem struct {
+	SourceNodeID    *NodeID
+	ReferenceTypeID *NodeID
+	IsForward       *Boolean
+	TargetServerURI *String
+	TargetNodeID    *ExpandedNodeID
+	TargetNodeClass NodeClass
+}
+
+// NewAddReferencesItem creates a new AddReferencesItem from source to target in the local server.
+// If isForward is false, the Reference is added in the inverse direction, from target to source.
+func NewAddReferencesItem(source, refType *NodeID, isForward bool, target *ExpandedNodeID, targetClass NodeClass) *AddReferencesItem {
+	return &AddReferencesItem{
+		SourceNodeID:    source,
+		ReferenceTypeID: refType,
+		IsForward:       NewBoolean(isForward),
+		TargetServerURI: &String{},
+		TargetNodeID:    target,
+		TargetNodeClass: targetClass,
+	}
+}
+
+// DecodeAddReferencesItem decodes given bytes into AddReferencesItem.
+func DecodeAddReferencesItem(b []byte) (*AddReferencesItem, error) {
+	a := &AddReferencesItem{}
+	if err := a.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// DecodeFromBytes decodes given bytes into AddReferencesItem.
+func (a *AddReferencesItem) DecodeFromBytes(b []byte) error {
+	dec := Decoder{b: b}
+	a.SourceNodeID = &NodeID{}
+	if err := dec.ReadData(a.SourceNodeID); err != nil {
+		return err
+	}
+	a.ReferenceTypeID = &NodeID{}
+	if err := dec.ReadData(a.ReferenceTypeID); err != nil {
+		return err
+	}
+	if len(dec.Remaining()) < 1 {
+		return errors.NewErrTooShortToDecode(a, "should contain IsForward")
+	}
+	a.IsForward = &Boolean{}
+	if err := dec.ReadData(a.IsForward); err != nil {
+		return err
+	}
+	a.TargetServerURI = &String{}
+	if err := dec.ReadData(a.TargetServerURI); err != nil {
+		return err
+	}
+	a.TargetNodeID = &ExpandedNodeID{}
+	if err := dec.ReadData(a.TargetNodeID); err != nil {
+		return err
+	}
+	class, err := dec.ReadUint32()
+	if err != nil {
+		return errors.NewErrTooShortToDecode(a, "should contain TargetNodeClass")
+	}
+	a.TargetNodeClass = NodeClass(class)
+
+	return nil
+}
+
+// Serialize serializes AddReferencesItem into bytes.
+func (a *AddReferencesItem) Serialize() ([]byte, error) {
+	b := make([]byte, a.Len())
+	if err := a.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes AddReferencesItem into bytes.
+func (a *AddReferencesItem) SerializeTo(b []byte) error {
+	if len(b) < a.Len() {
+		return errors.NewErrInvalidLength(a, "bytes should be longer")
+	}
+
+	// b is long enough that enc never reallocates.
+	enc := Encoder{buf: b[:0]}
+	for _, v := range []Encodable{a.SourceNodeID, a.ReferenceTypeID, a.IsForward, a.TargetServerURI, a.TargetNodeID} {
+		if err := enc.WriteData(v); err != nil {
+			return err
+		}
+	}
+	enc.WriteUint32(uint32(a.TargetNodeClass))
+
+	return nil
+}
+
+// Len returns the actual length of AddReferencesItem in int.
+func (a *AddReferencesItem) Len() int {
+	return a.SourceNodeID.Len() + a.ReferenceTypeID.Len() + a.IsForward.Len() +
+		a.TargetServerURI.Len() + a.TargetNodeID.Len() + 4
+}
+
+// AddReferencesItemArray represents an array of AddReferencesItems.
+// It does not correspond to a certain type from the specification
+// but makes encoding and decoding easier.
+type AddReferencesItemArray struct {
+	ArraySize int32
+	Items     []*AddReferencesItem
+}
+
+// NewAddReferencesItemArray creates a new AddReferencesItemArray from multiple AddReferencesItems.
+func NewAddReferencesItemArray(items []*AddReferencesItem) *AddReferencesItemArray {
+	if items == nil {
+		return &AddReferencesItemArray{
+			ArraySize: 0,
+		}
+	}
+
+	return &AddReferencesItemArray{
+		ArraySize: int32(len(items)),
+		Items:     items,
+	}
+}
+
+// DecodeAddReferencesItemArray decodes given bytes into AddReferencesItemArray.
+func DecodeAddReferencesItemArray(b []byte) (*AddReferencesItemArray, error) {
+	a := &AddReferencesItemArray{}
+	if err := a.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// DecodeFromBytes decodes given bytes into AddReferencesItemArray.
+func (a *AddReferencesItemArray) DecodeFromBytes(b []byte) error {
+	if len(b) < 4 {
+		return errors.NewErrTooShortToDecode(a, "should be longer than 4 bytes")
+	}
+	a.ArraySize = int32(binary.LittleEndian.Uint32(b[:4]))
+	if a.ArraySize <= 0 {
+		return nil
+	}
+
+	offset := 4
+	for i := 1; i <= int(a.ArraySize); i++ {
+		item, err := DecodeAddReferencesItem(b[offset:])
+		if err != nil {
+			return err
+		}
+		a.Items = append(a.Items, item)
+		offset += item.Len()
+	}
+
+	return nil
+}
+
+// Serialize serializes AddReferencesItemArray into bytes.
+func (a *AddReferencesItemArray) Serialize() ([]byte, error) {
+	b := make([]byte, a.Len())
+	if err := a.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes AddReferencesItemArray into bytes.
+func (a *AddReferencesItemArray) SerializeTo(b []byte) error {
+	offset := 4
+	binary.LittleEndian.PutUint32(b[:4], uint32(a.ArraySize))
+
+	for _, item := range a.Items {
+		if err := item.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += item.Len()
+	}
+
+	return nil
+}
+
+// Len returns the actual length in int.
+func (a *AddReferencesItemArray) Len() int {
+	l := 4
+	for _, item := range a.Items {
+		l += item.Len()
+	}
+
+	return l
+}
@@ -0,0 +1,98 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package datatypes
+
+import (
+	"testing"
+
+	"github.com/wmnsk/gopcua/utils/codectest"
+)
+
+func TestAddReferencesItem(t *testing.T) {
+	cases := []codectest.Case{
+		{
+			Name: "local",
+			Struct: NewAddReferencesItem(
+				NewFourByteNodeID(0, 85), NewFourByteNodeID(0, 35), true,
+				NewFourByteExpandedNodeID(2, 1000), NodeClassObject,
+			),
+			Bytes: []byte{
+				// SourceNodeID
+				0x01, 0x00, 0x55, 0x00,
+				// ReferenceTypeID
+				0x01, 0x00, 0x23, 0x00,
+				// IsForward
+				0x01,
+				// TargetServerURI
+				0x00, 0x00, 0x00, 0x00,
+				// TargetNodeID
+				0x01, 0x02, 0xe8, 0x03,
+				// TargetNodeClass
+				0x01, 0x00, 0x00, 0x00,
+			},
+		},
+		{
+			Name: "remote",
+			Struct: &AddReferencesItem{
+				SourceNodeID:    NewFourByteNodeID(2, 1000),
+				ReferenceTypeID: NewFourByteNodeID(0, 35),
+				IsForward:       NewBoolean(false),
+				TargetServerURI: NewString("urn:bar"),
+				TargetNodeID:    NewExpandedNodeID(true, true, NewStringNodeID(0, "foo"), "urn:foo", 1),
+				TargetNodeClass: NodeClassVariable,
+			},
+			Bytes: []byte{
+				// SourceNodeID
+				0x01, 0x02, 0xe8, 0x03,
+				// ReferenceTypeID
+				0x01, 0x00, 0x23, 0x00,
+				// IsForward
+				0x00,
+				// TargetServerURI
+				0x07, 0x00, 0x00, 0x00, 0x75, 0x72, 0x6e, 0x3a, 0x62, 0x61, 0x72,
+				// TargetNodeID: EncodingMask
+				0xc3,
+				// Namespace
+				0x00, 0x00,
+				// Identifier
+				0x03, 0x00, 0x00, 0x00, 0x66, 0x6f, 0x6f,
+				// NamespaceURI
+				0x07, 0x00, 0x00, 0x00, 0x75, 0x72, 0x6e, 0x3a, 0x66, 0x6f, 0x6f,
+				// ServerIndex
+				0x01, 0x00, 0x00, 0x00,
+				// TargetNodeClass
+				0x02, 0x00, 0x00, 0x00,
+			},
+		},
+	}
+	codectest.Run(t, cases, func(b []byte) (codectest.S, error) {
+		return DecodeAddReferencesItem(b)
+	})
+}
+
+func TestAddReferencesItemArray(t *testing.T) {
+	cases := []codectest.Case{
+		{
+			Name: "normal",
+			Struct: NewAddReferencesItemArray([]*AddReferencesItem{
+				NewAddReferencesItem(
+					NewFourByteNodeID(0, 85), NewFourByteNodeID(0, 35), true,
+					NewFourByteExpandedNodeID(2, 1000), NodeClassObject,
+				),
+			}),
+			Bytes: []byte{
+				// ArraySize
+				0x01, 0x00, 0x00, 0x00,
+				// AddReferencesItems
+				0x01, 0x00, 0x55, 0x00, 0x01, 0x00, 0x23, 0x00,
+				0x01, 0x00, 0x00, 0x00, 0x00, 0x01, 0x02, 0xe8,
+				0x03, 0x01, 0x00, 0x00, 0x00,
+			},
+		},
+	}
+	codectest.Run(t, cases, func(b []byte) (codectest.S, error) {
+		return DecodeAddReferencesItemArray(b)
+	})
+}
@@ -0,0 +1,149 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package datatypes
+
+import (
+	"strconv"
+
+	"github.com/wmnsk/gopcua/errors"
+	"github.com/wmnsk/gopcua/id"
+)
+
+// AttributeID is the identifier of an Attribute of the Nodes, which is
+// an IntegerID assigned to each Attribute defined in the specification.
+//
+// Specification: Part 6, A.1
+type AttributeID uint32
+
+// Identifiers assigned to Attributes.
+//
+// Specification: Part 6, A.1
+const (
+	AttributeIDNodeID AttributeID = iota + 1
+	AttributeIDNodeClass
+	AttributeIDBrowseName
+	AttributeIDDisplayName
+	AttributeIDDescription
+	AttributeIDWriteMask
+	AttributeIDUserWriteMask
+	AttributeIDIsAbstract
+	AttributeIDSymmetric
+	AttributeIDInverseName
+	AttributeIDContainsNoLoops
+	AttributeIDEventNotifier
+	AttributeIDValue
+	AttributeIDDataType
+	AttributeIDValueRank
+	AttributeIDArrayDimensions
+	AttributeIDAccessLevel
+	AttributeIDUserAccessLevel
+	AttributeIDMinimumSamplingInterval
+	AttributeIDHistorizing
+	AttributeIDExecutable
+	AttributeIDUserExecutable
+	AttributeIDDataTypeDefinition
+	AttributeIDRolePermissions
+	AttributeIDUserRolePermissions
+	AttributeIDAccessRestrictions
+	AttributeIDAccessLevelEx
+)
+
+// attributeNames is the BrowseName of each Attribute in the specification.
+var attributeNames = map[AttributeID]string{
+	AttributeIDNodeID:                  "NodeId",
+	AttributeIDNodeClass:               "NodeClass",
+	AttributeIDBrowseName:              "BrowseName",
+	AttributeIDDisplayName:             "DisplayName",
+	AttributeIDDescription:             "Description",
+	AttributeIDWriteMask:               "WriteMask",
+	AttributeIDUserWriteMask:           "UserWriteMask",
+	AttributeIDIsAbstract:              "IsAbstract",
+	AttributeIDSymmetric:               "Symmetric",
+	AttributeIDInverseName:             "InverseName",
+	AttributeIDContainsNoLoops:         "ContainsNoLoops",
+	AttributeIDEventNotifier:           "EventNotifier",
+	AttributeIDValue:                   "Value",
+	AttributeIDDataType:                "DataType",
+	AttributeIDValueRank:               "ValueRank",
+	AttributeIDArrayDimensions:         "ArrayDimensions",
+	AttributeIDAccessLevel:             "AccessLevel",
+	AttributeIDUserAccessLevel:         "UserAccessLevel",
+	AttributeIDMinimumSamplingInterval: "MinimumSamplingInterval",
+	AttributeIDHistorizing:             "Historizing",
+	AttributeIDExecutable:              "Executable",
+	AttributeIDUserExecutable:          "UserExecutable",
+	AttributeIDDataTypeDefinition:      "DataTypeDefinition",
+	AttributeIDRolePermissions:         "RolePermissions",
+	AttributeIDUserRolePermissions:     "UserRolePermissions",
+	AttributeIDAccessRestrictions:      "AccessRestrictions",
+	AttributeIDAccessLevelEx:           "AccessLevelEx",
+}
+
+// String returns the name of the Attribute as in the specification, e.g. "Value",
+// or the number with "AttributeID" prefix if it is unknown, e.g. "AttributeID(99)".
+func (a AttributeID) String() string {
+	if name, ok := attributeNames[a]; ok {
+		return name
+	}
+	return "AttributeID(" + strconv.FormatUint(uint64(a), 10) + ")"
+}
+
+// attributeType is the DataType of the values of an Attribute.
+type attributeType struct {
+	typ   uint8
+	name  string
+	array bool
+}
+
+// attributeTypes is the DataType of each Attribute in the specification except Value,
+// which can be of any DataType. The enumerations are Int32 and the Durations are Double.
+//
+// Specification: Part 3, 5
+var attributeTypes = map[AttributeID]attributeType{
+	AttributeIDNodeID:                  {id.NodeId, "NodeId", false},
+	AttributeIDNodeClass:               {id.Int32, "Int32", false},
+	AttributeIDBrowseName:              {id.QualifiedName, "QualifiedName", false},
+	AttributeIDDisplayName:             {id.LocalizedText, "LocalizedText", false},
+	AttributeIDDescription:             {id.LocalizedText, "LocalizedText", false},
+	AttributeIDWriteMask:               {id.UInt32, "UInt32", false},
+	AttributeIDUserWriteMask:           {id.UInt32, "UInt32", false},
+	AttributeIDIsAbstract:              {id.Boolean, "Boolean", false},
+	AttributeIDSymmetric:               {id.Boolean, "Boolean", false},
+	AttributeIDInverseName:             {id.LocalizedText, "LocalizedText", false},
+	AttributeIDContainsNoLoops:         {id.Boolean, "Boolean", false},
+	AttributeIDEventNotifier:           {id.Byte, "Byte", false},
+	AttributeIDDataType:                {id.NodeId, "NodeId", false},
+	AttributeIDValueRank:               {id.Int32, "Int32", false},
+	AttributeIDArrayDimensions:         {id.UInt32, "UInt32", true},
+	AttributeIDAccessLevel:             {id.Byte, "Byte", false},
+	AttributeIDUserAccessLevel:         {id.Byte, "Byte", false},
+	AttributeIDMinimumSamplingInterval: {id.Double, "Double", false},
+	AttributeIDHistorizing:             {id.Boolean, "Boolean", false},
+	AttributeIDExecutable:              {id.Boolean, "Boolean", false},
+	AttributeIDUserExecutable:          {id.Boolean, "Boolean", false},
+	AttributeIDDataTypeDefinition:      {id.Structure, "Structure", false},
+	AttributeIDRolePermissions:         {id.Structure, "Structure", true},
+	AttributeIDUserRolePermissions:     {id.Structure, "Structure", true},
+	AttributeIDAccessRestrictions:      {id.UInt16, "UInt16", false},
+	AttributeIDAccessLevelEx:           {id.UInt32, "UInt32", false},
+}
+
+// CheckValue returns an error if v is not of the DataType of the Attribute, e.g. the
+// DisplayName should be a LocalizedText, so that it is not rejected by the server.
+// Any value is accepted for the Value and the unknown Attributes.
+func (a AttributeID) CheckValue(v *Variant) error {
+	t, ok := attributeTypes[a]
+	if !ok {
+		return nil
+	}
+	if v == nil || v.Type() != t.typ || v.HasArrayValues() != t.array {
+		want := t.name
+		if t.array {
+			want += " array"
+		}
+		return errors.Errorf("value of %s should be %s", a, want)
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package datatypes
+
+import (
+	"testing"
+	"time"
+
+	"github.com/wmnsk/gopcua/id"
+)
+
+func TestAttributeID(t *testing.T) {
+	cases := []struct {
+		attr AttributeID
+		id   uint32
+		name string
+	}{
+		{AttributeIDNodeID, 1, "NodeId"},
+		{AttributeIDNodeClass, 2, "NodeClass"},
+		{AttributeIDBrowseName, 3, "BrowseName"},
+		{AttributeIDDisplayName, 4, "DisplayName"},
+		{AttributeIDDescription, 5, "Description"},
+		{AttributeIDWriteMask, 6, "WriteMask"},
+		{AttributeIDUserWriteMask, 7, "UserWriteMask"},
+		{AttributeIDIsAbstract, 8, "IsAbstract"},
+		{AttributeIDSymmetric, 9, "Symmetric"},
+		{AttributeIDInverseName, 10, "InverseName"},
+		{AttributeIDContainsNoLoops, 11, "ContainsNoLoops"},
+		{AttributeIDEventNotifier, 12, "EventNotifier"},
+		{AttributeIDValue, 13, "Value"},
+		{AttributeIDDataType, 14, "DataType"},
+		{AttributeIDValueRank, 15, "ValueRank"},
+		{AttributeIDArrayDimensions, 16, "ArrayDimensions"},
+		{AttributeIDAccessLevel, 17, "AccessLevel"},
+		{AttributeIDUserAccessLevel, 18, "UserAccessLevel"},
+		{AttributeIDMinimumSamplingInterval, 19, "MinimumSamplingInterval"},
+		{AttributeIDHistorizing, 20, "Historizing"},
+		{AttributeIDExecutable, 21, "Executable"},
+		{AttributeIDUserExecutable, 22, "UserExecutable"},
+		{AttributeIDDataTypeDefinition, 23, "DataTypeDefinition"},
+		{AttributeIDRolePermissions, 24, "RolePermissions"},
+		{AttributeIDUserRolePermissions, 25, "UserRolePermissions"},
+		{AttributeIDAccessRestrictions, 26, "AccessRestrictions"},
+		{AttributeIDAccessLevelEx, 27, "AccessLevelEx"},
+		{AttributeID(99), 99, "AttributeID(99)"},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := uint32(c.attr); got != c.id {
+				t.Errorf("got %d want %d", got, c.id)
+			}
+			if got := c.attr.String(); got != c.name {
+				t.Errorf("got %q want %q", got, c.name)
+			}
+		})
+	}
+}
+
+func TestAttributeIDCheckValue(t *testing.T) {
+	cases := []struct {
+		name string
+		attr AttributeID
+		v    *Variant
+		ok   bool
+	}{
+		{"DisplayName", AttributeIDDisplayName, NewVariant(NewLocalizedText("en-US", "foo")), true},
+		{"DisplayName/String", AttributeIDDisplayName, NewVariant(NewString("foo")), false},
+		{"DisplayName/array", AttributeIDDisplayName, NewArrayVariant(id.LocalizedText, NewLocalizedText("en-US", "foo")), false},
+		{"BrowseName", AttributeIDBrowseName, NewVariant(NewQualifiedName(2, "foo")), true},
+		{"Historizing", AttributeIDHistorizing, NewVariant(NewBoolean(true)), true},
+		{"Historizing/Int32", AttributeIDHistorizing, NewVariant(NewInt32(1)), false},
+		{"MinimumSamplingInterval", AttributeIDMinimumSamplingInterval, NewVariant(NewDouble(100)), true},
+		{"Value", AttributeIDValue, NewVariant(NewDateTime(time.Now())), true},
+		{"unknown", AttributeID(99), NewVariant(NewFloat(1.5)), true},
+		{"nil", AttributeIDDescription, nil, false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := c.attr.CheckValue(c.v)
+			if c.ok && err != nil {
+				t.Errorf("got %v want nil", err)
+			}
+			if !c.ok && err == nil {
+				t.Error("got nil want error")
+			}
+		})
+	}
+}
@@ -4,7 +4,10 @@
 
 package datatypes
 
-import "github.com/wmnsk/gopcua/id"
+import (
+	"github.com/wmnsk/gopcua/errors"
+	"github.com/wmnsk/gopcua/id"
+)
 
 // Boolean represents the datatype Boolean.
 //
@@ -34,7 +37,9 @@ func DecodeBoolean(b []byte) (*Boolean, error) {
 
 // DecodeFromBytes decodes given bytes into Boolean.
 func (bo *Boolean) DecodeFromBytes(b []byte) error {
-	// TODO: Add validation.
+	if len(b) < 1 {
+		return errors.NewErrTooShortToDecode(bo, "should be longer than 1 byte")
+	}
 	bo.Value = b[0]
 	return nil
 }
@@ -0,0 +1,280 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package datatypes
+
+import (
+	"encoding/binary"
+
+	"github.com/wmnsk/gopcua/errors"
+	"github.com/wmnsk/gopcua/id"
+)
+
+// BrowseDirection is the direction of the References to return in Browse.
+//
+// Specification: Part 4, 5.8.2.2
+type BrowseDirection uint32
+
+// BrowseDirection definitions.
+const (
+	BrowseDirectionForward BrowseDirection = iota
+	BrowseDirectionInverse
+	BrowseDirectionBoth
+)
+
+// BrowseResultMask is the set of the fields to return in the ReferenceDescriptions.
+//
+// Specification: Part 4, 5.8.2.2
+type BrowseResultMask uint32
+
+// BrowseResultMask definitions.
+const (
+	BrowseResultMaskNone           BrowseResultMask = 0
+	BrowseResultMaskReferenceType  BrowseResultMask = 1
+	BrowseResultMaskIsForward      BrowseResultMask = 2
+	BrowseResultMaskNodeClass      BrowseResultMask = 4
+	BrowseResultMaskBrowseName     BrowseResultMask = 8
+	BrowseResultMaskDisplayName    BrowseResultMask = 16
+	BrowseResultMaskTypeDefinition BrowseResultMask = 32
+	BrowseResultMaskAll            BrowseResultMask = 63
+)
+
+// BrowseDescription is a Node to be browsed and the filters applied to its References.
+//
+// A nil ReferenceTypeID is encoded as the null NodeID, which returns the References
+// of all types. The NodeClassMask is the NodeClasses combined with bitwise OR,
+// and 0 returns the targets of all the NodeClasses.
+//
+// Specification: Part 4, 5.8.2.2
+type BrowseDescription struct {
+	NodeID          *NodeID
+	BrowseDirection BrowseDirection
+	ReferenceTypeID *NodeID
+	IncludeSubtypes *Boolean
+	NodeClassMask   uint32
+	ResultMask      uint32
+}
+
+// NewBrowseDescription creates a new BrowseDescription.
+func NewBrowseDescription(node *NodeID, dir BrowseDirection, refType *NodeID, subtypes bool, nodeClassMask, resultMask uint32) *BrowseDescription {
+	return &BrowseDescription{
+		NodeID:          node,
+		BrowseDirection: dir,
+		ReferenceTypeID: refType,
+		IncludeSubtypes: NewBoolean(subtypes),
+		NodeClassMask:   nodeClassMask,
+		ResultMask:      resultMask,
+	}
+}
+
+// NewBrowseChildrenDescription creates a new BrowseDescription which returns all the fields
+// of the forward HierarchicalReferences from node, including their subtypes.
+func NewBrowseChildrenDescription(node *NodeID) *BrowseDescription {
+	return NewBrowseDescription(
+		node, BrowseDirectionForward,
+		NewTwoByteNodeID(id.HierarchicalReferences), true,
+		uint32(NodeClassUnspecified), uint32(BrowseResultMaskAll),
+	)
+}
+
+// DecodeBrowseDescription decodes given bytes into BrowseDescription.
+func DecodeBrowseDescription(b []byte) (*BrowseDescription, error) {
+	d := &BrowseDescription{}
+	if err := d.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// DecodeFromBytes decodes given bytes into BrowseDescription.
+func (d *BrowseDescription) DecodeFromBytes(b []byte) error {
+	d.NodeID = &NodeID{}
+	if err := d.NodeID.DecodeFromBytes(b); err != nil {
+		return err
+	}
+	offset := d.NodeID.Len()
+
+	if len(b[offset:]) < 4 {
+		return errors.NewErrTooShortToDecode(d, "should contain BrowseDirection")
+	}
+	d.BrowseDirection = BrowseDirection(binary.LittleEndian.Uint32(b[offset : offset+4]))
+	offset += 4
+
+	d.ReferenceTypeID = &NodeID{}
+	if err := d.ReferenceTypeID.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += d.ReferenceTypeID.Len()
+
+	if len(b[offset:]) < 9 {
+		return errors.NewErrTooShortToDecode(d, "should contain IncludeSubtypes, NodeClassMask and ResultMask")
+	}
+	d.IncludeSubtypes = &Boolean{}
+	if err := d.IncludeSubtypes.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += d.IncludeSubtypes.Len()
+
+	d.NodeClassMask = binary.LittleEndian.Uint32(b[offset : offset+4])
+	offset += 4
+
+	d.ResultMask = binary.LittleEndian.Uint32(b[offset : offset+4])
+	return nil
+}
+
+// Serialize serializes BrowseDescription into bytes.
+func (d *BrowseDescription) Serialize() ([]byte, error) {
+	b := make([]byte, d.Len())
+	if err := d.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes BrowseDescription into bytes.
+func (d *BrowseDescription) SerializeTo(b []byte) error {
+	offset := 0
+	if d.NodeID != nil {
+		if err := d.NodeID.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += d.NodeID.Len()
+	}
+
+	binary.LittleEndian.PutUint32(b[offset:offset+4], uint32(d.BrowseDirection))
+	offset += 4
+
+	refType := d.ReferenceTypeID
+	if refType == nil {
+		refType = NewTwoByteNodeID(0)
+	}
+	if err := refType.SerializeTo(b[offset:]); err != nil {
+		return err
+	}
+	offset += refType.Len()
+
+	subtypes := d.IncludeSubtypes
+	if subtypes == nil {
+		subtypes = NewBoolean(false)
+	}
+	if err := subtypes.SerializeTo(b[offset:]); err != nil {
+		return err
+	}
+	offset += subtypes.Len()
+
+	binary.LittleEndian.PutUint32(b[offset:offset+4], d.NodeClassMask)
+	offset += 4
+
+	binary.LittleEndian.PutUint32(b[offset:offset+4], d.ResultMask)
+	return nil
+}
+
+// Len returns the actual length of BrowseDescription in int.
+func (d *BrowseDescription) Len() int {
+	// IncludeSubtypes is always encoded, as false if it is nil.
+	l := 13
+	if d.NodeID != nil {
+		l += d.NodeID.Len()
+	}
+	if d.ReferenceTypeID != nil {
+		l += d.ReferenceTypeID.Len()
+	} else {
+		// the null NodeID.
+		l += 2
+	}
+
+	return l
+}
+
+// BrowseDescriptionArray represents an array of BrowseDescriptions.
+// It does not correspond to a certain type from the specification
+// but makes encoding and decoding easier.
+type BrowseDescriptionArray struct {
+	ArraySize          int32
+	BrowseDescriptions []*BrowseDescription
+}
+
+// NewBrowseDescriptionArray creates a new BrowseDescriptionArray from multiple BrowseDescriptions.
+func NewBrowseDescriptionArray(descs []*BrowseDescription) *BrowseDescriptionArray {
+	if descs == nil {
+		return &BrowseDescriptionArray{
+			ArraySize: 0,
+		}
+	}
+
+	return &BrowseDescriptionArray{
+		ArraySize:          int32(len(descs)),
+		BrowseDescriptions: descs,
+	}
+}
+
+// DecodeBrowseDescriptionArray decodes given bytes into BrowseDescriptionArray.
+func DecodeBrowseDescriptionArray(b []byte) (*BrowseDescriptionArray, error) {
+	a := &BrowseDescriptionArray{}
+	if err := a.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// DecodeFromBytes decodes given bytes into BrowseDescriptionArray.
+func (a *BrowseDescriptionArray) DecodeFromBytes(b []byte) error {
+	if len(b) < 4 {
+		return errors.NewErrTooShortToDecode(a, "should be longer than 4 bytes")
+	}
+	a.ArraySize = int32(binary.LittleEndian.Uint32(b[:4]))
+	if a.ArraySize <= 0 {
+		return nil
+	}
+
+	offset := 4
+	for i := 1; i <= int(a.ArraySize); i++ {
+		d, err := DecodeBrowseDescription(b[offset:])
+		if err != nil {
+			return err
+		}
+		a.BrowseDescriptions = append(a.BrowseDescriptions, d)
+		offset += d.Len()
+	}
+
+	return nil
+}
+
+// Serialize serializes BrowseDescriptionArray into bytes.
+func (a *BrowseDescriptionArray) Serialize() ([]byte, error) {
+	b := make([]byte, a.Len())
+	if err := a.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes BrowseDescriptionArray into bytes.
+func (a *BrowseDescriptionArray) SerializeTo(b []byte) error {
+	offset := 4
+	binary.LittleEndian.PutUint32(b[:4], uint32(a.ArraySize))
+
+	for _, d := range a.BrowseDescriptions {
+		if err := d.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += d.Len()
+	}
+
+	return nil
+}
+
+// Len returns the actual length in int.
+func (a *BrowseDescriptionArray) Len() int {
+	l := 4
+	for _, d := range a.BrowseDescriptions {
+		l += d.Len()
+	}
+
+	return l
+}
@@ -0,0 +1,162 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package datatypes
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/wmnsk/gopcua/utils/codectest"
+)
+
+func TestBrowseDescription(t *testing.T) {
+	cases := []codectest.Case{
+		{
+			Name: "normal",
+			Struct: NewBrowseDescription(
+				NewTwoByteNodeID(85), BrowseDirectionForward,
+				NewTwoByteNodeID(33), true, 0, 0x3f,
+			),
+			Bytes: []byte{
+				// NodeID
+				0x00, 0x55,
+				// BrowseDirection
+				0x00, 0x00, 0x00, 0x00,
+				// ReferenceTypeID
+				0x00, 0x21,
+				// IncludeSubtypes
+				0x01,
+				// NodeClassMask
+				0x00, 0x00, 0x00, 0x00,
+				// ResultMask
+				0x3f, 0x00, 0x00, 0x00,
+			},
+		},
+		{
+			Name: "filters",
+			Struct: NewBrowseDescription(
+				NewFourByteNodeID(2, 1000), BrowseDirectionInverse,
+				NewTwoByteNodeID(47), false,
+				uint32(NodeClassObject|NodeClassVariable),
+				uint32(BrowseResultMaskBrowseName|BrowseResultMaskNodeClass),
+			),
+			Bytes: []byte{
+				// NodeID
+				0x01, 0x02, 0xe8, 0x03,
+				// BrowseDirection
+				0x01, 0x00, 0x00, 0x00,
+				// ReferenceTypeID
+				0x00, 0x2f,
+				// IncludeSubtypes
+				0x00,
+				// NodeClassMask
+				0x03, 0x00, 0x00, 0x00,
+				// ResultMask
+				0x0c, 0x00, 0x00, 0x00,
+			},
+		},
+		{
+			Name:   "children",
+			Struct: NewBrowseChildrenDescription(NewTwoByteNodeID(85)),
+			Bytes: []byte{
+				// NodeID
+				0x00, 0x55,
+				// BrowseDirection
+				0x00, 0x00, 0x00, 0x00,
+				// ReferenceTypeID
+				0x00, 0x21,
+				// IncludeSubtypes
+				0x01,
+				// NodeClassMask
+				0x00, 0x00, 0x00, 0x00,
+				// ResultMask
+				0x3f, 0x00, 0x00, 0x00,
+			},
+		},
+	}
+	codectest.Run(t, cases, func(b []byte) (codectest.S, error) {
+		return DecodeBrowseDescription(b)
+	})
+}
+
+func TestBrowseDescriptionNilFilters(t *testing.T) {
+	d := &BrowseDescription{
+		NodeID:          NewTwoByteNodeID(85),
+		BrowseDirection: BrowseDirectionBoth,
+		NodeClassMask:   uint32(NodeClassMethod),
+	}
+	b, err := d.Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []byte{
+		// NodeID
+		0x00, 0x55,
+		// BrowseDirection
+		0x02, 0x00, 0x00, 0x00,
+		// ReferenceTypeID
+		0x00, 0x00,
+		// IncludeSubtypes
+		0x00,
+		// NodeClassMask
+		0x04, 0x00, 0x00, 0x00,
+		// ResultMask
+		0x00, 0x00, 0x00, 0x00,
+	}
+	if diff := cmp.Diff(b, want); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestBrowseDescriptionArray(t *testing.T) {
+	cases := []codectest.Case{
+		{
+			Name: "normal",
+			Struct: NewBrowseDescriptionArray([]*BrowseDescription{
+				NewBrowseDescription(
+					NewTwoByteNodeID(85), BrowseDirectionForward,
+					NewTwoByteNodeID(33), true, 0, 0x3f,
+				),
+				NewBrowseDescription(
+					NewFourByteNodeID(2, 1000), BrowseDirectionBoth,
+					NewTwoByteNodeID(0), false, 0x03, 0x01,
+				),
+			}),
+			Bytes: []byte{
+				// ArraySize
+				0x02, 0x00, 0x00, 0x00,
+				// NodeID
+				0x00, 0x55,
+				// BrowseDirection
+				0x00, 0x00, 0x00, 0x00,
+				// ReferenceTypeID
+				0x00, 0x21,
+				// IncludeSubtypes
+				0x01,
+				// NodeClassMask
+				0x00, 0x00, 0x00, 0x00,
+				// ResultMask
+				0x3f, 0x00, 0x00, 0x00,
+				// NodeID
+				0x01, 0x02, 0xe8, 0x03,
+				// BrowseDirection
+				0x02, 0x00, 0x00, 0x00,
+				// ReferenceTypeID
+				0x00, 0x00,
+				// IncludeSubtypes
+				0x00,
+				// NodeClassMask
+				0x03, 0x00, 0x00, 0x00,
+				// ResultMask
+				0x01, 0x00, 0x00, 0x00,
+			},
+		},
+	}
+	codectest.Run(t, cases, func(b []byte) (codectest.S, error) {
+		return DecodeBrowseDescriptionArray(b)
+	})
+}
@@ -0,0 +1,340 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package datatypes
+
+import (
+	"encoding/binary"
+
+	"github.com/wmnsk/gopcua/errors"
+)
+
+// BrowsePathTarget is a target Node of a BrowsePath.
+//
+// RemainingPathIndex is the index of the first RelativePathElement which has not
+// been followed, when the target is in another server, or 0xffffffff if the whole
+// RelativePath has been followed.
+//
+// Specification: Part 4, 5.8.4.2
+type BrowsePathTarget struct {
+	TargetID           *ExpandedNodeID
+	RemainingPathIndex uint32
+}
+
+// NewBrowsePathTarget creates a new BrowsePathTarget.
+func NewBrowsePathTarget(target *ExpandedNodeID, remaining uint32) *BrowsePathTarget {
+	return &BrowsePathTarget{
+		TargetID:           target,
+		RemainingPathIndex: remaining,
+	}
+}
+
+// DecodeBrowsePathTarget decodes given bytes into BrowsePathTarget.
+func DecodeBrowsePathTarget(b []byte) (*BrowsePathTarget, error) {
+	t := &BrowsePathTarget{}
+	if err := t.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// DecodeFromBytes decodes given bytes into BrowsePathTarget.
+func (t *BrowsePathTarget) DecodeFromBytes(b []byte) error {
+	t.TargetID = &ExpandedNodeID{}
+	if err := t.TargetID.DecodeFromBytes(b); err != nil {
+		return err
+	}
+	offset := t.TargetID.Len()
+
+	if len(b[offset:]) < 4 {
+		return errors.NewErrTooShortToDecode(t, "should contain RemainingPathIndex")
+	}
+	t.RemainingPathIndex = binary.LittleEndian.Uint32(b[offset : offset+4])
+
+	return nil
+}
+
+// Serialize serializes BrowsePathTarget into bytes.
+func (t *BrowsePathTarget) Serialize() ([]byte, error) {
+	b := make([]byte, t.Len())
+	if err := t.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes BrowsePathTarget into bytes.
+func (t *BrowsePathTarget) SerializeTo(b []byte) error {
+	offset := 0
+	if t.TargetID != nil {
+		if err := t.TargetID.SerializeTo(b); err != nil {
+			return err
+		}
+		offset += t.TargetID.Len()
+	}
+
+	binary.LittleEndian.PutUint32(b[offset:offset+4], t.RemainingPathIndex)
+
+	return nil
+}
+
+// Len returns the actual length of BrowsePathTarget in int.
+func (t *BrowsePathTarget) Len() int {
+	l := 4
+	if t.TargetID != nil {
+		l += t.TargetID.Len()
+	}
+
+	return l
+}
+
+// BrowsePathTargetArray represents an array of BrowsePathTargets.
+// It does not correspond to a certain type from the specification
+// but makes encoding and decoding easier.
+type BrowsePathTargetArray struct {
+	ArraySize         int32
+	BrowsePathTargets []*BrowsePathTarget
+}
+
+// NewBrowsePathTargetArray creates a new BrowsePathTargetArray from multiple BrowsePathTargets.
+func NewBrowsePathTargetArray(targets []*BrowsePathTarget) *BrowsePathTargetArray {
+	if targets == nil {
+		return &BrowsePathTargetArray{
+			ArraySize: 0,
+		}
+	}
+
+	return &BrowsePathTargetArray{
+		ArraySize:         int32(len(targets)),
+		BrowsePathTargets: targets,
+	}
+}
+
+// DecodeBrowsePathTargetArray decodes given bytes into BrowsePathTargetArray.
+func DecodeBrowsePathTargetArray(b []byte) (*BrowsePathTargetArray, error) {
+	a := &BrowsePathTargetArray{}
+	if err := a.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// DecodeFromBytes decodes given bytes into BrowsePathTargetArray.
+func (a *BrowsePathTargetArray) DecodeFromBytes(b []byte) error {
+	if len(b) < 4 {
+		return errors.NewErrTooShortToDecode(a, "should be longer than 4 bytes")
+	}
+	a.ArraySize = int32(binary.LittleEndian.Uint32(b[:4]))
+	if a.ArraySize <= 0 {
+		return nil
+	}
+
+	offset := 4
+	for i := 1; i <= int(a.ArraySize); i++ {
+		t, err := DecodeBrowsePathTarget(b[offset:])
+		if err != nil {
+			return err
+		}
+		a.BrowsePathTargets = append(a.BrowsePathTargets, t)
+		offset += t.Len()
+	}
+
+	return nil
+}
+
+// Serialize serializes BrowsePathTargetArray into bytes.
+func (a *BrowsePathTargetArray) Serialize() ([]byte, error) {
+	b := make([]byte, a.Len())
+	if err := a.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes BrowsePathTargetArray into bytes.
+func (a *BrowsePathTargetArray) SerializeTo(b []byte) error {
+	offset := 4
+	binary.LittleEndian.PutUint32(b[:4], uint32(a.ArraySize))
+
+	for _, t := range a.BrowsePathTargets {
+		if err := t.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += t.Len()
+	}
+
+	return nil
+}
+
+// Len returns the actual length in int.
+func (a *BrowsePathTargetArray) Len() int {
+	l := 4
+	for _, t := range a.BrowsePathTargets {
+		l += t.Len()
+	}
+
+	return l
+}
+
+// BrowsePathResult is the result of TranslateBrowsePathsToNodeIds for a single BrowsePath.
+//
+// Specification: Part 4, 5.8.4.2
+type BrowsePathResult struct {
+	StatusCode uint32
+	Targets    *BrowsePathTargetArray
+}
+
+// NewBrowsePathResult creates a new BrowsePathResult.
+func NewBrowsePathResult(code uint32, targets ...*BrowsePathTarget) *BrowsePathResult {
+	return &BrowsePathResult{
+		StatusCode: code,
+		Targets:    NewBrowsePathTargetArray(targets),
+	}
+}
+
+// DecodeBrowsePathResult decodes given bytes into BrowsePathResult.
+func DecodeBrowsePathResult(b []byte) (*BrowsePathResult, error) {
+	r := &BrowsePathResult{}
+	if err := r.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// DecodeFromBytes decodes given bytes into BrowsePathResult.
+func (r *BrowsePathResult) DecodeFromBytes(b []byte) error {
+	if len(b) < 4 {
+		return errors.NewErrTooShortToDecode(r, "should be longer than 4 bytes")
+	}
+	r.StatusCode = binary.LittleEndian.Uint32(b[:4])
+
+	r.Targets = &BrowsePathTargetArray{}
+	return r.Targets.DecodeFromBytes(b[4:])
+}
+
+// Serialize serializes BrowsePathResult into bytes.
+func (r *BrowsePathResult) Serialize() ([]byte, error) {
+	b := make([]byte, r.Len())
+	if err := r.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes BrowsePathResult into bytes.
+func (r *BrowsePathResult) SerializeTo(b []byte) error {
+	binary.LittleEndian.PutUint32(b[:4], r.StatusCode)
+
+	if r.Targets != nil {
+		return r.Targets.SerializeTo(b[4:])
+	}
+
+	return nil
+}
+
+// Len returns the actual length of BrowsePathResult in int.
+func (r *BrowsePathResult) Len() int {
+	l := 4
+	if r.Targets != nil {
+		l += r.Targets.Len()
+	}
+
+	return l
+}
+
+// BrowsePathResultArray represents an array of BrowsePathResults.
+// It does not correspond to a certain type from the specification
+// but makes encoding and decoding easier.
+type BrowsePathResultArray struct {
+	ArraySize         int32
+	BrowsePathResults []*BrowsePathResult
+}
+
+// NewBrowsePathResultArray creates a new BrowsePathResultArray from multiple BrowsePathResults.
+func NewBrowsePathResultArray(results []*BrowsePathResult) *BrowsePathResultArray {
+	if results == nil {
+		return &BrowsePathResultArray{
+			ArraySize: 0,
+		}
+	}
+
+	return &BrowsePathResultArray{
+		ArraySize:         int32(len(results)),
+		BrowsePathResults: results,
+	}
+}
+
+// DecodeBrowsePathResultArray decodes given bytes into BrowsePathResultArray.
+func DecodeBrowsePathResultArray(b []byte) (*BrowsePathResultArray, error) {
+	a := &BrowsePathResultArray{}
+	if err := a.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// DecodeFromBytes decodes given bytes into BrowsePathResultArray.
+func (a *BrowsePathResultArray) DecodeFromBytes(b []byte) error {
+	if len(b) < 4 {
+		return errors.NewErrTooShortToDecode(a, "should be longer than 4 bytes")
+	}
+	a.ArraySize = int32(binary.LittleEndian.Uint32(b[:4]))
+	if a.ArraySize <= 0 {
+		return nil
+	}
+
+	offset := 4
+	for i := 1; i <= int(a.ArraySize); i++ {
+		r, err := DecodeBrowsePathResult(b[offset:])
+		if err != nil {
+			return err
+		}
+		a.BrowsePathResults = append(a.BrowsePathResults, r)
+		offset += r.Len()
+	}
+
+	return nil
+}
+
+// Serialize serializes BrowsePathResultArray into bytes.
+func (a *BrowsePathResultArray) Serialize() ([]byte, error) {
+	b := make([]byte, a.Len())
+	if err := a.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes BrowsePathResultArray into bytes.
+func (a *BrowsePathResultArray) SerializeTo(b []byte) error {
+	offset := 4
+	binary.LittleEndian.PutUint32(b[:4], uint32(a.ArraySize))
+
+	for _, r := range a.BrowsePathResults {
+		if err := r.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += r.Len()
+	}
+
+	return nil
+}
+
+// Len returns the actual length in int.
+func (a *BrowsePathResultArray) Len() int {
+	l := 4
+	for _, r := range a.BrowsePathResults {
+		l += r.Len()
+	}
+
+	return l
+}
@@ -0,0 +1,51 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package datatypes
+
+import (
+	"testing"
+
+	"github.com/wmnsk/gopcua/utils/codectest"
+)
+
+func TestBrowsePathResult(t *testing.T) {
+	cases := []codectest.Case{
+		{
+			Name:   "no match",
+			Struct: NewBrowsePathResult(0x806f0000),
+			Bytes: []byte{
+				// StatusCode
+				0x00, 0x00, 0x6f, 0x80,
+				// Targets
+				0x00, 0x00, 0x00, 0x00,
+			},
+		},
+		{
+			Name: "with targets",
+			Struct: NewBrowsePathResult(
+				0,
+				NewBrowsePathTarget(NewStringExpandedNodeID(1, "foo"), 0xffffffff),
+				NewBrowsePathTarget(NewExpandedNodeIDWithServer(NewFourByteNodeID(2, 1000), 1), 1),
+			),
+			Bytes: []byte{
+				// StatusCode
+				0x00, 0x00, 0x00, 0x00,
+				// Targets: ArraySize
+				0x02, 0x00, 0x00, 0x00,
+				// TargetID
+				0x03, 0x01, 0x00, 0x03, 0x00, 0x00, 0x00, 0x66, 0x6f, 0x6f,
+				// RemainingPathIndex
+				0xff, 0xff, 0xff, 0xff,
+				// TargetID
+				0x41, 0x02, 0xe8, 0x03, 0x01, 0x00, 0x00, 0x00,
+				// RemainingPathIndex
+				0x01, 0x00, 0x00, 0x00,
+			},
+		},
+	}
+	codectest.Run(t, cases, func(b []byte) (codectest.S, error) {
+		return DecodeBrowsePathResult(b)
+	})
+}
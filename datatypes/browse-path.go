@@ -0,0 +1,180 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package datatypes
+
+import (
+	"encoding/binary"
+
+	"github.com/wmnsk/gopcua/errors"
+)
+
+// BrowsePath is a path to follow from the StartingNode to the target Nodes,
+// which is translated into their NodeIDs by TranslateBrowsePathsToNodeIds.
+//
+// Specification: Part 4, 5.8.4.2
+type BrowsePath struct {
+	StartingNode *NodeID
+	RelativePath *RelativePath
+}
+
+// NewBrowsePath creates a new BrowsePath.
+func NewBrowsePath(start *NodeID, path *RelativePath) *BrowsePath {
+	return &BrowsePath{
+		StartingNode: start,
+		RelativePath: path,
+	}
+}
+
+// DecodeBrowsePath decodes given bytes into BrowsePath.
+func DecodeBrowsePath(b []byte) (*BrowsePath, error) {
+	p := &BrowsePath{}
+	if err := p.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// DecodeFromBytes decodes given bytes into BrowsePath.
+func (p *BrowsePath) DecodeFromBytes(b []byte) error {
+	p.StartingNode = &NodeID{}
+	if err := p.StartingNode.DecodeFromBytes(b); err != nil {
+		return err
+	}
+	offset := p.StartingNode.Len()
+
+	p.RelativePath = &RelativePath{}
+	return p.RelativePath.DecodeFromBytes(b[offset:])
+}
+
+// Serialize serializes BrowsePath into bytes.
+func (p *BrowsePath) Serialize() ([]byte, error) {
+	b := make([]byte, p.Len())
+	if err := p.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes BrowsePath into bytes.
+func (p *BrowsePath) SerializeTo(b []byte) error {
+	offset := 0
+	if p.StartingNode != nil {
+		if err := p.StartingNode.SerializeTo(b); err != nil {
+			return err
+		}
+		offset += p.StartingNode.Len()
+	}
+
+	if p.RelativePath != nil {
+		return p.RelativePath.SerializeTo(b[offset:])
+	}
+
+	return nil
+}
+
+// Len returns the actual length of BrowsePath in int.
+func (p *BrowsePath) Len() int {
+	var l int
+	if p.StartingNode != nil {
+		l += p.StartingNode.Len()
+	}
+	if p.RelativePath != nil {
+		l += p.RelativePath.Len()
+	}
+
+	return l
+}
+
+// BrowsePathArray represents an array of BrowsePaths.
+// It does not correspond to a certain type from the specification
+// but makes encoding and decoding easier.
+type BrowsePathArray struct {
+	ArraySize   int32
+	BrowsePaths []*BrowsePath
+}
+
+// NewBrowsePathArray creates a new BrowsePathArray from multiple BrowsePaths.
+func NewBrowsePathArray(paths []*BrowsePath) *BrowsePathArray {
+	if paths == nil {
+		return &BrowsePathArray{
+			ArraySize: 0,
+		}
+	}
+
+	return &BrowsePathArray{
+		ArraySize:   int32(len(paths)),
+		BrowsePaths: paths,
+	}
+}
+
+// DecodeBrowsePathArray decodes given bytes into BrowsePathArray.
+func DecodeBrowsePathArray(b []byte) (*BrowsePathArray, error) {
+	a := &BrowsePathArray{}
+	if err := a.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// DecodeFromBytes decodes given bytes into BrowsePathArray.
+func (a *BrowsePathArray) DecodeFromBytes(b []byte) error {
+	if len(b) < 4 {
+		return errors.NewErrTooShortToDecode(a, "should be longer than 4 bytes")
+	}
+	a.ArraySize = int32(binary.LittleEndian.Uint32(b[:4]))
+	if a.ArraySize <= 0 {
+		return nil
+	}
+
+	offset := 4
+	for i := 1; i <= int(a.ArraySize); i++ {
+		p, err := DecodeBrowsePath(b[offset:])
+		if err != nil {
+			return err
+		}
+		a.BrowsePaths = append(a.BrowsePaths, p)
+		offset += p.Len()
+	}
+
+	return nil
+}
+
+// Serialize serializes BrowsePathArray into bytes.
+func (a *BrowsePathArray) Serialize() ([]byte, error) {
+	b := make([]byte, a.Len())
+	if err := a.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes BrowsePathArray into bytes.
+func (a *BrowsePathArray) SerializeTo(b []byte) error {
+	offset := 4
+	binary.LittleEndian.PutUint32(b[:4], uint32(a.ArraySize))
+
+	for _, p := range a.BrowsePaths {
+		if err := p.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += p.Len()
+	}
+
+	return nil
+}
+
+// Len returns the actual length in int.
+func (a *BrowsePathArray) Len() int {
+	l := 4
+	for _, p := range a.BrowsePaths {
+		l += p.Len()
+	}
+
+	return l
+}
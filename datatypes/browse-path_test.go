@@ -0,0 +1,36 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package datatypes
+
+import (
+	"testing"
+
+	"github.com/wmnsk/gopcua/utils/codectest"
+)
+
+func TestBrowsePath(t *testing.T) {
+	cases := []codectest.Case{
+		{
+			Name: "normal",
+			Struct: NewBrowsePath(
+				NewFourByteNodeID(0, 85),
+				NewRelativePath(
+					NewRelativePathElement(NewFourByteNodeID(0, 33), false, true, NewQualifiedName(2, "foo")),
+				),
+			),
+			Bytes: []byte{
+				// StartingNode
+				0x01, 0x00, 0x55, 0x00,
+				// RelativePath
+				0x01, 0x00, 0x00, 0x00,
+				0x01, 0x00, 0x21, 0x00, 0x00, 0x01,
+				0x02, 0x00, 0x03, 0x00, 0x00, 0x00, 0x66, 0x6f, 0x6f,
+			},
+		},
+	}
+	codectest.Run(t, cases, func(b []byte) (codectest.S, error) {
+		return DecodeBrowsePath(b)
+	})
+}
@@ -0,0 +1,192 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package datatypes
+
+import (
+	"encoding/binary"
+
+	"github.com/wmnsk/gopcua/errors"
+)
+
+// BrowseResult is the result of Browse or BrowseNext for a single Node.
+//
+// If not all References could be returned, ContinuationPoint is set and can be
+// passed to BrowseNext to get the remaining References.
+//
+// Specification: Part 4, 7.3
+type BrowseResult struct {
+	StatusCode        uint32
+	ContinuationPoint *ByteString
+	References        *ReferenceDescriptionArray
+}
+
+// NewBrowseResult creates a new BrowseResult.
+func NewBrowseResult(code uint32, cp []byte, refs ...*ReferenceDescription) *BrowseResult {
+	return &BrowseResult{
+		StatusCode:        code,
+		ContinuationPoint: NewByteString(cp),
+		References:        NewReferenceDescriptionArray(refs),
+	}
+}
+
+// DecodeBrowseResult decodes given bytes into BrowseResult.
+func DecodeBrowseResult(b []byte) (*BrowseResult, error) {
+	r := &BrowseResult{}
+	if err := r.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// DecodeFromBytes decodes given bytes into BrowseResult.
+func (r *BrowseResult) DecodeFromBytes(b []byte) error {
+	if len(b) < 4 {
+		return errors.NewErrTooShortToDecode(r, "should be longer than 4 bytes")
+	}
+	r.StatusCode = binary.LittleEndian.Uint32(b[:4])
+	offset := 4
+
+	r.ContinuationPoint = &ByteString{}
+	if err := r.ContinuationPoint.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += r.ContinuationPoint.Len()
+
+	r.References = &ReferenceDescriptionArray{}
+	return r.References.DecodeFromBytes(b[offset:])
+}
+
+// Serialize serializes BrowseResult into bytes.
+func (r *BrowseResult) Serialize() ([]byte, error) {
+	b := make([]byte, r.Len())
+	if err := r.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes BrowseResult into bytes.
+func (r *BrowseResult) SerializeTo(b []byte) error {
+	binary.LittleEndian.PutUint32(b[:4], r.StatusCode)
+	offset := 4
+
+	if r.ContinuationPoint != nil {
+		if err := r.ContinuationPoint.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += r.ContinuationPoint.Len()
+	}
+
+	if r.References != nil {
+		return r.References.SerializeTo(b[offset:])
+	}
+
+	return nil
+}
+
+// Len returns the actual length of BrowseResult in int.
+func (r *BrowseResult) Len() int {
+	l := 4
+	if r.ContinuationPoint != nil {
+		l += r.ContinuationPoint.Len()
+	}
+	if r.References != nil {
+		l += r.References.Len()
+	}
+
+	return l
+}
+
+// BrowseResultArray represents an array of BrowseResults.
+// It does not correspond to a certain type from the specification
+// but makes encoding and decoding easier.
+type BrowseResultArray struct {
+	ArraySize     int32
+	BrowseResults []*BrowseResult
+}
+
+// NewBrowseResultArray creates a new BrowseResultArray from multiple BrowseResults.
+func NewBrowseResultArray(results []*BrowseResult) *BrowseResultArray {
+	if results == nil {
+		return &BrowseResultArray{
+			ArraySize: 0,
+		}
+	}
+
+	return &BrowseResultArray{
+		ArraySize:     int32(len(results)),
+		BrowseResults: results,
+	}
+}
+
+// DecodeBrowseResultArray decodes given bytes into BrowseResultArray.
+func DecodeBrowseResultArray(b []byte) (*BrowseResultArray, error) {
+	a := &BrowseResultArray{}
+	if err := a.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// DecodeFromBytes decodes given bytes into BrowseResultArray.
+func (a *BrowseResultArray) DecodeFromBytes(b []byte) error {
+	if len(b) < 4 {
+		return errors.NewErrTooShortToDecode(a, "should be longer than 4 bytes")
+	}
+	a.ArraySize = int32(binary.LittleEndian.Uint32(b[:4]))
+	if a.ArraySize <= 0 {
+		return nil
+	}
+
+	offset := 4
+	for i := 1; i <= int(a.ArraySize); i++ {
+		r, err := DecodeBrowseResult(b[offset:])
+		if err != nil {
+			return err
+		}
+		a.BrowseResults = append(a.BrowseResults, r)
+		offset += r.Len()
+	}
+
+	return nil
+}
+
+// Serialize serializes BrowseResultArray into bytes.
+func (a *BrowseResultArray) Serialize() ([]byte, error) {
+	b := make([]byte, a.Len())
+	if err := a.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes BrowseResultArray into bytes.
+func (a *BrowseResultArray) SerializeTo(b []byte) error {
+	offset := 4
+	binary.LittleEndian.PutUint32(b[:4], uint32(a.ArraySize))
+
+	for _, r := range a.BrowseResults {
+		if err := r.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += r.Len()
+	}
+
+	return nil
+}
+
+// Len returns the actual length in int.
+func (a *BrowseResultArray) Len() int {
+	l := 4
+	for _, r := range a.BrowseResults {
+		l += r.Len()
+	}
+
+	return l
+}
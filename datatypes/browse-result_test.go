@@ -0,0 +1,93 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package datatypes
+
+import (
+	"testing"
+
+	"github.com/wmnsk/gopcua/utils/codectest"
+)
+
+func TestBrowseResult(t *testing.T) {
+	cases := []codectest.Case{
+		{
+			Name:   "without references",
+			Struct: NewBrowseResult(0x80340000, nil),
+			Bytes: []byte{
+				// StatusCode
+				0x00, 0x00, 0x34, 0x80,
+				// ContinuationPoint
+				0xff, 0xff, 0xff, 0xff,
+				// References
+				0x00, 0x00, 0x00, 0x00,
+			},
+		},
+		{
+			Name: "with continuation point",
+			Struct: NewBrowseResult(
+				0, []byte{0xde, 0xad, 0xbe, 0xef},
+				NewReferenceDescription(
+					NewTwoByteNodeID(35), true,
+					NewFourByteExpandedNodeID(0, 2253),
+					NewQualifiedName(0, "Server"),
+					NewLocalizedText("", "Server"),
+					NodeClassObject,
+					NewFourByteExpandedNodeID(0, 2004),
+				),
+			),
+			Bytes: []byte{
+				// StatusCode
+				0x00, 0x00, 0x00, 0x00,
+				// ContinuationPoint
+				0x04, 0x00, 0x00, 0x00, 0xde, 0xad, 0xbe, 0xef,
+				// References
+				0x01, 0x00, 0x00, 0x00,
+				0x00, 0x23,
+				0x01,
+				0x01, 0x00, 0xcd, 0x08,
+				0x00, 0x00, 0x06, 0x00, 0x00, 0x00,
+				0x53, 0x65, 0x72, 0x76, 0x65, 0x72,
+				0x02, 0x06, 0x00, 0x00, 0x00,
+				0x53, 0x65, 0x72, 0x76, 0x65, 0x72,
+				0x01, 0x00, 0x00, 0x00,
+				0x01, 0x00, 0xd4, 0x07,
+			},
+		},
+	}
+	codectest.Run(t, cases, func(b []byte) (codectest.S, error) {
+		return DecodeBrowseResult(b)
+	})
+}
+
+func TestBrowseResultArray(t *testing.T) {
+	cases := []codectest.Case{
+		{
+			Name: "normal",
+			Struct: NewBrowseResultArray([]*BrowseResult{
+				NewBrowseResult(0x80340000, nil),
+				NewBrowseResult(0, nil),
+			}),
+			Bytes: []byte{
+				// ArraySize
+				0x02, 0x00, 0x00, 0x00,
+				// StatusCode
+				0x00, 0x00, 0x34, 0x80,
+				// ContinuationPoint
+				0xff, 0xff, 0xff, 0xff,
+				// References
+				0x00, 0x00, 0x00, 0x00,
+				// StatusCode
+				0x00, 0x00, 0x00, 0x00,
+				// ContinuationPoint
+				0xff, 0xff, 0xff, 0xff,
+				// References
+				0x00, 0x00, 0x00, 0x00,
+			},
+		},
+	}
+	codectest.Run(t, cases, func(b []byte) (codectest.S, error) {
+		return DecodeBrowseResultArray(b)
+	})
+}
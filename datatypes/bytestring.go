@@ -5,36 +5,52 @@
 package datatypes
 
 import (
+	"encoding/base64"
 	"encoding/binary"
 
 	"github.com/wmnsk/gopcua/errors"
+	"github.com/wmnsk/gopcua/id"
 )
 
 // ByteString is encoded as sequence of bytes preceded by its length in bytes.
 // The length is encoded as a 32-bit signed integer.
 // If the length of the byte string is −1 then the byte string is ‘null’.
 //
+// A null ByteString is distinct from an empty one, whose length is 0. The Length is -1
+// if it is null, otherwise the length of the Value.
+//
 // Specification: Part 6, 5.2.2.7
 type ByteString struct {
 	Length int32
 	Value  []byte
 }
 
-// NewByteString creates a new ByteString.
+// NewByteString creates a new ByteString. It is null if b is nil,
+// and empty if b is empty but not nil, e.g. []byte{}.
 func NewByteString(b []byte) *ByteString {
-	if len(b) == 0 {
-		s := &ByteString{}
-		s.Length = -1
-
-		return s
+	if b == nil {
+		return NewNullByteString()
 	}
 
-	s := &ByteString{
-		Value: b,
+	return &ByteString{
+		Length: int32(len(b)),
+		Value:  b,
 	}
-	s.Length = int32(len(s.Value))
+}
 
-	return s
+// NewNullByteString creates a new null ByteString.
+func NewNullByteString() *ByteString {
+	return &ByteString{Length: -1}
+}
+
+// ParseByteStringBase64 creates a new ByteString from the base64 text given, which is
+// the one returned by Base64. The empty text is parsed into an empty ByteString.
+func ParseByteStringBase64(s string) (*ByteString, error) {
+	b, err := base64.StdEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return NewByteString(b), nil
 }
 
 // DecodeByteString decodes given bytes into ByteString.
@@ -48,15 +64,22 @@ func DecodeByteString(b []byte) (*ByteString, error) {
 }
 
 // DecodeFromBytes decodes given bytes into OPC UA ByteString.
+//
+// The Value refers to the bytes given, and it is nil if the ByteString is null
+// and empty but not nil if the ByteString is empty.
 func (s *ByteString) DecodeFromBytes(b []byte) error {
 	if len(b) < 4 {
 		return errors.NewErrTooShortToDecode(s, "should be longer than 4 bytes")
 	}
 
 	s.Length = int32(binary.LittleEndian.Uint32(b[:4]))
-	if s.Length <= 0 {
+	if s.Length < 0 {
+		s.Length, s.Value = -1, nil
 		return nil
 	}
+	if len(b[4:]) < int(s.Length) {
+		return errors.NewErrTooShortToDecode(s, "should contain the value of the length")
+	}
 
 	s.Value = b[4 : 4+s.Length]
 	return nil
@@ -78,7 +101,11 @@ func (s *ByteString) SerializeTo(b []byte) error {
 		return errors.NewErrInvalidLength(s, "bytes should be longer")
 	}
 
-	binary.LittleEndian.PutUint32(b[:4], uint32(s.Length))
+	l := int32(len(s.Value))
+	if s.IsNull() {
+		l = -1
+	}
+	binary.LittleEndian.PutUint32(b[:4], uint32(l))
 	copy(b[4:s.Len()], s.Value)
 
 	return nil
@@ -89,13 +116,120 @@ func (s *ByteString) Len() int {
 	return 4 + len(s.Value)
 }
 
-// Get returns the value in Golang's built-in type string.
+// DataType returns type of Data.
+func (s *ByteString) DataType() uint16 {
+	return id.ByteString
+}
+
+// IsNull reports whether the ByteString is null.
+func (s *ByteString) IsNull() bool {
+	return s.Length < 0 && len(s.Value) == 0
+}
+
+// Get returns the value in Golang's built-in type []byte, which is nil if the ByteString is null.
 func (s *ByteString) Get() []byte {
 	return s.Value
 }
 
-// Set sets the string value in ByteString and calcurate length.
+// Set sets the value in ByteString and calculates the length.
+// The ByteString is null if b is nil, as in NewByteString.
 func (s *ByteString) Set(b []byte) {
-	s.Value = b
-	s.Length = int32(len(s.Value))
+	*s = *NewByteString(b)
+}
+
+// Base64 returns the value in base64 text, which is empty if the ByteString is null or empty.
+func (s *ByteString) Base64() string {
+	return base64.StdEncoding.EncodeToString(s.Value)
+}
+
+// ByteStringArray represents the ByteStringArray.
+type ByteStringArray struct {
+	ArraySize   int32
+	ByteStrings []*ByteString
+}
+
+// NewByteStringArray creates a new ByteStringArray from multiple byte slices.
+func NewByteStringArray(bs [][]byte) *ByteStringArray {
+	if bs == nil {
+		return &ByteStringArray{
+			ArraySize: 0,
+		}
+	}
+
+	s := &ByteStringArray{
+		ArraySize: int32(len(bs)),
+	}
+	for _, b := range bs {
+		s.ByteStrings = append(s.ByteStrings, NewByteString(b))
+	}
+
+	return s
+}
+
+// DecodeByteStringArray decodes given bytes into ByteStringArray.
+func DecodeByteStringArray(b []byte) (*ByteStringArray, error) {
+	s := &ByteStringArray{}
+	if err := s.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// DecodeFromBytes decodes given bytes into ByteStringArray.
+func (s *ByteStringArray) DecodeFromBytes(b []byte) error {
+	if len(b) < 4 {
+		return errors.NewErrTooShortToDecode(s, "should be longer than 4 bytes")
+	}
+	s.ArraySize = int32(binary.LittleEndian.Uint32(b[:4]))
+	if s.ArraySize <= 0 {
+		return nil
+	}
+
+	var offset = 4
+	for i := 1; i <= int(s.ArraySize); i++ {
+		bs, err := DecodeByteString(b[offset:])
+		if err != nil {
+			return err
+		}
+		s.ByteStrings = append(s.ByteStrings, bs)
+		offset += bs.Len()
+	}
+
+	return nil
+}
+
+// Serialize serializes ByteStringArray into bytes.
+func (s *ByteStringArray) Serialize() ([]byte, error) {
+	b := make([]byte, s.Len())
+	if err := s.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes ByteStringArray into bytes.
+func (s *ByteStringArray) SerializeTo(b []byte) error {
+	var offset = 4
+	binary.LittleEndian.PutUint32(b[:4], uint32(s.ArraySize))
+
+	for _, bs := range s.ByteStrings {
+		if err := bs.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += bs.Len()
+	}
+
+	return nil
+}
+
+// Len returns the actual length in int.
+func (s *ByteStringArray) Len() int {
+	l := 4
+	for _, bs := range s.ByteStrings {
+		l += bs.Len()
+	}
+
+	return l
 }
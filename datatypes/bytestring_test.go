@@ -7,12 +7,15 @@ package datatypes
 import (
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
+
 	"github.com/wmnsk/gopcua/utils/codectest"
 )
 
 func TestByteString(t *testing.T) {
 	cases := []codectest.Case{
 		{
+			Name:   "normal",
 			Struct: NewByteString([]byte{0xde, 0xad, 0xbe, 0xef}),
 			Bytes: []byte{
 				// length
@@ -21,8 +24,108 @@ func TestByteString(t *testing.T) {
 				0xde, 0xad, 0xbe, 0xef,
 			},
 		},
+		{
+			Name:   "null",
+			Struct: NewNullByteString(),
+			Bytes:  []byte{0xff, 0xff, 0xff, 0xff},
+		},
+		{
+			Name:   "empty",
+			Struct: NewByteString([]byte{}),
+			Bytes:  []byte{0x00, 0x00, 0x00, 0x00},
+		},
 	}
 	codectest.Run(t, cases, func(b []byte) (codectest.S, error) {
 		return DecodeByteString(b)
 	})
+
+	t.Run("null-and-empty", func(t *testing.T) {
+		null, err := DecodeByteString([]byte{0xff, 0xff, 0xff, 0xff})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !null.IsNull() || null.Get() != nil {
+			t.Errorf("got %v want null", null)
+		}
+
+		empty, err := DecodeByteString([]byte{0x00, 0x00, 0x00, 0x00})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if empty.IsNull() || empty.Get() == nil || len(empty.Get()) != 0 {
+			t.Errorf("got %v want empty", empty)
+		}
+
+		if !NewByteString(nil).IsNull() {
+			t.Error("NewByteString(nil) should be null")
+		}
+		s := NewByteString([]byte{0x01})
+		s.Set(nil)
+		if !s.IsNull() {
+			t.Error("Set(nil) should make the ByteString null")
+		}
+	})
+
+	t.Run("too-short", func(t *testing.T) {
+		if _, err := DecodeByteString([]byte{0x04, 0x00, 0x00, 0x00, 0xde}); err == nil {
+			t.Error("expected error for the value shorter than the length, got nil")
+		}
+	})
+}
+
+func TestByteStringBase64(t *testing.T) {
+	cases := []struct {
+		name string
+		b    *ByteString
+		s    string
+	}{
+		{"normal", NewByteString([]byte{0xde, 0xad, 0xbe, 0xef}), "3q2+7w=="},
+		{"empty", NewByteString([]byte{}), ""},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got, want := c.b.Base64(), c.s; got != want {
+				t.Errorf("got %s want %s", got, want)
+			}
+			b, err := ParseByteStringBase64(c.s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(b, c.b); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+
+	if got := NewNullByteString().Base64(); got != "" {
+		t.Errorf("got %s want empty for null", got)
+	}
+	if _, err := ParseByteStringBase64("not base64!"); err == nil {
+		t.Error("expected error for invalid base64, got nil")
+	}
+}
+
+func TestByteStringArray(t *testing.T) {
+	cases := []codectest.Case{
+		{
+			Name:   "empty",
+			Struct: NewByteStringArray(nil),
+			Bytes:  []byte{0x00, 0x00, 0x00, 0x00},
+		},
+		{
+			Name:   "normal",
+			Struct: NewByteStringArray([][]byte{{0xde, 0xad}, {0xbe, 0xef}}),
+			Bytes: []byte{
+				// ArraySize
+				0x02, 0x00, 0x00, 0x00,
+				// first
+				0x02, 0x00, 0x00, 0x00, 0xde, 0xad,
+				// second
+				0x02, 0x00, 0x00, 0x00, 0xbe, 0xef,
+			},
+		},
+	}
+	codectest.Run(t, cases, func(b []byte) (codectest.S, error) {
+		return DecodeByteStringArray(b)
+	})
 }
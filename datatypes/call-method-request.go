@@ -0,0 +1,200 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package datatypes
+
+import (
+	"encoding/binary"
+
+	"github.com/wmnsk/gopcua/errors"
+)
+
+// CallMethodRequest is a Method to call with its input arguments.
+//
+// ObjectID is the Object or ObjectType on which the Method is called,
+// and MethodID is the Method which should be a component of it.
+//
+// Specification: Part 4, 5.11.2.2
+type CallMethodRequest struct {
+	ObjectID       *NodeID
+	MethodID       *NodeID
+	InputArguments *VariantArray
+}
+
+// NewCallMethodRequest creates a new CallMethodRequest.
+func NewCallMethodRequest(objectID, methodID *NodeID, inputs ...*Variant) *CallMethodRequest {
+	return &CallMethodRequest{
+		ObjectID:       objectID,
+		MethodID:       methodID,
+		InputArguments: NewVariantArray(inputs),
+	}
+}
+
+// DecodeCallMethodRequest decodes given bytes into CallMethodRequest.
+func DecodeCallMethodRequest(b []byte) (*CallMethodRequest, error) {
+	c := &CallMethodRequest{}
+	if err := c.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// DecodeFromBytes decodes given bytes into CallMethodRequest.
+func (c *CallMethodRequest) DecodeFromBytes(b []byte) error {
+	c.ObjectID = &NodeID{}
+	if err := c.ObjectID.DecodeFromBytes(b); err != nil {
+		return err
+	}
+	offset := c.ObjectID.Len()
+
+	c.MethodID = &NodeID{}
+	if err := c.MethodID.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += c.MethodID.Len()
+
+	c.InputArguments = &VariantArray{}
+	return c.InputArguments.DecodeFromBytes(b[offset:])
+}
+
+// Serialize serializes CallMethodRequest into bytes.
+func (c *CallMethodRequest) Serialize() ([]byte, error) {
+	b := make([]byte, c.Len())
+	if err := c.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes CallMethodRequest into bytes.
+func (c *CallMethodRequest) SerializeTo(b []byte) error {
+	offset := 0
+	if c.ObjectID != nil {
+		if err := c.ObjectID.SerializeTo(b); err != nil {
+			return err
+		}
+		offset += c.ObjectID.Len()
+	}
+
+	if c.MethodID != nil {
+		if err := c.MethodID.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += c.MethodID.Len()
+	}
+
+	if c.InputArguments != nil {
+		return c.InputArguments.SerializeTo(b[offset:])
+	}
+
+	return nil
+}
+
+// Len returns the actual length of CallMethodRequest in int.
+func (c *CallMethodRequest) Len() int {
+	var l int
+	if c.ObjectID != nil {
+		l += c.ObjectID.Len()
+	}
+	if c.MethodID != nil {
+		l += c.MethodID.Len()
+	}
+	if c.InputArguments != nil {
+		l += c.InputArguments.Len()
+	}
+
+	return l
+}
+
+// CallMethodRequestArray represents an array of CallMethodRequests.
+// It does not correspond to a certain type from the specification
+// but makes encoding and decoding easier.
+type CallMethodRequestArray struct {
+	ArraySize          int32
+	CallMethodRequests []*CallMethodRequest
+}
+
+// NewCallMethodRequestArray creates a new CallMethodRequestArray from multiple CallMethodRequests.
+func NewCallMethodRequestArray(reqs []*CallMethodRequest) *CallMethodRequestArray {
+	if reqs == nil {
+		return &CallMethodRequestArray{
+			ArraySize: 0,
+		}
+	}
+
+	return &CallMethodRequestArray{
+		ArraySize:          int32(len(reqs)),
+		CallMethodRequests: reqs,
+	}
+}
+
+// DecodeCallMethodRequestArray decodes given bytes into CallMethodRequestArray.
+func DecodeCallMethodRequestArray(b []byte) (*CallMethodRequestArray, error) {
+	a := &CallMethodRequestArray{}
+	if err := a.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// DecodeFromBytes decodes given bytes into CallMethodRequestArray.
+func (a *CallMethodRequestArray) DecodeFromBytes(b []byte) error {
+	if len(b) < 4 {
+		return errors.NewErrTooShortToDecode(a, "should be longer than 4 bytes")
+	}
+	a.ArraySize = int32(binary.LittleEndian.Uint32(b[:4]))
+	if a.ArraySize <= 0 {
+		return nil
+	}
+
+	offset := 4
+	for i := 1; i <= int(a.ArraySize); i++ {
+		c, err := DecodeCallMethodRequest(b[offset:])
+		if err != nil {
+			return err
+		}
+		a.CallMethodRequests = append(a.CallMethodRequests, c)
+		offset += c.Len()
+	}
+
+	return nil
+}
+
+// Serialize serializes CallMethodRequestArray into bytes.
+func (a *CallMethodRequestArray) Serialize() ([]byte, error) {
+	b := make([]byte, a.Len())
+	if err := a.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes CallMethodRequestArray into bytes.
+func (a *CallMethodRequestArray) SerializeTo(b []byte) error {
+	offset := 4
+	binary.LittleEndian.PutUint32(b[:4], uint32(a.ArraySize))
+
+	for _, c := range a.CallMethodRequests {
+		if err := c.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += c.Len()
+	}
+
+	return nil
+}
+
+// Len returns the actual length in int.
+func (a *CallMethodRequestArray) Len() int {
+	l := 4
+	for _, c := range a.CallMethodRequests {
+		l += c.Len()
+	}
+
+	return l
+}
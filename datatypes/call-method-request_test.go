@@ -0,0 +1,48 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package datatypes
+
+import (
+	"testing"
+
+	"github.com/wmnsk/gopcua/utils/codectest"
+)
+
+func TestCallMethodRequest(t *testing.T) {
+	cases := []codectest.Case{
+		{
+			Name: "normal",
+			Struct: NewCallMethodRequest(
+				NewFourByteNodeID(2, 1000), NewFourByteNodeID(2, 1001),
+				NewVariant(NewFloat(2.50025)), NewVariant(NewBoolean(true)),
+			),
+			Bytes: []byte{
+				// ObjectID
+				0x01, 0x02, 0xe8, 0x03,
+				// MethodID
+				0x01, 0x02, 0xe9, 0x03,
+				// InputArguments
+				0x02, 0x00, 0x00, 0x00,
+				0x0a, 0x19, 0x04, 0x20, 0x40,
+				0x01, 0x01,
+			},
+		},
+		{
+			Name:   "no arguments",
+			Struct: NewCallMethodRequest(NewFourByteNodeID(2, 1000), NewFourByteNodeID(2, 1001)),
+			Bytes: []byte{
+				// ObjectID
+				0x01, 0x02, 0xe8, 0x03,
+				// MethodID
+				0x01, 0x02, 0xe9, 0x03,
+				// InputArguments
+				0x00, 0x00, 0x00, 0x00,
+			},
+		},
+	}
+	codectest.Run(t, cases, func(b []byte) (codectest.S, error) {
+		return DecodeCallMethodRequest(b)
+	})
+}
@@ -0,0 +1,282 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package datatypes
+
+import (
+	"encoding/binary"
+	"io"
+	"sync"
+)
+
+// Encodable is a value which can be written with Encoder.WriteData, such as Data.
+type Encodable interface {
+	SerializeTo([]byte) error
+	Len() int
+}
+
+// Decodable is a value which can be read with Decoder.ReadData, such as Data.
+type Decodable interface {
+	DecodeFromBytes([]byte) error
+	Len() int
+}
+
+// Encoder writes the values in the OPC UA Binary encoding to a growable buffer.
+//
+// The zero value is an empty Encoder ready to use. To avoid allocating the buffer
+// for each value, get the Encoder with GetEncoder and put it back with PutEncoder.
+//
+// Specification: Part 6, 5.2
+type Encoder struct {
+	buf []byte
+}
+
+// NewEncoder creates a new Encoder which appends the values to buf.
+// Give buf[:0] to write into the capacity of buf without allocating.
+func NewEncoder(buf []byte) *Encoder {
+	return &Encoder{buf: buf}
+}
+
+// maxPooledEncoder is the capacity of the largest buffer kept in the pool,
+// so that an occasional large message does not stay in memory.
+const maxPooledEncoder = 0xffff
+
+var encoderPool = sync.Pool{
+	New: func() interface{} {
+		return &Encoder{buf: make([]byte, 0, 64)}
+	},
+}
+
+// GetEncoder returns an empty Encoder from the pool.
+func GetEncoder() *Encoder {
+	return encoderPool.Get().(*Encoder)
+}
+
+// PutEncoder resets enc and puts it back to the pool. The bytes returned by
+// enc.Bytes must not be used after that.
+func PutEncoder(enc *Encoder) {
+	if cap(enc.buf) > maxPooledEncoder {
+		return
+	}
+	enc.Reset()
+	encoderPool.Put(enc)
+}
+
+// Bytes returns the bytes written, which are valid until the next write or Reset.
+func (e *Encoder) Bytes() []byte {
+	return e.buf
+}
+
+// Len returns the number of bytes written.
+func (e *Encoder) Len() int {
+	return len(e.buf)
+}
+
+// Reset discards the bytes written and keeps the buffer for reuse.
+func (e *Encoder) Reset() {
+	e.buf = e.buf[:0]
+}
+
+// grow extends the bytes written by n and returns the extended part to write into.
+func (e *Encoder) grow(n int) []byte {
+	l := len(e.buf)
+	if cap(e.buf)-l < n {
+		buf := make([]byte, l, 2*cap(e.buf)+n)
+		copy(buf, e.buf)
+		e.buf = buf
+	}
+	e.buf = e.buf[:l+n]
+	return e.buf[l:]
+}
+
+// WriteUint8 writes v as a Byte.
+func (e *Encoder) WriteUint8(v uint8) {
+	e.grow(1)[0] = v
+}
+
+// WriteBool writes v as a Boolean.
+func (e *Encoder) WriteBool(v bool) {
+	if v {
+		e.WriteUint8(1)
+		return
+	}
+	e.WriteUint8(0)
+}
+
+// WriteUint16 writes v as a UInt16.
+func (e *Encoder) WriteUint16(v uint16) {
+	binary.LittleEndian.PutUint16(e.grow(2), v)
+}
+
+// WriteUint32 writes v as a UInt32.
+func (e *Encoder) WriteUint32(v uint32) {
+	binary.LittleEndian.PutUint32(e.grow(4), v)
+}
+
+// WriteInt32 writes v as an Int32.
+func (e *Encoder) WriteInt32(v int32) {
+	e.WriteUint32(uint32(v))
+}
+
+// WriteUint64 writes v as a UInt64.
+func (e *Encoder) WriteUint64(v uint64) {
+	binary.LittleEndian.PutUint64(e.grow(8), v)
+}
+
+// WriteBytes writes b as it is, without the length.
+func (e *Encoder) WriteBytes(b []byte) {
+	copy(e.grow(len(b)), b)
+}
+
+// WriteByteString writes b prefixed by its length as a ByteString.
+// A nil b is written as the null ByteString, which has the length of -1.
+func (e *Encoder) WriteByteString(b []byte) {
+	if b == nil {
+		e.WriteInt32(-1)
+		return
+	}
+	e.WriteInt32(int32(len(b)))
+	e.WriteBytes(b)
+}
+
+// WriteString writes s prefixed by its length as a String.
+func (e *Encoder) WriteString(s string) {
+	e.WriteInt32(int32(len(s)))
+	copy(e.grow(len(s)), s)
+}
+
+// WriteData writes v serialized with its SerializeTo. Nothing is written if it fails.
+func (e *Encoder) WriteData(v Encodable) error {
+	l := len(e.buf)
+	if err := v.SerializeTo(e.grow(v.Len())); err != nil {
+		e.buf = e.buf[:l]
+		return err
+	}
+	return nil
+}
+
+// Decoder reads the values in the OPC UA Binary encoding from a byte slice,
+// advancing its position by the bytes read.
+//
+// The read methods return io.ErrUnexpectedEOF without advancing if the bytes
+// left are shorter than the value.
+//
+// Specification: Part 6, 5.2
+type Decoder struct {
+	b   []byte
+	off int
+}
+
+// NewDecoder creates a new Decoder which reads the values from b.
+func NewDecoder(b []byte) *Decoder {
+	return &Decoder{b: b}
+}
+
+// Offset returns the number of bytes read.
+func (d *Decoder) Offset() int {
+	return d.off
+}
+
+// Remaining returns the bytes not read yet.
+func (d *Decoder) Remaining() []byte {
+	return d.b[d.off:]
+}
+
+// next returns the next n bytes and advances the position by n.
+func (d *Decoder) next(n int) ([]byte, error) {
+	if n < 0 || len(d.b)-d.off < n {
+		return nil, io.ErrUnexpectedEOF
+	}
+	b := d.b[d.off : d.off+n]
+	d.off += n
+	return b, nil
+}
+
+// ReadUint8 reads a Byte.
+func (d *Decoder) ReadUint8() (uint8, error) {
+	b, err := d.next(1)
+	if err != nil {
+		return 0, err
+	}
+	return b[0], nil
+}
+
+// ReadBool reads a Boolean, which is true if the byte is not 0.
+func (d *Decoder) ReadBool() (bool, error) {
+	v, err := d.ReadUint8()
+	return v != 0, err
+}
+
+// ReadUint16 reads a UInt16.
+func (d *Decoder) ReadUint16() (uint16, error) {
+	b, err := d.next(2)
+	if err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint16(b), nil
+}
+
+// ReadUint32 reads a UInt32.
+func (d *Decoder) ReadUint32() (uint32, error) {
+	b, err := d.next(4)
+	if err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint32(b), nil
+}
+
+// ReadInt32 reads an Int32.
+func (d *Decoder) ReadInt32() (int32, error) {
+	v, err := d.ReadUint32()
+	return int32(v), err
+}
+
+// ReadUint64 reads a UInt64.
+func (d *Decoder) ReadUint64() (uint64, error) {
+	b, err := d.next(8)
+	if err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint64(b), nil
+}
+
+// ReadBytes reads the next n bytes, which refer to the bytes given to NewDecoder.
+func (d *Decoder) ReadBytes(n int) ([]byte, error) {
+	return d.next(n)
+}
+
+// ReadByteString reads a ByteString, which refers to the bytes given to NewDecoder.
+// nil is returned for the null ByteString.
+func (d *Decoder) ReadByteString() ([]byte, error) {
+	off := d.off
+	l, err := d.ReadInt32()
+	if err != nil {
+		return nil, err
+	}
+	if l < 0 {
+		return nil, nil
+	}
+	b, err := d.next(int(l))
+	if err != nil {
+		d.off = off
+		return nil, err
+	}
+	return b, nil
+}
+
+// ReadString reads a String. The null String is read as an empty string.
+func (d *Decoder) ReadString() (string, error) {
+	b, err := d.ReadByteString()
+	return string(b), err
+}
+
+// ReadData decodes v with its DecodeFromBytes from the bytes left, and advances
+// the position by its Len.
+func (d *Decoder) ReadData(v Decodable) error {
+	if err := v.DecodeFromBytes(d.b[d.off:]); err != nil {
+		return err
+	}
+	d.off += v.Len()
+	return nil
+}
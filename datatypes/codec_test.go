@@ -0,0 +1,111 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package datatypes
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+func TestEncoderDecoder(t *testing.T) {
+	enc := NewEncoder(nil)
+	enc.WriteUint8(0x01)
+	enc.WriteBool(true)
+	enc.WriteUint16(0x0302)
+	enc.WriteUint32(0x07060504)
+	enc.WriteInt32(-1)
+	enc.WriteUint64(0x0f0e0d0c0b0a0908)
+	enc.WriteString("foo")
+	enc.WriteByteString(nil)
+	enc.WriteByteString([]byte{})
+	enc.WriteBytes([]byte{0xde, 0xad})
+	if err := enc.WriteData(NewInt32(2)); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []byte{
+		0x01, 0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07,
+		0xff, 0xff, 0xff, 0xff,
+		0x08, 0x09, 0x0a, 0x0b, 0x0c, 0x0d, 0x0e, 0x0f,
+		0x03, 0x00, 0x00, 0x00, 0x66, 0x6f, 0x6f,
+		0xff, 0xff, 0xff, 0xff,
+		0x00, 0x00, 0x00, 0x00,
+		0xde, 0xad,
+		0x02, 0x00, 0x00, 0x00,
+	}
+	if got := enc.Bytes(); !bytes.Equal(got, want) {
+		t.Fatalf("got %x want %x", got, want)
+	}
+
+	dec := NewDecoder(enc.Bytes())
+	check := func(got, want interface{}, err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Fatalf("got %v want %v", got, want)
+		}
+	}
+	u8, err := dec.ReadUint8()
+	check(u8, uint8(0x01), err)
+	bl, err := dec.ReadBool()
+	check(bl, true, err)
+	u16, err := dec.ReadUint16()
+	check(u16, uint16(0x0302), err)
+	u32, err := dec.ReadUint32()
+	check(u32, uint32(0x07060504), err)
+	i32, err := dec.ReadInt32()
+	check(i32, int32(-1), err)
+	u64, err := dec.ReadUint64()
+	check(u64, uint64(0x0f0e0d0c0b0a0908), err)
+	s, err := dec.ReadString()
+	check(s, "foo", err)
+	null, err := dec.ReadByteString()
+	check(null == nil, true, err)
+	empty, err := dec.ReadByteString()
+	check(empty != nil && len(empty) == 0, true, err)
+	raw, err := dec.ReadBytes(2)
+	check(bytes.Equal(raw, []byte{0xde, 0xad}), true, err)
+	v := &Int32{}
+	if err := dec.ReadData(v); err != nil || v.Value != 2 {
+		t.Fatalf("got %d, %v want 2, nil", v.Value, err)
+	}
+	check(dec.Offset(), len(want), nil)
+
+	if _, err := dec.ReadUint8(); err != io.ErrUnexpectedEOF {
+		t.Fatalf("got error %v want %v", err, io.ErrUnexpectedEOF)
+	}
+}
+
+func TestDecoderTooShort(t *testing.T) {
+	// the ByteString is shorter than its length.
+	dec := NewDecoder([]byte{0x04, 0x00, 0x00, 0x00, 0xde, 0xad})
+	if _, err := dec.ReadByteString(); err != io.ErrUnexpectedEOF {
+		t.Fatalf("got error %v want %v", err, io.ErrUnexpectedEOF)
+	}
+	if got := dec.Offset(); got != 0 {
+		t.Fatalf("got offset %d want 0", got)
+	}
+	if _, err := dec.ReadUint64(); err != io.ErrUnexpectedEOF {
+		t.Fatalf("got error %v want %v", err, io.ErrUnexpectedEOF)
+	}
+	if u32, err := dec.ReadUint32(); err != nil || u32 != 4 {
+		t.Fatalf("got %d, %v want 4, nil", u32, err)
+	}
+}
+
+func TestEncoderPool(t *testing.T) {
+	enc := GetEncoder()
+	enc.WriteUint32(1)
+	PutEncoder(enc)
+
+	enc = GetEncoder()
+	defer PutEncoder(enc)
+	if got := enc.Len(); got != 0 {
+		t.Fatalf("got %d bytes in the Encoder from the pool want 0", got)
+	}
+}
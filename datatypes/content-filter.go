@@ -0,0 +1,208 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package datatypes
+
+import (
+	"encoding/binary"
+
+	"github.com/wmnsk/gopcua/errors"
+)
+
+// FilterOperator is the operator of ContentFilterElement.
+//
+// Specification: Part 4, 7.4.3
+type FilterOperator uint32
+
+// FilterOperator definitions.
+const (
+	FilterOperatorEquals FilterOperator = iota
+	FilterOperatorIsNull
+	FilterOperatorGreaterThan
+	FilterOperatorLessThan
+	FilterOperatorGreaterThanOrEqual
+	FilterOperatorLessThanOrEqual
+	FilterOperatorLike
+	FilterOperatorNot
+	FilterOperatorBetween
+	FilterOperatorInList
+	FilterOperatorAnd
+	FilterOperatorOr
+	FilterOperatorCast
+	FilterOperatorInView
+	FilterOperatorOfType
+	FilterOperatorRelatedTo
+	FilterOperatorBitwiseAnd
+	FilterOperatorBitwiseOr
+)
+
+// ContentFilterElement is an element of ContentFilter, which applies the FilterOperator
+// to the FilterOperands, e.g. ElementOperand, LiteralOperand and SimpleAttributeOperand.
+//
+// Specification: Part 4, 7.4.1
+type ContentFilterElement struct {
+	FilterOperator FilterOperator
+	FilterOperands *ExtensionObjectArray
+}
+
+// NewContentFilterElement creates a new ContentFilterElement.
+func NewContentFilterElement(op FilterOperator, operands ...ExtensionObjectValue) *ContentFilterElement {
+	objs := make([]*ExtensionObject, len(operands))
+	for i, o := range operands {
+		objs[i] = NewExtensionObject(ExtensionObjectBinary, o)
+	}
+
+	return &ContentFilterElement{
+		FilterOperator: op,
+		FilterOperands: NewExtensionObjectArray(objs),
+	}
+}
+
+// DecodeContentFilterElement decodes given bytes into ContentFilterElement.
+func DecodeContentFilterElement(b []byte) (*ContentFilterElement, error) {
+	e := &ContentFilterElement{}
+	if err := e.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+// DecodeFromBytes decodes given bytes into ContentFilterElement.
+func (e *ContentFilterElement) DecodeFromBytes(b []byte) error {
+	return e.decode(b, 0)
+}
+
+// decode decodes given bytes into ContentFilterElement nested in the Variants to depth.
+func (e *ContentFilterElement) decode(b []byte, depth int) error {
+	if len(b) < 8 {
+		return errors.NewErrTooShortToDecode(e, "should be longer than 8 bytes")
+	}
+	e.FilterOperator = FilterOperator(binary.LittleEndian.Uint32(b[:4]))
+
+	e.FilterOperands = &ExtensionObjectArray{}
+	return e.FilterOperands.decode(b[4:], depth)
+}
+
+// Serialize serializes ContentFilterElement into bytes.
+func (e *ContentFilterElement) Serialize() ([]byte, error) {
+	b := make([]byte, e.Len())
+	if err := e.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes ContentFilterElement into bytes.
+func (e *ContentFilterElement) SerializeTo(b []byte) error {
+	binary.LittleEndian.PutUint32(b[:4], uint32(e.FilterOperator))
+
+	if e.FilterOperands != nil {
+		return e.FilterOperands.SerializeTo(b[4:])
+	}
+
+	return nil
+}
+
+// Len returns the actual length of ContentFilterElement in int.
+func (e *ContentFilterElement) Len() int {
+	l := 4
+	if e.FilterOperands != nil {
+		l += e.FilterOperands.Len()
+	}
+
+	return l
+}
+
+// ContentFilter is a filter of the Events or the Nodes, which is the array of ContentFilterElements
+// evaluated from the first one. It is used as the WhereClause of EventFilter, and the empty
+// ContentFilter selects all the Events.
+//
+// Specification: Part 4, 7.4.1
+type ContentFilter struct {
+	ArraySize int32
+	Elements  []*ContentFilterElement
+}
+
+// NewContentFilter creates a new ContentFilter from multiple ContentFilterElements.
+func NewContentFilter(elems ...*ContentFilterElement) *ContentFilter {
+	return &ContentFilter{
+		ArraySize: int32(len(elems)),
+		Elements:  elems,
+	}
+}
+
+// DecodeContentFilter decodes given bytes into ContentFilter.
+func DecodeContentFilter(b []byte) (*ContentFilter, error) {
+	f := &ContentFilter{}
+	if err := f.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// DecodeFromBytes decodes given bytes into ContentFilter.
+func (f *ContentFilter) DecodeFromBytes(b []byte) error {
+	return f.decode(b, 0)
+}
+
+// decode decodes given bytes into ContentFilter nested in the Variants to depth.
+func (f *ContentFilter) decode(b []byte, depth int) error {
+	if len(b) < 4 {
+		return errors.NewErrTooShortToDecode(f, "should be longer than 4 bytes")
+	}
+	f.ArraySize = int32(binary.LittleEndian.Uint32(b[:4]))
+	if f.ArraySize <= 0 {
+		return nil
+	}
+
+	offset := 4
+	for i := 1; i <= int(f.ArraySize); i++ {
+		e := &ContentFilterElement{}
+		if err := e.decode(b[offset:], depth); err != nil {
+			return err
+		}
+		f.Elements = append(f.Elements, e)
+		offset += e.Len()
+	}
+
+	return nil
+}
+
+// Serialize serializes ContentFilter into bytes.
+func (f *ContentFilter) Serialize() ([]byte, error) {
+	b := make([]byte, f.Len())
+	if err := f.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes ContentFilter into bytes.
+func (f *ContentFilter) SerializeTo(b []byte) error {
+	offset := 4
+	binary.LittleEndian.PutUint32(b[:4], uint32(f.ArraySize))
+
+	for _, e := range f.Elements {
+		if err := e.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += e.Len()
+	}
+
+	return nil
+}
+
+// Len returns the actual length of ContentFilter in int.
+func (f *ContentFilter) Len() int {
+	l := 4
+	for _, e := range f.Elements {
+		l += e.Len()
+	}
+
+	return l
+}
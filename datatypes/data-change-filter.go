@@ -0,0 +1,107 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package datatypes
+
+import (
+	"encoding/binary"
+	"math"
+
+	"github.com/wmnsk/gopcua/errors"
+	"github.com/wmnsk/gopcua/id"
+)
+
+// DataChangeTrigger specifies the conditions under which a data change notification should be reported.
+//
+// Specification: Part 4, 7.17.2
+type DataChangeTrigger uint32
+
+// DataChangeTrigger definitions.
+const (
+	DataChangeTriggerStatus DataChangeTrigger = iota
+	DataChangeTriggerStatusValue
+	DataChangeTriggerStatusValueTimestamp
+)
+
+// DeadbandType specifies how the DeadbandValue of DataChangeFilter is interpreted.
+//
+// Specification: Part 4, 7.17.2
+type DeadbandType uint32
+
+// DeadbandType definitions.
+const (
+	DeadbandTypeNone DeadbandType = iota
+	DeadbandTypeAbsolute
+	DeadbandTypePercent
+)
+
+// DataChangeFilter is a MonitoringFilter which defines the conditions under which
+// a data change notification should be reported and, optionally, a deadband to
+// suppress the changes of Value smaller than DeadbandValue.
+//
+// Specification: Part 4, 7.17.2
+type DataChangeFilter struct {
+	Trigger       DataChangeTrigger
+	DeadbandType  DeadbandType
+	DeadbandValue float64
+}
+
+// NewDataChangeFilter creates a new DataChangeFilter.
+func NewDataChangeFilter(trigger DataChangeTrigger, deadbandType DeadbandType, deadbandValue float64) *DataChangeFilter {
+	return &DataChangeFilter{
+		Trigger:       trigger,
+		DeadbandType:  deadbandType,
+		DeadbandValue: deadbandValue,
+	}
+}
+
+// DecodeDataChangeFilter decodes given bytes into DataChangeFilter.
+func DecodeDataChangeFilter(b []byte) (*DataChangeFilter, error) {
+	f := &DataChangeFilter{}
+	if err := f.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// DecodeFromBytes decodes given bytes into DataChangeFilter.
+func (f *DataChangeFilter) DecodeFromBytes(b []byte) error {
+	if len(b) < 16 {
+		return errors.NewErrTooShortToDecode(f, "should be longer than 16 bytes")
+	}
+
+	f.Trigger = DataChangeTrigger(binary.LittleEndian.Uint32(b[0:4]))
+	f.DeadbandType = DeadbandType(binary.LittleEndian.Uint32(b[4:8]))
+	f.DeadbandValue = math.Float64frombits(binary.LittleEndian.Uint64(b[8:16]))
+	return nil
+}
+
+// Serialize serializes DataChangeFilter into bytes.
+func (f *DataChangeFilter) Serialize() ([]byte, error) {
+	b := make([]byte, f.Len())
+	if err := f.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes DataChangeFilter into bytes.
+func (f *DataChangeFilter) SerializeTo(b []byte) error {
+	binary.LittleEndian.PutUint32(b[0:4], uint32(f.Trigger))
+	binary.LittleEndian.PutUint32(b[4:8], uint32(f.DeadbandType))
+	binary.LittleEndian.PutUint64(b[8:16], math.Float64bits(f.DeadbandValue))
+	return nil
+}
+
+// Len returns the actual length of DataChangeFilter in int.
+func (f *DataChangeFilter) Len() int {
+	return 16
+}
+
+// Type returns type of DataChangeFilter defined in NodeIds.csv in int.
+func (f *DataChangeFilter) Type() int {
+	return id.DataChangeFilter_Encoding_DefaultBinary
+}
@@ -0,0 +1,55 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package datatypes
+
+import (
+	"testing"
+
+	"github.com/wmnsk/gopcua/utils/codectest"
+)
+
+func TestDataChangeFilter(t *testing.T) {
+	cases := []codectest.Case{
+		{
+			Name:   "status-value",
+			Struct: NewDataChangeFilter(DataChangeTriggerStatusValue, DeadbandTypeNone, 0),
+			Bytes: []byte{
+				// Trigger
+				0x01, 0x00, 0x00, 0x00,
+				// DeadbandType
+				0x00, 0x00, 0x00, 0x00,
+				// DeadbandValue
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+			},
+		},
+		{
+			Name:   "absolute-deadband",
+			Struct: NewDataChangeFilter(DataChangeTriggerStatusValue, DeadbandTypeAbsolute, 0.5),
+			Bytes: []byte{
+				// Trigger
+				0x01, 0x00, 0x00, 0x00,
+				// DeadbandType
+				0x01, 0x00, 0x00, 0x00,
+				// DeadbandValue
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xe0, 0x3f,
+			},
+		},
+		{
+			Name:   "percent-deadband",
+			Struct: NewDataChangeFilter(DataChangeTriggerStatusValueTimestamp, DeadbandTypePercent, 10),
+			Bytes: []byte{
+				// Trigger
+				0x02, 0x00, 0x00, 0x00,
+				// DeadbandType
+				0x02, 0x00, 0x00, 0x00,
+				// DeadbandValue
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x24, 0x40,
+			},
+		},
+	}
+	codectest.Run(t, cases, func(b []byte) (codectest.S, error) {
+		return DecodeDataChangeFilter(b)
+	})
+}
@@ -0,0 +1,98 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package datatypes
+
+import (
+	"encoding/binary"
+
+	"github.com/wmnsk/gopcua/errors"
+	"github.com/wmnsk/gopcua/id"
+)
+
+// DataChangeNotification is the NotificationData which contains the changes of the values
+// of the MonitoredItems in a Subscription.
+//
+// DiagnosticInfos are not supported, as they are returned only when the Client requests
+// them in the RequestHeader. The empty array is always encoded.
+//
+// Specification: Part 4, 7.20.2
+type DataChangeNotification struct {
+	MonitoredItems *MonitoredItemNotificationArray
+}
+
+// NewDataChangeNotification creates a new DataChangeNotification.
+func NewDataChangeNotification(items ...*MonitoredItemNotification) *DataChangeNotification {
+	return &DataChangeNotification{
+		MonitoredItems: NewMonitoredItemNotificationArray(items),
+	}
+}
+
+// DecodeDataChangeNotification decodes given bytes into DataChangeNotification.
+func DecodeDataChangeNotification(b []byte) (*DataChangeNotification, error) {
+	d := &DataChangeNotification{}
+	if err := d.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// DecodeFromBytes decodes given bytes into DataChangeNotification.
+func (d *DataChangeNotification) DecodeFromBytes(b []byte) error {
+	d.MonitoredItems = &MonitoredItemNotificationArray{}
+	if err := d.MonitoredItems.DecodeFromBytes(b); err != nil {
+		return err
+	}
+	offset := d.MonitoredItems.Len()
+
+	if len(b[offset:]) < 4 {
+		return errors.NewErrTooShortToDecode(d, "should contain DiagnosticInfos")
+	}
+	if n := int32(binary.LittleEndian.Uint32(b[offset : offset+4])); n > 0 {
+		return errors.NewErrUnsupported(d, "DiagnosticInfos are not supported")
+	}
+
+	return nil
+}
+
+// Serialize serializes DataChangeNotification into bytes.
+func (d *DataChangeNotification) Serialize() ([]byte, error) {
+	b := make([]byte, d.Len())
+	if err := d.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes DataChangeNotification into bytes.
+func (d *DataChangeNotification) SerializeTo(b []byte) error {
+	offset := 0
+	if d.MonitoredItems != nil {
+		if err := d.MonitoredItems.SerializeTo(b); err != nil {
+			return err
+		}
+		offset += d.MonitoredItems.Len()
+	}
+
+	// DiagnosticInfos
+	binary.LittleEndian.PutUint32(b[offset:offset+4], 0)
+	return nil
+}
+
+// Len returns the actual length of DataChangeNotification in int.
+func (d *DataChangeNotification) Len() int {
+	l := 4
+	if d.MonitoredItems != nil {
+		l += d.MonitoredItems.Len()
+	}
+
+	return l
+}
+
+// Type returns type of DataChangeNotification defined in NodeIds.csv in int.
+func (d *DataChangeNotification) Type() int {
+	return id.DataChangeNotification_Encoding_DefaultBinary
+}
@@ -0,0 +1,43 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package datatypes
+
+import (
+	"testing"
+
+	"github.com/wmnsk/gopcua/utils/codectest"
+)
+
+func TestDataChangeNotification(t *testing.T) {
+	cases := []codectest.Case{
+		{
+			Name: "normal",
+			Struct: NewDataChangeNotification(
+				NewMonitoredItemNotification(1, &DataValue{EncodingMask: 0x01, Value: NewVariant(NewFloat(2.50025))}),
+			),
+			Bytes: []byte{
+				// MonitoredItems
+				0x01, 0x00, 0x00, 0x00,
+				0x01, 0x00, 0x00, 0x00,
+				0x01, 0x0a, 0x19, 0x04, 0x20, 0x40,
+				// DiagnosticInfos
+				0x00, 0x00, 0x00, 0x00,
+			},
+		},
+		{
+			Name:   "no-items",
+			Struct: NewDataChangeNotification(),
+			Bytes: []byte{
+				// MonitoredItems
+				0x00, 0x00, 0x00, 0x00,
+				// DiagnosticInfos
+				0x00, 0x00, 0x00, 0x00,
+			},
+		},
+	}
+	codectest.Run(t, cases, func(b []byte) (codectest.S, error) {
+		return DecodeDataChangeNotification(b)
+	})
+}
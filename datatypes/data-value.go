@@ -5,10 +5,12 @@
 package datatypes
 
 import (
+	"bytes"
 	"encoding/binary"
 	"time"
 
-	"github.com/wmnsk/gopcua/utils"
+	"github.com/wmnsk/gopcua/errors"
+	"github.com/wmnsk/gopcua/id"
 )
 
 // DataValue is always preceded by a mask that indicates which fields are present in the stream.
@@ -74,25 +76,37 @@ func DecodeDataValue(b []byte) (*DataValue, error) {
 
 // DecodeFromBytes decodes given bytes into DataValue.
 func (d *DataValue) DecodeFromBytes(b []byte) error {
+	return d.decode(b, 0)
+}
+
+// decode decodes given bytes into DataValue nested in the Variants to depth.
+func (d *DataValue) decode(b []byte, depth int) error {
+	if len(b) < 1 {
+		return errors.NewErrTooShortToDecode(d, "should be longer than 1 byte")
+	}
 	d.EncodingMask = b[0]
 
 	offset := 1
-
 	if d.HasValue() {
 		d.Value = &Variant{}
-		if err := d.Value.DecodeFromBytes(b[offset:]); err != nil {
+		if err := d.Value.decode(b[offset:], depth); err != nil {
 			return err
 		}
 		offset += d.Value.Len()
 	}
 
+	// the fields not indicated by EncodingMask are not in the stream.
+	if len(b) < offset+d.fieldsLen() {
+		return errors.NewErrTooShortToDecode(d, "should contain all the fields in EncodingMask")
+	}
+
 	if d.HasStatus() {
 		d.Status = binary.LittleEndian.Uint32(b[offset : offset+4])
 		offset += 4
 	}
 
 	if d.HasSourceTimestamp() {
-		d.SourceTimestamp = utils.DecodeTimestamp(b[offset : offset+8])
+		d.SourceTimestamp = decodeDateTime(b[offset : offset+8])
 		offset += 8
 	}
 
@@ -102,13 +116,12 @@ func (d *DataValue) DecodeFromBytes(b []byte) error {
 	}
 
 	if d.HasServerTimestamp() {
-		d.ServerTimestamp = utils.DecodeTimestamp(b[offset : offset+8])
+		d.ServerTimestamp = decodeDateTime(b[offset : offset+8])
 		offset += 8
 	}
 
 	if d.HasServerPicoSeconds() {
 		d.ServerPicoSeconds = binary.LittleEndian.Uint16(b[offset : offset+2])
-		offset += 2
 	}
 
 	return nil
@@ -129,7 +142,7 @@ func (d *DataValue) SerializeTo(b []byte) error {
 	b[0] = d.EncodingMask
 
 	offset := 1
-	if d.HasValue() {
+	if d.HasValue() && d.Value != nil {
 		if err := d.Value.SerializeTo(b[offset:]); err != nil {
 			return err
 		}
@@ -142,7 +155,7 @@ func (d *DataValue) SerializeTo(b []byte) error {
 	}
 
 	if d.HasSourceTimestamp() {
-		utils.EncodeTimestamp(b[offset:offset+8], d.SourceTimestamp)
+		encodeDateTime(b[offset:offset+8], d.SourceTimestamp)
 		offset += 8
 	}
 
@@ -152,7 +165,7 @@ func (d *DataValue) SerializeTo(b []byte) error {
 	}
 
 	if d.HasServerTimestamp() {
-		utils.EncodeTimestamp(b[offset:offset+8], d.ServerTimestamp)
+		encodeDateTime(b[offset:offset+8], d.ServerTimestamp)
 		offset += 8
 	}
 
@@ -166,14 +179,17 @@ func (d *DataValue) SerializeTo(b []byte) error {
 
 // Len returns the actual length of DataValue in int.
 func (d *DataValue) Len() int {
-	length := 1
-
-	if d.HasValue() {
-		if d.Value != nil {
-			length += d.Value.Len()
-		}
+	length := 1 + d.fieldsLen()
+	if d.HasValue() && d.Value != nil {
+		length += d.Value.Len()
 	}
 
+	return length
+}
+
+// fieldsLen returns the length of the fields indicated by EncodingMask except Value.
+func (d *DataValue) fieldsLen() int {
+	var length int
 	if d.HasStatus() {
 		length += 4
 	}
@@ -197,6 +213,11 @@ func (d *DataValue) Len() int {
 	return length
 }
 
+// DataType returns the data type id.
+func (d *DataValue) DataType() uint16 {
+	return id.DataValue
+}
+
 // HasValue checks if DataValue has Value or not.
 func (d *DataValue) HasValue() bool {
 	return d.EncodingMask&0x1 == 1
@@ -257,6 +278,130 @@ func (d *DataValue) SetServerPicoSecondsFlag() {
 	d.EncodingMask |= 0x20
 }
 
+// SetValue sets the Value of DataValue and its flag in EncodingMask.
+func (d *DataValue) SetValue(v *Variant) {
+	d.Value = v
+	d.SetValueFlag()
+}
+
+// SetStatus sets the Status of DataValue and its flag in EncodingMask.
+func (d *DataValue) SetStatus(code uint32) {
+	d.Status = code
+	d.SetStatusFlag()
+}
+
+// SetSourceTimestamp sets the SourceTimestamp and SourcePicoSeconds of DataValue
+// and their flags in EncodingMask. SourcePicoSeconds is omitted if ps is 0.
+func (d *DataValue) SetSourceTimestamp(t time.Time, ps uint16) {
+	d.SourceTimestamp, d.SourcePicoSeconds = t, ps
+	d.SetSourceTimestampFlag()
+	if ps != 0 {
+		d.SetSourcePicoSecondsFlag()
+	}
+}
+
+// SetServerTimestamp sets the ServerTimestamp and ServerPicoSeconds of DataValue
+// and their flags in EncodingMask. ServerPicoSeconds is omitted if ps is 0.
+func (d *DataValue) SetServerTimestamp(t time.Time, ps uint16) {
+	d.ServerTimestamp, d.ServerPicoSeconds = t, ps
+	d.SetServerTimestampFlag()
+	if ps != 0 {
+		d.SetServerPicoSecondsFlag()
+	}
+}
+
+// StatusCode returns the Status of DataValue, which is Good(=0) if it is omitted.
+func (d *DataValue) StatusCode() uint32 {
+	if !d.HasStatus() {
+		return 0
+	}
+	return d.Status
+}
+
+// SourceTime returns the SourceTimestamp of DataValue with SourcePicoSeconds added,
+// or the zero time.Time if it is omitted.
+//
+// Note that time.Time has the resolution of nanoseconds, which truncates the picoseconds.
+func (d *DataValue) SourceTime() time.Time {
+	if !d.HasSourceTimestamp() {
+		return time.Time{}
+	}
+	if !d.HasSourcePicoSeconds() {
+		return d.SourceTimestamp
+	}
+	return d.SourceTimestamp.Add(picoSeconds(d.SourcePicoSeconds))
+}
+
+// ServerTime returns the ServerTimestamp of DataValue with ServerPicoSeconds added,
+// or the zero time.Time if it is omitted.
+//
+// Note that time.Time has the resolution of nanoseconds, which truncates the picoseconds.
+func (d *DataValue) ServerTime() time.Time {
+	if !d.HasServerTimestamp() {
+		return time.Time{}
+	}
+	if !d.HasServerPicoSeconds() {
+		return d.ServerTimestamp
+	}
+	return d.ServerTimestamp.Add(picoSeconds(d.ServerPicoSeconds))
+}
+
+// EqualValue returns true if both DataValues have the same Value and StatusCode.
+// The timestamps are ignored, so that a value only refreshed by the server is not
+// treated as changed, e.g. to deduplicate the notifications.
+//
+// The Values are compared by their encoding, and an omitted Status is Good.
+func (d *DataValue) EqualValue(other *DataValue) bool {
+	if d == nil || other == nil {
+		return d == other
+	}
+	if d.StatusCode() != other.StatusCode() {
+		return false
+	}
+	return equalVariant(d.value(), other.value())
+}
+
+// Equal returns true if both DataValues have the same Value, StatusCode and
+// timestamps. An omitted timestamp is the zero time.Time.
+func (d *DataValue) Equal(other *DataValue) bool {
+	if d == nil || other == nil {
+		return d == other
+	}
+	return d.EqualValue(other) &&
+		d.SourceTime().Equal(other.SourceTime()) &&
+		d.ServerTime().Equal(other.ServerTime())
+}
+
+// value returns the Value of DataValue, or nil if it is omitted.
+func (d *DataValue) value() *Variant {
+	if !d.HasValue() {
+		return nil
+	}
+	return d.Value
+}
+
+// equalVariant returns true if both Variants are nil or have the same encoding.
+func equalVariant(v, w *Variant) bool {
+	if v == nil || w == nil {
+		return v == w
+	}
+	vb, err := v.Serialize()
+	if err != nil {
+		return false
+	}
+	wb, err := w.Serialize()
+	if err != nil {
+		return false
+	}
+	return bytes.Equal(vb, wb)
+}
+
+// picoSeconds converts the PicoSeconds in DataValue, which is the number of
+// 10 picoseconds intervals, into time.Duration.
+func picoSeconds(ps uint16) time.Duration {
+	return time.Duration(ps) * 10 / 1000
+}
+
 // DataValueArray represents the DataValueArray.
 type DataValueArray struct {
 	ArraySize  int32
@@ -294,6 +439,9 @@ func DecodeDataValueArray(b []byte) (*DataValueArray, error) {
 
 // DecodeFromBytes decodes given bytes into DataValueArray.
 func (d *DataValueArray) DecodeFromBytes(b []byte) error {
+	if len(b) < 4 {
+		return errors.NewErrTooShortToDecode(d, "should be longer than 4 bytes")
+	}
 	d.ArraySize = int32(binary.LittleEndian.Uint32(b[:4]))
 	if d.ArraySize <= 0 {
 		return nil
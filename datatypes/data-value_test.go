@@ -8,6 +8,8 @@ import (
 	"testing"
 	"time"
 
+	"github.com/google/go-cmp/cmp"
+
 	"github.com/wmnsk/gopcua/utils/codectest"
 )
 
@@ -35,12 +37,200 @@ func TestDataValue(t *testing.T) {
 				0xe8, 0xb3, 0x92, 0x4e, 0xd4, 0x01,
 			},
 		},
+		{
+			Name:   "null",
+			Struct: &DataValue{},
+			Bytes:  []byte{0x00},
+		},
+		{
+			Name:   "status only",
+			Struct: &DataValue{EncodingMask: 0x02, Status: 0x80340000},
+			Bytes:  []byte{0x02, 0x00, 0x00, 0x34, 0x80},
+		},
+		{
+			Name: "source timestamp and picoseconds",
+			Struct: &DataValue{
+				EncodingMask:      0x14,
+				SourceTimestamp:   time.Date(2018, time.August, 10, 23, 0, 0, 0, time.UTC),
+				SourcePicoSeconds: 9999,
+			},
+			Bytes: []byte{
+				0x14,
+				// SourceTimestamp
+				0x00, 0x98, 0x67, 0xdd, 0xfd, 0x30, 0xd4, 0x01,
+				// SourcePicoSeconds
+				0x0f, 0x27,
+			},
+		},
+		{
+			Name: "server timestamp and picoseconds",
+			Struct: &DataValue{
+				EncodingMask:      0x28,
+				ServerTimestamp:   time.Date(2018, time.August, 10, 23, 0, 0, 0, time.UTC),
+				ServerPicoSeconds: 1,
+			},
+			Bytes: []byte{
+				0x28,
+				// ServerTimestamp
+				0x00, 0x98, 0x67, 0xdd, 0xfd, 0x30, 0xd4, 0x01,
+				// ServerPicoSeconds
+				0x01, 0x00,
+			},
+		},
+		{
+			Name: "all fields",
+			Struct: NewDataValue(
+				true, true, true, true, true, true,
+				NewVariant(NewFloat(2.50025)), 0x80340000,
+				time.Date(2018, time.August, 10, 23, 0, 0, 0, time.UTC), 100,
+				time.Date(2018, time.August, 10, 23, 0, 0, 0, time.UTC), 200,
+			),
+			Bytes: []byte{
+				0x3f,
+				// Value
+				0x0a, 0x19, 0x04, 0x20, 0x40,
+				// Status
+				0x00, 0x00, 0x34, 0x80,
+				// SourceTimestamp
+				0x00, 0x98, 0x67, 0xdd, 0xfd, 0x30, 0xd4, 0x01,
+				// SourcePicoSeconds
+				0x64, 0x00,
+				// ServerTimestamp
+				0x00, 0x98, 0x67, 0xdd, 0xfd, 0x30, 0xd4, 0x01,
+				// ServerPicoSeconds
+				0xc8, 0x00,
+			},
+		},
 	}
 	codectest.Run(t, cases, func(b []byte) (codectest.S, error) {
 		return DecodeDataValue(b)
 	})
 }
 
+func TestDataValueEncodingMask(t *testing.T) {
+	ts := time.Date(2018, time.August, 10, 23, 0, 0, 100, time.UTC)
+
+	// each field should be encoded and decoded only when its bit is set in EncodingMask.
+	for mask := byte(0); mask <= 0x3f; mask++ {
+		d := &DataValue{EncodingMask: mask}
+		want := 1
+		if d.HasValue() {
+			d.Value = NewVariant(NewFloat(2.50025))
+			want += 5
+		}
+		if d.HasStatus() {
+			d.Status = 0x80340000
+			want += 4
+		}
+		if d.HasSourceTimestamp() {
+			d.SourceTimestamp = ts
+			want += 8
+		}
+		if d.HasSourcePicoSeconds() {
+			d.SourcePicoSeconds = 100
+			want += 2
+		}
+		if d.HasServerTimestamp() {
+			d.ServerTimestamp = ts.Add(time.Second)
+			want += 8
+		}
+		if d.HasServerPicoSeconds() {
+			d.ServerPicoSeconds = 200
+			want += 2
+		}
+
+		b, err := d.Serialize()
+		if err != nil {
+			t.Fatalf("mask 0x%02x: %s", mask, err)
+		}
+		if len(b) != want {
+			t.Errorf("mask 0x%02x: got %d bytes want %d", mask, len(b), want)
+		}
+
+		got, err := DecodeDataValue(b)
+		if err != nil {
+			t.Fatalf("mask 0x%02x: %s", mask, err)
+		}
+		if diff := cmp.Diff(got, d); diff != "" {
+			t.Errorf("mask 0x%02x: %s", mask, diff)
+		}
+
+		if _, err := DecodeDataValue(b[:len(b)-1]); len(b) > 1 && err == nil {
+			t.Errorf("mask 0x%02x: expected error for truncated bytes", mask)
+		}
+	}
+}
+
+func TestDataValueAccessors(t *testing.T) {
+	ts := time.Date(2018, time.August, 10, 23, 0, 0, 0, time.UTC)
+
+	d := &DataValue{}
+	if got := d.StatusCode(); got != 0 {
+		t.Errorf("StatusCode: got 0x%08x want 0 for omitted Status", got)
+	}
+	if got := d.SourceTime(); !got.IsZero() {
+		t.Errorf("SourceTime: got %v want zero for omitted SourceTimestamp", got)
+	}
+	if got := d.ServerTime(); !got.IsZero() {
+		t.Errorf("ServerTime: got %v want zero for omitted ServerTimestamp", got)
+	}
+
+	d.SetValue(NewVariant(NewFloat(2.50025)))
+	d.SetStatus(0x80340000)
+	d.SetSourceTimestamp(ts, 9999)
+	d.SetServerTimestamp(ts, 0)
+	if got, want := d.EncodingMask, byte(0x1f); got != want {
+		t.Errorf("EncodingMask: got 0x%02x want 0x%02x", got, want)
+	}
+	if got, want := d.StatusCode(), uint32(0x80340000); got != want {
+		t.Errorf("StatusCode: got 0x%08x want 0x%08x", got, want)
+	}
+	// 9999 * 10 picoseconds are truncated to 99 nanoseconds.
+	if got, want := d.SourceTime(), ts.Add(99*time.Nanosecond); !got.Equal(want) {
+		t.Errorf("SourceTime: got %v want %v", got, want)
+	}
+	if got := d.ServerTime(); !got.Equal(ts) {
+		t.Errorf("ServerTime: got %v want %v", got, ts)
+	}
+}
+
+func TestDataValueEqual(t *testing.T) {
+	ts := time.Date(2018, time.September, 17, 14, 28, 29, 112000000, time.UTC)
+	newDataValue := func(v float32, status uint32, ts time.Time) *DataValue {
+		d := &DataValue{}
+		d.SetValue(NewVariant(NewFloat(v)))
+		d.SetStatus(status)
+		d.SetSourceTimestamp(ts, 0)
+		d.SetServerTimestamp(ts, 0)
+		return d
+	}
+
+	cases := []struct {
+		name       string
+		a, b       *DataValue
+		equalValue bool
+		equal      bool
+	}{
+		{"same", newDataValue(2.5, 0, ts), newDataValue(2.5, 0, ts), true, true},
+		{"timestamp", newDataValue(2.5, 0, ts), newDataValue(2.5, 0, ts.Add(time.Second)), true, false},
+		{"value", newDataValue(2.5, 0, ts), newDataValue(2.6, 0, ts), false, false},
+		{"status", newDataValue(2.5, 0, ts), newDataValue(2.5, 0x80340000, ts), false, false},
+		{"omitted-status", newDataValue(2.5, 0, ts), &DataValue{EncodingMask: 0x0d, Value: NewVariant(NewFloat(2.5)), SourceTimestamp: ts, ServerTimestamp: ts}, true, true},
+		{"omitted-value", newDataValue(2.5, 0, ts), &DataValue{EncodingMask: 0x0e, SourceTimestamp: ts, ServerTimestamp: ts}, false, false},
+		{"nil", newDataValue(2.5, 0, ts), nil, false, false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := c.a.EqualValue(c.b); got != c.equalValue {
+				t.Errorf("EqualValue: got %v want %v", got, c.equalValue)
+			}
+			if got := c.a.Equal(c.b); got != c.equal {
+				t.Errorf("Equal: got %v want %v", got, c.equal)
+			}
+		})
+	}
+}
+
 func TestDataValueArray(t *testing.T) {
 	cases := []codectest.Case{
 		{
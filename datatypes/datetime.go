@@ -0,0 +1,134 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package datatypes
+
+import (
+	"encoding/binary"
+	"math"
+	"time"
+
+	"github.com/wmnsk/gopcua/errors"
+	"github.com/wmnsk/gopcua/id"
+)
+
+// epochDelta is the number of 100 nanosecond intervals from January 1, 1601 to January 1, 1970.
+const epochDelta = 116444736000000000
+
+// maxDateTimeTicks is MaxDateTime in 100 nanosecond intervals since January 1, 1601.
+const maxDateTimeTicks = 253402300799*10000000 + epochDelta
+
+// MaxDateTime is the latest time which can be represented by DateTime.
+// It is encoded as the maximum value of Int64, which means the infinite time.
+var MaxDateTime = time.Date(9999, time.December, 31, 23, 59, 59, 0, time.UTC)
+
+// ToDateTime converts t into the number of 100 nanosecond intervals since January 1, 1601 (UTC).
+//
+// The zero time.Time and the times before January 1, 1601 are converted into 0, which means
+// the time is not specified, and the times at or after MaxDateTime into the maximum value of Int64.
+func ToDateTime(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	if !t.Before(MaxDateTime) {
+		return math.MaxInt64
+	}
+
+	// the seconds are converted separately, as UnixNano overflows for the times far from 1970.
+	t = t.UTC()
+	ticks := t.Unix()*10000000 + int64(t.Nanosecond()/100) + epochDelta
+	if ticks < 0 {
+		return 0
+	}
+	return ticks
+}
+
+// FromDateTime converts the number of 100 nanosecond intervals since January 1, 1601 (UTC)
+// into time.Time.
+//
+// The ticks 0 or less are converted into the zero time.Time, and the maximum value of Int64
+// or the ticks after MaxDateTime into MaxDateTime.
+func FromDateTime(ticks int64) time.Time {
+	if ticks <= 0 {
+		return time.Time{}
+	}
+	if ticks >= maxDateTimeTicks {
+		return MaxDateTime
+	}
+
+	ticks -= epochDelta
+	return time.Unix(ticks/10000000, ticks%10000000*100).UTC()
+}
+
+// encodeDateTime serializes t into the first 8 bytes of b as DateTime.
+func encodeDateTime(b []byte, t time.Time) {
+	binary.LittleEndian.PutUint64(b, uint64(ToDateTime(t)))
+}
+
+// decodeDateTime decodes the first 8 bytes of b as DateTime.
+func decodeDateTime(b []byte) time.Time {
+	return FromDateTime(int64(binary.LittleEndian.Uint64(b[:8])))
+}
+
+// DateTime is encoded as the number of 100 nanosecond intervals since January 1, 1601 (UTC).
+//
+// The zero time.Time is encoded as 0, which means the value is not specified.
+// See ToDateTime and FromDateTime for the other special values.
+//
+// Specification: Part 6, 5.2.2.5
+type DateTime struct {
+	Value time.Time
+}
+
+// NewDateTime creates a new DateTime.
+func NewDateTime(t time.Time) *DateTime {
+	return &DateTime{
+		Value: t,
+	}
+}
+
+// DecodeDateTime decodes given bytes into DateTime.
+func DecodeDateTime(b []byte) (*DateTime, error) {
+	d := &DateTime{}
+	if err := d.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// DecodeFromBytes decodes given bytes into OPC UA DateTime.
+func (d *DateTime) DecodeFromBytes(b []byte) error {
+	if len(b) < 8 {
+		return errors.NewErrTooShortToDecode(d, "should be longer than 8 bytes")
+	}
+	d.Value = decodeDateTime(b)
+	return nil
+}
+
+// Serialize serializes DateTime into bytes.
+func (d *DateTime) Serialize() ([]byte, error) {
+	b := make([]byte, d.Len())
+	if err := d.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes DateTime into bytes.
+func (d *DateTime) SerializeTo(b []byte) error {
+	encodeDateTime(b, d.Value)
+	return nil
+}
+
+// Len returns the actual length of DateTime in int.
+func (d *DateTime) Len() int {
+	return 8
+}
+
+// DataType returns type of Data.
+func (d *DateTime) DataType() uint16 {
+	return id.DateTime
+}
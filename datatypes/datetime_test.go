@@ -0,0 +1,101 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package datatypes
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/wmnsk/gopcua/utils/codectest"
+)
+
+func TestDateTime(t *testing.T) {
+	cases := []codectest.Case{
+		{
+			Name:   "Normal",
+			Struct: NewDateTime(time.Date(2018, time.August, 10, 23, 0, 0, 0, time.UTC)),
+			Bytes:  []byte{0x00, 0x98, 0x67, 0xdd, 0xfd, 0x30, 0xd4, 0x01},
+		},
+		{
+			Name:   "Zero",
+			Struct: NewDateTime(time.Time{}),
+			Bytes:  []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+		},
+		{
+			Name:   "Max",
+			Struct: NewDateTime(MaxDateTime),
+			Bytes:  []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x7f},
+		},
+	}
+	codectest.Run(t, cases, func(b []byte) (codectest.S, error) {
+		return DecodeDateTime(b)
+	})
+}
+
+func TestToDateTime(t *testing.T) {
+	cases := []struct {
+		name  string
+		t     time.Time
+		ticks int64
+	}{
+		{"known", time.Date(2018, time.August, 10, 23, 0, 0, 0, time.UTC), 131784156000000000},
+		{"non-utc", time.Date(2018, time.August, 11, 8, 0, 0, 0, time.FixedZone("JST", 9*3600)), 131784156000000000},
+		{"zero", time.Time{}, 0},
+		{"epoch", time.Date(1601, time.January, 1, 0, 0, 0, 0, time.UTC), 0},
+		{"before-1601", time.Date(1600, time.December, 31, 0, 0, 0, 0, time.UTC), 0},
+		{"max", MaxDateTime, math.MaxInt64},
+		{"after-max", time.Date(10000, time.January, 1, 0, 0, 0, 0, time.UTC), math.MaxInt64},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := ToDateTime(c.t); got != c.ticks {
+				t.Errorf("got %d want %d", got, c.ticks)
+			}
+		})
+	}
+}
+
+func TestFromDateTime(t *testing.T) {
+	cases := []struct {
+		name  string
+		ticks int64
+		t     time.Time
+	}{
+		{"known", 131784156000000000, time.Date(2018, time.August, 10, 23, 0, 0, 0, time.UTC)},
+		{"zero", 0, time.Time{}},
+		{"negative", -1, time.Time{}},
+		{"max", math.MaxInt64, MaxDateTime},
+		{"after-max", maxDateTimeTicks + 1, MaxDateTime},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := FromDateTime(c.ticks); !got.Equal(c.t) {
+				t.Errorf("got %v want %v", got, c.t)
+			}
+		})
+	}
+}
+
+func TestDateTimeRoundTrip(t *testing.T) {
+	cases := []struct {
+		name string
+		t    time.Time
+	}{
+		{"zero", time.Time{}},
+		{"before-unix-epoch", time.Date(1900, time.March, 1, 12, 30, 0, 123456700, time.UTC)},
+		{"after-unixnano-range", time.Date(2500, time.December, 31, 23, 59, 59, 999999900, time.UTC)},
+		{"100ns", time.Date(2018, time.August, 10, 23, 0, 0, 100, time.UTC)},
+		{"before-max", MaxDateTime.Add(-100 * time.Nanosecond)},
+		{"max", MaxDateTime},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := FromDateTime(ToDateTime(c.t)); !got.Equal(c.t) {
+				t.Errorf("got %v want %v", got, c.t)
+			}
+		})
+	}
+}
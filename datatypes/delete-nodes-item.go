@@ -0,0 +1,185 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package datatypes
+
+import (
+	"encoding/binary"
+
+	"github.com/wmnsk/gopcua/errors"
+)
+
+// DeleteNodesItem is a Node to be deleted by DeleteNodes.
+//
+// If DeleteTargetReferences is true, the References which have the Node as the
+// target are also deleted. The References from the Node are always deleted.
+//
+// Specification: Part 4, 5.7.4.2
+type DeleteNodesItem struct {
+	NodeID                 *NodeID
+	DeleteTargetReferences *Boolean
+}
+
+// NewDeleteNodesItem creates a new DeleteNodesItem.
+func NewDeleteNodesItem(node *NodeID, deleteTargetRefs bool) *DeleteNodesItem {
+	return &DeleteNodesItem{
+		NodeID:                 node,
+		DeleteTargetReferences: NewBoolean(deleteTargetRefs),
+	}
+}
+
+// DecodeDeleteNodesItem decodes given bytes into DeleteNodesItem.
+func DecodeDeleteNodesItem(b []byte) (*DeleteNodesItem, error) {
+	d := &DeleteNodesItem{}
+	if err := d.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// DecodeFromBytes decodes given bytes into DeleteNodesItem.
+func (d *DeleteNodesItem) DecodeFromBytes(b []byte) error {
+	d.NodeID = &NodeID{}
+	if err := d.NodeID.DecodeFromBytes(b); err != nil {
+		return err
+	}
+	offset := d.NodeID.Len()
+
+	if len(b[offset:]) < 1 {
+		return errors.NewErrTooShortToDecode(d, "should contain DeleteTargetReferences")
+	}
+	d.DeleteTargetReferences = &Boolean{}
+	return d.DeleteTargetReferences.DecodeFromBytes(b[offset:])
+}
+
+// Serialize serializes DeleteNodesItem into bytes.
+func (d *DeleteNodesItem) Serialize() ([]byte, error) {
+	b := make([]byte, d.Len())
+	if err := d.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes DeleteNodesItem into bytes.
+func (d *DeleteNodesItem) SerializeTo(b []byte) error {
+	offset := 0
+	if d.NodeID != nil {
+		if err := d.NodeID.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += d.NodeID.Len()
+	}
+
+	if d.DeleteTargetReferences != nil {
+		return d.DeleteTargetReferences.SerializeTo(b[offset:])
+	}
+
+	return nil
+}
+
+// Len returns the actual length of DeleteNodesItem in int.
+func (d *DeleteNodesItem) Len() int {
+	l := 0
+	if d.NodeID != nil {
+		l += d.NodeID.Len()
+	}
+	if d.DeleteTargetReferences != nil {
+		l += d.DeleteTargetReferences.Len()
+	}
+
+	return l
+}
+
+// DeleteNodesItemArray represents an array of DeleteNodesItems.
+// It does not correspond to a certain type from the specification
+// but makes encoding and decoding easier.
+type DeleteNodesItemArray struct {
+	ArraySize int32
+	Items     []*DeleteNodesItem
+}
+
+// NewDeleteNodesItemArray creates a new DeleteNodesItemArray from multiple DeleteNodesItems.
+func NewDeleteNodesItemArray(items []*DeleteNodesItem) *DeleteNodesItemArray {
+	if items == nil {
+		return &DeleteNodesItemArray{
+			ArraySize: 0,
+		}
+	}
+
+	return &DeleteNodesItemArray{
+		ArraySize: int32(len(items)),
+		Items:     items,
+	}
+}
+
+// DecodeDeleteNodesItemArray decodes given bytes into DeleteNodesItemArray.
+func DecodeDeleteNodesItemArray(b []byte) (*DeleteNodesItemArray, error) {
+	a := &DeleteNodesItemArray{}
+	if err := a.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// DecodeFromBytes decodes given bytes into DeleteNodesItemArray.
+func (a *DeleteNodesItemArray) DecodeFromBytes(b []byte) error {
+	if len(b) < 4 {
+		return errors.NewErrTooShortToDecode(a, "should be longer than 4 bytes")
+	}
+	a.ArraySize = int32(binary.LittleEndian.Uint32(b[:4]))
+	if a.ArraySize <= 0 {
+		return nil
+	}
+
+	offset := 4
+	for i := 1; i <= int(a.ArraySize); i++ {
+		item, err := DecodeDeleteNodesItem(b[offset:])
+		if err != nil {
+			return err
+		}
+		a.Items = append(a.Items, item)
+		offset += item.Len()
+	}
+
+	return nil
+}
+
+// Serialize serializes DeleteNodesItemArray into bytes.
+func (a *DeleteNodesItemArray) Serialize() ([]byte, error) {
+	b := make([]byte, a.Len())
+	if err := a.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes DeleteNodesItemArray into bytes.
+func (a *DeleteNodesItemArray) SerializeTo(b []byte) error {
+	offset := 4
+	binary.LittleEndian.PutUint32(b[:4], uint32(a.ArraySize))
+
+	for _, item := range a.Items {
+		if err := item.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += item.Len()
+	}
+
+	return nil
+}
+
+// Len returns the actual length in int.
+func (a *DeleteNodesItemArray) Len() int {
+	l := 4
+	for _, item := range a.Items {
+		l += item.Len()
+	}
+
+	return l
+}
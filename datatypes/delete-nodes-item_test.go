@@ -0,0 +1,51 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package datatypes
+
+import (
+	"testing"
+
+	"github.com/wmnsk/gopcua/utils/codectest"
+)
+
+func TestDeleteNodesItem(t *testing.T) {
+	cases := []codectest.Case{
+		{
+			Name:   "normal",
+			Struct: NewDeleteNodesItem(NewStringNodeID(2, "foo"), true),
+			Bytes: []byte{
+				// NodeID
+				0x03, 0x02, 0x00, 0x03, 0x00, 0x00, 0x00, 0x66, 0x6f, 0x6f,
+				// DeleteTargetReferences
+				0x01,
+			},
+		},
+	}
+	codectest.Run(t, cases, func(b []byte) (codectest.S, error) {
+		return DecodeDeleteNodesItem(b)
+	})
+}
+
+func TestDeleteNodesItemArray(t *testing.T) {
+	cases := []codectest.Case{
+		{
+			Name: "normal",
+			Struct: NewDeleteNodesItemArray([]*DeleteNodesItem{
+				NewDeleteNodesItem(NewFourByteNodeID(2, 1000), false),
+				NewDeleteNodesItem(NewStringNodeID(2, "foo"), true),
+			}),
+			Bytes: []byte{
+				// ArraySize
+				0x02, 0x00, 0x00, 0x00,
+				// DeleteNodesItems
+				0x01, 0x02, 0xe8, 0x03, 0x00,
+				0x03, 0x02, 0x00, 0x03, 0x00, 0x00, 0x00, 0x66, 0x6f, 0x6f, 0x01,
+			},
+		},
+	}
+	codectest.Run(t, cases, func(b []byte) (codectest.S, error) {
+		return DecodeDeleteNodesItemArray(b)
+	})
+}
@@ -0,0 +1,198 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package datatypes
+
+import (
+	"encoding/binary"
+
+	"github.com/wmnsk/gopcua/errors"
+)
+
+// DeleteReferencesItem is a Reference to be deleted by DeleteReferences.
+//
+// If DeleteBidirectional is true, the Reference in the opposite direction is
+// also deleted if the server has it.
+//
+// Specification: Part 4, 5.7.5.2
+type DeleteReferencesItem struct {
+	SourceNodeID        *NodeID
+	ReferenceTypeID     *NodeID
+	IsForward           *Boolean
+	TargetNodeID        *ExpandedNodeID
+	DeleteBidirectional *Boolean
+}
+
+// NewDeleteReferencesItem creates a new DeleteReferencesItem.
+func NewDeleteReferencesItem(source, refType *NodeID, isForward bool, target *ExpandedNodeID, bidirectional bool) *DeleteReferencesItem {
+	return &DeleteReferencesItem{
+		SourceNodeID:        source,
+		ReferenceTypeID:     refType,
+		IsForward:           NewBoolean(isForward),
+		TargetNodeID:        target,
+		DeleteBidirectional: NewBoolean(bidirectional),
+	}
+}
+
+// DecodeDeleteReferencesItem decodes given bytes into DeleteReferencesItem.
+func DecodeDeleteReferencesItem(b []byte) (*DeleteReferencesItem, error) {
+	d := &DeleteReferencesItem{}
+	if err := d.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// DecodeFromBytes decodes given bytes into DeleteReferencesItem.
+func (d *DeleteReferencesItem) DecodeFromBytes(b []byte) error {
+	dec := Decoder{b: b}
+	d.SourceNodeID = &NodeID{}
+	if err := dec.ReadData(d.SourceNodeID); err != nil {
+		return err
+	}
+	d.ReferenceTypeID = &NodeID{}
+	if err := dec.ReadData(d.ReferenceTypeID); err != nil {
+		return err
+	}
+	if len(dec.Remaining()) < 1 {
+		return errors.NewErrTooShortToDecode(d, "should contain IsForward")
+	}
+	d.IsForward = &Boolean{}
+	if err := dec.ReadData(d.IsForward); err != nil {
+		return err
+	}
+	d.TargetNodeID = &ExpandedNodeID{}
+	if err := dec.ReadData(d.TargetNodeID); err != nil {
+		return err
+	}
+	if len(dec.Remaining()) < 1 {
+		return errors.NewErrTooShortToDecode(d, "should contain DeleteBidirectional")
+	}
+	d.DeleteBidirectional = &Boolean{}
+	return dec.ReadData(d.DeleteBidirectional)
+}
+
+// Serialize serializes DeleteReferencesItem into bytes.
+func (d *DeleteReferencesItem) Serialize() ([]byte, error) {
+	b := make([]byte, d.Len())
+	if err := d.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes DeleteReferencesItem into bytes.
+func (d *DeleteReferencesItem) SerializeTo(b []byte) error {
+	if len(b) < d.Len() {
+		return errors.NewErrInvalidLength(d, "bytes should be longer")
+	}
+
+	// b is long enough that enc never reallocates.
+	enc := Encoder{buf: b[:0]}
+	for _, v := range []Encodable{d.SourceNodeID, d.ReferenceTypeID, d.IsForward, d.TargetNodeID, d.DeleteBidirectional} {
+		if err := enc.WriteData(v); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Len returns the actual length of DeleteReferencesItem in int.
+func (d *DeleteReferencesItem) Len() int {
+	return d.SourceNodeID.Len() + d.ReferenceTypeID.Len() + d.IsForward.Len() +
+		d.TargetNodeID.Len() + d.DeleteBidirectional.Len()
+}
+
+// DeleteReferencesItemArray represents an array of DeleteReferencesItems.
+// It does not correspond to a certain type from the specification
+// but makes encoding and decoding easier.
+type DeleteReferencesItemArray struct {
+	ArraySize int32
+	Items     []*DeleteReferencesItem
+}
+
+// NewDeleteReferencesItemArray creates a new DeleteReferencesItemArray from multiple DeleteReferencesItems.
+func NewDeleteReferencesItemArray(items []*DeleteReferencesItem) *DeleteReferencesItemArray {
+	if items == nil {
+		return &DeleteReferencesItemArray{
+			ArraySize: 0,
+		}
+	}
+
+	return &DeleteReferencesItemArray{
+		ArraySize: int32(len(items)),
+		Items:     items,
+	}
+}
+
+// DecodeDeleteReferencesItemArray decodes given bytes into DeleteReferencesItemArray.
+func DecodeDeleteReferencesItemArray(b []byte) (*DeleteReferencesItemArray, error) {
+	a := &DeleteReferencesItemArray{}
+	if err := a.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// DecodeFromBytes decodes given bytes into DeleteReferencesItemArray.
+func (a *DeleteReferencesItemArray) DecodeFromBytes(b []byte) error {
+	if len(b) < 4 {
+		return errors.NewErrTooShortToDecode(a, "should be longer than 4 bytes")
+	}
+	a.ArraySize = int32(binary.LittleEndian.Uint32(b[:4]))
+	if a.ArraySize <= 0 {
+		return nil
+	}
+
+	offset := 4
+	for i := 1; i <= int(a.ArraySize); i++ {
+		item, err := DecodeDeleteReferencesItem(b[offset:])
+		if err != nil {
+			return err
+		}
+		a.Items = append(a.Items, item)
+		offset += item.Len()
+	}
+
+	return nil
+}
+
+// Serialize serializes DeleteReferencesItemArray into bytes.
+func (a *DeleteReferencesItemArray) Serialize() ([]byte, error) {
+	b := make([]byte, a.Len())
+	if err := a.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes DeleteReferencesItemArray into bytes.
+func (a *DeleteReferencesItemArray) SerializeTo(b []byte) error {
+	offset := 4
+	binary.LittleEndian.PutUint32(b[:4], uint32(a.ArraySize))
+
+	for _, item := range a.Items {
+		if err := item.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += item.Len()
+	}
+
+	return nil
+}
+
+// Len returns the actual length in int.
+func (a *DeleteReferencesItemArray) Len() int {
+	l := 4
+	for _, item := range a.Items {
+		l += item.Len()
+	}
+
+	return l
+}
@@ -0,0 +1,62 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package datatypes
+
+import (
+	"testing"
+
+	"github.com/wmnsk/gopcua/utils/codectest"
+)
+
+func TestDeleteReferencesItem(t *testing.T) {
+	cases := []codectest.Case{
+		{
+			Name: "normal",
+			Struct: NewDeleteReferencesItem(
+				NewFourByteNodeID(0, 85), NewFourByteNodeID(0, 35), true,
+				NewExpandedNodeIDWithServer(NewFourByteNodeID(2, 1000), 1), true,
+			),
+			Bytes: []byte{
+				// SourceNodeID
+				0x01, 0x00, 0x55, 0x00,
+				// ReferenceTypeID
+				0x01, 0x00, 0x23, 0x00,
+				// IsForward
+				0x01,
+				// TargetNodeID
+				0x41, 0x02, 0xe8, 0x03, 0x01, 0x00, 0x00, 0x00,
+				// DeleteBidirectional
+				0x01,
+			},
+		},
+	}
+	codectest.Run(t, cases, func(b []byte) (codectest.S, error) {
+		return DecodeDeleteReferencesItem(b)
+	})
+}
+
+func TestDeleteReferencesItemArray(t *testing.T) {
+	cases := []codectest.Case{
+		{
+			Name: "normal",
+			Struct: NewDeleteReferencesItemArray([]*DeleteReferencesItem{
+				NewDeleteReferencesItem(
+					NewFourByteNodeID(0, 85), NewFourByteNodeID(0, 35), false,
+					NewFourByteExpandedNodeID(2, 1000), false,
+				),
+			}),
+			Bytes: []byte{
+				// ArraySize
+				0x01, 0x00, 0x00, 0x00,
+				// DeleteReferencesItems
+				0x01, 0x00, 0x55, 0x00, 0x01, 0x00, 0x23, 0x00,
+				0x00, 0x01, 0x02, 0xe8, 0x03, 0x00,
+			},
+		},
+	}
+	codectest.Run(t, cases, func(b []byte) (codectest.S, error) {
+		return DecodeDeleteReferencesItemArray(b)
+	})
+}
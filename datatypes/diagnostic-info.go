@@ -0,0 +1,515 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package datatypes
+
+import (
+	"encoding/binary"
+	"fmt"
+	"strings"
+
+	"github.com/wmnsk/gopcua/errors"
+	"github.com/wmnsk/gopcua/id"
+	"github.com/wmnsk/gopcua/status"
+)
+
+// DiagnosticInfo represents the DiagnosticInfo.
+//
+// Specification: Part 4, 7.8
+type DiagnosticInfo struct {
+	EncodingMask        uint8
+	SymbolicID          int32
+	NamespaceURI        int32
+	Locale              int32
+	LocalizedText       int32
+	AdditionalInfo      *String
+	InnerStatusCode     uint32
+	InnerDiagnosticInfo *DiagnosticInfo
+}
+
+// NewDiagnosticInfo creates a new DiagnosticInfo.
+func NewDiagnosticInfo(hasSymID, hasURI, hasText, hasLocale, hasInfo, hasInnerStatus, hasInnerDiag bool, symID, uri, locale, text int32, info *String, code uint32, diag *DiagnosticInfo) *DiagnosticInfo {
+	d := &DiagnosticInfo{
+		SymbolicID:          symID,
+		NamespaceURI:        uri,
+		Locale:              locale,
+		LocalizedText:       text,
+		AdditionalInfo:      info,
+		InnerStatusCode:     code,
+		InnerDiagnosticInfo: diag,
+	}
+
+	if hasSymID {
+		d.SetSymbolicIDFlag()
+	}
+	if hasURI {
+		d.SetNamespaceURIFlag()
+	}
+	if hasText {
+		d.SetLocalizedTextFlag()
+	}
+	if hasLocale {
+		d.SetLocaleFlag()
+	}
+	if hasInfo {
+		d.SetAdditionalInfoFlag()
+	}
+	if hasInnerStatus {
+		d.SetInnerStatusCodeFlag()
+	}
+	if hasInnerDiag {
+		d.SetInnerDiagnosticInfoFlag()
+	}
+
+	return d
+}
+
+// NewNullDiagnosticInfo creates a DiagnosticInfo without any info.
+func NewNullDiagnosticInfo() *DiagnosticInfo {
+	return NewDiagnosticInfo(
+		false, false, false, false, false, false, false,
+		0, 0, 0, 0, nil, 0, nil,
+	)
+}
+
+// DecodeDiagnosticInfo decodes given bytes into DiagnosticInfo.
+func DecodeDiagnosticInfo(b []byte) (*DiagnosticInfo, error) {
+	d := &DiagnosticInfo{}
+	if err := d.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// DecodeFromBytes decodes given bytes into DiagnosticInfo.
+func (d *DiagnosticInfo) DecodeFromBytes(b []byte) error {
+	return d.decode(b, 0)
+}
+
+// decode decodes given bytes into DiagnosticInfo nested in the Variants or
+// the InnerDiagnosticInfos to depth.
+func (d *DiagnosticInfo) decode(b []byte, depth int) error {
+	if depth > maxNestingDepth {
+		return errors.NewErrUnsupported(d, fmt.Sprintf("should not be nested deeper than %d", maxNestingDepth))
+	}
+	if len(b) < 1 {
+		return errors.NewErrTooShortToDecode(d, "should be longer than 1 byte")
+	}
+	d.EncodingMask = b[0]
+	if len(b) < d.fixedLen() {
+		return errors.NewErrTooShortToDecode(d, "should contain all the fields in EncodingMask")
+	}
+
+	var offset = 1
+	if d.HasSymbolicID() {
+		d.SymbolicID = int32(binary.LittleEndian.Uint32(b[offset : offset+4]))
+		offset += 4
+	}
+	if d.HasNamespaceURI() {
+		d.NamespaceURI = int32(binary.LittleEndian.Uint32(b[offset : offset+4]))
+		offset += 4
+	}
+	if d.HasLocale() {
+		d.Locale = int32(binary.LittleEndian.Uint32(b[offset : offset+4]))
+		offset += 4
+	}
+	if d.HasLocalizedText() {
+		d.LocalizedText = int32(binary.LittleEndian.Uint32(b[offset : offset+4]))
+		offset += 4
+	}
+	if d.HasAdditionalInfo() {
+		d.AdditionalInfo = &String{}
+		if err := d.AdditionalInfo.DecodeFromBytes(b[offset:]); err != nil {
+			return err
+		}
+		offset += d.AdditionalInfo.Len()
+	}
+	if d.HasInnerStatusCode() {
+		if len(b) < offset+4 {
+			return errors.NewErrTooShortToDecode(d, "should contain InnerStatusCode")
+		}
+		d.InnerStatusCode = binary.LittleEndian.Uint32(b[offset : offset+4])
+		offset += 4
+	}
+	if d.HasInnerDiagnosticInfo() {
+		d.InnerDiagnosticInfo = &DiagnosticInfo{}
+		if err := d.InnerDiagnosticInfo.decode(b[offset:], depth+1); err != nil {
+			return err
+		}
+		offset += d.InnerDiagnosticInfo.Len()
+	}
+
+	return nil
+}
+
+// fixedLen returns the length of the EncodingMask and the int32 indices
+// which come before AdditionalInfo.
+func (d *DiagnosticInfo) fixedLen() int {
+	l := 1
+	for _, has := range []bool{d.HasSymbolicID(), d.HasNamespaceURI(), d.HasLocale(), d.HasLocalizedText()} {
+		if has {
+			l += 4
+		}
+	}
+
+	return l
+}
+
+// Serialize serializes DiagnosticInfo into bytes.
+func (d *DiagnosticInfo) Serialize() ([]byte, error) {
+	b := make([]byte, d.Len())
+	if err := d.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes DiagnosticInfo into bytes.
+func (d *DiagnosticInfo) SerializeTo(b []byte) error {
+	var offset = 1
+	b[0] = d.EncodingMask
+	if d.HasSymbolicID() {
+		binary.LittleEndian.PutUint32(b[offset:offset+4], uint32(d.SymbolicID))
+		offset += 4
+	}
+	if d.HasNamespaceURI() {
+		binary.LittleEndian.PutUint32(b[offset:offset+4], uint32(d.NamespaceURI))
+		offset += 4
+	}
+	if d.HasLocale() {
+		binary.LittleEndian.PutUint32(b[offset:offset+4], uint32(d.Locale))
+		offset += 4
+	}
+	if d.HasLocalizedText() {
+		binary.LittleEndian.PutUint32(b[offset:offset+4], uint32(d.LocalizedText))
+		offset += 4
+	}
+	if d.HasAdditionalInfo() {
+		info := d.AdditionalInfo
+		if info == nil {
+			info = NewString("")
+		}
+		if err := info.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += info.Len()
+	}
+	if d.HasInnerStatusCode() {
+		binary.LittleEndian.PutUint32(b[offset:offset+4], uint32(d.InnerStatusCode))
+		offset += 4
+	}
+	if d.HasInnerDiagnosticInfo() {
+		inner := d.InnerDiagnosticInfo
+		if inner == nil {
+			inner = NewNullDiagnosticInfo()
+		}
+		if err := inner.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += inner.Len()
+	}
+
+	return nil
+}
+
+// Len returns the actual length of DiagnosticInfo in int.
+func (d *DiagnosticInfo) Len() int {
+	l := 1
+	if d.HasSymbolicID() {
+		l += 4
+	}
+	if d.HasNamespaceURI() {
+		l += 4
+	}
+	if d.HasLocalizedText() {
+		l += 4
+	}
+	if d.HasLocale() {
+		l += 4
+	}
+	if d.HasAdditionalInfo() {
+		if d.AdditionalInfo != nil {
+			l += d.AdditionalInfo.Len()
+		} else {
+			// null String is encoded.
+			l += 4
+		}
+	}
+	if d.HasInnerStatusCode() {
+		l += 4
+	}
+	if d.HasInnerDiagnosticInfo() {
+		if d.InnerDiagnosticInfo != nil {
+			l += d.InnerDiagnosticInfo.Len()
+		} else {
+			// null DiagnosticInfo is encoded.
+			l++
+		}
+	}
+
+	return l
+}
+
+// HasSymbolicID checks if DiagnosticInfo has SymbolicID or not.
+func (d *DiagnosticInfo) HasSymbolicID() bool {
+	return d.EncodingMask&0x1 == 1
+}
+
+// SetSymbolicIDFlag sets SymbolicIDFlag in EncodingMask in DiagnosticInfo.
+func (d *DiagnosticInfo) SetSymbolicIDFlag() {
+	d.EncodingMask |= 0x1
+}
+
+// HasNamespaceURI checks if DiagnosticInfo has NamespaceURI or not.
+func (d *DiagnosticInfo) HasNamespaceURI() bool {
+	return (d.EncodingMask>>1)&0x1 == 1
+}
+
+// SetNamespaceURIFlag sets NamespaceURIFlag in EncodingMask in DiagnosticInfo.
+func (d *DiagnosticInfo) SetNamespaceURIFlag() {
+	d.EncodingMask |= 0x2
+}
+
+// HasLocalizedText checks if DiagnosticInfo has LocalizedText or not.
+func (d *DiagnosticInfo) HasLocalizedText() bool {
+	return (d.EncodingMask>>2)&0x1 == 1
+}
+
+// SetLocalizedTextFlag sets LocalizedTextFlag in EncodingMask in DiagnosticInfo.
+func (d *DiagnosticInfo) SetLocalizedTextFlag() {
+	d.EncodingMask |= 0x4
+}
+
+// HasLocale checks if DiagnosticInfo has Locale or not.
+func (d *DiagnosticInfo) HasLocale() bool {
+	return (d.EncodingMask>>3)&0x1 == 1
+}
+
+// SetLocaleFlag sets LocaleFlag in EncodingMask in DiagnosticInfo.
+func (d *DiagnosticInfo) SetLocaleFlag() {
+	d.EncodingMask |= 0x8
+}
+
+// HasAdditionalInfo checks if DiagnosticInfo has AdditionalInfo or not.
+func (d *DiagnosticInfo) HasAdditionalInfo() bool {
+	return (d.EncodingMask>>4)&0x1 == 1
+}
+
+// SetAdditionalInfoFlag sets AdditionalInfoFlag in EncodingMask in DiagnosticInfo.
+func (d *DiagnosticInfo) SetAdditionalInfoFlag() {
+	d.EncodingMask |= 0x10
+}
+
+// HasInnerStatusCode checks if DiagnosticInfo has InnerStatusCode or not.
+func (d *DiagnosticInfo) HasInnerStatusCode() bool {
+	return (d.EncodingMask>>5)&0x1 == 1
+}
+
+// SetInnerStatusCodeFlag sets InnerStatusCodeFlag in EncodingMask in DiagnosticInfo.
+func (d *DiagnosticInfo) SetInnerStatusCodeFlag() {
+	d.EncodingMask |= 0x20
+}
+
+// HasInnerDiagnosticInfo checks if DiagnosticInfo has InnerDiagnosticInfo or not.
+func (d *DiagnosticInfo) HasInnerDiagnosticInfo() bool {
+	return (d.EncodingMask>>6)&0x1 == 1
+}
+
+// SetInnerDiagnosticInfoFlag sets InnerDiagnosticInfoFlag in EncodingMask in DiagnosticInfo.
+func (d *DiagnosticInfo) SetInnerDiagnosticInfoFlag() {
+	d.EncodingMask |= 0x40
+}
+
+// String returns DiagnosticInfo in string.
+func (d *DiagnosticInfo) String() string {
+	var str []string
+	str = append(str, fmt.Sprintf("%x", d.EncodingMask))
+	if d.HasSymbolicID() {
+		str = append(str, fmt.Sprintf("%d", d.SymbolicID))
+	}
+	if d.HasNamespaceURI() {
+		str = append(str, fmt.Sprintf("%d", d.NamespaceURI))
+	}
+	if d.HasLocale() {
+		str = append(str, fmt.Sprintf("%d", d.Locale))
+	}
+	if d.HasLocalizedText() {
+		str = append(str, fmt.Sprintf("%d", d.LocalizedText))
+	}
+	if d.HasAdditionalInfo() {
+		str = append(str, fmt.Sprintf("%v", d.AdditionalInfo.Get()))
+	}
+	if d.HasInnerStatusCode() {
+		str = append(str, fmt.Sprintf("%d", d.InnerStatusCode))
+	}
+	if d.HasInnerDiagnosticInfo() {
+		str = append(str, fmt.Sprintf("%v", d.InnerDiagnosticInfo.String()))
+	}
+
+	return fmt.Sprintf("%v", str)
+}
+
+// DataType returns the data type id.
+func (d *DiagnosticInfo) DataType() uint16 {
+	return id.DiagnosticInfo
+}
+
+// Resolve returns the DiagnosticInfo with the indices resolved to the strings in
+// the StringTable given, which is usually the one in the ResponseHeader.
+//
+// The fields which are not present or out of the StringTable are left empty.
+func (d *DiagnosticInfo) Resolve(table []string) *ResolvedDiagnosticInfo {
+	lookup := func(has bool, idx int32) string {
+		if !has || idx < 0 || int(idx) >= len(table) {
+			return ""
+		}
+		return table[idx]
+	}
+
+	r := &ResolvedDiagnosticInfo{
+		SymbolicID:    lookup(d.HasSymbolicID(), d.SymbolicID),
+		NamespaceURI:  lookup(d.HasNamespaceURI(), d.NamespaceURI),
+		Locale:        lookup(d.HasLocale(), d.Locale),
+		LocalizedText: lookup(d.HasLocalizedText(), d.LocalizedText),
+	}
+	if d.HasAdditionalInfo() && d.AdditionalInfo != nil {
+		r.AdditionalInfo = d.AdditionalInfo.Get()
+	}
+	if d.HasInnerStatusCode() {
+		r.InnerStatusCode = d.InnerStatusCode
+	}
+	if d.HasInnerDiagnosticInfo() && d.InnerDiagnosticInfo != nil {
+		r.Inner = d.InnerDiagnosticInfo.Resolve(table)
+	}
+
+	return r
+}
+
+// ResolvedDiagnosticInfo is a DiagnosticInfo of which the indices into the StringTable
+// are replaced with the strings. It is created by DiagnosticInfo.Resolve.
+type ResolvedDiagnosticInfo struct {
+	SymbolicID      string
+	NamespaceURI    string
+	Locale          string
+	LocalizedText   string
+	AdditionalInfo  string
+	InnerStatusCode uint32
+	Inner           *ResolvedDiagnosticInfo
+}
+
+// String returns ResolvedDiagnosticInfo in string, with the inner ones after colons.
+func (r *ResolvedDiagnosticInfo) String() string {
+	var str []string
+	if r.NamespaceURI != "" || r.SymbolicID != "" {
+		str = append(str, r.NamespaceURI+r.SymbolicID)
+	}
+	if r.LocalizedText != "" {
+		str = append(str, fmt.Sprintf("%q", r.LocalizedText))
+	}
+	if r.AdditionalInfo != "" {
+		str = append(str, r.AdditionalInfo)
+	}
+	if r.InnerStatusCode != 0 {
+		str = append(str, fmt.Sprintf("inner status %v", status.StatusCode(r.InnerStatusCode)))
+	}
+	if r.Inner != nil {
+		if inner := r.Inner.String(); inner != "" {
+			str = append(str, inner)
+		}
+	}
+
+	return strings.Join(str, ": ")
+}
+
+// DiagnosticInfoArray represents the DiagnosticInfoArray.
+type DiagnosticInfoArray struct {
+	ArraySize       int32
+	DiagnosticInfos []*DiagnosticInfo
+}
+
+// NewDiagnosticInfoArray creates a new DiagnosticInfoArray from multiple strings.
+func NewDiagnosticInfoArray(diags []*DiagnosticInfo) *DiagnosticInfoArray {
+	if diags == nil {
+		return &DiagnosticInfoArray{
+			ArraySize: 0,
+		}
+	}
+
+	d := &DiagnosticInfoArray{
+		ArraySize: int32(len(diags)),
+	}
+	d.DiagnosticInfos = append(d.DiagnosticInfos, diags...)
+
+	return d
+}
+
+// DecodeDiagnosticInfoArray decodes given bytes into DiagnosticInfoArray.
+func DecodeDiagnosticInfoArray(b []byte) (*DiagnosticInfoArray, error) {
+	d := &DiagnosticInfoArray{}
+	if err := d.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// DecodeFromBytes decodes given bytes into DiagnosticInfoArray.
+func (d *DiagnosticInfoArray) DecodeFromBytes(b []byte) error {
+	if len(b) < 4 {
+		return errors.NewErrTooShortToDecode(d, "should be longer than 4 bytes")
+	}
+	d.ArraySize = int32(binary.LittleEndian.Uint32(b[:4]))
+	if d.ArraySize <= 0 {
+		return nil
+	}
+
+	var offset = 4
+	for i := 1; i <= int(d.ArraySize); i++ {
+		diag, err := DecodeDiagnosticInfo(b[offset:])
+		if err != nil {
+			return err
+		}
+		d.DiagnosticInfos = append(d.DiagnosticInfos, diag)
+		offset += diag.Len()
+	}
+
+	return nil
+}
+
+// Serialize serializes DiagnosticInfoArray into bytes.
+func (d *DiagnosticInfoArray) Serialize() ([]byte, error) {
+	b := make([]byte, d.Len())
+	if err := d.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes DiagnosticInfoArray into bytes.
+func (d *DiagnosticInfoArray) SerializeTo(b []byte) error {
+	var offset = 4
+	binary.LittleEndian.PutUint32(b[:4], uint32(d.ArraySize))
+
+	for _, diag := range d.DiagnosticInfos {
+		if err := diag.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += diag.Len()
+	}
+
+	return nil
+}
+
+// Len returns the actual length in int.
+func (d *DiagnosticInfoArray) Len() int {
+	l := 4
+	for _, diag := range d.DiagnosticInfos {
+		l += diag.Len()
+	}
+
+	return l
+}
@@ -2,12 +2,13 @@
 // Use of this source code is governed by a MIT-style license that can be
 // found in the LICENSE file.
 
-package services
+package datatypes
 
 import (
 	"testing"
 
-	"github.com/wmnsk/gopcua/datatypes"
+	"github.com/google/go-cmp/cmp"
+
 	"github.com/wmnsk/gopcua/utils/codectest"
 )
 
@@ -65,7 +66,7 @@ func TestDiagnosticInfo(t *testing.T) {
 			Struct: NewDiagnosticInfo(
 				false, false, false, false, true, false, false,
 				0, 0, 0, 0,
-				datatypes.NewString("foobar"),
+				NewString("foobar"),
 				0, nil,
 			),
 			Bytes: []byte{
@@ -102,7 +103,7 @@ func TestDiagnosticInfo(t *testing.T) {
 			Struct: NewDiagnosticInfo(
 				true, true, true, true, true, true, true,
 				1, 2, 4, 3,
-				datatypes.NewString("foobar"),
+				NewString("foobar"),
 				6,
 				NewDiagnosticInfo(
 					true, false, false, false, false, false, false,
@@ -131,6 +132,96 @@ func TestDiagnosticInfo(t *testing.T) {
 	codectest.Run(t, cases, func(b []byte) (codectest.S, error) {
 		return DecodeDiagnosticInfo(b)
 	})
+
+	t.Run("truncated", func(t *testing.T) {
+		b := []byte{0x7f, 0x01, 0x00, 0x00, 0x00, 0x02, 0x00}
+		if _, err := DecodeDiagnosticInfo(b); err == nil {
+			t.Error("expected error for truncated DiagnosticInfo, got nil")
+		}
+	})
+}
+
+// newNestedDiagnosticInfo returns a DiagnosticInfo with two levels of InnerDiagnosticInfo,
+// of which the indices point to the StringTable below.
+func newNestedDiagnosticInfo() *DiagnosticInfo {
+	return NewDiagnosticInfo(
+		true, true, true, false, false, true, true,
+		1, 0, 0, 2, nil, 0x80340000,
+		NewDiagnosticInfo(
+			true, true, false, false, true, false, true,
+			3, 0, 0, 0, NewString("foo"), 0,
+			NewDiagnosticInfo(
+				true, false, false, false, false, false, false,
+				4, 0, 0, 0, nil, 0, nil,
+			),
+		),
+	)
+}
+
+var nestedStringTable = []string{"http://example.com/", "BadNodeIdUnknown", "node not found", "Inner", "Innermost"}
+
+func TestNestedDiagnosticInfo(t *testing.T) {
+	cases := []codectest.Case{
+		{
+			Name:   "two levels",
+			Struct: newNestedDiagnosticInfo(),
+			Bytes: []byte{
+				0x67,
+				// SymbolicID
+				0x01, 0x00, 0x00, 0x00,
+				// NamespaceURI
+				0x00, 0x00, 0x00, 0x00,
+				// LocalizedText
+				0x02, 0x00, 0x00, 0x00,
+				// InnerStatusCode
+				0x00, 0x00, 0x34, 0x80,
+				// InnerDiagnosticInfo
+				0x53,
+				// SymbolicID
+				0x03, 0x00, 0x00, 0x00,
+				// NamespaceURI
+				0x00, 0x00, 0x00, 0x00,
+				// AdditionalInfo
+				0x03, 0x00, 0x00, 0x00, 0x66, 0x6f, 0x6f,
+				// InnerDiagnosticInfo
+				0x01, 0x04, 0x00, 0x00, 0x00,
+			},
+		},
+	}
+	codectest.Run(t, cases, func(b []byte) (codectest.S, error) {
+		return DecodeDiagnosticInfo(b)
+	})
+}
+
+func TestDiagnosticInfoResolve(t *testing.T) {
+	got := newNestedDiagnosticInfo().Resolve(nestedStringTable)
+	want := &ResolvedDiagnosticInfo{
+		SymbolicID:      "BadNodeIdUnknown",
+		NamespaceURI:    "http://example.com/",
+		LocalizedText:   "node not found",
+		InnerStatusCode: 0x80340000,
+		Inner: &ResolvedDiagnosticInfo{
+			SymbolicID:     "Inner",
+			NamespaceURI:   "http://example.com/",
+			AdditionalInfo: "foo",
+			Inner: &ResolvedDiagnosticInfo{
+				SymbolicID: "Innermost",
+			},
+		},
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Error(diff)
+	}
+
+	wantStr := `http://example.com/BadNodeIdUnknown: "node not found": inner status BadNodeIdUnknown (0x80340000): http://example.com/Inner: foo: Innermost`
+	if s := got.String(); s != wantStr {
+		t.Errorf("got %q want %q", s, wantStr)
+	}
+
+	// indices out of the StringTable are left empty.
+	if got := newNestedDiagnosticInfo().Resolve(nil); got.SymbolicID != "" || got.Inner.AdditionalInfo != "foo" {
+		t.Errorf("unexpected resolution without StringTable: %+v", got)
+	}
 }
 
 func TestDiagnosticInfoArray(t *testing.T) {
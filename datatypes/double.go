@@ -0,0 +1,83 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package datatypes
+
+import (
+	"encoding/binary"
+	"math"
+
+	"github.com/wmnsk/gopcua/errors"
+	"github.com/wmnsk/gopcua/id"
+)
+
+const f64qnan = 0xfff8000000000000
+
+// Double values shall be encoded with the appropriate IEEE-754 binary representation
+// which has three basic components: the sign, the exponent, and the fraction.
+//
+// Specification: Part 6, 5.2.2.3
+type Double struct {
+	Value float64
+}
+
+// NewDouble creates a new Double.
+func NewDouble(value float64) *Double {
+	return &Double{
+		Value: value,
+	}
+}
+
+// DecodeDouble decodes given bytes into Double.
+func DecodeDouble(b []byte) (*Double, error) {
+	d := &Double{}
+	if err := d.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// DecodeFromBytes decodes given bytes into OPC UA Double.
+func (d *Double) DecodeFromBytes(b []byte) error {
+	if len(b) < 8 {
+		return errors.NewErrTooShortToDecode(d, "should be longer than 8 bytes")
+	}
+	bits := binary.LittleEndian.Uint64(b)
+	d.Value = math.Float64frombits(bits)
+	return nil
+}
+
+// Serialize serializes Double into bytes.
+func (d *Double) Serialize() ([]byte, error) {
+	b := make([]byte, d.Len())
+	if err := d.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes Double into bytes.
+func (d *Double) SerializeTo(b []byte) error {
+	// Part 6, 5.2.2.3 encode NaN as IEEE 754 silent NaN
+	if math.IsNaN(d.Value) {
+		binary.LittleEndian.PutUint64(b, f64qnan)
+		return nil
+	}
+
+	bits := math.Float64bits(d.Value)
+	binary.LittleEndian.PutUint64(b, bits)
+	return nil
+}
+
+// Len returns the actual length of Double in int.
+func (d *Double) Len() int {
+	return 8
+}
+
+// DataType returns type of Data.
+func (d *Double) DataType() uint16 {
+	return id.Double
+}
@@ -0,0 +1,37 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package datatypes
+
+import (
+	"bytes"
+	"math"
+	"testing"
+
+	"github.com/wmnsk/gopcua/utils/codectest"
+)
+
+func TestDouble(t *testing.T) {
+	cases := []codectest.Case{
+		{
+			Name:   "Normal",
+			Struct: NewDouble(2.5),
+			Bytes:  []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x04, 0x40},
+		},
+	}
+	codectest.Run(t, cases, func(b []byte) (codectest.S, error) {
+		return DecodeDouble(b)
+	})
+}
+
+func TestDoubleNaN(t *testing.T) {
+	d := &Double{math.NaN()}
+	b, err := d.Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := b, []byte{0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xf8, 0xff}; !bytes.Equal(got, want) {
+		t.Fatalf("got %#v, want %#v", got, want)
+	}
+}
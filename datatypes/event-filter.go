@@ -0,0 +1,129 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package datatypes
+
+import (
+	"github.com/wmnsk/gopcua/errors"
+	"github.com/wmnsk/gopcua/id"
+)
+
+// EventFilter is a MonitoringFilter for the MonitoredItems of the EventNotifier Attribute.
+// The SelectClauses are the fields of the Events to be reported, and the WhereClause
+// filters the Events to be reported.
+//
+// Specification: Part 4, 7.17.3
+type EventFilter struct {
+	SelectClauses *SimpleAttributeOperandArray
+	WhereClause   *ContentFilter
+}
+
+// NewEventFilter creates a new EventFilter. The WhereClause is empty if where is nil.
+func NewEventFilter(where *ContentFilter, selects ...*SimpleAttributeOperand) *EventFilter {
+	if where == nil {
+		where = NewContentFilter()
+	}
+
+	return &EventFilter{
+		SelectClauses: NewSimpleAttributeOperandArray(selects),
+		WhereClause:   where,
+	}
+}
+
+// DecodeEventFilter decodes given bytes into EventFilter.
+func DecodeEventFilter(b []byte) (*EventFilter, error) {
+	f := &EventFilter{}
+	if err := f.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// DecodeFromBytes decodes given bytes into EventFilter.
+func (f *EventFilter) DecodeFromBytes(b []byte) error {
+	return f.decode(b, 0)
+}
+
+// decode decodes given bytes into EventFilter nested in the Variants to depth.
+func (f *EventFilter) decode(b []byte, depth int) error {
+	f.SelectClauses = &SimpleAttributeOperandArray{}
+	if err := f.SelectClauses.DecodeFromBytes(b); err != nil {
+		return err
+	}
+	offset := f.SelectClauses.Len()
+
+	f.WhereClause = &ContentFilter{}
+	return f.WhereClause.decode(b[offset:], depth)
+}
+
+// Serialize serializes EventFilter into bytes.
+func (f *EventFilter) Serialize() ([]byte, error) {
+	b := make([]byte, f.Len())
+	if err := f.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes EventFilter into bytes.
+func (f *EventFilter) SerializeTo(b []byte) error {
+	offset := 0
+	if f.SelectClauses != nil {
+		if err := f.SelectClauses.SerializeTo(b); err != nil {
+			return err
+		}
+		offset += f.SelectClauses.Len()
+	}
+
+	if f.WhereClause != nil {
+		return f.WhereClause.SerializeTo(b[offset:])
+	}
+
+	return nil
+}
+
+// Len returns the actual length of EventFilter in int.
+func (f *EventFilter) Len() int {
+	var l int
+	if f.SelectClauses != nil {
+		l += f.SelectClauses.Len()
+	}
+	if f.WhereClause != nil {
+		l += f.WhereClause.Len()
+	}
+
+	return l
+}
+
+// Type returns type of EventFilter defined in NodeIds.csv in int.
+func (f *EventFilter) Type() int {
+	return id.EventFilter_Encoding_DefaultBinary
+}
+
+// Fields returns the EventFields of the EventFieldList reported for the EventFilter,
+// keyed by the Path of the SelectClause of each of them, e.g. "Message".
+//
+// The EventFields are reported in the same order as the SelectClauses, so the number
+// of them should be the same.
+func (f *EventFilter) Fields(e *EventFieldList) (map[string]*Variant, error) {
+	var selects []*SimpleAttributeOperand
+	if f.SelectClauses != nil {
+		selects = f.SelectClauses.Operands
+	}
+	var fields []*Variant
+	if e.EventFields != nil {
+		fields = e.EventFields.Variants
+	}
+	if len(fields) != len(selects) {
+		return nil, errors.Errorf("got %d EventFields for %d SelectClauses", len(fields), len(selects))
+	}
+
+	m := make(map[string]*Variant, len(selects))
+	for i, s := range selects {
+		m[s.Path()] = fields[i]
+	}
+	return m, nil
+}
@@ -0,0 +1,115 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package datatypes
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/wmnsk/gopcua/utils/codectest"
+)
+
+func TestEventFilter(t *testing.T) {
+	cases := []codectest.Case{
+		{
+			Name:   "select-clauses",
+			Struct: NewEventFilter(nil, NewEventFieldOperand("Message"), NewEventFieldOperand("Severity")),
+			Bytes: []byte{
+				// SelectClauses: ArraySize
+				0x02, 0x00, 0x00, 0x00,
+				// TypeDefinitionID
+				0x01, 0x00, 0xf9, 0x07,
+				// BrowsePath
+				0x01, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x07, 0x00, 0x00, 0x00,
+				0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
+				// AttributeID
+				0x0d, 0x00, 0x00, 0x00,
+				// IndexRange
+				0xff, 0xff, 0xff, 0xff,
+				// TypeDefinitionID
+				0x01, 0x00, 0xf9, 0x07,
+				// BrowsePath
+				0x01, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x08, 0x00, 0x00, 0x00,
+				0x53, 0x65, 0x76, 0x65, 0x72, 0x69, 0x74, 0x79,
+				// AttributeID
+				0x0d, 0x00, 0x00, 0x00,
+				// IndexRange
+				0xff, 0xff, 0xff, 0xff,
+				// WhereClause
+				0x00, 0x00, 0x00, 0x00,
+			},
+		},
+		{
+			Name: "where-clause",
+			Struct: NewEventFilter(
+				NewContentFilter(
+					NewContentFilterElement(FilterOperatorGreaterThanOrEqual,
+						NewEventFieldOperand("Severity"),
+						NewLiteralOperand(NewVariant(NewInt32(500))),
+					),
+				),
+				NewEventFieldOperand("Message"),
+			),
+			Bytes: []byte{
+				// SelectClauses: ArraySize
+				0x01, 0x00, 0x00, 0x00,
+				// TypeDefinitionID
+				0x01, 0x00, 0xf9, 0x07,
+				// BrowsePath
+				0x01, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x07, 0x00, 0x00, 0x00,
+				0x4d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65,
+				// AttributeID
+				0x0d, 0x00, 0x00, 0x00,
+				// IndexRange
+				0xff, 0xff, 0xff, 0xff,
+				// WhereClause: ArraySize
+				0x01, 0x00, 0x00, 0x00,
+				// FilterOperator
+				0x04, 0x00, 0x00, 0x00,
+				// FilterOperands: ArraySize
+				0x02, 0x00, 0x00, 0x00,
+				// SimpleAttributeOperand: TypeID, EncodingMask, Length
+				0x01, 0x00, 0x5b, 0x02, 0x01, 0x1e, 0x00, 0x00, 0x00,
+				0x01, 0x00, 0xf9, 0x07,
+				0x01, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x08, 0x00, 0x00, 0x00,
+				0x53, 0x65, 0x76, 0x65, 0x72, 0x69, 0x74, 0x79,
+				0x0d, 0x00, 0x00, 0x00,
+				0xff, 0xff, 0xff, 0xff,
+				// LiteralOperand: TypeID, EncodingMask, Length
+				0x01, 0x00, 0x55, 0x02, 0x01, 0x05, 0x00, 0x00, 0x00,
+				0x06, 0xf4, 0x01, 0x00, 0x00,
+			},
+		},
+	}
+	codectest.Run(t, cases, func(b []byte) (codectest.S, error) {
+		return DecodeEventFilter(b)
+	})
+}
+
+func TestEventFilterFields(t *testing.T) {
+	f := NewEventFilter(nil, NewEventFieldOperand("Message"), NewEventFieldOperand("EnabledState", "Id"))
+	e := NewEventFieldList(1, NewVariant(NewLocalizedText("", "High")), NewVariant(NewBoolean(true)))
+
+	fields, err := f.Fields(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]*Variant{
+		"Message":         NewVariant(NewLocalizedText("", "High")),
+		"EnabledState/Id": NewVariant(NewBoolean(true)),
+	}
+	if diff := cmp.Diff(fields, want); diff != "" {
+		t.Error(diff)
+	}
+
+	if _, err := f.Fields(NewEventFieldList(1, NewVariant(NewBoolean(true)))); err == nil {
+		t.Error("expected error for the number of EventFields, got nil")
+	}
+}
@@ -0,0 +1,237 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package datatypes
+
+import (
+	"encoding/binary"
+
+	"github.com/wmnsk/gopcua/errors"
+	"github.com/wmnsk/gopcua/id"
+)
+
+// EventFieldList is an Event reported for a MonitoredItem in EventNotificationList.
+// The EventFields are the values of the SelectClauses of the EventFilter in the same order.
+//
+// ClientHandle is the one given by the Client in CreateMonitoredItems.
+//
+// Specification: Part 4, 7.20.3
+type EventFieldList struct {
+	ClientHandle uint32
+	EventFields  *VariantArray
+}
+
+// NewEventFieldList creates a new EventFieldList.
+func NewEventFieldList(handle uint32, fields ...*Variant) *EventFieldList {
+	return &EventFieldList{
+		ClientHandle: handle,
+		EventFields:  NewVariantArray(fields),
+	}
+}
+
+// DecodeEventFieldList decodes given bytes into EventFieldList.
+func DecodeEventFieldList(b []byte) (*EventFieldList, error) {
+	e := &EventFieldList{}
+	if err := e.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return e, nil
+}
+
+// DecodeFromBytes decodes given bytes into EventFieldList.
+func (e *EventFieldList) DecodeFromBytes(b []byte) error {
+	if len(b) < 8 {
+		return errors.NewErrTooShortToDecode(e, "should be longer than 8 bytes")
+	}
+	e.ClientHandle = binary.LittleEndian.Uint32(b[:4])
+
+	e.EventFields = &VariantArray{}
+	return e.EventFields.DecodeFromBytes(b[4:])
+}
+
+// Serialize serializes EventFieldList into bytes.
+func (e *EventFieldList) Serialize() ([]byte, error) {
+	b := make([]byte, e.Len())
+	if err := e.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes EventFieldList into bytes.
+func (e *EventFieldList) SerializeTo(b []byte) error {
+	binary.LittleEndian.PutUint32(b[:4], e.ClientHandle)
+
+	if e.EventFields != nil {
+		return e.EventFields.SerializeTo(b[4:])
+	}
+
+	return nil
+}
+
+// Len returns the actual length of EventFieldList in int.
+func (e *EventFieldList) Len() int {
+	l := 4
+	if e.EventFields != nil {
+		l += e.EventFields.Len()
+	}
+
+	return l
+}
+
+// EventFieldListArray represents an array of EventFieldLists.
+// It does not correspond to a certain type from the specification
+// but makes encoding and decoding easier.
+type EventFieldListArray struct {
+	ArraySize int32
+	Events    []*EventFieldList
+}
+
+// NewEventFieldListArray creates a new EventFieldListArray from multiple EventFieldLists.
+func NewEventFieldListArray(events []*EventFieldList) *EventFieldListArray {
+	if events == nil {
+		return &EventFieldListArray{
+			ArraySize: 0,
+		}
+	}
+
+	return &EventFieldListArray{
+		ArraySize: int32(len(events)),
+		Events:    events,
+	}
+}
+
+// DecodeEventFieldListArray decodes given bytes into EventFieldListArray.
+func DecodeEventFieldListArray(b []byte) (*EventFieldListArray, error) {
+	a := &EventFieldListArray{}
+	if err := a.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// DecodeFromBytes decodes given bytes into EventFieldListArray.
+func (a *EventFieldListArray) DecodeFromBytes(b []byte) error {
+	if len(b) < 4 {
+		return errors.NewErrTooShortToDecode(a, "should be longer than 4 bytes")
+	}
+	a.ArraySize = int32(binary.LittleEndian.Uint32(b[:4]))
+	if a.ArraySize <= 0 {
+		return nil
+	}
+
+	offset := 4
+	for i := 1; i <= int(a.ArraySize); i++ {
+		e, err := DecodeEventFieldList(b[offset:])
+		if err != nil {
+			return err
+		}
+		a.Events = append(a.Events, e)
+		offset += e.Len()
+	}
+
+	return nil
+}
+
+// Serialize serializes EventFieldListArray into bytes.
+func (a *EventFieldListArray) Serialize() ([]byte, error) {
+	b := make([]byte, a.Len())
+	if err := a.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes EventFieldListArray into bytes.
+func (a *EventFieldListArray) SerializeTo(b []byte) error {
+	offset := 4
+	binary.LittleEndian.PutUint32(b[:4], uint32(a.ArraySize))
+
+	for _, e := range a.Events {
+		if err := e.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += e.Len()
+	}
+
+	return nil
+}
+
+// Len returns the actual length in int.
+func (a *EventFieldListArray) Len() int {
+	l := 4
+	for _, e := range a.Events {
+		l += e.Len()
+	}
+
+	return l
+}
+
+// EventNotificationList is the NotificationData which contains the Events reported
+// for the MonitoredItems of the EventNotifier Attribute in a Subscription.
+//
+// Specification: Part 4, 7.20.3
+type EventNotificationList struct {
+	Events *EventFieldListArray
+}
+
+// NewEventNotificationList creates a new EventNotificationList.
+func NewEventNotificationList(events ...*EventFieldList) *EventNotificationList {
+	return &EventNotificationList{
+		Events: NewEventFieldListArray(events),
+	}
+}
+
+// DecodeEventNotificationList decodes given bytes into EventNotificationList.
+func DecodeEventNotificationList(b []byte) (*EventNotificationList, error) {
+	n := &EventNotificationList{}
+	if err := n.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return n, nil
+}
+
+// DecodeFromBytes decodes given bytes into EventNotificationList.
+func (n *EventNotificationList) DecodeFromBytes(b []byte) error {
+	n.Events = &EventFieldListArray{}
+	return n.Events.DecodeFromBytes(b)
+}
+
+// Serialize serializes EventNotificationList into bytes.
+func (n *EventNotificationList) Serialize() ([]byte, error) {
+	b := make([]byte, n.Len())
+	if err := n.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes EventNotificationList into bytes.
+func (n *EventNotificationList) SerializeTo(b []byte) error {
+	if n.Events != nil {
+		return n.Events.SerializeTo(b)
+	}
+
+	return nil
+}
+
+// Len returns the actual length of EventNotificationList in int.
+func (n *EventNotificationList) Len() int {
+	if n.Events != nil {
+		return n.Events.Len()
+	}
+
+	return 0
+}
+
+// Type returns type of EventNotificationList defined in NodeIds.csv in int.
+func (n *EventNotificationList) Type() int {
+	return id.EventNotificationList_Encoding_DefaultBinary
+}
@@ -0,0 +1,45 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package datatypes
+
+import (
+	"testing"
+
+	"github.com/wmnsk/gopcua/utils/codectest"
+)
+
+func TestEventNotificationList(t *testing.T) {
+	cases := []codectest.Case{
+		{
+			Name: "normal",
+			Struct: NewEventNotificationList(
+				NewEventFieldList(1, NewVariant(NewString("High")), NewVariant(NewInt32(500))),
+			),
+			Bytes: []byte{
+				// Events: ArraySize
+				0x01, 0x00, 0x00, 0x00,
+				// ClientHandle
+				0x01, 0x00, 0x00, 0x00,
+				// EventFields: ArraySize
+				0x02, 0x00, 0x00, 0x00,
+				// String
+				0x0c, 0x04, 0x00, 0x00, 0x00, 0x48, 0x69, 0x67, 0x68,
+				// Int32
+				0x06, 0xf4, 0x01, 0x00, 0x00,
+			},
+		},
+		{
+			Name:   "empty",
+			Struct: NewEventNotificationList(),
+			Bytes: []byte{
+				// Events: ArraySize
+				0x00, 0x00, 0x00, 0x00,
+			},
+		},
+	}
+	codectest.Run(t, cases, func(b []byte) (codectest.S, error) {
+		return DecodeEventNotificationList(b)
+	})
+}
@@ -0,0 +1,87 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package datatypes
+
+import (
+	"container/list"
+	"sync"
+)
+
+// ExpandedNodeIDCache is a LRU cache of the expanded node ids parsed by ParseExpandedNodeID,
+// to avoid parsing the same strings repeatedly, e.g. the ones in every incoming request.
+//
+// The expanded node ids are cached by the string given to Get as it is, so the same node
+// given in the different forms, e.g. "i=85" and "ns=0;i=85", is cached separately.
+// Get returns a clone of the cached expanded node id, so that the callers can modify it,
+// e.g. with ToNodeID which caches the resolved namespace index, without affecting the others.
+//
+// It is safe for concurrent use.
+type ExpandedNodeIDCache struct {
+	mu    sync.Mutex
+	size  int
+	ll    *list.List
+	items map[string]*list.Element
+}
+
+// expandedNodeIDCacheEntry is the value of the list elements in ExpandedNodeIDCache.
+type expandedNodeIDCacheEntry struct {
+	s string
+	e *ExpandedNodeID
+}
+
+// NewExpandedNodeIDCache creates a new ExpandedNodeIDCache which holds up to size expanded
+// node ids, discarding the least recently used ones. Nothing is cached if size is not positive.
+func NewExpandedNodeIDCache(size int) *ExpandedNodeIDCache {
+	return &ExpandedNodeIDCache{
+		size:  size,
+		ll:    list.New(),
+		items: map[string]*list.Element{},
+	}
+}
+
+// Get returns a clone of the expanded node id parsed from s with ParseExpandedNodeID.
+// It is parsed and cached only if s is not in the cache. The errors are not cached.
+func (c *ExpandedNodeIDCache) Get(s string) (*ExpandedNodeID, error) {
+	c.mu.Lock()
+	if el, ok := c.items[s]; ok {
+		c.ll.MoveToFront(el)
+		e := el.Value.(*expandedNodeIDCacheEntry).e
+		c.mu.Unlock()
+		return e.Clone(), nil
+	}
+	c.mu.Unlock()
+
+	// s is parsed without the lock so that the misses do not block the others.
+	e, err := ParseExpandedNodeID(s)
+	if err != nil {
+		return nil, err
+	}
+	if c.size <= 0 {
+		return e, nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	// the same s might have been cached by another caller while parsing.
+	if el, ok := c.items[s]; ok {
+		c.ll.MoveToFront(el)
+		return el.Value.(*expandedNodeIDCacheEntry).e.Clone(), nil
+	}
+	c.items[s] = c.ll.PushFront(&expandedNodeIDCacheEntry{s: s, e: e})
+	if c.ll.Len() > c.size {
+		el := c.ll.Back()
+		c.ll.Remove(el)
+		delete(c.items, el.Value.(*expandedNodeIDCacheEntry).s)
+	}
+	return e.Clone(), nil
+}
+
+// Len returns the number of expanded node ids in the cache.
+func (c *ExpandedNodeIDCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ll.Len()
+}
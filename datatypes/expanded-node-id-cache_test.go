@@ -0,0 +1,161 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package datatypes
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+)
+
+func TestExpandedNodeIDCache(t *testing.T) {
+	t.Run("hit", func(t *testing.T) {
+		c := NewExpandedNodeIDCache(2)
+		a, err := c.Get("nsu=http://example.com;s=foo.bar")
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := c.Get("nsu=http://example.com;s=foo.bar")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if a == b {
+			t.Error("got the same expanded node id, which should be a clone")
+		}
+		if !a.Equal(b) || !a.Equal(NewStringExpandedNodeIDWithURI("http://example.com", "foo.bar")) {
+			t.Errorf("got %s and %s", a, b)
+		}
+	})
+
+	t.Run("modify", func(t *testing.T) {
+		c := NewExpandedNodeIDCache(2)
+		a, err := c.Get("nsu=http://example.com;s=foo.bar")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := a.ToNodeID(map[string]uint16{"http://example.com": 2}); err != nil {
+			t.Fatal(err)
+		}
+		a.SetServerIndex(1)
+
+		b, err := c.Get("nsu=http://example.com;s=foo.bar")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if b.ResolvedNamespaceIndex != nil {
+			t.Errorf("got resolved namespace index %d want nil", *b.ResolvedNamespaceIndex)
+		}
+		if !b.Equal(NewStringExpandedNodeIDWithURI("http://example.com", "foo.bar")) {
+			t.Errorf("got %s", b)
+		}
+	})
+
+	t.Run("evict", func(t *testing.T) {
+		c := NewExpandedNodeIDCache(2)
+		c.Get("i=1")
+		c.Get("i=2")
+		// i=1 is used more recently than i=2, which is evicted by i=3.
+		c.Get("i=1")
+		c.Get("i=3")
+		if got, want := c.Len(), 2; got != want {
+			t.Fatalf("got %d entries want %d", got, want)
+		}
+		c.mu.Lock()
+		_, ok1 := c.items["i=1"]
+		_, ok2 := c.items["i=2"]
+		c.mu.Unlock()
+		if !ok1 {
+			t.Error("i=1 should not be evicted")
+		}
+		if ok2 {
+			t.Error("i=2 should be evicted")
+		}
+	})
+
+	t.Run("error", func(t *testing.T) {
+		c := NewExpandedNodeIDCache(2)
+		if _, err := c.Get("svr=abc;i=1"); err == nil {
+			t.Fatal("got nil error")
+		}
+		if got := c.Len(); got != 0 {
+			t.Errorf("got %d entries want 0", got)
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		c := NewExpandedNodeIDCache(0)
+		if _, err := c.Get("i=1"); err != nil {
+			t.Fatal(err)
+		}
+		if got := c.Len(); got != 0 {
+			t.Errorf("got %d entries want 0", got)
+		}
+	})
+}
+
+func TestExpandedNodeIDCacheConcurrent(t *testing.T) {
+	c := NewExpandedNodeIDCache(8)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				s := fmt.Sprintf("ns=%d;i=%d", i, j%16)
+				e, err := c.Get(s)
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				if !e.Equal(NewExpandedNodeID(false, false, NewNumericNodeID(uint16(i), uint32(j%16)), "", 0)) {
+					t.Errorf("got %s for %s", e, s)
+					return
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if got := c.Len(); got > 8 {
+		t.Errorf("got %d entries want up to 8", got)
+	}
+}
+
+func BenchmarkExpandedNodeIDCache(b *testing.B) {
+	const s = "svr=1;nsu=http://example.com;s=foo.bar"
+
+	b.Run("uncached", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := ParseExpandedNodeID(s); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("cached", func(b *testing.B) {
+		c := NewExpandedNodeIDCache(16)
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			if _, err := c.Get(s); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+
+	b.Run("cached-parallel", func(b *testing.B) {
+		c := NewExpandedNodeIDCache(16)
+		b.ReportAllocs()
+		b.RunParallel(func(pb *testing.PB) {
+			for pb.Next() {
+				if _, err := c.Get(s); err != nil {
+					b.Error(err)
+					return
+				}
+			}
+		})
+	})
+}
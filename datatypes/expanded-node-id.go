@@ -5,7 +5,18 @@
 package datatypes
 
 import (
+	"bytes"
 	"encoding/binary"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/wmnsk/gopcua/errors"
+	"github.com/wmnsk/gopcua/id"
 )
 
 // ExpandedNodeID extends the NodeID structure by allowing the NamespaceURI to be
@@ -17,6 +28,11 @@ type ExpandedNodeID struct {
 	NodeID       *NodeID
 	NamespaceURI *String
 	ServerIndex  uint32
+
+	// ResolvedNamespaceIndex is the namespace index which the NamespaceURI
+	// has been resolved to by ToNodeID. It is not part of the wire format and
+	// is ignored on serialization and comparison.
+	ResolvedNamespaceIndex *uint16
 }
 
 // NewExpandedNodeID creates a new ExpandedNodeID.
@@ -51,6 +67,83 @@ func NewFourByteExpandedNodeID(ns uint8, id uint16) *ExpandedNodeID {
 	}
 }
 
+// NewExpandedNodeIDWithServer creates an expanded node id which references
+// a node on the server with the given ServerIndex.
+func NewExpandedNodeIDWithServer(nodeID *NodeID, serverIdx uint32) *ExpandedNodeID {
+	return NewExpandedNodeID(false, true, nodeID, "", serverIdx)
+}
+
+// NewNullExpandedNodeID creates a null expanded node id, i.e. a two byte
+// numeric id of 0 without NamespaceURI and ServerIndex.
+func NewNullExpandedNodeID() *ExpandedNodeID {
+	return NewTwoByteExpandedNodeID(0)
+}
+
+// NewStringExpandedNodeID creates a string expanded node id.
+func NewStringExpandedNodeID(ns uint16, id string) *ExpandedNodeID {
+	return &ExpandedNodeID{
+		NodeID: NewStringNodeID(ns, id),
+	}
+}
+
+// NewStringExpandedNodeIDWithURI creates a string expanded node id
+// with the given NamespaceURI.
+func NewStringExpandedNodeIDWithURI(uri, id string) *ExpandedNodeID {
+	return NewExpandedNodeID(true, false, NewStringNodeID(0, id), uri, 0)
+}
+
+// NewGUIDExpandedNodeID creates a GUID expanded node id.
+func NewGUIDExpandedNodeID(ns uint16, guid string) *ExpandedNodeID {
+	return &ExpandedNodeID{
+		NodeID: NewGUIDNodeID(ns, guid),
+	}
+}
+
+// ParseExpandedNodeID returns an expanded node id from a string definition
+// of the format 'svr=<serverindex>;nsu=<uri>;<nodeid>' as returned by String.
+//
+// The 'svr=' and 'nsu=' segments are optional. The remaining node id is
+// parsed with NewNodeID. The namespace prefix 'ns=' of the node id can be
+// omitted in which case the namespace index is zero.
+func ParseExpandedNodeID(s string) (*ExpandedNodeID, error) {
+	var (
+		hasURI, hasIndex bool
+		uri              string
+		idx              uint32
+	)
+
+	rest := s
+	if strings.HasPrefix(rest, "svr=") {
+		p := strings.SplitN(rest, ";", 2)
+		if len(p) < 2 {
+			return nil, fmt.Errorf("invalid expanded node id: %s", s)
+		}
+		n, err := strconv.ParseUint(p[0][4:], 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid server index: %s", s)
+		}
+		hasIndex, idx, rest = true, uint32(n), p[1]
+	}
+
+	if strings.HasPrefix(rest, "nsu=") {
+		p := strings.SplitN(rest, ";", 2)
+		if len(p) < 2 {
+			return nil, fmt.Errorf("invalid expanded node id: %s", s)
+		}
+		hasURI, uri, rest = true, p[0][4:], p[1]
+	}
+
+	if rest == "" {
+		return nil, fmt.Errorf("invalid expanded node id: %s", s)
+	}
+	n, err := NewNodeID(rest)
+	if err != nil {
+		return nil, fmt.Errorf("invalid expanded node id: %s: %s", s, err)
+	}
+
+	return NewExpandedNodeID(hasURI, hasIndex, n, uri, idx), nil
+}
+
 // DecodeExpandedNodeID decodes given bytes into ExpandedNodeID.
 func DecodeExpandedNodeID(b []byte) (*ExpandedNodeID, error) {
 	e := &ExpandedNodeID{}
@@ -63,31 +156,148 @@ func DecodeExpandedNodeID(b []byte) (*ExpandedNodeID, error) {
 
 // DecodeFromBytes decodes given bytes into ExpandedNodeID.
 func (e *ExpandedNodeID) DecodeFromBytes(b []byte) error {
+	_, err := e.DecodeFromBytesN(b)
+	return err
+}
+
+// DecodeFromBytesStrict decodes given bytes into ExpandedNodeID like DecodeFromBytes,
+// but returns an error if the NamespaceURI flag is set and the NamespaceURI is
+// a null string. An empty NamespaceURI is still accepted.
+func (e *ExpandedNodeID) DecodeFromBytesStrict(b []byte) error {
+	_, err := e.decodeFromBytes(b, true)
+	return err
+}
+
+// DecodeFromBytesN decodes given bytes into ExpandedNodeID and returns
+// the number of bytes consumed. It returns io.ErrUnexpectedEOF if the
+// flags indicate optional fields which are not present in b.
+func (e *ExpandedNodeID) DecodeFromBytesN(b []byte) (int, error) {
+	return e.decodeFromBytes(b, false)
+}
+
+// DecodeFrom decodes the ExpandedNodeID from the bytes left in dec.
+func (e *ExpandedNodeID) DecodeFrom(dec *Decoder) error {
+	return e.decodeFrom(dec, false)
+}
+
+func (e *ExpandedNodeID) decodeFromBytes(b []byte, strict bool) (int, error) {
+	dec := Decoder{b: b}
+	if err := e.decodeFrom(&dec, strict); err != nil {
+		return 0, err
+	}
+	return dec.Offset(), nil
+}
+
+func (e *ExpandedNodeID) decodeFrom(dec *Decoder, strict bool) error {
 	node := &NodeID{}
-	if err := node.DecodeFromBytes(b); err != nil {
+	if err := dec.ReadData(node); err != nil {
 		return err
 	}
-	e.NodeID = node
-	b = b[node.Len():]
-	if len(b) < 2 {
-		return nil
-	}
+	*e = ExpandedNodeID{NodeID: node}
 
 	if e.HasNamespaceURI() {
 		e.NamespaceURI = &String{}
-		if err := e.NamespaceURI.DecodeFromBytes(b); err != nil {
+		if err := dec.ReadData(e.NamespaceURI); err != nil {
 			return err
 		}
-		b = b[e.NamespaceURI.Len():]
+		if strict && e.NamespaceURI.Length < 0 {
+			return errors.NewErrInvalidType(e, "decode", "NamespaceURI flag is set with null NamespaceURI")
+		}
 	}
 
 	if e.HasServerIndex() {
-		e.ServerIndex = binary.LittleEndian.Uint32(b[:4])
+		idx, err := dec.ReadUint32()
+		if err != nil {
+			return err
+		}
+		e.ServerIndex = idx
 	}
 
 	return nil
 }
 
+// maxReadLength is the largest length of the String identifier, the ByteString identifier
+// or the NamespaceURI read by ReadExpandedNodeID, which is far more than the ones in practice.
+// It keeps the stream from making ReadExpandedNodeID allocate for an arbitrary length.
+const maxReadLength = 0xffff
+
+// ReadExpandedNodeID reads an ExpandedNodeID from r. It reads only the
+// bytes which belong to the ExpandedNodeID and decodes them with
+// DecodeFromBytes. If r is empty, io.EOF is returned.
+//
+// The String and ByteString longer than maxReadLength are rejected before reading them.
+func ReadExpandedNodeID(r io.Reader) (*ExpandedNodeID, error) {
+	b := make([]byte, 1)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return nil, err
+	}
+	mask := b[0]
+
+	var err error
+	switch mask & 0x3f {
+	case TypeTwoByte:
+		b, err = readN(b, r, 1)
+	case TypeFourByte:
+		b, err = readN(b, r, 3)
+	case TypeNumeric:
+		b, err = readN(b, r, 6)
+	case TypeGUID:
+		b, err = readN(b, r, 18)
+	case TypeString, TypeOpaque:
+		if b, err = readN(b, r, 2); err == nil {
+			b, err = readString(b, r)
+		}
+	default:
+		return nil, fmt.Errorf("invalid node id type: %d", mask&0x3f)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if mask&0x80 == 0x80 {
+		if b, err = readString(b, r); err != nil {
+			return nil, err
+		}
+	}
+
+	if mask&0x40 == 0x40 {
+		if b, err = readN(b, r, 4); err != nil {
+			return nil, err
+		}
+	}
+
+	return DecodeExpandedNodeID(b)
+}
+
+// readString reads the String or ByteString from r with its length, and appends it to b.
+// The null one, of which the length is negative, has no bytes after the length.
+func readString(b []byte, r io.Reader) ([]byte, error) {
+	b, err := readN(b, r, 4)
+	if err != nil {
+		return nil, err
+	}
+	l := int32(binary.LittleEndian.Uint32(b[len(b)-4:]))
+	if l <= 0 {
+		return b, nil
+	}
+	if l > maxReadLength {
+		return nil, errors.NewErrInvalidLength(l, fmt.Sprintf("should not be longer than %d bytes", maxReadLength))
+	}
+	return readN(b, r, int(l))
+}
+
+// readN reads exactly n bytes from r, and appends them to b.
+func readN(b []byte, r io.Reader, n int) ([]byte, error) {
+	buf := make([]byte, n)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		if err == io.EOF {
+			return nil, io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	return append(b, buf...), nil
+}
+
 // Serialize serializes ExpandedNodeID into bytes.
 func (e *ExpandedNodeID) Serialize() ([]byte, error) {
 	b := make([]byte, e.Len())
@@ -100,27 +310,96 @@ func (e *ExpandedNodeID) Serialize() ([]byte, error) {
 
 // SerializeTo serializes ExpandedNodeID into bytes.
 func (e *ExpandedNodeID) SerializeTo(b []byte) error {
-	var offset = 0
-	if err := e.NodeID.SerializeTo(b); err != nil {
+	if err := e.Validate(); err != nil {
+		return err
+	}
+	if len(b) < e.Len() {
+		return errors.NewErrInvalidLength(e, "bytes should be longer")
+	}
+
+	// b is long enough that enc never reallocates.
+	enc := Encoder{buf: b[:0]}
+	return e.encode(&enc)
+}
+
+// EncodeTo writes the serialized ExpandedNodeID to enc. Nothing is written if it fails.
+func (e *ExpandedNodeID) EncodeTo(enc *Encoder) error {
+	if err := e.Validate(); err != nil {
 		return err
 	}
-	offset += e.NodeID.Len()
+	return e.encode(enc)
+}
 
+// encode writes the ExpandedNodeID validated to enc.
+func (e *ExpandedNodeID) encode(enc *Encoder) error {
+	l := enc.Len()
+	if err := enc.WriteData(e.NodeID); err != nil {
+		return err
+	}
 	if e.HasNamespaceURI() {
-		if err := e.NamespaceURI.SerializeTo(b[offset:]); err != nil {
+		if err := enc.WriteData(e.NamespaceURI); err != nil {
+			enc.buf = enc.buf[:l]
 			return err
 		}
-		offset += e.NamespaceURI.Len()
 	}
-
 	if e.HasServerIndex() {
-		binary.LittleEndian.PutUint32(b[offset:offset+4], e.ServerIndex)
-		offset += 4
+		enc.WriteUint32(e.ServerIndex)
 	}
 
 	return nil
 }
 
+// SerializeToBuf appends the serialized ExpandedNodeID to buf.
+//
+// Unlike Serialize, this does not allocate when buf has enough spare capacity,
+// so that the same buf can be reset and reused for each ExpandedNodeID.
+func (e *ExpandedNodeID) SerializeToBuf(buf *bytes.Buffer) error {
+	if err := e.Validate(); err != nil {
+		return err
+	}
+
+	// serialize into the spare capacity of buf and let Write take it in.
+	l, n := buf.Len(), e.Len()
+	buf.Grow(n)
+	b := buf.Bytes()[l : l+n]
+	enc := Encoder{buf: b[:0]}
+	if err := e.encode(&enc); err != nil {
+		return err
+	}
+	_, err := buf.Write(b)
+	return err
+}
+
+// MarshalBinary implements the encoding.BinaryMarshaler interface.
+func (e *ExpandedNodeID) MarshalBinary() ([]byte, error) {
+	return e.Serialize()
+}
+
+// UnmarshalBinary implements the encoding.BinaryUnmarshaler interface.
+//
+// b is copied before decoding, as the decoded ExpandedNodeID refers to the bytes given
+// while the callers of UnmarshalBinary may reuse them.
+func (e *ExpandedNodeID) UnmarshalBinary(b []byte) error {
+	return e.DecodeFromBytes(append([]byte(nil), b...))
+}
+
+// Validate checks that the NodeID is set and that the NamespaceURI and ServerIndex fields
+// are consistent with the flags in the EncodingMask of the NodeID.
+func (e *ExpandedNodeID) Validate() error {
+	switch {
+	case e.NodeID == nil:
+		return errors.NewErrInvalidType(e, "serialize", "NodeID is nil")
+	case e.NamespaceURI != nil && !e.HasNamespaceURI():
+		return errors.NewErrInvalidType(e, "serialize", "NamespaceURI is set without NamespaceURI flag")
+	case e.NamespaceURI == nil && e.HasNamespaceURI():
+		return errors.NewErrInvalidType(e, "serialize", "NamespaceURI flag is set without NamespaceURI")
+	case e.ServerIndex != 0 && !e.HasServerIndex():
+		return errors.NewErrInvalidType(e, "serialize", "ServerIndex is set without ServerIndex flag")
+	default:
+		return nil
+	}
+}
+
 // Len returns the actual length of ExpandedNodeID in int.
 func (e *ExpandedNodeID) Len() int {
 	if e.NodeID == nil {
@@ -128,7 +407,7 @@ func (e *ExpandedNodeID) Len() int {
 	}
 
 	l := e.NodeID.Len()
-	if e.HasNamespaceURI() {
+	if e.HasNamespaceURI() && e.NamespaceURI != nil {
 		l += e.NamespaceURI.Len()
 	}
 	if e.HasServerIndex() {
@@ -138,12 +417,329 @@ func (e *ExpandedNodeID) Len() int {
 	return l
 }
 
+// IsNull checks if an ExpandedNodeID is a null expanded node id, i.e. a
+// numeric id of 0 in namespace 0 without NamespaceURI and ServerIndex flags.
+func (e *ExpandedNodeID) IsNull() bool {
+	if e == nil || e.NodeID == nil {
+		return true
+	}
+	if e.HasNamespaceURI() || e.HasServerIndex() {
+		return false
+	}
+	return e.NodeID.isNumeric() && e.NodeID.ns == 0 && e.NodeID.nid == 0
+}
+
 // HasNamespaceURI checks if an ExpandedNodeID has NamespaceURI Flag.
 func (e *ExpandedNodeID) HasNamespaceURI() bool {
+	if e.NodeID == nil {
+		return false
+	}
 	return e.NodeID.EncodingMask()>>7&0x1 == 1
 }
 
 // HasServerIndex checks if an ExpandedNodeID has ServerIndex Flag.
 func (e *ExpandedNodeID) HasServerIndex() bool {
+	if e.NodeID == nil {
+		return false
+	}
 	return e.NodeID.EncodingMask()>>6&0x1 == 1
 }
+
+// String returns the string representation of the ExpandedNodeID
+// in the format 'svr=<serverindex>;nsu=<uri>;<nodeid>'.
+//
+// The 'svr=' and 'nsu=' segments are only added if the corresponding
+// flags are set. If the NamespaceURI is set, the namespace index of the
+// NodeID is omitted since it shall be ignored.
+//
+// Specification: Part 6, 5.3.1.11
+func (e *ExpandedNodeID) String() string {
+	if e.NodeID == nil {
+		return ""
+	}
+
+	s := e.NodeID.String()
+	if e.HasNamespaceURI() {
+		if strings.HasPrefix(s, "ns=") {
+			s = s[strings.Index(s, ";")+1:]
+		}
+		s = fmt.Sprintf("nsu=%s;%s", e.URI(), s)
+	}
+	if e.HasServerIndex() {
+		s = fmt.Sprintf("svr=%d;%s", e.ServerIndex, s)
+	}
+
+	return s
+}
+
+// SetNamespaceURI sets the NamespaceURI and the NamespaceURI flag.
+func (e *ExpandedNodeID) SetNamespaceURI(uri string) {
+	e.NamespaceURI = NewString(uri)
+	e.ResolvedNamespaceIndex = nil
+	e.NodeID.SetURIFlag()
+}
+
+// ClearNamespaceURI removes the NamespaceURI and clears the NamespaceURI flag.
+func (e *ExpandedNodeID) ClearNamespaceURI() {
+	e.NamespaceURI = nil
+	e.ResolvedNamespaceIndex = nil
+	e.NodeID.ClearURIFlag()
+}
+
+// SetServerIndex sets the ServerIndex and the ServerIndex flag.
+func (e *ExpandedNodeID) SetServerIndex(idx uint32) {
+	e.ServerIndex = idx
+	e.NodeID.SetIndexFlag()
+}
+
+// ClearServerIndex zeroes the ServerIndex and clears the ServerIndex flag.
+func (e *ExpandedNodeID) ClearServerIndex() {
+	e.ServerIndex = 0
+	e.NodeID.ClearIndexFlag()
+}
+
+// Clone returns a deep copy of the ExpandedNodeID which shares
+// no mutable state with e.
+func (e *ExpandedNodeID) Clone() *ExpandedNodeID {
+	if e == nil {
+		return nil
+	}
+
+	c := &ExpandedNodeID{
+		ServerIndex: e.ServerIndex,
+	}
+	if e.NodeID != nil {
+		c.NodeID = e.NodeID.clone()
+	}
+	if e.NamespaceURI != nil {
+		c.NamespaceURI = &String{Length: e.NamespaceURI.Length}
+		if e.NamespaceURI.Value != nil {
+			c.NamespaceURI.Value = append([]byte{}, e.NamespaceURI.Value...)
+		}
+	}
+	if e.ResolvedNamespaceIndex != nil {
+		ns := *e.ResolvedNamespaceIndex
+		c.ResolvedNamespaceIndex = &ns
+	}
+	return c
+}
+
+// ToNodeID returns a copy of the NodeID without the ExpandedNodeID flags.
+//
+// If the ExpandedNodeID has a NamespaceURI, it is resolved to a namespace
+// index with nsTable and the namespace of the returned NodeID is set
+// accordingly. Two byte and four byte node ids are converted to a larger
+// type if the namespace index does not fit. An error is returned if
+// the NamespaceURI is not in nsTable.
+//
+// The resolved index is cached in ResolvedNamespaceIndex and used instead of
+// nsTable on subsequent calls until the NamespaceURI is changed.
+func (e *ExpandedNodeID) ToNodeID(nsTable map[string]uint16) (*NodeID, error) {
+	if e.NodeID == nil {
+		return nil, errors.NewErrInvalidType(e, "convert", "NodeID is nil")
+	}
+
+	n := e.NodeID.clone()
+	n.ClearURIFlag()
+	n.ClearIndexFlag()
+
+	uri := e.URI()
+	if uri == "" {
+		return n, nil
+	}
+
+	if e.ResolvedNamespaceIndex == nil {
+		ns, ok := nsTable[uri]
+		if !ok {
+			return nil, fmt.Errorf("unknown namespace uri: %s", uri)
+		}
+		e.ResolvedNamespaceIndex = &ns
+	}
+
+	ns := *e.ResolvedNamespaceIndex
+	if err := n.SetNamespace(int(ns)); err != nil {
+		switch {
+		case ns <= math.MaxUint8 && n.nid <= math.MaxUint16:
+			n = NewFourByteNodeID(uint8(ns), uint16(n.nid))
+		default:
+			n = NewNumericNodeID(ns, n.nid)
+		}
+	}
+
+	return n, nil
+}
+
+// ToExpandedNodeID returns an ExpandedNodeID of a copy of n with the NamespaceURI
+// of its namespace index in nsArray, which is the NamespaceArray of the server.
+//
+// The namespace 0 of OPC UA and the indices not in nsArray are kept as they are
+// without the NamespaceURI.
+func (n *NodeID) ToExpandedNodeID(nsArray []string) *ExpandedNodeID {
+	c := n.clone()
+	ns := n.ns
+	if ns == 0 || int(ns) >= len(nsArray) {
+		return &ExpandedNodeID{NodeID: c}
+	}
+
+	c.ns = 0
+	e := NewExpandedNodeID(true, false, c, nsArray[ns], 0)
+	e.ResolvedNamespaceIndex = &ns
+	return e
+}
+
+// Equal returns true if both expanded node ids identify the same node.
+//
+// If both have a NamespaceURI, the URIs and the identifiers are compared and
+// the namespace index is ignored. An ExpandedNodeID with a NamespaceURI is never
+// equal to one without since the namespace index cannot be resolved here.
+// A nil or empty NamespaceURI is treated as absent.
+func (e *ExpandedNodeID) Equal(f *ExpandedNodeID) bool {
+	if e == nil || f == nil {
+		return e == f
+	}
+	if e.NodeID == nil || f.NodeID == nil {
+		return e.NodeID == f.NodeID
+	}
+	if e.serverIndex() != f.serverIndex() {
+		return false
+	}
+
+	eu, fu := e.URI(), f.URI()
+	switch {
+	case eu == "" && fu == "":
+		return e.NodeID.Equal(f.NodeID)
+	case eu == fu:
+		return e.NodeID.equalIdentifier(f.NodeID)
+	default:
+		return false
+	}
+}
+
+// URI returns the NamespaceURI if the NamespaceURI flag is set or an
+// empty string otherwise. Use HasURIValue to distinguish a null
+// NamespaceURI from an empty one.
+func (e *ExpandedNodeID) URI() string {
+	if !e.HasNamespaceURI() || e.NamespaceURI == nil {
+		return ""
+	}
+	return e.NamespaceURI.Get()
+}
+
+// HasURIValue returns true if the NamespaceURI flag is set and the
+// NamespaceURI is not a null string, i.e. it has a length of zero
+// or more.
+func (e *ExpandedNodeID) HasURIValue() bool {
+	return e.HasNamespaceURI() && e.NamespaceURI != nil && e.NamespaceURI.Length >= 0
+}
+
+// serverIndex returns the ServerIndex if the ServerIndex flag
+// is set or zero, i.e. the local server, otherwise.
+func (e *ExpandedNodeID) serverIndex() uint32 {
+	if !e.HasServerIndex() {
+		return 0
+	}
+	return e.ServerIndex
+}
+
+// expandedNodeIDJSON is the JSON representation of an ExpandedNodeID.
+//
+// Specification: Part 6, 5.4.2.11
+type expandedNodeIDJSON struct {
+	nodeIDJSON
+	ServerURI uint32 `json:"ServerUri,omitempty"`
+}
+
+// MarshalJSON returns the reversible JSON encoding of the ExpandedNodeID.
+// The Namespace is the NamespaceURI if the NamespaceURI flag is set and the
+// namespace index otherwise. The ServerUri is the ServerIndex.
+func (e *ExpandedNodeID) MarshalJSON() ([]byte, error) {
+	if e.NodeID == nil {
+		return []byte("null"), nil
+	}
+
+	v, err := e.NodeID.toJSON()
+	if err != nil {
+		return nil, err
+	}
+	ev := expandedNodeIDJSON{nodeIDJSON: *v, ServerURI: e.serverIndex()}
+	switch {
+	case e.HasNamespaceURI():
+		if ev.Namespace, err = json.Marshal(e.URI()); err != nil {
+			return nil, err
+		}
+	case e.NodeID.ns != 0:
+		ev.Namespace = json.RawMessage(strconv.Itoa(int(e.NodeID.ns)))
+	}
+	return json.Marshal(ev)
+}
+
+// UnmarshalJSON decodes the reversible JSON encoding of an ExpandedNodeID.
+func (e *ExpandedNodeID) UnmarshalJSON(b []byte) error {
+	var v expandedNodeIDJSON
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+
+	var (
+		ns     uint16
+		uri    string
+		hasURI bool
+	)
+	if len(v.Namespace) > 0 {
+		if v.Namespace[0] == '"' {
+			if err := json.Unmarshal(v.Namespace, &uri); err != nil {
+				return err
+			}
+			hasURI = true
+		} else if err := json.Unmarshal(v.Namespace, &ns); err != nil {
+			return fmt.Errorf("invalid namespace: %s", v.Namespace)
+		}
+	}
+
+	n := &NodeID{}
+	if err := n.fromJSON(v.nodeIDJSON, ns); err != nil {
+		return err
+	}
+	*e = *NewExpandedNodeID(hasURI, v.ServerURI != 0, n, uri, v.ServerURI)
+	return nil
+}
+
+// expandedNodeIDXML is the XML representation of an ExpandedNodeID.
+//
+// Specification: Part 6, 5.3.1.11
+type expandedNodeIDXML struct {
+	Identifier string `xml:"Identifier,omitempty"`
+}
+
+// MarshalXML encodes the ExpandedNodeID as an element which has the
+// string form returned by String in its Identifier element.
+func (e *ExpandedNodeID) MarshalXML(enc *xml.Encoder, start xml.StartElement) error {
+	return enc.EncodeElement(expandedNodeIDXML{Identifier: e.String()}, start)
+}
+
+// UnmarshalXML decodes an element which has the string form of an
+// ExpandedNodeID in its Identifier element as accepted by ParseExpandedNodeID.
+// A missing or empty Identifier results in the null ExpandedNodeID.
+func (e *ExpandedNodeID) UnmarshalXML(dec *xml.Decoder, start xml.StartElement) error {
+	var v expandedNodeIDXML
+	if err := dec.DecodeElement(&v, &start); err != nil {
+		return err
+	}
+
+	s := strings.TrimSpace(v.Identifier)
+	if s == "" {
+		*e = *NewNullExpandedNodeID()
+		return nil
+	}
+	f, err := ParseExpandedNodeID(s)
+	if err != nil {
+		return err
+	}
+	*e = *f
+	return nil
+}
+
+// DataType returns type of Data.
+func (e *ExpandedNodeID) DataType() uint16 {
+	return id.ExpandedNodeId
+}
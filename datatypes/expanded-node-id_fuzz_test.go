@@ -0,0 +1,46 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+//go:build go1.18
+// +build go1.18
+
+package datatypes
+
+import (
+	"bytes"
+	"testing"
+)
+
+func FuzzExpandedNodeID(f *testing.F) {
+	// two byte
+	f.Add([]byte{0x00, 0xff})
+	// four byte
+	f.Add([]byte{0x01, 0x01, 0xfe, 0xca})
+	// string
+	f.Add([]byte{0x03, 0x01, 0x00, 0x03, 0x00, 0x00, 0x00, 0x66, 0x6f, 0x6f})
+	// with NamespaceURI and ServerIndex
+	f.Add([]byte{
+		0xc0, 0xff, 0x06, 0x00, 0x00, 0x00, 0x66, 0x6f,
+		0x6f, 0x62, 0x61, 0x72, 0x00, 0x80, 0x00, 0x00,
+	})
+
+	f.Fuzz(func(t *testing.T, b []byte) {
+		e := &ExpandedNodeID{}
+		n, err := e.DecodeFromBytesN(b)
+		if err != nil {
+			return
+		}
+
+		got, err := e.Serialize()
+		if err != nil {
+			t.Fatalf("cannot serialize decoded %x: %s", b, err)
+		}
+		if len(got) != n {
+			t.Fatalf("serialized %d bytes but decoded %d", len(got), n)
+		}
+		if want := b[:n]; !bytes.Equal(got, want) {
+			t.Fatalf("got %x want %x", got, want)
+		}
+	})
+}
@@ -5,6 +5,14 @@
 package datatypes
 
 import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
 	"testing"
 
 	"github.com/wmnsk/gopcua/utils/codectest"
@@ -58,8 +66,1232 @@ func TestExpandedNodeID(t *testing.T) {
 				0x6f, 0x62, 0x61, 0x72, 0x00, 0x80, 0x00, 0x00,
 			},
 		},
+		{
+			Name:   "String",
+			Struct: NewStringExpandedNodeID(1, "foo"),
+			Bytes: []byte{
+				0x03, 0x01, 0x00, 0x03, 0x00, 0x00, 0x00, 0x66,
+				0x6f, 0x6f,
+			},
+		},
+		{
+			Name:   "String with NamespaceURI",
+			Struct: NewStringExpandedNodeIDWithURI("bar", "foo"),
+			Bytes: []byte{
+				0x83, 0x00, 0x00, 0x03, 0x00, 0x00, 0x00, 0x66,
+				0x6f, 0x6f, 0x03, 0x00, 0x00, 0x00, 0x62, 0x61,
+				0x72,
+			},
+		},
+		{
+			Name:   "GUID",
+			Struct: NewGUIDExpandedNodeID(4660, "AAAABBBB-CCDD-EEFF-0101-0123456789AB"),
+			Bytes: []byte{
+				0x04, 0x34, 0x12, 0xbb, 0xbb, 0xaa, 0xaa, 0xdd,
+				0xcc, 0xff, 0xee, 0x01, 0x01, 0x01, 0x23, 0x45,
+				0x67, 0x89, 0xab,
+			},
+		},
 	}
 	codectest.Run(t, cases, func(b []byte) (codectest.S, error) {
 		return DecodeExpandedNodeID(b)
 	})
 }
+
+func TestExpandedNodeIDCheckSerialize(t *testing.T) {
+	cases := []struct {
+		name string
+		e    *ExpandedNodeID
+	}{
+		{"two byte", NewTwoByteExpandedNodeID(0xff)},
+		{"four byte", NewFourByteExpandedNodeID(1, 0xcafe)},
+		{"numeric", NewExpandedNodeID(false, false, NewNumericNodeID(10, 70000), "", 0)},
+		{"string", NewStringExpandedNodeID(2, "foo.bar")},
+		{"guid", NewGUIDExpandedNodeID(1, "AAAABBBB-CCDD-EEFF-0101-0123456789AB")},
+		{"opaque", NewExpandedNodeID(false, false, NewOpaqueNodeID(1, []byte{0xde, 0xad, 0xbe, 0xef}), "", 0)},
+		{"with NamespaceURI", NewStringExpandedNodeIDWithURI("http://example.com", "foo")},
+		{"with ServerIndex", NewExpandedNodeIDWithServer(NewTwoByteNodeID(42), 0xdeadbeef)},
+		{"with NamespaceURI and ServerIndex", NewExpandedNodeID(true, true, NewNumericNodeID(0, 15001), "http://example.com", 3)},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			codectest.CheckSerialize(t, c.e, func(b []byte) (codectest.S, error) {
+				return DecodeExpandedNodeID(b)
+			})
+		})
+	}
+}
+
+func TestExpandedNodeIDString(t *testing.T) {
+	cases := []struct {
+		name string
+		e    *ExpandedNodeID
+		s    string
+	}{
+		{
+			name: "two byte",
+			e:    NewTwoByteExpandedNodeID(42),
+			s:    "i=42",
+		},
+		{
+			name: "four byte",
+			e:    NewFourByteExpandedNodeID(1, 4242),
+			s:    "ns=1;i=4242",
+		},
+		{
+			name: "with NamespaceURI",
+			e:    NewExpandedNodeID(true, false, NewFourByteNodeID(1, 42), "http://foo", 0),
+			s:    "nsu=http://foo;i=42",
+		},
+		{
+			name: "with empty NamespaceURI",
+			e:    NewExpandedNodeID(true, false, NewTwoByteNodeID(42), "", 0),
+			s:    "nsu=;i=42",
+		},
+		{
+			name: "with ServerIndex",
+			e:    NewExpandedNodeID(false, true, NewStringNodeID(2, "foo"), "", 3),
+			s:    "svr=3;ns=2;s=foo",
+		},
+		{
+			name: "with NamespaceURI and ServerIndex",
+			e:    NewExpandedNodeID(true, true, NewTwoByteNodeID(42), "http://foo", 1),
+			s:    "svr=1;nsu=http://foo;i=42",
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got, want := c.e.String(), c.s; got != want {
+				t.Fatalf("got %q want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestParseExpandedNodeID(t *testing.T) {
+	cases := []struct {
+		s   string
+		e   *ExpandedNodeID
+		err error
+	}{
+		// happy flows
+		{s: "i=1", e: NewTwoByteExpandedNodeID(1)},
+		{s: "ns=1;i=2", e: NewFourByteExpandedNodeID(1, 2)},
+		{s: "ns=2;s=MyTag", e: NewExpandedNodeID(false, false, NewStringNodeID(2, "MyTag"), "", 0)},
+		{s: "nsu=http://example.org/UA/;s=MyTag", e: NewExpandedNodeID(true, false, NewStringNodeID(0, "MyTag"), "http://example.org/UA/", 0)},
+		{s: "svr=1;ns=1;i=2", e: NewExpandedNodeID(false, true, NewFourByteNodeID(1, 2), "", 1)},
+		{s: "svr=1;nsu=http://example.org/UA/;s=MyTag", e: NewExpandedNodeID(true, true, NewStringNodeID(0, "MyTag"), "http://example.org/UA/", 1)},
+		{s: "svr=4294967295;nsu=;i=42", e: NewExpandedNodeID(true, true, NewTwoByteNodeID(42), "", 4294967295)},
+		{s: "ns=1;b=3q2+7w==", e: NewExpandedNodeID(false, false, NewOpaqueNodeID(1, []byte{0xde, 0xad, 0xbe, 0xef}), "", 0)},
+		{s: "nsu=http://example.org/UA/;b=3q2+7w==", e: NewExpandedNodeID(true, false, NewOpaqueNodeID(0, []byte{0xde, 0xad, 0xbe, 0xef}), "http://example.org/UA/", 0)},
+
+		// error flows
+		{s: "", err: errors.New("invalid expanded node id: ")},
+		{s: "svr=1", err: errors.New("invalid expanded node id: svr=1")},
+		{s: "svr=abc;i=1", err: errors.New("invalid server index: svr=abc;i=1")},
+		{s: "svr=-1;i=1", err: errors.New("invalid server index: svr=-1;i=1")},
+		{s: "svr=4294967296;i=1", err: errors.New("invalid server index: svr=4294967296;i=1")},
+		{s: "nsu=http://foo", err: errors.New("invalid expanded node id: nsu=http://foo")},
+		{s: "svr=1;nsu=http://foo;", err: errors.New("invalid expanded node id: svr=1;nsu=http://foo;")},
+		{s: "ns=1;i=2x", err: errors.New("invalid expanded node id: ns=1;i=2x: invalid numeric id: ns=1;i=2x")},
+	}
+
+	for _, c := range cases {
+		t.Run(c.s, func(t *testing.T) {
+			e, err := ParseExpandedNodeID(c.s)
+			if got, want := err, c.err; !reflect.DeepEqual(got, want) {
+				t.Fatalf("got error %v want %v", got, want)
+			}
+			if got, want := e, c.e; !reflect.DeepEqual(got, want) {
+				t.Fatalf("\ngot  %#v\nwant %#v", got, want)
+			}
+			if c.err != nil {
+				return
+			}
+			if got, want := e.String(), c.s; got != want {
+				t.Fatalf("got string %q want %q", got, want)
+			}
+		})
+	}
+}
+
+func TestExpandedNodeIDEqual(t *testing.T) {
+	cases := []struct {
+		name string
+		a, b *ExpandedNodeID
+		eq   bool
+	}{
+		{
+			name: "nil",
+			eq:   true,
+		},
+		{
+			name: "nil and non-nil",
+			a:    NewTwoByteExpandedNodeID(1),
+			eq:   false,
+		},
+		{
+			name: "same",
+			a:    NewFourByteExpandedNodeID(1, 2),
+			b:    NewFourByteExpandedNodeID(1, 2),
+			eq:   true,
+		},
+		{
+			name: "different encodings",
+			a:    NewTwoByteExpandedNodeID(1),
+			b:    NewExpandedNodeID(false, false, NewNumericNodeID(0, 1), "", 0),
+			eq:   true,
+		},
+		{
+			name: "different namespace",
+			a:    NewFourByteExpandedNodeID(1, 2),
+			b:    NewFourByteExpandedNodeID(2, 2),
+			eq:   false,
+		},
+		{
+			name: "same URI different namespace",
+			a:    NewExpandedNodeID(true, false, NewFourByteNodeID(1, 2), "http://foo", 0),
+			b:    NewExpandedNodeID(true, false, NewFourByteNodeID(3, 2), "http://foo", 0),
+			eq:   true,
+		},
+		{
+			name: "different URI",
+			a:    NewExpandedNodeID(true, false, NewFourByteNodeID(1, 2), "http://foo", 0),
+			b:    NewExpandedNodeID(true, false, NewFourByteNodeID(1, 2), "http://bar", 0),
+			eq:   false,
+		},
+		{
+			name: "URI and index",
+			a:    NewExpandedNodeID(true, false, NewFourByteNodeID(0, 2), "http://foo", 0),
+			b:    NewFourByteExpandedNodeID(1, 2),
+			eq:   false,
+		},
+		{
+			name: "empty URI and index",
+			a:    NewExpandedNodeID(true, false, NewFourByteNodeID(1, 2), "", 0),
+			b:    NewFourByteExpandedNodeID(1, 2),
+			eq:   true,
+		},
+		{
+			name: "zero ServerIndex",
+			a:    NewExpandedNodeID(false, true, NewTwoByteNodeID(1), "", 0),
+			b:    NewTwoByteExpandedNodeID(1),
+			eq:   true,
+		},
+		{
+			name: "different ServerIndex",
+			a:    NewExpandedNodeID(false, true, NewTwoByteNodeID(1), "", 1),
+			b:    NewExpandedNodeID(false, true, NewTwoByteNodeID(1), "", 2),
+			eq:   false,
+		},
+		{
+			name: "string",
+			a:    NewExpandedNodeID(false, false, NewStringNodeID(1, "foo"), "", 0),
+			b:    NewExpandedNodeID(false, false, NewStringNodeID(1, "foo"), "", 0),
+			eq:   true,
+		},
+		{
+			name: "string and opaque",
+			a:    NewExpandedNodeID(false, false, NewStringNodeID(1, "foo"), "", 0),
+			b:    NewExpandedNodeID(false, false, NewOpaqueNodeID(1, []byte("foo")), "", 0),
+			eq:   false,
+		},
+		{
+			name: "guid",
+			a:    NewExpandedNodeID(false, false, NewGUIDNodeID(1, "AAAABBBB-CCDD-EEFF-0101-0123456789AB"), "", 0),
+			b:    NewExpandedNodeID(false, false, NewGUIDNodeID(1, "aaaabbbbccddeeff01010123456789ab"), "", 0),
+			eq:   true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got, want := c.a.Equal(c.b), c.eq; got != want {
+				t.Fatalf("got %v want %v", got, want)
+			}
+			if got, want := c.b.Equal(c.a), c.eq; got != want {
+				t.Fatalf("reverse: got %v want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestExpandedNodeIDDecodeInvalid(t *testing.T) {
+	cases := []struct {
+		name string
+		b    []byte
+	}{
+		{
+			name: "NodeID",
+			b:    []byte{0x01, 0x00},
+		},
+		{
+			name: "NamespaceURI missing",
+			b:    []byte{0x80, 0xff},
+		},
+		{
+			name: "NamespaceURI too short",
+			b:    []byte{0x80, 0xff, 0x06, 0x00, 0x00, 0x00, 0x66, 0x6f},
+		},
+		{
+			name: "invalid type",
+			b:    []byte{0x0f, 0xff, 0x00, 0x00},
+		},
+		{
+			name: "invalid type with flags",
+			b:    []byte{0xcf, 0xff, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00},
+		},
+		{
+			name: "ServerIndex missing",
+			b:    []byte{0x40, 0xff},
+		},
+		{
+			name: "both flags truncated after NamespaceURI",
+			b: []byte{
+				0xc0, 0xff, 0x06, 0x00, 0x00, 0x00, 0x66, 0x6f,
+				0x6f, 0x62, 0x61, 0x72,
+			},
+		},
+		{
+			name: "both flags with short ServerIndex",
+			b: []byte{
+				0xc0, 0xff, 0x06, 0x00, 0x00, 0x00, 0x66, 0x6f,
+				0x6f, 0x62, 0x61, 0x72, 0x00, 0x80, 0x00,
+			},
+		},
+		{
+			name: "ServerIndex too short",
+			b:    []byte{0x40, 0xff, 0x00, 0x80, 0x00},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if _, err := DecodeExpandedNodeID(c.b); err == nil {
+				t.Fatal("got nil want error")
+			}
+			if _, err := ReadExpandedNodeID(bytes.NewReader(c.b)); err == nil {
+				t.Fatal("got nil want error from reader")
+			}
+		})
+	}
+}
+
+func TestExpandedNodeIDDecodeFromBytesStrict(t *testing.T) {
+	cases := []struct {
+		name       string
+		b          []byte
+		lenientURI *String
+		strictErr  bool
+	}{
+		{
+			name:       "null NamespaceURI",
+			b:          []byte{0x80, 0x01, 0xff, 0xff, 0xff, 0xff},
+			lenientURI: &String{Length: -1},
+			strictErr:  true,
+		},
+		{
+			name:       "empty NamespaceURI",
+			b:          []byte{0x80, 0x01, 0x00, 0x00, 0x00, 0x00},
+			lenientURI: &String{Length: 0},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			t.Run("lenient", func(t *testing.T) {
+				e := &ExpandedNodeID{}
+				if err := e.DecodeFromBytes(c.b); err != nil {
+					t.Fatal(err)
+				}
+				if got, want := e.NamespaceURI, c.lenientURI; !reflect.DeepEqual(got, want) {
+					t.Fatalf("got %#v want %#v", got, want)
+				}
+			})
+			t.Run("strict", func(t *testing.T) {
+				e := &ExpandedNodeID{}
+				err := e.DecodeFromBytesStrict(c.b)
+				if got, want := err != nil, c.strictErr; got != want {
+					t.Fatalf("got error %v want error %v", err, want)
+				}
+				if err == nil && !reflect.DeepEqual(e.NamespaceURI, c.lenientURI) {
+					t.Fatalf("got %#v want %#v", e.NamespaceURI, c.lenientURI)
+				}
+			})
+		})
+	}
+}
+
+func TestExpandedNodeIDDecodeFromBytesN(t *testing.T) {
+	b := []byte{
+		// TwoByte
+		0x00, 0xff,
+		// With ServerIndex
+		0x40, 0xff, 0x00, 0x80, 0x00, 0x00,
+		// With NamespaceURI
+		0x80, 0xff, 0x06, 0x00, 0x00, 0x00, 0x66, 0x6f,
+		0x6f, 0x62, 0x61, 0x72,
+	}
+	want := []*ExpandedNodeID{
+		NewTwoByteExpandedNodeID(0xff),
+		NewExpandedNodeID(false, true, NewTwoByteNodeID(0xff), "", 32768),
+		NewExpandedNodeID(true, false, NewTwoByteNodeID(0xff), "foobar", 0),
+	}
+
+	var got []*ExpandedNodeID
+	for len(b) > 0 {
+		e := &ExpandedNodeID{}
+		n, err := e.DecodeFromBytesN(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if n != e.Len() {
+			t.Fatalf("got %d bytes consumed want %d", n, e.Len())
+		}
+		got = append(got, e)
+		b = b[n:]
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("\ngot  %#v\nwant %#v", got, want)
+	}
+}
+
+func TestExpandedNodeIDEncoder(t *testing.T) {
+	want := []*ExpandedNodeID{
+		NewTwoByteExpandedNodeID(0xff),
+		NewExpandedNodeID(false, true, NewTwoByteNodeID(0xff), "", 32768),
+		NewExpandedNodeID(true, false, NewTwoByteNodeID(0xff), "foobar", 0),
+		NewExpandedNodeID(true, true, NewStringNodeID(2, "foo.bar"), "http://example.com", 3),
+		NewGUIDExpandedNodeID(4660, "AAAABBBB-CCDD-EEFF-0101-0123456789AB"),
+	}
+
+	enc := GetEncoder()
+	defer PutEncoder(enc)
+	var b []byte
+	for _, e := range want {
+		if err := e.EncodeTo(enc); err != nil {
+			t.Fatal(err)
+		}
+		s, err := e.Serialize()
+		if err != nil {
+			t.Fatal(err)
+		}
+		b = append(b, s...)
+	}
+	if got := enc.Bytes(); !bytes.Equal(got, b) {
+		t.Fatalf("got %x want %x", got, b)
+	}
+
+	var got []*ExpandedNodeID
+	dec := NewDecoder(enc.Bytes())
+	for len(dec.Remaining()) > 0 {
+		e := &ExpandedNodeID{}
+		if err := e.DecodeFrom(dec); err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, e)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("\ngot  %#v\nwant %#v", got, want)
+	}
+
+	// the invalid one is not written.
+	l := enc.Len()
+	if err := (&ExpandedNodeID{}).EncodeTo(enc); err == nil {
+		t.Fatal("got nil want error")
+	}
+	if enc.Len() != l {
+		t.Fatalf("got %d bytes want %d", enc.Len(), l)
+	}
+}
+
+func TestExpandedNodeIDValidate(t *testing.T) {
+	cases := []struct {
+		name string
+		e    *ExpandedNodeID
+		err  bool
+	}{
+		{
+			name: "valid",
+			e:    NewExpandedNodeID(true, true, NewTwoByteNodeID(1), "foo", 1),
+		},
+		{
+			name: "zero value",
+			e:    &ExpandedNodeID{},
+			err:  true,
+		},
+		{
+			name: "NamespaceURI without flag",
+			e:    &ExpandedNodeID{NodeID: NewTwoByteNodeID(1), NamespaceURI: NewString("foo")},
+			err:  true,
+		},
+		{
+			name: "NamespaceURI flag without NamespaceURI",
+			e: func() *ExpandedNodeID {
+				e := NewTwoByteExpandedNodeID(1)
+				e.NodeID.SetURIFlag()
+				return e
+			}(),
+			err: true,
+		},
+		{
+			name: "ServerIndex without flag",
+			e:    &ExpandedNodeID{NodeID: NewTwoByteNodeID(1), ServerIndex: 1},
+			err:  true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got, want := c.e.Validate() != nil, c.err; got != want {
+				t.Fatalf("got error %v want %v", got, want)
+			}
+			if _, err := c.e.Serialize(); (err != nil) != c.err {
+				t.Fatalf("got serialize error %v", err)
+			}
+		})
+	}
+}
+
+func TestExpandedNodeIDJSON(t *testing.T) {
+	cases := []struct {
+		name string
+		e    *ExpandedNodeID
+		json string
+	}{
+		{
+			name: "numeric",
+			e:    NewTwoByteExpandedNodeID(42),
+			json: `{"Id":42}`,
+		},
+		{
+			name: "numeric with namespace",
+			e:    NewExpandedNodeID(false, false, NewNumericNodeID(2, 70000), "", 0),
+			json: `{"Id":70000,"Namespace":2}`,
+		},
+		{
+			name: "string",
+			e:    NewExpandedNodeID(false, false, NewStringNodeID(1, "foo"), "", 0),
+			json: `{"IdType":1,"Id":"foo","Namespace":1}`,
+		},
+		{
+			name: "guid",
+			e:    NewExpandedNodeID(false, false, NewGUIDNodeID(1, "AAAABBBB-CCDD-EEFF-0101-0123456789AB"), "", 0),
+			json: `{"IdType":2,"Id":"AAAABBBB-CCDD-EEFF-0101-0123456789AB","Namespace":1}`,
+		},
+		{
+			name: "opaque",
+			e:    NewExpandedNodeID(false, false, NewOpaqueNodeID(1, []byte{0xde, 0xad, 0xbe, 0xef}), "", 0),
+			json: `{"IdType":3,"Id":"3q2+7w==","Namespace":1}`,
+		},
+		{
+			name: "with NamespaceURI",
+			e:    NewExpandedNodeID(true, false, NewStringNodeID(0, "foo"), "http://foo", 0),
+			json: `{"IdType":1,"Id":"foo","Namespace":"http://foo"}`,
+		},
+		{
+			name: "with NamespaceURI and ServerIndex",
+			e:    NewExpandedNodeID(true, true, NewTwoByteNodeID(42), "http://foo", 3),
+			json: `{"Id":42,"Namespace":"http://foo","ServerUri":3}`,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			b, err := json.Marshal(c.e)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := string(b), c.json; got != want {
+				t.Fatalf("got %s want %s", got, want)
+			}
+
+			e := &ExpandedNodeID{}
+			if err := json.Unmarshal(b, e); err != nil {
+				t.Fatal(err)
+			}
+			if !e.Equal(c.e) {
+				t.Fatalf("got %s want %s", e, c.e)
+			}
+		})
+	}
+}
+
+func TestExpandedNodeIDXML(t *testing.T) {
+	type value struct {
+		XMLName xml.Name        `xml:"Value"`
+		ID      *ExpandedNodeID `xml:"ExpandedNodeId"`
+	}
+
+	cases := []struct {
+		name string
+		e    *ExpandedNodeID
+		xml  string
+	}{
+		{
+			name: "numeric",
+			e:    NewExpandedNodeID(false, false, NewNumericNodeID(0, 2253), "", 0),
+			xml: `<Value>
+  <uax:ExpandedNodeId xmlns:uax="http://opcfoundation.org/UA/2008/02/Types.xsd">
+    <uax:Identifier>i=2253</uax:Identifier>
+  </uax:ExpandedNodeId>
+</Value>`,
+		},
+		{
+			name: "string with namespace",
+			e:    NewStringExpandedNodeID(1, "Demo.Static.Scalar"),
+			xml: `<Value>
+  <ExpandedNodeId xmlns="http://opcfoundation.org/UA/2008/02/Types.xsd">
+    <Identifier>ns=1;s=Demo.Static.Scalar</Identifier>
+  </ExpandedNodeId>
+</Value>`,
+		},
+		{
+			name: "with NamespaceURI and ServerIndex",
+			e:    NewExpandedNodeID(true, true, NewNumericNodeID(0, 15001), "http://opcfoundation.org/UA/DI/", 2),
+			xml: `<Value>
+  <ExpandedNodeId xmlns="http://opcfoundation.org/UA/2008/02/Types.xsd">
+    <Identifier>svr=2;nsu=http://opcfoundation.org/UA/DI/;i=15001</Identifier>
+  </ExpandedNodeId>
+</Value>`,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			var v value
+			if err := xml.Unmarshal([]byte(c.xml), &v); err != nil {
+				t.Fatal(err)
+			}
+			if !v.ID.Equal(c.e) {
+				t.Fatalf("got %s want %s", v.ID, c.e)
+			}
+
+			b, err := xml.Marshal(v)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var w value
+			if err := xml.Unmarshal(b, &w); err != nil {
+				t.Fatal(err)
+			}
+			if !w.ID.Equal(c.e) {
+				t.Fatalf("got %s want %s after round trip of %s", w.ID, c.e, b)
+			}
+		})
+	}
+
+	t.Run("empty", func(t *testing.T) {
+		var v value
+		if err := xml.Unmarshal([]byte(`<Value><ExpandedNodeId></ExpandedNodeId></Value>`), &v); err != nil {
+			t.Fatal(err)
+		}
+		if !v.ID.IsNull() {
+			t.Fatalf("got %s want null", v.ID)
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		var v value
+		if err := xml.Unmarshal([]byte(`<Value><ExpandedNodeId><Identifier>nsu=foo</Identifier></ExpandedNodeId></Value>`), &v); err == nil {
+			t.Fatal("got nil want error")
+		}
+	})
+}
+
+func TestExpandedNodeIDSerializeToBuf(t *testing.T) {
+	ids := []*ExpandedNodeID{
+		NewTwoByteExpandedNodeID(42),
+		NewExpandedNodeID(true, true, NewStringNodeID(2, "foo.bar"), "http://example.com", 3),
+	}
+
+	var buf bytes.Buffer
+	buf.Write([]byte{0xde, 0xad})
+	want := []byte{0xde, 0xad}
+	for _, e := range ids {
+		if err := e.SerializeToBuf(&buf); err != nil {
+			t.Fatal(err)
+		}
+		b, err := e.Serialize()
+		if err != nil {
+			t.Fatal(err)
+		}
+		want = append(want, b...)
+	}
+	if got := buf.Bytes(); !bytes.Equal(got, want) {
+		t.Fatalf("got %x want %x", got, want)
+	}
+
+	if err := (&ExpandedNodeID{}).SerializeToBuf(&buf); err == nil {
+		t.Fatal("got nil want error")
+	}
+	if got := buf.Bytes(); !bytes.Equal(got, want) {
+		t.Fatalf("buffer modified on error: got %x want %x", got, want)
+	}
+}
+
+func TestExpandedNodeIDSerializeToBufAllocs(t *testing.T) {
+	e := NewExpandedNodeID(true, true, NewStringNodeID(2, "foo.bar"), "http://example.com", 3)
+	buf := bytes.NewBuffer(make([]byte, 0, 64))
+
+	allocs := testing.AllocsPerRun(100, func() {
+		buf.Reset()
+		if err := e.SerializeToBuf(buf); err != nil {
+			t.Fatal(err)
+		}
+	})
+	if allocs != 0 {
+		t.Fatalf("got %v allocs want 0", allocs)
+	}
+}
+
+func BenchmarkExpandedNodeIDSerialize(b *testing.B) {
+	e := NewExpandedNodeID(true, true, NewStringNodeID(2, "foo.bar"), "http://example.com", 3)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := e.Serialize(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkExpandedNodeIDSerializeToBuf(b *testing.B) {
+	e := NewExpandedNodeID(true, true, NewStringNodeID(2, "foo.bar"), "http://example.com", 3)
+	buf := bytes.NewBuffer(make([]byte, 0, 64))
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		buf.Reset()
+		if err := e.SerializeToBuf(buf); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkExpandedNodeIDEncode(b *testing.B) {
+	e := NewExpandedNodeID(true, true, NewStringNodeID(2, "foo.bar"), "http://example.com", 3)
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		enc := GetEncoder()
+		if err := e.EncodeTo(enc); err != nil {
+			b.Fatal(err)
+		}
+		PutEncoder(enc)
+	}
+}
+
+func BenchmarkExpandedNodeIDDecode(b *testing.B) {
+	buf, err := NewExpandedNodeID(true, true, NewStringNodeID(2, "foo.bar"), "http://example.com", 3).Serialize()
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := DecodeExpandedNodeID(buf); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestExpandedNodeIDNilNodeID(t *testing.T) {
+	e := &ExpandedNodeID{}
+	if e.HasNamespaceURI() || e.HasServerIndex() {
+		t.Fatal("zero value should not have flags")
+	}
+	if _, err := e.Serialize(); err == nil {
+		t.Fatal("got nil want error")
+	}
+	if err := e.SerializeTo(make([]byte, 16)); err == nil {
+		t.Fatal("got nil want error")
+	}
+}
+
+func TestExpandedNodeIDSetters(t *testing.T) {
+	e := NewTwoByteExpandedNodeID(0xff)
+
+	steps := []struct {
+		name string
+		f    func()
+		b    []byte
+	}{
+		{
+			name: "SetServerIndex",
+			f:    func() { e.SetServerIndex(32768) },
+			b:    []byte{0x40, 0xff, 0x00, 0x80, 0x00, 0x00},
+		},
+		{
+			name: "SetNamespaceURI",
+			f:    func() { e.SetNamespaceURI("foobar") },
+			b: []byte{
+				0xc0, 0xff, 0x06, 0x00, 0x00, 0x00, 0x66, 0x6f,
+				0x6f, 0x62, 0x61, 0x72, 0x00, 0x80, 0x00, 0x00,
+			},
+		},
+		{
+			name: "ClearServerIndex",
+			f:    func() { e.ClearServerIndex() },
+			b: []byte{
+				0x80, 0xff, 0x06, 0x00, 0x00, 0x00, 0x66, 0x6f,
+				0x6f, 0x62, 0x61, 0x72,
+			},
+		},
+		{
+			name: "ClearNamespaceURI",
+			f:    func() { e.ClearNamespaceURI() },
+			b:    []byte{0x00, 0xff},
+		},
+	}
+	for _, s := range steps {
+		s.f()
+		b, err := e.Serialize()
+		if err != nil {
+			t.Fatalf("%s: %s", s.name, err)
+		}
+		if got, want := e.Len(), len(s.b); got != want {
+			t.Fatalf("%s: got length %d want %d", s.name, got, want)
+		}
+		if got, want := b, s.b; !bytes.Equal(got, want) {
+			t.Fatalf("%s: got %x want %x", s.name, got, want)
+		}
+	}
+}
+
+func TestExpandedNodeIDSettersNilNodeID(t *testing.T) {
+	// the setters do not panic on the ExpandedNodeID without NodeID, which has no flags.
+	e := &ExpandedNodeID{}
+	e.SetServerIndex(1)
+	e.SetNamespaceURI("foobar")
+	if e.HasServerIndex() || e.HasNamespaceURI() {
+		t.Error("got flags set without NodeID")
+	}
+	e.ClearServerIndex()
+	e.ClearNamespaceURI()
+	if e.ServerIndex != 0 || e.NamespaceURI != nil {
+		t.Errorf("got ServerIndex %d and NamespaceURI %v after clearing", e.ServerIndex, e.NamespaceURI)
+	}
+}
+
+func TestReadExpandedNodeID(t *testing.T) {
+	inputs := [][]byte{
+		{0x00, 0xff},
+		{0x01, 0x01, 0xfe, 0xca},
+		{0x02, 0x0a, 0x00, 0xef, 0xbe, 0xad, 0xde},
+		{
+			0x04, 0x34, 0x12, 0xbb, 0xbb, 0xaa, 0xaa, 0xdd,
+			0xcc, 0xff, 0xee, 0xab, 0x89, 0x67, 0x45, 0x23,
+			0x01, 0x01, 0x01,
+		},
+		{0x05, 0x00, 0x80, 0x04, 0x00, 0x00, 0x00, 0xde, 0xad, 0xbe, 0xef},
+		{0x05, 0x00, 0x80, 0xff, 0xff, 0xff, 0xff},
+		{
+			0xc3, 0x01, 0x00, 0x03, 0x00, 0x00, 0x00, 0x66,
+			0x6f, 0x6f, 0x03, 0x00, 0x00, 0x00, 0x62, 0x61,
+			0x72, 0x00, 0x80, 0x00, 0x00,
+		},
+		{0x80, 0xff, 0xff, 0xff, 0xff, 0xff},
+		{0x80, 0xff, 0x00, 0x00, 0x00, 0x00},
+
+		// errors
+		{0x0f, 0xff},
+		{0x03, 0x01, 0x00, 0x03, 0x00, 0x00, 0x00, 0x66},
+		{0x03, 0x01, 0x00, 0xff, 0xff, 0xff, 0xff, 0x66},
+		{0x80, 0xff, 0x06, 0x00, 0x00, 0x00, 0x66, 0x6f},
+		{0x40, 0xff, 0x00, 0x80},
+	}
+	for _, b := range inputs {
+		t.Run(fmt.Sprintf("%x", b), func(t *testing.T) {
+			want, wantErr := DecodeExpandedNodeID(b)
+
+			// append trailing bytes which must not be consumed
+			in := append([]byte{}, b...)
+			if wantErr == nil {
+				in = append(in, 0xaa, 0xbb)
+			}
+			r := bytes.NewReader(in)
+			got, err := ReadExpandedNodeID(r)
+			if (err != nil) != (wantErr != nil) {
+				t.Fatalf("got error %v want %v", err, wantErr)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Fatalf("\ngot  %#v\nwant %#v", got, want)
+			}
+			if err == nil && r.Len() != 2 {
+				t.Fatalf("got %d trailing bytes want 2", r.Len())
+			}
+		})
+	}
+}
+
+// zeroReader reads the zeros endlessly.
+type zeroReader struct{}
+
+func (zeroReader) Read(b []byte) (int, error) {
+	for i := range b {
+		b[i] = 0
+	}
+	return len(b), nil
+}
+
+func TestReadExpandedNodeIDTooLong(t *testing.T) {
+	inputs := [][]byte{
+		// String identifier
+		{0x03, 0x01, 0x00, 0xff, 0xff, 0xff, 0x7f},
+		// ByteString identifier
+		{0x05, 0x01, 0x00, 0x00, 0x00, 0x01, 0x00},
+		// NamespaceURI
+		{0x80, 0xff, 0xff, 0xff, 0xff, 0x7f},
+	}
+	for _, b := range inputs {
+		t.Run(fmt.Sprintf("%x", b), func(t *testing.T) {
+			// the bytes claimed are not read from the stream which has enough for them.
+			r := io.MultiReader(bytes.NewReader(b), zeroReader{})
+			if _, err := ReadExpandedNodeID(r); err == nil {
+				t.Fatal("got nil want error")
+			}
+		})
+	}
+}
+
+func TestExpandedNodeIDURI(t *testing.T) {
+	cases := []struct {
+		name     string
+		b        []byte
+		uri      string
+		hasValue bool
+	}{
+		{
+			name: "no NamespaceURI",
+			b:    []byte{0x00, 0xff},
+		},
+		{
+			name: "null",
+			b:    []byte{0x80, 0xff, 0xff, 0xff, 0xff, 0xff},
+		},
+		{
+			name:     "empty",
+			b:        []byte{0x80, 0xff, 0x00, 0x00, 0x00, 0x00},
+			hasValue: true,
+		},
+		{
+			name:     "populated",
+			b:        []byte{0x80, 0xff, 0x03, 0x00, 0x00, 0x00, 0x66, 0x6f, 0x6f},
+			uri:      "foo",
+			hasValue: true,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			e, err := DecodeExpandedNodeID(c.b)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := e.URI(), c.uri; got != want {
+				t.Fatalf("got URI %q want %q", got, want)
+			}
+			if got, want := e.HasURIValue(), c.hasValue; got != want {
+				t.Fatalf("got HasURIValue %v want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestExpandedNodeIDSerializeToShortBuffer(t *testing.T) {
+	e := NewExpandedNodeID(true, true, NewTwoByteNodeID(0xff), "foobar", 1)
+	if err := e.SerializeTo(make([]byte, e.Len()-1)); err == nil {
+		t.Fatal("got nil want error")
+	}
+
+	// NamespaceURI with a Length that exceeds its Value
+	e.NamespaceURI = &String{Length: 10, Value: []byte("foo")}
+	if _, err := e.Serialize(); err == nil {
+		t.Fatal("got nil want error")
+	}
+	if err := e.SerializeTo(make([]byte, 64)); err == nil {
+		t.Fatal("got nil want error")
+	}
+}
+
+func TestExpandedNodeIDToNodeID(t *testing.T) {
+	nsTable := map[string]uint16{
+		"http://opcfoundation.org/UA/": 0,
+		"http://foo":                   2,
+		"http://bar":                   300,
+	}
+
+	cases := []struct {
+		name string
+		e    *ExpandedNodeID
+		n    *NodeID
+		err  error
+	}{
+		{
+			name: "without NamespaceURI",
+			e:    NewFourByteExpandedNodeID(1, 2),
+			n:    NewFourByteNodeID(1, 2),
+		},
+		{
+			name: "with ServerIndex",
+			e:    NewExpandedNodeID(false, true, NewStringNodeID(1, "foo"), "", 3),
+			n:    NewStringNodeID(1, "foo"),
+		},
+		{
+			name: "string",
+			e:    NewStringExpandedNodeIDWithURI("http://foo", "foo"),
+			n:    NewStringNodeID(2, "foo"),
+		},
+		{
+			name: "namespace zero",
+			e:    NewExpandedNodeID(true, false, NewFourByteNodeID(5, 2), "http://opcfoundation.org/UA/", 0),
+			n:    NewFourByteNodeID(0, 2),
+		},
+		{
+			name: "two byte to four byte",
+			e:    NewExpandedNodeID(true, false, NewTwoByteNodeID(42), "http://foo", 0),
+			n:    NewFourByteNodeID(2, 42),
+		},
+		{
+			name: "four byte to numeric",
+			e:    NewExpandedNodeID(true, false, NewFourByteNodeID(0, 42), "http://bar", 0),
+			n:    NewNumericNodeID(300, 42),
+		},
+		{
+			name: "unknown NamespaceURI",
+			e:    NewStringExpandedNodeIDWithURI("http://baz", "foo"),
+			err:  errors.New("unknown namespace uri: http://baz"),
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			before := c.e.String()
+			n, err := c.e.ToNodeID(nsTable)
+			if got, want := err, c.err; !reflect.DeepEqual(got, want) {
+				t.Fatalf("got error %v want %v", got, want)
+			}
+			if got, want := n, c.n; !reflect.DeepEqual(got, want) {
+				t.Fatalf("\ngot  %#v\nwant %#v", got, want)
+			}
+			if got, want := c.e.String(), before; got != want {
+				t.Fatalf("receiver modified: got %s want %s", got, want)
+			}
+		})
+	}
+}
+
+func TestNodeIDToExpandedNodeID(t *testing.T) {
+	nsArray := []string{"http://opcfoundation.org/UA/", "http://foo", "http://bar"}
+
+	cases := []struct {
+		name string
+		n    *NodeID
+		e    *ExpandedNodeID
+	}{
+		{
+			name: "namespace zero",
+			n:    NewFourByteNodeID(0, 2),
+			e:    &ExpandedNodeID{NodeID: NewFourByteNodeID(0, 2)},
+		},
+		{
+			name: "string",
+			n:    NewStringNodeID(2, "foo"),
+			e:    NewExpandedNodeID(true, false, NewStringNodeID(0, "foo"), "http://bar", 0),
+		},
+		{
+			name: "unknown index",
+			n:    NewNumericNodeID(3, 42),
+			e:    &ExpandedNodeID{NodeID: NewNumericNodeID(3, 42)},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			before := c.n.String()
+			e := c.n.ToExpandedNodeID(nsArray)
+			if !e.Equal(c.e) {
+				t.Fatalf("got %s want %s", e, c.e)
+			}
+			if got, want := c.n.String(), before; got != want {
+				t.Fatalf("receiver modified: got %s want %s", got, want)
+			}
+
+			// the ExpandedNodeID is converted back to the NodeID.
+			n, err := e.ToNodeID(nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := n.String(), c.n.String(); got != want {
+				t.Fatalf("got %s want %s", got, want)
+			}
+		})
+	}
+}
+
+func TestExpandedNodeIDResolvedNamespaceIndex(t *testing.T) {
+	e := NewStringExpandedNodeIDWithURI("http://foo", "foo")
+	want, err := e.Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := e.ToNodeID(map[string]uint16{"http://foo": 2}); err != nil {
+		t.Fatal(err)
+	}
+	if e.ResolvedNamespaceIndex == nil || *e.ResolvedNamespaceIndex != 2 {
+		t.Fatalf("got %v want 2", e.ResolvedNamespaceIndex)
+	}
+
+	got, err := e.Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Fatalf("got %x want %x", got, want)
+	}
+	if !e.Equal(NewStringExpandedNodeIDWithURI("http://foo", "foo")) {
+		t.Fatal("should equal regardless of the resolved index")
+	}
+
+	// the cached index is used without nsTable.
+	n, err := e.ToNodeID(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := n, NewStringNodeID(2, "foo"); !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %s want %s", got, want)
+	}
+
+	e.SetNamespaceURI("http://bar")
+	if e.ResolvedNamespaceIndex != nil {
+		t.Fatalf("got %v want nil after SetNamespaceURI", *e.ResolvedNamespaceIndex)
+	}
+
+	ns := uint16(5)
+	d := &ExpandedNodeID{ResolvedNamespaceIndex: &ns}
+	if err := d.DecodeFromBytes(want); err != nil {
+		t.Fatal(err)
+	}
+	if d.ResolvedNamespaceIndex != nil {
+		t.Fatalf("got %v want nil after decoding", *d.ResolvedNamespaceIndex)
+	}
+}
+
+func TestExpandedNodeIDClone(t *testing.T) {
+	if got := (*ExpandedNodeID)(nil).Clone(); got != nil {
+		t.Fatalf("got %v want nil", got)
+	}
+
+	for _, e := range []*ExpandedNodeID{
+		NewExpandedNodeID(true, true, NewStringNodeID(1, "foo"), "http://foo", 3),
+		NewExpandedNodeID(true, false, NewOpaqueNodeID(1, []byte{0xde, 0xad}), "http://foo", 0),
+		NewExpandedNodeID(false, true, NewGUIDNodeID(1, "AAAABBBB-CCDD-EEFF-0101-0123456789AB"), "", 3),
+	} {
+		t.Run(e.String(), func(t *testing.T) {
+			orig, err := e.Serialize()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			c := e.Clone()
+			if !reflect.DeepEqual(c, e) {
+				t.Fatalf("\ngot  %#v\nwant %#v", c, e)
+			}
+
+			if c.NamespaceURI != nil {
+				c.NamespaceURI.Value[0] = 'x'
+			}
+			c.NodeID.SetNamespace(2)
+			c.NodeID.ClearIndexFlag()
+			c.NodeID.bid = append(c.NodeID.bid[:0], 'x')
+			if c.NodeID.gid != nil {
+				c.NodeID.gid.Data1 = 0
+			}
+			c.ServerIndex = 4
+
+			b, err := e.Serialize()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(b, orig) {
+				t.Fatalf("original modified: got %x want %x", b, orig)
+			}
+		})
+	}
+}
+
+func TestExpandedNodeIDGob(t *testing.T) {
+	want := []*ExpandedNodeID{
+		NewTwoByteExpandedNodeID(0xff),
+		NewStringExpandedNodeIDWithURI("http://foo", "foo"),
+		NewExpandedNodeID(true, true, NewGUIDNodeID(1, "AAAABBBB-CCDD-EEFF-0101-0123456789AB"), "http://foo", 3),
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(want); err != nil {
+		t.Fatal(err)
+	}
+	var got []*ExpandedNodeID
+	if err := gob.NewDecoder(&buf).Decode(&got); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("\ngot  %#v\nwant %#v", got, want)
+	}
+}
+
+func TestExpandedNodeIDIsNull(t *testing.T) {
+	e, err := DecodeExpandedNodeID([]byte{0x00, 0x00})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !e.IsNull() {
+		t.Fatal("decoded null should be null")
+	}
+	if !e.Equal(NewNullExpandedNodeID()) {
+		t.Fatal("decoded null should be equal to NewNullExpandedNodeID")
+	}
+
+	for _, e := range []*ExpandedNodeID{
+		NewTwoByteExpandedNodeID(1),
+		NewFourByteExpandedNodeID(1, 0),
+		NewStringExpandedNodeID(0, ""),
+		NewExpandedNodeID(false, true, NewTwoByteNodeID(0), "", 0),
+		NewExpandedNodeID(true, false, NewTwoByteNodeID(0), "http://foo", 0),
+	} {
+		if e.IsNull() {
+			t.Errorf("%s should not be null", e)
+		}
+	}
+}
+
+func TestNewExpandedNodeIDWithServer(t *testing.T) {
+	n := NewStringNodeID(1, "foo")
+	e := NewExpandedNodeIDWithServer(n, 2)
+	if !e.HasServerIndex() {
+		t.Fatal("HasServerIndex should be true")
+	}
+	if e.HasNamespaceURI() {
+		t.Fatal("HasNamespaceURI should be false")
+	}
+	b, err := e.Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(b), n.Len()+4; got != want {
+		t.Fatalf("got length %d want %d", got, want)
+	}
+}
+
+func TestExpandedNodeIDUnmarshalBinary(t *testing.T) {
+	want := NewExpandedNodeID(true, true, NewOpaqueNodeID(1, []byte{0xde, 0xad, 0xbe, 0xef}), "http://foo", 3)
+	b, err := want.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the bytes given are not referred to by the decoded value.
+	got := &ExpandedNodeID{}
+	if err := got.UnmarshalBinary(b); err != nil {
+		t.Fatal(err)
+	}
+	for i := range b {
+		b[i] = 0
+	}
+	if !got.Equal(want) {
+		t.Errorf("got %v want %v", got, want)
+	}
+}
@@ -6,14 +6,29 @@ package datatypes
 
 import (
 	"encoding/binary"
+	"fmt"
+	"sync"
 
 	"github.com/wmnsk/gopcua/errors"
 	"github.com/wmnsk/gopcua/id"
 )
 
+// EncodingMask of ExtensionObject, which tells how the body is encoded.
+//
+// Specification: Part 6, 5.2.2.15
+const (
+	ExtensionObjectEmpty  = 0x00
+	ExtensionObjectBinary = 0x01
+	ExtensionObjectXML    = 0x02
+)
+
 // ExtensionObject is encoded as sequence of bytes prefixed by the NodeId of its DataTypeEncoding
 // and the number of bytes encoded.
 //
+// The body encoded in binary is decoded into the ExtensionObjectValue registered with
+// RegisterExtensionObject or defined in this package for the TypeID. The others, including
+// the bodies encoded in XML, are decoded into RawExtensionObjectValue.
+//
 // Specification: Part 6, 5.2.2.15
 type ExtensionObject struct {
 	TypeID       *ExpandedNodeID
@@ -24,10 +39,17 @@ type ExtensionObject struct {
 
 // NewExtensionObject creates a new ExtensionObject from the ExtensionObjectValue given.
 func NewExtensionObject(mask uint8, extParam ExtensionObjectValue) *ExtensionObject {
+	return NewExtensionObjectWithTypeID(
+		mask, NewFourByteNodeID(0, uint16(extParam.Type())), extParam,
+	)
+}
+
+// NewExtensionObjectWithTypeID creates a new ExtensionObject from the ExtensionObjectValue given,
+// with the encoding NodeID typeID. It is used for the structures which are not defined in the
+// namespace 0, such as the ones registered with RegisterExtensionObject.
+func NewExtensionObjectWithTypeID(mask uint8, typeID *NodeID, extParam ExtensionObjectValue) *ExtensionObject {
 	e := &ExtensionObject{
-		TypeID: NewExpandedNodeID(
-			false, false, NewFourByteNodeID(0, uint16(extParam.Type())), "", 0,
-		),
+		TypeID:       NewExpandedNodeID(false, false, typeID, "", 0),
 		EncodingMask: mask,
 		Value:        extParam,
 	}
@@ -36,6 +58,14 @@ func NewExtensionObject(mask uint8, extParam ExtensionObjectValue) *ExtensionObj
 	return e
 }
 
+// NewNullExtensionObject creates a new ExtensionObject which has no body.
+func NewNullExtensionObject() *ExtensionObject {
+	return &ExtensionObject{
+		TypeID:       NewTwoByteExpandedNodeID(0),
+		EncodingMask: ExtensionObjectEmpty,
+	}
+}
+
 // DecodeExtensionObject decodes given bytes into ExtensionObject.
 func DecodeExtensionObject(b []byte) (*ExtensionObject, error) {
 	e := &ExtensionObject{}
@@ -47,6 +77,16 @@ func DecodeExtensionObject(b []byte) (*ExtensionObject, error) {
 
 // DecodeFromBytes decodes given bytes into ExtensionObject.
 func (e *ExtensionObject) DecodeFromBytes(b []byte) error {
+	return e.decode(b, 0)
+}
+
+// decode decodes given bytes into ExtensionObject nested in the Variants to depth.
+// The Variants in the body, e.g. the Value of LiteralOperand, are decoded at the same depth.
+func (e *ExtensionObject) decode(b []byte, depth int) error {
+	if depth > maxNestingDepth {
+		return errors.NewErrUnsupported(e, fmt.Sprintf("should not be nested deeper than %d", maxNestingDepth))
+	}
+
 	// type id
 	nodeID, err := DecodeExpandedNodeID(b)
 	if err != nil {
@@ -56,22 +96,36 @@ func (e *ExtensionObject) DecodeFromBytes(b []byte) error {
 	offset := e.TypeID.Len()
 
 	// encoding mask
+	if len(b[offset:]) < 1 {
+		return errors.NewErrTooShortToDecode(e, "should contain EncodingMask")
+	}
 	e.EncodingMask = b[offset]
 	offset++
 
+	// no body is encoded if the mask is 0x00.
+	if e.EncodingMask == ExtensionObjectEmpty {
+		e.Length = 0
+		e.Value = nil
+		return nil
+	}
+
+	if len(b[offset:]) < 4 {
+		return errors.NewErrTooShortToDecode(e, "should contain Length")
+	}
 	e.Length = int32(binary.LittleEndian.Uint32(b[offset : offset+4]))
 	offset += 4
+	if e.Length < 0 || len(b[offset:]) < int(e.Length) {
+		return errors.NewErrTooShortToDecode(e, "should contain the body of Length")
+	}
+	body := b[offset : offset+int(e.Length)]
 
 	// extension object parameter
-	var id int
-	node := e.TypeID.NodeID
-	switch node.Type() {
-	case TypeTwoByte, TypeFourByte, TypeNumeric:
-		id = node.IntID()
-	default:
-		return errors.NewErrInvalidType(e.TypeID.NodeID, "decode", "NodeID should be TwoByte, FourByte or Numeric")
+	val := e.newValue()
+	if x, ok := val.(nestedDecoder); ok {
+		err = x.decode(body, depth)
+	} else {
+		err = val.DecodeFromBytes(body)
 	}
-	val, err := DecodeExtensionObjectValue(b[offset:], int(id))
 	if err != nil {
 		return err
 	}
@@ -80,6 +134,32 @@ func (e *ExtensionObject) DecodeFromBytes(b []byte) error {
 	return nil
 }
 
+// newValue returns an empty ExtensionObjectValue to decode the body into,
+// which is RawExtensionObjectValue if the type is unknown or not implemented.
+func (e *ExtensionObject) newValue() ExtensionObjectValue {
+	node := e.TypeID.NodeID
+	var typ int
+	switch node.Type() {
+	case TypeTwoByte, TypeFourByte, TypeNumeric:
+		if node.Namespace() == 0 {
+			typ = node.IntID()
+		}
+	}
+	raw := &RawExtensionObjectValue{EncodingID: typ}
+
+	if e.EncodingMask != ExtensionObjectBinary || e.TypeID.HasNamespaceURI() {
+		return raw
+	}
+
+	if f := registeredExtensionObject(node); f != nil {
+		return f()
+	}
+	if val, err := newExtensionObjectValue(typ); err == nil && val != nil {
+		return val
+	}
+	return raw
+}
+
 // Serialize serializes ExtensionObject into bytes.
 func (e *ExtensionObject) Serialize() ([]byte, error) {
 	b := make([]byte, e.Len())
@@ -106,6 +186,10 @@ func (e *ExtensionObject) SerializeTo(b []byte) error {
 	b[offset] = e.EncodingMask
 	offset++
 
+	if e.EncodingMask == ExtensionObjectEmpty {
+		return nil
+	}
+
 	binary.LittleEndian.PutUint32(b[offset:offset+4], uint32(e.Length))
 	offset += 4
 
@@ -123,10 +207,16 @@ func (e *ExtensionObject) SerializeTo(b []byte) error {
 func (e *ExtensionObject) Len() int {
 	// encoding mask byte + length
 	length := 1 + 4
+	if e.EncodingMask == ExtensionObjectEmpty {
+		length = 1
+	}
 
 	if e.TypeID != nil {
 		length += e.TypeID.Len()
 	}
+	if e.EncodingMask == ExtensionObjectEmpty {
+		return length
+	}
 
 	if e.Value != nil {
 		length += e.Value.Len()
@@ -140,6 +230,12 @@ func (e *ExtensionObject) SetLength() {
 	e.Length = int32(e.Value.Len())
 }
 
+// DataType returns type of Data, which is the built-in type of ExtensionObject
+// that has the same identifier as Structure.
+func (e *ExtensionObject) DataType() uint16 {
+	return id.Structure
+}
+
 // ExtensionObjectValue represents the value in ExtensionObject.
 type ExtensionObjectValue interface {
 	DecodeFromBytes([]byte) error
@@ -153,62 +249,240 @@ type ExtensionObjectValue interface {
 // The type should be one defined in the DiscoveryConfiguration, UserIdentityToken, NodeAttributes,
 // HistoryReadDetails, HistoryData, HistoryUpdateDetails, MonitoringFilterResult, FilterOperand.
 func DecodeExtensionObjectValue(b []byte, typ int) (ExtensionObjectValue, error) {
-	var e ExtensionObjectValue
+	e, err := newExtensionObjectValue(typ)
+	if err != nil {
+		return nil, err
+	}
+	if e == nil {
+		return nil, errors.NewErrInvalidType(typ, "decode", "should be a type of ExtensionObjectValue")
+	}
+
+	if err := e.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// newExtensionObjectValue returns an empty ExtensionObjectValue of the type defined in this package,
+// or nil if the type is unknown.
+func newExtensionObjectValue(typ int) (ExtensionObjectValue, error) {
 	switch typ {
 	case id.ElementOperand_Encoding_DefaultBinary:
-		return nil, errors.NewErrUnsupported(typ, "not implemented")
+		return &ElementOperand{}, nil
 	case id.LiteralOperand_Encoding_DefaultBinary:
-		return nil, errors.NewErrUnsupported(typ, "not implemented")
+		return &LiteralOperand{}, nil
 	case id.AttributeOperand_Encoding_DefaultBinary:
 		return nil, errors.NewErrUnsupported(typ, "not implemented")
 	case id.SimpleAttributeOperand_Encoding_DefaultBinary:
-		return nil, errors.NewErrUnsupported(typ, "not implemented")
+		return &SimpleAttributeOperand{}, nil
 	case id.MdnsDiscoveryConfiguration_Encoding_DefaultBinary:
-		return nil, errors.NewErrUnsupported(typ, "not implemented")
+		return &MdnsDiscoveryConfiguration{}, nil
 	case id.DataChangeFilter_Encoding_DefaultBinary:
-		return nil, errors.NewErrUnsupported(typ, "not implemented")
+		return &DataChangeFilter{}, nil
 	case id.EventFilter_Encoding_DefaultBinary:
-		return nil, errors.NewErrUnsupported(typ, "not implemented")
+		return &EventFilter{}, nil
 	case id.AggregateFilter_Encoding_DefaultBinary:
 		return nil, errors.NewErrUnsupported(typ, "not implemented")
 	case id.ObjectAttributes_Encoding_DefaultBinary:
-		return nil, errors.NewErrUnsupported(typ, "not implemented")
+		return &ObjectAttributes{}, nil
 	case id.VariableAttributes_Encoding_DefaultBinary:
-		return nil, errors.NewErrUnsupported(typ, "not implemented")
+		return &VariableAttributes{}, nil
 	case id.MethodAttributes_Encoding_DefaultBinary:
-		return nil, errors.NewErrUnsupported(typ, "not implemented")
+		return &MethodAttributes{}, nil
 	case id.ObjectTypeAttributes_Encoding_DefaultBinary:
-		return nil, errors.NewErrUnsupported(typ, "not implemented")
+		return &ObjectTypeAttributes{}, nil
 	case id.VariableTypeAttributes_Encoding_DefaultBinary:
-		return nil, errors.NewErrUnsupported(typ, "not implemented")
+		return &VariableTypeAttributes{}, nil
 	case id.ReferenceTypeAttributes_Encoding_DefaultBinary:
-		return nil, errors.NewErrUnsupported(typ, "not implemented")
+		return &ReferenceTypeAttributes{}, nil
 	case id.DataTypeAttributes_Encoding_DefaultBinary:
-		return nil, errors.NewErrUnsupported(typ, "not implemented")
+		return &DataTypeAttributes{}, nil
 	case id.ViewAttributes_Encoding_DefaultBinary:
-		return nil, errors.NewErrUnsupported(typ, "not implemented")
+		return &ViewAttributes{}, nil
 	case id.GenericAttributes_Encoding_DefaultBinary:
 		return nil, errors.NewErrUnsupported(typ, "not implemented")
+	case id.ReadRawModifiedDetails_Encoding_DefaultBinary:
+		return &ReadRawModifiedDetails{}, nil
+	case id.HistoryData_Encoding_DefaultBinary:
+		return &HistoryData{}, nil
 	case id.DataChangeNotification_Encoding_DefaultBinary:
-		return nil, errors.NewErrUnsupported(typ, "not implemented")
+		return &DataChangeNotification{}, nil
 	case id.EventNotificationList_Encoding_DefaultBinary:
-		return nil, errors.NewErrUnsupported(typ, "not implemented")
+		return &EventNotificationList{}, nil
 	case id.StatusChangeNotification_Encoding_DefaultBinary:
 		return nil, errors.NewErrUnsupported(typ, "not implemented")
 	case id.AnonymousIdentityToken_Encoding_DefaultBinary:
-		e = &AnonymousIdentityToken{}
+		return &AnonymousIdentityToken{}, nil
 	case id.UserNameIdentityToken_Encoding_DefaultBinary:
-		e = &UserNameIdentityToken{}
+		return &UserNameIdentityToken{}, nil
 	case id.X509IdentityToken_Encoding_DefaultBinary:
-		e = &X509IdentityToken{}
+		return &X509IdentityToken{}, nil
 	case id.IssuedIdentityToken_Encoding_DefaultBinary:
-		e = &IssuedIdentityToken{}
+		return &IssuedIdentityToken{}, nil
 	default:
-		return nil, errors.NewErrInvalidType(typ, "decode", "should be a type of ExtensionObjectValue")
+		return nil, nil
 	}
+}
 
-	if err := e.DecodeFromBytes(b); err != nil {
+var (
+	extObjMu       sync.RWMutex
+	extObjRegistry = map[string]func() ExtensionObjectValue{}
+)
+
+// RegisterExtensionObject registers the function f which returns an empty value of
+// the structure encoded with encodingID, so that the ExtensionObjects with it
+// are decoded into the value. The types defined in this package can be overridden.
+//
+// The encodingID is the NodeID of the DataTypeEncoding, e.g. "Default Binary" of the
+// DataType, and is compared by its namespace index and identifier.
+func RegisterExtensionObject(encodingID *NodeID, f func() ExtensionObjectValue) {
+	extObjMu.Lock()
+	defer extObjMu.Unlock()
+
+	extObjRegistry[encodingID.String()] = f
+}
+
+// UnregisterExtensionObject removes the function registered for encodingID.
+func UnregisterExtensionObject(encodingID *NodeID) {
+	extObjMu.Lock()
+	defer extObjMu.Unlock()
+
+	delete(extObjRegistry, encodingID.String())
+}
+
+// registeredExtensionObject returns the function registered for encodingID, or nil.
+func registeredExtensionObject(encodingID *NodeID) func() ExtensionObjectValue {
+	extObjMu.RLock()
+	defer extObjMu.RUnlock()
+
+	return extObjRegistry[encodingID.String()]
+}
+
+// RawExtensionObjectValue is the body of an ExtensionObject whose type is unknown,
+// or encoded in XML, kept as it is.
+type RawExtensionObjectValue struct {
+	// EncodingID is the identifier of the encoding NodeID if it is a numeric one
+	// in the namespace 0, or 0 otherwise.
+	EncodingID int
+	Body       []byte
+}
+
+// DecodeFromBytes decodes given bytes into RawExtensionObjectValue.
+// All the bytes given are the body.
+func (r *RawExtensionObjectValue) DecodeFromBytes(b []byte) error {
+	r.Body = make([]byte, len(b))
+	copy(r.Body, b)
+	return nil
+}
+
+// SerializeTo serializes RawExtensionObjectValue into bytes.
+func (r *RawExtensionObjectValue) SerializeTo(b []byte) error {
+	copy(b, r.Body)
+	return nil
+}
+
+// Len returns the actual length of RawExtensionObjectValue in int.
+func (r *RawExtensionObjectValue) Len() int {
+	return len(r.Body)
+}
+
+// Type returns the EncodingID.
+func (r *RawExtensionObjectValue) Type() int {
+	return r.EncodingID
+}
+
+// ExtensionObjectArray represents an array of ExtensionObjects.
+// It does not correspond to a certain type from the specification
+// but makes encoding and decoding easier.
+type ExtensionObjectArray struct {
+	ArraySize        int32
+	ExtensionObjects []*ExtensionObject
+}
+
+// NewExtensionObjectArray creates a new ExtensionObjectArray from multiple ExtensionObjects.
+func NewExtensionObjectArray(objs []*ExtensionObject) *ExtensionObjectArray {
+	if objs == nil {
+		return &ExtensionObjectArray{
+			ArraySize: 0,
+		}
+	}
+
+	return &ExtensionObjectArray{
+		ArraySize:        int32(len(objs)),
+		ExtensionObjects: objs,
+	}
+}
+
+// DecodeExtensionObjectArray decodes given bytes into ExtensionObjectArray.
+func DecodeExtensionObjectArray(b []byte) (*ExtensionObjectArray, error) {
+	a := &ExtensionObjectArray{}
+	if err := a.DecodeFromBytes(b); err != nil {
 		return nil, err
 	}
-	return e, nil
+
+	return a, nil
+}
+
+// DecodeFromBytes decodes given bytes into ExtensionObjectArray.
+func (a *ExtensionObjectArray) DecodeFromBytes(b []byte) error {
+	return a.decode(b, 0)
+}
+
+// decode decodes given bytes into ExtensionObjectArray nested in the Variants to depth.
+// The ExtensionObjects are one level deeper than the array.
+func (a *ExtensionObjectArray) decode(b []byte, depth int) error {
+	if len(b) < 4 {
+		return errors.NewErrTooShortToDecode(a, "should be longer than 4 bytes")
+	}
+	a.ArraySize = int32(binary.LittleEndian.Uint32(b[:4]))
+	if a.ArraySize <= 0 {
+		return nil
+	}
+
+	offset := 4
+	for i := 1; i <= int(a.ArraySize); i++ {
+		e := &ExtensionObject{}
+		if err := e.decode(b[offset:], depth+1); err != nil {
+			return err
+		}
+		a.ExtensionObjects = append(a.ExtensionObjects, e)
+		offset += e.Len()
+	}
+
+	return nil
+}
+
+// Serialize serializes ExtensionObjectArray into bytes.
+func (a *ExtensionObjectArray) Serialize() ([]byte, error) {
+	b := make([]byte, a.Len())
+	if err := a.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes ExtensionObjectArray into bytes.
+func (a *ExtensionObjectArray) SerializeTo(b []byte) error {
+	offset := 4
+	binary.LittleEndian.PutUint32(b[:4], uint32(a.ArraySize))
+
+	for _, e := range a.ExtensionObjects {
+		if err := e.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += e.Len()
+	}
+
+	return nil
+}
+
+// Len returns the actual length in int.
+func (a *ExtensionObjectArray) Len() int {
+	l := 4
+	for _, e := range a.ExtensionObjects {
+		l += e.Len()
+	}
+
+	return l
 }
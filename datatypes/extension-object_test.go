@@ -5,8 +5,12 @@
 package datatypes
 
 import (
+	"encoding/binary"
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/wmnsk/gopcua/id"
 	"github.com/wmnsk/gopcua/utils/codectest"
 )
 
@@ -28,8 +32,153 @@ func TestExtensionObject(t *testing.T) {
 				0x09, 0x00, 0x00, 0x00, 0x61, 0x6e, 0x6f, 0x6e, 0x79, 0x6d, 0x6f, 0x75, 0x73,
 			},
 		},
+		{
+			Name: "data-change-filter",
+			Struct: NewExtensionObject(
+				0x01, NewDataChangeFilter(DataChangeTriggerStatusValue, DeadbandTypeAbsolute, 0.5),
+			),
+			Bytes: []byte{
+				// TypeID
+				0x01, 0x00, 0xd4, 0x02,
+				// EncodingMask
+				0x01,
+				// Length
+				0x10, 0x00, 0x00, 0x00,
+				// DataChangeFilter
+				0x01, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xe0, 0x3f,
+			},
+		},
+		{
+			Name: "unknown type",
+			Struct: NewExtensionObjectWithTypeID(
+				ExtensionObjectBinary, NewFourByteNodeID(3, 6001),
+				&RawExtensionObjectValue{Body: []byte{0xde, 0xad, 0xbe, 0xef}},
+			),
+			Bytes: []byte{
+				// TypeID
+				0x01, 0x03, 0x71, 0x17,
+				// EncodingMask
+				0x01,
+				// Length
+				0x04, 0x00, 0x00, 0x00,
+				// Body
+				0xde, 0xad, 0xbe, 0xef,
+			},
+		},
+		{
+			Name: "xml",
+			Struct: NewExtensionObject(
+				ExtensionObjectXML,
+				&RawExtensionObjectValue{
+					EncodingID: id.AnonymousIdentityToken_Encoding_DefaultBinary,
+					Body:       []byte{0x03, 0x00, 0x00, 0x00, 0x3c, 0x61, 0x3e},
+				},
+			),
+			Bytes: []byte{
+				// TypeID
+				0x01, 0x00, 0x41, 0x01,
+				// EncodingMask
+				0x02,
+				// Length
+				0x07, 0x00, 0x00, 0x00,
+				// Body
+				0x03, 0x00, 0x00, 0x00, 0x3c, 0x61, 0x3e,
+			},
+		},
+		{
+			Name:   "null",
+			Struct: NewNullExtensionObject(),
+			Bytes: []byte{
+				// TypeID
+				0x00, 0x00,
+				// EncodingMask
+				0x00,
+			},
+		},
 	}
 	codectest.Run(t, cases, func(b []byte) (codectest.S, error) {
 		return DecodeExtensionObject(b)
 	})
 }
+
+// point is a custom structure with two fields, X as Int32 and Name as String.
+type point struct {
+	X    int32
+	Name *String
+}
+
+func (p *point) DecodeFromBytes(b []byte) error {
+	p.X = int32(binary.LittleEndian.Uint32(b[:4]))
+	p.Name = &String{}
+	return p.Name.DecodeFromBytes(b[4:])
+}
+
+func (p *point) SerializeTo(b []byte) error {
+	binary.LittleEndian.PutUint32(b[:4], uint32(p.X))
+	return p.Name.SerializeTo(b[4:])
+}
+
+func (p *point) Len() int {
+	return 4 + p.Name.Len()
+}
+
+func (p *point) Type() int {
+	return 5001
+}
+
+func TestRegisterExtensionObject(t *testing.T) {
+	encodingID := NewFourByteNodeID(2, 5001)
+	b := []byte{
+		// TypeID
+		0x01, 0x02, 0x89, 0x13,
+		// EncodingMask
+		0x01,
+		// Length
+		0x0b, 0x00, 0x00, 0x00,
+		// X
+		0xfe, 0xff, 0xff, 0xff,
+		// Name
+		0x03, 0x00, 0x00, 0x00, 0x66, 0x6f, 0x6f,
+	}
+
+	RegisterExtensionObject(encodingID, func() ExtensionObjectValue { return &point{} })
+	e, err := DecodeExtensionObject(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := NewExtensionObjectWithTypeID(ExtensionObjectBinary, encodingID, &point{X: -2, Name: NewString("foo")})
+	if diff := cmp.Diff(e, want); diff != "" {
+		t.Error(diff)
+	}
+
+	got, err := e.Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(got, b); diff != "" {
+		t.Error(diff)
+	}
+
+	// the body is kept as it is once the type is unregistered.
+	UnregisterExtensionObject(encodingID)
+	e, err = DecodeExtensionObject(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw, ok := e.Value.(*RawExtensionObjectValue)
+	if !ok {
+		t.Fatalf("got %T want RawExtensionObjectValue", e.Value)
+	}
+	if diff := cmp.Diff(raw.Body, b[9:]); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestExtensionObjectTooShort(t *testing.T) {
+	// the Length exceeds the body.
+	b := []byte{0x01, 0x00, 0x41, 0x01, 0x01, 0x08, 0x00, 0x00, 0x00, 0x00}
+	if _, err := DecodeExtensionObject(b); err == nil {
+		t.Fatal("expected error for truncated body, got nil")
+	}
+}
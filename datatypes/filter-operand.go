@@ -0,0 +1,140 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package datatypes
+
+import (
+	"encoding/binary"
+
+	"github.com/wmnsk/gopcua/errors"
+	"github.com/wmnsk/gopcua/id"
+)
+
+// ElementOperand is a FilterOperand which refers to the result of another element
+// of the ContentFilter by its Index.
+//
+// Specification: Part 4, 7.4.4.2
+type ElementOperand struct {
+	Index uint32
+}
+
+// NewElementOperand creates a new ElementOperand.
+func NewElementOperand(index uint32) *ElementOperand {
+	return &ElementOperand{
+		Index: index,
+	}
+}
+
+// DecodeElementOperand decodes given bytes into ElementOperand.
+func DecodeElementOperand(b []byte) (*ElementOperand, error) {
+	o := &ElementOperand{}
+	if err := o.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return o, nil
+}
+
+// DecodeFromBytes decodes given bytes into ElementOperand.
+func (o *ElementOperand) DecodeFromBytes(b []byte) error {
+	if len(b) < 4 {
+		return errors.NewErrTooShortToDecode(o, "should be longer than 4 bytes")
+	}
+	o.Index = binary.LittleEndian.Uint32(b[:4])
+	return nil
+}
+
+// Serialize serializes ElementOperand into bytes.
+func (o *ElementOperand) Serialize() ([]byte, error) {
+	b := make([]byte, o.Len())
+	if err := o.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes ElementOperand into bytes.
+func (o *ElementOperand) SerializeTo(b []byte) error {
+	binary.LittleEndian.PutUint32(b[:4], o.Index)
+	return nil
+}
+
+// Len returns the actual length of ElementOperand in int.
+func (o *ElementOperand) Len() int {
+	return 4
+}
+
+// Type returns type of ElementOperand defined in NodeIds.csv in int.
+func (o *ElementOperand) Type() int {
+	return id.ElementOperand_Encoding_DefaultBinary
+}
+
+// LiteralOperand is a FilterOperand which is the literal Value to compare with.
+//
+// Specification: Part 4, 7.4.4.3
+type LiteralOperand struct {
+	Value *Variant
+}
+
+// NewLiteralOperand creates a new LiteralOperand.
+func NewLiteralOperand(value *Variant) *LiteralOperand {
+	return &LiteralOperand{
+		Value: value,
+	}
+}
+
+// DecodeLiteralOperand decodes given bytes into LiteralOperand.
+func DecodeLiteralOperand(b []byte) (*LiteralOperand, error) {
+	o := &LiteralOperand{}
+	if err := o.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return o, nil
+}
+
+// DecodeFromBytes decodes given bytes into LiteralOperand.
+func (o *LiteralOperand) DecodeFromBytes(b []byte) error {
+	return o.decode(b, 0)
+}
+
+// decode decodes given bytes into LiteralOperand nested in the Variants to depth.
+func (o *LiteralOperand) decode(b []byte, depth int) error {
+	o.Value = &Variant{}
+	return o.Value.decode(b, depth)
+}
+
+// Serialize serializes LiteralOperand into bytes.
+func (o *LiteralOperand) Serialize() ([]byte, error) {
+	b := make([]byte, o.Len())
+	if err := o.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes LiteralOperand into bytes.
+func (o *LiteralOperand) SerializeTo(b []byte) error {
+	if o.Value != nil {
+		return o.Value.SerializeTo(b)
+	}
+
+	return nil
+}
+
+// Len returns the actual length of LiteralOperand in int.
+func (o *LiteralOperand) Len() int {
+	if o.Value != nil {
+		return o.Value.Len()
+	}
+
+	return 0
+}
+
+// Type returns type of LiteralOperand defined in NodeIds.csv in int.
+func (o *LiteralOperand) Type() int {
+	return id.LiteralOperand_Encoding_DefaultBinary
+}
@@ -8,6 +8,7 @@ import (
 	"encoding/binary"
 	"math"
 
+	"github.com/wmnsk/gopcua/errors"
 	"github.com/wmnsk/gopcua/id"
 )
 
@@ -40,6 +41,9 @@ func DecodeFloat(b []byte) (*Float, error) {
 
 // DecodeFromBytes decodes given bytes into OPC UA Float.
 func (f *Float) DecodeFromBytes(b []byte) error {
+	if len(b) < 4 {
+		return errors.NewErrTooShortToDecode(f, "should be longer than 4 bytes")
+	}
 	bits := binary.LittleEndian.Uint32(b)
 	f.Value = math.Float32frombits(bits)
 	return nil
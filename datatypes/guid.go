@@ -15,6 +15,9 @@ import (
 
 // GUID represents GUID in binary stream. It is a 16-byte globally unique identifier.
 //
+// Data1, Data2 and Data3 are encoded in little endian, while Data4 is encoded
+// as the sequence of 8 bytes, which is big endian when it is held in uint64.
+//
 // Specification: Part 6, 5.1.3
 type GUID struct {
 	Data1 uint32
@@ -26,23 +29,42 @@ type GUID struct {
 // NewGUID creates a new GUID.
 // Input should be GUID string of 16 hexadecimal characters like 1111AAAA-22BB-33CC-44DD-55EE77FF9900.
 // Dash can be omitted, and alphabets are not case-sensitive.
+//
+// It returns nil if guid is invalid. Use ParseGUID to get the error.
 func NewGUID(guid string) *GUID {
-	h := strings.Replace(guid, "-", "", -1)
-	b, err := hex.DecodeString(h)
+	g, err := ParseGUID(guid)
 	if err != nil {
 		return nil
 	}
-	if len(b) < 16 {
-		return nil
-	}
+	return g
+}
 
-	g := &GUID{}
-	g.Data1 = binary.BigEndian.Uint32(b[:4])
-	g.Data2 = binary.BigEndian.Uint16(b[4:6])
-	g.Data3 = binary.BigEndian.Uint16(b[6:8])
-	g.Data4 = binary.BigEndian.Uint64(b[8:16])
+// ParseGUID parses the GUID string of 16 hexadecimal characters like 1111AAAA-22BB-33CC-44DD-55EE77FF9900.
+// Dash can be omitted, and alphabets are not case-sensitive.
+func ParseGUID(guid string) (*GUID, error) {
+	h := guid
+	if len(h) == 36 {
+		for _, i := range []int{8, 13, 18, 23} {
+			if h[i] != '-' {
+				return nil, errors.Errorf("invalid GUID %q: should be separated by dashes like 1111AAAA-22BB-33CC-44DD-55EE77FF9900", guid)
+			}
+		}
+		h = strings.Replace(h, "-", "", -1)
+	}
+	if len(h) != 32 {
+		return nil, errors.Errorf("invalid GUID %q: should have 32 hexadecimal characters", guid)
+	}
+	b, err := hex.DecodeString(h)
+	if err != nil {
+		return nil, errors.Errorf("invalid GUID %q: %s", guid, err)
+	}
 
-	return g
+	return &GUID{
+		Data1: binary.BigEndian.Uint32(b[:4]),
+		Data2: binary.BigEndian.Uint16(b[4:6]),
+		Data3: binary.BigEndian.Uint16(b[6:8]),
+		Data4: binary.BigEndian.Uint64(b[8:16]),
+	}, nil
 }
 
 // DecodeGUID decodes given bytes into GUID.
@@ -64,7 +86,7 @@ func (g *GUID) DecodeFromBytes(b []byte) error {
 	g.Data1 = binary.LittleEndian.Uint32(b[:4])
 	g.Data2 = binary.LittleEndian.Uint16(b[4:6])
 	g.Data3 = binary.LittleEndian.Uint16(b[6:8])
-	g.Data4 = binary.LittleEndian.Uint64(b[8:16])
+	g.Data4 = binary.BigEndian.Uint64(b[8:16])
 	return nil
 }
 
@@ -83,7 +105,7 @@ func (g *GUID) SerializeTo(b []byte) error {
 	binary.LittleEndian.PutUint32(b[:4], g.Data1)
 	binary.LittleEndian.PutUint16(b[4:6], g.Data2)
 	binary.LittleEndian.PutUint16(b[6:8], g.Data3)
-	binary.LittleEndian.PutUint64(b[8:16], g.Data4)
+	binary.BigEndian.PutUint64(b[8:16], g.Data4)
 
 	return nil
 }
@@ -93,16 +115,13 @@ func (g *GUID) Len() int {
 	return 16
 }
 
-// String returns GUID in human-readable string.
+// String returns GUID in the canonical form like 1111AAAA-22BB-33CC-44DD-55EE77FF9900.
 func (g *GUID) String() string {
-	d4 := make([]byte, 8)
-	binary.BigEndian.PutUint64(d4, g.Data4)
-
-	return fmt.Sprintf("%X-%X-%X-%X-%X",
+	return fmt.Sprintf("%08X-%04X-%04X-%04X-%012X",
 		g.Data1,
 		g.Data2,
 		g.Data3,
-		d4[:2],
-		d4[2:],
+		g.Data4>>48,
+		g.Data4&0xffffffffffff,
 	)
 }
@@ -7,17 +7,28 @@ package datatypes
 import (
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
+
 	"github.com/wmnsk/gopcua/utils/codectest"
 )
 
 func TestGUID(t *testing.T) {
 	cases := []codectest.Case{
+		{
+			// the example in Part 6, 5.1.3
+			Name:   "spec",
+			Struct: NewGUID("72962B91-FA75-4AE6-8D28-B404DC7DAF63"),
+			Bytes: []byte{
+				0x91, 0x2b, 0x96, 0x72, 0x75, 0xfa, 0xe6, 0x4a,
+				0x8d, 0x28, 0xb4, 0x04, 0xdc, 0x7d, 0xaf, 0x63,
+			},
+		},
 		{
 			Name:   "ok",
 			Struct: NewGUID("AAAABBBB-CCDD-EEFF-0101-0123456789AB"),
 			Bytes: []byte{
 				0xbb, 0xbb, 0xaa, 0xaa, 0xdd, 0xcc, 0xff, 0xee,
-				0xab, 0x89, 0x67, 0x45, 0x23, 0x01, 0x01, 0x01,
+				0x01, 0x01, 0x01, 0x23, 0x45, 0x67, 0x89, 0xab,
 			},
 		},
 	}
@@ -25,3 +36,58 @@ func TestGUID(t *testing.T) {
 		return DecodeGUID(b)
 	})
 }
+
+func TestParseGUID(t *testing.T) {
+	want := &GUID{Data1: 0x72962b91, Data2: 0xfa75, Data3: 0x4ae6, Data4: 0x8d28b404dc7daf63}
+	for _, s := range []string{
+		"72962B91-FA75-4AE6-8D28-B404DC7DAF63",
+		"72962b91-fa75-4ae6-8d28-b404dc7daf63",
+		"72962B91FA754AE68D28B404DC7DAF63",
+	} {
+		t.Run(s, func(t *testing.T) {
+			g, err := ParseGUID(s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(g, want); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+
+	for _, s := range []string{
+		"",
+		"72962B91-FA75-4AE6-8D28",
+		"72962B91-FA75-4AE6-8D28-B404DC7DAF6300",
+		"72962B91FA75-4AE6-8D28-B404DC7DAF63-",
+		"72962B91-FA75-4AE6-8D28-B404DC7DAF6Z",
+	} {
+		t.Run("invalid/"+s, func(t *testing.T) {
+			if g, err := ParseGUID(s); err == nil {
+				t.Errorf("got %v want error", g)
+			}
+		})
+	}
+}
+
+func TestGUIDString(t *testing.T) {
+	cases := []struct {
+		g   *GUID
+		str string
+	}{
+		{NewGUID("72962b91-fa75-4ae6-8d28-b404dc7daf63"), "72962B91-FA75-4AE6-8D28-B404DC7DAF63"},
+		// the leading zeros are kept in each group.
+		{&GUID{Data1: 0x1, Data2: 0x2, Data3: 0x3, Data4: 0x0004000000000005}, "00000001-0002-0003-0004-000000000005"},
+		{&GUID{}, "00000000-0000-0000-0000-000000000000"},
+	}
+	for _, c := range cases {
+		t.Run(c.str, func(t *testing.T) {
+			if got := c.g.String(); got != c.str {
+				t.Errorf("got %s want %s", got, c.str)
+			}
+			if g, err := ParseGUID(c.g.String()); err != nil || *g != *c.g {
+				t.Errorf("got %v, %v want %v, nil", g, err, c.g)
+			}
+		})
+	}
+}
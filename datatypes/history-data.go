@@ -0,0 +1,70 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package datatypes
+
+import "github.com/wmnsk/gopcua/id"
+
+// HistoryData is the history of the values of a Node returned by HistoryRead
+// for ReadRawModifiedDetails, in the order of their timestamps.
+//
+// Specification: Part 11, 6.5.2
+type HistoryData struct {
+	DataValues *DataValueArray
+}
+
+// NewHistoryData creates a new HistoryData.
+func NewHistoryData(values ...*DataValue) *HistoryData {
+	return &HistoryData{
+		DataValues: NewDataValueArray(values),
+	}
+}
+
+// DecodeHistoryData decodes given bytes into HistoryData.
+func DecodeHistoryData(b []byte) (*HistoryData, error) {
+	h := &HistoryData{}
+	if err := h.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return h, nil
+}
+
+// DecodeFromBytes decodes given bytes into HistoryData.
+func (h *HistoryData) DecodeFromBytes(b []byte) error {
+	h.DataValues = &DataValueArray{}
+	return h.DataValues.DecodeFromBytes(b)
+}
+
+// Serialize serializes HistoryData into bytes.
+func (h *HistoryData) Serialize() ([]byte, error) {
+	b := make([]byte, h.Len())
+	if err := h.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes HistoryData into bytes.
+func (h *HistoryData) SerializeTo(b []byte) error {
+	if h.DataValues != nil {
+		return h.DataValues.SerializeTo(b)
+	}
+
+	return nil
+}
+
+// Len returns the actual length of HistoryData in int.
+func (h *HistoryData) Len() int {
+	if h.DataValues == nil {
+		return 0
+	}
+	return h.DataValues.Len()
+}
+
+// Type returns type of HistoryData.
+func (h *HistoryData) Type() int {
+	return id.HistoryData_Encoding_DefaultBinary
+}
@@ -0,0 +1,46 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package datatypes
+
+import (
+	"testing"
+	"time"
+
+	"github.com/wmnsk/gopcua/utils/codectest"
+)
+
+func TestHistoryData(t *testing.T) {
+	cases := []codectest.Case{
+		{
+			Name: "normal",
+			Struct: NewHistoryData(
+				NewDataValue(
+					true, false, true, false, false, false,
+					NewVariant(NewFloat(2.50025)), 0,
+					time.Date(2018, time.August, 10, 23, 0, 0, 0, time.UTC), 0,
+					time.Time{}, 0,
+				),
+			),
+			Bytes: []byte{
+				// ArraySize
+				0x01, 0x00, 0x00, 0x00,
+				// EncodingMask
+				0x05,
+				// Value
+				0x0a, 0x19, 0x04, 0x20, 0x40,
+				// SourceTimestamp
+				0x00, 0x98, 0x67, 0xdd, 0xfd, 0x30, 0xd4, 0x01,
+			},
+		},
+		{
+			Name:   "empty",
+			Struct: NewHistoryData(),
+			Bytes:  []byte{0x00, 0x00, 0x00, 0x00},
+		},
+	}
+	codectest.Run(t, cases, func(b []byte) (codectest.S, error) {
+		return DecodeHistoryData(b)
+	})
+}
@@ -0,0 +1,198 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package datatypes
+
+import (
+	"encoding/binary"
+
+	"github.com/wmnsk/gopcua/errors"
+)
+
+// HistoryReadResult is the result of HistoryRead for a single Node.
+//
+// HistoryData is an ExtensionObject of which the type depends on the HistoryReadDetails
+// in the request, e.g. HistoryData for ReadRawModifiedDetails. If not all the values
+// could be returned, ContinuationPoint is set to read the rest in the next HistoryRead.
+//
+// Specification: Part 4, 5.10.3.2
+type HistoryReadResult struct {
+	StatusCode        uint32
+	ContinuationPoint *ByteString
+	HistoryData       *ExtensionObject
+}
+
+// NewHistoryReadResult creates a new HistoryReadResult.
+func NewHistoryReadResult(code uint32, cp []byte, data ExtensionObjectValue) *HistoryReadResult {
+	obj := NewNullExtensionObject()
+	if data != nil {
+		obj = NewExtensionObject(0x01, data)
+	}
+
+	return &HistoryReadResult{
+		StatusCode:        code,
+		ContinuationPoint: NewByteString(cp),
+		HistoryData:       obj,
+	}
+}
+
+// DecodeHistoryReadResult decodes given bytes into HistoryReadResult.
+func DecodeHistoryReadResult(b []byte) (*HistoryReadResult, error) {
+	r := &HistoryReadResult{}
+	if err := r.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// DecodeFromBytes decodes given bytes into HistoryReadResult.
+func (r *HistoryReadResult) DecodeFromBytes(b []byte) error {
+	if len(b) < 4 {
+		return errors.NewErrTooShortToDecode(r, "should be longer than 4 bytes")
+	}
+	r.StatusCode = binary.LittleEndian.Uint32(b[:4])
+	offset := 4
+
+	r.ContinuationPoint = &ByteString{}
+	if err := r.ContinuationPoint.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += r.ContinuationPoint.Len()
+
+	r.HistoryData = &ExtensionObject{}
+	return r.HistoryData.DecodeFromBytes(b[offset:])
+}
+
+// Serialize serializes HistoryReadResult into bytes.
+func (r *HistoryReadResult) Serialize() ([]byte, error) {
+	b := make([]byte, r.Len())
+	if err := r.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes HistoryReadResult into bytes.
+func (r *HistoryReadResult) SerializeTo(b []byte) error {
+	binary.LittleEndian.PutUint32(b[:4], r.StatusCode)
+	offset := 4
+
+	if r.ContinuationPoint != nil {
+		if err := r.ContinuationPoint.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += r.ContinuationPoint.Len()
+	}
+
+	if r.HistoryData != nil {
+		return r.HistoryData.SerializeTo(b[offset:])
+	}
+
+	return nil
+}
+
+// Len returns the actual length of HistoryReadResult in int.
+func (r *HistoryReadResult) Len() int {
+	l := 4
+	if r.ContinuationPoint != nil {
+		l += r.ContinuationPoint.Len()
+	}
+	if r.HistoryData != nil {
+		l += r.HistoryData.Len()
+	}
+
+	return l
+}
+
+// HistoryReadResultArray represents an array of HistoryReadResults.
+// It does not correspond to a certain type from the specification
+// but makes encoding and decoding easier.
+type HistoryReadResultArray struct {
+	ArraySize          int32
+	HistoryReadResults []*HistoryReadResult
+}
+
+// NewHistoryReadResultArray creates a new HistoryReadResultArray from multiple HistoryReadResults.
+func NewHistoryReadResultArray(results []*HistoryReadResult) *HistoryReadResultArray {
+	if results == nil {
+		return &HistoryReadResultArray{
+			ArraySize: 0,
+		}
+	}
+
+	return &HistoryReadResultArray{
+		ArraySize:          int32(len(results)),
+		HistoryReadResults: results,
+	}
+}
+
+// DecodeHistoryReadResultArray decodes given bytes into HistoryReadResultArray.
+func DecodeHistoryReadResultArray(b []byte) (*HistoryReadResultArray, error) {
+	a := &HistoryReadResultArray{}
+	if err := a.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// DecodeFromBytes decodes given bytes into HistoryReadResultArray.
+func (a *HistoryReadResultArray) DecodeFromBytes(b []byte) error {
+	if len(b) < 4 {
+		return errors.NewErrTooShortToDecode(a, "should be longer than 4 bytes")
+	}
+	a.ArraySize = int32(binary.LittleEndian.Uint32(b[:4]))
+	if a.ArraySize <= 0 {
+		return nil
+	}
+
+	offset := 4
+	for i := 1; i <= int(a.ArraySize); i++ {
+		r, err := DecodeHistoryReadResult(b[offset:])
+		if err != nil {
+			return err
+		}
+		a.HistoryReadResults = append(a.HistoryReadResults, r)
+		offset += r.Len()
+	}
+
+	return nil
+}
+
+// Serialize serializes HistoryReadResultArray into bytes.
+func (a *HistoryReadResultArray) Serialize() ([]byte, error) {
+	b := make([]byte, a.Len())
+	if err := a.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes HistoryReadResultArray into bytes.
+func (a *HistoryReadResultArray) SerializeTo(b []byte) error {
+	offset := 4
+	binary.LittleEndian.PutUint32(b[:4], uint32(a.ArraySize))
+
+	for _, r := range a.HistoryReadResults {
+		if err := r.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += r.Len()
+	}
+
+	return nil
+}
+
+// Len returns the actual length in int.
+func (a *HistoryReadResultArray) Len() int {
+	l := 4
+	for _, r := range a.HistoryReadResults {
+		l += r.Len()
+	}
+
+	return l
+}
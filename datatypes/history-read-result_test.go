@@ -0,0 +1,81 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package datatypes
+
+import (
+	"testing"
+	"time"
+
+	"github.com/wmnsk/gopcua/utils/codectest"
+)
+
+func TestHistoryReadResult(t *testing.T) {
+	cases := []codectest.Case{
+		{
+			Name: "continued",
+			Struct: NewHistoryReadResult(
+				0, []byte{0xde, 0xad},
+				NewHistoryData(NewDataValue(
+					true, false, false, false, false, false,
+					NewVariant(NewFloat(2.50025)), 0, time.Time{}, 0, time.Time{}, 0,
+				)),
+			),
+			Bytes: []byte{
+				// StatusCode
+				0x00, 0x00, 0x00, 0x00,
+				// ContinuationPoint
+				0x02, 0x00, 0x00, 0x00, 0xde, 0xad,
+				// HistoryData: TypeID
+				0x01, 0x00, 0x92, 0x02,
+				// EncodingMask
+				0x01,
+				// Length
+				0x0a, 0x00, 0x00, 0x00,
+				// DataValues
+				0x01, 0x00, 0x00, 0x00, 0x01, 0x0a, 0x19, 0x04, 0x20, 0x40,
+			},
+		},
+		{
+			Name:   "bad",
+			Struct: NewHistoryReadResult(0x80340000, nil, nil),
+			Bytes: []byte{
+				// StatusCode
+				0x00, 0x00, 0x34, 0x80,
+				// ContinuationPoint
+				0xff, 0xff, 0xff, 0xff,
+				// HistoryData
+				0x00, 0x00, 0x00,
+			},
+		},
+	}
+	codectest.Run(t, cases, func(b []byte) (codectest.S, error) {
+		return DecodeHistoryReadResult(b)
+	})
+}
+
+func TestHistoryReadResultArray(t *testing.T) {
+	cases := []codectest.Case{
+		{
+			Name: "normal",
+			Struct: NewHistoryReadResultArray([]*HistoryReadResult{
+				NewHistoryReadResult(0x80340000, nil, nil),
+			}),
+			Bytes: []byte{
+				// ArraySize
+				0x01, 0x00, 0x00, 0x00,
+				// StatusCode, ContinuationPoint, HistoryData
+				0x00, 0x00, 0x34, 0x80, 0xff, 0xff, 0xff, 0xff, 0x00, 0x00, 0x00,
+			},
+		},
+		{
+			Name:   "empty",
+			Struct: NewHistoryReadResultArray(nil),
+			Bytes:  []byte{0x00, 0x00, 0x00, 0x00},
+		},
+	}
+	codectest.Run(t, cases, func(b []byte) (codectest.S, error) {
+		return DecodeHistoryReadResultArray(b)
+	})
+}
@@ -0,0 +1,218 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package datatypes
+
+import (
+	"encoding/binary"
+
+	"github.com/wmnsk/gopcua/errors"
+)
+
+// HistoryReadValueID is an identifier for a Node of which the history is read.
+//
+// ContinuationPoint is the one returned by the previous HistoryRead for the Node,
+// which is null for the first one.
+//
+// Specification: Part 4, 5.10.3.2
+type HistoryReadValueID struct {
+	NodeID            *NodeID
+	IndexRange        *String
+	DataEncoding      *QualifiedName
+	ContinuationPoint *ByteString
+}
+
+// NewHistoryReadValueID creates a new HistoryReadValueID.
+func NewHistoryReadValueID(nodeID *NodeID, idxRange string, qIdx uint16, qName string, cp []byte) *HistoryReadValueID {
+	return &HistoryReadValueID{
+		NodeID:            nodeID,
+		IndexRange:        NewString(idxRange),
+		DataEncoding:      NewQualifiedName(qIdx, qName),
+		ContinuationPoint: NewByteString(cp),
+	}
+}
+
+// DecodeHistoryReadValueID decodes given bytes into HistoryReadValueID.
+func DecodeHistoryReadValueID(b []byte) (*HistoryReadValueID, error) {
+	h := &HistoryReadValueID{}
+	if err := h.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return h, nil
+}
+
+// DecodeFromBytes decodes given bytes into HistoryReadValueID.
+func (h *HistoryReadValueID) DecodeFromBytes(b []byte) error {
+	h.NodeID = &NodeID{}
+	if err := h.NodeID.DecodeFromBytes(b); err != nil {
+		return err
+	}
+	offset := h.NodeID.Len()
+
+	h.IndexRange = &String{}
+	if err := h.IndexRange.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += h.IndexRange.Len()
+
+	h.DataEncoding = &QualifiedName{}
+	if err := h.DataEncoding.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += h.DataEncoding.Len()
+
+	h.ContinuationPoint = &ByteString{}
+	return h.ContinuationPoint.DecodeFromBytes(b[offset:])
+}
+
+// Serialize serializes HistoryReadValueID into bytes.
+func (h *HistoryReadValueID) Serialize() ([]byte, error) {
+	b := make([]byte, h.Len())
+	if err := h.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes HistoryReadValueID into bytes.
+func (h *HistoryReadValueID) SerializeTo(b []byte) error {
+	offset := 0
+	if h.NodeID != nil {
+		if err := h.NodeID.SerializeTo(b); err != nil {
+			return err
+		}
+		offset += h.NodeID.Len()
+	}
+
+	if h.IndexRange != nil {
+		if err := h.IndexRange.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += h.IndexRange.Len()
+	}
+
+	if h.DataEncoding != nil {
+		if err := h.DataEncoding.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += h.DataEncoding.Len()
+	}
+
+	if h.ContinuationPoint != nil {
+		return h.ContinuationPoint.SerializeTo(b[offset:])
+	}
+
+	return nil
+}
+
+// Len returns the actual length of HistoryReadValueID in int.
+func (h *HistoryReadValueID) Len() int {
+	var l int
+	if h.NodeID != nil {
+		l += h.NodeID.Len()
+	}
+	if h.IndexRange != nil {
+		l += h.IndexRange.Len()
+	}
+	if h.DataEncoding != nil {
+		l += h.DataEncoding.Len()
+	}
+	if h.ContinuationPoint != nil {
+		l += h.ContinuationPoint.Len()
+	}
+
+	return l
+}
+
+// HistoryReadValueIDArray represents an array of HistoryReadValueIDs.
+// It does not correspond to a certain type from the specification
+// but makes encoding and decoding easier.
+type HistoryReadValueIDArray struct {
+	ArraySize           int32
+	HistoryReadValueIDs []*HistoryReadValueID
+}
+
+// NewHistoryReadValueIDArray creates a new HistoryReadValueIDArray from multiple HistoryReadValueIDs.
+func NewHistoryReadValueIDArray(ids []*HistoryReadValueID) *HistoryReadValueIDArray {
+	if ids == nil {
+		return &HistoryReadValueIDArray{
+			ArraySize: 0,
+		}
+	}
+
+	return &HistoryReadValueIDArray{
+		ArraySize:           int32(len(ids)),
+		HistoryReadValueIDs: ids,
+	}
+}
+
+// DecodeHistoryReadValueIDArray decodes given bytes into HistoryReadValueIDArray.
+func DecodeHistoryReadValueIDArray(b []byte) (*HistoryReadValueIDArray, error) {
+	a := &HistoryReadValueIDArray{}
+	if err := a.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// DecodeFromBytes decodes given bytes into HistoryReadValueIDArray.
+func (a *HistoryReadValueIDArray) DecodeFromBytes(b []byte) error {
+	if len(b) < 4 {
+		return errors.NewErrTooShortToDecode(a, "should be longer than 4 bytes")
+	}
+	a.ArraySize = int32(binary.LittleEndian.Uint32(b[:4]))
+	if a.ArraySize <= 0 {
+		return nil
+	}
+
+	offset := 4
+	for i := 1; i <= int(a.ArraySize); i++ {
+		h, err := DecodeHistoryReadValueID(b[offset:])
+		if err != nil {
+			return err
+		}
+		a.HistoryReadValueIDs = append(a.HistoryReadValueIDs, h)
+		offset += h.Len()
+	}
+
+	return nil
+}
+
+// Serialize serializes HistoryReadValueIDArray into bytes.
+func (a *HistoryReadValueIDArray) Serialize() ([]byte, error) {
+	b := make([]byte, a.Len())
+	if err := a.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes HistoryReadValueIDArray into bytes.
+func (a *HistoryReadValueIDArray) SerializeTo(b []byte) error {
+	offset := 4
+	binary.LittleEndian.PutUint32(b[:4], uint32(a.ArraySize))
+
+	for _, h := range a.HistoryReadValueIDs {
+		if err := h.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += h.Len()
+	}
+
+	return nil
+}
+
+// Len returns the actual length in int.
+func (a *HistoryReadValueIDArray) Len() int {
+	l := 4
+	for _, h := range a.HistoryReadValueIDs {
+		l += h.Len()
+	}
+
+	return l
+}
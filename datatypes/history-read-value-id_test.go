@@ -0,0 +1,78 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package datatypes
+
+import (
+	"testing"
+
+	"github.com/wmnsk/gopcua/utils/codectest"
+)
+
+func TestHistoryReadValueID(t *testing.T) {
+	cases := []codectest.Case{
+		{
+			Name:   "first",
+			Struct: NewHistoryReadValueID(NewStringNodeID(1, "foo"), "", 0, "", nil),
+			Bytes: []byte{
+				// NodeID
+				0x03, 0x01, 0x00, 0x03, 0x00, 0x00, 0x00, 0x66, 0x6f, 0x6f,
+				// IndexRange
+				0xff, 0xff, 0xff, 0xff,
+				// DataEncoding
+				0x00, 0x00, 0xff, 0xff, 0xff, 0xff,
+				// ContinuationPoint
+				0xff, 0xff, 0xff, 0xff,
+			},
+		},
+		{
+			Name:   "continued",
+			Struct: NewHistoryReadValueID(NewStringNodeID(1, "foo"), "", 0, "", []byte{0xde, 0xad}),
+			Bytes: []byte{
+				// NodeID
+				0x03, 0x01, 0x00, 0x03, 0x00, 0x00, 0x00, 0x66, 0x6f, 0x6f,
+				// IndexRange
+				0xff, 0xff, 0xff, 0xff,
+				// DataEncoding
+				0x00, 0x00, 0xff, 0xff, 0xff, 0xff,
+				// ContinuationPoint
+				0x02, 0x00, 0x00, 0x00, 0xde, 0xad,
+			},
+		},
+	}
+	codectest.Run(t, cases, func(b []byte) (codectest.S, error) {
+		return DecodeHistoryReadValueID(b)
+	})
+}
+
+func TestHistoryReadValueIDArray(t *testing.T) {
+	cases := []codectest.Case{
+		{
+			Name: "normal",
+			Struct: NewHistoryReadValueIDArray([]*HistoryReadValueID{
+				NewHistoryReadValueID(NewFourByteNodeID(0, 2256), "", 0, "", nil),
+			}),
+			Bytes: []byte{
+				// ArraySize
+				0x01, 0x00, 0x00, 0x00,
+				// NodeID
+				0x01, 0x00, 0xd0, 0x08,
+				// IndexRange
+				0xff, 0xff, 0xff, 0xff,
+				// DataEncoding
+				0x00, 0x00, 0xff, 0xff, 0xff, 0xff,
+				// ContinuationPoint
+				0xff, 0xff, 0xff, 0xff,
+			},
+		},
+		{
+			Name:   "empty",
+			Struct: NewHistoryReadValueIDArray(nil),
+			Bytes:  []byte{0x00, 0x00, 0x00, 0x00},
+		},
+	}
+	codectest.Run(t, cases, func(b []byte) (codectest.S, error) {
+		return DecodeHistoryReadValueIDArray(b)
+	})
+}
@@ -0,0 +1,72 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package datatypes
+
+import (
+	"encoding/binary"
+
+	"github.com/wmnsk/gopcua/errors"
+	"github.com/wmnsk/gopcua/id"
+)
+
+// Int32 represents the datatype Int32. It is also used to encode the
+// Enumerations such as NodeClass.
+//
+// Specification: Part 6, 5.2.2.2
+type Int32 struct {
+	Value int32
+}
+
+// NewInt32 creates a new Int32.
+func NewInt32(value int32) *Int32 {
+	return &Int32{
+		Value: value,
+	}
+}
+
+// DecodeInt32 decodes given bytes into Int32.
+func DecodeInt32(b []byte) (*Int32, error) {
+	i := &Int32{}
+	if err := i.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return i, nil
+}
+
+// DecodeFromBytes decodes given bytes into Int32.
+func (i *Int32) DecodeFromBytes(b []byte) error {
+	if len(b) < 4 {
+		return errors.NewErrTooShortToDecode(i, "should be longer than 4 bytes")
+	}
+	i.Value = int32(binary.LittleEndian.Uint32(b[:4]))
+	return nil
+}
+
+// Serialize serializes Int32 into bytes.
+func (i *Int32) Serialize() ([]byte, error) {
+	b := make([]byte, i.Len())
+	if err := i.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes Int32 into bytes.
+func (i *Int32) SerializeTo(b []byte) error {
+	binary.LittleEndian.PutUint32(b[:4], uint32(i.Value))
+	return nil
+}
+
+// Len returns the actual length of Int32 in int.
+func (i *Int32) Len() int {
+	return 4
+}
+
+// DataType returns type of Data.
+func (i *Int32) DataType() uint16 {
+	return id.Int32
+}
@@ -0,0 +1,29 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package datatypes
+
+import (
+	"testing"
+
+	"github.com/wmnsk/gopcua/utils/codectest"
+)
+
+func TestInt32(t *testing.T) {
+	cases := []codectest.Case{
+		{
+			Name:   "positive",
+			Struct: NewInt32(2),
+			Bytes:  []byte{0x02, 0x00, 0x00, 0x00},
+		},
+		{
+			Name:   "negative",
+			Struct: NewInt32(-2),
+			Bytes:  []byte{0xfe, 0xff, 0xff, 0xff},
+		},
+	}
+	codectest.Run(t, cases, func(b []byte) (codectest.S, error) {
+		return DecodeInt32(b)
+	})
+}
@@ -7,6 +7,7 @@ package datatypes
 import (
 	"fmt"
 
+	"github.com/wmnsk/gopcua/errors"
 	"github.com/wmnsk/gopcua/id"
 )
 
@@ -48,6 +49,9 @@ func DecodeLocalizedText(b []byte) (*LocalizedText, error) {
 
 // DecodeFromBytes decodes given bytes into LocalizedText.
 func (l *LocalizedText) DecodeFromBytes(b []byte) error {
+	if len(b) < 1 {
+		return errors.NewErrTooShortToDecode(l, "should be longer than 1 byte")
+	}
 	l.EncodingMask = b[0]
 
 	var offset = 1
@@ -81,18 +85,20 @@ func (l *LocalizedText) Serialize() ([]byte, error) {
 }
 
 // SerializeTo serializes LocalizedText into bytes.
+//
+// The Locale and Text are serialized only if their masks are set in EncodingMask.
 func (l *LocalizedText) SerializeTo(b []byte) error {
 	b[0] = l.EncodingMask
 
 	var offset = 1
-	if l.Locale != nil {
+	if l.HasLocale() && l.Locale != nil {
 		if err := l.Locale.SerializeTo(b[offset:]); err != nil {
 			return err
 		}
 		offset += l.Locale.Len()
 	}
 
-	if l.Text != nil {
+	if l.HasText() && l.Text != nil {
 		if err := l.Text.SerializeTo(b[offset:]); err != nil {
 			return err
 		}
@@ -125,10 +131,10 @@ func (l *LocalizedText) SetTextMask() {
 // Len returns the actual length of LocalizedText in int.
 func (l *LocalizedText) Len() int {
 	var ll = 1
-	if l.Locale != nil {
+	if l.HasLocale() && l.Locale != nil {
 		ll += l.Locale.Len()
 	}
-	if l.Text != nil {
+	if l.HasText() && l.Text != nil {
 		ll += l.Text.Len()
 	}
 
@@ -48,3 +48,9 @@ func TestLocalizedText(t *testing.T) {
 		return DecodeLocalizedText(b)
 	})
 }
+
+func TestLocalizedTextTooShort(t *testing.T) {
+	if _, err := DecodeLocalizedText([]byte{}); err == nil {
+		t.Fatal("expected error for empty bytes, got nil")
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package datatypes
+
+import "github.com/wmnsk/gopcua/id"
+
+// MdnsDiscoveryConfiguration is a DiscoveryConfiguration given in RegisterServer2, which has
+// the name of the Server announced by the Discovery Server with mDNS and its capabilities,
+// e.g. "DA" or "HD". The MdnsServerName should be unique in the network.
+//
+// Specification: Part 4, 7.9.2
+type MdnsDiscoveryConfiguration struct {
+	MdnsServerName     *String
+	ServerCapabilities *StringArray
+}
+
+// NewMdnsDiscoveryConfiguration creates a new MdnsDiscoveryConfiguration.
+func NewMdnsDiscoveryConfiguration(name string, caps ...string) *MdnsDiscoveryConfiguration {
+	return &MdnsDiscoveryConfiguration{
+		MdnsServerName:     NewString(name),
+		ServerCapabilities: NewStringArray(caps),
+	}
+}
+
+// DecodeMdnsDiscoveryConfiguration decodes given bytes into MdnsDiscoveryConfiguration.
+func DecodeMdnsDiscoveryConfiguration(b []byte) (*MdnsDiscoveryConfiguration, error) {
+	m := &MdnsDiscoveryConfiguration{}
+	if err := m.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// DecodeFromBytes decodes given bytes into MdnsDiscoveryConfiguration.
+func (m *MdnsDiscoveryConfiguration) DecodeFromBytes(b []byte) error {
+	m.MdnsServerName = &String{}
+	if err := m.MdnsServerName.DecodeFromBytes(b); err != nil {
+		return err
+	}
+	offset := m.MdnsServerName.Len()
+
+	m.ServerCapabilities = &StringArray{}
+	return m.ServerCapabilities.DecodeFromBytes(b[offset:])
+}
+
+// Serialize serializes MdnsDiscoveryConfiguration into bytes.
+func (m *MdnsDiscoveryConfiguration) Serialize() ([]byte, error) {
+	b := make([]byte, m.Len())
+	if err := m.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes MdnsDiscoveryConfiguration into bytes.
+func (m *MdnsDiscoveryConfiguration) SerializeTo(b []byte) error {
+	offset := 0
+	if m.MdnsServerName != nil {
+		if err := m.MdnsServerName.SerializeTo(b); err != nil {
+			return err
+		}
+		offset += m.MdnsServerName.Len()
+	}
+
+	if m.ServerCapabilities != nil {
+		return m.ServerCapabilities.SerializeTo(b[offset:])
+	}
+
+	return nil
+}
+
+// Len returns the actual length of MdnsDiscoveryConfiguration in int.
+func (m *MdnsDiscoveryConfiguration) Len() int {
+	var l int
+	if m.MdnsServerName != nil {
+		l += m.MdnsServerName.Len()
+	}
+	if m.ServerCapabilities != nil {
+		l += m.ServerCapabilities.Len()
+	}
+
+	return l
+}
+
+// Type returns type of MdnsDiscoveryConfiguration defined in NodeIds.csv in int.
+func (m *MdnsDiscoveryConfiguration) Type() int {
+	return id.MdnsDiscoveryConfiguration_Encoding_DefaultBinary
+}
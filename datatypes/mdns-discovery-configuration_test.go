@@ -0,0 +1,40 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package datatypes
+
+import (
+	"testing"
+
+	"github.com/wmnsk/gopcua/utils/codectest"
+)
+
+func TestMdnsDiscoveryConfiguration(t *testing.T) {
+	cases := []codectest.Case{
+		{
+			Name:   "single-cap",
+			Struct: NewMdnsDiscoveryConfiguration("srv", "DA"),
+			Bytes: []byte{
+				// MdnsServerName
+				0x03, 0x00, 0x00, 0x00, 0x73, 0x72, 0x76,
+				// ServerCapabilities
+				0x01, 0x00, 0x00, 0x00,
+				0x02, 0x00, 0x00, 0x00, 0x44, 0x41,
+			},
+		},
+		{
+			Name:   "no-caps",
+			Struct: NewMdnsDiscoveryConfiguration("srv"),
+			Bytes: []byte{
+				// MdnsServerName
+				0x03, 0x00, 0x00, 0x00, 0x73, 0x72, 0x76,
+				// ServerCapabilities
+				0x00, 0x00, 0x00, 0x00,
+			},
+		},
+	}
+	codectest.Run(t, cases, func(b []byte) (codectest.S, error) {
+		return DecodeMdnsDiscoveryConfiguration(b)
+	})
+}
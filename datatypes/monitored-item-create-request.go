@@ -0,0 +1,213 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package datatypes
+
+import (
+	"encoding/binary"
+
+	"github.com/wmnsk/gopcua/errors"
+)
+
+// MonitoringMode specifies whether sampling and reporting are enabled or disabled for a MonitoredItem.
+//
+// Specification: Part 4, 7.17
+type MonitoringMode uint32
+
+// MonitoringMode definitions.
+const (
+	MonitoringModeDisabled MonitoringMode = iota
+	MonitoringModeSampling
+	MonitoringModeReporting
+)
+
+// MonitoredItemCreateRequest is an item to be created in CreateMonitoredItems.
+//
+// Specification: Part 4, 5.12.2.2
+type MonitoredItemCreateRequest struct {
+	ItemToMonitor       *ReadValueID
+	MonitoringMode      MonitoringMode
+	RequestedParameters *MonitoringParameters
+}
+
+// NewMonitoredItemCreateRequest creates a new MonitoredItemCreateRequest.
+func NewMonitoredItemCreateRequest(item *ReadValueID, mode MonitoringMode, params *MonitoringParameters) *MonitoredItemCreateRequest {
+	return &MonitoredItemCreateRequest{
+		ItemToMonitor:       item,
+		MonitoringMode:      mode,
+		RequestedParameters: params,
+	}
+}
+
+// NewEventMonitoredItemCreateRequest creates a new MonitoredItemCreateRequest for the EventNotifier
+// Attribute of the node, which reports the Events selected by the filter with the handle.
+// The Events are not sampled, and the oldest ones are discarded if the queue of queueSize overflows.
+func NewEventMonitoredItemCreateRequest(node *NodeID, handle uint32, filter *EventFilter, queueSize uint32) *MonitoredItemCreateRequest {
+	return NewMonitoredItemCreateRequest(
+		NewReadValueID(node, AttributeIDEventNotifier, "", 0, ""),
+		MonitoringModeReporting,
+		NewMonitoringParameters(handle, 0, filter, queueSize, true),
+	)
+}
+
+// DecodeMonitoredItemCreateRequest decodes given bytes into MonitoredItemCreateRequest.
+func DecodeMonitoredItemCreateRequest(b []byte) (*MonitoredItemCreateRequest, error) {
+	m := &MonitoredItemCreateRequest{}
+	if err := m.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// DecodeFromBytes decodes given bytes into MonitoredItemCreateRequest.
+func (m *MonitoredItemCreateRequest) DecodeFromBytes(b []byte) error {
+	m.ItemToMonitor = &ReadValueID{}
+	if err := m.ItemToMonitor.DecodeFromBytes(b); err != nil {
+		return err
+	}
+	offset := m.ItemToMonitor.Len()
+
+	if len(b[offset:]) < 4 {
+		return errors.NewErrTooShortToDecode(m, "should contain MonitoringMode")
+	}
+	m.MonitoringMode = MonitoringMode(binary.LittleEndian.Uint32(b[offset : offset+4]))
+	offset += 4
+
+	m.RequestedParameters = &MonitoringParameters{}
+	return m.RequestedParameters.DecodeFromBytes(b[offset:])
+}
+
+// Serialize serializes MonitoredItemCreateRequest into bytes.
+func (m *MonitoredItemCreateRequest) Serialize() ([]byte, error) {
+	b := make([]byte, m.Len())
+	if err := m.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes MonitoredItemCreateRequest into bytes.
+func (m *MonitoredItemCreateRequest) SerializeTo(b []byte) error {
+	offset := 0
+	if m.ItemToMonitor != nil {
+		if err := m.ItemToMonitor.SerializeTo(b); err != nil {
+			return err
+		}
+		offset += m.ItemToMonitor.Len()
+	}
+
+	binary.LittleEndian.PutUint32(b[offset:offset+4], uint32(m.MonitoringMode))
+	offset += 4
+
+	if m.RequestedParameters != nil {
+		return m.RequestedParameters.SerializeTo(b[offset:])
+	}
+
+	return nil
+}
+
+// Len returns the actual length of MonitoredItemCreateRequest in int.
+func (m *MonitoredItemCreateRequest) Len() int {
+	l := 4
+	if m.ItemToMonitor != nil {
+		l += m.ItemToMonitor.Len()
+	}
+	if m.RequestedParameters != nil {
+		l += m.RequestedParameters.Len()
+	}
+
+	return l
+}
+
+// MonitoredItemCreateRequestArray represents an array of MonitoredItemCreateRequests.
+// It does not correspond to a certain type from the specification
+// but makes encoding and decoding easier.
+type MonitoredItemCreateRequestArray struct {
+	ArraySize int32
+	Items     []*MonitoredItemCreateRequest
+}
+
+// NewMonitoredItemCreateRequestArray creates a new MonitoredItemCreateRequestArray from multiple MonitoredItemCreateRequests.
+func NewMonitoredItemCreateRequestArray(items []*MonitoredItemCreateRequest) *MonitoredItemCreateRequestArray {
+	if items == nil {
+		return &MonitoredItemCreateRequestArray{
+			ArraySize: 0,
+		}
+	}
+
+	return &MonitoredItemCreateRequestArray{
+		ArraySize: int32(len(items)),
+		Items:     items,
+	}
+}
+
+// DecodeMonitoredItemCreateRequestArray decodes given bytes into MonitoredItemCreateRequestArray.
+func DecodeMonitoredItemCreateRequestArray(b []byte) (*MonitoredItemCreateRequestArray, error) {
+	a := &MonitoredItemCreateRequestArray{}
+	if err := a.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// DecodeFromBytes decodes given bytes into MonitoredItemCreateRequestArray.
+func (a *MonitoredItemCreateRequestArray) DecodeFromBytes(b []byte) error {
+	if len(b) < 4 {
+		return errors.NewErrTooShortToDecode(a, "should be longer than 4 bytes")
+	}
+	a.ArraySize = int32(binary.LittleEndian.Uint32(b[:4]))
+	if a.ArraySize <= 0 {
+		return nil
+	}
+
+	offset := 4
+	for i := 1; i <= int(a.ArraySize); i++ {
+		m, err := DecodeMonitoredItemCreateRequest(b[offset:])
+		if err != nil {
+			return err
+		}
+		a.Items = append(a.Items, m)
+		offset += m.Len()
+	}
+
+	return nil
+}
+
+// Serialize serializes MonitoredItemCreateRequestArray into bytes.
+func (a *MonitoredItemCreateRequestArray) Serialize() ([]byte, error) {
+	b := make([]byte, a.Len())
+	if err := a.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes MonitoredItemCreateRequestArray into bytes.
+func (a *MonitoredItemCreateRequestArray) SerializeTo(b []byte) error {
+	offset := 4
+	binary.LittleEndian.PutUint32(b[:4], uint32(a.ArraySize))
+
+	for _, m := range a.Items {
+		if err := m.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += m.Len()
+	}
+
+	return nil
+}
+
+// Len returns the actual length in int.
+func (a *MonitoredItemCreateRequestArray) Len() int {
+	l := 4
+	for _, m := range a.Items {
+		l += m.Len()
+	}
+
+	return l
+}
@@ -0,0 +1,191 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package datatypes
+
+import (
+	"encoding/binary"
+	"math"
+
+	"github.com/wmnsk/gopcua/errors"
+)
+
+// MonitoredItemCreateResult is the result of CreateMonitoredItems for a single item.
+//
+// FilterResult is the null ExtensionObject unless the Server revised the filter requested.
+//
+// Specification: Part 4, 5.12.2.2
+type MonitoredItemCreateResult struct {
+	StatusCode              uint32
+	MonitoredItemID         uint32
+	RevisedSamplingInterval float64
+	RevisedQueueSize        uint32
+	FilterResult            *ExtensionObject
+}
+
+// NewMonitoredItemCreateResult creates a new MonitoredItemCreateResult.
+//
+// If filterResult is nil, the null ExtensionObject is set in FilterResult.
+func NewMonitoredItemCreateResult(code, itemID uint32, interval float64, queueSize uint32, filterResult ExtensionObjectValue) *MonitoredItemCreateResult {
+	r := &MonitoredItemCreateResult{
+		StatusCode:              code,
+		MonitoredItemID:         itemID,
+		RevisedSamplingInterval: interval,
+		RevisedQueueSize:        queueSize,
+		FilterResult:            NewNullExtensionObject(),
+	}
+	if filterResult != nil {
+		r.FilterResult = NewExtensionObject(0x01, filterResult)
+	}
+
+	return r
+}
+
+// DecodeMonitoredItemCreateResult decodes given bytes into MonitoredItemCreateResult.
+func DecodeMonitoredItemCreateResult(b []byte) (*MonitoredItemCreateResult, error) {
+	r := &MonitoredItemCreateResult{}
+	if err := r.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// DecodeFromBytes decodes given bytes into MonitoredItemCreateResult.
+func (r *MonitoredItemCreateResult) DecodeFromBytes(b []byte) error {
+	if len(b) < 20 {
+		return errors.NewErrTooShortToDecode(r, "should be longer than 20 bytes")
+	}
+	r.StatusCode = binary.LittleEndian.Uint32(b[0:4])
+	r.MonitoredItemID = binary.LittleEndian.Uint32(b[4:8])
+	r.RevisedSamplingInterval = math.Float64frombits(binary.LittleEndian.Uint64(b[8:16]))
+	r.RevisedQueueSize = binary.LittleEndian.Uint32(b[16:20])
+
+	r.FilterResult = &ExtensionObject{}
+	return r.FilterResult.DecodeFromBytes(b[20:])
+}
+
+// Serialize serializes MonitoredItemCreateResult into bytes.
+func (r *MonitoredItemCreateResult) Serialize() ([]byte, error) {
+	b := make([]byte, r.Len())
+	if err := r.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes MonitoredItemCreateResult into bytes.
+func (r *MonitoredItemCreateResult) SerializeTo(b []byte) error {
+	binary.LittleEndian.PutUint32(b[0:4], r.StatusCode)
+	binary.LittleEndian.PutUint32(b[4:8], r.MonitoredItemID)
+	binary.LittleEndian.PutUint64(b[8:16], math.Float64bits(r.RevisedSamplingInterval))
+	binary.LittleEndian.PutUint32(b[16:20], r.RevisedQueueSize)
+
+	if r.FilterResult != nil {
+		return r.FilterResult.SerializeTo(b[20:])
+	}
+
+	return nil
+}
+
+// Len returns the actual length of MonitoredItemCreateResult in int.
+func (r *MonitoredItemCreateResult) Len() int {
+	l := 20
+	if r.FilterResult != nil {
+		l += r.FilterResult.Len()
+	}
+
+	return l
+}
+
+// MonitoredItemCreateResultArray represents an array of MonitoredItemCreateResults.
+// It does not correspond to a certain type from the specification
+// but makes encoding and decoding easier.
+type MonitoredItemCreateResultArray struct {
+	ArraySize int32
+	Results   []*MonitoredItemCreateResult
+}
+
+// NewMonitoredItemCreateResultArray creates a new MonitoredItemCreateResultArray from multiple MonitoredItemCreateResults.
+func NewMonitoredItemCreateResultArray(results []*MonitoredItemCreateResult) *MonitoredItemCreateResultArray {
+	if results == nil {
+		return &MonitoredItemCreateResultArray{
+			ArraySize: 0,
+		}
+	}
+
+	return &MonitoredItemCreateResultArray{
+		ArraySize: int32(len(results)),
+		Results:   results,
+	}
+}
+
+// DecodeMonitoredItemCreateResultArray decodes given bytes into MonitoredItemCreateResultArray.
+func DecodeMonitoredItemCreateResultArray(b []byte) (*MonitoredItemCreateResultArray, error) {
+	a := &MonitoredItemCreateResultArray{}
+	if err := a.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// DecodeFromBytes decodes given bytes into MonitoredItemCreateResultArray.
+func (a *MonitoredItemCreateResultArray) DecodeFromBytes(b []byte) error {
+	if len(b) < 4 {
+		return errors.NewErrTooShortToDecode(a, "should be longer than 4 bytes")
+	}
+	a.ArraySize = int32(binary.LittleEndian.Uint32(b[:4]))
+	if a.ArraySize <= 0 {
+		return nil
+	}
+
+	offset := 4
+	for i := 1; i <= int(a.ArraySize); i++ {
+		r, err := DecodeMonitoredItemCreateResult(b[offset:])
+		if err != nil {
+			return err
+		}
+		a.Results = append(a.Results, r)
+		offset += r.Len()
+	}
+
+	return nil
+}
+
+// Serialize serializes MonitoredItemCreateResultArray into bytes.
+func (a *MonitoredItemCreateResultArray) Serialize() ([]byte, error) {
+	b := make([]byte, a.Len())
+	if err := a.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes MonitoredItemCreateResultArray into bytes.
+func (a *MonitoredItemCreateResultArray) SerializeTo(b []byte) error {
+	offset := 4
+	binary.LittleEndian.PutUint32(b[:4], uint32(a.ArraySize))
+
+	for _, r := range a.Results {
+		if err := r.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += r.Len()
+	}
+
+	return nil
+}
+
+// Len returns the actual length in int.
+func (a *MonitoredItemCreateResultArray) Len() int {
+	l := 4
+	for _, r := range a.Results {
+		l += r.Len()
+	}
+
+	return l
+}
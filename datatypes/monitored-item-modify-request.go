@@ -0,0 +1,169 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package datatypes
+
+import (
+	"encoding/binary"
+
+	"github.com/wmnsk/gopcua/errors"
+)
+
+// MonitoredItemModifyRequest is an item to be modified in ModifyMonitoredItems.
+//
+// Specification: Part 4, 5.12.3.2
+type MonitoredItemModifyRequest struct {
+	MonitoredItemID     uint32
+	RequestedParameters *MonitoringParameters
+}
+
+// NewMonitoredItemModifyRequest creates a new MonitoredItemModifyRequest.
+func NewMonitoredItemModifyRequest(itemID uint32, params *MonitoringParameters) *MonitoredItemModifyRequest {
+	return &MonitoredItemModifyRequest{
+		MonitoredItemID:     itemID,
+		RequestedParameters: params,
+	}
+}
+
+// DecodeMonitoredItemModifyRequest decodes given bytes into MonitoredItemModifyRequest.
+func DecodeMonitoredItemModifyRequest(b []byte) (*MonitoredItemModifyRequest, error) {
+	m := &MonitoredItemModifyRequest{}
+	if err := m.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// DecodeFromBytes decodes given bytes into MonitoredItemModifyRequest.
+func (m *MonitoredItemModifyRequest) DecodeFromBytes(b []byte) error {
+	if len(b) < 4 {
+		return errors.NewErrTooShortToDecode(m, "should be longer than 4 bytes")
+	}
+	m.MonitoredItemID = binary.LittleEndian.Uint32(b[:4])
+
+	m.RequestedParameters = &MonitoringParameters{}
+	return m.RequestedParameters.DecodeFromBytes(b[4:])
+}
+
+// Serialize serializes MonitoredItemModifyRequest into bytes.
+func (m *MonitoredItemModifyRequest) Serialize() ([]byte, error) {
+	b := make([]byte, m.Len())
+	if err := m.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes MonitoredItemModifyRequest into bytes.
+func (m *MonitoredItemModifyRequest) SerializeTo(b []byte) error {
+	binary.LittleEndian.PutUint32(b[:4], m.MonitoredItemID)
+
+	if m.RequestedParameters != nil {
+		return m.RequestedParameters.SerializeTo(b[4:])
+	}
+
+	return nil
+}
+
+// Len returns the actual length of MonitoredItemModifyRequest in int.
+func (m *MonitoredItemModifyRequest) Len() int {
+	l := 4
+	if m.RequestedParameters != nil {
+		l += m.RequestedParameters.Len()
+	}
+
+	return l
+}
+
+// MonitoredItemModifyRequestArray represents an array of MonitoredItemModifyRequests.
+// It does not correspond to a certain type from the specification
+// but makes encoding and decoding easier.
+type MonitoredItemModifyRequestArray struct {
+	ArraySize int32
+	Items     []*MonitoredItemModifyRequest
+}
+
+// NewMonitoredItemModifyRequestArray creates a new MonitoredItemModifyRequestArray from multiple MonitoredItemModifyRequests.
+func NewMonitoredItemModifyRequestArray(items []*MonitoredItemModifyRequest) *MonitoredItemModifyRequestArray {
+	if items == nil {
+		return &MonitoredItemModifyRequestArray{
+			ArraySize: 0,
+		}
+	}
+
+	return &MonitoredItemModifyRequestArray{
+		ArraySize: int32(len(items)),
+		Items:     items,
+	}
+}
+
+// DecodeMonitoredItemModifyRequestArray decodes given bytes into MonitoredItemModifyRequestArray.
+func DecodeMonitoredItemModifyRequestArray(b []byte) (*MonitoredItemModifyRequestArray, error) {
+	a := &MonitoredItemModifyRequestArray{}
+	if err := a.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// DecodeFromBytes decodes given bytes into MonitoredItemModifyRequestArray.
+func (a *MonitoredItemModifyRequestArray) DecodeFromBytes(b []byte) error {
+	if len(b) < 4 {
+		return errors.NewErrTooShortToDecode(a, "should be longer than 4 bytes")
+	}
+	a.ArraySize = int32(binary.LittleEndian.Uint32(b[:4]))
+	if a.ArraySize <= 0 {
+		return nil
+	}
+
+	offset := 4
+	for i := 1; i <= int(a.ArraySize); i++ {
+		m, err := DecodeMonitoredItemModifyRequest(b[offset:])
+		if err != nil {
+			return err
+		}
+		a.Items = append(a.Items, m)
+		offset += m.Len()
+	}
+
+	return nil
+}
+
+// Serialize serializes MonitoredItemModifyRequestArray into bytes.
+func (a *MonitoredItemModifyRequestArray) Serialize() ([]byte, error) {
+	b := make([]byte, a.Len())
+	if err := a.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes MonitoredItemModifyRequestArray into bytes.
+func (a *MonitoredItemModifyRequestArray) SerializeTo(b []byte) error {
+	offset := 4
+	binary.LittleEndian.PutUint32(b[:4], uint32(a.ArraySize))
+
+	for _, m := range a.Items {
+		if err := m.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += m.Len()
+	}
+
+	return nil
+}
+
+// Len returns the actual length in int.
+func (a *MonitoredItemModifyRequestArray) Len() int {
+	l := 4
+	for _, m := range a.Items {
+		l += m.Len()
+	}
+
+	return l
+}
@@ -0,0 +1,33 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package datatypes
+
+import (
+	"testing"
+
+	"github.com/wmnsk/gopcua/utils/codectest"
+)
+
+func TestMonitoredItemModifyRequest(t *testing.T) {
+	cases := []codectest.Case{
+		{
+			Name:   "no-filter",
+			Struct: NewMonitoredItemModifyRequest(3, NewMonitoringParameters(1, 1000, nil, 10, true)),
+			Bytes: []byte{
+				// MonitoredItemID
+				0x03, 0x00, 0x00, 0x00,
+				// RequestedParameters
+				0x01, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x40, 0x8f, 0x40,
+				0x00, 0x00, 0x00,
+				0x0a, 0x00, 0x00, 0x00,
+				0x01,
+			},
+		},
+	}
+	codectest.Run(t, cases, func(b []byte) (codectest.S, error) {
+		return DecodeMonitoredItemModifyRequest(b)
+	})
+}
@@ -0,0 +1,188 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package datatypes
+
+import (
+	"encoding/binary"
+	"math"
+
+	"github.com/wmnsk/gopcua/errors"
+)
+
+// MonitoredItemModifyResult is the result of ModifyMonitoredItems for a single item,
+// which has the parameters revised by the Server.
+//
+// FilterResult is the null ExtensionObject unless the Server revised the filter requested.
+//
+// Specification: Part 4, 5.12.3.2
+type MonitoredItemModifyResult struct {
+	StatusCode              uint32
+	RevisedSamplingInterval float64
+	RevisedQueueSize        uint32
+	FilterResult            *ExtensionObject
+}
+
+// NewMonitoredItemModifyResult creates a new MonitoredItemModifyResult.
+//
+// If filterResult is nil, the null ExtensionObject is set in FilterResult.
+func NewMonitoredItemModifyResult(code uint32, interval float64, queueSize uint32, filterResult ExtensionObjectValue) *MonitoredItemModifyResult {
+	r := &MonitoredItemModifyResult{
+		StatusCode:              code,
+		RevisedSamplingInterval: interval,
+		RevisedQueueSize:        queueSize,
+		FilterResult:            NewNullExtensionObject(),
+	}
+	if filterResult != nil {
+		r.FilterResult = NewExtensionObject(0x01, filterResult)
+	}
+
+	return r
+}
+
+// DecodeMonitoredItemModifyResult decodes given bytes into MonitoredItemModifyResult.
+func DecodeMonitoredItemModifyResult(b []byte) (*MonitoredItemModifyResult, error) {
+	r := &MonitoredItemModifyResult{}
+	if err := r.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// DecodeFromBytes decodes given bytes into MonitoredItemModifyResult.
+func (r *MonitoredItemModifyResult) DecodeFromBytes(b []byte) error {
+	if len(b) < 16 {
+		return errors.NewErrTooShortToDecode(r, "should be longer than 16 bytes")
+	}
+	r.StatusCode = binary.LittleEndian.Uint32(b[0:4])
+	r.RevisedSamplingInterval = math.Float64frombits(binary.LittleEndian.Uint64(b[4:12]))
+	r.RevisedQueueSize = binary.LittleEndian.Uint32(b[12:16])
+
+	r.FilterResult = &ExtensionObject{}
+	return r.FilterResult.DecodeFromBytes(b[16:])
+}
+
+// Serialize serializes MonitoredItemModifyResult into bytes.
+func (r *MonitoredItemModifyResult) Serialize() ([]byte, error) {
+	b := make([]byte, r.Len())
+	if err := r.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes MonitoredItemModifyResult into bytes.
+func (r *MonitoredItemModifyResult) SerializeTo(b []byte) error {
+	binary.LittleEndian.PutUint32(b[0:4], r.StatusCode)
+	binary.LittleEndian.PutUint64(b[4:12], math.Float64bits(r.RevisedSamplingInterval))
+	binary.LittleEndian.PutUint32(b[12:16], r.RevisedQueueSize)
+
+	if r.FilterResult != nil {
+		return r.FilterResult.SerializeTo(b[16:])
+	}
+
+	return nil
+}
+
+// Len returns the actual length of MonitoredItemModifyResult in int.
+func (r *MonitoredItemModifyResult) Len() int {
+	l := 16
+	if r.FilterResult != nil {
+		l += r.FilterResult.Len()
+	}
+
+	return l
+}
+
+// MonitoredItemModifyResultArray represents an array of MonitoredItemModifyResults.
+// It does not correspond to a certain type from the specification
+// but makes encoding and decoding easier.
+type MonitoredItemModifyResultArray struct {
+	ArraySize int32
+	Results   []*MonitoredItemModifyResult
+}
+
+// NewMonitoredItemModifyResultArray creates a new MonitoredItemModifyResultArray from multiple MonitoredItemModifyResults.
+func NewMonitoredItemModifyResultArray(results []*MonitoredItemModifyResult) *MonitoredItemModifyResultArray {
+	if results == nil {
+		return &MonitoredItemModifyResultArray{
+			ArraySize: 0,
+		}
+	}
+
+	return &MonitoredItemModifyResultArray{
+		ArraySize: int32(len(results)),
+		Results:   results,
+	}
+}
+
+// DecodeMonitoredItemModifyResultArray decodes given bytes into MonitoredItemModifyResultArray.
+func DecodeMonitoredItemModifyResultArray(b []byte) (*MonitoredItemModifyResultArray, error) {
+	a := &MonitoredItemModifyResultArray{}
+	if err := a.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// DecodeFromBytes decodes given bytes into MonitoredItemModifyResultArray.
+func (a *MonitoredItemModifyResultArray) DecodeFromBytes(b []byte) error {
+	if len(b) < 4 {
+		return errors.NewErrTooShortToDecode(a, "should be longer than 4 bytes")
+	}
+	a.ArraySize = int32(binary.LittleEndian.Uint32(b[:4]))
+	if a.ArraySize <= 0 {
+		return nil
+	}
+
+	offset := 4
+	for i := 1; i <= int(a.ArraySize); i++ {
+		r, err := DecodeMonitoredItemModifyResult(b[offset:])
+		if err != nil {
+			return err
+		}
+		a.Results = append(a.Results, r)
+		offset += r.Len()
+	}
+
+	return nil
+}
+
+// Serialize serializes MonitoredItemModifyResultArray into bytes.
+func (a *MonitoredItemModifyResultArray) Serialize() ([]byte, error) {
+	b := make([]byte, a.Len())
+	if err := a.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes MonitoredItemModifyResultArray into bytes.
+func (a *MonitoredItemModifyResultArray) SerializeTo(b []byte) error {
+	offset := 4
+	binary.LittleEndian.PutUint32(b[:4], uint32(a.ArraySize))
+
+	for _, r := range a.Results {
+		if err := r.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += r.Len()
+	}
+
+	return nil
+}
+
+// Len returns the actual length in int.
+func (a *MonitoredItemModifyResultArray) Len() int {
+	l := 4
+	for _, r := range a.Results {
+		l += r.Len()
+	}
+
+	return l
+}
@@ -0,0 +1,33 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package datatypes
+
+import (
+	"testing"
+
+	"github.com/wmnsk/gopcua/utils/codectest"
+)
+
+func TestMonitoredItemModifyResult(t *testing.T) {
+	cases := []codectest.Case{
+		{
+			Name:   "no-filter-result",
+			Struct: NewMonitoredItemModifyResult(0, 1000, 10, nil),
+			Bytes: []byte{
+				// StatusCode
+				0x00, 0x00, 0x00, 0x00,
+				// RevisedSamplingInterval
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x40, 0x8f, 0x40,
+				// RevisedQueueSize
+				0x0a, 0x00, 0x00, 0x00,
+				// FilterResult
+				0x00, 0x00, 0x00,
+			},
+		},
+	}
+	codectest.Run(t, cases, func(b []byte) (codectest.S, error) {
+		return DecodeMonitoredItemModifyResult(b)
+	})
+}
@@ -0,0 +1,172 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package datatypes
+
+import (
+	"encoding/binary"
+
+	"github.com/wmnsk/gopcua/errors"
+)
+
+// MonitoredItemNotification is the change of the value of a single MonitoredItem
+// reported in DataChangeNotification.
+//
+// ClientHandle is the one given by the Client in CreateMonitoredItems.
+//
+// Specification: Part 4, 7.20.2
+type MonitoredItemNotification struct {
+	ClientHandle uint32
+	Value        *DataValue
+}
+
+// NewMonitoredItemNotification creates a new MonitoredItemNotification.
+func NewMonitoredItemNotification(handle uint32, value *DataValue) *MonitoredItemNotification {
+	return &MonitoredItemNotification{
+		ClientHandle: handle,
+		Value:        value,
+	}
+}
+
+// DecodeMonitoredItemNotification decodes given bytes into MonitoredItemNotification.
+func DecodeMonitoredItemNotification(b []byte) (*MonitoredItemNotification, error) {
+	m := &MonitoredItemNotification{}
+	if err := m.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// DecodeFromBytes decodes given bytes into MonitoredItemNotification.
+func (m *MonitoredItemNotification) DecodeFromBytes(b []byte) error {
+	if len(b) < 5 {
+		return errors.NewErrTooShortToDecode(m, "should be longer than 5 bytes")
+	}
+	m.ClientHandle = binary.LittleEndian.Uint32(b[:4])
+
+	m.Value = &DataValue{}
+	return m.Value.DecodeFromBytes(b[4:])
+}
+
+// Serialize serializes MonitoredItemNotification into bytes.
+func (m *MonitoredItemNotification) Serialize() ([]byte, error) {
+	b := make([]byte, m.Len())
+	if err := m.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes MonitoredItemNotification into bytes.
+func (m *MonitoredItemNotification) SerializeTo(b []byte) error {
+	binary.LittleEndian.PutUint32(b[:4], m.ClientHandle)
+
+	if m.Value != nil {
+		return m.Value.SerializeTo(b[4:])
+	}
+
+	return nil
+}
+
+// Len returns the actual length of MonitoredItemNotification in int.
+func (m *MonitoredItemNotification) Len() int {
+	l := 4
+	if m.Value != nil {
+		l += m.Value.Len()
+	}
+
+	return l
+}
+
+// MonitoredItemNotificationArray represents an array of MonitoredItemNotifications.
+// It does not correspond to a certain type from the specification
+// but makes encoding and decoding easier.
+type MonitoredItemNotificationArray struct {
+	ArraySize     int32
+	Notifications []*MonitoredItemNotification
+}
+
+// NewMonitoredItemNotificationArray creates a new MonitoredItemNotificationArray from multiple MonitoredItemNotifications.
+func NewMonitoredItemNotificationArray(notifications []*MonitoredItemNotification) *MonitoredItemNotificationArray {
+	if notifications == nil {
+		return &MonitoredItemNotificationArray{
+			ArraySize: 0,
+		}
+	}
+
+	return &MonitoredItemNotificationArray{
+		ArraySize:     int32(len(notifications)),
+		Notifications: notifications,
+	}
+}
+
+// DecodeMonitoredItemNotificationArray decodes given bytes into MonitoredItemNotificationArray.
+func DecodeMonitoredItemNotificationArray(b []byte) (*MonitoredItemNotificationArray, error) {
+	a := &MonitoredItemNotificationArray{}
+	if err := a.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// DecodeFromBytes decodes given bytes into MonitoredItemNotificationArray.
+func (a *MonitoredItemNotificationArray) DecodeFromBytes(b []byte) error {
+	if len(b) < 4 {
+		return errors.NewErrTooShortToDecode(a, "should be longer than 4 bytes")
+	}
+	a.ArraySize = int32(binary.LittleEndian.Uint32(b[:4]))
+	if a.ArraySize <= 0 {
+		return nil
+	}
+
+	offset := 4
+	for i := 1; i <= int(a.ArraySize); i++ {
+		m, err := DecodeMonitoredItemNotification(b[offset:])
+		if err != nil {
+			return err
+		}
+		a.Notifications = append(a.Notifications, m)
+		offset += m.Len()
+	}
+
+	return nil
+}
+
+// Serialize serializes MonitoredItemNotificationArray into bytes.
+func (a *MonitoredItemNotificationArray) Serialize() ([]byte, error) {
+	b := make([]byte, a.Len())
+	if err := a.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes MonitoredItemNotificationArray into bytes.
+func (a *MonitoredItemNotificationArray) SerializeTo(b []byte) error {
+	offset := 4
+	binary.LittleEndian.PutUint32(b[:4], uint32(a.ArraySize))
+
+	for _, m := range a.Notifications {
+		if err := m.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += m.Len()
+	}
+
+	return nil
+}
+
+// Len returns the actual length in int.
+func (a *MonitoredItemNotificationArray) Len() int {
+	l := 4
+	for _, m := range a.Notifications {
+		l += m.Len()
+	}
+
+	return l
+}
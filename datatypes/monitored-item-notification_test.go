@@ -0,0 +1,58 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package datatypes
+
+import (
+	"testing"
+
+	"github.com/wmnsk/gopcua/utils/codectest"
+)
+
+func TestMonitoredItemNotification(t *testing.T) {
+	cases := []codectest.Case{
+		{
+			Name: "normal",
+			Struct: NewMonitoredItemNotification(
+				1, &DataValue{EncodingMask: 0x01, Value: NewVariant(NewFloat(2.50025))},
+			),
+			Bytes: []byte{
+				// ClientHandle
+				0x01, 0x00, 0x00, 0x00,
+				// Value
+				0x01, 0x0a, 0x19, 0x04, 0x20, 0x40,
+			},
+		},
+	}
+	codectest.Run(t, cases, func(b []byte) (codectest.S, error) {
+		return DecodeMonitoredItemNotification(b)
+	})
+}
+
+func TestMonitoredItemNotificationArray(t *testing.T) {
+	cases := []codectest.Case{
+		{
+			Name: "normal",
+			Struct: NewMonitoredItemNotificationArray([]*MonitoredItemNotification{
+				NewMonitoredItemNotification(1, &DataValue{EncodingMask: 0x01, Value: NewVariant(NewFloat(2.50025))}),
+				NewMonitoredItemNotification(2, &DataValue{EncodingMask: 0x02, Status: 0x80000000}),
+			}),
+			Bytes: []byte{
+				// ArraySize
+				0x02, 0x00, 0x00, 0x00,
+				// ClientHandle
+				0x01, 0x00, 0x00, 0x00,
+				// Value
+				0x01, 0x0a, 0x19, 0x04, 0x20, 0x40,
+				// ClientHandle
+				0x02, 0x00, 0x00, 0x00,
+				// Value
+				0x02, 0x00, 0x00, 0x00, 0x80,
+			},
+		},
+	}
+	codectest.Run(t, cases, func(b []byte) (codectest.S, error) {
+		return DecodeMonitoredItemNotificationArray(b)
+	})
+}
@@ -0,0 +1,125 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package datatypes
+
+import (
+	"encoding/binary"
+	"math"
+
+	"github.com/wmnsk/gopcua/errors"
+)
+
+// MonitoringParameters is the set of parameters requested for a MonitoredItem.
+//
+// Filter is a MonitoringFilter such as DataChangeFilter encoded as an ExtensionObject.
+// It is the null ExtensionObject if no filter is used.
+//
+// Specification: Part 4, 7.16
+type MonitoringParameters struct {
+	ClientHandle     uint32
+	SamplingInterval float64
+	Filter           *ExtensionObject
+	QueueSize        uint32
+	DiscardOldest    *Boolean
+}
+
+// NewMonitoringParameters creates a new MonitoringParameters.
+//
+// If filter is nil, the null ExtensionObject is set in Filter.
+func NewMonitoringParameters(handle uint32, interval float64, filter ExtensionObjectValue, queueSize uint32, discardOldest bool) *MonitoringParameters {
+	p := &MonitoringParameters{
+		ClientHandle:     handle,
+		SamplingInterval: interval,
+		Filter:           NewNullExtensionObject(),
+		QueueSize:        queueSize,
+		DiscardOldest:    NewBoolean(discardOldest),
+	}
+	if filter != nil {
+		p.Filter = NewExtensionObject(0x01, filter)
+	}
+
+	return p
+}
+
+// DecodeMonitoringParameters decodes given bytes into MonitoringParameters.
+func DecodeMonitoringParameters(b []byte) (*MonitoringParameters, error) {
+	p := &MonitoringParameters{}
+	if err := p.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// DecodeFromBytes decodes given bytes into MonitoringParameters.
+func (p *MonitoringParameters) DecodeFromBytes(b []byte) error {
+	if len(b) < 12 {
+		return errors.NewErrTooShortToDecode(p, "should be longer than 12 bytes")
+	}
+	p.ClientHandle = binary.LittleEndian.Uint32(b[0:4])
+	p.SamplingInterval = math.Float64frombits(binary.LittleEndian.Uint64(b[4:12]))
+	offset := 12
+
+	p.Filter = &ExtensionObject{}
+	if err := p.Filter.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += p.Filter.Len()
+
+	if len(b[offset:]) < 5 {
+		return errors.NewErrTooShortToDecode(p, "should contain QueueSize and DiscardOldest")
+	}
+	p.QueueSize = binary.LittleEndian.Uint32(b[offset : offset+4])
+	offset += 4
+
+	p.DiscardOldest = &Boolean{}
+	return p.DiscardOldest.DecodeFromBytes(b[offset:])
+}
+
+// Serialize serializes MonitoringParameters into bytes.
+func (p *MonitoringParameters) Serialize() ([]byte, error) {
+	b := make([]byte, p.Len())
+	if err := p.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes MonitoringParameters into bytes.
+func (p *MonitoringParameters) SerializeTo(b []byte) error {
+	binary.LittleEndian.PutUint32(b[0:4], p.ClientHandle)
+	binary.LittleEndian.PutUint64(b[4:12], math.Float64bits(p.SamplingInterval))
+	offset := 12
+
+	if p.Filter != nil {
+		if err := p.Filter.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += p.Filter.Len()
+	}
+
+	binary.LittleEndian.PutUint32(b[offset:offset+4], p.QueueSize)
+	offset += 4
+
+	if p.DiscardOldest != nil {
+		return p.DiscardOldest.SerializeTo(b[offset:])
+	}
+
+	return nil
+}
+
+// Len returns the actual length of MonitoringParameters in int.
+func (p *MonitoringParameters) Len() int {
+	l := 16
+	if p.Filter != nil {
+		l += p.Filter.Len()
+	}
+	if p.DiscardOldest != nil {
+		l += p.DiscardOldest.Len()
+	}
+
+	return l
+}
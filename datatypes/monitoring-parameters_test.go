@@ -0,0 +1,55 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package datatypes
+
+import (
+	"testing"
+
+	"github.com/wmnsk/gopcua/utils/codectest"
+)
+
+func TestMonitoringParameters(t *testing.T) {
+	cases := []codectest.Case{
+		{
+			Name:   "no-filter",
+			Struct: NewMonitoringParameters(1, 1000, nil, 10, true),
+			Bytes: []byte{
+				// ClientHandle
+				0x01, 0x00, 0x00, 0x00,
+				// SamplingInterval
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x40, 0x8f, 0x40,
+				// Filter
+				0x00, 0x00, 0x00,
+				// QueueSize
+				0x0a, 0x00, 0x00, 0x00,
+				// DiscardOldest
+				0x01,
+			},
+		},
+		{
+			Name: "data-change-filter",
+			Struct: NewMonitoringParameters(
+				1, 1000, NewDataChangeFilter(DataChangeTriggerStatusValue, DeadbandTypeAbsolute, 0.5), 10, true,
+			),
+			Bytes: []byte{
+				// ClientHandle
+				0x01, 0x00, 0x00, 0x00,
+				// SamplingInterval
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x40, 0x8f, 0x40,
+				// Filter
+				0x01, 0x00, 0xd4, 0x02, 0x01, 0x10, 0x00, 0x00, 0x00,
+				0x01, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xe0, 0x3f,
+				// QueueSize
+				0x0a, 0x00, 0x00, 0x00,
+				// DiscardOldest
+				0x01,
+			},
+		},
+	}
+	codectest.Run(t, cases, func(b []byte) (codectest.S, error) {
+		return DecodeMonitoringParameters(b)
+	})
+}
@@ -0,0 +1,845 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package datatypes
+
+import (
+	"math"
+
+	"github.com/wmnsk/gopcua/errors"
+	"github.com/wmnsk/gopcua/id"
+)
+
+// NodeAttributesMask is the bit mask of SpecifiedAttributes in the NodeAttributes,
+// which tells the Attributes set by the Client to be used by the Server.
+//
+// Specification: Part 4, 7.19.1
+const (
+	NodeAttributesMaskAccessLevel             uint32 = 1
+	NodeAttributesMaskArrayDimensions         uint32 = 2
+	NodeAttributesMaskBrowseName              uint32 = 4
+	NodeAttributesMaskContainsNoLoops         uint32 = 8
+	NodeAttributesMaskDataType                uint32 = 16
+	NodeAttributesMaskDescription             uint32 = 32
+	NodeAttributesMaskDisplayName             uint32 = 64
+	NodeAttributesMaskEventNotifier           uint32 = 128
+	NodeAttributesMaskExecutable              uint32 = 256
+	NodeAttributesMaskHistorizing             uint32 = 512
+	NodeAttributesMaskInverseName             uint32 = 1024
+	NodeAttributesMaskIsAbstract              uint32 = 2048
+	NodeAttributesMaskMinimumSamplingInterval uint32 = 4096
+	NodeAttributesMaskNodeClass               uint32 = 8192
+	NodeAttributesMaskNodeID                  uint32 = 16384
+	NodeAttributesMaskSymmetric               uint32 = 32768
+	NodeAttributesMaskUserAccessLevel         uint32 = 65536
+	NodeAttributesMaskUserExecutable          uint32 = 131072
+	NodeAttributesMaskUserWriteMask           uint32 = 262144
+	NodeAttributesMaskValueRank               uint32 = 524288
+	NodeAttributesMaskWriteMask               uint32 = 1048576
+	NodeAttributesMaskValue                   uint32 = 2097152
+)
+
+// NodeClassAttributes is the NodeAttributes of a NodeClass, such as VariableAttributes,
+// which is given to AddNodes as the ExtensionObject in the AddNodesItem.
+type NodeClassAttributes interface {
+	ExtensionObjectValue
+	NodeClass() NodeClass
+}
+
+// NodeAttributes are the Attributes common to the NodeAttributes of all the NodeClasses.
+//
+// The constructors of the NodeAttributes of each NodeClass set the bits in SpecifiedAttributes
+// for the Attributes given. It should be updated if the fields are set directly.
+//
+// Specification: Part 4, 7.19.1
+type NodeAttributes struct {
+	SpecifiedAttributes uint32
+	DisplayName         *LocalizedText
+	Description         *LocalizedText
+	WriteMask           uint32
+	UserWriteMask       uint32
+}
+
+// newNodeAttributes creates a new NodeAttributes with the mask of the Attributes of the NodeClass
+// and the ones of displayName and description if they are not nil.
+func newNodeAttributes(mask uint32, displayName, description *LocalizedText) *NodeAttributes {
+	a := &NodeAttributes{
+		SpecifiedAttributes: mask,
+		DisplayName:         &LocalizedText{},
+		Description:         &LocalizedText{},
+	}
+	if displayName != nil {
+		a.DisplayName = displayName
+		a.SpecifiedAttributes |= NodeAttributesMaskDisplayName
+	}
+	if description != nil {
+		a.Description = description
+		a.SpecifiedAttributes |= NodeAttributesMaskDescription
+	}
+
+	return a
+}
+
+// decodeFrom decodes the common Attributes from dec.
+func (a *NodeAttributes) decodeFrom(dec *Decoder) error {
+	var err error
+	if a.SpecifiedAttributes, err = dec.ReadUint32(); err != nil {
+		return errors.NewErrTooShortToDecode(a, "should contain SpecifiedAttributes")
+	}
+	a.DisplayName = &LocalizedText{}
+	if err := dec.ReadData(a.DisplayName); err != nil {
+		return err
+	}
+	a.Description = &LocalizedText{}
+	if err := dec.ReadData(a.Description); err != nil {
+		return err
+	}
+	if a.WriteMask, err = dec.ReadUint32(); err != nil {
+		return errors.NewErrTooShortToDecode(a, "should contain WriteMask")
+	}
+	if a.UserWriteMask, err = dec.ReadUint32(); err != nil {
+		return errors.NewErrTooShortToDecode(a, "should contain UserWriteMask")
+	}
+
+	return nil
+}
+
+// encode writes the common Attributes to enc.
+func (a *NodeAttributes) encode(enc *Encoder) error {
+	if a == nil {
+		a = &NodeAttributes{}
+	}
+	enc.WriteUint32(a.SpecifiedAttributes)
+	if err := enc.WriteData(a.displayName()); err != nil {
+		return err
+	}
+	if err := enc.WriteData(a.description()); err != nil {
+		return err
+	}
+	enc.WriteUint32(a.WriteMask)
+	enc.WriteUint32(a.UserWriteMask)
+
+	return nil
+}
+
+// Len returns the actual length of NodeAttributes in int.
+func (a *NodeAttributes) Len() int {
+	if a == nil {
+		return 14
+	}
+	return 12 + a.displayName().Len() + a.description().Len()
+}
+
+// displayName returns the DisplayName, or the empty LocalizedText if it is nil.
+func (a *NodeAttributes) displayName() *LocalizedText {
+	if a.DisplayName == nil {
+		return &LocalizedText{}
+	}
+	return a.DisplayName
+}
+
+// description returns the Description, or the empty LocalizedText if it is nil.
+func (a *NodeAttributes) description() *LocalizedText {
+	if a.Description == nil {
+		return &LocalizedText{}
+	}
+	return a.Description
+}
+
+// encodeNodeAttributes serializes the NodeAttributes of a NodeClass into b with encode.
+func encodeNodeAttributes(v ExtensionObjectValue, b []byte, encode func(*Encoder) error) error {
+	if len(b) < v.Len() {
+		return errors.NewErrInvalidLength(v, "bytes should be longer")
+	}
+
+	// b is long enough that enc never reallocates.
+	enc := Encoder{buf: b[:0]}
+	return encode(&enc)
+}
+
+// serializeNodeAttributes serializes the NodeAttributes of a NodeClass into a new byte slice.
+func serializeNodeAttributes(v ExtensionObjectValue) ([]byte, error) {
+	b := make([]byte, v.Len())
+	if err := v.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// ObjectAttributes is the NodeAttributes of an Object.
+//
+// Specification: Part 4, 7.19.2
+type ObjectAttributes struct {
+	*NodeAttributes
+	EventNotifier uint8
+}
+
+// NewObjectAttributes creates a new ObjectAttributes.
+// The displayName and description are not specified if they are nil.
+func NewObjectAttributes(displayName, description *LocalizedText, eventNotifier uint8) *ObjectAttributes {
+	return &ObjectAttributes{
+		NodeAttributes: newNodeAttributes(NodeAttributesMaskEventNotifier, displayName, description),
+		EventNotifier:  eventNotifier,
+	}
+}
+
+// DecodeFromBytes decodes given bytes into ObjectAttributes.
+func (o *ObjectAttributes) DecodeFromBytes(b []byte) error {
+	dec := Decoder{b: b}
+	o.NodeAttributes = &NodeAttributes{}
+	if err := o.NodeAttributes.decodeFrom(&dec); err != nil {
+		return err
+	}
+
+	var err error
+	if o.EventNotifier, err = dec.ReadUint8(); err != nil {
+		return errors.NewErrTooShortToDecode(o, "should contain EventNotifier")
+	}
+
+	return nil
+}
+
+// Serialize serializes ObjectAttributes into bytes.
+func (o *ObjectAttributes) Serialize() ([]byte, error) {
+	return serializeNodeAttributes(o)
+}
+
+// SerializeTo serializes ObjectAttributes into bytes.
+func (o *ObjectAttributes) SerializeTo(b []byte) error {
+	return encodeNodeAttributes(o, b, func(enc *Encoder) error {
+		if err := o.NodeAttributes.encode(enc); err != nil {
+			return err
+		}
+		enc.WriteUint8(o.EventNotifier)
+		return nil
+	})
+}
+
+// Len returns the actual length of ObjectAttributes in int.
+func (o *ObjectAttributes) Len() int {
+	return o.NodeAttributes.Len() + 1
+}
+
+// Type returns type of ObjectAttributes.
+func (o *ObjectAttributes) Type() int {
+	return id.ObjectAttributes_Encoding_DefaultBinary
+}
+
+// NodeClass returns NodeClassObject.
+func (o *ObjectAttributes) NodeClass() NodeClass {
+	return NodeClassObject
+}
+
+// VariableAttributes is the NodeAttributes of a Variable.
+//
+// ArrayDimensions is not encoded as the null array but as the empty one if it is nil.
+//
+// Specification: Part 4, 7.19.3
+type VariableAttributes struct {
+	*NodeAttributes
+	Value                   *Variant
+	DataType                *NodeID
+	ValueRank               int32
+	ArrayDimensions         []uint32
+	AccessLevel             uint8
+	UserAccessLevel         uint8
+	MinimumSamplingInterval float64
+	Historizing             bool
+}
+
+// NewVariableAttributes creates a new VariableAttributes with the initial value v of dataType.
+// The displayName and description are not specified if they are nil.
+//
+// The accessLevel is also set as the UserAccessLevel.
+func NewVariableAttributes(displayName, description *LocalizedText, v *Variant, dataType *NodeID, valueRank int32, accessLevel uint8) *VariableAttributes {
+	mask := NodeAttributesMaskValue | NodeAttributesMaskDataType | NodeAttributesMaskValueRank |
+		NodeAttributesMaskAccessLevel | NodeAttributesMaskUserAccessLevel
+	return &VariableAttributes{
+		NodeAttributes:  newNodeAttributes(mask, displayName, description),
+		Value:           v,
+		DataType:        dataType,
+		ValueRank:       valueRank,
+		AccessLevel:     accessLevel,
+		UserAccessLevel: accessLevel,
+	}
+}
+
+// DecodeFromBytes decodes given bytes into VariableAttributes.
+func (v *VariableAttributes) DecodeFromBytes(b []byte) error {
+	dec := Decoder{b: b}
+	v.NodeAttributes = &NodeAttributes{}
+	if err := v.NodeAttributes.decodeFrom(&dec); err != nil {
+		return err
+	}
+
+	var err error
+	if v.Value, v.DataType, v.ValueRank, v.ArrayDimensions, err = decodeVariableBase(&dec, v); err != nil {
+		return err
+	}
+	if v.AccessLevel, err = dec.ReadUint8(); err != nil {
+		return errors.NewErrTooShortToDecode(v, "should contain AccessLevel")
+	}
+	if v.UserAccessLevel, err = dec.ReadUint8(); err != nil {
+		return errors.NewErrTooShortToDecode(v, "should contain UserAccessLevel")
+	}
+	interval, err := dec.ReadUint64()
+	if err != nil {
+		return errors.NewErrTooShortToDecode(v, "should contain MinimumSamplingInterval")
+	}
+	v.MinimumSamplingInterval = math.Float64frombits(interval)
+	if v.Historizing, err = dec.ReadBool(); err != nil {
+		return errors.NewErrTooShortToDecode(v, "should contain Historizing")
+	}
+
+	return nil
+}
+
+// Serialize serializes VariableAttributes into bytes.
+func (v *VariableAttributes) Serialize() ([]byte, error) {
+	return serializeNodeAttributes(v)
+}
+
+// SerializeTo serializes VariableAttributes into bytes.
+func (v *VariableAttributes) SerializeTo(b []byte) error {
+	return encodeNodeAttributes(v, b, func(enc *Encoder) error {
+		if err := v.NodeAttributes.encode(enc); err != nil {
+			return err
+		}
+		if err := encodeVariableBase(enc, v.Value, v.DataType, v.ValueRank, v.ArrayDimensions); err != nil {
+			return err
+		}
+		enc.WriteUint8(v.AccessLevel)
+		enc.WriteUint8(v.UserAccessLevel)
+		enc.WriteUint64(math.Float64bits(v.MinimumSamplingInterval))
+		enc.WriteBool(v.Historizing)
+		return nil
+	})
+}
+
+// Len returns the actual length of VariableAttributes in int.
+func (v *VariableAttributes) Len() int {
+	return v.NodeAttributes.Len() + variableBaseLen(v.Value, v.DataType, v.ArrayDimensions) + 11
+}
+
+// Type returns type of VariableAttributes.
+func (v *VariableAttributes) Type() int {
+	return id.VariableAttributes_Encoding_DefaultBinary
+}
+
+// NodeClass returns NodeClassVariable.
+func (v *VariableAttributes) NodeClass() NodeClass {
+	return NodeClassVariable
+}
+
+// MethodAttributes is the NodeAttributes of a Method.
+//
+// Specification: Part 4, 7.19.4
+type MethodAttributes struct {
+	*NodeAttributes
+	Executable     bool
+	UserExecutable bool
+}
+
+// NewMethodAttributes creates a new MethodAttributes.
+// The displayName and description are not specified if they are nil.
+//
+// The executable is also set as the UserExecutable.
+func NewMethodAttributes(displayName, description *LocalizedText, executable bool) *MethodAttributes {
+	mask := NodeAttributesMaskExecutable | NodeAttributesMaskUserExecutable
+	return &MethodAttributes{
+		NodeAttributes: newNodeAttributes(mask, displayName, description),
+		Executable:     executable,
+		UserExecutable: executable,
+	}
+}
+
+// DecodeFromBytes decodes given bytes into MethodAttributes.
+func (m *MethodAttributes) DecodeFromBytes(b []byte) error {
+	dec := Decoder{b: b}
+	m.NodeAttributes = &NodeAttributes{}
+	if err := m.NodeAttributes.decodeFrom(&dec); err != nil {
+		return err
+	}
+
+	var err error
+	if m.Executable, err = dec.ReadBool(); err != nil {
+		return errors.NewErrTooShortToDecode(m, "should contain Executable")
+	}
+	if m.UserExecutable, err = dec.ReadBool(); err != nil {
+		return errors.NewErrTooShortToDecode(m, "should contain UserExecutable")
+	}
+
+	return nil
+}
+
+// Serialize serializes MethodAttributes into bytes.
+func (m *MethodAttributes) Serialize() ([]byte, error) {
+	return serializeNodeAttributes(m)
+}
+
+// SerializeTo serializes MethodAttributes into bytes.
+func (m *MethodAttributes) SerializeTo(b []byte) error {
+	return encodeNodeAttributes(m, b, func(enc *Encoder) error {
+		if err := m.NodeAttributes.encode(enc); err != nil {
+			return err
+		}
+		enc.WriteBool(m.Executable)
+		enc.WriteBool(m.UserExecutable)
+		return nil
+	})
+}
+
+// Len returns the actual length of MethodAttributes in int.
+func (m *MethodAttributes) Len() int {
+	return m.NodeAttributes.Len() + 2
+}
+
+// Type returns type of MethodAttributes.
+func (m *MethodAttributes) Type() int {
+	return id.MethodAttributes_Encoding_DefaultBinary
+}
+
+// NodeClass returns NodeClassMethod.
+func (m *MethodAttributes) NodeClass() NodeClass {
+	return NodeClassMethod
+}
+
+// ObjectTypeAttributes is the NodeAttributes of an ObjectType.
+//
+// Specification: Part 4, 7.19.5
+type ObjectTypeAttributes struct {
+	*NodeAttributes
+	IsAbstract bool
+}
+
+// NewObjectTypeAttributes creates a new ObjectTypeAttributes.
+// The displayName and description are not specified if they are nil.
+func NewObjectTypeAttributes(displayName, description *LocalizedText, isAbstract bool) *ObjectTypeAttributes {
+	return &ObjectTypeAttributes{
+		NodeAttributes: newNodeAttributes(NodeAttributesMaskIsAbstract, displayName, description),
+		IsAbstract:     isAbstract,
+	}
+}
+
+// DecodeFromBytes decodes given bytes into ObjectTypeAttributes.
+func (o *ObjectTypeAttributes) DecodeFromBytes(b []byte) error {
+	dec := Decoder{b: b}
+	o.NodeAttributes = &NodeAttributes{}
+	if err := o.NodeAttributes.decodeFrom(&dec); err != nil {
+		return err
+	}
+
+	var err error
+	if o.IsAbstract, err = dec.ReadBool(); err != nil {
+		return errors.NewErrTooShortToDecode(o, "should contain IsAbstract")
+	}
+
+	return nil
+}
+
+// Serialize serializes ObjectTypeAttributes into bytes.
+func (o *ObjectTypeAttributes) Serialize() ([]byte, error) {
+	return serializeNodeAttributes(o)
+}
+
+// SerializeTo serializes ObjectTypeAttributes into bytes.
+func (o *ObjectTypeAttributes) SerializeTo(b []byte) error {
+	return encodeNodeAttributes(o, b, func(enc *Encoder) error {
+		if err := o.NodeAttributes.encode(enc); err != nil {
+			return err
+		}
+		enc.WriteBool(o.IsAbstract)
+		return nil
+	})
+}
+
+// Len returns the actual length of ObjectTypeAttributes in int.
+func (o *ObjectTypeAttributes) Len() int {
+	return o.NodeAttributes.Len() + 1
+}
+
+// Type returns type of ObjectTypeAttributes.
+func (o *ObjectTypeAttributes) Type() int {
+	return id.ObjectTypeAttributes_Encoding_DefaultBinary
+}
+
+// NodeClass returns NodeClassObjectType.
+func (o *ObjectTypeAttributes) NodeClass() NodeClass {
+	return NodeClassObjectType
+}
+
+// VariableTypeAttributes is the NodeAttributes of a VariableType.
+//
+// ArrayDimensions is not encoded as the null array but as the empty one if it is nil.
+//
+// Specification: Part 4, 7.19.6
+type VariableTypeAttributes struct {
+	*NodeAttributes
+	Value           *Variant
+	DataType        *NodeID
+	ValueRank       int32
+	ArrayDimensions []uint32
+	IsAbstract      bool
+}
+
+// NewVariableTypeAttributes creates a new VariableTypeAttributes with the default value v of dataType.
+// The displayName and description are not specified if they are nil.
+func NewVariableTypeAttributes(displayName, description *LocalizedText, v *Variant, dataType *NodeID, valueRank int32, isAbstract bool) *VariableTypeAttributes {
+	mask := NodeAttributesMaskValue | NodeAttributesMaskDataType | NodeAttributesMaskValueRank | NodeAttributesMaskIsAbstract
+	return &VariableTypeAttributes{
+		NodeAttributes: newNodeAttributes(mask, displayName, description),
+		Value:          v,
+		DataType:       dataType,
+		ValueRank:      valueRank,
+		IsAbstract:     isAbstract,
+	}
+}
+
+// DecodeFromBytes decodes given bytes into VariableTypeAttributes.
+func (v *VariableTypeAttributes) DecodeFromBytes(b []byte) error {
+	dec := Decoder{b: b}
+	v.NodeAttributes = &NodeAttributes{}
+	if err := v.NodeAttributes.decodeFrom(&dec); err != nil {
+		return err
+	}
+
+	var err error
+	if v.Value, v.DataType, v.ValueRank, v.ArrayDimensions, err = decodeVariableBase(&dec, v); err != nil {
+		return err
+	}
+	if v.IsAbstract, err = dec.ReadBool(); err != nil {
+		return errors.NewErrTooShortToDecode(v, "should contain IsAbstract")
+	}
+
+	return nil
+}
+
+// Serialize serializes VariableTypeAttributes into bytes.
+func (v *VariableTypeAttributes) Serialize() ([]byte, error) {
+	return serializeNodeAttributes(v)
+}
+
+// SerializeTo serializes VariableTypeAttributes into bytes.
+func (v *VariableTypeAttributes) SerializeTo(b []byte) error {
+	return encodeNodeAttributes(v, b, func(enc *Encoder) error {
+		if err := v.NodeAttributes.encode(enc); err != nil {
+			return err
+		}
+		if err := encodeVariableBase(enc, v.Value, v.DataType, v.ValueRank, v.ArrayDimensions); err != nil {
+			return err
+		}
+		enc.WriteBool(v.IsAbstract)
+		return nil
+	})
+}
+
+// Len returns the actual length of VariableTypeAttributes in int.
+func (v *VariableTypeAttributes) Len() int {
+	return v.NodeAttributes.Len() + variableBaseLen(v.Value, v.DataType, v.ArrayDimensions) + 1
+}
+
+// Type returns type of VariableTypeAttributes.
+func (v *VariableTypeAttributes) Type() int {
+	return id.VariableTypeAttributes_Encoding_DefaultBinary
+}
+
+// NodeClass returns NodeClassVariableType.
+func (v *VariableTypeAttributes) NodeClass() NodeClass {
+	return NodeClassVariableType
+}
+
+// decodeVariableBase decodes the Value, DataType, ValueRank and ArrayDimensions
+// of VariableAttributes and VariableTypeAttributes.
+func decodeVariableBase(dec *Decoder, v interface{}) (*Variant, *NodeID, int32, []uint32, error) {
+	// the Value is the null Variant if it is not specified.
+	val := &Variant{}
+	if rest := dec.Remaining(); len(rest) > 0 && rest[0] == 0 {
+		dec.off++
+	} else if err := dec.ReadData(val); err != nil {
+		return nil, nil, 0, nil, err
+	}
+	dataType := &NodeID{}
+	if err := dec.ReadData(dataType); err != nil {
+		return nil, nil, 0, nil, err
+	}
+	rank, err := dec.ReadInt32()
+	if err != nil {
+		return nil, nil, 0, nil, errors.NewErrTooShortToDecode(v, "should contain ValueRank")
+	}
+	n, err := dec.ReadInt32()
+	if err != nil {
+		return nil, nil, 0, nil, errors.NewErrTooShortToDecode(v, "should contain ArrayDimensions")
+	}
+	var dims []uint32
+	for i := int32(0); i < n; i++ {
+		d, err := dec.ReadUint32()
+		if err != nil {
+			return nil, nil, 0, nil, errors.NewErrTooShortToDecode(v, "should contain ArrayDimensions of the length")
+		}
+		dims = append(dims, d)
+	}
+
+	return val, dataType, rank, dims, nil
+}
+
+// encodeVariableBase writes the Value, DataType, ValueRank and ArrayDimensions
+// of VariableAttributes and VariableTypeAttributes to enc.
+func encodeVariableBase(enc *Encoder, v *Variant, dataType *NodeID, rank int32, dims []uint32) error {
+	if v == nil {
+		v = &Variant{}
+	}
+	if err := enc.WriteData(v); err != nil {
+		return err
+	}
+	if dataType == nil {
+		dataType = NewTwoByteNodeID(0)
+	}
+	if err := enc.WriteData(dataType); err != nil {
+		return err
+	}
+	enc.WriteInt32(rank)
+	enc.WriteInt32(int32(len(dims)))
+	for _, d := range dims {
+		enc.WriteUint32(d)
+	}
+
+	return nil
+}
+
+// variableBaseLen returns the length of the Value, DataType, ValueRank and ArrayDimensions
+// of VariableAttributes and VariableTypeAttributes.
+func variableBaseLen(v *Variant, dataType *NodeID, dims []uint32) int {
+	// the null Variant, the null NodeID, ValueRank and the length of ArrayDimensions.
+	l := 1 + 2 + 4 + 4 + 4*len(dims)
+	if v != nil {
+		l += v.Len() - 1
+	}
+	if dataType != nil {
+		l += dataType.Len() - 2
+	}
+
+	return l
+}
+
+// ReferenceTypeAttributes is the NodeAttributes of a ReferenceType.
+//
+// Specification: Part 4, 7.19.7
+type ReferenceTypeAttributes struct {
+	*NodeAttributes
+	IsAbstract  bool
+	Symmetric   bool
+	InverseName *LocalizedText
+}
+
+// NewReferenceTypeAttributes creates a new ReferenceTypeAttributes.
+// The displayName, description and inverseName are not specified if they are nil.
+func NewReferenceTypeAttributes(displayName, description *LocalizedText, isAbstract, symmetric bool, inverseName *LocalizedText) *ReferenceTypeAttributes {
+	r := &ReferenceTypeAttributes{
+		NodeAttributes: newNodeAttributes(NodeAttributesMaskIsAbstract|NodeAttributesMaskSymmetric, displayName, description),
+		IsAbstract:     isAbstract,
+		Symmetric:      symmetric,
+		InverseName:    &LocalizedText{},
+	}
+	if inverseName != nil {
+		r.InverseName = inverseName
+		r.SpecifiedAttributes |= NodeAttributesMaskInverseName
+	}
+
+	return r
+}
+
+// DecodeFromBytes decodes given bytes into ReferenceTypeAttributes.
+func (r *ReferenceTypeAttributes) DecodeFromBytes(b []byte) error {
+	dec := Decoder{b: b}
+	r.NodeAttributes = &NodeAttributes{}
+	if err := r.NodeAttributes.decodeFrom(&dec); err != nil {
+		return err
+	}
+
+	var err error
+	if r.IsAbstract, err = dec.ReadBool(); err != nil {
+		return errors.NewErrTooShortToDecode(r, "should contain IsAbstract")
+	}
+	if r.Symmetric, err = dec.ReadBool(); err != nil {
+		return errors.NewErrTooShortToDecode(r, "should contain Symmetric")
+	}
+	r.InverseName = &LocalizedText{}
+	return dec.ReadData(r.InverseName)
+}
+
+// Serialize serializes ReferenceTypeAttributes into bytes.
+func (r *ReferenceTypeAttributes) Serialize() ([]byte, error) {
+	return serializeNodeAttributes(r)
+}
+
+// SerializeTo serializes ReferenceTypeAttributes into bytes.
+func (r *ReferenceTypeAttributes) SerializeTo(b []byte) error {
+	return encodeNodeAttributes(r, b, func(enc *Encoder) error {
+		if err := r.NodeAttributes.encode(enc); err != nil {
+			return err
+		}
+		enc.WriteBool(r.IsAbstract)
+		enc.WriteBool(r.Symmetric)
+		return enc.WriteData(r.inverseName())
+	})
+}
+
+// Len returns the actual length of ReferenceTypeAttributes in int.
+func (r *ReferenceTypeAttributes) Len() int {
+	return r.NodeAttributes.Len() + 2 + r.inverseName().Len()
+}
+
+// inverseName returns the InverseName, or the empty LocalizedText if it is nil.
+func (r *ReferenceTypeAttributes) inverseName() *LocalizedText {
+	if r.InverseName == nil {
+		return &LocalizedText{}
+	}
+	return r.InverseName
+}
+
+// Type returns type of ReferenceTypeAttributes.
+func (r *ReferenceTypeAttributes) Type() int {
+	return id.ReferenceTypeAttributes_Encoding_DefaultBinary
+}
+
+// NodeClass returns NodeClassReferenceType.
+func (r *ReferenceTypeAttributes) NodeClass() NodeClass {
+	return NodeClassReferenceType
+}
+
+// DataTypeAttributes is the NodeAttributes of a DataType.
+//
+// Specification: Part 4, 7.19.8
+type DataTypeAttributes struct {
+	*NodeAttributes
+	IsAbstract bool
+}
+
+// NewDataTypeAttributes creates a new DataTypeAttributes.
+// The displayName and description are not specified if they are nil.
+func NewDataTypeAttributes(displayName, description *LocalizedText, isAbstract bool) *DataTypeAttributes {
+	return &DataTypeAttributes{
+		NodeAttributes: newNodeAttributes(NodeAttributesMaskIsAbstract, displayName, description),
+		IsAbstract:     isAbstract,
+	}
+}
+
+// DecodeFromBytes decodes given bytes into DataTypeAttributes.
+func (d *DataTypeAttributes) DecodeFromBytes(b []byte) error {
+	dec := Decoder{b: b}
+	d.NodeAttributes = &NodeAttributes{}
+	if err := d.NodeAttributes.decodeFrom(&dec); err != nil {
+		return err
+	}
+
+	var err error
+	if d.IsAbstract, err = dec.ReadBool(); err != nil {
+		return errors.NewErrTooShortToDecode(d, "should contain IsAbstract")
+	}
+
+	return nil
+}
+
+// Serialize serializes DataTypeAttributes into bytes.
+func (d *DataTypeAttributes) Serialize() ([]byte, error) {
+	return serializeNodeAttributes(d)
+}
+
+// SerializeTo serializes DataTypeAttributes into bytes.
+func (d *DataTypeAttributes) SerializeTo(b []byte) error {
+	return encodeNodeAttributes(d, b, func(enc *Encoder) error {
+		if err := d.NodeAttributes.encode(enc); err != nil {
+			return err
+		}
+		enc.WriteBool(d.IsAbstract)
+		return nil
+	})
+}
+
+// Len returns the actual length of DataTypeAttributes in int.
+func (d *DataTypeAttributes) Len() int {
+	return d.NodeAttributes.Len() + 1
+}
+
+// Type returns type of DataTypeAttributes.
+func (d *DataTypeAttributes) Type() int {
+	return id.DataTypeAttributes_Encoding_DefaultBinary
+}
+
+// NodeClass returns NodeClassDataType.
+func (d *DataTypeAttributes) NodeClass() NodeClass {
+	return NodeClassDataType
+}
+
+// ViewAttributes is the NodeAttributes of a View.
+//
+// Specification: Part 4, 7.19.9
+type ViewAttributes struct {
+	*NodeAttributes
+	ContainsNoLoops bool
+	EventNotifier   uint8
+}
+
+// NewViewAttributes creates a new ViewAttributes.
+// The displayName and description are not specified if they are nil.
+func NewViewAttributes(displayName, description *LocalizedText, containsNoLoops bool, eventNotifier uint8) *ViewAttributes {
+	mask := NodeAttributesMaskContainsNoLoops | NodeAttributesMaskEventNotifier
+	return &ViewAttributes{
+		NodeAttributes:  newNodeAttributes(mask, displayName, description),
+		ContainsNoLoops: containsNoLoops,
+		EventNotifier:   eventNotifier,
+	}
+}
+
+// DecodeFromBytes decodes given bytes into ViewAttributes.
+func (v *ViewAttributes) DecodeFromBytes(b []byte) error {
+	dec := Decoder{b: b}
+	v.NodeAttributes = &NodeAttributes{}
+	if err := v.NodeAttributes.decodeFrom(&dec); err != nil {
+		return err
+	}
+
+	var err error
+	if v.ContainsNoLoops, err = dec.ReadBool(); err != nil {
+		return errors.NewErrTooShortToDecode(v, "should contain ContainsNoLoops")
+	}
+	if v.EventNotifier, err = dec.ReadUint8(); err != nil {
+		return errors.NewErrTooShortToDecode(v, "should contain EventNotifier")
+	}
+
+	return nil
+}
+
+// Serialize serializes ViewAttributes into bytes.
+func (v *ViewAttributes) Serialize() ([]byte, error) {
+	return serializeNodeAttributes(v)
+}
+
+// SerializeTo serializes ViewAttributes into bytes.
+func (v *ViewAttributes) SerializeTo(b []byte) error {
+	return encodeNodeAttributes(v, b, func(enc *Encoder) error {
+		if err := v.NodeAttributes.encode(enc); err != nil {
+			return err
+		}
+		enc.WriteBool(v.ContainsNoLoops)
+		enc.WriteUint8(v.EventNotifier)
+		return nil
+	})
+}
+
+// Len returns the actual length of ViewAttributes in int.
+func (v *ViewAttributes) Len() int {
+	return v.NodeAttributes.Len() + 2
+}
+
+// Type returns type of ViewAttributes.
+func (v *ViewAttributes) Type() int {
+	return id.ViewAttributes_Encoding_DefaultBinary
+}
+
+// NodeClass returns NodeClassView.
+func (v *ViewAttributes) NodeClass() NodeClass {
+	return NodeClassView
+}
@@ -0,0 +1,167 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package datatypes
+
+import (
+	"testing"
+
+	"github.com/wmnsk/gopcua/utils/codectest"
+)
+
+func TestVariableAttributes(t *testing.T) {
+	cases := []codectest.Case{
+		{
+			Name: "normal",
+			Struct: NewVariableAttributes(
+				NewLocalizedText("", "Temperature"), nil,
+				NewVariant(NewInt32(42)), NewTwoByteNodeID(6), -1, 3,
+			),
+			Bytes: []byte{
+				// SpecifiedAttributes
+				0x51, 0x00, 0x29, 0x00,
+				// DisplayName
+				0x02, 0x0b, 0x00, 0x00, 0x00, 0x54, 0x65, 0x6d,
+				0x70, 0x65, 0x72, 0x61, 0x74, 0x75, 0x72, 0x65,
+				// Description
+				0x00,
+				// WriteMask
+				0x00, 0x00, 0x00, 0x00,
+				// UserWriteMask
+				0x00, 0x00, 0x00, 0x00,
+				// Value
+				0x06, 0x2a, 0x00, 0x00, 0x00,
+				// DataType
+				0x00, 0x06,
+				// ValueRank
+				0xff, 0xff, 0xff, 0xff,
+				// ArrayDimensions
+				0x00, 0x00, 0x00, 0x00,
+				// AccessLevel
+				0x03,
+				// UserAccessLevel
+				0x03,
+				// MinimumSamplingInterval
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				// Historizing
+				0x00,
+			},
+		},
+		{
+			Name: "array",
+			Struct: &VariableAttributes{
+				NodeAttributes: &NodeAttributes{
+					SpecifiedAttributes: NodeAttributesMaskArrayDimensions | NodeAttributesMaskHistorizing,
+					DisplayName:         &LocalizedText{},
+					Description:         &LocalizedText{},
+				},
+				Value:           &Variant{},
+				DataType:        NewTwoByteNodeID(0),
+				ValueRank:       1,
+				ArrayDimensions: []uint32{2},
+				Historizing:     true,
+			},
+			Bytes: []byte{
+				// SpecifiedAttributes
+				0x02, 0x02, 0x00, 0x00,
+				// DisplayName
+				0x00,
+				// Description
+				0x00,
+				// WriteMask
+				0x00, 0x00, 0x00, 0x00,
+				// UserWriteMask
+				0x00, 0x00, 0x00, 0x00,
+				// Value
+				0x00,
+				// DataType
+				0x00, 0x00,
+				// ValueRank
+				0x01, 0x00, 0x00, 0x00,
+				// ArrayDimensions
+				0x01, 0x00, 0x00, 0x00, 0x02, 0x00, 0x00, 0x00,
+				// AccessLevel
+				0x00,
+				// UserAccessLevel
+				0x00,
+				// MinimumSamplingInterval
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				// Historizing
+				0x01,
+			},
+		},
+	}
+	codectest.Run(t, cases, func(b []byte) (codectest.S, error) {
+		v := &VariableAttributes{}
+		if err := v.DecodeFromBytes(b); err != nil {
+			return nil, err
+		}
+		return v, nil
+	})
+}
+
+func TestObjectAttributes(t *testing.T) {
+	cases := []codectest.Case{
+		{
+			Name:   "normal",
+			Struct: NewObjectAttributes(NewLocalizedText("", "Device"), NewLocalizedText("en", "a device"), 1),
+			Bytes: []byte{
+				// SpecifiedAttributes
+				0xe0, 0x00, 0x00, 0x00,
+				// DisplayName
+				0x02, 0x06, 0x00, 0x00, 0x00, 0x44, 0x65, 0x76, 0x69, 0x63, 0x65,
+				// Description
+				0x03, 0x02, 0x00, 0x00, 0x00, 0x65, 0x6e,
+				0x08, 0x00, 0x00, 0x00, 0x61, 0x20, 0x64, 0x65, 0x76, 0x69, 0x63, 0x65,
+				// WriteMask
+				0x00, 0x00, 0x00, 0x00,
+				// UserWriteMask
+				0x00, 0x00, 0x00, 0x00,
+				// EventNotifier
+				0x01,
+			},
+		},
+	}
+	codectest.Run(t, cases, func(b []byte) (codectest.S, error) {
+		v := &ObjectAttributes{}
+		if err := v.DecodeFromBytes(b); err != nil {
+			return nil, err
+		}
+		return v, nil
+	})
+}
+
+func TestNodeAttributesNodeClass(t *testing.T) {
+	cases := []struct {
+		attrs NodeClassAttributes
+		class NodeClass
+	}{
+		{NewObjectAttributes(nil, nil, 0), NodeClassObject},
+		{NewVariableAttributes(nil, nil, nil, nil, -1, 0), NodeClassVariable},
+		{NewMethodAttributes(nil, nil, true), NodeClassMethod},
+		{NewObjectTypeAttributes(nil, nil, false), NodeClassObjectType},
+		{NewVariableTypeAttributes(nil, nil, nil, nil, -1, false), NodeClassVariableType},
+		{NewReferenceTypeAttributes(nil, nil, false, true, nil), NodeClassReferenceType},
+		{NewDataTypeAttributes(nil, nil, false), NodeClassDataType},
+		{NewViewAttributes(nil, nil, true, 0), NodeClassView},
+	}
+	for _, c := range cases {
+		if got, want := c.attrs.NodeClass(), c.class; got != want {
+			t.Errorf("%T: got NodeClass %d want %d", c.attrs, got, want)
+		}
+
+		// the ExtensionObject is decoded into the NodeAttributes of the same type.
+		b, err := NewExtensionObject(ExtensionObjectBinary, c.attrs).Serialize()
+		if err != nil {
+			t.Fatalf("%T: %s", c.attrs, err)
+		}
+		e, err := DecodeExtensionObject(b)
+		if err != nil {
+			t.Fatalf("%T: %s", c.attrs, err)
+		}
+		if v, ok := e.Value.(NodeClassAttributes); !ok || v.NodeClass() != c.class {
+			t.Errorf("%T: decoded into %T", c.attrs, e.Value)
+		}
+	}
+}
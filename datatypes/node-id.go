@@ -5,13 +5,18 @@
 package datatypes
 
 import (
+	"bytes"
 	"encoding/base64"
 	"encoding/binary"
+	"encoding/json"
 	"fmt"
 	"io"
 	"math"
 	"strconv"
 	"strings"
+
+	"github.com/wmnsk/gopcua/errors"
+	"github.com/wmnsk/gopcua/id"
 )
 
 // NodeID type definitions.
@@ -92,7 +97,9 @@ func NewOpaqueNodeID(ns uint16, id []byte) *NodeID {
 // NewNodeID returns a node id from a string definition of the format
 // 'ns=<namespace>;{s,i,b,g}=<identifier>'.
 //
-// For string node ids the 's=' prefix can be omitted.
+// The 'ns=' prefix can be omitted in which case the namespace is zero.
+// For string node ids the 's=' prefix can be omitted, so the string which does
+// not start with 'ns=<digits>;' is in the namespace zero, e.g. 'ns=foo;bar'.
 //
 // For numeric ids the smallest possible type which can store the namespace
 // and id value is returned.
@@ -104,15 +111,17 @@ func NewNodeID(s string) (*NodeID, error) {
 		return NewTwoByteNodeID(0), nil
 	}
 
-	p := strings.SplitN(s, ";", 2)
-	if len(p) < 2 {
-		return nil, fmt.Errorf("invalid node id: %s", s)
+	nsval, idval := "", s
+	if p := strings.SplitN(s, ";", 2); len(p) == 2 && (isNamespaceIndex(p[0]) || strings.HasPrefix(p[0], "nsu=")) {
+		nsval, idval = p[0], p[1]
 	}
-	nsval, idval := p[0], p[1]
 
 	// parse namespace
 	var ns uint16
 	switch {
+	case nsval == "":
+		// namespace zero
+
 	case strings.HasPrefix(nsval, "nsu="):
 		return nil, fmt.Errorf("namespace urls are not supported: %s", s)
 
@@ -138,14 +147,14 @@ func NewNodeID(s string) (*NodeID, error) {
 			return nil, fmt.Errorf("invalid numeric id: %s", s)
 		}
 		switch {
-		case ns == 0 && id < 256:
+		case id < 0 || id > math.MaxUint32:
+			return nil, fmt.Errorf("numeric id out of range (0..2^32-1): %s", s)
+		case ns == 0 && id <= math.MaxUint8:
 			return NewTwoByteNodeID(byte(id)), nil
-		case ns < 256 && id >= 0 && id < math.MaxUint16:
+		case ns <= math.MaxUint8 && id <= math.MaxUint16:
 			return NewFourByteNodeID(byte(ns), uint16(id)), nil
-		case id >= 0 && id < math.MaxUint32:
-			return NewNumericNodeID(ns, uint32(id)), nil
 		default:
-			return nil, fmt.Errorf("numeric id out of range (0..2^32-1): %s", s)
+			return NewNumericNodeID(ns, uint32(id)), nil
 		}
 
 	case strings.HasPrefix(idval, "s="):
@@ -170,15 +179,29 @@ func NewNodeID(s string) (*NodeID, error) {
 	}
 }
 
+// isNamespaceIndex reports whether s is the namespace index of the format 'ns=<digits>'.
+func isNamespaceIndex(s string) bool {
+	if !strings.HasPrefix(s, "ns=") || len(s) == len("ns=") {
+		return false
+	}
+	for _, c := range s[len("ns="):] {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
 // EncodingMask returns the encoding mask field including the
 // type information and additional flags.
 func (n *NodeID) EncodingMask() uint8 {
 	return n.mask
 }
 
-// Type returns the node id type in EncodingMask.
+// Type returns the node id type in EncodingMask. Bits 0-5
+// select the type, bits 6 and 7 are the ExpandedNodeID flags.
 func (n *NodeID) Type() uint8 {
-	return n.mask & 0xf
+	return n.mask & 0x3f
 }
 
 // URIFlag returns whether the URI flag is set in EncodingMask.
@@ -186,21 +209,43 @@ func (n *NodeID) URIFlag() bool {
 	return n.mask&0x80 == 0x80
 }
 
-// SetURIFlag sets NamespaceURI flag in EncodingMask.
+// SetURIFlag sets NamespaceURI flag in EncodingMask. It does nothing if n is nil.
 func (n *NodeID) SetURIFlag() {
+	if n == nil {
+		return
+	}
 	n.mask |= 0x80
 }
 
+// ClearURIFlag clears NamespaceURI flag in EncodingMask. It does nothing if n is nil.
+func (n *NodeID) ClearURIFlag() {
+	if n == nil {
+		return
+	}
+	n.mask &^= 0x80
+}
+
 // IndexFlag returns whether the Index flag is set in EncodingMask.
 func (n *NodeID) IndexFlag() bool {
 	return n.mask&0x40 == 0x40
 }
 
-// SetIndexFlag sets NamespaceURI flag in EncodingMask.
+// SetIndexFlag sets ServerIndex flag in EncodingMask. It does nothing if n is nil.
 func (n *NodeID) SetIndexFlag() {
+	if n == nil {
+		return
+	}
 	n.mask |= 0x40
 }
 
+// ClearIndexFlag clears ServerIndex flag in EncodingMask. It does nothing if n is nil.
+func (n *NodeID) ClearIndexFlag() {
+	if n == nil {
+		return
+	}
+	n.mask &^= 0x40
+}
+
 // Serialize serializes NodeID to bytes.
 func (n *NodeID) Serialize() ([]byte, error) {
 	b := make([]byte, n.Len())
@@ -235,18 +280,21 @@ func (n *NodeID) SerializeTo(b []byte) error {
 		binary.LittleEndian.PutUint16(b[1:3], n.ns)
 		return n.gid.SerializeTo(b[3:])
 
-	case TypeString, TypeOpaque:
+	case TypeString:
 		b[0] = n.mask
 		binary.LittleEndian.PutUint16(b[1:3], n.ns)
 		binary.LittleEndian.PutUint32(b[3:7], uint32(len(n.bid)))
 		copy(b[7:7+len(n.bid)], n.bid)
 		return nil
 
+	case TypeOpaque:
+		b[0] = n.mask
+		binary.LittleEndian.PutUint16(b[1:3], n.ns)
+		return NewByteString(n.bid).SerializeTo(b[3:])
+
 	default:
 		return fmt.Errorf("invalid node id type: %d", n.Type())
 	}
-
-	return nil
 }
 
 // DecodeFromBytes decodes a NodeID from bytes.
@@ -288,21 +336,40 @@ func (n *NodeID) DecodeFromBytes(b []byte) error {
 		n.gid = &GUID{}
 		return n.gid.DecodeFromBytes(b[3:19])
 
-	case TypeString, TypeOpaque:
+	case TypeString:
 		if len(b) < 7 {
 			return io.ErrUnexpectedEOF
 		}
 		n.ns = binary.LittleEndian.Uint16(b[1:3])
+		// the length is checked against the bytes remaining before allocating for it,
+		// as 7+int(l) may overflow where int is 32 bits.
 		l := binary.LittleEndian.Uint32(b[3:7])
-		if len(b) < int(l) {
+		if uint64(l) > uint64(len(b)-7) {
 			return io.ErrUnexpectedEOF
 		}
 		n.bid = make([]byte, l)
 		copy(n.bid, b[7:7+l])
 		return nil
 
+	case TypeOpaque:
+		if len(b) < 7 {
+			return io.ErrUnexpectedEOF
+		}
+		n.ns = binary.LittleEndian.Uint16(b[1:3])
+		// the identifier is a ByteString, which is nil if it is null.
+		s, err := DecodeByteString(b[3:])
+		if err != nil {
+			return err
+		}
+		n.bid = nil
+		if !s.IsNull() {
+			n.bid = make([]byte, len(s.Value))
+			copy(n.bid, s.Value)
+		}
+		return nil
+
 	default:
-		panic(fmt.Sprintf("invalid node id type: %d", n.Type()))
+		return fmt.Errorf("invalid node id type: %d", n.Type())
 	}
 }
 
@@ -329,6 +396,11 @@ func (n *NodeID) Len() int {
 	}
 }
 
+// DataType returns type of Data.
+func (n *NodeID) DataType() uint16 {
+	return id.NodeId
+}
+
 // Namespace returns the namespace id. For two byte node ids
 // this will always be zero.
 func (n *NodeID) Namespace() int {
@@ -412,7 +484,7 @@ func (n *NodeID) StringID() string {
 	case TypeString:
 		return string(n.bid)
 	case TypeOpaque:
-		return base64.StdEncoding.EncodeToString(n.bid)
+		return NewByteString(n.bid).Base64()
 	default:
 		return ""
 	}
@@ -431,11 +503,11 @@ func (n *NodeID) SetStringID(v string) error {
 		return nil
 
 	case TypeOpaque:
-		b, err := base64.StdEncoding.DecodeString(v)
+		b, err := ParseByteStringBase64(v)
 		if err != nil {
 			return err
 		}
-		n.bid = b
+		n.bid = b.Value
 		return nil
 
 	default:
@@ -476,15 +548,194 @@ func (n *NodeID) String() string {
 
 	case TypeOpaque:
 		if n.ns == 0 {
-			return fmt.Sprintf("o=%s", n.StringID())
+			return fmt.Sprintf("b=%s", n.StringID())
 		}
-		return fmt.Sprintf("ns=%d;o=%s", n.ns, n.StringID())
+		return fmt.Sprintf("ns=%d;b=%s", n.ns, n.StringID())
 
 	default:
 		panic(fmt.Sprintf("invalid node id type: %d", n.Type()))
 	}
 }
 
+// clone returns a deep copy of the node id.
+func (n *NodeID) clone() *NodeID {
+	c := *n
+	if n.bid != nil {
+		c.bid = append([]byte{}, n.bid...)
+	}
+	if n.gid != nil {
+		g := *n.gid
+		c.gid = &g
+	}
+	return &c
+}
+
+// Equal returns true if both node ids identify the same node. Numeric
+// node ids are compared by value regardless of their encoding and the
+// ExpandedNodeID flags in the EncodingMask are ignored.
+func (n *NodeID) Equal(m *NodeID) bool {
+	if n == nil || m == nil {
+		return n == m
+	}
+	return n.ns == m.ns && n.equalIdentifier(m)
+}
+
+// equalIdentifier returns true if both node ids have the same
+// identifier type and value. The namespace is not compared.
+func (n *NodeID) equalIdentifier(m *NodeID) bool {
+	if n.isNumeric() && m.isNumeric() {
+		return n.nid == m.nid
+	}
+	if n.Type() != m.Type() {
+		return false
+	}
+
+	switch n.Type() {
+	case TypeGUID:
+		if n.gid == nil || m.gid == nil {
+			return n.gid == m.gid
+		}
+		return *n.gid == *m.gid
+	case TypeString, TypeOpaque:
+		return bytes.Equal(n.bid, m.bid)
+	default:
+		return false
+	}
+}
+
+// isNumeric returns true if the node id is a two byte, four byte
+// or numeric node id.
+func (n *NodeID) isNumeric() bool {
+	switch n.Type() {
+	case TypeTwoByte, TypeFourByte, TypeNumeric:
+		return true
+	default:
+		return false
+	}
+}
+
+// JSON IdType values of the NodeID.
+//
+// Specification: Part 6, 5.4.2.10
+const (
+	jsonIDTypeNumeric = iota
+	jsonIDTypeString
+	jsonIDTypeGUID
+	jsonIDTypeOpaque
+)
+
+// nodeIDJSON is the JSON representation of a NodeID. The Namespace is
+// either the namespace index or, for an ExpandedNodeID, the NamespaceURI.
+//
+// Specification: Part 6, 5.4.2.10
+type nodeIDJSON struct {
+	IDType    uint8           `json:"IdType,omitempty"`
+	ID        json.RawMessage `json:"Id"`
+	Namespace json.RawMessage `json:"Namespace,omitempty"`
+}
+
+// MarshalJSON returns the JSON encoding of the NodeID.
+func (n *NodeID) MarshalJSON() ([]byte, error) {
+	v, err := n.toJSON()
+	if err != nil {
+		return nil, err
+	}
+	if n.ns != 0 {
+		v.Namespace = json.RawMessage(strconv.Itoa(int(n.ns)))
+	}
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON decodes the JSON encoding of a NodeID.
+func (n *NodeID) UnmarshalJSON(b []byte) error {
+	var v nodeIDJSON
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	var ns uint16
+	if len(v.Namespace) > 0 {
+		if err := json.Unmarshal(v.Namespace, &ns); err != nil {
+			return fmt.Errorf("invalid namespace: %s", v.Namespace)
+		}
+	}
+	return n.fromJSON(v, ns)
+}
+
+// toJSON returns the JSON representation of the identifier
+// without the namespace.
+func (n *NodeID) toJSON() (*nodeIDJSON, error) {
+	var (
+		v   = &nodeIDJSON{}
+		id  interface{}
+		err error
+	)
+	switch n.Type() {
+	case TypeTwoByte, TypeFourByte, TypeNumeric:
+		v.IDType, id = jsonIDTypeNumeric, n.nid
+	case TypeString:
+		v.IDType, id = jsonIDTypeString, string(n.bid)
+	case TypeGUID:
+		v.IDType, id = jsonIDTypeGUID, n.StringID()
+	case TypeOpaque:
+		v.IDType, id = jsonIDTypeOpaque, n.bid
+	default:
+		return nil, fmt.Errorf("invalid node id type: %d", n.Type())
+	}
+	if v.ID, err = json.Marshal(id); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// fromJSON sets the node id from the JSON representation and the
+// given namespace. Numeric ids use the smallest possible type.
+func (n *NodeID) fromJSON(v nodeIDJSON, ns uint16) error {
+	switch v.IDType {
+	case jsonIDTypeNumeric:
+		var id uint32
+		if err := json.Unmarshal(v.ID, &id); err != nil {
+			return fmt.Errorf("invalid numeric id: %s", v.ID)
+		}
+		switch {
+		case ns == 0 && id <= math.MaxUint8:
+			*n = *NewTwoByteNodeID(uint8(id))
+		case ns <= math.MaxUint8 && id <= math.MaxUint16:
+			*n = *NewFourByteNodeID(uint8(ns), uint16(id))
+		default:
+			*n = *NewNumericNodeID(ns, id)
+		}
+
+	case jsonIDTypeString:
+		var id string
+		if err := json.Unmarshal(v.ID, &id); err != nil {
+			return fmt.Errorf("invalid string id: %s", v.ID)
+		}
+		*n = *NewStringNodeID(ns, id)
+
+	case jsonIDTypeGUID:
+		var id string
+		if err := json.Unmarshal(v.ID, &id); err != nil {
+			return fmt.Errorf("invalid guid id: %s", v.ID)
+		}
+		g := NewGUIDNodeID(ns, id)
+		if g.gid == nil {
+			return fmt.Errorf("invalid guid id: %s", v.ID)
+		}
+		*n = *g
+
+	case jsonIDTypeOpaque:
+		var id []byte
+		if err := json.Unmarshal(v.ID, &id); err != nil {
+			return fmt.Errorf("invalid opaque id: %s", v.ID)
+		}
+		*n = *NewOpaqueNodeID(ns, id)
+
+	default:
+		return fmt.Errorf("invalid id type: %d", v.IDType)
+	}
+	return nil
+}
+
 // DecodeNodeID decodes a node id from bytes.
 func DecodeNodeID(b []byte) (*NodeID, error) {
 	n := &NodeID{}
@@ -493,3 +744,93 @@ func DecodeNodeID(b []byte) (*NodeID, error) {
 	}
 	return n, nil
 }
+
+// NodeIDArray represents an array of NodeIDs.
+// It does not correspond to a certain type from the specification
+// but makes encoding and decoding easier.
+type NodeIDArray struct {
+	ArraySize int32
+	NodeIDs   []*NodeID
+}
+
+// NewNodeIDArray creates a new NodeIDArray from multiple NodeIDs.
+func NewNodeIDArray(ids []*NodeID) *NodeIDArray {
+	if ids == nil {
+		return &NodeIDArray{
+			ArraySize: 0,
+		}
+	}
+
+	return &NodeIDArray{
+		ArraySize: int32(len(ids)),
+		NodeIDs:   ids,
+	}
+}
+
+// DecodeNodeIDArray decodes given bytes into NodeIDArray.
+func DecodeNodeIDArray(b []byte) (*NodeIDArray, error) {
+	a := &NodeIDArray{}
+	if err := a.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// DecodeFromBytes decodes given bytes into NodeIDArray.
+func (a *NodeIDArray) DecodeFromBytes(b []byte) error {
+	if len(b) < 4 {
+		return errors.NewErrTooShortToDecode(a, "should be longer than 4 bytes")
+	}
+	a.ArraySize = int32(binary.LittleEndian.Uint32(b[:4]))
+	if a.ArraySize <= 0 {
+		return nil
+	}
+
+	offset := 4
+	for i := 1; i <= int(a.ArraySize); i++ {
+		n, err := DecodeNodeID(b[offset:])
+		if err != nil {
+			return err
+		}
+		a.NodeIDs = append(a.NodeIDs, n)
+		offset += n.Len()
+	}
+
+	return nil
+}
+
+// Serialize serializes NodeIDArray into bytes.
+func (a *NodeIDArray) Serialize() ([]byte, error) {
+	b := make([]byte, a.Len())
+	if err := a.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes NodeIDArray into bytes.
+func (a *NodeIDArray) SerializeTo(b []byte) error {
+	offset := 4
+	binary.LittleEndian.PutUint32(b[:4], uint32(a.ArraySize))
+
+	for _, n := range a.NodeIDs {
+		if err := n.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += n.Len()
+	}
+
+	return nil
+}
+
+// Len returns the actual length in int.
+func (a *NodeIDArray) Len() int {
+	l := 4
+	for _, n := range a.NodeIDs {
+		l += n.Len()
+	}
+
+	return l
+}
@@ -6,6 +6,7 @@ package datatypes
 
 import (
 	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"reflect"
 	"testing"
@@ -73,7 +74,7 @@ func TestNodeID(t *testing.T) {
 				0x34, 0x12,
 				// id
 				0xbb, 0xbb, 0xaa, 0xaa, 0xdd, 0xcc, 0xff, 0xee,
-				0xab, 0x89, 0x67, 0x45, 0x23, 0x01, 0x01, 0x01,
+				0x01, 0x01, 0x01, 0x23, 0x45, 0x67, 0x89, 0xab,
 			},
 		},
 		{
@@ -90,12 +91,50 @@ func TestNodeID(t *testing.T) {
 				0xde, 0xad, 0xbe, 0xef,
 			},
 		},
+		{
+			Name:   "Opaque.null",
+			Struct: NewOpaqueNodeID(1, nil),
+			Bytes: []byte{
+				// mask
+				0x05,
+				// namespace
+				0x01, 0x00,
+				// length
+				0xff, 0xff, 0xff, 0xff,
+			},
+		},
+		{
+			Name:   "Opaque.empty",
+			Struct: NewOpaqueNodeID(1, []byte{}),
+			Bytes: []byte{
+				// mask
+				0x05,
+				// namespace
+				0x01, 0x00,
+				// length
+				0x00, 0x00, 0x00, 0x00,
+			},
+		},
 	}
 	codectest.Run(t, cases, func(b []byte) (codectest.S, error) {
 		return DecodeNodeID(b)
 	})
 }
 
+func TestDecodeNodeIDInvalidType(t *testing.T) {
+	for _, b := range [][]byte{
+		{0x06, 0x00},
+		{0x0f, 0x00, 0x00, 0x00},
+		{0x3f, 0x00, 0x00, 0x00},
+		{0xcf, 0x00, 0x00, 0x00},
+		{0x10, 0x00},
+	} {
+		if _, err := DecodeNodeID(b); err == nil {
+			t.Errorf("%x: got nil want error", b)
+		}
+	}
+}
+
 func TestNewNodeID(t *testing.T) {
 	cases := []struct {
 		s   string
@@ -113,12 +152,22 @@ func TestNewNodeID(t *testing.T) {
 		{s: "ns=1;b=YWJj", n: NewOpaqueNodeID(1, []byte{'a', 'b', 'c'})},
 		{s: "ns=1;s=a", n: NewStringNodeID(1, "a")},
 		{s: "ns=1;a", n: NewStringNodeID(1, "a")},
+		{s: "i=1", n: NewTwoByteNodeID(1)},
+		{s: "i=65535", n: NewFourByteNodeID(0, 65535)},
+		{s: "i=4294967295", n: NewNumericNodeID(0, 4294967295)},
+		{s: "s=a;b", n: NewStringNodeID(0, "a;b")},
+		{s: "a", n: NewStringNodeID(0, "a")},
+		// the string ids which look like the namespace.
+		{s: "ns", n: NewStringNodeID(0, "ns")},
+		{s: "nsfoo;bar", n: NewStringNodeID(0, "nsfoo;bar")},
+		{s: "ns=1", n: NewStringNodeID(0, "ns=1")},
+		{s: "ns=abc;i=1", n: NewStringNodeID(0, "ns=abc;i=1")},
+		{s: "ns=;i=1", n: NewStringNodeID(0, "ns=;i=1")},
 
 		// error flows
-		{s: "i=1", err: errors.New("invalid node id: i=1")},
+		{s: "i=-1", err: errors.New("numeric id out of range (0..2^32-1): i=-1")},
 		{s: "nsu=abc;i=1", err: errors.New("namespace urls are not supported: nsu=abc;i=1")},
 		{s: "ns=65536;i=1", err: errors.New("namespace id out of range (0..65535): ns=65536;i=1")},
-		{s: "ns=abc;i=1", err: errors.New("invalid namespace id: ns=abc;i=1")},
 		{s: "ns=1;i=abc", err: errors.New("invalid numeric id: ns=1;i=abc")},
 		{s: "ns=1;i=4294967296", err: errors.New("numeric id out of range (0..2^32-1): ns=1;i=4294967296")},
 		{s: "ns=1;g=x", err: errors.New("invalid guid node id: ns=1;g=x")},
@@ -138,6 +187,39 @@ func TestNewNodeID(t *testing.T) {
 	}
 }
 
+func TestNodeIDStringRoundTrip(t *testing.T) {
+	cases := []struct {
+		name string
+		n    *NodeID
+		s    string
+	}{
+		{"two byte", NewTwoByteNodeID(1), "i=1"},
+		{"four byte", NewFourByteNodeID(1, 2), "ns=1;i=2"},
+		{"four byte without namespace", NewFourByteNodeID(0, 300), "i=300"},
+		{"numeric", NewNumericNodeID(256, 70000), "ns=256;i=70000"},
+		{"numeric without namespace", NewNumericNodeID(0, 70000), "i=70000"},
+		{"string", NewStringNodeID(1, "foo.bar"), "ns=1;s=foo.bar"},
+		{"string without namespace", NewStringNodeID(0, "foo"), "s=foo"},
+		{"guid", NewGUIDNodeID(1, "5eac051c-c313-43d7-b790-24aa2c3cfd37"), "ns=1;g=5EAC051C-C313-43D7-B790-24AA2C3CFD37"},
+		{"opaque", NewOpaqueNodeID(1, []byte{'a', 'b', 'c'}), "ns=1;b=YWJj"},
+		{"opaque without namespace", NewOpaqueNodeID(0, []byte{0xde, 0xad, 0xbe, 0xef}), "b=3q2+7w=="},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got, want := c.n.String(), c.s; got != want {
+				t.Fatalf("got %s want %s", got, want)
+			}
+			n, err := NewNodeID(c.s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := n, c.n; !reflect.DeepEqual(got, want) {
+				t.Fatalf("\ngot  %#v\nwant %#v", got, want)
+			}
+		})
+	}
+}
+
 func TestSetIntID(t *testing.T) {
 	tests := []struct {
 		name string
@@ -430,3 +512,64 @@ func TestSetNamespace(t *testing.T) {
 		})
 	}
 }
+
+func TestNodeIDJSON(t *testing.T) {
+	cases := []struct {
+		n    *NodeID
+		json string
+	}{
+		{n: NewTwoByteNodeID(1), json: `{"Id":1}`},
+		{n: NewFourByteNodeID(1, 2), json: `{"Id":2,"Namespace":1}`},
+		{n: NewNumericNodeID(256, 65536), json: `{"Id":65536,"Namespace":256}`},
+		{n: NewStringNodeID(1, "a"), json: `{"IdType":1,"Id":"a","Namespace":1}`},
+		{n: NewGUIDNodeID(1, "5EAC051C-C313-43D7-B790-24AA2C3CFD37"), json: `{"IdType":2,"Id":"5EAC051C-C313-43D7-B790-24AA2C3CFD37","Namespace":1}`},
+		{n: NewOpaqueNodeID(1, []byte{'a', 'b', 'c'}), json: `{"IdType":3,"Id":"YWJj","Namespace":1}`},
+	}
+	for _, c := range cases {
+		t.Run(c.json, func(t *testing.T) {
+			b, err := json.Marshal(c.n)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := string(b), c.json; got != want {
+				t.Fatalf("got %s want %s", got, want)
+			}
+
+			n := &NodeID{}
+			if err := json.Unmarshal(b, n); err != nil {
+				t.Fatal(err)
+			}
+			if got, want := n, c.n; !reflect.DeepEqual(got, want) {
+				t.Fatalf("\ngot  %#v\nwant %#v", got, want)
+			}
+		})
+	}
+}
+
+func TestNodeIDArray(t *testing.T) {
+	cases := []codectest.Case{
+		{
+			Name: "normal",
+			Struct: NewNodeIDArray([]*NodeID{
+				NewFourByteNodeID(2, 1000),
+				NewOpaqueNodeID(1, []byte{0xde, 0xad}),
+			}),
+			Bytes: []byte{
+				// ArraySize
+				0x02, 0x00, 0x00, 0x00,
+				// FourByte
+				0x01, 0x02, 0xe8, 0x03,
+				// Opaque
+				0x05, 0x01, 0x00, 0x02, 0x00, 0x00, 0x00, 0xde, 0xad,
+			},
+		},
+		{
+			Name:   "empty",
+			Struct: NewNodeIDArray(nil),
+			Bytes:  []byte{0x00, 0x00, 0x00, 0x00},
+		},
+	}
+	codectest.Run(t, cases, func(b []byte) (codectest.S, error) {
+		return DecodeNodeIDArray(b)
+	})
+}
@@ -0,0 +1,98 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package datatypes
+
+import (
+	"encoding/binary"
+	"time"
+
+	"github.com/wmnsk/gopcua/errors"
+)
+
+// NotificationMessage is the message returned in PublishResponse which contains
+// the Notifications of a Subscription.
+//
+// A NotificationMessage with no NotificationData is a keep-alive message, and its
+// SequenceNumber is the one which will be used for the next NotificationMessage.
+//
+// Specification: Part 4, 7.21
+type NotificationMessage struct {
+	SequenceNumber   uint32
+	PublishTime      time.Time
+	NotificationData *ExtensionObjectArray
+}
+
+// NewNotificationMessage creates a new NotificationMessage.
+func NewNotificationMessage(seq uint32, publishTime time.Time, data ...ExtensionObjectValue) *NotificationMessage {
+	var objs []*ExtensionObject
+	for _, d := range data {
+		objs = append(objs, NewExtensionObject(0x01, d))
+	}
+
+	return &NotificationMessage{
+		SequenceNumber:   seq,
+		PublishTime:      publishTime,
+		NotificationData: NewExtensionObjectArray(objs),
+	}
+}
+
+// DecodeNotificationMessage decodes given bytes into NotificationMessage.
+func DecodeNotificationMessage(b []byte) (*NotificationMessage, error) {
+	n := &NotificationMessage{}
+	if err := n.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return n, nil
+}
+
+// DecodeFromBytes decodes given bytes into NotificationMessage.
+func (n *NotificationMessage) DecodeFromBytes(b []byte) error {
+	if len(b) < 12 {
+		return errors.NewErrTooShortToDecode(n, "should be longer than 12 bytes")
+	}
+	n.SequenceNumber = binary.LittleEndian.Uint32(b[:4])
+	n.PublishTime = decodeDateTime(b[4:12])
+
+	n.NotificationData = &ExtensionObjectArray{}
+	return n.NotificationData.DecodeFromBytes(b[12:])
+}
+
+// Serialize serializes NotificationMessage into bytes.
+func (n *NotificationMessage) Serialize() ([]byte, error) {
+	b := make([]byte, n.Len())
+	if err := n.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes NotificationMessage into bytes.
+func (n *NotificationMessage) SerializeTo(b []byte) error {
+	binary.LittleEndian.PutUint32(b[:4], n.SequenceNumber)
+	encodeDateTime(b[4:12], n.PublishTime)
+
+	if n.NotificationData != nil {
+		return n.NotificationData.SerializeTo(b[12:])
+	}
+
+	return nil
+}
+
+// Len returns the actual length of NotificationMessage in int.
+func (n *NotificationMessage) Len() int {
+	l := 12
+	if n.NotificationData != nil {
+		l += n.NotificationData.Len()
+	}
+
+	return l
+}
+
+// IsKeepAlive reports whether the NotificationMessage is a keep-alive message.
+func (n *NotificationMessage) IsKeepAlive() bool {
+	return n.NotificationData == nil || len(n.NotificationData.ExtensionObjects) == 0
+}
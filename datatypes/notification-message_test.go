@@ -0,0 +1,98 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package datatypes
+
+import (
+	"testing"
+	"time"
+
+	"github.com/wmnsk/gopcua/utils/codectest"
+)
+
+func TestNotificationMessage(t *testing.T) {
+	cases := []codectest.Case{
+		{
+			Name: "data-change",
+			Struct: NewNotificationMessage(
+				1, time.Date(2018, time.September, 17, 14, 28, 29, 112000000, time.UTC),
+				NewDataChangeNotification(
+					NewMonitoredItemNotification(1, &DataValue{EncodingMask: 0x01, Value: NewVariant(NewFloat(2.50025))}),
+				),
+			),
+			Bytes: []byte{
+				// SequenceNumber
+				0x01, 0x00, 0x00, 0x00,
+				// PublishTime
+				0x80, 0x3b, 0xe8, 0xb3, 0x92, 0x4e, 0xd4, 0x01,
+				// NotificationData: ArraySize
+				0x01, 0x00, 0x00, 0x00,
+				// TypeID
+				0x01, 0x00, 0x2b, 0x03,
+				// EncodingMask
+				0x01,
+				// Length
+				0x12, 0x00, 0x00, 0x00,
+				// DataChangeNotification
+				0x01, 0x00, 0x00, 0x00,
+				0x01, 0x00, 0x00, 0x00,
+				0x01, 0x0a, 0x19, 0x04, 0x20, 0x40,
+				0x00, 0x00, 0x00, 0x00,
+			},
+		},
+		{
+			Name: "event",
+			Struct: NewNotificationMessage(
+				3, time.Date(2018, time.September, 17, 14, 28, 29, 112000000, time.UTC),
+				NewEventNotificationList(
+					NewEventFieldList(1, NewVariant(NewString("High")), NewVariant(NewInt32(500))),
+				),
+			),
+			Bytes: []byte{
+				// SequenceNumber
+				0x03, 0x00, 0x00, 0x00,
+				// PublishTime
+				0x80, 0x3b, 0xe8, 0xb3, 0x92, 0x4e, 0xd4, 0x01,
+				// NotificationData: ArraySize
+				0x01, 0x00, 0x00, 0x00,
+				// TypeID
+				0x01, 0x00, 0x94, 0x03,
+				// EncodingMask
+				0x01,
+				// Length
+				0x1a, 0x00, 0x00, 0x00,
+				// EventNotificationList
+				0x01, 0x00, 0x00, 0x00,
+				0x01, 0x00, 0x00, 0x00,
+				0x02, 0x00, 0x00, 0x00,
+				0x0c, 0x04, 0x00, 0x00, 0x00, 0x48, 0x69, 0x67, 0x68,
+				0x06, 0xf4, 0x01, 0x00, 0x00,
+			},
+		},
+		{
+			Name:   "keep-alive",
+			Struct: NewNotificationMessage(2, time.Date(2018, time.September, 17, 14, 28, 29, 112000000, time.UTC)),
+			Bytes: []byte{
+				// SequenceNumber
+				0x02, 0x00, 0x00, 0x00,
+				// PublishTime
+				0x80, 0x3b, 0xe8, 0xb3, 0x92, 0x4e, 0xd4, 0x01,
+				// NotificationData: ArraySize
+				0x00, 0x00, 0x00, 0x00,
+			},
+		},
+	}
+	codectest.Run(t, cases, func(b []byte) (codectest.S, error) {
+		return DecodeNotificationMessage(b)
+	})
+
+	t.Run("keep-alive", func(t *testing.T) {
+		for _, c := range cases {
+			got := c.Struct.(*NotificationMessage).IsKeepAlive()
+			if want := c.Name == "keep-alive"; got != want {
+				t.Errorf("%s: got %v want %v", c.Name, got, want)
+			}
+		}
+	})
+}
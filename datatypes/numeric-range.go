@@ -0,0 +1,91 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package datatypes
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/wmnsk/gopcua/errors"
+)
+
+// IndexRange is the range of the indexes in a dimension of an array, from Low to High
+// inclusive. It is a single index if Low and High are the same.
+type IndexRange struct {
+	Low, High uint32
+}
+
+// String returns the text format of IndexRange, e.g. "2:5", or "2" for a single index.
+func (r IndexRange) String() string {
+	if r.Low == r.High {
+		return strconv.FormatUint(uint64(r.Low), 10)
+	}
+	return strconv.FormatUint(uint64(r.Low), 10) + ":" + strconv.FormatUint(uint64(r.High), 10)
+}
+
+// NumericRange is the ranges of the indexes in each dimension of an array or a matrix,
+// which is used as the IndexRange of ReadValueID and WriteValue to access a part of it.
+// The String and the ByteString are the arrays of the Bytes in this context.
+//
+// Specification: Part 4, 7.22
+type NumericRange []IndexRange
+
+// String returns the text format of NumericRange, e.g. "2:5,0:1".
+func (r NumericRange) String() string {
+	dims := make([]string, len(r))
+	for i, d := range r {
+		dims[i] = d.String()
+	}
+	return strings.Join(dims, ",")
+}
+
+// ParseNumericRange parses the text format of NumericRange, e.g. "2:5,0:1".
+//
+// The ranges of the dimensions are separated by "," and each of them is either a single
+// index or the first and the last indexes separated by ":". The first index should be
+// less than the last one.
+//
+// Specification: Part 4, 7.22
+func ParseNumericRange(s string) (NumericRange, error) {
+	if s == "" {
+		return nil, errors.New("numeric range should not be empty")
+	}
+
+	var r NumericRange
+	for _, dim := range strings.Split(s, ",") {
+		bounds := strings.Split(dim, ":")
+		if len(bounds) > 2 {
+			return nil, errors.Errorf("invalid index range %q in numeric range", dim)
+		}
+
+		var idx [2]uint32
+		for i, b := range bounds {
+			n, err := strconv.ParseUint(b, 10, 32)
+			if err != nil {
+				return nil, errors.Errorf("invalid index %q in numeric range", b)
+			}
+			idx[i] = uint32(n)
+		}
+		if len(bounds) == 1 {
+			r = append(r, IndexRange{Low: idx[0], High: idx[0]})
+			continue
+		}
+		if idx[0] >= idx[1] {
+			return nil, errors.Errorf("first index should be less than the last in index range %q", dim)
+		}
+		r = append(r, IndexRange{Low: idx[0], High: idx[1]})
+	}
+	return r, nil
+}
+
+// ParseIndexRange parses the NumericRange s and returns it in the canonical text format
+// to be set as the IndexRange of ReadValueID, e.g. "2:5,0:1" for "02:5,0:1".
+func ParseIndexRange(s string) (string, error) {
+	r, err := ParseNumericRange(s)
+	if err != nil {
+		return "", err
+	}
+	return r.String(), nil
+}
@@ -0,0 +1,52 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package datatypes
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseNumericRange(t *testing.T) {
+	cases := []struct {
+		s    string
+		want NumericRange
+		text string
+	}{
+		{"2", NumericRange{{2, 2}}, "2"},
+		{"2:5", NumericRange{{2, 5}}, "2:5"},
+		{"2:5,0:1", NumericRange{{2, 5}, {0, 1}}, "2:5,0:1"},
+		{"02:5,1", NumericRange{{2, 5}, {1, 1}}, "2:5,1"},
+		{"0:4294967295", NumericRange{{0, 4294967295}}, "0:4294967295"},
+	}
+	for _, c := range cases {
+		t.Run(c.s, func(t *testing.T) {
+			got, err := ParseNumericRange(c.s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(got, c.want); diff != "" {
+				t.Error(diff)
+			}
+
+			text, err := ParseIndexRange(c.s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if text != c.text {
+				t.Errorf("got %q want %q", text, c.text)
+			}
+		})
+	}
+
+	for _, s := range []string{"", ",", "5:2", "2:2", "1:2:3", "-1", "a:b", "1, 2", "4294967296"} {
+		t.Run("invalid "+s, func(t *testing.T) {
+			if _, err := ParseIndexRange(s); err == nil {
+				t.Errorf("expected error for %q, got nil", s)
+			}
+		})
+	}
+}
@@ -4,7 +4,15 @@
 
 package datatypes
 
-import "encoding/binary"
+import (
+	"encoding/binary"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/wmnsk/gopcua/errors"
+	"github.com/wmnsk/gopcua/id"
+)
 
 // QualifiedName contains a qualified name. It is, for example, used as BrowseName.
 // The name part of the QualifiedName is restricted to 512 characters.
@@ -38,6 +46,42 @@ func NewQualifiedName(index uint16, name string) *QualifiedName {
 	return q
 }
 
+// ParseQualifiedName parses the QualifiedName in the format of String, e.g. "2:Temperature".
+//
+// The NamespaceIndex is the decimal number before the first colon, and is 0 if omitted.
+// The rest is the Name as it is, which may contain colons.
+// If the part before the first colon is not a number, e.g. "urn:device", the whole string
+// is the Name in the namespace 0.
+func ParseQualifiedName(s string) (*QualifiedName, error) {
+	if s == "" {
+		return nil, errors.New("qualified name should not be empty")
+	}
+
+	i := strings.IndexByte(s, ':')
+	if i < 0 || !isDigits(s[:i]) {
+		return NewQualifiedName(0, s), nil
+	}
+
+	ns, err := strconv.ParseUint(s[:i], 10, 16)
+	if err != nil {
+		return nil, errors.Errorf("invalid namespace index in qualified name: %q", s[:i])
+	}
+	return NewQualifiedName(uint16(ns), s[i+1:]), nil
+}
+
+// isDigits returns true if s is not empty and consists of decimal digits.
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
+
 // DecodeQualifiedName decodes given bytes into QualifiedName.
 func DecodeQualifiedName(b []byte) (*QualifiedName, error) {
 	q := &QualifiedName{}
@@ -50,6 +94,9 @@ func DecodeQualifiedName(b []byte) (*QualifiedName, error) {
 
 // DecodeFromBytes decodes given bytes into OPC UA QualifiedName.
 func (q *QualifiedName) DecodeFromBytes(b []byte) error {
+	if len(b) < 6 {
+		return errors.NewErrTooShortToDecode(q, "should be longer than 6 bytes")
+	}
 	q.NamespaceIndex = binary.LittleEndian.Uint16(b[:2])
 	q.Name = &String{}
 	return q.Name.DecodeFromBytes(b[2:])
@@ -75,3 +122,17 @@ func (q *QualifiedName) SerializeTo(b []byte) error {
 func (q *QualifiedName) Len() int {
 	return 2 + q.Name.Len()
 }
+
+// String returns QualifiedName in string, with the NamespaceIndex and
+// the Name separated by a colon, e.g. "2:Device".
+func (q *QualifiedName) String() string {
+	if q.Name == nil {
+		return fmt.Sprintf("%d:", q.NamespaceIndex)
+	}
+	return fmt.Sprintf("%d:%s", q.NamespaceIndex, q.Name.Get())
+}
+
+// DataType returns type of Data.
+func (q *QualifiedName) DataType() uint16 {
+	return id.QualifiedName
+}
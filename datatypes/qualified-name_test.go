@@ -7,6 +7,7 @@ package datatypes
 import (
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
 	"github.com/wmnsk/gopcua/utils/codectest"
 )
 
@@ -36,3 +37,55 @@ func TestQualifiedName(t *testing.T) {
 		return DecodeQualifiedName(b)
 	})
 }
+
+func TestQualifiedNameTooShort(t *testing.T) {
+	if _, err := DecodeQualifiedName([]byte{}); err == nil {
+		t.Fatal("expected error for empty bytes, got nil")
+	}
+}
+
+func TestParseQualifiedName(t *testing.T) {
+	cases := []struct {
+		s    string
+		want *QualifiedName
+		str  string
+	}{
+		{"2:Temperature", NewQualifiedName(2, "Temperature"), "2:Temperature"},
+		{"Temperature", NewQualifiedName(0, "Temperature"), "0:Temperature"},
+		{"0:Temperature", NewQualifiedName(0, "Temperature"), "0:Temperature"},
+		{"3:Device:Temperature", NewQualifiedName(3, "Device:Temperature"), "3:Device:Temperature"},
+		{"urn:device", NewQualifiedName(0, "urn:device"), "0:urn:device"},
+		{"2:", NewQualifiedName(2, ""), "2:"},
+	}
+	for _, c := range cases {
+		t.Run(c.s, func(t *testing.T) {
+			q, err := ParseQualifiedName(c.s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(q, c.want); diff != "" {
+				t.Error(diff)
+			}
+			if got := q.String(); got != c.str {
+				t.Errorf("String: got %q want %q", got, c.str)
+			}
+
+			// String should be parsed back into the same QualifiedName.
+			r, err := ParseQualifiedName(q.String())
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(r, c.want); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+
+	for _, s := range []string{"", "65536:Temperature"} {
+		t.Run("invalid/"+s, func(t *testing.T) {
+			if q, err := ParseQualifiedName(s); err == nil {
+				t.Errorf("got %v want error", q)
+			}
+		})
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package datatypes
+
+import (
+	"encoding/binary"
+	"time"
+
+	"github.com/wmnsk/gopcua/errors"
+	"github.com/wmnsk/gopcua/id"
+)
+
+// ReadRawModifiedDetails is the HistoryReadDetails to read the raw values, or the values
+// modified if IsReadModified is true, of the Nodes between StartTime and EndTime.
+//
+// At most NumValuesPerNode values are returned for each Node, or all of them if it is 0.
+// If ReturnBounds is true, the bounding values just before StartTime and just after
+// EndTime are returned as well.
+//
+// Specification: Part 11, 6.4.3
+type ReadRawModifiedDetails struct {
+	IsReadModified   *Boolean
+	StartTime        time.Time
+	EndTime          time.Time
+	NumValuesPerNode uint32
+	ReturnBounds     *Boolean
+}
+
+// NewReadRawModifiedDetails creates a new ReadRawModifiedDetails.
+func NewReadRawModifiedDetails(modified bool, start, end time.Time, numValues uint32, bounds bool) *ReadRawModifiedDetails {
+	return &ReadRawModifiedDetails{
+		IsReadModified:   NewBoolean(modified),
+		StartTime:        start,
+		EndTime:          end,
+		NumValuesPerNode: numValues,
+		ReturnBounds:     NewBoolean(bounds),
+	}
+}
+
+// DecodeReadRawModifiedDetails decodes given bytes into ReadRawModifiedDetails.
+func DecodeReadRawModifiedDetails(b []byte) (*ReadRawModifiedDetails, error) {
+	r := &ReadRawModifiedDetails{}
+	if err := r.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// DecodeFromBytes decodes given bytes into ReadRawModifiedDetails.
+func (r *ReadRawModifiedDetails) DecodeFromBytes(b []byte) error {
+	if len(b) < 22 {
+		return errors.NewErrTooShortToDecode(r, "should be longer than 22 bytes")
+	}
+
+	r.IsReadModified = &Boolean{}
+	if err := r.IsReadModified.DecodeFromBytes(b); err != nil {
+		return err
+	}
+	offset := r.IsReadModified.Len()
+
+	r.StartTime = decodeDateTime(b[offset : offset+8])
+	offset += 8
+	r.EndTime = decodeDateTime(b[offset : offset+8])
+	offset += 8
+	r.NumValuesPerNode = binary.LittleEndian.Uint32(b[offset : offset+4])
+	offset += 4
+
+	r.ReturnBounds = &Boolean{}
+	return r.ReturnBounds.DecodeFromBytes(b[offset:])
+}
+
+// Serialize serializes ReadRawModifiedDetails into bytes.
+func (r *ReadRawModifiedDetails) Serialize() ([]byte, error) {
+	b := make([]byte, r.Len())
+	if err := r.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes ReadRawModifiedDetails into bytes.
+func (r *ReadRawModifiedDetails) SerializeTo(b []byte) error {
+	offset := 0
+	if r.IsReadModified != nil {
+		if err := r.IsReadModified.SerializeTo(b); err != nil {
+			return err
+		}
+		offset += r.IsReadModified.Len()
+	}
+
+	encodeDateTime(b[offset:offset+8], r.StartTime)
+	offset += 8
+	encodeDateTime(b[offset:offset+8], r.EndTime)
+	offset += 8
+	binary.LittleEndian.PutUint32(b[offset:offset+4], r.NumValuesPerNode)
+	offset += 4
+
+	if r.ReturnBounds != nil {
+		return r.ReturnBounds.SerializeTo(b[offset:])
+	}
+
+	return nil
+}
+
+// Len returns the actual length of ReadRawModifiedDetails in int.
+func (r *ReadRawModifiedDetails) Len() int {
+	l := 20
+	if r.IsReadModified != nil {
+		l += r.IsReadModified.Len()
+	}
+	if r.ReturnBounds != nil {
+		l += r.ReturnBounds.Len()
+	}
+
+	return l
+}
+
+// Type returns type of ReadRawModifiedDetails.
+func (r *ReadRawModifiedDetails) Type() int {
+	return id.ReadRawModifiedDetails_Encoding_DefaultBinary
+}
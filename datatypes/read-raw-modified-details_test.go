@@ -0,0 +1,42 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package datatypes
+
+import (
+	"testing"
+	"time"
+
+	"github.com/wmnsk/gopcua/utils/codectest"
+)
+
+func TestReadRawModifiedDetails(t *testing.T) {
+	cases := []codectest.Case{
+		{
+			Name: "normal",
+			Struct: NewReadRawModifiedDetails(
+				false,
+				time.Date(2018, time.August, 10, 23, 0, 0, 0, time.UTC),
+				time.Time{},
+				100,
+				true,
+			),
+			Bytes: []byte{
+				// IsReadModified
+				0x00,
+				// StartTime
+				0x00, 0x98, 0x67, 0xdd, 0xfd, 0x30, 0xd4, 0x01,
+				// EndTime
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				// NumValuesPerNode
+				0x64, 0x00, 0x00, 0x00,
+				// ReturnBounds
+				0x01,
+			},
+		},
+	}
+	codectest.Run(t, cases, func(b []byte) (codectest.S, error) {
+		return DecodeReadRawModifiedDetails(b)
+	})
+}
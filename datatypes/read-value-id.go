@@ -12,37 +12,37 @@ import "encoding/binary"
 // Specification: Part 4, 7.14
 type IntegerID uint32
 
-// Identifiers assigned to Attributes.
+// Identifiers assigned to Attributes, which are the same as the AttributeIDs.
 //
-// Specification: Part 6, A.1
+// Deprecated: Use the AttributeID constants instead, e.g. AttributeIDValue for IntegerIDValue.
 const (
-	IntegerIDNodeID IntegerID = iota + 1
-	IntegerIDNodeClass
-	IntegerIDBrowseName
-	IntegerIDDisplayName
-	IntegerIDDescription
-	IntegerIDWriteMask
-	IntegerIDUserWriteMask
-	IntegerIDIsAbstract
-	IntegerIDSymmetric
-	IntegerIDInverseName
-	IntegerIDContainsNoLoops
-	IntegerIDEventNotifier
-	IntegerIDValue
-	IntegerIDDataType
-	IntegerIDValueRank
-	IntegerIDArrayDimensions
-	IntegerIDAccessLevel
-	IntegerIDUserAccessLevel
-	IntegerIDMinimumSamplingInterval
-	IntegerIDHistorizing
-	IntegerIDExecutable
-	IntegerIDUserExecutable
-	IntegerIDDataTypeDefinition
-	IntegerIDRolePermissions
-	IntegerIDUserRolePermissions
-	IntegerIDAccessRestrictions
-	IntegerIDAccessLevelEx
+	IntegerIDNodeID                  = AttributeIDNodeID
+	IntegerIDNodeClass               = AttributeIDNodeClass
+	IntegerIDBrowseName              = AttributeIDBrowseName
+	IntegerIDDisplayName             = AttributeIDDisplayName
+	IntegerIDDescription             = AttributeIDDescription
+	IntegerIDWriteMask               = AttributeIDWriteMask
+	IntegerIDUserWriteMask           = AttributeIDUserWriteMask
+	IntegerIDIsAbstract              = AttributeIDIsAbstract
+	IntegerIDSymmetric               = AttributeIDSymmetric
+	IntegerIDInverseName             = AttributeIDInverseName
+	IntegerIDContainsNoLoops         = AttributeIDContainsNoLoops
+	IntegerIDEventNotifier           = AttributeIDEventNotifier
+	IntegerIDValue                   = AttributeIDValue
+	IntegerIDDataType                = AttributeIDDataType
+	IntegerIDValueRank               = AttributeIDValueRank
+	IntegerIDArrayDimensions         = AttributeIDArrayDimensions
+	IntegerIDAccessLevel             = AttributeIDAccessLevel
+	IntegerIDUserAccessLevel         = AttributeIDUserAccessLevel
+	IntegerIDMinimumSamplingInterval = AttributeIDMinimumSamplingInterval
+	IntegerIDHistorizing             = AttributeIDHistorizing
+	IntegerIDExecutable              = AttributeIDExecutable
+	IntegerIDUserExecutable          = AttributeIDUserExecutable
+	IntegerIDDataTypeDefinition      = AttributeIDDataTypeDefinition
+	IntegerIDRolePermissions         = AttributeIDRolePermissions
+	IntegerIDUserRolePermissions     = AttributeIDUserRolePermissions
+	IntegerIDAccessRestrictions      = AttributeIDAccessRestrictions
+	IntegerIDAccessLevelEx           = AttributeIDAccessLevelEx
 )
 
 // ReadValueID is an identifier for an item to read or to monitor.
@@ -50,13 +50,13 @@ const (
 // Specification: Part 4, 7.24
 type ReadValueID struct {
 	NodeID       *NodeID
-	AttributeID  IntegerID
+	AttributeID  AttributeID
 	IndexRange   *String
 	DataEncoding *QualifiedName
 }
 
 // NewReadValueID creates a new ReadValueID.
-func NewReadValueID(nodeID *NodeID, attrID IntegerID, idxRange string, qIdx uint16, qName string) *ReadValueID {
+func NewReadValueID(nodeID *NodeID, attrID AttributeID, idxRange string, qIdx uint16, qName string) *ReadValueID {
 	return &ReadValueID{
 		NodeID:       nodeID,
 		AttributeID:  attrID,
@@ -84,7 +84,7 @@ func (r *ReadValueID) DecodeFromBytes(b []byte) error {
 	offset := r.NodeID.Len()
 
 	// attribute id
-	r.AttributeID = IntegerID(binary.LittleEndian.Uint32(b[offset:]))
+	r.AttributeID = AttributeID(binary.LittleEndian.Uint32(b[offset:]))
 	offset += 4
 
 	// index range
@@ -16,7 +16,7 @@ func TestReadValueID(t *testing.T) {
 			Name: "Normal",
 			Struct: NewReadValueID(
 				NewFourByteNodeID(0, 2256),
-				IntegerIDValue,
+				AttributeIDValue,
 				"", 0, "",
 			),
 			Bytes: []byte{
@@ -33,6 +33,30 @@ func TestReadValueID(t *testing.T) {
 				0xff, 0xff, 0xff, 0xff,
 			},
 		},
+		{
+			Name: "IndexRange",
+			Struct: NewReadValueID(
+				NewFourByteNodeID(0, 2256),
+				AttributeIDValue,
+				"2:5,0:1", 0, "Default Binary",
+			),
+			Bytes: []byte{
+				// NodeID
+				0x01,
+				0x00,
+				0xd0, 0x08,
+				// AttributeID
+				0x0d, 0x00, 0x00, 0x00,
+				// Index Range
+				0x07, 0x00, 0x00, 0x00,
+				0x32, 0x3a, 0x35, 0x2c, 0x30, 0x3a, 0x31,
+				// qualified name
+				0x00, 0x00,
+				0x0e, 0x00, 0x00, 0x00,
+				0x44, 0x65, 0x66, 0x61, 0x75, 0x6c, 0x74, 0x20,
+				0x42, 0x69, 0x6e, 0x61, 0x72, 0x79,
+			},
+		},
 	}
 	codectest.Run(t, cases, func(b []byte) (codectest.S, error) {
 		return DecodeReadValueID(b)
@@ -47,19 +71,19 @@ func TestReadValueIDArray(t *testing.T) {
 				[]*ReadValueID{
 					{
 						NodeID:       NewStringNodeID(1, "Temperature"),
-						AttributeID:  IntegerIDNodeClass,
+						AttributeID:  AttributeIDNodeClass,
 						IndexRange:   NewString(""),
 						DataEncoding: NewQualifiedName(0, ""),
 					},
 					{
 						NodeID:       NewStringNodeID(1, "Temperature"),
-						AttributeID:  IntegerIDBrowseName,
+						AttributeID:  AttributeIDBrowseName,
 						IndexRange:   NewString(""),
 						DataEncoding: NewQualifiedName(0, ""),
 					},
 					{
 						NodeID:       NewStringNodeID(1, "Temperature"),
-						AttributeID:  IntegerIDDisplayName,
+						AttributeID:  AttributeIDDisplayName,
 						IndexRange:   NewString(""),
 						DataEncoding: NewQualifiedName(0, ""),
 					},
@@ -0,0 +1,284 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package datatypes
+
+import (
+	"encoding/binary"
+
+	"github.com/wmnsk/gopcua/errors"
+)
+
+// NodeClass is the class of a Node.
+//
+// Specification: Part 3, 8.29
+type NodeClass uint32
+
+// NodeClass definitions.
+const (
+	NodeClassUnspecified   NodeClass = 0
+	NodeClassObject        NodeClass = 1
+	NodeClassVariable      NodeClass = 2
+	NodeClassMethod        NodeClass = 4
+	NodeClassObjectType    NodeClass = 8
+	NodeClassVariableType  NodeClass = 16
+	NodeClassReferenceType NodeClass = 32
+	NodeClassDataType      NodeClass = 64
+	NodeClassView          NodeClass = 128
+)
+
+// ReferenceDescription is a Reference returned by Browse and the
+// attributes of its target Node.
+//
+// Specification: Part 4, 7.25
+type ReferenceDescription struct {
+	ReferenceTypeID *NodeID
+	IsForward       *Boolean
+	NodeID          *ExpandedNodeID
+	BrowseName      *QualifiedName
+	DisplayName     *LocalizedText
+	NodeClass       NodeClass
+	TypeDefinition  *ExpandedNodeID
+}
+
+// NewReferenceDescription creates a new ReferenceDescription.
+func NewReferenceDescription(refType *NodeID, isForward bool, node *ExpandedNodeID, browseName *QualifiedName, displayName *LocalizedText, nodeClass NodeClass, typeDef *ExpandedNodeID) *ReferenceDescription {
+	return &ReferenceDescription{
+		ReferenceTypeID: refType,
+		IsForward:       NewBoolean(isForward),
+		NodeID:          node,
+		BrowseName:      browseName,
+		DisplayName:     displayName,
+		NodeClass:       nodeClass,
+		TypeDefinition:  typeDef,
+	}
+}
+
+// DecodeReferenceDescription decodes given bytes into ReferenceDescription.
+func DecodeReferenceDescription(b []byte) (*ReferenceDescription, error) {
+	r := &ReferenceDescription{}
+	if err := r.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// DecodeFromBytes decodes given bytes into ReferenceDescription.
+func (r *ReferenceDescription) DecodeFromBytes(b []byte) error {
+	r.ReferenceTypeID = &NodeID{}
+	if err := r.ReferenceTypeID.DecodeFromBytes(b); err != nil {
+		return err
+	}
+	offset := r.ReferenceTypeID.Len()
+
+	if len(b[offset:]) < 1 {
+		return errors.NewErrTooShortToDecode(r, "should contain IsForward")
+	}
+	r.IsForward = &Boolean{}
+	if err := r.IsForward.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += r.IsForward.Len()
+
+	r.NodeID = &ExpandedNodeID{}
+	if err := r.NodeID.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += r.NodeID.Len()
+
+	r.BrowseName = &QualifiedName{}
+	if err := r.BrowseName.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += r.BrowseName.Len()
+
+	r.DisplayName = &LocalizedText{}
+	if err := r.DisplayName.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += r.DisplayName.Len()
+
+	if len(b[offset:]) < 4 {
+		return errors.NewErrTooShortToDecode(r, "should contain NodeClass")
+	}
+	r.NodeClass = NodeClass(binary.LittleEndian.Uint32(b[offset : offset+4]))
+	offset += 4
+
+	r.TypeDefinition = &ExpandedNodeID{}
+	return r.TypeDefinition.DecodeFromBytes(b[offset:])
+}
+
+// Serialize serializes ReferenceDescription into bytes.
+func (r *ReferenceDescription) Serialize() ([]byte, error) {
+	b := make([]byte, r.Len())
+	if err := r.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes ReferenceDescription into bytes.
+func (r *ReferenceDescription) SerializeTo(b []byte) error {
+	offset := 0
+	if r.ReferenceTypeID != nil {
+		if err := r.ReferenceTypeID.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += r.ReferenceTypeID.Len()
+	}
+
+	if r.IsForward != nil {
+		if err := r.IsForward.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += r.IsForward.Len()
+	}
+
+	if r.NodeID != nil {
+		if err := r.NodeID.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += r.NodeID.Len()
+	}
+
+	if r.BrowseName != nil {
+		if err := r.BrowseName.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += r.BrowseName.Len()
+	}
+
+	if r.DisplayName != nil {
+		if err := r.DisplayName.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += r.DisplayName.Len()
+	}
+
+	binary.LittleEndian.PutUint32(b[offset:offset+4], uint32(r.NodeClass))
+	offset += 4
+
+	if r.TypeDefinition != nil {
+		return r.TypeDefinition.SerializeTo(b[offset:])
+	}
+
+	return nil
+}
+
+// Len returns the actual length of ReferenceDescription in int.
+func (r *ReferenceDescription) Len() int {
+	l := 4
+	if r.ReferenceTypeID != nil {
+		l += r.ReferenceTypeID.Len()
+	}
+	if r.IsForward != nil {
+		l += r.IsForward.Len()
+	}
+	if r.NodeID != nil {
+		l += r.NodeID.Len()
+	}
+	if r.BrowseName != nil {
+		l += r.BrowseName.Len()
+	}
+	if r.DisplayName != nil {
+		l += r.DisplayName.Len()
+	}
+	if r.TypeDefinition != nil {
+		l += r.TypeDefinition.Len()
+	}
+
+	return l
+}
+
+// ReferenceDescriptionArray represents an array of ReferenceDescriptions.
+// It does not correspond to a certain type from the specification
+// but makes encoding and decoding easier.
+type ReferenceDescriptionArray struct {
+	ArraySize             int32
+	ReferenceDescriptions []*ReferenceDescription
+}
+
+// NewReferenceDescriptionArray creates a new ReferenceDescriptionArray from multiple ReferenceDescriptions.
+func NewReferenceDescriptionArray(refs []*ReferenceDescription) *ReferenceDescriptionArray {
+	if refs == nil {
+		return &ReferenceDescriptionArray{
+			ArraySize: 0,
+		}
+	}
+
+	return &ReferenceDescriptionArray{
+		ArraySize:             int32(len(refs)),
+		ReferenceDescriptions: refs,
+	}
+}
+
+// DecodeReferenceDescriptionArray decodes given bytes into ReferenceDescriptionArray.
+func DecodeReferenceDescriptionArray(b []byte) (*ReferenceDescriptionArray, error) {
+	a := &ReferenceDescriptionArray{}
+	if err := a.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// DecodeFromBytes decodes given bytes into ReferenceDescriptionArray.
+func (a *ReferenceDescriptionArray) DecodeFromBytes(b []byte) error {
+	if len(b) < 4 {
+		return errors.NewErrTooShortToDecode(a, "should be longer than 4 bytes")
+	}
+	a.ArraySize = int32(binary.LittleEndian.Uint32(b[:4]))
+	if a.ArraySize <= 0 {
+		return nil
+	}
+
+	offset := 4
+	for i := 1; i <= int(a.ArraySize); i++ {
+		r, err := DecodeReferenceDescription(b[offset:])
+		if err != nil {
+			return err
+		}
+		a.ReferenceDescriptions = append(a.ReferenceDescriptions, r)
+		offset += r.Len()
+	}
+
+	return nil
+}
+
+// Serialize serializes ReferenceDescriptionArray into bytes.
+func (a *ReferenceDescriptionArray) Serialize() ([]byte, error) {
+	b := make([]byte, a.Len())
+	if err := a.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes ReferenceDescriptionArray into bytes.
+func (a *ReferenceDescriptionArray) SerializeTo(b []byte) error {
+	offset := 4
+	binary.LittleEndian.PutUint32(b[:4], uint32(a.ArraySize))
+
+	for _, r := range a.ReferenceDescriptions {
+		if err := r.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += r.Len()
+	}
+
+	return nil
+}
+
+// Len returns the actual length in int.
+func (a *ReferenceDescriptionArray) Len() int {
+	l := 4
+	for _, r := range a.ReferenceDescriptions {
+		l += r.Len()
+	}
+
+	return l
+}
@@ -0,0 +1,94 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package datatypes
+
+import (
+	"testing"
+
+	"github.com/wmnsk/gopcua/utils/codectest"
+)
+
+func TestReferenceDescription(t *testing.T) {
+	cases := []codectest.Case{
+		{
+			Name: "normal",
+			Struct: NewReferenceDescription(
+				NewTwoByteNodeID(35), true,
+				NewFourByteExpandedNodeID(0, 2253),
+				NewQualifiedName(0, "Server"),
+				NewLocalizedText("", "Server"),
+				NodeClassObject,
+				NewFourByteExpandedNodeID(0, 2004),
+			),
+			Bytes: []byte{
+				// ReferenceTypeID
+				0x00, 0x23,
+				// IsForward
+				0x01,
+				// NodeID
+				0x01, 0x00, 0xcd, 0x08,
+				// BrowseName
+				0x00, 0x00, 0x06, 0x00, 0x00, 0x00,
+				0x53, 0x65, 0x72, 0x76, 0x65, 0x72,
+				// DisplayName
+				0x02, 0x06, 0x00, 0x00, 0x00,
+				0x53, 0x65, 0x72, 0x76, 0x65, 0x72,
+				// NodeClass
+				0x01, 0x00, 0x00, 0x00,
+				// TypeDefinition
+				0x01, 0x00, 0xd4, 0x07,
+			},
+		},
+	}
+	codectest.Run(t, cases, func(b []byte) (codectest.S, error) {
+		return DecodeReferenceDescription(b)
+	})
+}
+
+func TestReferenceDescriptionArray(t *testing.T) {
+	cases := []codectest.Case{
+		{
+			Name:   "empty",
+			Struct: NewReferenceDescriptionArray(nil),
+			Bytes:  []byte{0x00, 0x00, 0x00, 0x00},
+		},
+		{
+			Name: "normal",
+			Struct: NewReferenceDescriptionArray([]*ReferenceDescription{
+				NewReferenceDescription(
+					NewTwoByteNodeID(35), true,
+					NewFourByteExpandedNodeID(0, 2253),
+					NewQualifiedName(0, "Server"),
+					NewLocalizedText("", "Server"),
+					NodeClassObject,
+					NewFourByteExpandedNodeID(0, 2004),
+				),
+			}),
+			Bytes: []byte{
+				// ArraySize
+				0x01, 0x00, 0x00, 0x00,
+				// ReferenceTypeID
+				0x00, 0x23,
+				// IsForward
+				0x01,
+				// NodeID
+				0x01, 0x00, 0xcd, 0x08,
+				// BrowseName
+				0x00, 0x00, 0x06, 0x00, 0x00, 0x00,
+				0x53, 0x65, 0x72, 0x76, 0x65, 0x72,
+				// DisplayName
+				0x02, 0x06, 0x00, 0x00, 0x00,
+				0x53, 0x65, 0x72, 0x76, 0x65, 0x72,
+				// NodeClass
+				0x01, 0x00, 0x00, 0x00,
+				// TypeDefinition
+				0x01, 0x00, 0xd4, 0x07,
+			},
+		},
+	}
+	codectest.Run(t, cases, func(b []byte) (codectest.S, error) {
+		return DecodeReferenceDescriptionArray(b)
+	})
+}
@@ -0,0 +1,215 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package datatypes
+
+import (
+	"encoding/binary"
+
+	"github.com/wmnsk/gopcua/errors"
+	"github.com/wmnsk/gopcua/id"
+)
+
+// RegisteredServer is the information about a Server registered to a Discovery Server
+// with RegisterServer2. The ServerType is the ApplicationType, e.g. services.AppTypeServer.
+//
+// IsOnline should be false when the Server is shutting down, so that the Discovery Server
+// removes it immediately.
+//
+// Specification: Part 4, 7.32
+type RegisteredServer struct {
+	ServerURI         *String
+	ProductURI        *String
+	ServerNames       []*LocalizedText
+	ServerType        uint32
+	GatewayServerURI  *String
+	DiscoveryURIs     *StringArray
+	SemaphoreFilePath *String
+	IsOnline          *Boolean
+}
+
+// NewRegisteredServer creates a new RegisteredServer with the ServerName in the locale "en-US".
+func NewRegisteredServer(serverURI, productURI, serverName string, serverType uint32, discoveryURIs []string, isOnline bool) *RegisteredServer {
+	return &RegisteredServer{
+		ServerURI:         NewString(serverURI),
+		ProductURI:        NewString(productURI),
+		ServerNames:       []*LocalizedText{NewLocalizedText("en-US", serverName)},
+		ServerType:        serverType,
+		GatewayServerURI:  NewString(""),
+		DiscoveryURIs:     NewStringArray(discoveryURIs),
+		SemaphoreFilePath: NewString(""),
+		IsOnline:          NewBoolean(isOnline),
+	}
+}
+
+// DecodeRegisteredServer decodes given bytes into RegisteredServer.
+func DecodeRegisteredServer(b []byte) (*RegisteredServer, error) {
+	r := &RegisteredServer{}
+	if err := r.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// DecodeFromBytes decodes given bytes into RegisteredServer.
+func (r *RegisteredServer) DecodeFromBytes(b []byte) error {
+	r.ServerURI = &String{}
+	if err := r.ServerURI.DecodeFromBytes(b); err != nil {
+		return err
+	}
+	offset := r.ServerURI.Len()
+
+	r.ProductURI = &String{}
+	if err := r.ProductURI.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += r.ProductURI.Len()
+
+	if len(b[offset:]) < 4 {
+		return errors.NewErrTooShortToDecode(r, "should contain ServerNames")
+	}
+	n := int32(binary.LittleEndian.Uint32(b[offset : offset+4]))
+	offset += 4
+
+	r.ServerNames = nil
+	for i := 0; i < int(n); i++ {
+		l := &LocalizedText{}
+		if err := l.DecodeFromBytes(b[offset:]); err != nil {
+			return err
+		}
+		r.ServerNames = append(r.ServerNames, l)
+		offset += l.Len()
+	}
+
+	if len(b[offset:]) < 4 {
+		return errors.NewErrTooShortToDecode(r, "should contain ServerType")
+	}
+	r.ServerType = binary.LittleEndian.Uint32(b[offset : offset+4])
+	offset += 4
+
+	r.GatewayServerURI = &String{}
+	if err := r.GatewayServerURI.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += r.GatewayServerURI.Len()
+
+	r.DiscoveryURIs = &StringArray{}
+	if err := r.DiscoveryURIs.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += r.DiscoveryURIs.Len()
+
+	r.SemaphoreFilePath = &String{}
+	if err := r.SemaphoreFilePath.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += r.SemaphoreFilePath.Len()
+
+	if len(b[offset:]) < 1 {
+		return errors.NewErrTooShortToDecode(r, "should contain IsOnline")
+	}
+	r.IsOnline = &Boolean{}
+	return r.IsOnline.DecodeFromBytes(b[offset:])
+}
+
+// Serialize serializes RegisteredServer into bytes.
+func (r *RegisteredServer) Serialize() ([]byte, error) {
+	b := make([]byte, r.Len())
+	if err := r.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes RegisteredServer into bytes.
+func (r *RegisteredServer) SerializeTo(b []byte) error {
+	offset := 0
+	if r.ServerURI != nil {
+		if err := r.ServerURI.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += r.ServerURI.Len()
+	}
+
+	if r.ProductURI != nil {
+		if err := r.ProductURI.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += r.ProductURI.Len()
+	}
+
+	binary.LittleEndian.PutUint32(b[offset:offset+4], uint32(len(r.ServerNames)))
+	offset += 4
+	for _, l := range r.ServerNames {
+		if err := l.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += l.Len()
+	}
+
+	binary.LittleEndian.PutUint32(b[offset:offset+4], r.ServerType)
+	offset += 4
+
+	if r.GatewayServerURI != nil {
+		if err := r.GatewayServerURI.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += r.GatewayServerURI.Len()
+	}
+
+	if r.DiscoveryURIs != nil {
+		if err := r.DiscoveryURIs.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += r.DiscoveryURIs.Len()
+	}
+
+	if r.SemaphoreFilePath != nil {
+		if err := r.SemaphoreFilePath.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += r.SemaphoreFilePath.Len()
+	}
+
+	if r.IsOnline != nil {
+		return r.IsOnline.SerializeTo(b[offset:])
+	}
+
+	return nil
+}
+
+// Len returns the actual length of RegisteredServer in int.
+func (r *RegisteredServer) Len() int {
+	l := 8
+	if r.ServerURI != nil {
+		l += r.ServerURI.Len()
+	}
+	if r.ProductURI != nil {
+		l += r.ProductURI.Len()
+	}
+	for _, n := range r.ServerNames {
+		l += n.Len()
+	}
+	if r.GatewayServerURI != nil {
+		l += r.GatewayServerURI.Len()
+	}
+	if r.DiscoveryURIs != nil {
+		l += r.DiscoveryURIs.Len()
+	}
+	if r.SemaphoreFilePath != nil {
+		l += r.SemaphoreFilePath.Len()
+	}
+	if r.IsOnline != nil {
+		l += r.IsOnline.Len()
+	}
+
+	return l
+}
+
+// Type returns type of RegisteredServer defined in NodeIds.csv in int.
+func (r *RegisteredServer) Type() int {
+	return id.RegisteredServer_Encoding_DefaultBinary
+}
@@ -0,0 +1,81 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package datatypes
+
+import (
+	"testing"
+
+	"github.com/wmnsk/gopcua/utils/codectest"
+)
+
+func TestRegisteredServer(t *testing.T) {
+	cases := []codectest.Case{
+		{
+			Name: "online",
+			Struct: NewRegisteredServer(
+				"urn:srv", "urn:prod", "srv", 0, []string{"opc.tcp://a"}, true,
+			),
+			Bytes: []byte{
+				// ServerURI
+				0x07, 0x00, 0x00, 0x00,
+				0x75, 0x72, 0x6e, 0x3a, 0x73, 0x72, 0x76,
+				// ProductURI
+				0x08, 0x00, 0x00, 0x00,
+				0x75, 0x72, 0x6e, 0x3a, 0x70, 0x72, 0x6f, 0x64,
+				// ServerNames
+				0x01, 0x00, 0x00, 0x00,
+				0x03,
+				0x05, 0x00, 0x00, 0x00, 0x65, 0x6e, 0x2d, 0x55, 0x53,
+				0x03, 0x00, 0x00, 0x00, 0x73, 0x72, 0x76,
+				// ServerType
+				0x00, 0x00, 0x00, 0x00,
+				// GatewayServerURI
+				0xff, 0xff, 0xff, 0xff,
+				// DiscoveryURIs
+				0x01, 0x00, 0x00, 0x00,
+				0x0b, 0x00, 0x00, 0x00,
+				0x6f, 0x70, 0x63, 0x2e, 0x74, 0x63, 0x70, 0x3a, 0x2f, 0x2f, 0x61,
+				// SemaphoreFilePath
+				0xff, 0xff, 0xff, 0xff,
+				// IsOnline
+				0x01,
+			},
+		},
+		{
+			Name: "offline-no-names",
+			Struct: &RegisteredServer{
+				ServerURI:         NewString("urn:srv"),
+				ProductURI:        NewString(""),
+				ServerType:        3,
+				GatewayServerURI:  NewString(""),
+				DiscoveryURIs:     NewStringArray(nil),
+				SemaphoreFilePath: NewString(""),
+				IsOnline:          NewBoolean(false),
+			},
+			Bytes: []byte{
+				// ServerURI
+				0x07, 0x00, 0x00, 0x00,
+				0x75, 0x72, 0x6e, 0x3a, 0x73, 0x72, 0x76,
+				// ProductURI
+				0xff, 0xff, 0xff, 0xff,
+				// ServerNames
+				0x00, 0x00, 0x00, 0x00,
+				// ServerType
+				0x03, 0x00, 0x00, 0x00,
+				// GatewayServerURI
+				0xff, 0xff, 0xff, 0xff,
+				// DiscoveryURIs
+				0x00, 0x00, 0x00, 0x00,
+				// SemaphoreFilePath
+				0xff, 0xff, 0xff, 0xff,
+				// IsOnline
+				0x00,
+			},
+		},
+	}
+	codectest.Run(t, cases, func(b []byte) (codectest.S, error) {
+		return DecodeRegisteredServer(b)
+	})
+}
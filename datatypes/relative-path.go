@@ -0,0 +1,287 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package datatypes
+
+import (
+	"encoding/binary"
+	"strconv"
+	"strings"
+
+	"github.com/wmnsk/gopcua/errors"
+	"github.com/wmnsk/gopcua/id"
+)
+
+// RelativePathElement is an element of RelativePath, which follows the References
+// of ReferenceTypeID to the target which has the TargetName as BrowseName.
+//
+// Specification: Part 4, 7.26
+type RelativePathElement struct {
+	ReferenceTypeID *NodeID
+	IsInverse       *Boolean
+	IncludeSubtypes *Boolean
+	TargetName      *QualifiedName
+}
+
+// NewRelativePathElement creates a new RelativePathElement.
+func NewRelativePathElement(refType *NodeID, inverse, subtypes bool, target *QualifiedName) *RelativePathElement {
+	return &RelativePathElement{
+		ReferenceTypeID: refType,
+		IsInverse:       NewBoolean(inverse),
+		IncludeSubtypes: NewBoolean(subtypes),
+		TargetName:      target,
+	}
+}
+
+// DecodeRelativePathElement decodes given bytes into RelativePathElement.
+func DecodeRelativePathElement(b []byte) (*RelativePathElement, error) {
+	r := &RelativePathElement{}
+	if err := r.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// DecodeFromBytes decodes given bytes into RelativePathElement.
+func (r *RelativePathElement) DecodeFromBytes(b []byte) error {
+	r.ReferenceTypeID = &NodeID{}
+	if err := r.ReferenceTypeID.DecodeFromBytes(b); err != nil {
+		return err
+	}
+	offset := r.ReferenceTypeID.Len()
+
+	if len(b[offset:]) < 4 {
+		return errors.NewErrTooShortToDecode(r, "should contain IsInverse, IncludeSubtypes and TargetName")
+	}
+	r.IsInverse = &Boolean{}
+	if err := r.IsInverse.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += r.IsInverse.Len()
+
+	r.IncludeSubtypes = &Boolean{}
+	if err := r.IncludeSubtypes.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += r.IncludeSubtypes.Len()
+
+	if len(b[offset:]) < 6 {
+		return errors.NewErrTooShortToDecode(r, "should contain TargetName")
+	}
+	r.TargetName = &QualifiedName{}
+	return r.TargetName.DecodeFromBytes(b[offset:])
+}
+
+// Serialize serializes RelativePathElement into bytes.
+func (r *RelativePathElement) Serialize() ([]byte, error) {
+	b := make([]byte, r.Len())
+	if err := r.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes RelativePathElement into bytes.
+func (r *RelativePathElement) SerializeTo(b []byte) error {
+	offset := 0
+	if r.ReferenceTypeID != nil {
+		if err := r.ReferenceTypeID.SerializeTo(b); err != nil {
+			return err
+		}
+		offset += r.ReferenceTypeID.Len()
+	}
+
+	if r.IsInverse != nil {
+		if err := r.IsInverse.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += r.IsInverse.Len()
+	}
+
+	if r.IncludeSubtypes != nil {
+		if err := r.IncludeSubtypes.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += r.IncludeSubtypes.Len()
+	}
+
+	if r.TargetName != nil {
+		return r.TargetName.SerializeTo(b[offset:])
+	}
+
+	return nil
+}
+
+// Len returns the actual length of RelativePathElement in int.
+func (r *RelativePathElement) Len() int {
+	var l int
+	if r.ReferenceTypeID != nil {
+		l += r.ReferenceTypeID.Len()
+	}
+	if r.IsInverse != nil {
+		l += r.IsInverse.Len()
+	}
+	if r.IncludeSubtypes != nil {
+		l += r.IncludeSubtypes.Len()
+	}
+	if r.TargetName != nil {
+		l += r.TargetName.Len()
+	}
+
+	return l
+}
+
+// RelativePath is a sequence of References and BrowseNames to follow from a starting Node.
+//
+// Specification: Part 4, 7.26
+type RelativePath struct {
+	ArraySize int32
+	Elements  []*RelativePathElement
+}
+
+// NewRelativePath creates a new RelativePath from multiple RelativePathElements.
+func NewRelativePath(elems ...*RelativePathElement) *RelativePath {
+	return &RelativePath{
+		ArraySize: int32(len(elems)),
+		Elements:  elems,
+	}
+}
+
+// ParseRelativePath parses the text format of RelativePath, e.g. "/2:Device.Temperature".
+//
+// Each element starts with "/" to follow the HierarchicalReferences or "." to follow
+// the Aggregates, both forward with their subtypes, and is followed by the BrowseName
+// of the target with an optional namespace index and a colon. The reserved characters
+// "/.<>:#!&" in the BrowseNames should be escaped by "&". The elements with the ReferenceType
+// in angle brackets are not supported as the BrowseName of it cannot be resolved here.
+//
+// Specification: Part 4, A.2
+func ParseRelativePath(s string) (*RelativePath, error) {
+	var elems []*RelativePathElement
+	for len(s) > 0 {
+		var refType uint16
+		switch s[0] {
+		case '/':
+			refType = id.HierarchicalReferences
+		case '.':
+			refType = id.Aggregates
+		case '<':
+			return nil, errors.NewErrUnsupported(s, "ReferenceType in RelativePath is not supported")
+		default:
+			return nil, errors.Errorf("relative path element should start with / or ., got %q", s)
+		}
+
+		name, rest, err := parseRelativePathName(s[1:])
+		if err != nil {
+			return nil, err
+		}
+		elems = append(elems, NewRelativePathElement(NewFourByteNodeID(0, refType), false, true, name))
+		s = rest
+	}
+
+	return NewRelativePath(elems...), nil
+}
+
+// parseRelativePathName parses the BrowseName at the beginning of s until the next element,
+// and returns it with the rest of s.
+func parseRelativePathName(s string) (*QualifiedName, string, error) {
+	var name strings.Builder
+	var ns uint16
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '&':
+			if i+1 >= len(s) {
+				return nil, "", errors.New("relative path should not end with &")
+			}
+			i++
+			name.WriteByte(s[i])
+		case '/', '.', '<':
+			return NewQualifiedName(ns, name.String()), s[i:], nil
+		case ':':
+			// the prefix should be the namespace index.
+			idx, err := strconv.ParseUint(name.String(), 10, 16)
+			if err != nil {
+				return nil, "", errors.Errorf("invalid namespace index in relative path: %q", name.String())
+			}
+			ns = uint16(idx)
+			name.Reset()
+		case '>', '#', '!':
+			return nil, "", errors.Errorf("reserved character %q should be escaped in relative path", c)
+		default:
+			name.WriteByte(c)
+		}
+	}
+
+	return NewQualifiedName(ns, name.String()), "", nil
+}
+
+// DecodeRelativePath decodes given bytes into RelativePath.
+func DecodeRelativePath(b []byte) (*RelativePath, error) {
+	r := &RelativePath{}
+	if err := r.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// DecodeFromBytes decodes given bytes into RelativePath.
+func (r *RelativePath) DecodeFromBytes(b []byte) error {
+	if len(b) < 4 {
+		return errors.NewErrTooShortToDecode(r, "should be longer than 4 bytes")
+	}
+	r.ArraySize = int32(binary.LittleEndian.Uint32(b[:4]))
+	if r.ArraySize <= 0 {
+		return nil
+	}
+
+	offset := 4
+	for i := 1; i <= int(r.ArraySize); i++ {
+		e, err := DecodeRelativePathElement(b[offset:])
+		if err != nil {
+			return err
+		}
+		r.Elements = append(r.Elements, e)
+		offset += e.Len()
+	}
+
+	return nil
+}
+
+// Serialize serializes RelativePath into bytes.
+func (r *RelativePath) Serialize() ([]byte, error) {
+	b := make([]byte, r.Len())
+	if err := r.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes RelativePath into bytes.
+func (r *RelativePath) SerializeTo(b []byte) error {
+	offset := 4
+	binary.LittleEndian.PutUint32(b[:4], uint32(r.ArraySize))
+
+	for _, e := range r.Elements {
+		if err := e.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += e.Len()
+	}
+
+	return nil
+}
+
+// Len returns the actual length of RelativePath in int.
+func (r *RelativePath) Len() int {
+	l := 4
+	for _, e := range r.Elements {
+		l += e.Len()
+	}
+
+	return l
+}
@@ -0,0 +1,95 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package datatypes
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/wmnsk/gopcua/utils/codectest"
+)
+
+func TestRelativePath(t *testing.T) {
+	cases := []codectest.Case{
+		{
+			Name:   "empty",
+			Struct: NewRelativePath(),
+			Bytes: []byte{
+				0x00, 0x00, 0x00, 0x00,
+			},
+		},
+		{
+			Name: "hierarchical and aggregates",
+			Struct: NewRelativePath(
+				NewRelativePathElement(NewFourByteNodeID(0, 33), false, true, NewQualifiedName(2, "foo")),
+				NewRelativePathElement(NewFourByteNodeID(0, 44), true, false, NewQualifiedName(0, "bar")),
+			),
+			Bytes: []byte{
+				// ArraySize
+				0x02, 0x00, 0x00, 0x00,
+				// ReferenceTypeID
+				0x01, 0x00, 0x21, 0x00,
+				// IsInverse
+				0x00,
+				// IncludeSubtypes
+				0x01,
+				// TargetName
+				0x02, 0x00, 0x03, 0x00, 0x00, 0x00, 0x66, 0x6f, 0x6f,
+				// ReferenceTypeID
+				0x01, 0x00, 0x2c, 0x00,
+				// IsInverse
+				0x01,
+				// IncludeSubtypes
+				0x00,
+				// TargetName
+				0x00, 0x00, 0x03, 0x00, 0x00, 0x00, 0x62, 0x61, 0x72,
+			},
+		},
+	}
+	codectest.Run(t, cases, func(b []byte) (codectest.S, error) {
+		return DecodeRelativePath(b)
+	})
+}
+
+func TestParseRelativePath(t *testing.T) {
+	hierarchical := func(ns uint16, name string) *RelativePathElement {
+		return NewRelativePathElement(NewFourByteNodeID(0, 33), false, true, NewQualifiedName(ns, name))
+	}
+	aggregates := func(ns uint16, name string) *RelativePathElement {
+		return NewRelativePathElement(NewFourByteNodeID(0, 44), false, true, NewQualifiedName(ns, name))
+	}
+
+	cases := []struct {
+		s    string
+		want *RelativePath
+	}{
+		{"", NewRelativePath()},
+		{"/Foo", NewRelativePath(hierarchical(0, "Foo"))},
+		{"/Foo/Bar", NewRelativePath(hierarchical(0, "Foo"), hierarchical(0, "Bar"))},
+		{"/2:Device.Temperature", NewRelativePath(hierarchical(2, "Device"), aggregates(0, "Temperature"))},
+		{"/1:a&/b&.c", NewRelativePath(hierarchical(1, "a/b.c"))},
+		{"/&1&:x", NewRelativePath(hierarchical(0, "1:x"))},
+	}
+	for _, c := range cases {
+		t.Run(c.s, func(t *testing.T) {
+			got, err := ParseRelativePath(c.s)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(got, c.want); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+
+	for _, s := range []string{"Foo", "/x:Foo", "/Foo&", "/Foo#", "<HasChild>Foo"} {
+		t.Run("invalid "+s, func(t *testing.T) {
+			if _, err := ParseRelativePath(s); err == nil {
+				t.Errorf("%q should not be parsed", s)
+			}
+		})
+	}
+}
@@ -0,0 +1,257 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package datatypes
+
+import (
+	"encoding/binary"
+	"strconv"
+	"strings"
+
+	"github.com/wmnsk/gopcua/errors"
+	"github.com/wmnsk/gopcua/id"
+)
+
+// SimpleAttributeOperand is a FilterOperand which selects an Attribute of the Node found
+// by following the BrowsePath of hierarchical References from the TypeDefinitionID.
+// It is used as the SelectClauses of EventFilter to select the fields of the Events.
+//
+// Specification: Part 4, 7.4.4.5
+type SimpleAttributeOperand struct {
+	TypeDefinitionID *NodeID
+	BrowsePath       []*QualifiedName
+	AttributeID      AttributeID
+	IndexRange       *String
+}
+
+// NewSimpleAttributeOperand creates a new SimpleAttributeOperand.
+func NewSimpleAttributeOperand(typeDef *NodeID, attrID AttributeID, path ...*QualifiedName) *SimpleAttributeOperand {
+	return &SimpleAttributeOperand{
+		TypeDefinitionID: typeDef,
+		BrowsePath:       path,
+		AttributeID:      attrID,
+		IndexRange:       NewString(""),
+	}
+}
+
+// NewEventFieldOperand creates a new SimpleAttributeOperand which selects the Value of the field
+// of BaseEventType with the BrowseNames in the namespace 0, e.g. "Message" or "EnabledState", "Id".
+func NewEventFieldOperand(names ...string) *SimpleAttributeOperand {
+	path := make([]*QualifiedName, len(names))
+	for i, name := range names {
+		path[i] = NewQualifiedName(0, name)
+	}
+	return NewSimpleAttributeOperand(NewFourByteNodeID(0, id.BaseEventType), AttributeIDValue, path...)
+}
+
+// DecodeSimpleAttributeOperand decodes given bytes into SimpleAttributeOperand.
+func DecodeSimpleAttributeOperand(b []byte) (*SimpleAttributeOperand, error) {
+	o := &SimpleAttributeOperand{}
+	if err := o.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return o, nil
+}
+
+// DecodeFromBytes decodes given bytes into SimpleAttributeOperand.
+func (o *SimpleAttributeOperand) DecodeFromBytes(b []byte) error {
+	o.TypeDefinitionID = &NodeID{}
+	if err := o.TypeDefinitionID.DecodeFromBytes(b); err != nil {
+		return err
+	}
+	offset := o.TypeDefinitionID.Len()
+
+	if len(b[offset:]) < 4 {
+		return errors.NewErrTooShortToDecode(o, "should contain BrowsePath")
+	}
+	n := int32(binary.LittleEndian.Uint32(b[offset : offset+4]))
+	offset += 4
+
+	o.BrowsePath = nil
+	for i := 0; i < int(n); i++ {
+		q, err := DecodeQualifiedName(b[offset:])
+		if err != nil {
+			return err
+		}
+		o.BrowsePath = append(o.BrowsePath, q)
+		offset += q.Len()
+	}
+
+	if len(b[offset:]) < 8 {
+		return errors.NewErrTooShortToDecode(o, "should contain AttributeID and IndexRange")
+	}
+	o.AttributeID = AttributeID(binary.LittleEndian.Uint32(b[offset : offset+4]))
+	offset += 4
+
+	o.IndexRange = &String{}
+	return o.IndexRange.DecodeFromBytes(b[offset:])
+}
+
+// Serialize serializes SimpleAttributeOperand into bytes.
+func (o *SimpleAttributeOperand) Serialize() ([]byte, error) {
+	b := make([]byte, o.Len())
+	if err := o.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes SimpleAttributeOperand into bytes.
+func (o *SimpleAttributeOperand) SerializeTo(b []byte) error {
+	offset := 0
+	if o.TypeDefinitionID != nil {
+		if err := o.TypeDefinitionID.SerializeTo(b); err != nil {
+			return err
+		}
+		offset += o.TypeDefinitionID.Len()
+	}
+
+	binary.LittleEndian.PutUint32(b[offset:offset+4], uint32(len(o.BrowsePath)))
+	offset += 4
+	for _, q := range o.BrowsePath {
+		if err := q.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += q.Len()
+	}
+
+	binary.LittleEndian.PutUint32(b[offset:offset+4], uint32(o.AttributeID))
+	offset += 4
+
+	if o.IndexRange != nil {
+		return o.IndexRange.SerializeTo(b[offset:])
+	}
+
+	return nil
+}
+
+// Len returns the actual length of SimpleAttributeOperand in int.
+func (o *SimpleAttributeOperand) Len() int {
+	l := 8
+	if o.TypeDefinitionID != nil {
+		l += o.TypeDefinitionID.Len()
+	}
+	for _, q := range o.BrowsePath {
+		l += q.Len()
+	}
+	if o.IndexRange != nil {
+		l += o.IndexRange.Len()
+	}
+
+	return l
+}
+
+// Type returns type of SimpleAttributeOperand defined in NodeIds.csv in int.
+func (o *SimpleAttributeOperand) Type() int {
+	return id.SimpleAttributeOperand_Encoding_DefaultBinary
+}
+
+// Path returns the BrowsePath in text, with the BrowseNames separated by "/" and prefixed
+// by the namespace index and a colon if it is not 0, e.g. "Message" or "EnabledState/Id".
+func (o *SimpleAttributeOperand) Path() string {
+	names := make([]string, len(o.BrowsePath))
+	for i, q := range o.BrowsePath {
+		var name string
+		if q.Name != nil {
+			name = q.Name.Get()
+		}
+		if q.NamespaceIndex != 0 {
+			name = strconv.Itoa(int(q.NamespaceIndex)) + ":" + name
+		}
+		names[i] = name
+	}
+	return strings.Join(names, "/")
+}
+
+// SimpleAttributeOperandArray represents an array of SimpleAttributeOperands.
+// It does not correspond to a certain type from the specification
+// but makes encoding and decoding easier.
+type SimpleAttributeOperandArray struct {
+	ArraySize int32
+	Operands  []*SimpleAttributeOperand
+}
+
+// NewSimpleAttributeOperandArray creates a new SimpleAttributeOperandArray from multiple SimpleAttributeOperands.
+func NewSimpleAttributeOperandArray(ops []*SimpleAttributeOperand) *SimpleAttributeOperandArray {
+	if ops == nil {
+		return &SimpleAttributeOperandArray{
+			ArraySize: 0,
+		}
+	}
+
+	return &SimpleAttributeOperandArray{
+		ArraySize: int32(len(ops)),
+		Operands:  ops,
+	}
+}
+
+// DecodeSimpleAttributeOperandArray decodes given bytes into SimpleAttributeOperandArray.
+func DecodeSimpleAttributeOperandArray(b []byte) (*SimpleAttributeOperandArray, error) {
+	a := &SimpleAttributeOperandArray{}
+	if err := a.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// DecodeFromBytes decodes given bytes into SimpleAttributeOperandArray.
+func (a *SimpleAttributeOperandArray) DecodeFromBytes(b []byte) error {
+	if len(b) < 4 {
+		return errors.NewErrTooShortToDecode(a, "should be longer than 4 bytes")
+	}
+	a.ArraySize = int32(binary.LittleEndian.Uint32(b[:4]))
+	if a.ArraySize <= 0 {
+		return nil
+	}
+
+	offset := 4
+	for i := 1; i <= int(a.ArraySize); i++ {
+		o, err := DecodeSimpleAttributeOperand(b[offset:])
+		if err != nil {
+			return err
+		}
+		a.Operands = append(a.Operands, o)
+		offset += o.Len()
+	}
+
+	return nil
+}
+
+// Serialize serializes SimpleAttributeOperandArray into bytes.
+func (a *SimpleAttributeOperandArray) Serialize() ([]byte, error) {
+	b := make([]byte, a.Len())
+	if err := a.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes SimpleAttributeOperandArray into bytes.
+func (a *SimpleAttributeOperandArray) SerializeTo(b []byte) error {
+	offset := 4
+	binary.LittleEndian.PutUint32(b[:4], uint32(a.ArraySize))
+
+	for _, o := range a.Operands {
+		if err := o.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += o.Len()
+	}
+
+	return nil
+}
+
+// Len returns the actual length in int.
+func (a *SimpleAttributeOperandArray) Len() int {
+	l := 4
+	for _, o := range a.Operands {
+		l += o.Len()
+	}
+
+	return l
+}
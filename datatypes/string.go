@@ -9,6 +9,7 @@ import (
 	"fmt"
 
 	"github.com/wmnsk/gopcua/errors"
+	"github.com/wmnsk/gopcua/id"
 )
 
 // String represents the String type in OPC UA Specifications. This consists of the four-byte length field and variable length of contents.
@@ -54,6 +55,9 @@ func (s *String) DecodeFromBytes(b []byte) error {
 	if s.Length <= 0 {
 		return nil
 	}
+	if len(b) < 4+int(s.Length) {
+		return errors.NewErrTooShortToDecode(s, "should be longer than the given Length")
+	}
 	s.Value = b[4 : 4+s.Length]
 	return nil
 }
@@ -73,6 +77,9 @@ func (s *String) SerializeTo(b []byte) error {
 	if len(b) < s.Len() {
 		return errors.NewErrInvalidLength(s, "bytes should be longer")
 	}
+	if s.Length >= 0 && int(s.Length) != len(s.Value) {
+		return errors.NewErrInvalidLength(s, "should match the length of Value")
+	}
 
 	binary.LittleEndian.PutUint32(b[:4], uint32(s.Length))
 	copy(b[4:s.Len()], s.Value)
@@ -101,6 +108,11 @@ func (s *String) String() string {
 	return fmt.Sprintf("%d, %s", s.Length, s.Get())
 }
 
+// DataType returns type of Data.
+func (s *String) DataType() uint16 {
+	return id.String
+}
+
 // StringArray represents the StringArray.
 type StringArray struct {
 	ArraySize int32
@@ -126,6 +138,19 @@ func NewStringArray(strs []string) *StringArray {
 	return s
 }
 
+// Get returns the values in Golang's built-in type string.
+func (s *StringArray) Get() []string {
+	if s == nil {
+		return nil
+	}
+
+	strs := make([]string, len(s.Strings))
+	for i, ss := range s.Strings {
+		strs[i] = ss.Get()
+	}
+	return strs
+}
+
 // DecodeStringArray decodes given bytes into StringArray.
 func DecodeStringArray(b []byte) (*StringArray, error) {
 	s := &StringArray{}
@@ -7,6 +7,8 @@ package datatypes
 import (
 	"testing"
 
+	"github.com/google/go-cmp/cmp"
+
 	"github.com/wmnsk/gopcua/utils/codectest"
 )
 
@@ -68,3 +70,17 @@ func TestStringArray(t *testing.T) {
 		return DecodeStringArray(b)
 	})
 }
+
+func TestStringArrayGet(t *testing.T) {
+	if diff := cmp.Diff(NewStringArray([]string{"foo", "bar"}).Get(), []string{"foo", "bar"}); diff != "" {
+		t.Error(diff)
+	}
+	if diff := cmp.Diff(NewStringArray(nil).Get(), []string{}); diff != "" {
+		t.Error(diff)
+	}
+
+	var s *StringArray
+	if got := s.Get(); got != nil {
+		t.Errorf("got %v want nil", got)
+	}
+}
@@ -0,0 +1,156 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package datatypes
+
+import (
+	"encoding/binary"
+
+	"github.com/wmnsk/gopcua/errors"
+)
+
+// SubscriptionAcknowledgement acknowledges the receipt of the NotificationMessage
+// which has SequenceNumber in the Subscription, so that the Server can delete it
+// from the retransmission queue.
+//
+// Specification: Part 4, 5.13.5.2
+type SubscriptionAcknowledgement struct {
+	SubscriptionID uint32
+	SequenceNumber uint32
+}
+
+// NewSubscriptionAcknowledgement creates a new SubscriptionAcknowledgement.
+func NewSubscriptionAcknowledgement(subID, seq uint32) *SubscriptionAcknowledgement {
+	return &SubscriptionAcknowledgement{
+		SubscriptionID: subID,
+		SequenceNumber: seq,
+	}
+}
+
+// DecodeSubscriptionAcknowledgement decodes given bytes into SubscriptionAcknowledgement.
+func DecodeSubscriptionAcknowledgement(b []byte) (*SubscriptionAcknowledgement, error) {
+	s := &SubscriptionAcknowledgement{}
+	if err := s.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// DecodeFromBytes decodes given bytes into SubscriptionAcknowledgement.
+func (s *SubscriptionAcknowledgement) DecodeFromBytes(b []byte) error {
+	if len(b) < 8 {
+		return errors.NewErrTooShortToDecode(s, "should be longer than 8 bytes")
+	}
+	s.SubscriptionID = binary.LittleEndian.Uint32(b[:4])
+	s.SequenceNumber = binary.LittleEndian.Uint32(b[4:8])
+	return nil
+}
+
+// Serialize serializes SubscriptionAcknowledgement into bytes.
+func (s *SubscriptionAcknowledgement) Serialize() ([]byte, error) {
+	b := make([]byte, s.Len())
+	if err := s.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes SubscriptionAcknowledgement into bytes.
+func (s *SubscriptionAcknowledgement) SerializeTo(b []byte) error {
+	binary.LittleEndian.PutUint32(b[:4], s.SubscriptionID)
+	binary.LittleEndian.PutUint32(b[4:8], s.SequenceNumber)
+	return nil
+}
+
+// Len returns the actual length of SubscriptionAcknowledgement in int.
+func (s *SubscriptionAcknowledgement) Len() int {
+	return 8
+}
+
+// SubscriptionAcknowledgementArray represents an array of SubscriptionAcknowledgements.
+// It does not correspond to a certain type from the specification
+// but makes encoding and decoding easier.
+type SubscriptionAcknowledgementArray struct {
+	ArraySize        int32
+	Acknowledgements []*SubscriptionAcknowledgement
+}
+
+// NewSubscriptionAcknowledgementArray creates a new SubscriptionAcknowledgementArray from multiple SubscriptionAcknowledgements.
+func NewSubscriptionAcknowledgementArray(acks []*SubscriptionAcknowledgement) *SubscriptionAcknowledgementArray {
+	if acks == nil {
+		return &SubscriptionAcknowledgementArray{
+			ArraySize: 0,
+		}
+	}
+
+	return &SubscriptionAcknowledgementArray{
+		ArraySize:        int32(len(acks)),
+		Acknowledgements: acks,
+	}
+}
+
+// DecodeSubscriptionAcknowledgementArray decodes given bytes into SubscriptionAcknowledgementArray.
+func DecodeSubscriptionAcknowledgementArray(b []byte) (*SubscriptionAcknowledgementArray, error) {
+	a := &SubscriptionAcknowledgementArray{}
+	if err := a.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// DecodeFromBytes decodes given bytes into SubscriptionAcknowledgementArray.
+func (a *SubscriptionAcknowledgementArray) DecodeFromBytes(b []byte) error {
+	if len(b) < 4 {
+		return errors.NewErrTooShortToDecode(a, "should be longer than 4 bytes")
+	}
+	a.ArraySize = int32(binary.LittleEndian.Uint32(b[:4]))
+	if a.ArraySize <= 0 {
+		return nil
+	}
+
+	offset := 4
+	for i := 1; i <= int(a.ArraySize); i++ {
+		s, err := DecodeSubscriptionAcknowledgement(b[offset:])
+		if err != nil {
+			return err
+		}
+		a.Acknowledgements = append(a.Acknowledgements, s)
+		offset += s.Len()
+	}
+
+	return nil
+}
+
+// Serialize serializes SubscriptionAcknowledgementArray into bytes.
+func (a *SubscriptionAcknowledgementArray) Serialize() ([]byte, error) {
+	b := make([]byte, a.Len())
+	if err := a.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes SubscriptionAcknowledgementArray into bytes.
+func (a *SubscriptionAcknowledgementArray) SerializeTo(b []byte) error {
+	offset := 4
+	binary.LittleEndian.PutUint32(b[:4], uint32(a.ArraySize))
+
+	for _, s := range a.Acknowledgements {
+		if err := s.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += s.Len()
+	}
+
+	return nil
+}
+
+// Len returns the actual length in int.
+func (a *SubscriptionAcknowledgementArray) Len() int {
+	return 4 + 8*len(a.Acknowledgements)
+}
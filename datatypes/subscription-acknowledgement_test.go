@@ -0,0 +1,56 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package datatypes
+
+import (
+	"testing"
+
+	"github.com/wmnsk/gopcua/utils/codectest"
+)
+
+func TestSubscriptionAcknowledgement(t *testing.T) {
+	cases := []codectest.Case{
+		{
+			Name:   "normal",
+			Struct: NewSubscriptionAcknowledgement(1, 2),
+			Bytes: []byte{
+				// SubscriptionID
+				0x01, 0x00, 0x00, 0x00,
+				// SequenceNumber
+				0x02, 0x00, 0x00, 0x00,
+			},
+		},
+	}
+	codectest.Run(t, cases, func(b []byte) (codectest.S, error) {
+		return DecodeSubscriptionAcknowledgement(b)
+	})
+}
+
+func TestSubscriptionAcknowledgementArray(t *testing.T) {
+	cases := []codectest.Case{
+		{
+			Name: "normal",
+			Struct: NewSubscriptionAcknowledgementArray([]*SubscriptionAcknowledgement{
+				NewSubscriptionAcknowledgement(1, 2),
+				NewSubscriptionAcknowledgement(1, 3),
+			}),
+			Bytes: []byte{
+				// ArraySize
+				0x02, 0x00, 0x00, 0x00,
+				// SubscriptionID, SequenceNumber
+				0x01, 0x00, 0x00, 0x00, 0x02, 0x00, 0x00, 0x00,
+				0x01, 0x00, 0x00, 0x00, 0x03, 0x00, 0x00, 0x00,
+			},
+		},
+		{
+			Name:   "empty",
+			Struct: NewSubscriptionAcknowledgementArray(nil),
+			Bytes:  []byte{0x00, 0x00, 0x00, 0x00},
+		},
+	}
+	codectest.Run(t, cases, func(b []byte) (codectest.S, error) {
+		return DecodeSubscriptionAcknowledgementArray(b)
+	})
+}
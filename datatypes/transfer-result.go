@@ -0,0 +1,172 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package datatypes
+
+import (
+	"encoding/binary"
+
+	"github.com/wmnsk/gopcua/errors"
+)
+
+// TransferResult is the result of TransferSubscriptions for a single Subscription.
+//
+// AvailableSequenceNumbers are the sequence numbers of the NotificationMessages
+// which are available for retransmission in the Subscription transferred.
+//
+// Specification: Part 4, 5.13.7.2
+type TransferResult struct {
+	StatusCode               uint32
+	AvailableSequenceNumbers *Uint32Array
+}
+
+// NewTransferResult creates a new TransferResult.
+func NewTransferResult(code uint32, seqs ...uint32) *TransferResult {
+	return &TransferResult{
+		StatusCode:               code,
+		AvailableSequenceNumbers: NewUint32Array(seqs),
+	}
+}
+
+// DecodeTransferResult decodes given bytes into TransferResult.
+func DecodeTransferResult(b []byte) (*TransferResult, error) {
+	r := &TransferResult{}
+	if err := r.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// DecodeFromBytes decodes given bytes into TransferResult.
+func (r *TransferResult) DecodeFromBytes(b []byte) error {
+	if len(b) < 4 {
+		return errors.NewErrTooShortToDecode(r, "should be longer than 4 bytes")
+	}
+	r.StatusCode = binary.LittleEndian.Uint32(b[:4])
+
+	r.AvailableSequenceNumbers = &Uint32Array{}
+	return r.AvailableSequenceNumbers.DecodeFromBytes(b[4:])
+}
+
+// Serialize serializes TransferResult into bytes.
+func (r *TransferResult) Serialize() ([]byte, error) {
+	b := make([]byte, r.Len())
+	if err := r.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes TransferResult into bytes.
+func (r *TransferResult) SerializeTo(b []byte) error {
+	binary.LittleEndian.PutUint32(b[:4], r.StatusCode)
+
+	if r.AvailableSequenceNumbers != nil {
+		return r.AvailableSequenceNumbers.SerializeTo(b[4:])
+	}
+
+	return nil
+}
+
+// Len returns the actual length of TransferResult in int.
+func (r *TransferResult) Len() int {
+	l := 4
+	if r.AvailableSequenceNumbers != nil {
+		l += r.AvailableSequenceNumbers.Len()
+	}
+
+	return l
+}
+
+// TransferResultArray represents an array of TransferResults.
+// It does not correspond to a certain type from the specification
+// but makes encoding and decoding easier.
+type TransferResultArray struct {
+	ArraySize       int32
+	TransferResults []*TransferResult
+}
+
+// NewTransferResultArray creates a new TransferResultArray from multiple TransferResults.
+func NewTransferResultArray(results []*TransferResult) *TransferResultArray {
+	if results == nil {
+		return &TransferResultArray{
+			ArraySize: 0,
+		}
+	}
+
+	return &TransferResultArray{
+		ArraySize:       int32(len(results)),
+		TransferResults: results,
+	}
+}
+
+// DecodeTransferResultArray decodes given bytes into TransferResultArray.
+func DecodeTransferResultArray(b []byte) (*TransferResultArray, error) {
+	a := &TransferResultArray{}
+	if err := a.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// DecodeFromBytes decodes given bytes into TransferResultArray.
+func (a *TransferResultArray) DecodeFromBytes(b []byte) error {
+	if len(b) < 4 {
+		return errors.NewErrTooShortToDecode(a, "should be longer than 4 bytes")
+	}
+	a.ArraySize = int32(binary.LittleEndian.Uint32(b[:4]))
+	if a.ArraySize <= 0 {
+		return nil
+	}
+
+	offset := 4
+	for i := 1; i <= int(a.ArraySize); i++ {
+		r, err := DecodeTransferResult(b[offset:])
+		if err != nil {
+			return err
+		}
+		a.TransferResults = append(a.TransferResults, r)
+		offset += r.Len()
+	}
+
+	return nil
+}
+
+// Serialize serializes TransferResultArray into bytes.
+func (a *TransferResultArray) Serialize() ([]byte, error) {
+	b := make([]byte, a.Len())
+	if err := a.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes TransferResultArray into bytes.
+func (a *TransferResultArray) SerializeTo(b []byte) error {
+	offset := 4
+	binary.LittleEndian.PutUint32(b[:4], uint32(a.ArraySize))
+
+	for _, r := range a.TransferResults {
+		if err := r.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += r.Len()
+	}
+
+	return nil
+}
+
+// Len returns the actual length in int.
+func (a *TransferResultArray) Len() int {
+	l := 4
+	for _, r := range a.TransferResults {
+		l += r.Len()
+	}
+
+	return l
+}
@@ -0,0 +1,67 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package datatypes
+
+import (
+	"testing"
+
+	"github.com/wmnsk/gopcua/utils/codectest"
+)
+
+func TestTransferResult(t *testing.T) {
+	cases := []codectest.Case{
+		{
+			Name:   "normal",
+			Struct: NewTransferResult(0, 1, 2),
+			Bytes: []byte{
+				// StatusCode
+				0x00, 0x00, 0x00, 0x00,
+				// AvailableSequenceNumbers
+				0x02, 0x00, 0x00, 0x00,
+				0x01, 0x00, 0x00, 0x00, 0x02, 0x00, 0x00, 0x00,
+			},
+		},
+		{
+			Name:   "bad",
+			Struct: NewTransferResult(0x80280000),
+			Bytes: []byte{
+				// StatusCode
+				0x00, 0x00, 0x28, 0x80,
+				// AvailableSequenceNumbers
+				0x00, 0x00, 0x00, 0x00,
+			},
+		},
+	}
+	codectest.Run(t, cases, func(b []byte) (codectest.S, error) {
+		return DecodeTransferResult(b)
+	})
+}
+
+func TestTransferResultArray(t *testing.T) {
+	cases := []codectest.Case{
+		{
+			Name: "normal",
+			Struct: NewTransferResultArray([]*TransferResult{
+				NewTransferResult(0, 1),
+				NewTransferResult(0x80280000),
+			}),
+			Bytes: []byte{
+				// ArraySize
+				0x02, 0x00, 0x00, 0x00,
+				// StatusCode, AvailableSequenceNumbers
+				0x00, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x28, 0x80, 0x00, 0x00, 0x00, 0x00,
+			},
+		},
+		{
+			Name:   "empty",
+			Struct: NewTransferResultArray(nil),
+			Bytes:  []byte{0x00, 0x00, 0x00, 0x00},
+		},
+	}
+	codectest.Run(t, cases, func(b []byte) (codectest.S, error) {
+		return DecodeTransferResultArray(b)
+	})
+}
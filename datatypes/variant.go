@@ -5,17 +5,47 @@
 package datatypes
 
 import (
+	"encoding/binary"
+	"fmt"
+	"reflect"
+	"time"
+
 	"github.com/wmnsk/gopcua/errors"
 	"github.com/wmnsk/gopcua/id"
 )
 
+// Variant EncodingMask flags.
+//
+// Specification: Part 6, 5.2.2.16
+const (
+	VariantArrayDimensionsFlag = 0x40
+	VariantArrayValuesFlag     = 0x80
+)
+
+// maxNestingDepth is the maximum depth of the DataValues, the DiagnosticInfos and the
+// ExtensionObjects nested in the Variants to be decoded, to prevent the stack from being
+// exhausted by a malicious message. The messages in practice are never nested this deep.
+const maxNestingDepth = 100
+
+// nestedDecoder is implemented by the values which may contain the Variants, to be decoded
+// with the depth they are nested to.
+type nestedDecoder interface {
+	decode(b []byte, depth int) error
+}
+
 // Variant is a union of the built-in types.
 //
+// A scalar value is stored in Value. If the array values flag is set in
+// EncodingMask, the elements of the array are stored in Values instead.
+// Multi-dimensional arrays are stored in row-major order with the length
+// of each dimension in ArrayDimensions.
+//
 // Specification: Part 6, 5.2.2.16
 type Variant struct {
 	EncodingMask          uint8
 	ArrayLength           *int32
 	Value                 Data
+	Values                []Data
 	ArrayDimensionsLength *int32
 	ArrayDimensions       []*int32
 }
@@ -29,6 +59,105 @@ func NewVariant(data Data) *Variant {
 	return v
 }
 
+// NewArrayVariant creates a new Variant with a one-dimensional array
+// of values of the given built-in type.
+func NewArrayVariant(typ uint16, values ...Data) *Variant {
+	l := int32(len(values))
+	return &Variant{
+		EncodingMask: uint8(typ) | VariantArrayValuesFlag,
+		ArrayLength:  &l,
+		Values:       values,
+	}
+}
+
+// NewMultiDimensionalArrayVariant creates a new Variant with a multi-dimensional
+// array of values of the given built-in type. The values are given in row-major
+// order, i.e. the last dimension varies fastest.
+//
+// The number of the values should be the product of dims, otherwise the Variant
+// fails to be serialized. Use SetDimensions to validate them beforehand.
+func NewMultiDimensionalArrayVariant(typ uint16, dims []int32, values ...Data) *Variant {
+	v := NewArrayVariant(typ, values...)
+	v.setDimensions(dims)
+	return v
+}
+
+// NewVariantFrom creates a new Variant from the Go value v, inferring the built-in type.
+//
+// The Go types are mapped as follows, and the slices of them are stored as the arrays:
+//
+//	bool       Boolean
+//	int32      Int32
+//	float32    Float
+//	float64    Double
+//	string     String
+//	time.Time  DateTime
+//	[]byte     ByteString
+//
+// The values of the built-in types in this package, e.g. *NodeID and *LocalizedText,
+// are stored as they are. An error is returned for the other types.
+func NewVariantFrom(v interface{}) (*Variant, error) {
+	if d, err := newVariantData(v); err == nil {
+		return NewVariant(d), nil
+	}
+
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice {
+		return nil, errors.NewErrUnsupported(v, "cannot be stored in Variant")
+	}
+
+	// the type of the elements is inferred from the zero value, so that empty slices are also typed.
+	elem := rv.Type().Elem()
+	zero := reflect.Zero(elem)
+	if elem.Kind() == reflect.Ptr {
+		zero = reflect.New(elem.Elem())
+	}
+	typ, err := newVariantData(zero.Interface())
+	if err != nil {
+		return nil, errors.NewErrUnsupported(v, "elements cannot be stored in Variant")
+	}
+
+	var values []Data
+	for i := 0; i < rv.Len(); i++ {
+		d, err := newVariantData(rv.Index(i).Interface())
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, d)
+	}
+	return NewArrayVariant(typ.DataType(), values...), nil
+}
+
+// newVariantData returns the Data of the built-in type for the Go value v.
+func newVariantData(v interface{}) (Data, error) {
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Ptr && rv.IsNil() {
+		return nil, errors.NewErrUnsupported(v, "nil cannot be stored in Variant")
+	}
+
+	switch x := v.(type) {
+	case bool:
+		return NewBoolean(x), nil
+	case int32:
+		return NewInt32(x), nil
+	case float32:
+		return NewFloat(x), nil
+	case float64:
+		return NewDouble(x), nil
+	case string:
+		return NewString(x), nil
+	case time.Time:
+		return NewDateTime(x), nil
+	case []byte:
+		return NewByteString(x), nil
+	case *Boolean, *Int32, *Float, *Double, *String, *DateTime, *ByteString,
+		*NodeID, *ExpandedNodeID, *QualifiedName, *LocalizedText, *ExtensionObject,
+		*DataValue, *DiagnosticInfo:
+		return x.(Data), nil
+	default:
+		return nil, errors.NewErrUnsupported(v, "cannot be stored in Variant")
+	}
+}
+
 // DecodeVariant decodes given bytes into Variant.
 func DecodeVariant(b []byte) (*Variant, error) {
 	v := &Variant{}
@@ -39,24 +168,126 @@ func DecodeVariant(b []byte) (*Variant, error) {
 }
 
 // DecodeFromBytes decodes given bytes into Variant.
+//
+// The DataValues, the DiagnosticInfos and the ExtensionObjects in the Variant are decoded
+// recursively, and an error is returned if they are nested too deeply.
 func (v *Variant) DecodeFromBytes(b []byte) error {
+	return v.decode(b, 0)
+}
+
+// decode decodes given bytes into Variant nested in the DataValues or the ExtensionObjects to depth.
+func (v *Variant) decode(b []byte, depth int) error {
+	if depth > maxNestingDepth {
+		return errors.NewErrUnsupported(v, fmt.Sprintf("should not be nested deeper than %d", maxNestingDepth))
+	}
+	if len(b) < 1 {
+		return errors.NewErrTooShortToDecode(v, "should be longer than 1 byte")
+	}
 	v.EncodingMask = b[0]
 
-	switch v.EncodingMask {
+	if !v.HasArrayValues() {
+		val, err := newVariantValue(v.Type())
+		if err != nil {
+			return err
+		}
+		if err := decodeVariantValue(val, b[1:], depth); err != nil {
+			return err
+		}
+		v.Value = val
+		return nil
+	}
+
+	offset := 1
+	if len(b[offset:]) < 4 {
+		return errors.NewErrTooShortToDecode(v, "should contain ArrayLength")
+	}
+	l := int32(binary.LittleEndian.Uint32(b[offset : offset+4]))
+	v.ArrayLength = &l
+	offset += 4
+
+	for i := int32(0); i < l; i++ {
+		// each of the values takes at least 1 byte, which also bounds the ArrayLength.
+		if len(b[offset:]) == 0 {
+			return errors.NewErrTooShortToDecode(v, "should contain ArrayValues")
+		}
+		val, err := newVariantValue(v.Type())
+		if err != nil {
+			return err
+		}
+		if err := decodeVariantValue(val, b[offset:], depth); err != nil {
+			return err
+		}
+		v.Values = append(v.Values, val)
+		offset += val.Len()
+	}
+
+	if !v.HasArrayDimensions() {
+		return nil
+	}
+
+	if len(b[offset:]) < 4 {
+		return errors.NewErrTooShortToDecode(v, "should contain ArrayDimensionsLength")
+	}
+	dl := int32(binary.LittleEndian.Uint32(b[offset : offset+4]))
+	v.ArrayDimensionsLength = &dl
+	offset += 4
+
+	for i := int32(0); i < dl; i++ {
+		if len(b[offset:]) < 4 {
+			return errors.NewErrTooShortToDecode(v, "should contain ArrayDimensions")
+		}
+		d := int32(binary.LittleEndian.Uint32(b[offset : offset+4]))
+		v.ArrayDimensions = append(v.ArrayDimensions, &d)
+		offset += 4
+	}
+
+	return checkDimensions(v, v.Dimensions(), len(v.Values))
+}
+
+// newVariantValue returns an empty value of the given built-in type.
+func newVariantValue(typ uint8) (Data, error) {
+	switch typ {
 	case id.Boolean:
-		v.Value = &Boolean{}
+		return &Boolean{}, nil
+	case id.QualifiedName:
+		return &QualifiedName{}, nil
 	case id.LocalizedText:
-		v.Value = &LocalizedText{}
+		return &LocalizedText{}, nil
+	case id.Int32:
+		return &Int32{}, nil
 	case id.Float:
-		v.Value = &Float{}
+		return &Float{}, nil
+	case id.Double:
+		return &Double{}, nil
+	case id.String:
+		return &String{}, nil
+	case id.DateTime:
+		return &DateTime{}, nil
+	case id.ByteString:
+		return &ByteString{}, nil
+	case id.NodeId:
+		return &NodeID{}, nil
+	case id.ExpandedNodeId:
+		return &ExpandedNodeID{}, nil
+	case id.Structure:
+		// the body is decoded into the ExtensionObjectValue of its TypeID if known.
+		return &ExtensionObject{}, nil
+	case id.DataValue:
+		return &DataValue{}, nil
+	case id.DiagnosticInfo:
+		return &DiagnosticInfo{}, nil
 	default:
-		return errors.NewErrInvalidType(v.EncodingMask, "decode", "got undefined type")
+		return nil, errors.NewErrInvalidType(typ, "decode", "got undefined type")
 	}
+}
 
-	if err := v.Value.DecodeFromBytes(b[1:]); err != nil {
-		return err
+// decodeVariantValue decodes given bytes into val in the Variant nested to depth.
+// The DataValues, the DiagnosticInfos and the ExtensionObjects are one level deeper than the Variant.
+func decodeVariantValue(val Data, b []byte, depth int) error {
+	if x, ok := val.(nestedDecoder); ok {
+		return x.decode(b, depth+1)
 	}
-	return nil
+	return val.DecodeFromBytes(b)
 }
 
 // Serialize serializes Variant into bytes.
@@ -81,6 +312,29 @@ func (v *Variant) SerializeTo(b []byte) error {
 		offset += v.Value.Len()
 	}
 
+	if v.HasArrayValues() {
+		binary.LittleEndian.PutUint32(b[offset:offset+4], uint32(v.arrayLength()))
+		offset += 4
+		for _, val := range v.Values {
+			if err := val.SerializeTo(b[offset:]); err != nil {
+				return err
+			}
+			offset += val.Len()
+		}
+	}
+
+	if v.HasArrayDimensions() {
+		if err := checkDimensions(v, v.Dimensions(), len(v.Values)); err != nil {
+			return err
+		}
+		binary.LittleEndian.PutUint32(b[offset:offset+4], uint32(len(v.ArrayDimensions)))
+		offset += 4
+		for _, d := range v.ArrayDimensions {
+			binary.LittleEndian.PutUint32(b[offset:offset+4], uint32(*d))
+			offset += 4
+		}
+	}
+
 	return nil
 }
 
@@ -92,5 +346,458 @@ func (v *Variant) Len() int {
 		length += v.Value.Len()
 	}
 
+	if v.HasArrayValues() {
+		length += 4
+		for _, val := range v.Values {
+			length += val.Len()
+		}
+	}
+
+	if v.HasArrayDimensions() {
+		length += 4 + 4*len(v.ArrayDimensions)
+	}
+
 	return length
 }
+
+// Type returns the built-in type of the Variant value.
+func (v *Variant) Type() uint8 {
+	return v.EncodingMask & 0x3f
+}
+
+// HasArrayValues checks if a Variant has the array values flag.
+func (v *Variant) HasArrayValues() bool {
+	return v.EncodingMask&VariantArrayValuesFlag == VariantArrayValuesFlag
+}
+
+// HasArrayDimensions checks if a Variant has the array dimensions flag.
+func (v *Variant) HasArrayDimensions() bool {
+	return v.EncodingMask&VariantArrayDimensionsFlag == VariantArrayDimensionsFlag
+}
+
+// Dimensions returns the length of each dimension of the array in the Variant,
+// the highest rank first. A one-dimensional array without ArrayDimensions has
+// the single dimension of its length, and nil is returned for a scalar.
+//
+// The method is not named ArrayDimensions as it is the field holding the encoded ones.
+func (v *Variant) Dimensions() []int32 {
+	if !v.HasArrayValues() {
+		return nil
+	}
+	if !v.HasArrayDimensions() {
+		return []int32{int32(len(v.Values))}
+	}
+
+	dims := make([]int32, len(v.ArrayDimensions))
+	for i, d := range v.ArrayDimensions {
+		if d != nil {
+			dims[i] = *d
+		}
+	}
+	return dims
+}
+
+// SetDimensions sets the dimensions of the array in the Variant, which are encoded
+// with the array dimensions flag. An error is returned if the number of the values
+// is not the product of dims.
+func (v *Variant) SetDimensions(dims ...int32) error {
+	if !v.HasArrayValues() {
+		return errors.NewErrInvalidType(v, "set dimensions", "should be an array")
+	}
+	if err := checkDimensions(v, dims, len(v.Values)); err != nil {
+		return err
+	}
+	v.setDimensions(dims)
+	return nil
+}
+
+func (v *Variant) setDimensions(dims []int32) {
+	v.EncodingMask |= VariantArrayDimensionsFlag
+
+	l := int32(len(dims))
+	v.ArrayDimensionsLength = &l
+	v.ArrayDimensions = nil
+	for i := range dims {
+		d := dims[i]
+		v.ArrayDimensions = append(v.ArrayDimensions, &d)
+	}
+}
+
+// Reshape returns the values of the array in the Variant as nested slices of Data
+// by Dimensions, e.g. [][]Data for a matrix, where the first index is the highest
+// rank. An error is returned if the Variant is not an array or the number of the
+// values is not the product of the dimensions.
+func (v *Variant) Reshape() (interface{}, error) {
+	if !v.HasArrayValues() {
+		return nil, errors.NewErrInvalidType(v, "reshape", "should be an array")
+	}
+	dims := v.Dimensions()
+	if err := checkDimensions(v, dims, len(v.Values)); err != nil {
+		return nil, err
+	}
+	return reshape(dims, v.Values).Interface(), nil
+}
+
+// reshape returns the values split by dims as nested slices of Data.
+func reshape(dims []int32, values []Data) reflect.Value {
+	if len(dims) <= 1 {
+		return reflect.ValueOf(append([]Data{}, values...))
+	}
+
+	typ := reflect.TypeOf([]Data(nil))
+	for range dims[1:] {
+		typ = reflect.SliceOf(typ)
+	}
+	s := reflect.MakeSlice(typ, int(dims[0]), int(dims[0]))
+	if dims[0] == 0 {
+		return s
+	}
+	n := len(values) / int(dims[0])
+	for i := 0; i < int(dims[0]); i++ {
+		s.Index(i).Set(reshape(dims[1:], values[i*n:(i+1)*n]))
+	}
+	return s
+}
+
+// checkDimensions returns an error if dims are negative or their product is not n.
+func checkDimensions(v *Variant, dims []int32, n int) error {
+	// the product is not accumulated beyond n, so that it does not overflow.
+	total, empty := int64(1), false
+	for _, d := range dims {
+		if d < 0 {
+			return errors.NewErrInvalidLength(v, "ArrayDimensions should not be negative")
+		}
+		if d == 0 {
+			empty = true
+		}
+		if total <= int64(n) {
+			total *= int64(d)
+		}
+	}
+	if empty {
+		total = 0
+	}
+	if total != int64(n) {
+		return errors.NewErrInvalidLength(v, fmt.Sprintf("got %d values for ArrayDimensions %v", n, dims))
+	}
+	return nil
+}
+
+// Bool returns the value of a scalar Boolean Variant.
+// The second return value is false if the Variant holds any other value.
+func (v *Variant) Bool() (bool, bool) {
+	b, ok := v.Value.(*Boolean)
+	if !ok || v.HasArrayValues() {
+		return false, false
+	}
+	return b.Value != 0x00, true
+}
+
+// Int32 returns the value of a scalar Int32 Variant.
+// The second return value is false if the Variant holds any other value.
+func (v *Variant) Int32() (int32, bool) {
+	i, ok := v.Value.(*Int32)
+	if !ok || v.HasArrayValues() {
+		return 0, false
+	}
+	return i.Value, true
+}
+
+// Float returns the value of a scalar Float Variant.
+// The second return value is false if the Variant holds any other value.
+func (v *Variant) Float() (float32, bool) {
+	f, ok := v.Value.(*Float)
+	if !ok || v.HasArrayValues() {
+		return 0, false
+	}
+	return f.Value, true
+}
+
+// Double returns the value of a scalar Double Variant.
+// The second return value is false if the Variant holds any other value.
+func (v *Variant) Double() (float64, bool) {
+	d, ok := v.Value.(*Double)
+	if !ok || v.HasArrayValues() {
+		return 0, false
+	}
+	return d.Value, true
+}
+
+// DateTime returns the value of a scalar DateTime Variant.
+// The second return value is false if the Variant holds any other value.
+func (v *Variant) DateTime() (time.Time, bool) {
+	d, ok := v.Value.(*DateTime)
+	if !ok || v.HasArrayValues() {
+		return time.Time{}, false
+	}
+	return d.Value, true
+}
+
+// ByteString returns the value of a scalar ByteString Variant, which is nil if it is null.
+// The second return value is false if the Variant holds any other value.
+func (v *Variant) ByteString() ([]byte, bool) {
+	b, ok := v.Value.(*ByteString)
+	if !ok || v.HasArrayValues() {
+		return nil, false
+	}
+	return b.Get(), true
+}
+
+// LocalizedText returns the value of a scalar LocalizedText Variant.
+// The second return value is false if the Variant holds any other value.
+func (v *Variant) LocalizedText() (*LocalizedText, bool) {
+	l, ok := v.Value.(*LocalizedText)
+	if !ok || v.HasArrayValues() {
+		return nil, false
+	}
+	return l, true
+}
+
+// QualifiedName returns the value of a scalar QualifiedName Variant.
+// The second return value is false if the Variant holds any other value.
+func (v *Variant) QualifiedName() (*QualifiedName, bool) {
+	q, ok := v.Value.(*QualifiedName)
+	if !ok || v.HasArrayValues() {
+		return nil, false
+	}
+	return q, true
+}
+
+// NodeID returns the value of a scalar NodeID Variant.
+// The second return value is false if the Variant holds any other value.
+func (v *Variant) NodeID() (*NodeID, bool) {
+	n, ok := v.Value.(*NodeID)
+	if !ok || v.HasArrayValues() {
+		return nil, false
+	}
+	return n, true
+}
+
+// ExpandedNodeID returns the value of a scalar ExpandedNodeID Variant.
+// The second return value is false if the Variant holds any other value.
+func (v *Variant) ExpandedNodeID() (*ExpandedNodeID, bool) {
+	e, ok := v.Value.(*ExpandedNodeID)
+	if !ok || v.HasArrayValues() {
+		return nil, false
+	}
+	return e, true
+}
+
+// ExtensionObject returns the value of a scalar ExtensionObject Variant.
+// The second return value is false if the Variant holds any other value.
+func (v *Variant) ExtensionObject() (*ExtensionObject, bool) {
+	e, ok := v.Value.(*ExtensionObject)
+	if !ok || v.HasArrayValues() {
+		return nil, false
+	}
+	return e, true
+}
+
+// DataValue returns the value of a scalar DataValue Variant.
+// The second return value is false if the Variant holds any other value.
+func (v *Variant) DataValue() (*DataValue, bool) {
+	d, ok := v.Value.(*DataValue)
+	if !ok || v.HasArrayValues() {
+		return nil, false
+	}
+	return d, true
+}
+
+// DiagnosticInfo returns the value of a scalar DiagnosticInfo Variant.
+// The second return value is false if the Variant holds any other value.
+func (v *Variant) DiagnosticInfo() (*DiagnosticInfo, bool) {
+	d, ok := v.Value.(*DiagnosticInfo)
+	if !ok || v.HasArrayValues() {
+		return nil, false
+	}
+	return d, true
+}
+
+// NodeIDSlice returns the values of a NodeID array Variant.
+// The second return value is false if the Variant is not an array of NodeID.
+func (v *Variant) NodeIDSlice() ([]*NodeID, bool) {
+	if !v.HasArrayValues() || v.Type() != id.NodeId {
+		return nil, false
+	}
+
+	s := make([]*NodeID, len(v.Values))
+	for i, val := range v.Values {
+		n, ok := val.(*NodeID)
+		if !ok {
+			return nil, false
+		}
+		s[i] = n
+	}
+	return s, true
+}
+
+// ExpandedNodeIDSlice returns the values of an ExpandedNodeID array Variant.
+// The second return value is false if the Variant is not an array of ExpandedNodeID.
+func (v *Variant) ExpandedNodeIDSlice() ([]*ExpandedNodeID, bool) {
+	if !v.HasArrayValues() || v.Type() != id.ExpandedNodeId {
+		return nil, false
+	}
+
+	s := make([]*ExpandedNodeID, len(v.Values))
+	for i, val := range v.Values {
+		e, ok := val.(*ExpandedNodeID)
+		if !ok {
+			return nil, false
+		}
+		s[i] = e
+	}
+	return s, true
+}
+
+// QualifiedNameSlice returns the values of a QualifiedName array Variant.
+// The second return value is false if the Variant is not an array of QualifiedName.
+func (v *Variant) QualifiedNameSlice() ([]*QualifiedName, bool) {
+	if !v.HasArrayValues() || v.Type() != id.QualifiedName {
+		return nil, false
+	}
+
+	s := make([]*QualifiedName, len(v.Values))
+	for i, val := range v.Values {
+		q, ok := val.(*QualifiedName)
+		if !ok {
+			return nil, false
+		}
+		s[i] = q
+	}
+	return s, true
+}
+
+// LocalizedTextSlice returns the values of a LocalizedText array Variant.
+// The second return value is false if the Variant is not an array of LocalizedText.
+func (v *Variant) LocalizedTextSlice() ([]*LocalizedText, bool) {
+	if !v.HasArrayValues() || v.Type() != id.LocalizedText {
+		return nil, false
+	}
+
+	s := make([]*LocalizedText, len(v.Values))
+	for i, val := range v.Values {
+		l, ok := val.(*LocalizedText)
+		if !ok {
+			return nil, false
+		}
+		s[i] = l
+	}
+	return s, true
+}
+
+// ExtensionObjectSlice returns the values of an ExtensionObject array Variant.
+// The second return value is false if the Variant is not an array of ExtensionObject.
+func (v *Variant) ExtensionObjectSlice() ([]*ExtensionObject, bool) {
+	if !v.HasArrayValues() || v.Type() != id.Structure {
+		return nil, false
+	}
+
+	s := make([]*ExtensionObject, len(v.Values))
+	for i, val := range v.Values {
+		e, ok := val.(*ExtensionObject)
+		if !ok {
+			return nil, false
+		}
+		s[i] = e
+	}
+	return s, true
+}
+
+// arrayLength returns the number of array elements to serialize.
+// A null array with no elements keeps its length of -1.
+func (v *Variant) arrayLength() int32 {
+	if len(v.Values) == 0 && v.ArrayLength != nil && *v.ArrayLength < 0 {
+		return *v.ArrayLength
+	}
+	return int32(len(v.Values))
+}
+
+// VariantArray represents an array of Variants, e.g. the arguments of a Method.
+// It does not correspond to a certain type from the specification
+// but makes encoding and decoding easier.
+type VariantArray struct {
+	ArraySize int32
+	Variants  []*Variant
+}
+
+// NewVariantArray creates a new VariantArray from multiple Variants.
+func NewVariantArray(vs []*Variant) *VariantArray {
+	if vs == nil {
+		return &VariantArray{
+			ArraySize: 0,
+		}
+	}
+
+	return &VariantArray{
+		ArraySize: int32(len(vs)),
+		Variants:  vs,
+	}
+}
+
+// DecodeVariantArray decodes given bytes into VariantArray.
+func DecodeVariantArray(b []byte) (*VariantArray, error) {
+	a := &VariantArray{}
+	if err := a.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// DecodeFromBytes decodes given bytes into VariantArray.
+func (a *VariantArray) DecodeFromBytes(b []byte) error {
+	if len(b) < 4 {
+		return errors.NewErrTooShortToDecode(a, "should be longer than 4 bytes")
+	}
+	a.ArraySize = int32(binary.LittleEndian.Uint32(b[:4]))
+	if a.ArraySize <= 0 {
+		return nil
+	}
+
+	offset := 4
+	for i := 1; i <= int(a.ArraySize); i++ {
+		v, err := DecodeVariant(b[offset:])
+		if err != nil {
+			return err
+		}
+		a.Variants = append(a.Variants, v)
+		offset += v.Len()
+	}
+
+	return nil
+}
+
+// Serialize serializes VariantArray into bytes.
+func (a *VariantArray) Serialize() ([]byte, error) {
+	b := make([]byte, a.Len())
+	if err := a.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes VariantArray into bytes.
+func (a *VariantArray) SerializeTo(b []byte) error {
+	offset := 4
+	binary.LittleEndian.PutUint32(b[:4], uint32(a.ArraySize))
+
+	for _, v := range a.Variants {
+		if err := v.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += v.Len()
+	}
+
+	return nil
+}
+
+// Len returns the actual length in int.
+func (a *VariantArray) Len() int {
+	l := 4
+	for _, v := range a.Variants {
+		l += v.Len()
+	}
+
+	return l
+}
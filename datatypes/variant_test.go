@@ -6,7 +6,11 @@ package datatypes
 
 import (
 	"testing"
+	"time"
 
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/wmnsk/gopcua/id"
 	"github.com/wmnsk/gopcua/utils/codectest"
 )
 
@@ -32,6 +36,48 @@ func TestVariant(t *testing.T) {
 				0x00,
 			},
 		},
+		{
+			Name:   "int32",
+			Struct: NewVariant(NewInt32(2)),
+			Bytes: []byte{
+				// encoding mask
+				0x06,
+				// value
+				0x02, 0x00, 0x00, 0x00,
+			},
+		},
+		{
+			Name:   "double",
+			Struct: NewVariant(NewDouble(2.5)),
+			Bytes: []byte{
+				// encoding mask
+				0x0b,
+				// value
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x04, 0x40,
+			},
+		},
+		{
+			Name:   "string",
+			Struct: NewVariant(NewString("foo")),
+			Bytes: []byte{
+				// encoding mask
+				0x0c,
+				// length
+				0x03, 0x00, 0x00, 0x00,
+				// value
+				0x66, 0x6f, 0x6f,
+			},
+		},
+		{
+			Name:   "datetime",
+			Struct: NewVariant(NewDateTime(time.Date(2018, time.August, 10, 23, 0, 0, 0, time.UTC))),
+			Bytes: []byte{
+				// encoding mask
+				0x0d,
+				// value
+				0x00, 0x98, 0x67, 0xdd, 0xfd, 0x30, 0xd4, 0x01,
+			},
+		},
 		{
 			Name:   "localized text",
 			Struct: NewVariant(NewLocalizedText("", "Gross value")),
@@ -45,8 +91,695 @@ func TestVariant(t *testing.T) {
 				0x47, 0x72, 0x6f, 0x73, 0x73, 0x20, 0x76, 0x61, 0x6c, 0x75, 0x65,
 			},
 		},
+		{
+			Name:   "localized text with locale",
+			Struct: NewVariant(NewLocalizedText("en-US", "")),
+			Bytes: []byte{
+				// variant encoding mask
+				0x15,
+				// localized text encoding mask
+				0x01,
+				// locale
+				0x05, 0x00, 0x00, 0x00, 0x65, 0x6e, 0x2d, 0x55, 0x53,
+			},
+		},
+		{
+			Name:   "localized text with locale and text",
+			Struct: NewVariant(NewLocalizedText("en-US", "foo")),
+			Bytes: []byte{
+				// variant encoding mask
+				0x15,
+				// localized text encoding mask
+				0x03,
+				// locale
+				0x05, 0x00, 0x00, 0x00, 0x65, 0x6e, 0x2d, 0x55, 0x53,
+				// text
+				0x03, 0x00, 0x00, 0x00, 0x66, 0x6f, 0x6f,
+			},
+		},
+		{
+			Name: "localized text array",
+			Struct: NewArrayVariant(
+				id.LocalizedText,
+				NewLocalizedText("", "foo"),
+				NewLocalizedText("en-US", ""),
+				NewLocalizedText("en-US", "foo"),
+			),
+			Bytes: []byte{
+				// encoding mask
+				0x95,
+				// array length
+				0x03, 0x00, 0x00, 0x00,
+				// text only
+				0x02, 0x03, 0x00, 0x00, 0x00, 0x66, 0x6f, 0x6f,
+				// locale only
+				0x01, 0x05, 0x00, 0x00, 0x00, 0x65, 0x6e, 0x2d, 0x55, 0x53,
+				// both
+				0x03, 0x05, 0x00, 0x00, 0x00, 0x65, 0x6e, 0x2d, 0x55, 0x53,
+				0x03, 0x00, 0x00, 0x00, 0x66, 0x6f, 0x6f,
+			},
+		},
+		{
+			Name:   "qualified name",
+			Struct: NewVariant(NewQualifiedName(2, "foo")),
+			Bytes: []byte{
+				// encoding mask
+				0x14,
+				// namespace index
+				0x02, 0x00,
+				// name
+				0x03, 0x00, 0x00, 0x00, 0x66, 0x6f, 0x6f,
+			},
+		},
+		{
+			Name: "qualified name array",
+			Struct: NewArrayVariant(
+				id.QualifiedName,
+				NewQualifiedName(0, "Server"),
+				NewQualifiedName(2, ""),
+			),
+			Bytes: []byte{
+				// encoding mask
+				0x94,
+				// array length
+				0x02, 0x00, 0x00, 0x00,
+				// values
+				0x00, 0x00, 0x06, 0x00, 0x00, 0x00, 0x53, 0x65, 0x72, 0x76, 0x65, 0x72,
+				0x02, 0x00, 0xff, 0xff, 0xff, 0xff,
+			},
+		},
+		{
+			Name:   "ExpandedNodeID",
+			Struct: NewVariant(NewFourByteExpandedNodeID(1, 0xcafe)),
+			Bytes: []byte{
+				// encoding mask
+				0x12,
+				// value
+				0x01, 0x01, 0xfe, 0xca,
+			},
+		},
+		{
+			Name: "ExpandedNodeID array",
+			Struct: NewArrayVariant(
+				id.ExpandedNodeId,
+				NewTwoByteExpandedNodeID(1),
+				NewExpandedNodeID(true, true, NewTwoByteNodeID(2), "foo", 3),
+			),
+			Bytes: []byte{
+				// encoding mask
+				0x92,
+				// array length
+				0x02, 0x00, 0x00, 0x00,
+				// values
+				0x00, 0x01,
+				0xc0, 0x02, 0x03, 0x00, 0x00, 0x00, 0x66, 0x6f, 0x6f, 0x03, 0x00, 0x00, 0x00,
+			},
+		},
+		{
+			Name: "ExpandedNodeID matrix",
+			Struct: NewMultiDimensionalArrayVariant(
+				id.ExpandedNodeId,
+				[]int32{2, 2},
+				NewTwoByteExpandedNodeID(1),
+				NewTwoByteExpandedNodeID(2),
+				NewTwoByteExpandedNodeID(3),
+				NewFourByteExpandedNodeID(1, 4),
+			),
+			Bytes: []byte{
+				// encoding mask
+				0xd2,
+				// array length
+				0x04, 0x00, 0x00, 0x00,
+				// values
+				0x00, 0x01,
+				0x00, 0x02,
+				0x00, 0x03,
+				0x01, 0x01, 0x04, 0x00,
+				// array dimensions length
+				0x02, 0x00, 0x00, 0x00,
+				// array dimensions
+				0x02, 0x00, 0x00, 0x00,
+				0x02, 0x00, 0x00, 0x00,
+			},
+		},
+		{
+			Name:   "Int32 3x4 matrix",
+			Struct: NewMultiDimensionalArrayVariant(id.Int32, []int32{3, 4}, int32Values(12)...),
+			Bytes: []byte{
+				// encoding mask
+				0xc6,
+				// array length
+				0x0c, 0x00, 0x00, 0x00,
+				// values
+				0x00, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x02, 0x00, 0x00, 0x00, 0x03, 0x00, 0x00, 0x00,
+				0x04, 0x00, 0x00, 0x00, 0x05, 0x00, 0x00, 0x00, 0x06, 0x00, 0x00, 0x00, 0x07, 0x00, 0x00, 0x00,
+				0x08, 0x00, 0x00, 0x00, 0x09, 0x00, 0x00, 0x00, 0x0a, 0x00, 0x00, 0x00, 0x0b, 0x00, 0x00, 0x00,
+				// array dimensions length
+				0x02, 0x00, 0x00, 0x00,
+				// array dimensions
+				0x03, 0x00, 0x00, 0x00,
+				0x04, 0x00, 0x00, 0x00,
+			},
+		},
+		{
+			Name:   "NodeID",
+			Struct: NewVariant(NewFourByteNodeID(1, 0xcafe)),
+			Bytes: []byte{
+				// encoding mask
+				0x11,
+				// value
+				0x01, 0x01, 0xfe, 0xca,
+			},
+		},
+		{
+			Name:   "ByteString",
+			Struct: NewVariant(NewByteString([]byte{0xde, 0xad})),
+			Bytes: []byte{
+				// encoding mask
+				0x0f,
+				// value
+				0x02, 0x00, 0x00, 0x00, 0xde, 0xad,
+			},
+		},
+		{
+			Name: "ExtensionObject array",
+			Struct: NewArrayVariant(
+				id.Structure,
+				NewExtensionObject(ExtensionObjectBinary, NewAnonymousIdentityToken("anonymous")),
+				NewExtensionObject(ExtensionObjectBinary, &RawExtensionObjectValue{
+					EncodingID: id.EnumValueType_Encoding_DefaultBinary,
+					Body:       []byte{0xde, 0xad, 0xbe, 0xef},
+				}),
+			),
+			Bytes: []byte{
+				// encoding mask
+				0x96,
+				// array length
+				0x02, 0x00, 0x00, 0x00,
+				// AnonymousIdentityToken: TypeID, EncodingMask, Length
+				0x01, 0x00, 0x41, 0x01, 0x01, 0x0d, 0x00, 0x00, 0x00,
+				// PolicyID
+				0x09, 0x00, 0x00, 0x00, 0x61, 0x6e, 0x6f, 0x6e, 0x79, 0x6d, 0x6f, 0x75, 0x73,
+				// unknown EnumValueType: TypeID, EncodingMask, Length
+				0x01, 0x00, 0x3b, 0x20, 0x01, 0x04, 0x00, 0x00, 0x00,
+				// body
+				0xde, 0xad, 0xbe, 0xef,
+			},
+		},
+		{
+			Name:   "null ByteString",
+			Struct: NewVariant(NewNullByteString()),
+			Bytes: []byte{
+				// encoding mask
+				0x0f,
+				// value
+				0xff, 0xff, 0xff, 0xff,
+			},
+		},
+		{
+			Name: "DataValue",
+			Struct: NewVariant(NewDataValue(
+				true, true, false, false, false, false,
+				NewVariant(NewInt32(2)), 0x80340000, time.Time{}, 0, time.Time{}, 0,
+			)),
+			Bytes: []byte{
+				// encoding mask
+				0x17,
+				// DataValue encoding mask
+				0x03,
+				// value
+				0x06, 0x02, 0x00, 0x00, 0x00,
+				// status
+				0x00, 0x00, 0x34, 0x80,
+			},
+		},
+		{
+			Name: "DataValue array",
+			Struct: NewArrayVariant(id.DataValue,
+				NewDataValue(false, true, false, false, false, false, nil, 0x80340000, time.Time{}, 0, time.Time{}, 0),
+				NewDataValue(true, false, false, false, false, false, NewVariant(NewBoolean(true)), 0, time.Time{}, 0, time.Time{}, 0),
+			),
+			Bytes: []byte{
+				// encoding mask
+				0x97,
+				// array length
+				0x02, 0x00, 0x00, 0x00,
+				// status only
+				0x02, 0x00, 0x00, 0x34, 0x80,
+				// value only
+				0x01, 0x01, 0x01,
+			},
+		},
+		{
+			Name: "DiagnosticInfo",
+			Struct: NewVariant(NewDiagnosticInfo(
+				true, false, false, false, false, true, true,
+				1, 0, 0, 0, nil, 0x80340000, NewNullDiagnosticInfo(),
+			)),
+			Bytes: []byte{
+				// encoding mask
+				0x19,
+				// DiagnosticInfo encoding mask
+				0x61,
+				// symbolic id
+				0x01, 0x00, 0x00, 0x00,
+				// inner status code
+				0x00, 0x00, 0x34, 0x80,
+				// inner diagnostic info
+				0x00,
+			},
+		},
 	}
 	codectest.Run(t, cases, func(b []byte) (codectest.S, error) {
 		return DecodeVariant(b)
 	})
 }
+
+// nestedDataValues returns the bytes of a Variant of a DataValue, which has a Variant
+// of a DataValue and so on to the depth, with an Int32 Variant at the bottom.
+func nestedDataValues(t *testing.T, depth int) []byte {
+	t.Helper()
+
+	v := NewVariant(NewInt32(1))
+	for i := 0; i < depth; i++ {
+		v = NewVariant(NewDataValue(true, false, false, false, false, false, v, 0, time.Time{}, 0, time.Time{}, 0))
+	}
+	b, err := v.Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestVariantNestingDepth(t *testing.T) {
+	t.Run("max", func(t *testing.T) {
+		b := nestedDataValues(t, maxNestingDepth)
+		v, err := DecodeVariant(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := v.Len(), len(b); got != want {
+			t.Errorf("got length %d want %d", got, want)
+		}
+	})
+	t.Run("too deep", func(t *testing.T) {
+		if _, err := DecodeVariant(nestedDataValues(t, maxNestingDepth+1)); err == nil {
+			t.Error("got nil want error")
+		}
+	})
+	t.Run("inner DiagnosticInfo too deep", func(t *testing.T) {
+		d := NewNullDiagnosticInfo()
+		for i := 0; i <= maxNestingDepth; i++ {
+			d = NewDiagnosticInfo(false, false, false, false, false, false, true, 0, 0, 0, 0, nil, 0, d)
+		}
+		b, err := NewVariant(d).Serialize()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := DecodeVariant(b); err == nil {
+			t.Error("got nil want error")
+		}
+	})
+	t.Run("LiteralOperand max", func(t *testing.T) {
+		b := nestedLiteralOperands(t, maxNestingDepth)
+		v, err := DecodeVariant(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := v.Len(), len(b); got != want {
+			t.Errorf("got length %d want %d", got, want)
+		}
+	})
+	t.Run("LiteralOperand too deep", func(t *testing.T) {
+		if _, err := DecodeVariant(nestedLiteralOperands(t, maxNestingDepth+1)); err == nil {
+			t.Error("got nil want error")
+		}
+	})
+	t.Run("EventFilter too deep", func(t *testing.T) {
+		v := NewVariant(NewInt32(1))
+		for i := 0; i <= maxNestingDepth; i++ {
+			where := NewContentFilter(NewContentFilterElement(FilterOperatorEquals, NewLiteralOperand(v)))
+			v = NewVariant(NewExtensionObject(ExtensionObjectBinary, NewEventFilter(where)))
+		}
+		b, err := v.Serialize()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := DecodeVariant(b); err == nil {
+			t.Error("got nil want error")
+		}
+	})
+}
+
+func TestVariantArrayTooShort(t *testing.T) {
+	cases := []struct {
+		name string
+		b    []byte
+	}{
+		{
+			// the Booleans claimed are more than the ones present.
+			"Boolean",
+			[]byte{0x81, 0x04, 0x00, 0x00, 0x00, 0x01, 0x00},
+		},
+		{
+			"Int32",
+			[]byte{0x86, 0x02, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00},
+		},
+		{
+			"no values",
+			[]byte{0x81, 0xff, 0xff, 0xff, 0x7f},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if _, err := DecodeVariant(c.b); err == nil {
+				t.Error("got nil want error")
+			}
+			if _, err := DecodeDataValue(append([]byte{0x01}, c.b...)); err == nil {
+				t.Error("got nil want error for DataValue")
+			}
+		})
+	}
+}
+
+// nestedLiteralOperands returns the bytes of a Variant of an ExtensionObject of a LiteralOperand,
+// which has a Variant of an ExtensionObject and so on to the depth, with an Int32 Variant at the bottom.
+func nestedLiteralOperands(t *testing.T, depth int) []byte {
+	t.Helper()
+
+	v := NewVariant(NewInt32(1))
+	for i := 0; i < depth; i++ {
+		v = NewVariant(NewExtensionObject(ExtensionObjectBinary, NewLiteralOperand(v)))
+	}
+	b, err := v.Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+// int32Values returns n Int32 values from 0 to n-1.
+func int32Values(n int) []Data {
+	values := make([]Data, n)
+	for i := range values {
+		values[i] = NewInt32(int32(i))
+	}
+	return values
+}
+
+func TestVariantDimensions(t *testing.T) {
+	t.Run("matrix", func(t *testing.T) {
+		b, err := NewMultiDimensionalArrayVariant(id.Int32, []int32{3, 4}, int32Values(12)...).Serialize()
+		if err != nil {
+			t.Fatal(err)
+		}
+		v, err := DecodeVariant(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(v.Dimensions(), []int32{3, 4}); diff != "" {
+			t.Error(diff)
+		}
+
+		m, err := v.Reshape()
+		if err != nil {
+			t.Fatal(err)
+		}
+		values := int32Values(12)
+		want := [][]Data{values[0:4], values[4:8], values[8:12]}
+		if diff := cmp.Diff(m, want); diff != "" {
+			t.Error(diff)
+		}
+	})
+
+	t.Run("array", func(t *testing.T) {
+		v := NewArrayVariant(id.Int32, int32Values(3)...)
+		if diff := cmp.Diff(v.Dimensions(), []int32{3}); diff != "" {
+			t.Error(diff)
+		}
+		s, err := v.Reshape()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(s, int32Values(3)); diff != "" {
+			t.Error(diff)
+		}
+		if NewVariant(NewInt32(1)).Dimensions() != nil {
+			t.Error("scalar should have no dimensions")
+		}
+	})
+
+	t.Run("set", func(t *testing.T) {
+		v := NewArrayVariant(id.Int32, int32Values(12)...)
+		if err := v.SetDimensions(3, 5); err == nil {
+			t.Error("12 values should not be set to 3x5")
+		}
+		if v.HasArrayDimensions() {
+			t.Error("dimensions should not be set on error")
+		}
+		if err := v.SetDimensions(2, 3, 2); err != nil {
+			t.Fatal(err)
+		}
+		m, err := v.Reshape()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := m.([][][]Data); !ok {
+			t.Errorf("got %T want [][][]Data", m)
+		}
+		if err := NewVariant(NewInt32(1)).SetDimensions(1); err == nil {
+			t.Error("scalar should not have dimensions")
+		}
+	})
+
+	t.Run("mismatch", func(t *testing.T) {
+		v := NewMultiDimensionalArrayVariant(id.Int32, []int32{3, 5}, int32Values(12)...)
+		if _, err := v.Serialize(); err == nil {
+			t.Error("12 values should not be serialized as 3x5")
+		}
+		if _, err := v.Reshape(); err == nil {
+			t.Error("12 values should not be reshaped to 3x5")
+		}
+
+		b := []byte{
+			// encoding mask
+			0xc6,
+			// array length
+			0x02, 0x00, 0x00, 0x00,
+			// values
+			0x00, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00,
+			// array dimensions length
+			0x02, 0x00, 0x00, 0x00,
+			// array dimensions
+			0x02, 0x00, 0x00, 0x00,
+			0x02, 0x00, 0x00, 0x00,
+		}
+		if _, err := DecodeVariant(b); err == nil {
+			t.Error("2 values should not be decoded as 2x2")
+		}
+	})
+}
+
+func TestVariantGetters(t *testing.T) {
+	n := NewFourByteNodeID(1, 0xcafe)
+	e := NewStringExpandedNodeIDWithURI("http://example.com", "foo")
+
+	t.Run("NodeID", func(t *testing.T) {
+		got, ok := NewVariant(n).NodeID()
+		if !ok || got != n {
+			t.Fatalf("got %v, %v want %v, true", got, ok, n)
+		}
+		if got, ok := NewVariant(e).NodeID(); ok {
+			t.Fatalf("got %v, true want nil, false", got)
+		}
+	})
+
+	t.Run("ExpandedNodeID", func(t *testing.T) {
+		got, ok := NewVariant(e).ExpandedNodeID()
+		if !ok || got != e {
+			t.Fatalf("got %v, %v want %v, true", got, ok, e)
+		}
+		if got, ok := NewVariant(n).ExpandedNodeID(); ok {
+			t.Fatalf("got %v, true want nil, false", got)
+		}
+		if got, ok := NewArrayVariant(id.ExpandedNodeId, e).ExpandedNodeID(); ok {
+			t.Fatalf("got %v, true want nil, false for array", got)
+		}
+	})
+
+	t.Run("NodeIDSlice", func(t *testing.T) {
+		got, ok := NewArrayVariant(id.NodeId, n, n).NodeIDSlice()
+		if !ok || len(got) != 2 || got[0] != n || got[1] != n {
+			t.Fatalf("got %v, %v want [%v %v], true", got, ok, n, n)
+		}
+		if got, ok := NewVariant(n).NodeIDSlice(); ok {
+			t.Fatalf("got %v, true want nil, false for scalar", got)
+		}
+		if got, ok := NewArrayVariant(id.ExpandedNodeId, e).NodeIDSlice(); ok {
+			t.Fatalf("got %v, true want nil, false", got)
+		}
+	})
+
+	t.Run("ExpandedNodeIDSlice", func(t *testing.T) {
+		got, ok := NewArrayVariant(id.ExpandedNodeId, e).ExpandedNodeIDSlice()
+		if !ok || len(got) != 1 || got[0] != e {
+			t.Fatalf("got %v, %v want [%v], true", got, ok, e)
+		}
+		if got, ok := NewArrayVariant(id.NodeId, n).ExpandedNodeIDSlice(); ok {
+			t.Fatalf("got %v, true want nil, false", got)
+		}
+	})
+
+	t.Run("Bool", func(t *testing.T) {
+		if got, ok := NewVariant(NewBoolean(true)).Bool(); !ok || !got {
+			t.Fatalf("got %v, %v want true, true", got, ok)
+		}
+		if _, ok := NewVariant(NewFloat(1)).Bool(); ok {
+			t.Fatal("got true want false")
+		}
+	})
+
+	t.Run("Int32", func(t *testing.T) {
+		if got, ok := NewVariant(NewInt32(-2)).Int32(); !ok || got != -2 {
+			t.Fatalf("got %v, %v want -2, true", got, ok)
+		}
+		if _, ok := NewVariant(NewFloat(1)).Int32(); ok {
+			t.Fatal("got true want false")
+		}
+	})
+
+	t.Run("Float", func(t *testing.T) {
+		if got, ok := NewVariant(NewFloat(1.5)).Float(); !ok || got != 1.5 {
+			t.Fatalf("got %v, %v want 1.5, true", got, ok)
+		}
+		if _, ok := NewVariant(NewBoolean(true)).Float(); ok {
+			t.Fatal("got true want false")
+		}
+	})
+
+	t.Run("LocalizedText", func(t *testing.T) {
+		l := NewLocalizedText("en-US", "foo")
+		if got, ok := NewVariant(l).LocalizedText(); !ok || got != l {
+			t.Fatalf("got %v, %v want %v, true", got, ok, l)
+		}
+		if _, ok := NewVariant(n).LocalizedText(); ok {
+			t.Fatal("got true want false")
+		}
+	})
+
+	t.Run("QualifiedName", func(t *testing.T) {
+		q := NewQualifiedName(2, "foo")
+		if got, ok := NewVariant(q).QualifiedName(); !ok || got != q {
+			t.Fatalf("got %v, %v want %v, true", got, ok, q)
+		}
+		if _, ok := NewArrayVariant(id.QualifiedName, q).QualifiedName(); ok {
+			t.Fatal("got true want false for array")
+		}
+	})
+
+	t.Run("LocalizedTextSlice", func(t *testing.T) {
+		l := NewLocalizedText("en-US", "foo")
+		got, ok := NewArrayVariant(id.LocalizedText, l).LocalizedTextSlice()
+		if !ok || len(got) != 1 || got[0] != l {
+			t.Fatalf("got %v, %v want [%v], true", got, ok, l)
+		}
+		if got, ok := NewVariant(l).LocalizedTextSlice(); ok {
+			t.Fatalf("got %v, true want nil, false for scalar", got)
+		}
+	})
+
+	t.Run("QualifiedNameSlice", func(t *testing.T) {
+		q := NewQualifiedName(2, "foo")
+		got, ok := NewArrayVariant(id.QualifiedName, q).QualifiedNameSlice()
+		if !ok || len(got) != 1 || got[0] != q {
+			t.Fatalf("got %v, %v want [%v], true", got, ok, q)
+		}
+		if got, ok := NewArrayVariant(id.LocalizedText, NewLocalizedText("", "foo")).QualifiedNameSlice(); ok {
+			t.Fatalf("got %v, true want nil, false", got)
+		}
+	})
+
+	t.Run("ExtensionObjectSlice", func(t *testing.T) {
+		o := NewExtensionObject(ExtensionObjectBinary, NewAnonymousIdentityToken("anonymous"))
+		got, ok := NewArrayVariant(id.Structure, o, o).ExtensionObjectSlice()
+		if !ok || len(got) != 2 || got[0] != o || got[1] != o {
+			t.Fatalf("got %v, %v want [%v %v], true", got, ok, o, o)
+		}
+		if got, ok := NewVariant(o).ExtensionObjectSlice(); ok {
+			t.Fatalf("got %v, true want nil, false for scalar", got)
+		}
+		if got, ok := NewVariant(o).ExtensionObject(); !ok || got != o {
+			t.Fatalf("got %v, %v want %v, true", got, ok, o)
+		}
+	})
+}
+
+func TestNewVariantFrom(t *testing.T) {
+	ts := time.Date(2018, time.August, 10, 23, 0, 0, 0, time.UTC)
+	n := NewStringNodeID(2, "foo")
+
+	cases := []struct {
+		name string
+		v    interface{}
+		want *Variant
+	}{
+		{"int32", int32(2), NewVariant(NewInt32(2))},
+		{"float32", float32(1.5), NewVariant(NewFloat(1.5))},
+		{"float64", 2.5, NewVariant(NewDouble(2.5))},
+		{"string", "foo", NewVariant(NewString("foo"))},
+		{"bool", true, NewVariant(NewBoolean(true))},
+		{"node id", n, NewVariant(n)},
+		{"time", ts, NewVariant(NewDateTime(ts))},
+		{"bytes", []byte{0xde, 0xad}, NewVariant(NewByteString([]byte{0xde, 0xad}))},
+		{"extension object", NewNullExtensionObject(), NewVariant(NewNullExtensionObject())},
+		{"int32 slice", []int32{1, 2}, NewArrayVariant(id.Int32, NewInt32(1), NewInt32(2))},
+		{"string slice", []string{"foo", "bar"}, NewArrayVariant(id.String, NewString("foo"), NewString("bar"))},
+		{"node id slice", []*NodeID{n}, NewArrayVariant(id.NodeId, n)},
+		{"empty slice", []float64{}, NewArrayVariant(id.Double)},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := NewVariantFrom(c.v)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(got, c.want); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+
+	for _, v := range []interface{}{int(1), []int{1}, (*NodeID)(nil), []*NodeID{nil}, nil} {
+		if _, err := NewVariantFrom(v); err == nil {
+			t.Errorf("got nil error for %T", v)
+		}
+	}
+}
+
+func TestVariantArray(t *testing.T) {
+	cases := []codectest.Case{
+		{
+			Name: "normal",
+			Struct: NewVariantArray([]*Variant{
+				NewVariant(NewFloat(2.50025)),
+				NewVariant(NewBoolean(true)),
+			}),
+			Bytes: []byte{
+				// ArraySize
+				0x02, 0x00, 0x00, 0x00,
+				// Float
+				0x0a, 0x19, 0x04, 0x20, 0x40,
+				// Boolean
+				0x01, 0x01,
+			},
+		},
+		{
+			Name:   "empty",
+			Struct: NewVariantArray(nil),
+			Bytes:  []byte{0x00, 0x00, 0x00, 0x00},
+		},
+	}
+	codectest.Run(t, cases, func(b []byte) (codectest.S, error) {
+		return DecodeVariantArray(b)
+	})
+}
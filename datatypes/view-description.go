@@ -0,0 +1,112 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package datatypes
+
+import (
+	"encoding/binary"
+	"time"
+
+	"github.com/wmnsk/gopcua/errors"
+)
+
+// ViewDescription specifies a View to be used in Browse.
+//
+// The null ViewDescription with a null ViewID, zero Timestamp and ViewVersion
+// selects the entire AddressSpace.
+//
+// Specification: Part 4, 7.39
+type ViewDescription struct {
+	ViewID      *NodeID
+	Timestamp   time.Time
+	ViewVersion uint32
+}
+
+// NewViewDescription creates a new ViewDescription.
+func NewViewDescription(view *NodeID, ts time.Time, version uint32) *ViewDescription {
+	return &ViewDescription{
+		ViewID:      view,
+		Timestamp:   ts,
+		ViewVersion: version,
+	}
+}
+
+// NewNullViewDescription creates a null ViewDescription which selects the entire AddressSpace.
+func NewNullViewDescription() *ViewDescription {
+	return NewViewDescription(NewTwoByteNodeID(0), time.Time{}, 0)
+}
+
+// DecodeViewDescription decodes given bytes into ViewDescription.
+func DecodeViewDescription(b []byte) (*ViewDescription, error) {
+	v := &ViewDescription{}
+	if err := v.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return v, nil
+}
+
+// DecodeFromBytes decodes given bytes into ViewDescription.
+func (v *ViewDescription) DecodeFromBytes(b []byte) error {
+	v.ViewID = &NodeID{}
+	if err := v.ViewID.DecodeFromBytes(b); err != nil {
+		return err
+	}
+	offset := v.ViewID.Len()
+
+	if len(b[offset:]) < 12 {
+		return errors.NewErrTooShortToDecode(v, "should contain Timestamp and ViewVersion")
+	}
+	v.Timestamp = time.Time{}
+	if binary.LittleEndian.Uint64(b[offset:offset+8]) != 0 {
+		v.Timestamp = decodeDateTime(b[offset : offset+8])
+	}
+	offset += 8
+
+	v.ViewVersion = binary.LittleEndian.Uint32(b[offset : offset+4])
+	return nil
+}
+
+// Serialize serializes ViewDescription into bytes.
+func (v *ViewDescription) Serialize() ([]byte, error) {
+	b := make([]byte, v.Len())
+	if err := v.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes ViewDescription into bytes.
+//
+// A zero Timestamp is serialized as 0 which means that the current View is used.
+func (v *ViewDescription) SerializeTo(b []byte) error {
+	offset := 0
+	if v.ViewID != nil {
+		if err := v.ViewID.SerializeTo(b); err != nil {
+			return err
+		}
+		offset += v.ViewID.Len()
+	}
+
+	if v.Timestamp.IsZero() {
+		binary.LittleEndian.PutUint64(b[offset:offset+8], 0)
+	} else {
+		encodeDateTime(b[offset:offset+8], v.Timestamp)
+	}
+	offset += 8
+
+	binary.LittleEndian.PutUint32(b[offset:offset+4], v.ViewVersion)
+	return nil
+}
+
+// Len returns the actual length of ViewDescription in int.
+func (v *ViewDescription) Len() int {
+	l := 12
+	if v.ViewID != nil {
+		l += v.ViewID.Len()
+	}
+
+	return l
+}
@@ -0,0 +1,48 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package datatypes
+
+import (
+	"testing"
+	"time"
+
+	"github.com/wmnsk/gopcua/utils/codectest"
+)
+
+func TestViewDescription(t *testing.T) {
+	cases := []codectest.Case{
+		{
+			Name:   "null",
+			Struct: NewNullViewDescription(),
+			Bytes: []byte{
+				// ViewID
+				0x00, 0x00,
+				// Timestamp
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				// ViewVersion
+				0x00, 0x00, 0x00, 0x00,
+			},
+		},
+		{
+			Name: "with timestamp",
+			Struct: NewViewDescription(
+				NewFourByteNodeID(1, 1000),
+				time.Date(2018, time.August, 10, 23, 0, 0, 0, time.UTC),
+				2,
+			),
+			Bytes: []byte{
+				// ViewID
+				0x01, 0x01, 0xe8, 0x03,
+				// Timestamp
+				0x00, 0x98, 0x67, 0xdd, 0xfd, 0x30, 0xd4, 0x01,
+				// ViewVersion
+				0x02, 0x00, 0x00, 0x00,
+			},
+		},
+	}
+	codectest.Run(t, cases, func(b []byte) (codectest.S, error) {
+		return DecodeViewDescription(b)
+	})
+}
@@ -13,13 +13,13 @@ import (
 // Specification: Part4, 5.10.4.2
 type WriteValue struct {
 	NodeID      *NodeID
-	AttributeID IntegerID
+	AttributeID AttributeID
 	IndexRange  *String
 	Value       *DataValue
 }
 
 // NewWriteValue creates a new NewWriteValue.
-func NewWriteValue(node *NodeID, attr IntegerID, idxRange string, value *DataValue) *WriteValue {
+func NewWriteValue(node *NodeID, attr AttributeID, idxRange string, value *DataValue) *WriteValue {
 	return &WriteValue{
 		NodeID:      node,
 		AttributeID: attr,
@@ -47,7 +47,7 @@ func (w *WriteValue) DecodeFromBytes(b []byte) error {
 	w.NodeID = nodeID
 	offset := w.NodeID.Len()
 
-	w.AttributeID = IntegerID(binary.LittleEndian.Uint32(b[offset:]))
+	w.AttributeID = AttributeID(binary.LittleEndian.Uint32(b[offset:]))
 	offset += 4
 
 	w.IndexRange = &String{}
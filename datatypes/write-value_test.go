@@ -17,7 +17,7 @@ func TestWriteValue(t *testing.T) {
 			Name: "normal",
 			Struct: NewWriteValue(
 				NewFourByteNodeID(0, 2256),
-				IntegerIDValue,
+				AttributeIDValue,
 				"",
 				NewDataValue(
 					true, false, true, false, true, false,
@@ -57,7 +57,7 @@ func TestWriteValueArray(t *testing.T) {
 				[]*WriteValue{
 					NewWriteValue(
 						NewFourByteNodeID(0, 2256),
-						IntegerIDValue,
+						AttributeIDValue,
 						"",
 						NewDataValue(
 							true, false, true, false, true, false,
@@ -71,7 +71,7 @@ func TestWriteValueArray(t *testing.T) {
 					),
 					NewWriteValue(
 						NewFourByteNodeID(0, 2256),
-						IntegerIDValue,
+						AttributeIDValue,
 						"",
 						NewDataValue(
 							true, false, true, false, true, false,
@@ -0,0 +1,247 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package gopcua
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/wmnsk/gopcua/datatypes"
+	"github.com/wmnsk/gopcua/errors"
+	"github.com/wmnsk/gopcua/services"
+	"github.com/wmnsk/gopcua/status"
+	"github.com/wmnsk/gopcua/uacp"
+	"github.com/wmnsk/gopcua/uasc"
+)
+
+// GetEndpoints returns the Endpoints provided by the server at discoveryURL.
+//
+// It opens a SecureChannel with security mode None without creating a Session,
+// as the discovery services do not require it, and closes it before returning.
+func GetEndpoints(ctx context.Context, discoveryURL string) ([]*services.EndpointDescription, error) {
+	srv, err := discover(ctx, discoveryURL, func(secChan *uasc.SecureChannel) error {
+		return secChan.GetEndpointsRequest(nil, nil)
+	}, func(srv services.Service) bool {
+		_, ok := srv.(*services.GetEndpointsResponse)
+		return ok
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := srv.(*services.GetEndpointsResponse)
+	if code := res.ServiceResult; code != 0 {
+		return nil, errors.Errorf("GetEndpoints failed with status %v", status.StatusCode(code))
+	}
+	if res.Endpoints == nil {
+		return nil, nil
+	}
+	return res.Endpoints.EndpointDescriptions, nil
+}
+
+// FindServers returns the servers known to the server at discoveryURL, which is typically
+// a Discovery Server where the servers are registered. The URLs to get the Endpoints of
+// each server are in its DiscoveryURIs.
+//
+// If serverURIs are given, only the servers with the ApplicationURIs in them are returned.
+// Like GetEndpoints, it does not create a Session.
+func FindServers(ctx context.Context, discoveryURL string, serverURIs []string) ([]*services.ApplicationDescription, error) {
+	srv, err := discover(ctx, discoveryURL, func(secChan *uasc.SecureChannel) error {
+		return secChan.FindServersRequest(nil, serverURIs...)
+	}, func(srv services.Service) bool {
+		_, ok := srv.(*services.FindServersResponse)
+		return ok
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	res := srv.(*services.FindServersResponse)
+	if code := res.ServiceResult; code != 0 {
+		return nil, errors.Errorf("FindServers failed with status %v", status.StatusCode(code))
+	}
+	if res.Servers == nil {
+		return nil, nil
+	}
+	return res.Servers.ApplicationDescriptions, nil
+}
+
+// RegisterServer2 registers the server to the Local Discovery Server at lds, with the
+// MdnsDiscoveryConfigurations to announce it in the network if any.
+//
+// The Discovery Server removes the servers not registered again for a while, so the server
+// should call it periodically while running. See KeepRegistered. Like GetEndpoints, it opens
+// a SecureChannel with security mode None, which some Discovery Servers may reject.
+func RegisterServer2(ctx context.Context, lds string, server *datatypes.RegisteredServer, config []*datatypes.MdnsDiscoveryConfiguration) error {
+	configs := make([]datatypes.ExtensionObjectValue, len(config))
+	for i, c := range config {
+		configs[i] = c
+	}
+
+	srv, err := discover(ctx, lds, func(secChan *uasc.SecureChannel) error {
+		h := services.NewRequestHeader(
+			datatypes.NewTwoByteNodeID(0), time.Now(), 1, 0, 0, "", services.NewNullAdditionalHeader(), nil,
+		)
+		b, err := services.NewRegisterServer2Request(h, server, configs...).Serialize()
+		if err != nil {
+			return err
+		}
+		_, err = secChan.WriteService(b)
+		return err
+	}, func(srv services.Service) bool {
+		_, ok := srv.(*services.RegisterServer2Response)
+		return ok
+	})
+	if err != nil {
+		return err
+	}
+
+	res := srv.(*services.RegisterServer2Response)
+	if code := res.ServiceResult; code != 0 {
+		return errors.Errorf("RegisterServer2 failed with status %v", status.StatusCode(code))
+	}
+	if res.ConfigurationResults == nil {
+		return nil
+	}
+	for i, code := range res.ConfigurationResults.Values {
+		if code != 0 {
+			return errors.Errorf("RegisterServer2 failed for the configuration %d with status %v", i, status.StatusCode(code))
+		}
+	}
+	return nil
+}
+
+// DefaultRegisterInterval is the interval of KeepRegistered used if it is not given,
+// which the servers commonly use to register again to the Discovery Servers.
+const DefaultRegisterInterval = 10 * time.Minute
+
+// KeepRegistered registers the server to the Local Discovery Server at lds with RegisterServer2
+// every interval, or DefaultRegisterInterval if it is not positive, until ctx is done.
+// Then it registers the server once again with IsOnline false, so that the Discovery Server
+// removes it immediately.
+//
+// It returns the error if the first registration fails. The errors in the following ones are
+// passed to onError if it is not nil, and the registration is tried again on the next interval.
+func KeepRegistered(ctx context.Context, lds string, server *datatypes.RegisteredServer, config []*datatypes.MdnsDiscoveryConfiguration, interval time.Duration, onError func(error)) error {
+	if interval <= 0 {
+		interval = DefaultRegisterInterval
+	}
+
+	online := *server
+	online.IsOnline = datatypes.NewBoolean(true)
+	if err := RegisterServer2(ctx, lds, &online, config); err != nil {
+		return err
+	}
+
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			offline := *server
+			offline.IsOnline = datatypes.NewBoolean(false)
+
+			// ctx is already done, so the last registration has its own timeout.
+			octx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := RegisterServer2(octx, lds, &offline, config); err != nil && onError != nil {
+				onError(err)
+			}
+			return nil
+		case <-t.C:
+			if err := RegisterServer2(ctx, lds, &online, config); err != nil && onError != nil && ctx.Err() == nil {
+				onError(err)
+			}
+		}
+	}
+}
+
+// discover opens a SecureChannel with security mode None to discoveryURL, sends the
+// request with send and returns the first service received which matches.
+func discover(ctx context.Context, discoveryURL string, send func(*uasc.SecureChannel) error, match func(services.Service) bool) (services.Service, error) {
+	conn, err := uacp.Dial(ctx, discoveryURL)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	secChan, err := uasc.OpenSecureChannel(ctx, conn, uasc.NewClientConfigSecurityNone(3333, 3600000), 5*time.Second, 3)
+	if err != nil {
+		return nil, err
+	}
+	defer secChan.Close()
+
+	if err := send(secChan); err != nil {
+		return nil, err
+	}
+
+	type result struct {
+		res services.Service
+		err error
+	}
+	resChan := make(chan result, 1)
+	go func() {
+		res, err := readResponse(secChan, match)
+		resChan <- result{res, err}
+	}()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case r := <-resChan:
+		return r.res, r.err
+	}
+}
+
+// readResponse reads the services from secChan until the one which matches arrives.
+func readResponse(secChan *uasc.SecureChannel, match func(services.Service) bool) (services.Service, error) {
+	buf := make([]byte, 0xffff)
+	for {
+		n, err := secChan.ReadService(buf)
+		if err != nil {
+			if err == io.ErrShortBuffer {
+				buf = make([]byte, n)
+				continue
+			}
+			return nil, err
+		}
+
+		// the service refers to the bytes it is decoded from, which should
+		// not be overwritten by the next read.
+		b := make([]byte, n)
+		copy(b, buf[:n])
+		srv, err := services.Decode(b)
+		if err != nil {
+			continue
+		}
+		if match(srv) {
+			return srv, nil
+		}
+	}
+}
+
+// SelectEndpoint returns the Endpoint which has the SecurityPolicyURI and MessageSecurityMode given.
+//
+// If there are multiple matches, the one with the highest SecurityLevel is returned.
+func SelectEndpoint(endpoints []*services.EndpointDescription, policyURI string, mode uint32) (*services.EndpointDescription, error) {
+	var selected *services.EndpointDescription
+	for _, ep := range endpoints {
+		if ep.SecurityPolicyURI == nil || ep.SecurityPolicyURI.Get() != policyURI {
+			continue
+		}
+		if ep.MessageSecurityMode != mode {
+			continue
+		}
+		if selected == nil || ep.SecurityLevel > selected.SecurityLevel {
+			selected = ep
+		}
+	}
+
+	if selected == nil {
+		return nil, errors.Errorf("no endpoint found for policy %s and security mode %d", policyURI, mode)
+	}
+	return selected, nil
+}
@@ -0,0 +1,354 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package gopcua
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/wmnsk/gopcua/datatypes"
+	"github.com/wmnsk/gopcua/services"
+	"github.com/wmnsk/gopcua/status"
+	"github.com/wmnsk/gopcua/uacp"
+	"github.com/wmnsk/gopcua/uasc"
+)
+
+const (
+	policyBasic256Sha256 = "http://opcfoundation.org/UA/SecurityPolicy#Basic256Sha256"
+)
+
+func newEndpoint(policyURI string, mode uint32, level uint8) *services.EndpointDescription {
+	return services.NewEndpointDescription(
+		endpoint,
+		services.NewApplicationDescription(
+			"app-uri", "prod-uri", "app-name", services.AppTypeServer,
+			"", "", []string{endpoint},
+		),
+		[]byte{},
+		mode,
+		policyURI,
+		services.NewUserTokenPolicyArray(
+			[]*services.UserTokenPolicy{
+				services.NewUserTokenPolicy("anonymous", services.UserTokenAnonymous, "", "", ""),
+			},
+		),
+		"http://opcfoundation.org/UA-Profile/Transport/uatcp-uasc-uabinary",
+		level,
+	)
+}
+
+func TestGetEndpoints(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	want := []*services.EndpointDescription{
+		newEndpoint(policyURI, services.SecModeNone, 0),
+		newEndpoint(policyBasic256Sha256, services.SecModeSignAndEncrypt, 3),
+	}
+
+	ln, err := uacp.Listen(endpoint, 0xffff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	go func() {
+		srvConn, err := ln.Accept(ctx)
+		if err != nil {
+			return
+		}
+		defer srvConn.Close()
+
+		srvCfg := uasc.NewServerConfig(policyURI, nil, nil, 1111, services.SecModeNone, 2222, 3600000)
+		srvChan, err := uasc.ListenAndAcceptSecureChannel(ctx, srvConn, srvCfg)
+		if err != nil {
+			return
+		}
+
+		buf := make([]byte, 0xffff)
+		for {
+			n, err := srvChan.ReadService(buf)
+			if err != nil {
+				return
+			}
+			srv, err := services.Decode(buf[:n])
+			if err != nil {
+				continue
+			}
+			if _, ok := srv.(*services.GetEndpointsRequest); ok {
+				srvChan.GetEndpointsResponse(0, want...)
+			}
+		}
+	}()
+
+	gctx, gcancel := context.WithTimeout(ctx, 10*time.Second)
+	defer gcancel()
+
+	got, err := GetEndpoints(gctx, endpoint)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestFindServers(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	servers := []*services.ApplicationDescription{
+		services.NewApplicationDescription(
+			"urn:server-1", "prod-uri", "server-1", services.AppTypeServer,
+			"", "", []string{"opc.tcp://10.0.0.1:4840", "opc.tcp://server-1:4840"},
+		),
+		services.NewApplicationDescription(
+			"urn:server-2", "prod-uri", "server-2", services.AppTypeServer,
+			"", "", []string{"opc.tcp://10.0.0.2:4840"},
+		),
+	}
+
+	ln, err := uacp.Listen(endpoint, 0xffff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	uris := make(chan []string, 1)
+	go func() {
+		srvConn, err := ln.Accept(ctx)
+		if err != nil {
+			return
+		}
+		defer srvConn.Close()
+
+		srvCfg := uasc.NewServerConfig(policyURI, nil, nil, 1111, services.SecModeNone, 2222, 3600000)
+		srvChan, err := uasc.ListenAndAcceptSecureChannel(ctx, srvConn, srvCfg)
+		if err != nil {
+			return
+		}
+
+		buf := make([]byte, 0xffff)
+		for {
+			n, err := srvChan.ReadService(buf)
+			if err != nil {
+				return
+			}
+			srv, err := services.Decode(buf[:n])
+			if err != nil {
+				continue
+			}
+			if req, ok := srv.(*services.FindServersRequest); ok {
+				uris <- req.ServerURIs.Get()
+				srvChan.FindServersResponse(0, servers...)
+			}
+		}
+	}()
+
+	fctx, fcancel := context.WithTimeout(ctx, 10*time.Second)
+	defer fcancel()
+
+	got, err := FindServers(fctx, endpoint, []string{"urn:server-1", "urn:server-2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(<-uris, []string{"urn:server-1", "urn:server-2"}); diff != "" {
+		t.Error(diff)
+	}
+	if len(got) != 2 {
+		t.Fatalf("got %d servers want 2", len(got))
+	}
+
+	want := [][]string{
+		{"opc.tcp://10.0.0.1:4840", "opc.tcp://server-1:4840"},
+		{"opc.tcp://10.0.0.2:4840"},
+	}
+	for i, srv := range got {
+		if diff := cmp.Diff(srv.DiscoveryURIs.Get(), want[i]); diff != "" {
+			t.Errorf("server %d: %s", i, diff)
+		}
+	}
+}
+
+// serveRegisterServer2 accepts the connections on ln until ctx is done and responds to
+// the RegisterServer2Requests with the results, after sending them to reqs.
+func serveRegisterServer2(ctx context.Context, ln *uacp.Listener, reqs chan<- *services.RegisterServer2Request, results ...uint32) {
+	for {
+		srvConn, err := ln.Accept(ctx)
+		if err != nil {
+			return
+		}
+
+		go func() {
+			defer srvConn.Close()
+
+			srvCfg := uasc.NewServerConfig(policyURI, nil, nil, 1111, services.SecModeNone, 2222, 3600000)
+			srvChan, err := uasc.ListenAndAcceptSecureChannel(ctx, srvConn, srvCfg)
+			if err != nil {
+				return
+			}
+
+			buf := make([]byte, 0xffff)
+			for {
+				n, err := srvChan.ReadService(buf)
+				if err != nil {
+					return
+				}
+				b := make([]byte, n)
+				copy(b, buf[:n])
+				srv, err := services.Decode(b)
+				if err != nil {
+					continue
+				}
+				if req, ok := srv.(*services.RegisterServer2Request); ok {
+					reqs <- req
+					res, err := services.NewRegisterServer2Response(newResponseHeader(req.RequestHandle), nil, results...).Serialize()
+					if err != nil {
+						return
+					}
+					srvChan.WriteService(res)
+				}
+			}
+		}()
+	}
+}
+
+func TestRegisterServer2(t *testing.T) {
+	server := datatypes.NewRegisteredServer(
+		"urn:gopcua:server", "urn:gopcua", "gopcua", services.AppTypeServer, []string{"opc.tcp://server:4840"}, true,
+	)
+	config := []*datatypes.MdnsDiscoveryConfiguration{
+		datatypes.NewMdnsDiscoveryConfiguration("gopcua", "DA"),
+	}
+
+	cases := []struct {
+		name    string
+		results []uint32
+		ok      bool
+	}{
+		{"accepted", []uint32{0}, true},
+		{"configuration-rejected", []uint32{status.BadNotSupported}, false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			ln, err := uacp.Listen(endpoint, 0xffff)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer ln.Close()
+
+			reqs := make(chan *services.RegisterServer2Request, 1)
+			go serveRegisterServer2(ctx, ln, reqs, c.results...)
+
+			err = RegisterServer2(ctx, endpoint, server, config)
+			if c.ok && err != nil {
+				t.Fatal(err)
+			}
+			if !c.ok && err == nil {
+				t.Fatal("expected error, got nil")
+			}
+
+			req := <-reqs
+			if diff := cmp.Diff(req.Server, server); diff != "" {
+				t.Error(diff)
+			}
+			if got := req.DiscoveryConfiguration.ExtensionObjects; len(got) != 1 {
+				t.Fatalf("got %d configurations want 1", len(got))
+			} else if diff := cmp.Diff(got[0].Value, config[0]); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestKeepRegistered(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	ln, err := uacp.Listen(endpoint, 0xffff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	reqs := make(chan *services.RegisterServer2Request, 16)
+	go serveRegisterServer2(ctx, ln, reqs)
+
+	server := datatypes.NewRegisteredServer(
+		"urn:gopcua:server", "urn:gopcua", "gopcua", services.AppTypeServer, []string{"opc.tcp://server:4840"}, true,
+	)
+	kctx, kcancel := context.WithCancel(ctx)
+	defer kcancel()
+	done := make(chan error, 1)
+	go func() {
+		done <- KeepRegistered(kctx, endpoint, server, nil, 50*time.Millisecond, func(err error) {
+			t.Error(err)
+		})
+	}()
+
+	// the server is registered on start and again after the interval.
+	for i := 0; i < 2; i++ {
+		if req := <-reqs; req.Server.IsOnline.Value != 1 {
+			t.Errorf("registration %d: got IsOnline %d want 1", i, req.Server.IsOnline.Value)
+		}
+	}
+	kcancel()
+	if err := <-done; err != nil {
+		t.Fatal(err)
+	}
+
+	// the last registration is offline.
+	var last *services.RegisterServer2Request
+	for len(reqs) > 0 {
+		last = <-reqs
+	}
+	if last == nil || last.Server.IsOnline.Value != 0 {
+		t.Errorf("got last registration %v want offline", last)
+	}
+}
+
+func TestSelectEndpoint(t *testing.T) {
+	endpoints := []*services.EndpointDescription{
+		newEndpoint(policyURI, services.SecModeNone, 0),
+		newEndpoint(policyBasic256Sha256, services.SecModeSign, 1),
+		newEndpoint(policyBasic256Sha256, services.SecModeSignAndEncrypt, 2),
+		newEndpoint(policyBasic256Sha256, services.SecModeSignAndEncrypt, 3),
+	}
+
+	cases := []struct {
+		name      string
+		policyURI string
+		mode      uint32
+		want      *services.EndpointDescription
+	}{
+		{"none", policyURI, services.SecModeNone, endpoints[0]},
+		{"sign", policyBasic256Sha256, services.SecModeSign, endpoints[1]},
+		{"sign-and-encrypt", policyBasic256Sha256, services.SecModeSignAndEncrypt, endpoints[3]},
+		{"no-match", policyURI, services.SecModeSign, nil},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got, err := SelectEndpoint(endpoints, c.policyURI, c.mode)
+			if c.want == nil {
+				if err == nil {
+					t.Fatalf("expected error, got %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != c.want {
+				t.Errorf("got endpoint with security level %d, want %d", got.SecurityLevel, c.want.SecurityLevel)
+			}
+		})
+	}
+}
@@ -95,7 +95,7 @@ func main() {
 
 	if err := session.ReadRequest(
 		2000, services.TimestampsToReturnBoth, datatypes.NewReadValueID(
-			datatypes.NewNumericNodeID(0, 11111), datatypes.IntegerIDValue, "", 0, "",
+			datatypes.NewNumericNodeID(0, 11111), datatypes.AttributeIDValue, "", 0, "",
 		),
 	); err != nil {
 		log.Fatal(err)
@@ -0,0 +1,149 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package gopcua
+
+import (
+	"context"
+	"time"
+
+	"github.com/wmnsk/gopcua/datatypes"
+	"github.com/wmnsk/gopcua/errors"
+	"github.com/wmnsk/gopcua/services"
+	"github.com/wmnsk/gopcua/status"
+)
+
+// HistoryRead sends a HistoryReadRequest with the details for the nodes given
+// and returns the HistoryReadResponse.
+//
+// If release is true, the continuation points in nodes are released and no data is returned.
+func (c *Client) HistoryRead(details datatypes.ExtensionObjectValue, tsRet services.TimestampsToReturn, release bool, nodes ...*datatypes.HistoryReadValueID) (*services.HistoryReadResponse, error) {
+	return c.HistoryReadWithContext(context.Background(), details, tsRet, release, nodes...)
+}
+
+// HistoryReadWithContext is the same as HistoryRead but returns ctx.Err() if ctx is done
+// before the HistoryReadResponse arrives.
+func (c *Client) HistoryReadWithContext(ctx context.Context, details datatypes.ExtensionObjectValue, tsRet services.TimestampsToReturn, release bool, nodes ...*datatypes.HistoryReadValueID) (*services.HistoryReadResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.session == nil {
+		return nil, ErrNotConnected
+	}
+
+	h := c.requestHeader()
+	res, err := c.send(ctx, services.NewHistoryReadRequest(h, details, tsRet, release, nodes...), h.RequestHandle)
+	if err != nil {
+		return nil, err
+	}
+
+	r, ok := res.(*services.HistoryReadResponse)
+	if !ok {
+		return nil, errors.NewErrInvalidType(res, "history read", "should be HistoryReadResponse")
+	}
+	return r, nil
+}
+
+// HistoryReadRaw reads the raw values of the nodes between start and end, at most numValues
+// for each node or all of them if it is 0. If bounds is true, the values just before start
+// and just after end are returned as well.
+//
+// The continuation points are followed until the server has no more values, and the values
+// are returned in a HistoryData for each node in the same order as nodes. If the history of
+// some of the nodes cannot be read, their HistoryData are nil and an error is returned
+// with the others. The continuation points held by the server are released if ctx is done.
+func (c *Client) HistoryReadRaw(ctx context.Context, nodes []*datatypes.NodeID, start, end time.Time, numValues uint32, bounds bool) ([]*datatypes.HistoryData, error) {
+	details := datatypes.NewReadRawModifiedDetails(false, start, end, numValues, bounds)
+
+	data := make([]*datatypes.HistoryData, len(nodes))
+	cps := make([][]byte, len(nodes))
+	pending := make([]int, len(nodes))
+	for i := range nodes {
+		data[i] = datatypes.NewHistoryData()
+		pending[i] = i
+	}
+
+	var failed error
+	for len(pending) > 0 {
+		if err := ctx.Err(); err != nil {
+			c.releaseHistory(details, nodes, cps, pending)
+			return nil, err
+		}
+
+		ids := make([]*datatypes.HistoryReadValueID, len(pending))
+		for j, i := range pending {
+			ids[j] = datatypes.NewHistoryReadValueID(nodes[i], "", 0, "", cps[i])
+		}
+
+		res, err := c.HistoryReadWithContext(ctx, details, services.TimestampsToReturnBoth, false, ids...)
+		if err != nil {
+			if ctx.Err() != nil {
+				c.releaseHistory(details, nodes, cps, pending)
+			}
+			return nil, err
+		}
+		if res.Results == nil || len(res.Results.HistoryReadResults) != len(pending) {
+			c.releaseHistory(details, nodes, cps, pending)
+			return nil, errors.New("history read returned unexpected number of results")
+		}
+
+		var next []int
+		for j, i := range pending {
+			r := res.Results.HistoryReadResults[j]
+			cps[i] = nil
+
+			// Good and Uncertain results may still have the values.
+			if r.StatusCode&0x80000000 != 0 {
+				data[i] = nil
+				if failed == nil {
+					failed = errors.Errorf("history read failed for %v with status %v", nodes[i], status.StatusCode(r.StatusCode))
+				}
+				continue
+			}
+
+			if r.HistoryData != nil {
+				if d, ok := r.HistoryData.Value.(*datatypes.HistoryData); ok && d.DataValues != nil {
+					data[i].DataValues = datatypes.NewDataValueArray(
+						append(data[i].DataValues.DataValues, d.DataValues.DataValues...),
+					)
+				}
+			}
+
+			if r.ContinuationPoint != nil && len(r.ContinuationPoint.Get()) > 0 {
+				cps[i] = r.ContinuationPoint.Get()
+				next = append(next, i)
+			}
+		}
+		pending = next
+	}
+
+	return data, failed
+}
+
+// releaseHistory asks the server to release the continuation points of the pending nodes.
+// Like release, it does not wait for the HistoryReadResponse and the errors are ignored.
+func (c *Client) releaseHistory(details datatypes.ExtensionObjectValue, nodes []*datatypes.NodeID, cps [][]byte, pending []int) {
+	var ids []*datatypes.HistoryReadValueID
+	for _, i := range pending {
+		if len(cps[i]) > 0 {
+			ids = append(ids, datatypes.NewHistoryReadValueID(nodes[i], "", 0, "", cps[i]))
+		}
+	}
+	if len(ids) == 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.session == nil {
+		return
+	}
+
+	b, err := services.NewHistoryReadRequest(c.requestHeader(), details, services.TimestampsToReturnBoth, true, ids...).Serialize()
+	if err != nil {
+		return
+	}
+	c.session.WriteService(b)
+}
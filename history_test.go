@@ -0,0 +1,105 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package gopcua
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/wmnsk/gopcua/datatypes"
+	"github.com/wmnsk/gopcua/services"
+	"github.com/wmnsk/gopcua/uacp"
+)
+
+func TestClientHistoryReadRaw(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ln, err := uacp.Listen(endpoint, 0xffff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	nodes := []*datatypes.NodeID{
+		datatypes.NewStringNodeID(1, "temperature"),
+		datatypes.NewStringNodeID(1, "unknown"),
+	}
+	start := time.Date(2018, time.August, 10, 23, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+
+	// the server returns one value with a continuation point for the first node
+	// and a bad StatusCode for the second one, then the rest for the first node.
+	reqs := make(chan *services.HistoryReadRequest, 2)
+	var calls int
+	go serveSession(ctx, ln, func(srv services.Service) (services.Service, bool) {
+		req, ok := srv.(*services.HistoryReadRequest)
+		if !ok {
+			return nil, false
+		}
+		reqs <- req
+
+		calls++
+		if calls == 1 {
+			return services.NewHistoryReadResponse(
+				newResponseHeader(req.RequestHandle), nil,
+				datatypes.NewHistoryReadResult(0, []byte{0x01}, datatypes.NewHistoryData(newValue(datatypes.NewFloat(1)))),
+				datatypes.NewHistoryReadResult(0x80340000, nil, nil),
+			), false
+		}
+		return services.NewHistoryReadResponse(
+			newResponseHeader(req.RequestHandle), nil,
+			datatypes.NewHistoryReadResult(0, nil, datatypes.NewHistoryData(newValue(datatypes.NewFloat(2)))),
+		), false
+	})
+
+	c := NewClient(endpoint)
+	if err := c.Connect(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	rctx, rcancel := context.WithTimeout(ctx, 10*time.Second)
+	defer rcancel()
+
+	got, err := c.HistoryReadRaw(rctx, nodes, start, end, 1, true)
+	if err == nil {
+		t.Error("expected error for the bad node, got nil")
+	}
+
+	want := []*datatypes.HistoryData{
+		datatypes.NewHistoryData(newValue(datatypes.NewFloat(1)), newValue(datatypes.NewFloat(2))),
+		nil,
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Error(diff)
+	}
+
+	first, second := <-reqs, <-reqs
+	d, ok := first.HistoryReadDetails.Value.(*datatypes.ReadRawModifiedDetails)
+	if !ok {
+		t.Fatalf("got %T, want ReadRawModifiedDetails", first.HistoryReadDetails.Value)
+	}
+	if diff := cmp.Diff(d, datatypes.NewReadRawModifiedDetails(false, start, end, 1, true)); diff != "" {
+		t.Error(diff)
+	}
+	if n := len(first.NodesToRead.HistoryReadValueIDs); n != 2 {
+		t.Errorf("first request has %d nodes, want 2", n)
+	}
+
+	next := second.NodesToRead.HistoryReadValueIDs
+	if len(next) != 1 {
+		t.Fatalf("second request has %d nodes, want 1", len(next))
+	}
+	if diff := cmp.Diff(next[0].NodeID, nodes[0]); diff != "" {
+		t.Error(diff)
+	}
+	if diff := cmp.Diff(next[0].ContinuationPoint.Get(), []byte{0x01}); diff != "" {
+		t.Error(diff)
+	}
+}
@@ -0,0 +1,123 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package gopcua
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/wmnsk/gopcua/datatypes"
+	"github.com/wmnsk/gopcua/errors"
+	"github.com/wmnsk/gopcua/id"
+	"github.com/wmnsk/gopcua/uasc"
+)
+
+// WithKeepAlive makes the Client read the State of the ServerStatus of the server at
+// the interval given while it is connected, to detect the connection silently lost
+// before the next request fails.
+//
+// The health check fails if the read fails or the State is not Running. On failure,
+// the Client is marked unhealthy and the callback set by WithKeepAliveCallback is called.
+// If WithReconnect is also set, the connection is closed to start reconnecting.
+func WithKeepAlive(interval time.Duration) Option {
+	return func(c *Client) {
+		c.keepAlive = interval
+	}
+}
+
+// WithKeepAliveCallback sets the callback called with the error when the health check
+// by WithKeepAlive fails.
+//
+// The callback is called from the goroutine of the health check, so it should return
+// quickly and must not call the methods of the Client.
+func WithKeepAliveCallback(f func(error)) Option {
+	return func(c *Client) {
+		c.onKeepAlive = f
+	}
+}
+
+// Healthy reports whether the Client is connected and the last health check by
+// WithKeepAlive, if any, has succeeded.
+func (c *Client) Healthy() bool {
+	return atomic.LoadInt32(&c.healthy) == 1
+}
+
+func (c *Client) setHealthy(ok bool) {
+	var v int32
+	if ok {
+		v = 1
+	}
+	atomic.StoreInt32(&c.healthy, v)
+}
+
+// checkHealth checks the server periodically while session is used by the Client,
+// until ctx is done or stop is closed by Close.
+func (c *Client) checkHealth(ctx context.Context, stop chan struct{}, session *uasc.Session) {
+	ticker := time.NewTicker(c.keepAlive)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+
+		c.mu.Lock()
+		current := c.session == session
+		c.mu.Unlock()
+		if !current {
+			return
+		}
+
+		err := c.readServerState(ctx)
+		if err == nil {
+			c.setHealthy(true)
+			continue
+		}
+
+		c.log().Warn("health check failed", "endpoint", c.endpoint, "error", err)
+		c.setHealthy(false)
+		if c.onKeepAlive != nil {
+			c.onKeepAlive(err)
+		}
+		if c.backoff != nil {
+			c.mu.Lock()
+			if c.session == session {
+				// the pending read fails, then monitor closes the connection and
+				// starts reconnecting.
+				c.conn.SetReadDeadline(time.Now())
+			}
+			c.mu.Unlock()
+			return
+		}
+	}
+}
+
+// readServerState reads the State of the ServerStatus and returns an error if it
+// cannot be read within the interval of the health check or is not Running.
+func (c *Client) readServerState(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, c.keepAlive)
+	defer cancel()
+
+	v, err := c.readAttribute(ctx, datatypes.NewNumericNodeID(0, id.Server_ServerStatus_State), datatypes.AttributeIDValue)
+	if err != nil {
+		return err
+	}
+	state, ok := v.Int32()
+	if !ok {
+		return errors.Errorf("read returned %T, not ServerState", v.Value)
+	}
+	if state != serverStateRunning {
+		return errors.Errorf("server state is %d, not Running", state)
+	}
+	return nil
+}
+
+// serverStateRunning is the ServerState of the server running normally.
+const serverStateRunning = 0
@@ -0,0 +1,158 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package gopcua
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/wmnsk/gopcua/datatypes"
+	"github.com/wmnsk/gopcua/id"
+	"github.com/wmnsk/gopcua/services"
+	"github.com/wmnsk/gopcua/status"
+)
+
+// handleServerState responds to the reads of the State of the ServerStatus with the value
+// returned by state, and counts them in checks.
+func handleServerState(checks *int32, state func() *datatypes.DataValue) func(services.Service) services.Service {
+	return handleRead(func(req *services.ReadRequest) *datatypes.DataValue {
+		if req.NodesToRead.ReadValueIDs[0].NodeID.Equal(datatypes.NewNumericNodeID(0, id.Server_ServerStatus_State)) {
+			atomic.AddInt32(checks, 1)
+		}
+		return state()
+	})
+}
+
+func TestClientKeepAlive(t *testing.T) {
+	t.Run("healthy", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		var checks int32
+		failed := make(chan error, 1)
+		c, err := setUpClient(ctx, handleServerState(&checks, func() *datatypes.DataValue {
+			return newValue(datatypes.NewInt32(0))
+		}), WithKeepAlive(10*time.Millisecond), WithKeepAliveCallback(func(err error) {
+			failed <- err
+		}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer c.Close()
+
+		deadline := time.Now().Add(time.Second)
+		for atomic.LoadInt32(&checks) < 3 {
+			if time.Now().After(deadline) {
+				t.Fatalf("got %d health checks want 3", atomic.LoadInt32(&checks))
+			}
+			time.Sleep(5 * time.Millisecond)
+		}
+		select {
+		case err := <-failed:
+			t.Fatalf("health check failed: %s", err)
+		default:
+		}
+		if !c.Healthy() {
+			t.Error("got unhealthy want healthy")
+		}
+	})
+
+	t.Run("failing", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		var checks int32
+		failed := make(chan error, 1)
+		c, err := setUpClient(ctx, handleServerState(&checks, func() *datatypes.DataValue {
+			return datatypes.NewDataValue(
+				false, true, false, false, false, false,
+				nil, status.BadNodeIdUnknown, time.Time{}, 0, time.Time{}, 0,
+			)
+		}), WithKeepAlive(10*time.Millisecond), WithKeepAliveCallback(func(err error) {
+			select {
+			case failed <- err:
+			default:
+			}
+		}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer c.Close()
+
+		select {
+		case err := <-failed:
+			if err == nil {
+				t.Error("got nil error")
+			}
+		case <-time.After(time.Second):
+			t.Fatal("callback not called")
+		}
+		if c.Healthy() {
+			t.Error("got healthy want unhealthy")
+		}
+	})
+
+	t.Run("not-running", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		var checks int32
+		failed := make(chan error, 1)
+		// 4 is Shutdown.
+		c, err := setUpClient(ctx, handleServerState(&checks, func() *datatypes.DataValue {
+			return newValue(datatypes.NewInt32(4))
+		}), WithKeepAlive(10*time.Millisecond), WithKeepAliveCallback(func(err error) {
+			select {
+			case failed <- err:
+			default:
+			}
+		}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer c.Close()
+
+		select {
+		case <-failed:
+		case <-time.After(time.Second):
+			t.Fatal("callback not called")
+		}
+	})
+
+	t.Run("reconnect", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		var checks int32
+		states := make(chan ConnState, 8)
+		c, err := setUpClient(ctx, handleServerState(&checks, func() *datatypes.DataValue {
+			return newValue(datatypes.NewInt32(4))
+		}), WithKeepAlive(10*time.Millisecond), WithReconnect(ExponentialBackoff(time.Millisecond, time.Millisecond, 1)),
+			WithStateCallback(func(s ConnState) {
+				select {
+				case states <- s:
+				default:
+				}
+			}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer c.Close()
+
+		// the connection is closed by the failing health check.
+		for {
+			select {
+			case s := <-states:
+				if s == StateDisconnected {
+					return
+				}
+			case <-time.After(time.Second):
+				t.Fatal("connection not closed")
+			}
+		}
+	})
+}
@@ -0,0 +1,53 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package gopcua
+
+import (
+	"github.com/wmnsk/gopcua/uasc"
+	"github.com/wmnsk/gopcua/utils"
+)
+
+// Logger logs the events in the Client and the underlying SecureChannel and Session,
+// e.g. the MessageChunks sent and received, the renewals of the SecureChannel and
+// the publish cycles of the Subscriptions. It is the same as uasc.Logger.
+type Logger = uasc.Logger
+
+// WithLogger sets the Logger of the Client, which overrides the one in the Config
+// of SecureChannel. Nothing is logged by default.
+func WithLogger(l Logger) Option {
+	return func(c *Client) {
+		c.logger = l
+	}
+}
+
+// nopLogger is the Logger used if no Logger is set, which discards everything.
+type nopLogger struct{}
+
+func (nopLogger) Debug(string, ...interface{}) {}
+func (nopLogger) Info(string, ...interface{})  {}
+func (nopLogger) Warn(string, ...interface{})  {}
+func (nopLogger) Error(string, ...interface{}) {}
+
+// log returns the Logger of the Client.
+func (c *Client) log() Logger {
+	if c.logger != nil {
+		return c.logger
+	}
+	if c.cfg != nil && c.cfg.Logger != nil {
+		return c.cfg.Logger
+	}
+	return nopLogger{}
+}
+
+// dumpMessage renders the message with utils.Dump when it is formatted, so that
+// the messages are only rendered if the Logger actually logs them at debug level.
+type dumpMessage struct {
+	msg interface{}
+}
+
+// String returns the message rendered by utils.Dump.
+func (d dumpMessage) String() string {
+	return utils.Dump(d.msg)
+}
@@ -0,0 +1,125 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package gopcua
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/wmnsk/gopcua/datatypes"
+	"github.com/wmnsk/gopcua/services"
+)
+
+// fakeLogger records the messages logged for each level.
+type fakeLogger struct {
+	mu   sync.Mutex
+	logs map[string][]string
+	odd  []string
+
+	// messages are the messages rendered for the "message" keys.
+	messages []string
+}
+
+func (l *fakeLogger) log(level, msg string, keyvals []interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.logs == nil {
+		l.logs = map[string][]string{}
+	}
+	l.logs[level] = append(l.logs[level], msg)
+	if len(keyvals)%2 != 0 {
+		l.odd = append(l.odd, msg)
+		return
+	}
+	for i := 0; i < len(keyvals); i += 2 {
+		if keyvals[i] == "message" {
+			l.messages = append(l.messages, fmt.Sprint(keyvals[i+1]))
+		}
+	}
+}
+
+func (l *fakeLogger) Debug(msg string, keyvals ...interface{}) { l.log("debug", msg, keyvals) }
+func (l *fakeLogger) Info(msg string, keyvals ...interface{})  { l.log("info", msg, keyvals) }
+func (l *fakeLogger) Warn(msg string, keyvals ...interface{})  { l.log("warn", msg, keyvals) }
+func (l *fakeLogger) Error(msg string, keyvals ...interface{}) { l.log("error", msg, keyvals) }
+
+func (l *fakeLogger) has(level, msg string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, m := range l.logs[level] {
+		if m == msg {
+			return true
+		}
+	}
+	return false
+}
+
+func TestClientLogger(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	l := &fakeLogger{}
+	c, err := setUpClient(ctx, func(services.Service) services.Service { return nil }, WithLogger(l))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	for _, want := range []struct{ level, msg string }{
+		{"debug", "sending chunk"},
+		{"debug", "received chunk"},
+		{"info", "secure channel opened"},
+		{"info", "session activated"},
+	} {
+		if !l.has(want.level, want.msg) {
+			t.Errorf("%q is not logged at %s", want.msg, want.level)
+		}
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if len(l.odd) != 0 {
+		t.Errorf("logged without values for the keys: %v", l.odd)
+	}
+}
+
+func TestClientLoggerDump(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	l := &fakeLogger{}
+	c, err := setUpClient(ctx, handleRead(func(req *services.ReadRequest) *datatypes.DataValue {
+		return newValue(datatypes.NewInt32(42))
+	}), WithLogger(l))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	node := datatypes.NewReadValueID(datatypes.NewStringNodeID(2, "foo"), datatypes.AttributeIDValue, "", 0, "")
+	if _, err := c.Read(0, services.TimestampsToReturnBoth, node); err != nil {
+		t.Fatal(err)
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, want := range []string{"NodeID: ns=2;s=foo", "Value: Variant(Int32) 42"} {
+		var found bool
+		for _, m := range l.messages {
+			if strings.Contains(m, want) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("%q is not logged in the messages: %v", want, l.messages)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package gopcua
+
+import (
+	"context"
+
+	"github.com/wmnsk/gopcua/datatypes"
+	"github.com/wmnsk/gopcua/errors"
+	"github.com/wmnsk/gopcua/id"
+	"github.com/wmnsk/gopcua/services"
+	"github.com/wmnsk/gopcua/status"
+)
+
+// NamespaceIndex returns the index of the NamespaceURI in the NamespaceArray of the server.
+// The second return value is false if the uri is unknown.
+//
+// The NamespaceArray is read once when the Session is activated, and can be read again
+// with UpdateNamespaces if the server adds namespaces later.
+func (c *Client) NamespaceIndex(uri string) (uint16, bool) {
+	c.nsMu.Lock()
+	defer c.nsMu.Unlock()
+
+	ns, ok := c.nsIndex[uri]
+	return ns, ok
+}
+
+// NamespaceURI returns the NamespaceURI at the index in the NamespaceArray of the server.
+// The second return value is false if the index is out of the NamespaceArray.
+func (c *Client) NamespaceURI(index uint16) (string, bool) {
+	c.nsMu.Lock()
+	defer c.nsMu.Unlock()
+
+	if int(index) >= len(c.namespaces) {
+		return "", false
+	}
+	return c.namespaces[index], true
+}
+
+// Namespaces returns a copy of the NamespaceArray of the server read by the Client.
+func (c *Client) Namespaces() []string {
+	c.nsMu.Lock()
+	defer c.nsMu.Unlock()
+
+	return append([]string{}, c.namespaces...)
+}
+
+// ToNodeID returns the NodeID of the ExpandedNodeID, resolving its NamespaceURI
+// with the NamespaceArray of the server. See datatypes.ExpandedNodeID.ToNodeID.
+func (c *Client) ToNodeID(e *datatypes.ExpandedNodeID) (*datatypes.NodeID, error) {
+	c.nsMu.Lock()
+	table := c.nsIndex
+	c.nsMu.Unlock()
+
+	return e.ToNodeID(table)
+}
+
+// ToExpandedNodeID returns the ExpandedNodeID of the NodeID with the NamespaceURI
+// of its namespace index in the NamespaceArray of the server.
+// See datatypes.NodeID.ToExpandedNodeID.
+func (c *Client) ToExpandedNodeID(n *datatypes.NodeID) *datatypes.ExpandedNodeID {
+	c.nsMu.Lock()
+	namespaces := c.namespaces
+	c.nsMu.Unlock()
+
+	return n.ToExpandedNodeID(namespaces)
+}
+
+// UpdateNamespaces reads the NamespaceArray of the server again and replaces
+// the one cached by the Client.
+func (c *Client) UpdateNamespaces() error {
+	return c.UpdateNamespacesWithContext(context.Background())
+}
+
+// UpdateNamespacesWithContext is the same as UpdateNamespaces but returns ctx.Err()
+// if ctx is done before the NamespaceArray is read.
+func (c *Client) UpdateNamespacesWithContext(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.session == nil {
+		return ErrNotConnected
+	}
+	return c.readNamespaces(ctx)
+}
+
+// updateNamespaces reads the NamespaceArray on activation of the Session. The error is
+// only logged as the Client can be used without it, keeping the ones read before.
+//
+// This should be called with c.mu held.
+func (c *Client) updateNamespaces(ctx context.Context) {
+	if err := c.readNamespaces(ctx); err != nil {
+		c.log().Warn("failed to read NamespaceArray", "error", err)
+	}
+}
+
+// readNamespaces reads the NamespaceArray of the server and caches it
+// with the index of each NamespaceURI.
+//
+// This should be called with c.mu held.
+func (c *Client) readNamespaces(ctx context.Context) error {
+	node := datatypes.NewReadValueID(datatypes.NewNumericNodeID(0, id.Server_NamespaceArray), datatypes.AttributeIDValue, "", 0, "")
+	h := c.requestHeader()
+	res, err := c.send(ctx, services.NewReadRequest(h, 0, services.TimestampsToReturnNeither, node), h.RequestHandle)
+	if err != nil {
+		return err
+	}
+
+	r, ok := res.(*services.ReadResponse)
+	if !ok {
+		return errors.NewErrInvalidType(res, "read", "should be ReadResponse")
+	}
+	if r.Results == nil || len(r.Results.DataValues) != 1 {
+		return errors.New("read of NamespaceArray returned no value")
+	}
+	dv := r.Results.DataValues[0]
+	if dv.HasStatus() && dv.Status != 0 {
+		return errors.Errorf("read of NamespaceArray failed with status %v", status.StatusCode(dv.Status))
+	}
+	v := dv.Value
+	if v == nil || !v.HasArrayValues() || v.Type() != id.String {
+		return errors.New("NamespaceArray should be an array of String")
+	}
+
+	namespaces := make([]string, len(v.Values))
+	index := make(map[string]uint16, len(v.Values))
+	for i, val := range v.Values {
+		s, ok := val.(*datatypes.String)
+		if !ok {
+			return errors.NewErrInvalidType(val, "read", "should be String in NamespaceArray")
+		}
+		namespaces[i] = s.Get()
+		index[s.Get()] = uint16(i)
+	}
+
+	c.nsMu.Lock()
+	c.namespaces, c.nsIndex = namespaces, index
+	c.nsMu.Unlock()
+	return nil
+}
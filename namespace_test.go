@@ -0,0 +1,112 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package gopcua
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/wmnsk/gopcua/datatypes"
+	"github.com/wmnsk/gopcua/id"
+	"github.com/wmnsk/gopcua/services"
+	"github.com/wmnsk/gopcua/uacp"
+)
+
+func TestClientNamespaces(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ln, err := uacp.Listen(endpoint, 0xffff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	// the server counts the reads of the NamespaceArray.
+	var mu sync.Mutex
+	namespaces := []string{"http://opcfoundation.org/UA/", "urn:gopcua:foo", "urn:gopcua:bar"}
+	var reads int32
+	srv := NewServer(endpoint, nil, ReadHandlerFunc(func(ctx context.Context, req *services.ReadRequest) ([]*datatypes.DataValue, error) {
+		n := req.NodesToRead.ReadValueIDs[0].NodeID
+		if !n.Equal(datatypes.NewNumericNodeID(0, id.Server_NamespaceArray)) {
+			return nil, errors.New("unknown node")
+		}
+		atomic.AddInt32(&reads, 1)
+
+		mu.Lock()
+		defer mu.Unlock()
+		var values []datatypes.Data
+		for _, ns := range namespaces {
+			values = append(values, datatypes.NewString(ns))
+		}
+		return []*datatypes.DataValue{datatypes.NewDataValue(
+			true, false, false, false, false, false,
+			datatypes.NewArrayVariant(id.String, values...), 0, time.Time{}, 0, time.Time{}, 0,
+		)}, nil
+	}))
+	go srv.Serve(ctx, ln)
+
+	c := NewClient(endpoint)
+	if err := c.Connect(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	for i, uri := range namespaces[:3] {
+		ns, ok := c.NamespaceIndex(uri)
+		if !ok || int(ns) != i {
+			t.Errorf("NamespaceIndex(%q): got %d, %v want %d", uri, ns, ok, i)
+		}
+		got, ok := c.NamespaceURI(uint16(i))
+		if !ok || got != uri {
+			t.Errorf("NamespaceURI(%d): got %q, %v want %q", i, got, ok, uri)
+		}
+	}
+	if _, ok := c.NamespaceIndex("urn:gopcua:unknown"); ok {
+		t.Error("unknown uri should not be found")
+	}
+	if _, ok := c.NamespaceURI(3); ok {
+		t.Error("index out of the NamespaceArray should not be found")
+	}
+
+	e := datatypes.NewStringExpandedNodeIDWithURI("urn:gopcua:bar", "Temperature")
+	n, err := c.ToNodeID(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(n.String(), datatypes.NewStringNodeID(2, "Temperature").String()); diff != "" {
+		t.Error(diff)
+	}
+	if got := c.ToExpandedNodeID(datatypes.NewStringNodeID(1, "Pressure")); got.URI() != "urn:gopcua:foo" || got.NodeID.Namespace() != 0 {
+		t.Errorf("got %v with uri %q", got.NodeID, got.URI())
+	}
+	if got := c.ToExpandedNodeID(datatypes.NewStringNodeID(5, "Pressure")); got.URI() != "" || got.NodeID.Namespace() != 5 {
+		t.Errorf("got %v with uri %q", got.NodeID, got.URI())
+	}
+
+	if got := atomic.LoadInt32(&reads); got != 1 {
+		t.Errorf("NamespaceArray was read %d times, want 1", got)
+	}
+
+	// the server adds a namespace, which is found after UpdateNamespaces.
+	mu.Lock()
+	namespaces = append(namespaces, "urn:gopcua:baz")
+	mu.Unlock()
+	if _, ok := c.NamespaceIndex("urn:gopcua:baz"); ok {
+		t.Error("new namespace should not be found before UpdateNamespaces")
+	}
+	if err := c.UpdateNamespaces(); err != nil {
+		t.Fatal(err)
+	}
+	if ns, ok := c.NamespaceIndex("urn:gopcua:baz"); !ok || ns != 3 {
+		t.Errorf("got %d, %v want 3", ns, ok)
+	}
+}
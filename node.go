@@ -0,0 +1,139 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package gopcua
+
+import (
+	"context"
+	"sync"
+
+	"github.com/wmnsk/gopcua/datatypes"
+	"github.com/wmnsk/gopcua/errors"
+)
+
+// Node is a handle of a Node in the address space of the server, which reads
+// its attributes and browses its References on demand.
+//
+// The NodeClass and the BrowseName are cached after they are read once as they
+// do not change, while the others are read from the server on every call.
+type Node struct {
+	ID *datatypes.NodeID
+	c  *Client
+
+	mu         sync.Mutex
+	nodeClass  *datatypes.NodeClass
+	browseName *datatypes.QualifiedName
+}
+
+// Node returns a handle of the Node with id. No request is sent until
+// the methods of the Node are called.
+func (c *Client) Node(id *datatypes.NodeID) *Node {
+	return &Node{ID: id, c: c}
+}
+
+// NodeClass returns the NodeClass of the Node.
+func (n *Node) NodeClass() (datatypes.NodeClass, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.nodeClass != nil {
+		return *n.nodeClass, nil
+	}
+
+	v, err := n.c.readAttribute(context.Background(), n.ID, datatypes.AttributeIDNodeClass)
+	if err != nil {
+		return datatypes.NodeClassUnspecified, err
+	}
+	i, ok := v.Int32()
+	if !ok {
+		return datatypes.NodeClassUnspecified, errors.Errorf("read returned %T, not NodeClass", v.Value)
+	}
+
+	nc := datatypes.NodeClass(i)
+	n.nodeClass = &nc
+	return nc, nil
+}
+
+// BrowseName returns the BrowseName of the Node.
+func (n *Node) BrowseName() (*datatypes.QualifiedName, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.browseName != nil {
+		return n.browseName, nil
+	}
+
+	v, err := n.c.readAttribute(context.Background(), n.ID, datatypes.AttributeIDBrowseName)
+	if err != nil {
+		return nil, err
+	}
+	q, ok := v.QualifiedName()
+	if !ok {
+		return nil, errors.Errorf("read returned %T, not QualifiedName", v.Value)
+	}
+
+	n.browseName = q
+	return q, nil
+}
+
+// DisplayName returns the DisplayName of the Node in the locale of the Session.
+func (n *Node) DisplayName() (*datatypes.LocalizedText, error) {
+	v, err := n.c.readAttribute(context.Background(), n.ID, datatypes.AttributeIDDisplayName)
+	if err != nil {
+		return nil, err
+	}
+	l, ok := v.LocalizedText()
+	if !ok {
+		return nil, errors.Errorf("read returned %T, not LocalizedText", v.Value)
+	}
+	return l, nil
+}
+
+// Value returns the value of the Node, which should be a Variable.
+func (n *Node) Value() (*datatypes.Variant, error) {
+	return n.c.readAttribute(context.Background(), n.ID, datatypes.AttributeIDValue)
+}
+
+// ValueRange returns the part of the array or the matrix value of the Node in the NumericRange
+// given, e.g. "2:5" for the elements from 2 to 5 or "2:5,0:1" for a sub-matrix.
+// The server returns only the elements in the range. See datatypes.ParseNumericRange.
+func (n *Node) ValueRange(indexRange string) (*datatypes.Variant, error) {
+	r, err := datatypes.ParseIndexRange(indexRange)
+	if err != nil {
+		return nil, err
+	}
+	return n.c.readValueID(context.Background(), datatypes.NewReadValueID(n.ID, datatypes.AttributeIDValue, r, 0, ""))
+}
+
+// Children returns the Nodes which are the targets of the forward HierarchicalReferences
+// of the Node, including their subtypes.
+//
+// The NodeClass and the BrowseName returned by Browse are cached in the children.
+// The targets in other servers are skipped as they cannot be accessed with the Client.
+func (n *Node) Children() ([]*Node, error) {
+	refs, err := n.c.BrowseChildren(n.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	var children []*Node
+	for _, ref := range refs {
+		if ref.NodeID == nil || (ref.NodeID.HasServerIndex() && ref.NodeID.ServerIndex != 0) {
+			continue
+		}
+		id, err := n.c.ToNodeID(ref.NodeID)
+		if err != nil {
+			return nil, err
+		}
+
+		child := n.c.Node(id)
+		if ref.NodeClass != datatypes.NodeClassUnspecified {
+			nc := ref.NodeClass
+			child.nodeClass = &nc
+		}
+		child.browseName = ref.BrowseName
+		children = append(children, child)
+	}
+	return children, nil
+}
@@ -0,0 +1,200 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package gopcua
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/wmnsk/gopcua/datatypes"
+	"github.com/wmnsk/gopcua/id"
+	"github.com/wmnsk/gopcua/services"
+	"github.com/wmnsk/gopcua/status"
+)
+
+func TestNode(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// the server has a Variable with a Float value under the Objects folder.
+	objects := datatypes.NewFourByteNodeID(0, 85)
+	temp := datatypes.NewStringNodeID(2, "Temperature")
+
+	var mu sync.Mutex
+	reads := map[datatypes.AttributeID]int{}
+	var browsed []*datatypes.NodeID
+	c, err := setUpClient(ctx, func(srv services.Service) services.Service {
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch req := srv.(type) {
+		case *services.ReadRequest:
+			r := req.NodesToRead.ReadValueIDs[0]
+			reads[r.AttributeID]++
+
+			var v datatypes.Data
+			switch r.AttributeID {
+			case datatypes.AttributeIDNodeClass:
+				v = datatypes.NewInt32(int32(datatypes.NodeClassObject))
+			case datatypes.AttributeIDBrowseName:
+				v = datatypes.NewQualifiedName(0, "Objects")
+			case datatypes.AttributeIDDisplayName:
+				v = datatypes.NewLocalizedText("en-US", "Objects")
+			case datatypes.AttributeIDValue:
+				v = datatypes.NewFloat(21.5)
+			}
+			return services.NewReadResponse(newResponseHeader(req.RequestHandle), nil, newValue(v))
+		case *services.BrowseRequest:
+			browsed = append(browsed, req.NodesToBrowse.BrowseDescriptions[0].NodeID)
+			return services.NewBrowseResponse(newResponseHeader(req.RequestHandle), nil, datatypes.NewBrowseResult(
+				0, nil,
+				datatypes.NewReferenceDescription(
+					datatypes.NewFourByteNodeID(0, 47), true,
+					&datatypes.ExpandedNodeID{NodeID: temp},
+					datatypes.NewQualifiedName(2, "Temperature"),
+					datatypes.NewLocalizedText("", "Temperature"),
+					datatypes.NodeClassVariable,
+					datatypes.NewFourByteExpandedNodeID(0, 63),
+				),
+				datatypes.NewReferenceDescription(
+					datatypes.NewFourByteNodeID(0, 35), true,
+					datatypes.NewExpandedNodeIDWithServer(datatypes.NewStringNodeID(1, "remote"), 1),
+					datatypes.NewQualifiedName(1, "Remote"),
+					datatypes.NewLocalizedText("", "Remote"),
+					datatypes.NodeClassObject,
+					datatypes.NewFourByteExpandedNodeID(0, 61),
+				),
+			))
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	n := c.Node(objects)
+
+	// NodeClass and BrowseName are read only once.
+	for i := 0; i < 2; i++ {
+		nc, err := n.NodeClass()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if nc != datatypes.NodeClassObject {
+			t.Errorf("got NodeClass %d want %d", nc, datatypes.NodeClassObject)
+		}
+
+		name, err := n.BrowseName()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(name, datatypes.NewQualifiedName(0, "Objects")); diff != "" {
+			t.Error(diff)
+		}
+	}
+
+	dn, err := n.DisplayName()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(dn, datatypes.NewLocalizedText("en-US", "Objects")); diff != "" {
+		t.Error(diff)
+	}
+
+	children, err := n.Children()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(children) != 1 {
+		t.Fatalf("got %d children want 1", len(children))
+	}
+	child := children[0]
+	if diff := cmp.Diff(child.ID, temp); diff != "" {
+		t.Error(diff)
+	}
+
+	// the attributes from Browse are cached in the child.
+	if nc, err := child.NodeClass(); err != nil || nc != datatypes.NodeClassVariable {
+		t.Errorf("got %d, %v want %d, nil", nc, err, datatypes.NodeClassVariable)
+	}
+
+	v, err := child.Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(v, datatypes.NewVariant(datatypes.NewFloat(21.5))); diff != "" {
+		t.Error(diff)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	want := map[datatypes.AttributeID]int{
+		datatypes.AttributeIDNodeClass:   1,
+		datatypes.AttributeIDBrowseName:  1,
+		datatypes.AttributeIDDisplayName: 1,
+		datatypes.AttributeIDValue:       1,
+	}
+	if diff := cmp.Diff(reads, want); diff != "" {
+		t.Error(diff)
+	}
+	if diff := cmp.Diff(browsed, []*datatypes.NodeID{objects}); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestNodeValueRange(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// the server returns the elements of the array in the IndexRange requested.
+	values := []datatypes.Data{}
+	for i := int32(0); i < 10; i++ {
+		values = append(values, datatypes.NewInt32(i))
+	}
+	ranges := make(chan string, 1)
+	c, err := setUpClient(ctx, func(srv services.Service) services.Service {
+		req, ok := srv.(*services.ReadRequest)
+		if !ok {
+			return nil
+		}
+		idx := req.NodesToRead.ReadValueIDs[0].IndexRange.Get()
+		ranges <- idx
+		r, err := datatypes.ParseNumericRange(idx)
+		if err != nil {
+			return services.NewReadResponse(newResponseHeader(req.RequestHandle), nil, datatypes.NewDataValue(
+				false, true, false, false, false, false, nil, status.BadIndexRangeInvalid, time.Time{}, 0, time.Time{}, 0,
+			))
+		}
+		v := datatypes.NewArrayVariant(id.Int32, values[r[0].Low:r[0].High+1]...)
+		return services.NewReadResponse(newResponseHeader(req.RequestHandle), nil, datatypes.NewDataValue(
+			true, false, false, false, false, false, v, 0, time.Time{}, 0, time.Time{}, 0,
+		))
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	v, err := c.Node(datatypes.NewStringNodeID(2, "MyArray")).ValueRange("02:5")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := <-ranges; got != "2:5" {
+		t.Errorf("got IndexRange %q want %q", got, "2:5")
+	}
+	if diff := cmp.Diff(v, datatypes.NewArrayVariant(id.Int32, values[2:6]...)); diff != "" {
+		t.Error(diff)
+	}
+
+	// the invalid range is not sent.
+	if _, err := c.Node(datatypes.NewStringNodeID(2, "MyArray")).ValueRange("5:2"); err == nil {
+		t.Error("expected error for the invalid range, got nil")
+	}
+}
@@ -0,0 +1,151 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package gopcua
+
+import (
+	"context"
+
+	"github.com/wmnsk/gopcua/datatypes"
+	"github.com/wmnsk/gopcua/errors"
+	"github.com/wmnsk/gopcua/services"
+)
+
+// AddNodes adds the Nodes to the AddressSpace of the server and returns the AddNodesResult
+// for each of the items in the same order. The items can be created with datatypes.NewAddNodesItem
+// and the NodeAttributes of the NodeClass, e.g. datatypes.NewVariableAttributes.
+//
+// The error is only returned if the AddNodesRequest fails as a whole. The StatusCode of each
+// AddNodesResult should be checked to know if the Node is added with the AddedNodeID.
+func (c *Client) AddNodes(items []*datatypes.AddNodesItem) ([]*datatypes.AddNodesResult, error) {
+	return c.AddNodesWithContext(context.Background(), items)
+}
+
+// AddNodesWithContext is the same as AddNodes but returns ctx.Err() if ctx is done
+// before the AddNodesResponse arrives.
+func (c *Client) AddNodesWithContext(ctx context.Context, items []*datatypes.AddNodesItem) ([]*datatypes.AddNodesResult, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.session == nil {
+		return nil, ErrNotConnected
+	}
+
+	h := c.requestHeader()
+	res, err := c.send(ctx, services.NewAddNodesRequest(h, items...), h.RequestHandle)
+	if err != nil {
+		return nil, err
+	}
+
+	r, ok := res.(*services.AddNodesResponse)
+	if !ok {
+		return nil, errors.NewErrInvalidType(res, "add nodes", "should be AddNodesResponse")
+	}
+	if r.Results == nil || len(r.Results.Results) != len(items) {
+		return nil, errors.New("add nodes returned unexpected number of results")
+	}
+	return r.Results.Results, nil
+}
+
+// DeleteNodes deletes the Nodes from the AddressSpace of the server and returns the StatusCode
+// for each of the items in the same order.
+//
+// As with AddNodes, the error is only returned if the DeleteNodesRequest fails as a whole.
+func (c *Client) DeleteNodes(items []*datatypes.DeleteNodesItem) ([]uint32, error) {
+	return c.DeleteNodesWithContext(context.Background(), items)
+}
+
+// DeleteNodesWithContext is the same as DeleteNodes but returns ctx.Err() if ctx is done
+// before the DeleteNodesResponse arrives.
+func (c *Client) DeleteNodesWithContext(ctx context.Context, items []*datatypes.DeleteNodesItem) ([]uint32, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.session == nil {
+		return nil, ErrNotConnected
+	}
+
+	h := c.requestHeader()
+	res, err := c.send(ctx, services.NewDeleteNodesRequest(h, items...), h.RequestHandle)
+	if err != nil {
+		return nil, err
+	}
+
+	r, ok := res.(*services.DeleteNodesResponse)
+	if !ok {
+		return nil, errors.NewErrInvalidType(res, "delete nodes", "should be DeleteNodesResponse")
+	}
+	if r.Results == nil || len(r.Results.Values) != len(items) {
+		return nil, errors.New("delete nodes returned unexpected number of results")
+	}
+	return r.Results.Values, nil
+}
+
+// AddReferences adds the References between the Nodes of the server and returns the StatusCode
+// for each of the items in the same order. The items can be created with datatypes.NewAddReferencesItem.
+//
+// As with AddNodes, the error is only returned if the AddReferencesRequest fails as a whole.
+func (c *Client) AddReferences(items []*datatypes.AddReferencesItem) ([]uint32, error) {
+	return c.AddReferencesWithContext(context.Background(), items)
+}
+
+// AddReferencesWithContext is the same as AddReferences but returns ctx.Err() if ctx is done
+// before the AddReferencesResponse arrives.
+func (c *Client) AddReferencesWithContext(ctx context.Context, items []*datatypes.AddReferencesItem) ([]uint32, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.session == nil {
+		return nil, ErrNotConnected
+	}
+
+	h := c.requestHeader()
+	res, err := c.send(ctx, services.NewAddReferencesRequest(h, items...), h.RequestHandle)
+	if err != nil {
+		return nil, err
+	}
+
+	r, ok := res.(*services.AddReferencesResponse)
+	if !ok {
+		return nil, errors.NewErrInvalidType(res, "add references", "should be AddReferencesResponse")
+	}
+	if r.Results == nil || len(r.Results.Values) != len(items) {
+		return nil, errors.New("add references returned unexpected number of results")
+	}
+	return r.Results.Values, nil
+}
+
+// DeleteReferences deletes the References between the Nodes of the server and returns the StatusCode
+// for each of the items in the same order.
+//
+// As with AddNodes, the error is only returned if the DeleteReferencesRequest fails as a whole.
+func (c *Client) DeleteReferences(items []*datatypes.DeleteReferencesItem) ([]uint32, error) {
+	return c.DeleteReferencesWithContext(context.Background(), items)
+}
+
+// DeleteReferencesWithContext is the same as DeleteReferences but returns ctx.Err() if ctx is done
+// before the DeleteReferencesResponse arrives.
+func (c *Client) DeleteReferencesWithContext(ctx context.Context, items []*datatypes.DeleteReferencesItem) ([]uint32, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.session == nil {
+		return nil, ErrNotConnected
+	}
+
+	h := c.requestHeader()
+	res, err := c.send(ctx, services.NewDeleteReferencesRequest(h, items...), h.RequestHandle)
+	if err != nil {
+		return nil, err
+	}
+
+	r, ok := res.(*services.DeleteReferencesResponse)
+	if !ok {
+		return nil, errors.NewErrInvalidType(res, "delete references", "should be DeleteReferencesResponse")
+	}
+	if r.Results == nil || len(r.Results.Values) != len(items) {
+		return nil, errors.New("delete references returned unexpected number of results")
+	}
+	return r.Results.Values, nil
+}
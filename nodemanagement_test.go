@@ -0,0 +1,190 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package gopcua
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/wmnsk/gopcua/datatypes"
+	"github.com/wmnsk/gopcua/id"
+	"github.com/wmnsk/gopcua/services"
+	"github.com/wmnsk/gopcua/status"
+)
+
+func TestClientAddNodes(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// the server adds the Variables under the Objects folder, and rejects the others.
+	c, err := setUpClient(ctx, func(srv services.Service) services.Service {
+		req, ok := srv.(*services.AddNodesRequest)
+		if !ok {
+			return nil
+		}
+		var results []*datatypes.AddNodesResult
+		for _, item := range req.NodesToAdd.Items {
+			attrs, ok := item.NodeAttributes.Value.(*datatypes.VariableAttributes)
+			if !ok || item.NodeClass != datatypes.NodeClassVariable || !item.ParentNodeID.NodeID.Equal(datatypes.NewNumericNodeID(0, id.ObjectsFolder)) {
+				results = append(results, datatypes.NewAddNodesResult(status.BadNodeAttributesInvalid, datatypes.NewTwoByteNodeID(0)))
+				continue
+			}
+			if v, ok := attrs.Value.Value.(*datatypes.Int32); !ok || v.Value != 42 {
+				results = append(results, datatypes.NewAddNodesResult(status.BadTypeMismatch, datatypes.NewTwoByteNodeID(0)))
+				continue
+			}
+			results = append(results, datatypes.NewAddNodesResult(0, datatypes.NewStringNodeID(2, item.BrowseName.Name.Get())))
+		}
+		return services.NewAddNodesResponse(newResponseHeader(req.RequestHandle), nil, results...)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	objects := datatypes.NewFourByteExpandedNodeID(0, id.ObjectsFolder)
+	hasComponent := datatypes.NewFourByteNodeID(0, id.HasComponent)
+	results, err := c.AddNodes([]*datatypes.AddNodesItem{
+		datatypes.NewAddNodesItem(
+			objects, hasComponent, nil, datatypes.NewQualifiedName(2, "Temperature"),
+			datatypes.NewVariableAttributes(
+				datatypes.NewLocalizedText("", "Temperature"), nil,
+				datatypes.NewVariant(datatypes.NewInt32(42)), datatypes.NewTwoByteNodeID(id.Int32), -1, 3,
+			),
+			datatypes.NewFourByteExpandedNodeID(0, id.BaseDataVariableType),
+		),
+		datatypes.NewAddNodesItem(
+			objects, hasComponent, nil, datatypes.NewQualifiedName(2, "Device"),
+			datatypes.NewObjectAttributes(nil, nil, 0),
+			datatypes.NewFourByteExpandedNodeID(0, id.BaseObjectType),
+		),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []*datatypes.AddNodesResult{
+		datatypes.NewAddNodesResult(0, datatypes.NewStringNodeID(2, "Temperature")),
+		datatypes.NewAddNodesResult(status.BadNodeAttributesInvalid, datatypes.NewTwoByteNodeID(0)),
+	}
+	if diff := cmp.Diff(results, want); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestClientDeleteNodes(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	c, err := setUpClient(ctx, func(srv services.Service) services.Service {
+		req, ok := srv.(*services.DeleteNodesRequest)
+		if !ok {
+			return nil
+		}
+		var codes []uint32
+		for _, item := range req.NodesToDelete.Items {
+			if !item.NodeID.Equal(datatypes.NewStringNodeID(2, "Temperature")) {
+				codes = append(codes, status.BadNodeIdUnknown)
+				continue
+			}
+			codes = append(codes, 0)
+		}
+		return services.NewDeleteNodesResponse(newResponseHeader(req.RequestHandle), nil, codes...)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	codes, err := c.DeleteNodes([]*datatypes.DeleteNodesItem{
+		datatypes.NewDeleteNodesItem(datatypes.NewStringNodeID(2, "Temperature"), true),
+		datatypes.NewDeleteNodesItem(datatypes.NewStringNodeID(2, "foo"), false),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(codes, []uint32{0, status.BadNodeIdUnknown}); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestClientReferences(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// the server keeps the Organizes References by the source and the target.
+	var mu sync.Mutex
+	refs := map[string]bool{}
+	key := func(source *datatypes.NodeID, target *datatypes.ExpandedNodeID) string {
+		return source.String() + " " + target.String()
+	}
+	organizes := datatypes.NewFourByteNodeID(0, id.Organizes)
+	c, err := setUpClient(ctx, func(srv services.Service) services.Service {
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch req := srv.(type) {
+		case *services.AddReferencesRequest:
+			var codes []uint32
+			for _, item := range req.ReferencesToAdd.Items {
+				if !item.ReferenceTypeID.Equal(organizes) || item.IsForward.Value == 0 {
+					codes = append(codes, status.BadReferenceTypeIdInvalid)
+					continue
+				}
+				refs[key(item.SourceNodeID, item.TargetNodeID)] = true
+				codes = append(codes, 0)
+			}
+			return services.NewAddReferencesResponse(newResponseHeader(req.RequestHandle), nil, codes...)
+		case *services.DeleteReferencesRequest:
+			var codes []uint32
+			for _, item := range req.ReferencesToDelete.Items {
+				k := key(item.SourceNodeID, item.TargetNodeID)
+				if !refs[k] {
+					codes = append(codes, status.BadNotFound)
+					continue
+				}
+				delete(refs, k)
+				codes = append(codes, 0)
+			}
+			return services.NewDeleteReferencesResponse(newResponseHeader(req.RequestHandle), nil, codes...)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	source := datatypes.NewStringNodeID(2, "Device")
+	target := datatypes.NewExpandedNodeIDWithServer(datatypes.NewStringNodeID(2, "Temperature"), 1)
+	codes, err := c.AddReferences([]*datatypes.AddReferencesItem{
+		datatypes.NewAddReferencesItem(source, organizes, true, target, datatypes.NodeClassVariable),
+		datatypes.NewAddReferencesItem(source, organizes, false, target, datatypes.NodeClassVariable),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(codes, []uint32{0, status.BadReferenceTypeIdInvalid}); diff != "" {
+		t.Error(diff)
+	}
+
+	mu.Lock()
+	if !refs[key(source, target)] {
+		t.Errorf("reference to %s is not added: %v", target, refs)
+	}
+	mu.Unlock()
+
+	// the second one is already deleted by the first.
+	item := datatypes.NewDeleteReferencesItem(source, organizes, true, target, true)
+	codes, err = c.DeleteReferences([]*datatypes.DeleteReferencesItem{item, item})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(codes, []uint32{0, status.BadNotFound}); diff != "" {
+		t.Error(diff)
+	}
+}
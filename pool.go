@@ -0,0 +1,250 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package gopcua
+
+import (
+	"context"
+	"sync"
+
+	"github.com/wmnsk/gopcua/datatypes"
+	"github.com/wmnsk/gopcua/errors"
+	"github.com/wmnsk/gopcua/services"
+)
+
+// Pool is a set of Clients connected to the same endpoint, each with its own Session,
+// which serve the requests concurrently.
+//
+// Each request is sent on the Client with the fewest requests in flight. A Client which
+// loses its connection is replaced with a new one in background, while the requests are
+// sent on the others.
+type Pool struct {
+	endpoint string
+	size     int
+	opts     []Option
+
+	mu     sync.Mutex
+	ctx    context.Context
+	conns  []*poolConn
+	closed bool
+}
+
+// poolConn is a Client in the Pool with the number of requests in flight on it.
+type poolConn struct {
+	c    *Client
+	busy int
+	// broken is set when the Client is found not connected, and recycling
+	// while it is being replaced with a new one.
+	broken, recycling bool
+}
+
+// NewPool creates a new Pool of size Clients for the endpoint, each of which
+// is created with opts. No connection is established until Connect is called.
+func NewPool(endpoint string, size int, opts ...Option) *Pool {
+	return &Pool{
+		endpoint: endpoint,
+		size:     size,
+		opts:     opts,
+	}
+}
+
+// Connect connects all the Clients in the Pool. If any of them fails,
+// the ones already connected are closed and the error is returned.
+//
+// The ctx is also used to connect the Clients replacing the broken ones.
+func (p *Pool) Connect(ctx context.Context) error {
+	if p.size <= 0 {
+		return errors.New("pool size should be positive")
+	}
+
+	conns := make([]*poolConn, 0, p.size)
+	for i := 0; i < p.size; i++ {
+		c := NewClient(p.endpoint, p.opts...)
+		if err := c.Connect(ctx); err != nil {
+			for _, pc := range conns {
+				pc.c.Close()
+			}
+			return err
+		}
+		conns = append(conns, &poolConn{c: c})
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.ctx, p.conns, p.closed = ctx, conns, false
+	return nil
+}
+
+// Close closes all the Clients in the Pool and returns the first error occurred.
+func (p *Pool) Close() error {
+	p.mu.Lock()
+	if p.conns == nil {
+		p.mu.Unlock()
+		return ErrNotConnected
+	}
+	// the errors of the broken Clients are ignored as they are not connected.
+	broken := map[*Client]bool{}
+	for _, pc := range p.conns {
+		broken[pc.c] = pc.broken
+	}
+	p.conns, p.closed = nil, true
+	p.mu.Unlock()
+
+	var err error
+	for c, b := range broken {
+		if cerr := c.Close(); cerr != nil && !b && err == nil {
+			err = cerr
+		}
+	}
+	return err
+}
+
+// acquire returns the Client with the fewest requests in flight,
+// and starts replacing the broken ones found.
+func (p *Pool) acquire() (*poolConn, *Client, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var found *poolConn
+	for _, pc := range p.conns {
+		if pc.broken {
+			p.recycle(pc)
+			continue
+		}
+		if found == nil || pc.busy < found.busy {
+			found = pc
+		}
+	}
+	if found == nil {
+		return nil, nil, ErrNotConnected
+	}
+
+	found.busy++
+	return found, found.c, nil
+}
+
+// release returns the Client borrowed by acquire, marking it broken
+// if the request failed as it is not connected.
+func (p *Pool) release(pc *poolConn, c *Client, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	pc.busy--
+	if err == ErrNotConnected && pc.c == c {
+		pc.broken = true
+		p.recycle(pc)
+	}
+}
+
+// recycle replaces the Client of pc with a new one connected in background.
+// If it fails to connect, it is tried again on the next acquire.
+//
+// This should be called with p.mu held.
+func (p *Pool) recycle(pc *poolConn) {
+	if pc.recycling || p.closed {
+		return
+	}
+	pc.recycling = true
+
+	ctx := p.ctx
+	go func() {
+		c := NewClient(p.endpoint, p.opts...)
+		err := c.Connect(ctx)
+
+		p.mu.Lock()
+		defer p.mu.Unlock()
+
+		pc.recycling = false
+		if err != nil {
+			return
+		}
+		if p.closed {
+			c.Close()
+			return
+		}
+
+		old := pc.c
+		pc.c, pc.broken = c, false
+		go old.Close()
+	}()
+}
+
+// do calls f with a Client borrowed from the Pool.
+func (p *Pool) do(f func(c *Client) error) error {
+	pc, c, err := p.acquire()
+	if err != nil {
+		return err
+	}
+	err = f(c)
+	p.release(pc, c, err)
+	return err
+}
+
+// Read sends a ReadRequest on one of the Clients in the Pool. See Client.Read.
+func (p *Pool) Read(maxAge float64, tsRet services.TimestampsToReturn, nodes ...*datatypes.ReadValueID) (*services.ReadResponse, error) {
+	return p.ReadWithContext(context.Background(), maxAge, tsRet, nodes...)
+}
+
+// ReadWithContext is the same as Read but returns ctx.Err() if ctx is done
+// before the ReadResponse arrives.
+func (p *Pool) ReadWithContext(ctx context.Context, maxAge float64, tsRet services.TimestampsToReturn, nodes ...*datatypes.ReadValueID) (*services.ReadResponse, error) {
+	var res *services.ReadResponse
+	err := p.do(func(c *Client) (err error) {
+		res, err = c.ReadWithContext(ctx, maxAge, tsRet, nodes...)
+		return err
+	})
+	return res, err
+}
+
+// Write sends a WriteRequest on one of the Clients in the Pool. See Client.Write.
+func (p *Pool) Write(nodes ...*datatypes.WriteValue) (*services.WriteResponse, error) {
+	return p.WriteWithContext(context.Background(), nodes...)
+}
+
+// WriteWithContext is the same as Write but returns ctx.Err() if ctx is done
+// before the WriteResponse arrives.
+func (p *Pool) WriteWithContext(ctx context.Context, nodes ...*datatypes.WriteValue) (*services.WriteResponse, error) {
+	var res *services.WriteResponse
+	err := p.do(func(c *Client) (err error) {
+		res, err = c.WriteWithContext(ctx, nodes...)
+		return err
+	})
+	return res, err
+}
+
+// Browse sends a BrowseRequest on one of the Clients in the Pool. See Client.Browse.
+//
+// The ContinuationPoints in the BrowseResponse are only valid in the Session of the Client
+// which received them, so BrowseAll should be used to follow them.
+func (p *Pool) Browse(view *datatypes.ViewDescription, maxRefs uint32, nodes ...*datatypes.BrowseDescription) (*services.BrowseResponse, error) {
+	return p.BrowseWithContext(context.Background(), view, maxRefs, nodes...)
+}
+
+// BrowseWithContext is the same as Browse but returns ctx.Err() if ctx is done
+// before the BrowseResponse arrives.
+func (p *Pool) BrowseWithContext(ctx context.Context, view *datatypes.ViewDescription, maxRefs uint32, nodes ...*datatypes.BrowseDescription) (*services.BrowseResponse, error) {
+	var res *services.BrowseResponse
+	err := p.do(func(c *Client) (err error) {
+		res, err = c.BrowseWithContext(ctx, view, maxRefs, nodes...)
+		return err
+	})
+	return res, err
+}
+
+// BrowseAll returns all the References of node on one of the Clients in the Pool,
+// following the ContinuationPoints in the same Session. See Client.BrowseAll.
+func (p *Pool) BrowseAll(node *datatypes.NodeID, desc *datatypes.BrowseDescription) ([]*datatypes.ReferenceDescription, error) {
+	return p.BrowseAllWithContext(context.Background(), node, desc)
+}
+
+// BrowseAllWithContext is the same as BrowseAll but returns ctx.Err() if ctx is done
+// before all the References are received.
+func (p *Pool) BrowseAllWithContext(ctx context.Context, node *datatypes.NodeID, desc *datatypes.BrowseDescription) ([]*datatypes.ReferenceDescription, error) {
+	var refs []*datatypes.ReferenceDescription
+	err := p.do(func(c *Client) (err error) {
+		refs, err = c.BrowseAllWithContext(ctx, node, desc)
+		return err
+	})
+	return refs, err
+}
@@ -0,0 +1,128 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package gopcua
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/wmnsk/gopcua/datatypes"
+	"github.com/wmnsk/gopcua/services"
+	"github.com/wmnsk/gopcua/uacp"
+	"github.com/wmnsk/gopcua/uasc"
+)
+
+func TestPoolRead(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	const size = 3
+	ln, err := uacp.Listen(endpoint, 0xffff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	// each Session responds with its index once all of them have a ReadRequest,
+	// so the Reads only complete if they are sent on all the Clients concurrently.
+	var wg sync.WaitGroup
+	wg.Add(size)
+	all := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(all)
+	}()
+	for i := 0; i < size; i++ {
+		go func(i int) {
+			var once sync.Once
+			serveSession(ctx, ln, func(srv services.Service) (services.Service, bool) {
+				req, ok := srv.(*services.ReadRequest)
+				if !ok {
+					return nil, false
+				}
+				once.Do(wg.Done)
+				select {
+				case <-all:
+				case <-time.After(5 * time.Second):
+				}
+				return services.NewReadResponse(newResponseHeader(req.RequestHandle), nil, newValue(datatypes.NewInt32(int32(i)))), false
+			})
+		}(i)
+	}
+
+	p := NewPool(endpoint, size)
+	if err := p.Connect(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer p.Close()
+
+	var mu sync.Mutex
+	got := map[int32]int{}
+	var reads sync.WaitGroup
+	for i := 0; i < size; i++ {
+		reads.Add(1)
+		go func() {
+			defer reads.Done()
+			node := datatypes.NewReadValueID(datatypes.NewNumericNodeID(0, 2256), datatypes.AttributeIDValue, "", 0, "")
+			res, err := p.ReadWithContext(ctx, 0, services.TimestampsToReturnNeither, node)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			v, _ := res.Results.DataValues[0].Value.Int32()
+			mu.Lock()
+			got[v]++
+			mu.Unlock()
+		}()
+	}
+	reads.Wait()
+
+	select {
+	case <-all:
+	default:
+		t.Fatal("reads were not sent on all the clients")
+	}
+	if len(got) != size {
+		t.Errorf("got responses from %v want %d sessions", got, size)
+	}
+}
+
+func TestPoolSharedConfig(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	const size = 2
+	ln, err := uacp.Listen(endpoint, 0xffff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	for i := 0; i < size; i++ {
+		go serveSession(ctx, ln, func(srv services.Service) (services.Service, bool) {
+			return nil, false
+		})
+	}
+
+	// the Configs given by the options are shared by all the Clients in the Pool.
+	cfg := uasc.NewClientConfigSecurityNone(3333, 3600000)
+	sessCfg := uasc.NewClientSessionConfig([]string{"en-US"}, datatypes.NewAnonymousIdentityToken("anonymous"))
+	want, wantSess := *cfg, *sessCfg
+
+	p := NewPool(endpoint, size, WithConfig(cfg), WithSessionConfig(sessCfg))
+	if err := p.Connect(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer p.Close()
+
+	if cfg.SecureChannelID != want.SecureChannelID || cfg.SequenceNumber != want.SequenceNumber || cfg.RequestID != want.RequestID {
+		t.Errorf("got Config updated by the SecureChannels: %+v", cfg)
+	}
+	if sessCfg.SessionTimeout != wantSess.SessionTimeout || len(sessCfg.ServerEndpoints) != len(wantSess.ServerEndpoints) {
+		t.Errorf("got SessionConfig updated by the Sessions: %+v", sessCfg)
+	}
+}
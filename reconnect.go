@@ -0,0 +1,197 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package gopcua
+
+import (
+	"context"
+	"time"
+
+	"github.com/wmnsk/gopcua/datatypes"
+	"github.com/wmnsk/gopcua/services"
+	"github.com/wmnsk/gopcua/uasc"
+)
+
+// ConnState is the state of the connection of the Client to the endpoint,
+// which is reported to the callback set by WithStateCallback.
+type ConnState int
+
+// ConnState definitions.
+const (
+	// StateClosed is reported when the Client is closed, or gives up reconnecting.
+	StateClosed ConnState = iota
+	// StateConnected is reported when the Session is activated on Connect or on reconnection.
+	StateConnected
+	// StateDisconnected is reported when the connection is lost without Close.
+	StateDisconnected
+	// StateReconnecting is reported before each attempt to reconnect.
+	StateReconnecting
+)
+
+// Backoff returns how long to wait before the attempt-th reconnection, counted from 0,
+// or false to give up reconnecting.
+type Backoff func(attempt int) (time.Duration, bool)
+
+// ExponentialBackoff returns a Backoff which waits initial before the first attempt
+// and doubles the wait for each of the following ones up to max.
+//
+// It gives up after the number of attempts given, or never if attempts is 0.
+func ExponentialBackoff(initial, max time.Duration, attempts int) Backoff {
+	return func(attempt int) (time.Duration, bool) {
+		if attempts > 0 && attempt >= attempts {
+			return 0, false
+		}
+
+		d := initial
+		for i := 0; i < attempt && d < max; i++ {
+			d *= 2
+		}
+		if d > max {
+			d = max
+		}
+		return d, true
+	}
+}
+
+// WithReconnect makes the Client reconnect to the endpoint when the connection is lost,
+// waiting between the attempts as backoff tells.
+//
+// On reconnection, the Session is activated again on a new SecureChannel. If the server
+// has discarded it, a new Session is created and the Subscriptions are transferred to it,
+// so that they keep delivering the Notifications without creating the MonitoredItems again.
+// The Subscriptions which cannot be transferred are closed.
+func WithReconnect(backoff Backoff) Option {
+	return func(c *Client) {
+		c.backoff = backoff
+	}
+}
+
+// WithStateCallback sets the callback called when the state of the connection changes.
+//
+// The callback is called from the goroutine monitoring the connection, so it should
+// return quickly and must not call the methods of the Client.
+func WithStateCallback(f func(ConnState)) Option {
+	return func(c *Client) {
+		c.onState = f
+	}
+}
+
+func (c *Client) setState(state ConnState) {
+	if c.onState != nil {
+		c.onState(state)
+	}
+}
+
+// reconnect tries to resume the Session lost until it succeeds, the Backoff gives up,
+// ctx is done, or stop is closed by Close.
+func (c *Client) reconnect(ctx context.Context, stop chan struct{}, session *uasc.Session) bool {
+	for attempt := 0; ; attempt++ {
+		wait, ok := c.backoff(attempt)
+		if !ok {
+			return false
+		}
+
+		select {
+		case <-ctx.Done():
+			return false
+		case <-stop:
+			return false
+		case <-time.After(wait):
+		}
+
+		c.setState(StateReconnecting)
+		err := c.resume(ctx, stop, session)
+		if err == nil {
+			c.log().Info("reconnected", "endpoint", c.endpoint, "attempt", attempt)
+			c.setState(StateConnected)
+			return true
+		}
+		c.log().Warn("failed to reconnect", "endpoint", c.endpoint, "attempt", attempt, "error", err)
+	}
+}
+
+// resume opens a new SecureChannel to the endpoint and activates the Session lost on it.
+// If the server does not know the Session anymore, a new Session is created instead and
+// the Subscriptions are transferred to it.
+func (c *Client) resume(ctx context.Context, stop chan struct{}, old *uasc.Session) error {
+	conn, err := c.dial(ctx)
+	if err != nil {
+		return err
+	}
+
+	// the Config is updated by the SecureChannel, which should not be shared with the lost one.
+	cfg := *c.cfg
+	secChan, err := uasc.OpenSecureChannel(ctx, conn, &cfg, 5*time.Second, 3)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+
+	session, resumed, err := uasc.ResumeSession(ctx, secChan, old, 3, 5*time.Second)
+	if err != nil {
+		secChan.Close()
+		conn.Close()
+		return err
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.stop != stop {
+		// Close is called while reconnecting.
+		session.Close()
+		secChan.Close()
+		conn.Close()
+		return ErrNotConnected
+	}
+
+	c.start(ctx, conn, secChan, session)
+	// the server may have been restarted with the namespaces in another order.
+	c.updateNamespaces(ctx)
+	if !resumed {
+		c.transferSubscriptions(ctx)
+	}
+	c.publish()
+	return nil
+}
+
+// transferSubscriptions transfers the Subscriptions to the new Session with their MonitoredItems.
+// The Subscriptions which cannot be transferred are closed, as no more NotificationMessages arrive.
+//
+// This should be called with c.mu held.
+func (c *Client) transferSubscriptions(ctx context.Context) {
+	c.subMu.Lock()
+	ids := make([]uint32, 0, len(c.subs))
+	for id := range c.subs {
+		ids = append(ids, id)
+	}
+	c.subMu.Unlock()
+	if len(ids) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	h := c.requestHeader()
+	res, err := c.send(ctx, services.NewTransferSubscriptionsRequest(h, true, ids...), h.RequestHandle)
+
+	var results []*datatypes.TransferResult
+	if r, ok := res.(*services.TransferSubscriptionsResponse); ok && r.Results != nil {
+		results = r.Results.TransferResults
+	}
+
+	c.subMu.Lock()
+	defer c.subMu.Unlock()
+
+	for i, id := range ids {
+		if err == nil && i < len(results) && results[i].StatusCode == 0 {
+			continue
+		}
+		if sub, ok := c.subs[id]; ok {
+			close(sub.notifs)
+			delete(c.subs, id)
+		}
+	}
+}
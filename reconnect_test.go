@@ -0,0 +1,211 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package gopcua
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/wmnsk/gopcua/datatypes"
+	"github.com/wmnsk/gopcua/services"
+	"github.com/wmnsk/gopcua/uacp"
+	"github.com/wmnsk/gopcua/uasc"
+)
+
+// serveSession accepts a connection on ln and responds to each request with the response
+// returned by handle, until handle tells it is the last one. The connection is closed
+// after that without closing the Session, as if it is lost.
+func serveSession(ctx context.Context, ln *uacp.Listener, handle func(services.Service) (services.Service, bool)) error {
+	srvConn, err := ln.Accept(ctx)
+	if err != nil {
+		return err
+	}
+	return serveSessionConn(ctx, srvConn, handle)
+}
+
+// serveSessionConn is serveSession on the connection srvConn established.
+func serveSessionConn(ctx context.Context, srvConn *uacp.Conn, handle func(services.Service) (services.Service, bool)) error {
+	defer srvConn.Close()
+
+	srvCfg := uasc.NewServerConfig(policyURI, nil, nil, 1111, services.SecModeNone, 2222, 3600000)
+	srvChan, err := uasc.ListenAndAcceptSecureChannel(ctx, srvConn, srvCfg)
+	if err != nil {
+		return err
+	}
+	srvSession, err := uasc.ListenAndAcceptSession(ctx, srvChan, uasc.NewServerSessionConfig(srvChan))
+	if err != nil {
+		return err
+	}
+
+	buf := make([]byte, 0xffff)
+	for {
+		n, err := srvSession.ReadService(buf)
+		if err != nil {
+			return err
+		}
+		srv, err := services.Decode(buf[:n])
+		if err != nil {
+			continue
+		}
+		if res := handleNamespaces(srv); res != nil {
+			b, err := res.Serialize()
+			if err != nil {
+				return err
+			}
+			if _, err := srvSession.WriteService(b); err != nil {
+				return err
+			}
+			continue
+		}
+
+		res, last := handle(srv)
+		if res != nil {
+			b, err := res.Serialize()
+			if err != nil {
+				return err
+			}
+			if _, err := srvSession.WriteService(b); err != nil {
+				return err
+			}
+		}
+		if last {
+			return nil
+		}
+	}
+}
+
+func TestClientReconnect(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ln, err := uacp.Listen(endpoint, 0xffff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	// the server drops the connection right after publishing the first data change,
+	// and does not know the Session on the next connection, so the Subscription
+	// should be transferred to the new Session to get the second one.
+	const subID = 7
+	transferred := make(chan []uint32, 1)
+	go func() {
+		err := serveSession(ctx, ln, func(srv services.Service) (services.Service, bool) {
+			switch req := srv.(type) {
+			case *services.CreateSubscriptionRequest:
+				return services.NewCreateSubscriptionResponse(newResponseHeader(req.RequestHandle), subID, 100, 60, 20), false
+			case *services.PublishRequest:
+				return newDataChangeResponse(req.RequestHandle, subID, 1, datatypes.NewFloat(10)), true
+			}
+			return nil, false
+		})
+		if err != nil {
+			return
+		}
+
+		var published int
+		serveSession(ctx, ln, func(srv services.Service) (services.Service, bool) {
+			switch req := srv.(type) {
+			case *services.TransferSubscriptionsRequest:
+				transferred <- req.SubscriptionIDs.Values
+				return services.NewTransferSubscriptionsResponse(
+					newResponseHeader(req.RequestHandle), nil, datatypes.NewTransferResult(0, 1),
+				), false
+			case *services.PublishRequest:
+				published++
+				if published == 1 {
+					return newDataChangeResponse(req.RequestHandle, subID, 2, datatypes.NewFloat(20)), false
+				}
+			}
+			return nil, false
+		})
+	}()
+
+	var mu sync.Mutex
+	var states []ConnState
+	c := NewClient(endpoint,
+		WithReconnect(ExponentialBackoff(10*time.Millisecond, 100*time.Millisecond, 5)),
+		WithStateCallback(func(s ConnState) {
+			mu.Lock()
+			defer mu.Unlock()
+			states = append(states, s)
+		}),
+	)
+	if err := c.Connect(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	sub, err := c.CreateSubscription(100, 60, 20, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, want := range []float32{10, 20} {
+		select {
+		case msg, ok := <-sub.Notifs():
+			if !ok {
+				t.Fatalf("subscription was closed before notification %v", want)
+			}
+			d := msg.NotificationData.ExtensionObjects[0].Value.(*datatypes.DataChangeNotification)
+			got := d.MonitoredItems.Notifications[0].Value.Value.Value
+			if diff := cmp.Diff(got, datatypes.NewFloat(want)); diff != "" {
+				t.Error(diff)
+			}
+		case <-time.After(10 * time.Second):
+			t.Fatalf("notification %v was not delivered", want)
+		}
+	}
+
+	select {
+	case ids := <-transferred:
+		if diff := cmp.Diff(ids, []uint32{subID}); diff != "" {
+			t.Error(diff)
+		}
+	default:
+		t.Error("subscription was not transferred")
+	}
+
+	// the state is reported after the PublishRequests are sent on reconnection,
+	// which may be after the notification is delivered.
+	want := []ConnState{StateConnected, StateDisconnected, StateReconnecting, StateConnected}
+	var got []ConnState
+	for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(10 * time.Millisecond) {
+		mu.Lock()
+		got = append([]ConnState{}, states...)
+		mu.Unlock()
+		if len(got) >= len(want) {
+			break
+		}
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	b := ExponentialBackoff(100*time.Millisecond, time.Second, 6)
+
+	want := []time.Duration{
+		100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond,
+		800 * time.Millisecond, time.Second, time.Second,
+	}
+	for i, w := range want {
+		got, ok := b(i)
+		if !ok {
+			t.Fatalf("gave up at attempt %d", i)
+		}
+		if got != w {
+			t.Errorf("attempt %d: got %v want %v", i, got, w)
+		}
+	}
+	if _, ok := b(len(want)); ok {
+		t.Errorf("did not give up after %d attempts", len(want))
+	}
+}
@@ -0,0 +1,93 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package gopcua
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/wmnsk/gopcua/errors"
+	"github.com/wmnsk/gopcua/status"
+)
+
+// RetryPolicy tells which errors of the requests are transient and how to retry them.
+type RetryPolicy struct {
+	// Backoff returns how long to wait before the attempt-th retry, counted from 0,
+	// or false to give up retrying.
+	Backoff Backoff
+	// Jitter is the fraction of the wait returned by Backoff, from 0 to 1, which is
+	// randomly cut so that the Clients retrying at the same time do not collide again.
+	Jitter float64
+	// MaxElapsed is the time after which the request is not retried anymore,
+	// counted from the first failure. It is never given up by the time if 0.
+	MaxElapsed time.Duration
+	// StatusCodes are the ServiceResults of ServiceError to be retried.
+	StatusCodes []uint32
+	// Errors are the errors of the transport to be retried, e.g. ErrTimeout.
+	Errors []error
+}
+
+// NewRetryPolicy creates a new RetryPolicy which retries the ServiceResults of BadTimeout,
+// BadTooManyOperations, BadTcpServerTooBusy and ErrTimeout up to the number of attempts,
+// waiting as ExponentialBackoff(initial, max, attempts) with 20% jitter.
+func NewRetryPolicy(initial, max time.Duration, attempts int) *RetryPolicy {
+	return &RetryPolicy{
+		Backoff:     ExponentialBackoff(initial, max, attempts),
+		Jitter:      0.2,
+		StatusCodes: []uint32{status.BadTimeout, status.BadTooManyOperations, status.BadTcpServerTooBusy},
+		Errors:      []error{ErrTimeout},
+	}
+}
+
+// WithRetry makes the Client retry the requests failed with the errors retryable by policy.
+// The other errors are returned immediately.
+//
+// The request is sent again with a new RequestHandle, so the services which are not
+// idempotent, e.g. Call, may be executed more than once if the response is lost.
+// DeleteSubscriptions and Publish are never retried.
+func WithRetry(policy *RetryPolicy) Option {
+	return func(c *Client) {
+		c.retry = policy
+	}
+}
+
+// retryable returns true if err is one of the StatusCodes or the Errors of p.
+func (p *RetryPolicy) retryable(err error) bool {
+	err = errors.Cause(err)
+	if e, ok := err.(*ServiceError); ok {
+		for _, code := range p.StatusCodes {
+			if e.Code == code {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, e := range p.Errors {
+		if err == e {
+			return true
+		}
+	}
+	return false
+}
+
+// wait returns how long to wait before the attempt-th retry, or false if the Backoff
+// gives up or retrying for elapsed exceeds MaxElapsed.
+func (p *RetryPolicy) wait(attempt int, elapsed time.Duration) (time.Duration, bool) {
+	if p.Backoff == nil {
+		return 0, false
+	}
+	d, ok := p.Backoff(attempt)
+	if !ok {
+		return 0, false
+	}
+	if p.Jitter > 0 {
+		d -= time.Duration(p.Jitter * rand.Float64() * float64(d))
+	}
+	if p.MaxElapsed > 0 && elapsed+d > p.MaxElapsed {
+		return 0, false
+	}
+	return d, true
+}
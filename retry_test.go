@@ -0,0 +1,156 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package gopcua
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/wmnsk/gopcua/datatypes"
+	"github.com/wmnsk/gopcua/errors"
+	"github.com/wmnsk/gopcua/services"
+	"github.com/wmnsk/gopcua/status"
+)
+
+func TestClientRetry(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	busy := datatypes.NewStringNodeID(2, "Busy")
+	unknown := datatypes.NewStringNodeID(2, "Unknown")
+
+	// the ServiceResults returned for the ReadRequests of each node in order.
+	results := map[string][]uint32{
+		busy.String():    {status.BadTcpServerTooBusy, status.BadTcpServerTooBusy, status.Good},
+		unknown.String(): {status.BadNodeIdUnknown, status.Good},
+	}
+	var mu sync.Mutex
+	handles := map[string][]uint32{}
+	c, err := setUpClient(ctx, func(srv services.Service) services.Service {
+		req, ok := srv.(*services.ReadRequest)
+		if !ok {
+			return nil
+		}
+		name := req.NodesToRead.ReadValueIDs[0].NodeID.String()
+		mu.Lock()
+		defer mu.Unlock()
+		code := results[name][len(handles[name])]
+		handles[name] = append(handles[name], req.RequestHandle)
+
+		h := newResponseHeader(req.RequestHandle)
+		h.ServiceResult = code
+		return services.NewReadResponse(h, nil, newValue(datatypes.NewFloat(21.5)))
+	}, WithRetry(NewRetryPolicy(time.Millisecond, 10*time.Millisecond, 3)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	v, err := c.Node(busy).Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, ok := v.Float(); !ok || got != 21.5 {
+		t.Errorf("got %v want 21.5", v)
+	}
+
+	mu.Lock()
+	if n := len(handles[busy.String()]); n != 3 {
+		t.Errorf("got %d attempts want 3", n)
+	}
+	if h := handles[busy.String()]; len(h) == 3 && (h[0] == h[1] || h[1] == h[2]) {
+		t.Errorf("got RequestHandles %v want a new one for each attempt", h)
+	}
+	mu.Unlock()
+
+	// the error which is not retryable is returned immediately.
+	_, err = c.Node(unknown).Value()
+	e, ok := errors.Cause(err).(*ServiceError)
+	if !ok || e.Code != status.BadNodeIdUnknown {
+		t.Errorf("got %v want ServiceError of BadNodeIdUnknown", err)
+	}
+	mu.Lock()
+	if n := len(handles[unknown.String()]); n != 1 {
+		t.Errorf("got %d attempts want 1", n)
+	}
+	mu.Unlock()
+}
+
+func TestClientRetryClose(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	received := make(chan struct{}, 1)
+	c, err := setUpClient(ctx, func(srv services.Service) services.Service {
+		req, ok := srv.(*services.ReadRequest)
+		if !ok {
+			return nil
+		}
+		select {
+		case received <- struct{}{}:
+		default:
+		}
+		h := newResponseHeader(req.RequestHandle)
+		h.ServiceResult = status.BadTcpServerTooBusy
+		return services.NewReadResponse(h, nil, newValue(datatypes.NewFloat(21.5)))
+	}, WithRetry(NewRetryPolicy(time.Minute, time.Minute, 3)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	errChan := make(chan error, 1)
+	go func() {
+		_, err := c.Node(datatypes.NewStringNodeID(2, "Busy")).Value()
+		errChan <- err
+	}()
+	select {
+	case <-received:
+	case <-time.After(5 * time.Second):
+		t.Fatal("ReadRequest was not sent")
+	}
+
+	// Close is not blocked by the request waiting to retry, which gives up.
+	closed := make(chan struct{})
+	go func() {
+		c.Close()
+		close(closed)
+	}()
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Close was blocked while waiting to retry")
+	}
+	select {
+	case err := <-errChan:
+		if err != ErrNotConnected {
+			t.Errorf("got %v want %v", err, ErrNotConnected)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("request was not given up after Close")
+	}
+}
+
+func TestRetryPolicyWait(t *testing.T) {
+	p := NewRetryPolicy(100*time.Millisecond, time.Second, 3)
+	p.MaxElapsed = 2 * time.Second
+
+	for attempt, max := range []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond} {
+		d, ok := p.wait(attempt, 0)
+		if !ok {
+			t.Fatalf("attempt %d: gave up", attempt)
+		}
+		if d > max || d < max*8/10 {
+			t.Errorf("attempt %d: got %v want between %v and %v", attempt, d, max*8/10, max)
+		}
+	}
+	if _, ok := p.wait(3, 0); ok {
+		t.Error("should give up after the attempts")
+	}
+	if _, ok := p.wait(0, 2*time.Second); ok {
+		t.Error("should give up after MaxElapsed")
+	}
+}
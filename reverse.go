@@ -0,0 +1,68 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package gopcua
+
+import (
+	"context"
+
+	"github.com/wmnsk/gopcua/uacp"
+)
+
+// ListenReverse listens on the endpoint addr for the servers connecting with reverse connect,
+// e.g. the ones behind NAT, and calls handler with a connected Client for each of them.
+//
+// See ListenReverseWithContext for the details.
+func ListenReverse(addr string, handler func(*Client), opts ...Option) error {
+	return ListenReverseWithContext(context.Background(), addr, handler, opts...)
+}
+
+// ListenReverseWithContext is ListenReverse with a context, which stops listening when done.
+//
+// Each Client is created with the options given for the EndpointURL in the ReverseHello
+// the server sends, and the SecureChannel and the Session are opened on the connection
+// accepted. The handler is called in a new goroutine, and should Close the Client when done.
+// The connections failed to open are closed without calling handler.
+//
+// The Clients do not reconnect when the connections are lost, as they are initiated by the servers.
+func ListenReverseWithContext(ctx context.Context, addr string, handler func(*Client), opts ...Option) error {
+	ln, err := uacp.Listen(addr, 0xffff)
+	if err != nil {
+		return err
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-done:
+		}
+		// unblocks ServeReverse.
+		ln.Close()
+	}()
+
+	err = ln.ServeReverse(ctx, func(conn *uacp.Conn) {
+		acceptReverse(ctx, conn, handler, opts)
+	})
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return err
+}
+
+// acceptReverse opens the Client on the connection accepted and calls handler with it.
+func acceptReverse(ctx context.Context, conn *uacp.Conn, handler func(*Client), opts []Option) {
+	c := NewClient(conn.RemoteEndpoint(), opts...)
+	c.backoff = nil
+	if err := c.prepare(); err != nil {
+		conn.Close()
+		return
+	}
+	if err := c.open(ctx, conn); err != nil {
+		conn.Close()
+		return
+	}
+	handler(c)
+}
@@ -0,0 +1,86 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package gopcua
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/wmnsk/gopcua/datatypes"
+	"github.com/wmnsk/gopcua/services"
+	"github.com/wmnsk/gopcua/uacp"
+)
+
+func TestListenReverse(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	addr := "opc.tcp://127.0.0.1:4843"
+	clients := make(chan *Client)
+	errChan := make(chan error, 2)
+	go func() {
+		errChan <- ListenReverseWithContext(ctx, addr, func(c *Client) {
+			clients <- c
+		})
+	}()
+
+	// the server connects to the client once it is listening.
+	go func() {
+		var conn *uacp.Conn
+		var err error
+		for i := 0; i < 10; i++ {
+			if conn, err = uacp.DialReverse(ctx, addr, "urn:gopcua:server", endpoint); err == nil {
+				break
+			}
+			time.Sleep(100 * time.Millisecond)
+		}
+		if err != nil {
+			errChan <- err
+			return
+		}
+		read := handleRead(func(*services.ReadRequest) *datatypes.DataValue {
+			return newValue(datatypes.NewFloat(21.5))
+		})
+		serveSessionConn(ctx, conn, func(srv services.Service) (services.Service, bool) {
+			return read(srv), false
+		})
+	}()
+
+	var c *Client
+	select {
+	case c = <-clients:
+	case err := <-errChan:
+		t.Fatal(err)
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out")
+	}
+	defer c.Close()
+
+	if got, want := c.endpoint, endpoint; got != want {
+		t.Errorf("got endpoint %s want %s", got, want)
+	}
+
+	// the Session is activated on the connection from the server.
+	v, err := c.Node(datatypes.NewStringNodeID(2, "Temperature")).Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(v, datatypes.NewVariant(datatypes.NewFloat(21.5))); diff != "" {
+		t.Error(diff)
+	}
+
+	cancel()
+	select {
+	case err := <-errChan:
+		if err != context.Canceled {
+			t.Errorf("got error %v want %v", err, context.Canceled)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out")
+	}
+}
@@ -7,6 +7,20 @@ package securitypolicy
 import "crypto/rsa"
 
 // Because rsa.PublicKey.Size() was only added in Go 1.11
+//
+// It returns 0 for nil, so that the algorithms which only encrypt or decrypt
+// can be created with one of the keys.
 func keySize(pub *rsa.PublicKey) int {
+	if pub == nil {
+		return 0
+	}
 	return (pub.N.BitLen() + 7) / 8
 }
+
+// privateKeySize returns the size of priv in bytes, or 0 for nil.
+func privateKeySize(priv *rsa.PrivateKey) int {
+	if priv == nil {
+		return 0
+	}
+	return keySize(&priv.PublicKey)
+}
@@ -89,7 +89,7 @@ func newBasic128Rsa15Asymmetric(localKey *rsa.PrivateKey, remoteKey *rsa.PublicK
 	e.decrypt = decryptPKCS1v15(localKey)                      // RSA-SHA15+KWRSA15
 	e.signature = signPKCS1v15(crypto.SHA1, localKey)          // RSA-SHA1
 	e.verifySignature = verifyPKCS1v15(crypto.SHA1, remoteKey) // RSA-SHA1
-	e.signatureLength = keySize(&localKey.PublicKey)
+	e.signatureLength = privateKeySize(localKey)
 	e.encryptionURI = "http://www.w3.org/2001/04/xmlenc#rsa-1_5"
 	e.signatureURI = "http://www.w3.org/2000/09/xmldsig#rsa-sha1"
 
@@ -8,6 +8,7 @@
 package securitypolicy
 
 import (
+	"crypto/rand"
 	"crypto/rsa"
 	"errors"
 )
@@ -68,6 +69,47 @@ func Symmetric(policyURI string, localNonce []byte, remoteNonce []byte) (*Encryp
 	return policy.symmetricInitFunc(localNonce, remoteNonce)
 }
 
+// NonceLength returns the length of the nonces exchanged in the OpenSecureChannel service
+// for the Security Policy, e.g. 32 for Basic256Sha256.
+// For Security Policy "None", it is 0 as the nonces are not used.
+func NonceLength(policyURI string) (int, error) {
+	policy, ok := supportedPolicies[policyURI]
+	if !ok {
+		return 0, errors.New("unknown security policy")
+	}
+
+	return policy.nonceLength, nil
+}
+
+// GenerateNonce returns a new nonce of NonceLength for the Security Policy read from
+// crypto/rand. For Security Policy "None", it returns nil.
+func GenerateNonce(policyURI string) ([]byte, error) {
+	n, err := NonceLength(policyURI)
+	if err != nil || n == 0 {
+		return nil, err
+	}
+
+	nonce := make([]byte, n)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	return nonce, nil
+}
+
+// SymmetricSign is the same as Symmetric but returns the EncryptionAlgorithm which only
+// signs and verifies the messages, for the MessageSecurityMode Sign.
+// The encryption keys are not kept, and Encrypt and Decrypt return the input as it is.
+func SymmetricSign(policyURI string, localNonce []byte, remoteNonce []byte) (*EncryptionAlgorithm, error) {
+	e, err := Symmetric(policyURI, localNonce, remoteNonce)
+	if err != nil {
+		return nil, err
+	}
+
+	e.encrypt, e.decrypt = encryptNone, decryptNone
+	e.encryptionURI = ""
+	return e, nil
+}
+
 // BlockSize returns the underlying encryption algorithm's blocksize.
 // Used to calculate the padding required to make the cleartext an
 // even multiple of the blocksize
@@ -28,6 +28,49 @@ func TestSupportedPolicies(t *testing.T) {
 
 }
 
+func TestGenerateNonce(t *testing.T) {
+	cases := []struct {
+		uri string
+		len int
+	}{
+		{"http://opcfoundation.org/UA/SecurityPolicy#None", 0},
+		{"http://opcfoundation.org/UA/SecurityPolicy#Basic128Rsa15", 16},
+		{"http://opcfoundation.org/UA/SecurityPolicy#Basic256", 32},
+		{"http://opcfoundation.org/UA/SecurityPolicy#Basic256Sha256", 32},
+		{"http://opcfoundation.org/UA/SecurityPolicy#Aes128_Sha256_RsaOaep", 32},
+		{"http://opcfoundation.org/UA/SecurityPolicy#Aes256_Sha256_RsaPss", 32},
+	}
+	for _, c := range cases {
+		t.Run(c.uri, func(t *testing.T) {
+			if n, err := NonceLength(c.uri); err != nil || n != c.len {
+				t.Fatalf("NonceLength: got %d, %v want %d", n, err, c.len)
+			}
+
+			a, err := GenerateNonce(c.uri)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(a) != c.len {
+				t.Fatalf("got %d bytes want %d", len(a), c.len)
+			}
+			if c.len == 0 {
+				return
+			}
+			b, err := GenerateNonce(c.uri)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if cmp.Equal(a, b) {
+				t.Error("got the same nonce twice")
+			}
+		})
+	}
+
+	if _, err := GenerateNonce("http://opcfoundation.org/UA/SecurityPolicy#Unknown"); err == nil {
+		t.Error("got nil error for unknown security policy")
+	}
+}
+
 func TestGenerateKeysLength(t *testing.T) {
 	localNonce := make([]byte, 32)
 	remoteNonce := make([]byte, 32)
@@ -94,6 +137,80 @@ func TestGenerateKeys(t *testing.T) {
 	}
 }
 
+// TestGenerateKeysSha256 checks P_SHA256 used by Basic256Sha256 against the
+// reference vector of the TLS 1.2 PRF, where the seed is the label followed by the seed.
+func TestGenerateKeysSha256(t *testing.T) {
+	secret := []byte("\x9B\xBE\x43\x6B\xA9\x40\xF0\x17\xB1\x76\x52\x84\x9A\x71\xDB\x35")
+	seed := append([]byte("test label"), []byte("\xA0\xBA\x9F\x93\x6C\xDA\x31\x18\x27\xA6\xF7\x96\xFF\xD5\x19\x8C")...)
+
+	want := &derivedKeys{
+		signing: []byte("\xE3\xF2\x29\xBA\x72\x7B\xE1\x7B\x8D\x12\x26\x20\x55\x7C\xD4\x53" +
+			"\xC2\xAA\xB2\x1D\x07\xC3\xD4\x95\x32\x9B\x52\xD4\xE6\x1E\xDB\x5A"),
+		encryption: []byte("\x6B\x30\x17\x91\xE9\x0D\x35\xC9\xC9\xA4\x6B\x4E\x14\xBA\xF9\xAF" +
+			"\x0F\xA0\x22\xF7\x07\x7D\xEF\x17\xAB\xFD\x37\x97\xC0\x56\x4B\xAB"),
+		iv: []byte("\x4F\xBC\x91\x66\x6E\x9D\xEF\x9B\x97\xFC\xE3\x4F\x79\x67\x89\xBA" +
+			"\xA4\x80\x82\xD1\x22\xEE\x42\xC5\xA7\x2E\x5A\x51\x10\xFF\xF7\x01" +
+			"\x87\x34\x7B\x66"),
+	}
+
+	keys := generateKeys(computeHmac(crypto.SHA256, secret), seed, 32, 32, 36)
+	if diff := cmp.Diff(keys.signing, want.signing, nil); diff != "" {
+		t.Errorf("signing key generation failed:\n%s\n", diff)
+	}
+	if diff := cmp.Diff(keys.encryption, want.encryption, nil); diff != "" {
+		t.Errorf("encryption key generation failed:\n%s\n", diff)
+	}
+	if diff := cmp.Diff(keys.iv, want.iv, nil); diff != "" {
+		t.Errorf("iv generation failed:\n%s\n", diff)
+	}
+}
+
+// TestBasic256Sha256SymmetricKeys checks that the keys derived by the client and
+// the server from the same nonces match each other, so that each side can decrypt
+// and verify what the other side encrypted and signed.
+func TestBasic256Sha256SymmetricKeys(t *testing.T) {
+	const uri = "http://opcfoundation.org/UA/SecurityPolicy#Basic256Sha256"
+
+	cliNonce := []byte("\xEE\x51\x68\x84\x0E\x07\xF3\x94\x5B\x6D\xB7\x3A\x41\x3E\xC2\x5C" +
+		"\x9B\x0F\x5B\xF8\x5E\x32\xFB\x37\x01\x43\x69\xB3\x14\xDE\x7A\xE7")
+	srvNonce := []byte("\x9B\x0F\x5B\xF8\x5E\x32\xFB\x37\x01\x43\x69\xB3\x14\xDE\x7A\xE7" +
+		"\xEE\x51\x68\x84\x0E\x07\xF3\x94\x5B\x6D\xB7\x3A\x41\x3E\xC2\x5C")
+
+	cli, err := Symmetric(uri, cliNonce, srvNonce)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv, err := Symmetric(uri, srvNonce, cliNonce)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cli.SignatureLength() != 32 {
+		t.Errorf("signature length should be 32, got %d", cli.SignatureLength())
+	}
+
+	plaintext := []byte("0123456789abcdef0123456789abcdef")
+	ciphertext, err := cli.Encrypt(plaintext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	deciphered, err := srv.Decrypt(ciphertext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(deciphered, plaintext); diff != "" {
+		t.Error(diff)
+	}
+
+	signature, err := srv.Signature(plaintext)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cli.VerifySignature(plaintext, signature); err != nil {
+		t.Error(err)
+	}
+}
+
 // Test all supported encryption algorithms.  Because the majority of the algorithms
 // use randomization, the ciphertext will be different on every run even if we used
 // the same keys.  This makes testing against known byte slices impossible.
@@ -10,26 +10,32 @@ var supportedPolicies = map[string]policyInitFuncs{
 	"http://opcfoundation.org/UA/SecurityPolicy#None": {
 		asymmetricInitFunc: newNoneAsymmetric,
 		symmetricInitFunc:  newNoneSymmetric,
+		nonceLength:        0,
 	},
 	"http://opcfoundation.org/UA/SecurityPolicy#Basic128Rsa15": { // Obsolete in OPC-UA 1.04
 		asymmetricInitFunc: newBasic128Rsa15Asymmetric,
 		symmetricInitFunc:  newBasic128Rsa15Symmetric,
+		nonceLength:        16,
 	},
 	"http://opcfoundation.org/UA/SecurityPolicy#Basic256": { // Obsolete in OPC-UA 1.04
 		asymmetricInitFunc: newBasic256Asymmetric,
 		symmetricInitFunc:  newBasic256Symmetric,
+		nonceLength:        32,
 	},
 	"http://opcfoundation.org/UA/SecurityPolicy#Basic256Sha256": {
 		asymmetricInitFunc: newBasic256Rsa256Asymmetric,
 		symmetricInitFunc:  newBasic256Rsa256Symmetric,
+		nonceLength:        32,
 	},
 	"http://opcfoundation.org/UA/SecurityPolicy#Aes128_Sha256_RsaOaep": {
 		asymmetricInitFunc: newAes128Sha256RsaOaepAsymmetric,
 		symmetricInitFunc:  newAes128Sha256RsaOaepSymmetric,
+		nonceLength:        32,
 	},
 	"http://opcfoundation.org/UA/SecurityPolicy#Aes256_Sha256_RsaPss": {
 		asymmetricInitFunc: newAes256Sha256RsaPssAsymmetric,
 		symmetricInitFunc:  newAes256Sha256RsaPssSymmetric,
+		nonceLength:        32,
 	},
 	// http://opcfoundation.org/UA/SecurityPolicy#PubSub_Aes128_CTR
 	// http://opcfoundation.org/UA/SecurityPolicy#PubSub_Aes256_CTR
@@ -52,4 +58,7 @@ func SupportedPolicies() []string {
 type policyInitFuncs struct {
 	asymmetricInitFunc func(localKey *rsa.PrivateKey, remoteKey *rsa.PublicKey) (*EncryptionAlgorithm, error)
 	symmetricInitFunc  func(localNonce []byte, remoteNonce []byte) (*EncryptionAlgorithm, error)
+
+	// nonceLength is the SecureChannelNonceLength, which is 0 if the nonces are not used.
+	nonceLength int
 }
@@ -0,0 +1,182 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package gopcua
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/wmnsk/gopcua/datatypes"
+	"github.com/wmnsk/gopcua/services"
+	"github.com/wmnsk/gopcua/status"
+	"github.com/wmnsk/gopcua/uacp"
+	"github.com/wmnsk/gopcua/uasc"
+)
+
+// ReadHandler reads the attributes requested by the ReadRequests the Server receives.
+//
+// Read should return a DataValue for each of the nodes to read, in the same order.
+// The attributes which cannot be read should have a bad StatusCode in the DataValue
+// rather than failing the whole request. If an error is returned, the ReadResponse
+// has BadInternalError as the ServiceResult and no results.
+type ReadHandler interface {
+	Read(ctx context.Context, req *services.ReadRequest) ([]*datatypes.DataValue, error)
+}
+
+// ReadHandlerFunc is an adapter to use a function as ReadHandler.
+type ReadHandlerFunc func(ctx context.Context, req *services.ReadRequest) ([]*datatypes.DataValue, error)
+
+// Read calls f(ctx, req).
+func (f ReadHandlerFunc) Read(ctx context.Context, req *services.ReadRequest) ([]*datatypes.DataValue, error) {
+	return f(ctx, req)
+}
+
+// Server is a minimal OPC UA server which accepts the UACP Connections, the SecureChannels
+// and the Sessions from the clients and responds to the ReadRequests with the ReadHandler.
+//
+// The other requests received on the Sessions are responded with the ServiceFault
+// of BadServiceUnsupported.
+type Server struct {
+	endpoint string
+	cfg      *uasc.Config
+	handler  ReadHandler
+}
+
+// NewServer creates a new Server listening on the endpoint given.
+//
+// If cfg is nil, the SecureChannels are opened with security mode None.
+func NewServer(endpoint string, cfg *uasc.Config, h ReadHandler) *Server {
+	if cfg == nil {
+		cfg = uasc.NewServerConfig(
+			"http://opcfoundation.org/UA/SecurityPolicy#None", nil, nil,
+			1, services.SecModeNone, 1, 3600000,
+		)
+	}
+	return &Server{
+		endpoint: endpoint,
+		cfg:      cfg,
+		handler:  h,
+	}
+}
+
+// ListenAndServe listens on the endpoint of the Server and serves the clients
+// connected until ctx is done.
+func (s *Server) ListenAndServe(ctx context.Context) error {
+	ln, err := uacp.Listen(s.endpoint, 0xffff)
+	if err != nil {
+		return err
+	}
+	return s.Serve(ctx, ln)
+}
+
+// Serve accepts the connections on ln and serves each of them in a new goroutine
+// until ctx is done. The ln is closed when Serve returns.
+func (s *Server) Serve(ctx context.Context, ln *uacp.Listener) error {
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+		case <-done:
+		}
+		// unblocks Accept.
+		ln.Close()
+	}()
+
+	for {
+		conn, err := ln.Accept(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+		if conn == nil {
+			continue
+		}
+		go s.serveConn(ctx, conn)
+	}
+}
+
+// serveConn opens the SecureChannel and the Session on conn and responds to
+// the requests until the Session is closed or lost.
+func (s *Server) serveConn(ctx context.Context, conn *uacp.Conn) {
+	defer conn.Close()
+
+	secChan, err := uasc.ListenAndAcceptSecureChannel(ctx, conn, s.cfg)
+	if err != nil {
+		return
+	}
+	defer secChan.Close()
+
+	session, err := uasc.ListenAndAcceptSession(ctx, secChan, uasc.NewServerSessionConfig(secChan))
+	if err != nil {
+		return
+	}
+
+	buf := make([]byte, 0xffff)
+	for {
+		n, err := session.ReadService(buf)
+		if err != nil {
+			if err == io.ErrShortBuffer {
+				// the request is read again after extending buf.
+				buf = make([]byte, n)
+				continue
+			}
+			if _, ok := err.(*uasc.AbortError); ok {
+				// the request aborted by the client is not responded.
+				continue
+			}
+			return
+		}
+		srv, err := services.Decode(buf[:n])
+		if err != nil {
+			continue
+		}
+
+		var res services.Service
+		switch req := srv.(type) {
+		case *services.ReadRequest:
+			res = s.read(ctx, req)
+		case interface {
+			Header() *services.RequestHeader
+		}:
+			res = services.NewServiceFault(newServerResponseHeader(req.Header().RequestHandle, status.BadServiceUnsupported))
+		default:
+			continue
+		}
+
+		b, err := res.Serialize()
+		if err != nil {
+			continue
+		}
+		if _, err := session.WriteService(b); err != nil {
+			return
+		}
+	}
+}
+
+// read calls the ReadHandler and returns the ReadResponse for req.
+func (s *Server) read(ctx context.Context, req *services.ReadRequest) *services.ReadResponse {
+	var nodes int
+	if req.NodesToRead != nil {
+		nodes = len(req.NodesToRead.ReadValueIDs)
+	}
+
+	results, err := s.handler.Read(ctx, req)
+	if err != nil || len(results) != nodes {
+		return services.NewReadResponse(newServerResponseHeader(req.RequestHandle, status.BadInternalError), nil)
+	}
+	return services.NewReadResponse(newServerResponseHeader(req.RequestHandle, 0), nil, results...)
+}
+
+// newServerResponseHeader creates a ResponseHeader for the request with the handle given.
+func newServerResponseHeader(handle, code uint32) *services.ResponseHeader {
+	return services.NewResponseHeader(
+		time.Now(), handle, code, services.NewNullDiagnosticInfo(),
+		[]string{}, services.NewNullAdditionalHeader(), nil,
+	)
+}
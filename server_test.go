@@ -0,0 +1,129 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package gopcua
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/wmnsk/gopcua/datatypes"
+	"github.com/wmnsk/gopcua/errors"
+	"github.com/wmnsk/gopcua/services"
+	"github.com/wmnsk/gopcua/status"
+	"github.com/wmnsk/gopcua/uacp"
+)
+
+func TestServerRead(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ln, err := uacp.Listen(endpoint, 0xffff)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the server has a single variable, and fails to read the others.
+	temp := datatypes.NewStringNodeID(2, "Temperature")
+	srv := NewServer(endpoint, nil, ReadHandlerFunc(func(ctx context.Context, req *services.ReadRequest) ([]*datatypes.DataValue, error) {
+		var results []*datatypes.DataValue
+		for _, r := range req.NodesToRead.ReadValueIDs {
+			if r.NodeID.String() != temp.String() || r.AttributeID != datatypes.AttributeIDValue {
+				return nil, errors.New("unknown node")
+			}
+			results = append(results, newValue(datatypes.NewFloat(21.5)))
+		}
+		return results, nil
+	}))
+	served := make(chan error, 1)
+	go func() {
+		served <- srv.Serve(ctx, ln)
+	}()
+
+	c := NewClient(endpoint)
+	if err := c.Connect(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	v, err := c.Node(temp).Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(v, datatypes.NewVariant(datatypes.NewFloat(21.5))); diff != "" {
+		t.Error(diff)
+	}
+
+	if _, err := c.Node(datatypes.NewStringNodeID(2, "Pressure")).Value(); err == nil {
+		t.Error("expected error for unknown node, got nil")
+	}
+
+	cancel()
+	if err := <-served; err != context.Canceled {
+		t.Errorf("got %v want %v", err, context.Canceled)
+	}
+}
+
+func TestServerLargeAndUnsupportedRequests(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// the buffers are large enough for the ReadRequest larger than the one the server
+	// reads it into first, as the Client sends each request in a single chunk.
+	limits := uacp.DefaultLimits
+	limits.ReceiveBufSize, limits.SendBufSize = 1<<20, 1<<20
+	ln, err := uacp.ListenWithLimits(endpoint, limits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := NewServer(endpoint, nil, ReadHandlerFunc(func(ctx context.Context, req *services.ReadRequest) ([]*datatypes.DataValue, error) {
+		results := make([]*datatypes.DataValue, len(req.NodesToRead.ReadValueIDs))
+		for i := range results {
+			results[i] = newValue(datatypes.NewFloat(21.5))
+		}
+		return results, nil
+	}))
+	served := make(chan error, 1)
+	go func() {
+		served <- srv.Serve(ctx, ln)
+	}()
+
+	conn, err := uacp.DialWithLimits(ctx, endpoint, limits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := NewClient(endpoint)
+	if err := c.prepare(); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.open(ctx, conn); err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	nodes := make([]*datatypes.ReadValueID, 4000)
+	for i := range nodes {
+		nodes[i] = datatypes.NewReadValueID(datatypes.NewStringNodeID(2, "Temperature"), datatypes.AttributeIDValue, "", 0, "")
+	}
+	res, err := c.ReadWithContext(ctx, 0, services.TimestampsToReturnNeither, nodes...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(res.Results.DataValues), len(nodes); got != want {
+		t.Errorf("got %d results want %d", got, want)
+	}
+
+	// the requests other than Read are not supported.
+	_, err = c.RegisterNodesWithContext(ctx, []*datatypes.NodeID{datatypes.NewStringNodeID(2, "Temperature")})
+	if e, ok := errors.Cause(err).(*ServiceError); !ok || e.Code != status.BadServiceUnsupported {
+		t.Errorf("got error %v want %v", err, status.StatusCode(status.BadServiceUnsupported))
+	}
+
+	cancel()
+	if err := <-served; err != context.Canceled {
+		t.Errorf("got %v want %v", err, context.Canceled)
+	}
+}
@@ -0,0 +1,122 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package services
+
+import (
+	"fmt"
+
+	"github.com/wmnsk/gopcua/datatypes"
+)
+
+// AddNodesRequest is used to add one or more Nodes into the AddressSpace hierarchy.
+//
+// Specification: Part 4, 5.7.2.2
+type AddNodesRequest struct {
+	TypeID *datatypes.ExpandedNodeID
+	*RequestHeader
+	NodesToAdd *datatypes.AddNodesItemArray
+}
+
+// NewAddNodesRequest creates a new AddNodesRequest.
+func NewAddNodesRequest(reqHeader *RequestHeader, items ...*datatypes.AddNodesItem) *AddNodesRequest {
+	return &AddNodesRequest{
+		TypeID:        datatypes.NewFourByteExpandedNodeID(0, ServiceTypeAddNodesRequest),
+		RequestHeader: reqHeader,
+		NodesToAdd:    datatypes.NewAddNodesItemArray(items),
+	}
+}
+
+// DecodeAddNodesRequest decodes given bytes into AddNodesRequest.
+func DecodeAddNodesRequest(b []byte) (*AddNodesRequest, error) {
+	r := &AddNodesRequest{}
+	if err := r.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// DecodeFromBytes decodes given bytes into AddNodesRequest.
+func (r *AddNodesRequest) DecodeFromBytes(b []byte) error {
+	var offset = 0
+	r.TypeID = &datatypes.ExpandedNodeID{}
+	if err := r.TypeID.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += r.TypeID.Len()
+
+	r.RequestHeader = &RequestHeader{}
+	if err := r.RequestHeader.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += r.RequestHeader.Len() - len(r.RequestHeader.Payload)
+
+	r.NodesToAdd = &datatypes.AddNodesItemArray{}
+	return r.NodesToAdd.DecodeFromBytes(b[offset:])
+}
+
+// Serialize serializes AddNodesRequest into bytes.
+func (r *AddNodesRequest) Serialize() ([]byte, error) {
+	b := make([]byte, r.Len())
+	if err := r.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes AddNodesRequest into bytes.
+func (r *AddNodesRequest) SerializeTo(b []byte) error {
+	var offset = 0
+	if r.TypeID != nil {
+		if err := r.TypeID.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += r.TypeID.Len()
+	}
+
+	if r.RequestHeader != nil {
+		if err := r.RequestHeader.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += r.RequestHeader.Len()
+	}
+
+	if r.NodesToAdd != nil {
+		return r.NodesToAdd.SerializeTo(b[offset:])
+	}
+
+	return nil
+}
+
+// Len returns the actual length of AddNodesRequest in int.
+func (r *AddNodesRequest) Len() int {
+	var l = 0
+	if r.TypeID != nil {
+		l += r.TypeID.Len()
+	}
+	if r.RequestHeader != nil {
+		l += r.RequestHeader.Len()
+	}
+	if r.NodesToAdd != nil {
+		l += r.NodesToAdd.Len()
+	}
+
+	return l
+}
+
+// String returns AddNodesRequest in string.
+func (r *AddNodesRequest) String() string {
+	return fmt.Sprintf("%v, %v, %v",
+		r.TypeID,
+		r.RequestHeader,
+		r.NodesToAdd,
+	)
+}
+
+// ServiceType returns type of Service in uint16.
+func (r *AddNodesRequest) ServiceType() uint16 {
+	return ServiceTypeAddNodesRequest
+}
@@ -0,0 +1,145 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package services
+
+import (
+	"fmt"
+
+	"github.com/wmnsk/gopcua/datatypes"
+)
+
+// AddNodesResponse returns the AddNodesResults for the Nodes in the AddNodesRequest,
+// in the same order.
+//
+// Specification: Part 4, 5.7.2.2
+type AddNodesResponse struct {
+	TypeID *datatypes.ExpandedNodeID
+	*ResponseHeader
+	Results         *datatypes.AddNodesResultArray
+	DiagnosticInfos *DiagnosticInfoArray
+}
+
+// NewAddNodesResponse creates a new AddNodesResponse.
+func NewAddNodesResponse(resHeader *ResponseHeader, diags []*DiagnosticInfo, results ...*datatypes.AddNodesResult) *AddNodesResponse {
+	return &AddNodesResponse{
+		TypeID:          datatypes.NewFourByteExpandedNodeID(0, ServiceTypeAddNodesResponse),
+		ResponseHeader:  resHeader,
+		Results:         datatypes.NewAddNodesResultArray(results),
+		DiagnosticInfos: NewDiagnosticInfoArray(diags),
+	}
+}
+
+// DecodeAddNodesResponse decodes given bytes into AddNodesResponse.
+func DecodeAddNodesResponse(b []byte) (*AddNodesResponse, error) {
+	r := &AddNodesResponse{}
+	if err := r.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// DecodeFromBytes decodes given bytes into AddNodesResponse.
+func (r *AddNodesResponse) DecodeFromBytes(b []byte) error {
+	var offset = 0
+	r.TypeID = &datatypes.ExpandedNodeID{}
+	if err := r.TypeID.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += r.TypeID.Len()
+
+	r.ResponseHeader = &ResponseHeader{}
+	if err := r.ResponseHeader.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += r.ResponseHeader.Len() - len(r.ResponseHeader.Payload)
+
+	r.Results = &datatypes.AddNodesResultArray{}
+	if err := r.Results.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += r.Results.Len()
+
+	r.DiagnosticInfos = &DiagnosticInfoArray{}
+	return r.DiagnosticInfos.DecodeFromBytes(b[offset:])
+}
+
+// Serialize serializes AddNodesResponse into bytes.
+func (r *AddNodesResponse) Serialize() ([]byte, error) {
+	b := make([]byte, r.Len())
+	if err := r.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes AddNodesResponse into bytes.
+func (r *AddNodesResponse) SerializeTo(b []byte) error {
+	var offset = 0
+	if r.TypeID != nil {
+		if err := r.TypeID.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += r.TypeID.Len()
+	}
+
+	if r.ResponseHeader != nil {
+		if err := r.ResponseHeader.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += r.ResponseHeader.Len()
+	}
+
+	if r.Results != nil {
+		if err := r.Results.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += r.Results.Len()
+	}
+
+	if r.DiagnosticInfos != nil {
+		return r.DiagnosticInfos.SerializeTo(b[offset:])
+	}
+
+	return nil
+}
+
+// Len returns the actual length of AddNodesResponse in int.
+func (r *AddNodesResponse) Len() int {
+	l := 0
+	if r.TypeID != nil {
+		l += r.TypeID.Len()
+	}
+
+	if r.ResponseHeader != nil {
+		l += r.ResponseHeader.Len()
+	}
+
+	if r.Results != nil {
+		l += r.Results.Len()
+	}
+
+	if r.DiagnosticInfos != nil {
+		l += r.DiagnosticInfos.Len()
+	}
+
+	return l
+}
+
+// String returns AddNodesResponse in string.
+func (r *AddNodesResponse) String() string {
+	return fmt.Sprintf("%v, %v, %v, %v",
+		r.TypeID,
+		r.ResponseHeader,
+		r.Results,
+		r.DiagnosticInfos,
+	)
+}
+
+// ServiceType returns type of Service in uint16.
+func (r *AddNodesResponse) ServiceType() uint16 {
+	return ServiceTypeAddNodesResponse
+}
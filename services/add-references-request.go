@@ -0,0 +1,122 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package services
+
+import (
+	"fmt"
+
+	"github.com/wmnsk/gopcua/datatypes"
+)
+
+// AddReferencesRequest is used to add one or more References between Nodes.
+//
+// Specification: Part 4, 5.7.3.2
+type AddReferencesRequest struct {
+	TypeID *datatypes.ExpandedNodeID
+	*RequestHeader
+	ReferencesToAdd *datatypes.AddReferencesItemArray
+}
+
+// NewAddReferencesRequest creates a new AddReferencesRequest.
+func NewAddReferencesRequest(reqHeader *RequestHeader, items ...*datatypes.AddReferencesItem) *AddReferencesRequest {
+	return &AddReferencesRequest{
+		TypeID:          datatypes.NewFourByteExpandedNodeID(0, ServiceTypeAddReferencesRequest),
+		RequestHeader:   reqHeader,
+		ReferencesToAdd: datatypes.NewAddReferencesItemArray(items),
+	}
+}
+
+// DecodeAddReferencesRequest decodes given bytes into AddReferencesRequest.
+func DecodeAddReferencesRequest(b []byte) (*AddReferencesRequest, error) {
+	r := &AddReferencesRequest{}
+	if err := r.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// DecodeFromBytes decodes given bytes into AddReferencesRequest.
+func (r *AddReferencesRequest) DecodeFromBytes(b []byte) error {
+	var offset = 0
+	r.TypeID = &datatypes.ExpandedNodeID{}
+	if err := r.TypeID.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += r.TypeID.Len()
+
+	r.RequestHeader = &RequestHeader{}
+	if err := r.RequestHeader.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += r.RequestHeader.Len() - len(r.RequestHeader.Payload)
+
+	r.ReferencesToAdd = &datatypes.AddReferencesItemArray{}
+	return r.ReferencesToAdd.DecodeFromBytes(b[offset:])
+}
+
+// Serialize serializes AddReferencesRequest into bytes.
+func (r *AddReferencesRequest) Serialize() ([]byte, error) {
+	b := make([]byte, r.Len())
+	if err := r.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes AddReferencesRequest into bytes.
+func (r *AddReferencesRequest) SerializeTo(b []byte) error {
+	var offset = 0
+	if r.TypeID != nil {
+		if err := r.TypeID.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += r.TypeID.Len()
+	}
+
+	if r.RequestHeader != nil {
+		if err := r.RequestHeader.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += r.RequestHeader.Len()
+	}
+
+	if r.ReferencesToAdd != nil {
+		return r.ReferencesToAdd.SerializeTo(b[offset:])
+	}
+
+	return nil
+}
+
+// Len returns the actual length of AddReferencesRequest in int.
+func (r *AddReferencesRequest) Len() int {
+	var l = 0
+	if r.TypeID != nil {
+		l += r.TypeID.Len()
+	}
+	if r.RequestHeader != nil {
+		l += r.RequestHeader.Len()
+	}
+	if r.ReferencesToAdd != nil {
+		l += r.ReferencesToAdd.Len()
+	}
+
+	return l
+}
+
+// String returns AddReferencesRequest in string.
+func (r *AddReferencesRequest) String() string {
+	return fmt.Sprintf("%v, %v, %v",
+		r.TypeID,
+		r.RequestHeader,
+		r.ReferencesToAdd,
+	)
+}
+
+// ServiceType returns type of Service in uint16.
+func (r *AddReferencesRequest) ServiceType() uint16 {
+	return ServiceTypeAddReferencesRequest
+}
@@ -0,0 +1,145 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package services
+
+import (
+	"fmt"
+
+	"github.com/wmnsk/gopcua/datatypes"
+)
+
+// AddReferencesResponse returns the StatusCodes for the References in the AddReferencesRequest,
+// in the same order.
+//
+// Specification: Part 4, 5.7.3.2
+type AddReferencesResponse struct {
+	TypeID *datatypes.ExpandedNodeID
+	*ResponseHeader
+	Results         *datatypes.Uint32Array
+	DiagnosticInfos *DiagnosticInfoArray
+}
+
+// NewAddReferencesResponse creates a new AddReferencesResponse.
+func NewAddReferencesResponse(resHeader *ResponseHeader, diags []*DiagnosticInfo, results ...uint32) *AddReferencesResponse {
+	return &AddReferencesResponse{
+		TypeID:          datatypes.NewFourByteExpandedNodeID(0, ServiceTypeAddReferencesResponse),
+		ResponseHeader:  resHeader,
+		Results:         datatypes.NewUint32Array(results),
+		DiagnosticInfos: NewDiagnosticInfoArray(diags),
+	}
+}
+
+// DecodeAddReferencesResponse decodes given bytes into AddReferencesResponse.
+func DecodeAddReferencesResponse(b []byte) (*AddReferencesResponse, error) {
+	r := &AddReferencesResponse{}
+	if err := r.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// DecodeFromBytes decodes given bytes into AddReferencesResponse.
+func (r *AddReferencesResponse) DecodeFromBytes(b []byte) error {
+	var offset = 0
+	r.TypeID = &datatypes.ExpandedNodeID{}
+	if err := r.TypeID.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += r.TypeID.Len()
+
+	r.ResponseHeader = &ResponseHeader{}
+	if err := r.ResponseHeader.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += r.ResponseHeader.Len() - len(r.ResponseHeader.Payload)
+
+	r.Results = &datatypes.Uint32Array{}
+	if err := r.Results.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += r.Results.Len()
+
+	r.DiagnosticInfos = &DiagnosticInfoArray{}
+	return r.DiagnosticInfos.DecodeFromBytes(b[offset:])
+}
+
+// Serialize serializes AddReferencesResponse into bytes.
+func (r *AddReferencesResponse) Serialize() ([]byte, error) {
+	b := make([]byte, r.Len())
+	if err := r.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes AddReferencesResponse into bytes.
+func (r *AddReferencesResponse) SerializeTo(b []byte) error {
+	var offset = 0
+	if r.TypeID != nil {
+		if err := r.TypeID.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += r.TypeID.Len()
+	}
+
+	if r.ResponseHeader != nil {
+		if err := r.ResponseHeader.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += r.ResponseHeader.Len()
+	}
+
+	if r.Results != nil {
+		if err := r.Results.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += r.Results.Len()
+	}
+
+	if r.DiagnosticInfos != nil {
+		return r.DiagnosticInfos.SerializeTo(b[offset:])
+	}
+
+	return nil
+}
+
+// Len returns the actual length of AddReferencesResponse in int.
+func (r *AddReferencesResponse) Len() int {
+	l := 0
+	if r.TypeID != nil {
+		l += r.TypeID.Len()
+	}
+
+	if r.ResponseHeader != nil {
+		l += r.ResponseHeader.Len()
+	}
+
+	if r.Results != nil {
+		l += r.Results.Len()
+	}
+
+	if r.DiagnosticInfos != nil {
+		l += r.DiagnosticInfos.Len()
+	}
+
+	return l
+}
+
+// String returns AddReferencesResponse in string.
+func (r *AddReferencesResponse) String() string {
+	return fmt.Sprintf("%v, %v, %v, %v",
+		r.TypeID,
+		r.ResponseHeader,
+		r.Results,
+		r.DiagnosticInfos,
+	)
+}
+
+// ServiceType returns type of Service in uint16.
+func (r *AddReferencesResponse) ServiceType() uint16 {
+	return ServiceTypeAddReferencesResponse
+}
@@ -0,0 +1,136 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package services
+
+import (
+	"github.com/wmnsk/gopcua/datatypes"
+)
+
+// BrowseNextRequest is used to request the next set of Browse or BrowseNext
+// response information that is too large to be sent in a single response.
+//
+// Specification: Part 4, 5.8.3.2
+type BrowseNextRequest struct {
+	TypeID *datatypes.ExpandedNodeID
+	*RequestHeader
+
+	// If true, the ContinuationPoints are released and no References are returned.
+	ReleaseContinuationPoints *datatypes.Boolean
+
+	// A list of Server-defined opaque values that represent continuation points.
+	ContinuationPoints *datatypes.ByteStringArray
+}
+
+// NewBrowseNextRequest creates a new BrowseNextRequest.
+func NewBrowseNextRequest(reqHeader *RequestHeader, release bool, cps ...[]byte) *BrowseNextRequest {
+	return &BrowseNextRequest{
+		TypeID:                    datatypes.NewFourByteExpandedNodeID(0, ServiceTypeBrowseNextRequest),
+		RequestHeader:             reqHeader,
+		ReleaseContinuationPoints: datatypes.NewBoolean(release),
+		ContinuationPoints:        datatypes.NewByteStringArray(cps),
+	}
+}
+
+// DecodeBrowseNextRequest decodes given bytes into BrowseNextRequest.
+func DecodeBrowseNextRequest(b []byte) (*BrowseNextRequest, error) {
+	r := &BrowseNextRequest{}
+	if err := r.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// DecodeFromBytes decodes given bytes into BrowseNextRequest.
+func (r *BrowseNextRequest) DecodeFromBytes(b []byte) error {
+	offset := 0
+	r.TypeID = &datatypes.ExpandedNodeID{}
+	if err := r.TypeID.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += r.TypeID.Len()
+
+	r.RequestHeader = &RequestHeader{}
+	if err := r.RequestHeader.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += r.RequestHeader.Len() - len(r.RequestHeader.Payload)
+
+	r.ReleaseContinuationPoints = &datatypes.Boolean{}
+	if err := r.ReleaseContinuationPoints.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += r.ReleaseContinuationPoints.Len()
+
+	r.ContinuationPoints = &datatypes.ByteStringArray{}
+	return r.ContinuationPoints.DecodeFromBytes(b[offset:])
+}
+
+// Serialize serializes BrowseNextRequest into bytes.
+func (r *BrowseNextRequest) Serialize() ([]byte, error) {
+	b := make([]byte, r.Len())
+	if err := r.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes BrowseNextRequest into bytes.
+func (r *BrowseNextRequest) SerializeTo(b []byte) error {
+	offset := 0
+	if r.TypeID != nil {
+		if err := r.TypeID.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += r.TypeID.Len()
+	}
+
+	if r.RequestHeader != nil {
+		if err := r.RequestHeader.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += r.RequestHeader.Len()
+	}
+
+	if r.ReleaseContinuationPoints != nil {
+		if err := r.ReleaseContinuationPoints.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += r.ReleaseContinuationPoints.Len()
+	}
+
+	if r.ContinuationPoints != nil {
+		return r.ContinuationPoints.SerializeTo(b[offset:])
+	}
+
+	return nil
+}
+
+// Len returns the actual length of BrowseNextRequest.
+func (r *BrowseNextRequest) Len() int {
+	l := 0
+	if r.TypeID != nil {
+		l += r.TypeID.Len()
+	}
+
+	if r.RequestHeader != nil {
+		l += r.RequestHeader.Len()
+	}
+
+	if r.ReleaseContinuationPoints != nil {
+		l += r.ReleaseContinuationPoints.Len()
+	}
+
+	if r.ContinuationPoints != nil {
+		l += r.ContinuationPoints.Len()
+	}
+
+	return l
+}
+
+// ServiceType returns type of Service in uint16.
+func (r *BrowseNextRequest) ServiceType() uint16 {
+	return ServiceTypeBrowseNextRequest
+}
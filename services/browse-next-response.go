@@ -0,0 +1,145 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package services
+
+import (
+	"fmt"
+
+	"github.com/wmnsk/gopcua/datatypes"
+)
+
+// BrowseNextResponse is returned by the Server for the BrowseNextRequest.
+// It has a BrowseResult for each continuation point.
+//
+// Specification: Part 4, 5.8.3.2
+type BrowseNextResponse struct {
+	TypeID *datatypes.ExpandedNodeID
+	*ResponseHeader
+	Results         *datatypes.BrowseResultArray
+	DiagnosticInfos *DiagnosticInfoArray
+}
+
+// NewBrowseNextResponse creates a new BrowseNextResponse.
+func NewBrowseNextResponse(resHeader *ResponseHeader, diags []*DiagnosticInfo, results ...*datatypes.BrowseResult) *BrowseNextResponse {
+	return &BrowseNextResponse{
+		TypeID:          datatypes.NewFourByteExpandedNodeID(0, ServiceTypeBrowseNextResponse),
+		ResponseHeader:  resHeader,
+		Results:         datatypes.NewBrowseResultArray(results),
+		DiagnosticInfos: NewDiagnosticInfoArray(diags),
+	}
+}
+
+// DecodeBrowseNextResponse decodes given bytes into BrowseNextResponse.
+func DecodeBrowseNextResponse(b []byte) (*BrowseNextResponse, error) {
+	r := &BrowseNextResponse{}
+	if err := r.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// DecodeFromBytes decodes given bytes into BrowseNextResponse.
+func (r *BrowseNextResponse) DecodeFromBytes(b []byte) error {
+	var offset = 0
+	r.TypeID = &datatypes.ExpandedNodeID{}
+	if err := r.TypeID.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += r.TypeID.Len()
+
+	r.ResponseHeader = &ResponseHeader{}
+	if err := r.ResponseHeader.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += r.ResponseHeader.Len() - len(r.ResponseHeader.Payload)
+
+	r.Results = &datatypes.BrowseResultArray{}
+	if err := r.Results.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += r.Results.Len()
+
+	r.DiagnosticInfos = &DiagnosticInfoArray{}
+	return r.DiagnosticInfos.DecodeFromBytes(b[offset:])
+}
+
+// Serialize serializes BrowseNextResponse into bytes.
+func (r *BrowseNextResponse) Serialize() ([]byte, error) {
+	b := make([]byte, r.Len())
+	if err := r.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes BrowseNextResponse into bytes.
+func (r *BrowseNextResponse) SerializeTo(b []byte) error {
+	var offset = 0
+	if r.TypeID != nil {
+		if err := r.TypeID.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += r.TypeID.Len()
+	}
+
+	if r.ResponseHeader != nil {
+		if err := r.ResponseHeader.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += r.ResponseHeader.Len()
+	}
+
+	if r.Results != nil {
+		if err := r.Results.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += r.Results.Len()
+	}
+
+	if r.DiagnosticInfos != nil {
+		return r.DiagnosticInfos.SerializeTo(b[offset:])
+	}
+
+	return nil
+}
+
+// Len returns the actual length of BrowseNextResponse in int.
+func (r *BrowseNextResponse) Len() int {
+	l := 0
+	if r.TypeID != nil {
+		l += r.TypeID.Len()
+	}
+
+	if r.ResponseHeader != nil {
+		l += r.ResponseHeader.Len()
+	}
+
+	if r.Results != nil {
+		l += r.Results.Len()
+	}
+
+	if r.DiagnosticInfos != nil {
+		l += r.DiagnosticInfos.Len()
+	}
+
+	return l
+}
+
+// String returns BrowseNextResponse in string.
+func (r *BrowseNextResponse) String() string {
+	return fmt.Sprintf("%v, %v, %v, %v",
+		r.TypeID,
+		r.ResponseHeader,
+		r.Results,
+		r.DiagnosticInfos,
+	)
+}
+
+// ServiceType returns type of Service in uint16.
+func (r *BrowseNextResponse) ServiceType() uint16 {
+	return ServiceTypeBrowseNextResponse
+}
@@ -0,0 +1,139 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package services
+
+import (
+	"encoding/binary"
+
+	"github.com/wmnsk/gopcua/datatypes"
+)
+
+// BrowseRequest is used to discover the References of one or more Nodes.
+//
+// Specification: Part 4, 5.8.2.2
+type BrowseRequest struct {
+	TypeID *datatypes.ExpandedNodeID
+	*RequestHeader
+
+	// Description of the View to browse. An empty ViewDescription
+	// browses the entire AddressSpace.
+	View *datatypes.ViewDescription
+
+	// The maximum number of References to return for each starting Node.
+	// The value 0 indicates that the Client is imposing no limitation.
+	RequestedMaxReferencesPerNode uint32
+
+	// A list of Nodes to Browse.
+	NodesToBrowse *datatypes.BrowseDescriptionArray
+}
+
+// NewBrowseRequest creates a new BrowseRequest.
+func NewBrowseRequest(reqHeader *RequestHeader, view *datatypes.ViewDescription, maxRefs uint32, nodes ...*datatypes.BrowseDescription) *BrowseRequest {
+	return &BrowseRequest{
+		TypeID:                        datatypes.NewFourByteExpandedNodeID(0, ServiceTypeBrowseRequest),
+		RequestHeader:                 reqHeader,
+		View:                          view,
+		RequestedMaxReferencesPerNode: maxRefs,
+		NodesToBrowse:                 datatypes.NewBrowseDescriptionArray(nodes),
+	}
+}
+
+// DecodeBrowseRequest decodes given bytes into BrowseRequest.
+func DecodeBrowseRequest(b []byte) (*BrowseRequest, error) {
+	r := &BrowseRequest{}
+	if err := r.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// DecodeFromBytes decodes given bytes into BrowseRequest.
+func (r *BrowseRequest) DecodeFromBytes(b []byte) error {
+	offset := 0
+	r.TypeID = &datatypes.ExpandedNodeID{}
+	if err := r.TypeID.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += r.TypeID.Len()
+
+	r.RequestHeader = &RequestHeader{}
+	if err := r.RequestHeader.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += r.RequestHeader.Len() - len(r.RequestHeader.Payload)
+
+	r.View = &datatypes.ViewDescription{}
+	if err := r.View.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += r.View.Len()
+
+	r.RequestedMaxReferencesPerNode = binary.LittleEndian.Uint32(b[offset : offset+4])
+	offset += 4
+
+	r.NodesToBrowse = &datatypes.BrowseDescriptionArray{}
+	return r.NodesToBrowse.DecodeFromBytes(b[offset:])
+}
+
+// Serialize serializes BrowseRequest into bytes.
+func (r *BrowseRequest) Serialize() ([]byte, error) {
+	b := make([]byte, r.Len())
+	if err := r.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes BrowseRequest into bytes.
+func (r *BrowseRequest) SerializeTo(b []byte) error {
+	offset := 0
+	if err := r.TypeID.SerializeTo(b[offset:]); err != nil {
+		return err
+	}
+	offset += r.TypeID.Len()
+
+	if err := r.RequestHeader.SerializeTo(b[offset:]); err != nil {
+		return err
+	}
+	offset += r.RequestHeader.Len()
+
+	if err := r.View.SerializeTo(b[offset:]); err != nil {
+		return err
+	}
+	offset += r.View.Len()
+
+	binary.LittleEndian.PutUint32(b[offset:offset+4], r.RequestedMaxReferencesPerNode)
+	offset += 4
+
+	return r.NodesToBrowse.SerializeTo(b[offset:])
+}
+
+// Len returns the actual length of BrowseRequest.
+func (r *BrowseRequest) Len() int {
+	l := 4
+	if r.TypeID != nil {
+		l += r.TypeID.Len()
+	}
+
+	if r.RequestHeader != nil {
+		l += r.RequestHeader.Len()
+	}
+
+	if r.View != nil {
+		l += r.View.Len()
+	}
+
+	if r.NodesToBrowse != nil {
+		l += r.NodesToBrowse.Len()
+	}
+
+	return l
+}
+
+// ServiceType returns type of Service in uint16.
+func (r *BrowseRequest) ServiceType() uint16 {
+	return ServiceTypeBrowseRequest
+}
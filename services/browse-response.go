@@ -0,0 +1,145 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package services
+
+import (
+	"fmt"
+
+	"github.com/wmnsk/gopcua/datatypes"
+)
+
+// BrowseResponse is returned by the Server for the BrowseRequest.
+// It has a BrowseResult for each Node to browse.
+//
+// Specification: Part 4, 5.8.2.2
+type BrowseResponse struct {
+	TypeID *datatypes.ExpandedNodeID
+	*ResponseHeader
+	Results         *datatypes.BrowseResultArray
+	DiagnosticInfos *DiagnosticInfoArray
+}
+
+// NewBrowseResponse creates a new BrowseResponse.
+func NewBrowseResponse(resHeader *ResponseHeader, diags []*DiagnosticInfo, results ...*datatypes.BrowseResult) *BrowseResponse {
+	return &BrowseResponse{
+		TypeID:          datatypes.NewFourByteExpandedNodeID(0, ServiceTypeBrowseResponse),
+		ResponseHeader:  resHeader,
+		Results:         datatypes.NewBrowseResultArray(results),
+		DiagnosticInfos: NewDiagnosticInfoArray(diags),
+	}
+}
+
+// DecodeBrowseResponse decodes given bytes into BrowseResponse.
+func DecodeBrowseResponse(b []byte) (*BrowseResponse, error) {
+	r := &BrowseResponse{}
+	if err := r.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// DecodeFromBytes decodes given bytes into BrowseResponse.
+func (r *BrowseResponse) DecodeFromBytes(b []byte) error {
+	var offset = 0
+	r.TypeID = &datatypes.ExpandedNodeID{}
+	if err := r.TypeID.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += r.TypeID.Len()
+
+	r.ResponseHeader = &ResponseHeader{}
+	if err := r.ResponseHeader.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += r.ResponseHeader.Len() - len(r.ResponseHeader.Payload)
+
+	r.Results = &datatypes.BrowseResultArray{}
+	if err := r.Results.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += r.Results.Len()
+
+	r.DiagnosticInfos = &DiagnosticInfoArray{}
+	return r.DiagnosticInfos.DecodeFromBytes(b[offset:])
+}
+
+// Serialize serializes BrowseResponse into bytes.
+func (r *BrowseResponse) Serialize() ([]byte, error) {
+	b := make([]byte, r.Len())
+	if err := r.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes BrowseResponse into bytes.
+func (r *BrowseResponse) SerializeTo(b []byte) error {
+	var offset = 0
+	if r.TypeID != nil {
+		if err := r.TypeID.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += r.TypeID.Len()
+	}
+
+	if r.ResponseHeader != nil {
+		if err := r.ResponseHeader.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += r.ResponseHeader.Len()
+	}
+
+	if r.Results != nil {
+		if err := r.Results.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += r.Results.Len()
+	}
+
+	if r.DiagnosticInfos != nil {
+		return r.DiagnosticInfos.SerializeTo(b[offset:])
+	}
+
+	return nil
+}
+
+// Len returns the actual length of BrowseResponse in int.
+func (r *BrowseResponse) Len() int {
+	l := 0
+	if r.TypeID != nil {
+		l += r.TypeID.Len()
+	}
+
+	if r.ResponseHeader != nil {
+		l += r.ResponseHeader.Len()
+	}
+
+	if r.Results != nil {
+		l += r.Results.Len()
+	}
+
+	if r.DiagnosticInfos != nil {
+		l += r.DiagnosticInfos.Len()
+	}
+
+	return l
+}
+
+// String returns BrowseResponse in string.
+func (r *BrowseResponse) String() string {
+	return fmt.Sprintf("%v, %v, %v, %v",
+		r.TypeID,
+		r.ResponseHeader,
+		r.Results,
+		r.DiagnosticInfos,
+	)
+}
+
+// ServiceType returns type of Service in uint16.
+func (r *BrowseResponse) ServiceType() uint16 {
+	return ServiceTypeBrowseResponse
+}
@@ -0,0 +1,90 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/wmnsk/gopcua/datatypes"
+	"github.com/wmnsk/gopcua/utils/codectest"
+)
+
+func TestBrowseResponse(t *testing.T) {
+	cases := []codectest.Case{
+		{
+			Name: "single-result",
+			Struct: NewBrowseResponse(
+				NewResponseHeader(
+					time.Date(2018, time.August, 10, 23, 0, 0, 0, time.UTC),
+					1, 0, NewNullDiagnosticInfo(), []string{}, NewNullAdditionalHeader(), nil,
+				),
+				nil,
+				datatypes.NewBrowseResult(
+					0, nil,
+					datatypes.NewReferenceDescription(
+						datatypes.NewTwoByteNodeID(35), true,
+						datatypes.NewFourByteExpandedNodeID(0, 2253),
+						datatypes.NewQualifiedName(0, "Server"),
+						datatypes.NewLocalizedText("", "Server"),
+						datatypes.NodeClassObject,
+						datatypes.NewFourByteExpandedNodeID(0, 2004),
+					),
+				),
+			),
+			Bytes: []byte{
+				// TypeID
+				0x01, 0x00, 0x12, 0x02,
+				// Timestamp
+				0x00, 0x98, 0x67, 0xdd, 0xfd, 0x30, 0xd4, 0x01,
+				// RequestHandle
+				0x01, 0x00, 0x00, 0x00,
+				// ServiceResult
+				0x00, 0x00, 0x00, 0x00,
+				// ServiceDiagnostics
+				0x00,
+				// StringTable
+				0x00, 0x00, 0x00, 0x00,
+				// AdditionalHeader
+				0x00, 0x00, 0x00,
+				// Results
+				// ArraySize
+				0x01, 0x00, 0x00, 0x00,
+				// StatusCode
+				0x00, 0x00, 0x00, 0x00,
+				// ContinuationPoint
+				0xff, 0xff, 0xff, 0xff,
+				// References
+				0x01, 0x00, 0x00, 0x00,
+				0x00, 0x23,
+				0x01,
+				0x01, 0x00, 0xcd, 0x08,
+				0x00, 0x00, 0x06, 0x00, 0x00, 0x00,
+				0x53, 0x65, 0x72, 0x76, 0x65, 0x72,
+				0x02, 0x06, 0x00, 0x00, 0x00,
+				0x53, 0x65, 0x72, 0x76, 0x65, 0x72,
+				0x01, 0x00, 0x00, 0x00,
+				0x01, 0x00, 0xd4, 0x07,
+				// DiagnosticInfos
+				0x00, 0x00, 0x00, 0x00,
+			},
+		},
+	}
+	codectest.Run(t, cases, func(b []byte) (codectest.S, error) {
+		v, err := DecodeBrowseResponse(b)
+		if err != nil {
+			return nil, err
+		}
+		v.Payload = nil
+		return v, nil
+	})
+
+	t.Run("service-id", func(t *testing.T) {
+		id := new(BrowseResponse).ServiceType()
+		if got, want := id, uint16(ServiceTypeBrowseResponse); got != want {
+			t.Fatalf("got %d want %d", got, want)
+		}
+	})
+}
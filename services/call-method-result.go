@@ -0,0 +1,222 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package services
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/wmnsk/gopcua/datatypes"
+	"github.com/wmnsk/gopcua/errors"
+)
+
+// CallMethodResult is the result of a Method called, with the output arguments of it.
+//
+// InputArgumentResults has the StatusCode for each of the input arguments,
+// which tells the invalid ones if StatusCode is BadInvalidArgument.
+//
+// Specification: Part 4, 5.11.2.2
+type CallMethodResult struct {
+	StatusCode                   uint32
+	InputArgumentResults         *datatypes.Uint32Array
+	InputArgumentDiagnosticInfos *DiagnosticInfoArray
+	OutputArguments              *datatypes.VariantArray
+}
+
+// NewCallMethodResult creates a new CallMethodResult.
+func NewCallMethodResult(code uint32, inputResults []uint32, inputDiags []*DiagnosticInfo, outputs ...*datatypes.Variant) *CallMethodResult {
+	return &CallMethodResult{
+		StatusCode:                   code,
+		InputArgumentResults:         datatypes.NewUint32Array(inputResults),
+		InputArgumentDiagnosticInfos: NewDiagnosticInfoArray(inputDiags),
+		OutputArguments:              datatypes.NewVariantArray(outputs),
+	}
+}
+
+// DecodeCallMethodResult decodes given bytes into CallMethodResult.
+func DecodeCallMethodResult(b []byte) (*CallMethodResult, error) {
+	c := &CallMethodResult{}
+	if err := c.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// DecodeFromBytes decodes given bytes into CallMethodResult.
+func (c *CallMethodResult) DecodeFromBytes(b []byte) error {
+	if len(b) < 4 {
+		return errors.NewErrTooShortToDecode(c, "should be longer than 4 bytes")
+	}
+	c.StatusCode = binary.LittleEndian.Uint32(b[:4])
+	offset := 4
+
+	c.InputArgumentResults = &datatypes.Uint32Array{}
+	if err := c.InputArgumentResults.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += c.InputArgumentResults.Len()
+
+	c.InputArgumentDiagnosticInfos = &DiagnosticInfoArray{}
+	if err := c.InputArgumentDiagnosticInfos.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += c.InputArgumentDiagnosticInfos.Len()
+
+	c.OutputArguments = &datatypes.VariantArray{}
+	return c.OutputArguments.DecodeFromBytes(b[offset:])
+}
+
+// Serialize serializes CallMethodResult into bytes.
+func (c *CallMethodResult) Serialize() ([]byte, error) {
+	b := make([]byte, c.Len())
+	if err := c.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes CallMethodResult into bytes.
+func (c *CallMethodResult) SerializeTo(b []byte) error {
+	binary.LittleEndian.PutUint32(b[:4], c.StatusCode)
+	offset := 4
+
+	if c.InputArgumentResults != nil {
+		if err := c.InputArgumentResults.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += c.InputArgumentResults.Len()
+	}
+
+	if c.InputArgumentDiagnosticInfos != nil {
+		if err := c.InputArgumentDiagnosticInfos.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += c.InputArgumentDiagnosticInfos.Len()
+	}
+
+	if c.OutputArguments != nil {
+		return c.OutputArguments.SerializeTo(b[offset:])
+	}
+
+	return nil
+}
+
+// Len returns the actual length of CallMethodResult in int.
+func (c *CallMethodResult) Len() int {
+	l := 4
+	if c.InputArgumentResults != nil {
+		l += c.InputArgumentResults.Len()
+	}
+	if c.InputArgumentDiagnosticInfos != nil {
+		l += c.InputArgumentDiagnosticInfos.Len()
+	}
+	if c.OutputArguments != nil {
+		l += c.OutputArguments.Len()
+	}
+
+	return l
+}
+
+// String returns CallMethodResult in string.
+func (c *CallMethodResult) String() string {
+	return fmt.Sprintf("%d, %v, %v, %v",
+		c.StatusCode,
+		c.InputArgumentResults,
+		c.InputArgumentDiagnosticInfos,
+		c.OutputArguments,
+	)
+}
+
+// CallMethodResultArray represents an array of CallMethodResults.
+// It does not correspond to a certain type from the specification
+// but makes encoding and decoding easier.
+type CallMethodResultArray struct {
+	ArraySize         int32
+	CallMethodResults []*CallMethodResult
+}
+
+// NewCallMethodResultArray creates a new CallMethodResultArray from multiple CallMethodResults.
+func NewCallMethodResultArray(results []*CallMethodResult) *CallMethodResultArray {
+	if results == nil {
+		return &CallMethodResultArray{
+			ArraySize: 0,
+		}
+	}
+
+	return &CallMethodResultArray{
+		ArraySize:         int32(len(results)),
+		CallMethodResults: results,
+	}
+}
+
+// DecodeCallMethodResultArray decodes given bytes into CallMethodResultArray.
+func DecodeCallMethodResultArray(b []byte) (*CallMethodResultArray, error) {
+	a := &CallMethodResultArray{}
+	if err := a.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return a, nil
+}
+
+// DecodeFromBytes decodes given bytes into CallMethodResultArray.
+func (a *CallMethodResultArray) DecodeFromBytes(b []byte) error {
+	if len(b) < 4 {
+		return errors.NewErrTooShortToDecode(a, "should be longer than 4 bytes")
+	}
+	a.ArraySize = int32(binary.LittleEndian.Uint32(b[:4]))
+	if a.ArraySize <= 0 {
+		return nil
+	}
+
+	offset := 4
+	for i := 1; i <= int(a.ArraySize); i++ {
+		c, err := DecodeCallMethodResult(b[offset:])
+		if err != nil {
+			return err
+		}
+		a.CallMethodResults = append(a.CallMethodResults, c)
+		offset += c.Len()
+	}
+
+	return nil
+}
+
+// Serialize serializes CallMethodResultArray into bytes.
+func (a *CallMethodResultArray) Serialize() ([]byte, error) {
+	b := make([]byte, a.Len())
+	if err := a.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes CallMethodResultArray into bytes.
+func (a *CallMethodResultArray) SerializeTo(b []byte) error {
+	offset := 4
+	binary.LittleEndian.PutUint32(b[:4], uint32(a.ArraySize))
+
+	for _, c := range a.CallMethodResults {
+		if err := c.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += c.Len()
+	}
+
+	return nil
+}
+
+// Len returns the actual length in int.
+func (a *CallMethodResultArray) Len() int {
+	l := 4
+	for _, c := range a.CallMethodResults {
+		l += c.Len()
+	}
+
+	return l
+}
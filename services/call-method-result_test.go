@@ -0,0 +1,54 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package services
+
+import (
+	"testing"
+
+	"github.com/wmnsk/gopcua/datatypes"
+	"github.com/wmnsk/gopcua/utils/codectest"
+)
+
+func TestCallMethodResult(t *testing.T) {
+	cases := []codectest.Case{
+		{
+			Name: "normal",
+			Struct: NewCallMethodResult(
+				0, []uint32{0, 0}, nil,
+				datatypes.NewVariant(datatypes.NewFloat(2.50025)),
+			),
+			Bytes: []byte{
+				// StatusCode
+				0x00, 0x00, 0x00, 0x00,
+				// InputArgumentResults
+				0x02, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				// InputArgumentDiagnosticInfos
+				0x00, 0x00, 0x00, 0x00,
+				// OutputArguments
+				0x01, 0x00, 0x00, 0x00,
+				0x0a, 0x19, 0x04, 0x20, 0x40,
+			},
+		},
+		{
+			Name:   "invalid argument",
+			Struct: NewCallMethodResult(0x80ab0000, []uint32{0, 0x80740000}, nil),
+			Bytes: []byte{
+				// StatusCode
+				0x00, 0x00, 0xab, 0x80,
+				// InputArgumentResults
+				0x02, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x74, 0x80,
+				// InputArgumentDiagnosticInfos
+				0x00, 0x00, 0x00, 0x00,
+				// OutputArguments
+				0x00, 0x00, 0x00, 0x00,
+			},
+		},
+	}
+	codectest.Run(t, cases, func(b []byte) (codectest.S, error) {
+		return DecodeCallMethodResult(b)
+	})
+}
@@ -0,0 +1,122 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package services
+
+import (
+	"fmt"
+
+	"github.com/wmnsk/gopcua/datatypes"
+)
+
+// CallRequest is used to call a list of Methods.
+//
+// Specification: Part 4, 5.11.2.2
+type CallRequest struct {
+	TypeID *datatypes.ExpandedNodeID
+	*RequestHeader
+	MethodsToCall *datatypes.CallMethodRequestArray
+}
+
+// NewCallRequest creates a new CallRequest.
+func NewCallRequest(reqHeader *RequestHeader, methods ...*datatypes.CallMethodRequest) *CallRequest {
+	return &CallRequest{
+		TypeID:        datatypes.NewFourByteExpandedNodeID(0, ServiceTypeCallRequest),
+		RequestHeader: reqHeader,
+		MethodsToCall: datatypes.NewCallMethodRequestArray(methods),
+	}
+}
+
+// DecodeCallRequest decodes given bytes into CallRequest.
+func DecodeCallRequest(b []byte) (*CallRequest, error) {
+	c := &CallRequest{}
+	if err := c.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// DecodeFromBytes decodes given bytes into CallRequest.
+func (c *CallRequest) DecodeFromBytes(b []byte) error {
+	var offset = 0
+	c.TypeID = &datatypes.ExpandedNodeID{}
+	if err := c.TypeID.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += c.TypeID.Len()
+
+	c.RequestHeader = &RequestHeader{}
+	if err := c.RequestHeader.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += c.RequestHeader.Len() - len(c.RequestHeader.Payload)
+
+	c.MethodsToCall = &datatypes.CallMethodRequestArray{}
+	return c.MethodsToCall.DecodeFromBytes(b[offset:])
+}
+
+// Serialize serializes CallRequest into bytes.
+func (c *CallRequest) Serialize() ([]byte, error) {
+	b := make([]byte, c.Len())
+	if err := c.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes CallRequest into bytes.
+func (c *CallRequest) SerializeTo(b []byte) error {
+	var offset = 0
+	if c.TypeID != nil {
+		if err := c.TypeID.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += c.TypeID.Len()
+	}
+
+	if c.RequestHeader != nil {
+		if err := c.RequestHeader.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += c.RequestHeader.Len()
+	}
+
+	if c.MethodsToCall != nil {
+		return c.MethodsToCall.SerializeTo(b[offset:])
+	}
+
+	return nil
+}
+
+// Len returns the actual length of CallRequest in int.
+func (c *CallRequest) Len() int {
+	var l = 0
+	if c.TypeID != nil {
+		l += c.TypeID.Len()
+	}
+	if c.RequestHeader != nil {
+		l += c.RequestHeader.Len()
+	}
+	if c.MethodsToCall != nil {
+		l += c.MethodsToCall.Len()
+	}
+
+	return l
+}
+
+// String returns CallRequest in string.
+func (c *CallRequest) String() string {
+	return fmt.Sprintf("%v, %v, %v",
+		c.TypeID,
+		c.RequestHeader,
+		c.MethodsToCall,
+	)
+}
+
+// ServiceType returns type of Service in uint16.
+func (c *CallRequest) ServiceType() uint16 {
+	return ServiceTypeCallRequest
+}
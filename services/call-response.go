@@ -0,0 +1,142 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package services
+
+import (
+	"fmt"
+
+	"github.com/wmnsk/gopcua/datatypes"
+)
+
+// CallResponse returns the CallMethodResults for the Methods in the CallRequest,
+// in the same order.
+//
+// Specification: Part 4, 5.11.2.2
+type CallResponse struct {
+	TypeID *datatypes.ExpandedNodeID
+	*ResponseHeader
+	Results         *CallMethodResultArray
+	DiagnosticInfos *DiagnosticInfoArray
+}
+
+// NewCallResponse creates a new CallResponse.
+func NewCallResponse(resHeader *ResponseHeader, diags []*DiagnosticInfo, results ...*CallMethodResult) *CallResponse {
+	return &CallResponse{
+		TypeID:          datatypes.NewFourByteExpandedNodeID(0, ServiceTypeCallResponse),
+		ResponseHeader:  resHeader,
+		Results:         NewCallMethodResultArray(results),
+		DiagnosticInfos: NewDiagnosticInfoArray(diags),
+	}
+}
+
+// DecodeCallResponse decodes given bytes into CallResponse.
+func DecodeCallResponse(b []byte) (*CallResponse, error) {
+	c := &CallResponse{}
+	if err := c.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// DecodeFromBytes decodes given bytes into CallResponse.
+func (c *CallResponse) DecodeFromBytes(b []byte) error {
+	var offset = 0
+	c.TypeID = &datatypes.ExpandedNodeID{}
+	if err := c.TypeID.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += c.TypeID.Len()
+
+	c.ResponseHeader = &ResponseHeader{}
+	if err := c.ResponseHeader.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += c.ResponseHeader.Len() - len(c.ResponseHeader.Payload)
+
+	c.Results = &CallMethodResultArray{}
+	if err := c.Results.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += c.Results.Len()
+
+	c.DiagnosticInfos = &DiagnosticInfoArray{}
+	return c.DiagnosticInfos.DecodeFromBytes(b[offset:])
+}
+
+// Serialize serializes CallResponse into bytes.
+func (c *CallResponse) Serialize() ([]byte, error) {
+	b := make([]byte, c.Len())
+	if err := c.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes CallResponse into bytes.
+func (c *CallResponse) SerializeTo(b []byte) error {
+	var offset = 0
+	if c.TypeID != nil {
+		if err := c.TypeID.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += c.TypeID.Len()
+	}
+
+	if c.ResponseHeader != nil {
+		if err := c.ResponseHeader.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += c.ResponseHeader.Len()
+	}
+
+	if c.Results != nil {
+		if err := c.Results.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += c.Results.Len()
+	}
+
+	if c.DiagnosticInfos != nil {
+		return c.DiagnosticInfos.SerializeTo(b[offset:])
+	}
+
+	return nil
+}
+
+// Len returns the actual length of CallResponse in int.
+func (c *CallResponse) Len() int {
+	var l = 0
+	if c.TypeID != nil {
+		l += c.TypeID.Len()
+	}
+	if c.ResponseHeader != nil {
+		l += c.ResponseHeader.Len()
+	}
+	if c.Results != nil {
+		l += c.Results.Len()
+	}
+	if c.DiagnosticInfos != nil {
+		l += c.DiagnosticInfos.Len()
+	}
+
+	return l
+}
+
+// String returns CallResponse in string.
+func (c *CallResponse) String() string {
+	return fmt.Sprintf("%v, %v, %v, %v",
+		c.TypeID,
+		c.ResponseHeader,
+		c.Results,
+		c.DiagnosticInfos,
+	)
+}
+
+// ServiceType returns type of Service in uint16.
+func (c *CallResponse) ServiceType() uint16 {
+	return ServiceTypeCallResponse
+}
@@ -0,0 +1,75 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/wmnsk/gopcua/datatypes"
+	"github.com/wmnsk/gopcua/utils/codectest"
+)
+
+func TestCallResponse(t *testing.T) {
+	cases := []codectest.Case{
+		{
+			Name: "normal",
+			Struct: NewCallResponse(
+				NewResponseHeader(
+					time.Date(2018, time.August, 10, 23, 0, 0, 0, time.UTC),
+					1, 0, NewNullDiagnosticInfo(), []string{}, NewNullAdditionalHeader(), nil,
+				),
+				nil,
+				NewCallMethodResult(
+					0, []uint32{0}, nil,
+					datatypes.NewVariant(datatypes.NewFloat(2.50025)),
+				),
+			),
+			Bytes: []byte{
+				// TypeID
+				0x01, 0x00, 0xcb, 0x02,
+				// Timestamp
+				0x00, 0x98, 0x67, 0xdd, 0xfd, 0x30, 0xd4, 0x01,
+				// RequestHandle
+				0x01, 0x00, 0x00, 0x00,
+				// ServiceResult
+				0x00, 0x00, 0x00, 0x00,
+				// ServiceDiagnostics
+				0x00,
+				// StringTable
+				0x00, 0x00, 0x00, 0x00,
+				// AdditionalHeader
+				0x00, 0x00, 0x00,
+				// Results: ArraySize
+				0x01, 0x00, 0x00, 0x00,
+				// StatusCode
+				0x00, 0x00, 0x00, 0x00,
+				// InputArgumentResults
+				0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				// InputArgumentDiagnosticInfos
+				0x00, 0x00, 0x00, 0x00,
+				// OutputArguments
+				0x01, 0x00, 0x00, 0x00, 0x0a, 0x19, 0x04, 0x20, 0x40,
+				// DiagnosticInfos
+				0x00, 0x00, 0x00, 0x00,
+			},
+		},
+	}
+	codectest.Run(t, cases, func(b []byte) (codectest.S, error) {
+		v, err := DecodeCallResponse(b)
+		if err != nil {
+			return nil, err
+		}
+		v.Payload = nil
+		return v, nil
+	})
+
+	t.Run("service-id", func(t *testing.T) {
+		id := new(CallResponse).ServiceType()
+		if got, want := id, uint16(ServiceTypeCallResponse); got != want {
+			t.Fatalf("got %d want %d", got, want)
+		}
+	})
+}
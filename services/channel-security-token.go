@@ -9,8 +9,7 @@ import (
 	"fmt"
 	"time"
 
-	"github.com/wmnsk/gopcua/utils"
-
+	"github.com/wmnsk/gopcua/datatypes"
 	"github.com/wmnsk/gopcua/errors"
 )
 
@@ -53,7 +52,7 @@ func (c *ChannelSecurityToken) DecodeFromBytes(b []byte) error {
 
 	c.ChannelID = binary.LittleEndian.Uint32(b[0:4])
 	c.TokenID = binary.LittleEndian.Uint32(b[4:8])
-	c.CreatedAt = utils.DecodeTimestamp(b[8:16])
+	c.CreatedAt = datatypes.FromDateTime(int64(binary.LittleEndian.Uint64(b[8:16])))
 	c.RevisedLifetime = binary.LittleEndian.Uint32(b[16:20])
 
 	return nil
@@ -73,7 +72,7 @@ func (c *ChannelSecurityToken) Serialize() ([]byte, error) {
 func (c *ChannelSecurityToken) SerializeTo(b []byte) error {
 	binary.LittleEndian.PutUint32(b[:4], c.ChannelID)
 	binary.LittleEndian.PutUint32(b[4:8], c.TokenID)
-	utils.EncodeTimestamp(b[8:16], c.CreatedAt)
+	binary.LittleEndian.PutUint64(b[8:16], uint64(datatypes.ToDateTime(c.CreatedAt)))
 	binary.LittleEndian.PutUint32(b[16:20], c.RevisedLifetime)
 
 	return nil
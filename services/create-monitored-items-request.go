@@ -0,0 +1,138 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package services
+
+import (
+	"encoding/binary"
+
+	"github.com/wmnsk/gopcua/datatypes"
+)
+
+// CreateMonitoredItemsRequest is used to create and add one or more MonitoredItems to a Subscription.
+//
+// Specification: Part 4, 5.12.2.2
+type CreateMonitoredItemsRequest struct {
+	TypeID *datatypes.ExpandedNodeID
+	*RequestHeader
+
+	// The Server-assigned identifier for the Subscription that will report
+	// Notifications for the MonitoredItems.
+	SubscriptionID uint32
+
+	// The timestamp Attributes to be transmitted for each MonitoredItem.
+	TimestampsToReturn TimestampsToReturn
+
+	// A list of MonitoredItems to be created and assigned to the specified Subscription.
+	ItemsToCreate *datatypes.MonitoredItemCreateRequestArray
+}
+
+// NewCreateMonitoredItemsRequest creates a new CreateMonitoredItemsRequest.
+func NewCreateMonitoredItemsRequest(reqHeader *RequestHeader, subID uint32, ts TimestampsToReturn, items ...*datatypes.MonitoredItemCreateRequest) *CreateMonitoredItemsRequest {
+	return &CreateMonitoredItemsRequest{
+		TypeID:             datatypes.NewFourByteExpandedNodeID(0, ServiceTypeCreateMonitoredItemsRequest),
+		RequestHeader:      reqHeader,
+		SubscriptionID:     subID,
+		TimestampsToReturn: ts,
+		ItemsToCreate:      datatypes.NewMonitoredItemCreateRequestArray(items),
+	}
+}
+
+// DecodeCreateMonitoredItemsRequest decodes given bytes into CreateMonitoredItemsRequest.
+func DecodeCreateMonitoredItemsRequest(b []byte) (*CreateMonitoredItemsRequest, error) {
+	c := &CreateMonitoredItemsRequest{}
+	if err := c.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// DecodeFromBytes decodes given bytes into CreateMonitoredItemsRequest.
+func (c *CreateMonitoredItemsRequest) DecodeFromBytes(b []byte) error {
+	offset := 0
+	c.TypeID = &datatypes.ExpandedNodeID{}
+	if err := c.TypeID.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += c.TypeID.Len()
+
+	c.RequestHeader = &RequestHeader{}
+	if err := c.RequestHeader.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += c.RequestHeader.Len() - len(c.RequestHeader.Payload)
+
+	c.SubscriptionID = binary.LittleEndian.Uint32(b[offset : offset+4])
+	offset += 4
+
+	c.TimestampsToReturn = TimestampsToReturn(binary.LittleEndian.Uint32(b[offset : offset+4]))
+	offset += 4
+
+	c.ItemsToCreate = &datatypes.MonitoredItemCreateRequestArray{}
+	return c.ItemsToCreate.DecodeFromBytes(b[offset:])
+}
+
+// Serialize serializes CreateMonitoredItemsRequest into bytes.
+func (c *CreateMonitoredItemsRequest) Serialize() ([]byte, error) {
+	b := make([]byte, c.Len())
+	if err := c.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes CreateMonitoredItemsRequest into bytes.
+func (c *CreateMonitoredItemsRequest) SerializeTo(b []byte) error {
+	offset := 0
+	if c.TypeID != nil {
+		if err := c.TypeID.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += c.TypeID.Len()
+	}
+
+	if c.RequestHeader != nil {
+		if err := c.RequestHeader.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += c.RequestHeader.Len() - len(c.Payload)
+	}
+
+	binary.LittleEndian.PutUint32(b[offset:offset+4], c.SubscriptionID)
+	offset += 4
+
+	binary.LittleEndian.PutUint32(b[offset:offset+4], uint32(c.TimestampsToReturn))
+	offset += 4
+
+	if c.ItemsToCreate != nil {
+		return c.ItemsToCreate.SerializeTo(b[offset:])
+	}
+
+	return nil
+}
+
+// Len returns the actual length of CreateMonitoredItemsRequest in int.
+func (c *CreateMonitoredItemsRequest) Len() int {
+	l := 8
+	if c.TypeID != nil {
+		l += c.TypeID.Len()
+	}
+
+	if c.RequestHeader != nil {
+		l += c.RequestHeader.Len()
+	}
+
+	if c.ItemsToCreate != nil {
+		l += c.ItemsToCreate.Len()
+	}
+
+	return l
+}
+
+// ServiceType returns type of Service in uint16.
+func (c *CreateMonitoredItemsRequest) ServiceType() uint16 {
+	return ServiceTypeCreateMonitoredItemsRequest
+}
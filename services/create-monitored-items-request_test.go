@@ -0,0 +1,130 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/wmnsk/gopcua/datatypes"
+	"github.com/wmnsk/gopcua/utils/codectest"
+)
+
+func TestCreateMonitoredItemsRequest(t *testing.T) {
+	cases := []codectest.Case{
+		{
+			Name: "absolute-deadband",
+			Struct: NewCreateMonitoredItemsRequest(
+				NewRequestHeader(
+					datatypes.NewOpaqueNodeID(0x00, []byte{
+						0x08, 0x22, 0x87, 0x62, 0xba, 0x81, 0xe1, 0x11,
+						0xa6, 0x43, 0xf8, 0x77, 0x7b, 0xc6, 0x2f, 0xc8,
+					}),
+					time.Date(2018, time.August, 10, 23, 0, 0, 0, time.UTC),
+					1, 0, 0, "", NewNullAdditionalHeader(), nil,
+				),
+				1, TimestampsToReturnBoth,
+				datatypes.NewMonitoredItemCreateRequest(
+					datatypes.NewReadValueID(
+						datatypes.NewFourByteNodeID(0, 2256),
+						datatypes.AttributeIDValue,
+						"", 0, "",
+					),
+					datatypes.MonitoringModeReporting,
+					datatypes.NewMonitoringParameters(
+						1, 1000,
+						datatypes.NewDataChangeFilter(
+							datatypes.DataChangeTriggerStatusValue, datatypes.DeadbandTypeAbsolute, 0.5,
+						),
+						10, true,
+					),
+				),
+			),
+			Bytes: []byte{
+				// TypeID
+				0x01, 0x00, 0xef, 0x02,
+				// AuthenticationToken
+				0x05, 0x00, 0x00, 0x10, 0x00, 0x00, 0x00, 0x08,
+				0x22, 0x87, 0x62, 0xba, 0x81, 0xe1, 0x11, 0xa6,
+				0x43, 0xf8, 0x77, 0x7b, 0xc6, 0x2f, 0xc8,
+				// Timestamp
+				0x00, 0x98, 0x67, 0xdd, 0xfd, 0x30, 0xd4, 0x01,
+				// RequestHandle
+				0x01, 0x00, 0x00, 0x00,
+				// ReturnDiagnostics
+				0x00, 0x00, 0x00, 0x00,
+				// AuditEntryID
+				0xff, 0xff, 0xff, 0xff,
+				// TimeoutHint
+				0x00, 0x00, 0x00, 0x00,
+				// AdditionalHeader
+				0x00, 0x00, 0x00,
+				// SubscriptionID
+				0x01, 0x00, 0x00, 0x00,
+				// TimestampsToReturn
+				0x02, 0x00, 0x00, 0x00,
+				// ItemsToCreate
+				// ArraySize
+				0x01, 0x00, 0x00, 0x00,
+				// ItemToMonitor
+				0x01, 0x00, 0xd0, 0x08,
+				0x0d, 0x00, 0x00, 0x00, 0xff, 0xff, 0xff, 0xff,
+				0x00, 0x00, 0xff, 0xff, 0xff, 0xff,
+				// MonitoringMode
+				0x02, 0x00, 0x00, 0x00,
+				// RequestedParameters
+				// ClientHandle
+				0x01, 0x00, 0x00, 0x00,
+				// SamplingInterval
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x40, 0x8f, 0x40,
+				// Filter
+				0x01, 0x00, 0xd4, 0x02, 0x01, 0x10, 0x00, 0x00, 0x00,
+				0x01, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00,
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xe0, 0x3f,
+				// QueueSize
+				0x0a, 0x00, 0x00, 0x00,
+				// DiscardOldest
+				0x01,
+			},
+		},
+	}
+	codectest.Run(t, cases, func(b []byte) (codectest.S, error) {
+		v, err := DecodeCreateMonitoredItemsRequest(b)
+		if err != nil {
+			return nil, err
+		}
+		v.Payload = nil
+		return v, nil
+	})
+
+	t.Run("deadband-value", func(t *testing.T) {
+		b, err := cases[0].Struct.(*CreateMonitoredItemsRequest).Serialize()
+		if err != nil {
+			t.Fatal(err)
+		}
+		c, err := DecodeCreateMonitoredItemsRequest(b)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		f, ok := c.ItemsToCreate.Items[0].RequestedParameters.Filter.Value.(*datatypes.DataChangeFilter)
+		if !ok {
+			t.Fatalf("got filter %T, want *datatypes.DataChangeFilter", c.ItemsToCreate.Items[0].RequestedParameters.Filter.Value)
+		}
+		if got, want := f.DeadbandType, datatypes.DeadbandTypeAbsolute; got != want {
+			t.Errorf("got DeadbandType %d, want %d", got, want)
+		}
+		if got, want := f.DeadbandValue, 0.5; got != want {
+			t.Errorf("got DeadbandValue %v, want %v", got, want)
+		}
+	})
+
+	t.Run("service-id", func(t *testing.T) {
+		id := new(CreateMonitoredItemsRequest).ServiceType()
+		if got, want := id, uint16(ServiceTypeCreateMonitoredItemsRequest); got != want {
+			t.Fatalf("got %d want %d", got, want)
+		}
+	})
+}
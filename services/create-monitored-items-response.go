@@ -0,0 +1,145 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package services
+
+import (
+	"fmt"
+
+	"github.com/wmnsk/gopcua/datatypes"
+)
+
+// CreateMonitoredItemsResponse is returned by the Server for the CreateMonitoredItemsRequest.
+// It has a MonitoredItemCreateResult for each item to create.
+//
+// Specification: Part 4, 5.12.2.2
+type CreateMonitoredItemsResponse struct {
+	TypeID *datatypes.ExpandedNodeID
+	*ResponseHeader
+	Results         *datatypes.MonitoredItemCreateResultArray
+	DiagnosticInfos *DiagnosticInfoArray
+}
+
+// NewCreateMonitoredItemsResponse creates a new CreateMonitoredItemsResponse.
+func NewCreateMonitoredItemsResponse(resHeader *ResponseHeader, diags []*DiagnosticInfo, results ...*datatypes.MonitoredItemCreateResult) *CreateMonitoredItemsResponse {
+	return &CreateMonitoredItemsResponse{
+		TypeID:          datatypes.NewFourByteExpandedNodeID(0, ServiceTypeCreateMonitoredItemsResponse),
+		ResponseHeader:  resHeader,
+		Results:         datatypes.NewMonitoredItemCreateResultArray(results),
+		DiagnosticInfos: NewDiagnosticInfoArray(diags),
+	}
+}
+
+// DecodeCreateMonitoredItemsResponse decodes given bytes into CreateMonitoredItemsResponse.
+func DecodeCreateMonitoredItemsResponse(b []byte) (*CreateMonitoredItemsResponse, error) {
+	r := &CreateMonitoredItemsResponse{}
+	if err := r.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// DecodeFromBytes decodes given bytes into CreateMonitoredItemsResponse.
+func (r *CreateMonitoredItemsResponse) DecodeFromBytes(b []byte) error {
+	var offset = 0
+	r.TypeID = &datatypes.ExpandedNodeID{}
+	if err := r.TypeID.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += r.TypeID.Len()
+
+	r.ResponseHeader = &ResponseHeader{}
+	if err := r.ResponseHeader.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += r.ResponseHeader.Len() - len(r.ResponseHeader.Payload)
+
+	r.Results = &datatypes.MonitoredItemCreateResultArray{}
+	if err := r.Results.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += r.Results.Len()
+
+	r.DiagnosticInfos = &DiagnosticInfoArray{}
+	return r.DiagnosticInfos.DecodeFromBytes(b[offset:])
+}
+
+// Serialize serializes CreateMonitoredItemsResponse into bytes.
+func (r *CreateMonitoredItemsResponse) Serialize() ([]byte, error) {
+	b := make([]byte, r.Len())
+	if err := r.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes CreateMonitoredItemsResponse into bytes.
+func (r *CreateMonitoredItemsResponse) SerializeTo(b []byte) error {
+	var offset = 0
+	if r.TypeID != nil {
+		if err := r.TypeID.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += r.TypeID.Len()
+	}
+
+	if r.ResponseHeader != nil {
+		if err := r.ResponseHeader.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += r.ResponseHeader.Len()
+	}
+
+	if r.Results != nil {
+		if err := r.Results.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += r.Results.Len()
+	}
+
+	if r.DiagnosticInfos != nil {
+		return r.DiagnosticInfos.SerializeTo(b[offset:])
+	}
+
+	return nil
+}
+
+// Len returns the actual length of CreateMonitoredItemsResponse in int.
+func (r *CreateMonitoredItemsResponse) Len() int {
+	l := 0
+	if r.TypeID != nil {
+		l += r.TypeID.Len()
+	}
+
+	if r.ResponseHeader != nil {
+		l += r.ResponseHeader.Len()
+	}
+
+	if r.Results != nil {
+		l += r.Results.Len()
+	}
+
+	if r.DiagnosticInfos != nil {
+		l += r.DiagnosticInfos.Len()
+	}
+
+	return l
+}
+
+// String returns CreateMonitoredItemsResponse in string.
+func (r *CreateMonitoredItemsResponse) String() string {
+	return fmt.Sprintf("%v, %v, %v, %v",
+		r.TypeID,
+		r.ResponseHeader,
+		r.Results,
+		r.DiagnosticInfos,
+	)
+}
+
+// ServiceType returns type of Service in uint16.
+func (r *CreateMonitoredItemsResponse) ServiceType() uint16 {
+	return ServiceTypeCreateMonitoredItemsResponse
+}
@@ -0,0 +1,75 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/wmnsk/gopcua/datatypes"
+	"github.com/wmnsk/gopcua/utils/codectest"
+)
+
+func TestCreateMonitoredItemsResponse(t *testing.T) {
+	cases := []codectest.Case{
+		{
+			Name: "single-result",
+			Struct: NewCreateMonitoredItemsResponse(
+				NewResponseHeader(
+					time.Date(2018, time.August, 10, 23, 0, 0, 0, time.UTC),
+					1, 0, NewNullDiagnosticInfo(), []string{}, NewNullAdditionalHeader(), nil,
+				),
+				nil,
+				datatypes.NewMonitoredItemCreateResult(0, 1, 1000, 10, nil),
+			),
+			Bytes: []byte{
+				// TypeID
+				0x01, 0x00, 0xf2, 0x02,
+				// Timestamp
+				0x00, 0x98, 0x67, 0xdd, 0xfd, 0x30, 0xd4, 0x01,
+				// RequestHandle
+				0x01, 0x00, 0x00, 0x00,
+				// ServiceResult
+				0x00, 0x00, 0x00, 0x00,
+				// ServiceDiagnostics
+				0x00,
+				// StringTable
+				0x00, 0x00, 0x00, 0x00,
+				// AdditionalHeader
+				0x00, 0x00, 0x00,
+				// Results
+				// ArraySize
+				0x01, 0x00, 0x00, 0x00,
+				// StatusCode
+				0x00, 0x00, 0x00, 0x00,
+				// MonitoredItemID
+				0x01, 0x00, 0x00, 0x00,
+				// RevisedSamplingInterval
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x40, 0x8f, 0x40,
+				// RevisedQueueSize
+				0x0a, 0x00, 0x00, 0x00,
+				// FilterResult
+				0x00, 0x00, 0x00,
+				// DiagnosticInfos
+				0x00, 0x00, 0x00, 0x00,
+			},
+		},
+	}
+	codectest.Run(t, cases, func(b []byte) (codectest.S, error) {
+		v, err := DecodeCreateMonitoredItemsResponse(b)
+		if err != nil {
+			return nil, err
+		}
+		v.Payload = nil
+		return v, nil
+	})
+
+	t.Run("service-id", func(t *testing.T) {
+		id := new(CreateMonitoredItemsResponse).ServiceType()
+		if got, want := id, uint16(ServiceTypeCreateMonitoredItemsResponse); got != want {
+			t.Fatalf("got %d want %d", got, want)
+		}
+	})
+}
@@ -38,7 +38,7 @@ func TestCreateSessionResponse(t *testing.T) {
 						"app-uri", "prod-uri", "app-name", AppTypeServer,
 						"gw-uri", "prof-uri", []string{"discov-uri-1", "discov-uri-2"},
 					),
-					[]byte{},
+					nil,
 					SecModeNone,
 					"sec-uri",
 					NewUserTokenPolicyArray(
@@ -62,7 +62,7 @@ func TestCreateSessionResponse(t *testing.T) {
 						"app-uri", "prod-uri", "app-name", AppTypeServer,
 						"gw-uri", "prof-uri", []string{"discov-uri-1", "discov-uri-2"},
 					),
-					[]byte{},
+					nil,
 					SecModeNone,
 					"sec-uri",
 					NewUserTokenPolicyArray(
@@ -0,0 +1,146 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package services
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+
+	"github.com/wmnsk/gopcua/datatypes"
+)
+
+// CreateSubscriptionResponse is returned by the Server for CreateSubscriptionRequest,
+// with the parameters revised by the Server.
+//
+// Specification: Part 4, 5.13.2
+type CreateSubscriptionResponse struct {
+	TypeID *datatypes.ExpandedNodeID
+	*ResponseHeader
+	SubscriptionID            uint32
+	RevisedPublishingInterval float64
+	RevisedLifetimeCount      uint32
+	RevisedMaxKeepAliveCount  uint32
+}
+
+// NewCreateSubscriptionResponse creates a new CreateSubscriptionResponse.
+func NewCreateSubscriptionResponse(resHeader *ResponseHeader, subID uint32, pubInterval float64, lifetime, keepAlive uint32) *CreateSubscriptionResponse {
+	return &CreateSubscriptionResponse{
+		TypeID:                    datatypes.NewFourByteExpandedNodeID(0, ServiceTypeCreateSubscriptionResponse),
+		ResponseHeader:            resHeader,
+		SubscriptionID:            subID,
+		RevisedPublishingInterval: pubInterval,
+		RevisedLifetimeCount:      lifetime,
+		RevisedMaxKeepAliveCount:  keepAlive,
+	}
+}
+
+// DecodeCreateSubscriptionResponse decodes given bytes into CreateSubscriptionResponse.
+func DecodeCreateSubscriptionResponse(b []byte) (*CreateSubscriptionResponse, error) {
+	c := &CreateSubscriptionResponse{}
+	if err := c.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// DecodeFromBytes decodes given bytes into CreateSubscriptionResponse.
+func (c *CreateSubscriptionResponse) DecodeFromBytes(b []byte) error {
+	var offset = 0
+	c.TypeID = &datatypes.ExpandedNodeID{}
+	if err := c.TypeID.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += c.TypeID.Len()
+
+	c.ResponseHeader = &ResponseHeader{}
+	if err := c.ResponseHeader.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += c.ResponseHeader.Len() - len(c.ResponseHeader.Payload)
+
+	c.SubscriptionID = binary.LittleEndian.Uint32(b[offset : offset+4])
+	offset += 4
+
+	c.RevisedPublishingInterval = math.Float64frombits(binary.LittleEndian.Uint64(b[offset : offset+8]))
+	offset += 8
+
+	c.RevisedLifetimeCount = binary.LittleEndian.Uint32(b[offset : offset+4])
+	offset += 4
+
+	c.RevisedMaxKeepAliveCount = binary.LittleEndian.Uint32(b[offset : offset+4])
+	return nil
+}
+
+// Serialize serializes CreateSubscriptionResponse into bytes.
+func (c *CreateSubscriptionResponse) Serialize() ([]byte, error) {
+	b := make([]byte, c.Len())
+	if err := c.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes CreateSubscriptionResponse into bytes.
+func (c *CreateSubscriptionResponse) SerializeTo(b []byte) error {
+	var offset = 0
+	if c.TypeID != nil {
+		if err := c.TypeID.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += c.TypeID.Len()
+	}
+
+	if c.ResponseHeader != nil {
+		if err := c.ResponseHeader.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += c.ResponseHeader.Len()
+	}
+
+	binary.LittleEndian.PutUint32(b[offset:offset+4], c.SubscriptionID)
+	offset += 4
+
+	binary.LittleEndian.PutUint64(b[offset:offset+8], math.Float64bits(c.RevisedPublishingInterval))
+	offset += 8
+
+	binary.LittleEndian.PutUint32(b[offset:offset+4], c.RevisedLifetimeCount)
+	offset += 4
+
+	binary.LittleEndian.PutUint32(b[offset:offset+4], c.RevisedMaxKeepAliveCount)
+	return nil
+}
+
+// Len returns the actual length of CreateSubscriptionResponse in int.
+func (c *CreateSubscriptionResponse) Len() int {
+	var l = 20
+	if c.TypeID != nil {
+		l += c.TypeID.Len()
+	}
+	if c.ResponseHeader != nil {
+		l += c.ResponseHeader.Len()
+	}
+
+	return l
+}
+
+// String returns CreateSubscriptionResponse in string.
+func (c *CreateSubscriptionResponse) String() string {
+	return fmt.Sprintf("%v, %v, %d, %f, %d, %d",
+		c.TypeID,
+		c.ResponseHeader,
+		c.SubscriptionID,
+		c.RevisedPublishingInterval,
+		c.RevisedLifetimeCount,
+		c.RevisedMaxKeepAliveCount,
+	)
+}
+
+// ServiceType returns type of Service in uint16.
+func (c *CreateSubscriptionResponse) ServiceType() uint16 {
+	return ServiceTypeCreateSubscriptionResponse
+}
@@ -0,0 +1,66 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/wmnsk/gopcua/utils/codectest"
+)
+
+func TestCreateSubscriptionResponse(t *testing.T) {
+	cases := []codectest.Case{
+		{
+			Name: "normal",
+			Struct: NewCreateSubscriptionResponse(
+				NewResponseHeader(
+					time.Date(2018, time.August, 10, 23, 0, 0, 0, time.UTC),
+					1, 0, NewNullDiagnosticInfo(), []string{}, NewNullAdditionalHeader(), nil,
+				),
+				1, 500, 2400, 10,
+			),
+			Bytes: []byte{
+				// TypeID
+				0x01, 0x00, 0x16, 0x03,
+				// Timestamp
+				0x00, 0x98, 0x67, 0xdd, 0xfd, 0x30, 0xd4, 0x01,
+				// RequestHandle
+				0x01, 0x00, 0x00, 0x00,
+				// ServiceResult
+				0x00, 0x00, 0x00, 0x00,
+				// ServiceDiagnostics
+				0x00,
+				// StringTable
+				0x00, 0x00, 0x00, 0x00,
+				// AdditionalHeader
+				0x00, 0x00, 0x00,
+				// SubscriptionID
+				0x01, 0x00, 0x00, 0x00,
+				// RevisedPublishingInterval
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x40, 0x7f, 0x40,
+				// RevisedLifetimeCount
+				0x60, 0x09, 0x00, 0x00,
+				// RevisedMaxKeepAliveCount
+				0x0a, 0x00, 0x00, 0x00,
+			},
+		},
+	}
+	codectest.Run(t, cases, func(b []byte) (codectest.S, error) {
+		v, err := DecodeCreateSubscriptionResponse(b)
+		if err != nil {
+			return nil, err
+		}
+		v.Payload = nil
+		return v, nil
+	})
+
+	t.Run("service-id", func(t *testing.T) {
+		id := new(CreateSubscriptionResponse).ServiceType()
+		if got, want := id, uint16(ServiceTypeCreateSubscriptionResponse); got != want {
+			t.Fatalf("got %d want %d", got, want)
+		}
+	})
+}
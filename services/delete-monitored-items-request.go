@@ -0,0 +1,142 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package services
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/wmnsk/gopcua/datatypes"
+	"github.com/wmnsk/gopcua/errors"
+)
+
+// DeleteMonitoredItemsRequest is used to remove one or more MonitoredItems of a Subscription.
+//
+// Specification: Part 4, 5.12.6
+type DeleteMonitoredItemsRequest struct {
+	TypeID *datatypes.ExpandedNodeID
+	*RequestHeader
+
+	// The Server-assigned identifier for the Subscription that contains the MonitoredItems.
+	SubscriptionID uint32
+
+	// The Server-assigned identifiers of the MonitoredItems to be deleted.
+	MonitoredItemIDs *datatypes.Uint32Array
+}
+
+// NewDeleteMonitoredItemsRequest creates a new DeleteMonitoredItemsRequest.
+func NewDeleteMonitoredItemsRequest(reqHeader *RequestHeader, subID uint32, itemIDs ...uint32) *DeleteMonitoredItemsRequest {
+	return &DeleteMonitoredItemsRequest{
+		TypeID:           datatypes.NewFourByteExpandedNodeID(0, ServiceTypeDeleteMonitoredItemsRequest),
+		RequestHeader:    reqHeader,
+		SubscriptionID:   subID,
+		MonitoredItemIDs: datatypes.NewUint32Array(itemIDs),
+	}
+}
+
+// DecodeDeleteMonitoredItemsRequest decodes given bytes into DeleteMonitoredItemsRequest.
+func DecodeDeleteMonitoredItemsRequest(b []byte) (*DeleteMonitoredItemsRequest, error) {
+	d := &DeleteMonitoredItemsRequest{}
+	if err := d.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// DecodeFromBytes decodes given bytes into DeleteMonitoredItemsRequest.
+func (d *DeleteMonitoredItemsRequest) DecodeFromBytes(b []byte) error {
+	offset := 0
+	d.TypeID = &datatypes.ExpandedNodeID{}
+	if err := d.TypeID.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += d.TypeID.Len()
+
+	d.RequestHeader = &RequestHeader{}
+	if err := d.RequestHeader.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += d.RequestHeader.Len() - len(d.RequestHeader.Payload)
+
+	if len(b[offset:]) < 4 {
+		return errors.NewErrTooShortToDecode(d, "should contain SubscriptionID")
+	}
+	d.SubscriptionID = binary.LittleEndian.Uint32(b[offset : offset+4])
+	offset += 4
+
+	d.MonitoredItemIDs = &datatypes.Uint32Array{}
+	return d.MonitoredItemIDs.DecodeFromBytes(b[offset:])
+}
+
+// Serialize serializes DeleteMonitoredItemsRequest into bytes.
+func (d *DeleteMonitoredItemsRequest) Serialize() ([]byte, error) {
+	b := make([]byte, d.Len())
+	if err := d.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes DeleteMonitoredItemsRequest into bytes.
+func (d *DeleteMonitoredItemsRequest) SerializeTo(b []byte) error {
+	offset := 0
+	if d.TypeID != nil {
+		if err := d.TypeID.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += d.TypeID.Len()
+	}
+
+	if d.RequestHeader != nil {
+		if err := d.RequestHeader.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += d.RequestHeader.Len()
+	}
+
+	binary.LittleEndian.PutUint32(b[offset:offset+4], d.SubscriptionID)
+	offset += 4
+
+	if d.MonitoredItemIDs != nil {
+		return d.MonitoredItemIDs.SerializeTo(b[offset:])
+	}
+
+	return nil
+}
+
+// Len returns the actual length of DeleteMonitoredItemsRequest in int.
+func (d *DeleteMonitoredItemsRequest) Len() int {
+	l := 4
+	if d.TypeID != nil {
+		l += d.TypeID.Len()
+	}
+
+	if d.RequestHeader != nil {
+		l += d.RequestHeader.Len()
+	}
+
+	if d.MonitoredItemIDs != nil {
+		l += d.MonitoredItemIDs.Len()
+	}
+
+	return l
+}
+
+// String returns DeleteMonitoredItemsRequest in string.
+func (d *DeleteMonitoredItemsRequest) String() string {
+	return fmt.Sprintf("%v, %v, %d, %v",
+		d.TypeID,
+		d.RequestHeader,
+		d.SubscriptionID,
+		d.MonitoredItemIDs,
+	)
+}
+
+// ServiceType returns type of Service in uint16.
+func (d *DeleteMonitoredItemsRequest) ServiceType() uint16 {
+	return ServiceTypeDeleteMonitoredItemsRequest
+}
@@ -0,0 +1,145 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package services
+
+import (
+	"fmt"
+
+	"github.com/wmnsk/gopcua/datatypes"
+)
+
+// DeleteMonitoredItemsResponse returns the StatusCodes for the MonitoredItems
+// in the DeleteMonitoredItemsRequest, in the same order.
+//
+// Specification: Part 4, 5.12.6
+type DeleteMonitoredItemsResponse struct {
+	TypeID *datatypes.ExpandedNodeID
+	*ResponseHeader
+	Results         *datatypes.Uint32Array
+	DiagnosticInfos *DiagnosticInfoArray
+}
+
+// NewDeleteMonitoredItemsResponse creates a new DeleteMonitoredItemsResponse.
+func NewDeleteMonitoredItemsResponse(resHeader *ResponseHeader, diags []*DiagnosticInfo, results ...uint32) *DeleteMonitoredItemsResponse {
+	return &DeleteMonitoredItemsResponse{
+		TypeID:          datatypes.NewFourByteExpandedNodeID(0, ServiceTypeDeleteMonitoredItemsResponse),
+		ResponseHeader:  resHeader,
+		Results:         datatypes.NewUint32Array(results),
+		DiagnosticInfos: NewDiagnosticInfoArray(diags),
+	}
+}
+
+// DecodeDeleteMonitoredItemsResponse decodes given bytes into DeleteMonitoredItemsResponse.
+func DecodeDeleteMonitoredItemsResponse(b []byte) (*DeleteMonitoredItemsResponse, error) {
+	d := &DeleteMonitoredItemsResponse{}
+	if err := d.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// DecodeFromBytes decodes given bytes into DeleteMonitoredItemsResponse.
+func (d *DeleteMonitoredItemsResponse) DecodeFromBytes(b []byte) error {
+	var offset = 0
+	d.TypeID = &datatypes.ExpandedNodeID{}
+	if err := d.TypeID.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += d.TypeID.Len()
+
+	d.ResponseHeader = &ResponseHeader{}
+	if err := d.ResponseHeader.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += d.ResponseHeader.Len() - len(d.ResponseHeader.Payload)
+
+	d.Results = &datatypes.Uint32Array{}
+	if err := d.Results.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += d.Results.Len()
+
+	d.DiagnosticInfos = &DiagnosticInfoArray{}
+	return d.DiagnosticInfos.DecodeFromBytes(b[offset:])
+}
+
+// Serialize serializes DeleteMonitoredItemsResponse into bytes.
+func (d *DeleteMonitoredItemsResponse) Serialize() ([]byte, error) {
+	b := make([]byte, d.Len())
+	if err := d.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes DeleteMonitoredItemsResponse into bytes.
+func (d *DeleteMonitoredItemsResponse) SerializeTo(b []byte) error {
+	var offset = 0
+	if d.TypeID != nil {
+		if err := d.TypeID.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += d.TypeID.Len()
+	}
+
+	if d.ResponseHeader != nil {
+		if err := d.ResponseHeader.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += d.ResponseHeader.Len()
+	}
+
+	if d.Results != nil {
+		if err := d.Results.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += d.Results.Len()
+	}
+
+	if d.DiagnosticInfos != nil {
+		return d.DiagnosticInfos.SerializeTo(b[offset:])
+	}
+
+	return nil
+}
+
+// Len returns the actual length of DeleteMonitoredItemsResponse in int.
+func (d *DeleteMonitoredItemsResponse) Len() int {
+	l := 0
+	if d.TypeID != nil {
+		l += d.TypeID.Len()
+	}
+
+	if d.ResponseHeader != nil {
+		l += d.ResponseHeader.Len()
+	}
+
+	if d.Results != nil {
+		l += d.Results.Len()
+	}
+
+	if d.DiagnosticInfos != nil {
+		l += d.DiagnosticInfos.Len()
+	}
+
+	return l
+}
+
+// String returns DeleteMonitoredItemsResponse in string.
+func (d *DeleteMonitoredItemsResponse) String() string {
+	return fmt.Sprintf("%v, %v, %v, %v",
+		d.TypeID,
+		d.ResponseHeader,
+		d.Results,
+		d.DiagnosticInfos,
+	)
+}
+
+// ServiceType returns type of Service in uint16.
+func (d *DeleteMonitoredItemsResponse) ServiceType() uint16 {
+	return ServiceTypeDeleteMonitoredItemsResponse
+}
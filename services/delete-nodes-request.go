@@ -0,0 +1,122 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package services
+
+import (
+	"fmt"
+
+	"github.com/wmnsk/gopcua/datatypes"
+)
+
+// DeleteNodesRequest is used to delete one or more Nodes from the AddressSpace.
+//
+// Specification: Part 4, 5.7.4.2
+type DeleteNodesRequest struct {
+	TypeID *datatypes.ExpandedNodeID
+	*RequestHeader
+	NodesToDelete *datatypes.DeleteNodesItemArray
+}
+
+// NewDeleteNodesRequest creates a new DeleteNodesRequest.
+func NewDeleteNodesRequest(reqHeader *RequestHeader, items ...*datatypes.DeleteNodesItem) *DeleteNodesRequest {
+	return &DeleteNodesRequest{
+		TypeID:        datatypes.NewFourByteExpandedNodeID(0, ServiceTypeDeleteNodesRequest),
+		RequestHeader: reqHeader,
+		NodesToDelete: datatypes.NewDeleteNodesItemArray(items),
+	}
+}
+
+// DecodeDeleteNodesRequest decodes given bytes into DeleteNodesRequest.
+func DecodeDeleteNodesRequest(b []byte) (*DeleteNodesRequest, error) {
+	r := &DeleteNodesRequest{}
+	if err := r.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// DecodeFromBytes decodes given bytes into DeleteNodesRequest.
+func (r *DeleteNodesRequest) DecodeFromBytes(b []byte) error {
+	var offset = 0
+	r.TypeID = &datatypes.ExpandedNodeID{}
+	if err := r.TypeID.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += r.TypeID.Len()
+
+	r.RequestHeader = &RequestHeader{}
+	if err := r.RequestHeader.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += r.RequestHeader.Len() - len(r.RequestHeader.Payload)
+
+	r.NodesToDelete = &datatypes.DeleteNodesItemArray{}
+	return r.NodesToDelete.DecodeFromBytes(b[offset:])
+}
+
+// Serialize serializes DeleteNodesRequest into bytes.
+func (r *DeleteNodesRequest) Serialize() ([]byte, error) {
+	b := make([]byte, r.Len())
+	if err := r.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes DeleteNodesRequest into bytes.
+func (r *DeleteNodesRequest) SerializeTo(b []byte) error {
+	var offset = 0
+	if r.TypeID != nil {
+		if err := r.TypeID.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += r.TypeID.Len()
+	}
+
+	if r.RequestHeader != nil {
+		if err := r.RequestHeader.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += r.RequestHeader.Len()
+	}
+
+	if r.NodesToDelete != nil {
+		return r.NodesToDelete.SerializeTo(b[offset:])
+	}
+
+	return nil
+}
+
+// Len returns the actual length of DeleteNodesRequest in int.
+func (r *DeleteNodesRequest) Len() int {
+	var l = 0
+	if r.TypeID != nil {
+		l += r.TypeID.Len()
+	}
+	if r.RequestHeader != nil {
+		l += r.RequestHeader.Len()
+	}
+	if r.NodesToDelete != nil {
+		l += r.NodesToDelete.Len()
+	}
+
+	return l
+}
+
+// String returns DeleteNodesRequest in string.
+func (r *DeleteNodesRequest) String() string {
+	return fmt.Sprintf("%v, %v, %v",
+		r.TypeID,
+		r.RequestHeader,
+		r.NodesToDelete,
+	)
+}
+
+// ServiceType returns type of Service in uint16.
+func (r *DeleteNodesRequest) ServiceType() uint16 {
+	return ServiceTypeDeleteNodesRequest
+}
@@ -0,0 +1,122 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package services
+
+import (
+	"fmt"
+
+	"github.com/wmnsk/gopcua/datatypes"
+)
+
+// DeleteReferencesRequest is used to delete one or more References from Nodes.
+//
+// Specification: Part 4, 5.7.5.2
+type DeleteReferencesRequest struct {
+	TypeID *datatypes.ExpandedNodeID
+	*RequestHeader
+	ReferencesToDelete *datatypes.DeleteReferencesItemArray
+}
+
+// NewDeleteReferencesRequest creates a new DeleteReferencesRequest.
+func NewDeleteReferencesRequest(reqHeader *RequestHeader, items ...*datatypes.DeleteReferencesItem) *DeleteReferencesRequest {
+	return &DeleteReferencesRequest{
+		TypeID:             datatypes.NewFourByteExpandedNodeID(0, ServiceTypeDeleteReferencesRequest),
+		RequestHeader:      reqHeader,
+		ReferencesToDelete: datatypes.NewDeleteReferencesItemArray(items),
+	}
+}
+
+// DecodeDeleteReferencesRequest decodes given bytes into DeleteReferencesRequest.
+func DecodeDeleteReferencesRequest(b []byte) (*DeleteReferencesRequest, error) {
+	r := &DeleteReferencesRequest{}
+	if err := r.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// DecodeFromBytes decodes given bytes into DeleteReferencesRequest.
+func (r *DeleteReferencesRequest) DecodeFromBytes(b []byte) error {
+	var offset = 0
+	r.TypeID = &datatypes.ExpandedNodeID{}
+	if err := r.TypeID.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += r.TypeID.Len()
+
+	r.RequestHeader = &RequestHeader{}
+	if err := r.RequestHeader.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += r.RequestHeader.Len() - len(r.RequestHeader.Payload)
+
+	r.ReferencesToDelete = &datatypes.DeleteReferencesItemArray{}
+	return r.ReferencesToDelete.DecodeFromBytes(b[offset:])
+}
+
+// Serialize serializes DeleteReferencesRequest into bytes.
+func (r *DeleteReferencesRequest) Serialize() ([]byte, error) {
+	b := make([]byte, r.Len())
+	if err := r.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes DeleteReferencesRequest into bytes.
+func (r *DeleteReferencesRequest) SerializeTo(b []byte) error {
+	var offset = 0
+	if r.TypeID != nil {
+		if err := r.TypeID.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += r.TypeID.Len()
+	}
+
+	if r.RequestHeader != nil {
+		if err := r.RequestHeader.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += r.RequestHeader.Len()
+	}
+
+	if r.ReferencesToDelete != nil {
+		return r.ReferencesToDelete.SerializeTo(b[offset:])
+	}
+
+	return nil
+}
+
+// Len returns the actual length of DeleteReferencesRequest in int.
+func (r *DeleteReferencesRequest) Len() int {
+	var l = 0
+	if r.TypeID != nil {
+		l += r.TypeID.Len()
+	}
+	if r.RequestHeader != nil {
+		l += r.RequestHeader.Len()
+	}
+	if r.ReferencesToDelete != nil {
+		l += r.ReferencesToDelete.Len()
+	}
+
+	return l
+}
+
+// String returns DeleteReferencesRequest in string.
+func (r *DeleteReferencesRequest) String() string {
+	return fmt.Sprintf("%v, %v, %v",
+		r.TypeID,
+		r.RequestHeader,
+		r.ReferencesToDelete,
+	)
+}
+
+// ServiceType returns type of Service in uint16.
+func (r *DeleteReferencesRequest) ServiceType() uint16 {
+	return ServiceTypeDeleteReferencesRequest
+}
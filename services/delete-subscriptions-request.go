@@ -0,0 +1,123 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package services
+
+import (
+	"fmt"
+
+	"github.com/wmnsk/gopcua/datatypes"
+)
+
+// DeleteSubscriptionsRequest is used to delete one or more Subscriptions that belong to the
+// Client's Session. The MonitoredItems of the Subscriptions are also deleted.
+//
+// Specification: Part 4, 5.13.8
+type DeleteSubscriptionsRequest struct {
+	TypeID *datatypes.ExpandedNodeID
+	*RequestHeader
+	SubscriptionIDs *datatypes.Uint32Array
+}
+
+// NewDeleteSubscriptionsRequest creates a new DeleteSubscriptionsRequest.
+func NewDeleteSubscriptionsRequest(reqHeader *RequestHeader, subIDs ...uint32) *DeleteSubscriptionsRequest {
+	return &DeleteSubscriptionsRequest{
+		TypeID:          datatypes.NewFourByteExpandedNodeID(0, ServiceTypeDeleteSubscriptionsRequest),
+		RequestHeader:   reqHeader,
+		SubscriptionIDs: datatypes.NewUint32Array(subIDs),
+	}
+}
+
+// DecodeDeleteSubscriptionsRequest decodes given bytes into DeleteSubscriptionsRequest.
+func DecodeDeleteSubscriptionsRequest(b []byte) (*DeleteSubscriptionsRequest, error) {
+	d := &DeleteSubscriptionsRequest{}
+	if err := d.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// DecodeFromBytes decodes given bytes into DeleteSubscriptionsRequest.
+func (d *DeleteSubscriptionsRequest) DecodeFromBytes(b []byte) error {
+	var offset = 0
+	d.TypeID = &datatypes.ExpandedNodeID{}
+	if err := d.TypeID.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += d.TypeID.Len()
+
+	d.RequestHeader = &RequestHeader{}
+	if err := d.RequestHeader.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += d.RequestHeader.Len() - len(d.RequestHeader.Payload)
+
+	d.SubscriptionIDs = &datatypes.Uint32Array{}
+	return d.SubscriptionIDs.DecodeFromBytes(b[offset:])
+}
+
+// Serialize serializes DeleteSubscriptionsRequest into bytes.
+func (d *DeleteSubscriptionsRequest) Serialize() ([]byte, error) {
+	b := make([]byte, d.Len())
+	if err := d.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes DeleteSubscriptionsRequest into bytes.
+func (d *DeleteSubscriptionsRequest) SerializeTo(b []byte) error {
+	var offset = 0
+	if d.TypeID != nil {
+		if err := d.TypeID.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += d.TypeID.Len()
+	}
+
+	if d.RequestHeader != nil {
+		if err := d.RequestHeader.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += d.RequestHeader.Len()
+	}
+
+	if d.SubscriptionIDs != nil {
+		return d.SubscriptionIDs.SerializeTo(b[offset:])
+	}
+
+	return nil
+}
+
+// Len returns the actual length of DeleteSubscriptionsRequest in int.
+func (d *DeleteSubscriptionsRequest) Len() int {
+	var l = 0
+	if d.TypeID != nil {
+		l += d.TypeID.Len()
+	}
+	if d.RequestHeader != nil {
+		l += d.RequestHeader.Len()
+	}
+	if d.SubscriptionIDs != nil {
+		l += d.SubscriptionIDs.Len()
+	}
+
+	return l
+}
+
+// String returns DeleteSubscriptionsRequest in string.
+func (d *DeleteSubscriptionsRequest) String() string {
+	return fmt.Sprintf("%v, %v, %v",
+		d.TypeID,
+		d.RequestHeader,
+		d.SubscriptionIDs,
+	)
+}
+
+// ServiceType returns type of Service in uint16.
+func (d *DeleteSubscriptionsRequest) ServiceType() uint16 {
+	return ServiceTypeDeleteSubscriptionsRequest
+}
@@ -0,0 +1,145 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package services
+
+import (
+	"fmt"
+
+	"github.com/wmnsk/gopcua/datatypes"
+)
+
+// DeleteSubscriptionsResponse returns the StatusCodes for the Subscriptions
+// in the DeleteSubscriptionsRequest, in the same order.
+//
+// Specification: Part 4, 5.13.8
+type DeleteSubscriptionsResponse struct {
+	TypeID *datatypes.ExpandedNodeID
+	*ResponseHeader
+	Results         *datatypes.Uint32Array
+	DiagnosticInfos *DiagnosticInfoArray
+}
+
+// NewDeleteSubscriptionsResponse creates a new DeleteSubscriptionsResponse.
+func NewDeleteSubscriptionsResponse(resHeader *ResponseHeader, diags []*DiagnosticInfo, results ...uint32) *DeleteSubscriptionsResponse {
+	return &DeleteSubscriptionsResponse{
+		TypeID:          datatypes.NewFourByteExpandedNodeID(0, ServiceTypeDeleteSubscriptionsResponse),
+		ResponseHeader:  resHeader,
+		Results:         datatypes.NewUint32Array(results),
+		DiagnosticInfos: NewDiagnosticInfoArray(diags),
+	}
+}
+
+// DecodeDeleteSubscriptionsResponse decodes given bytes into DeleteSubscriptionsResponse.
+func DecodeDeleteSubscriptionsResponse(b []byte) (*DeleteSubscriptionsResponse, error) {
+	d := &DeleteSubscriptionsResponse{}
+	if err := d.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return d, nil
+}
+
+// DecodeFromBytes decodes given bytes into DeleteSubscriptionsResponse.
+func (d *DeleteSubscriptionsResponse) DecodeFromBytes(b []byte) error {
+	var offset = 0
+	d.TypeID = &datatypes.ExpandedNodeID{}
+	if err := d.TypeID.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += d.TypeID.Len()
+
+	d.ResponseHeader = &ResponseHeader{}
+	if err := d.ResponseHeader.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += d.ResponseHeader.Len() - len(d.ResponseHeader.Payload)
+
+	d.Results = &datatypes.Uint32Array{}
+	if err := d.Results.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += d.Results.Len()
+
+	d.DiagnosticInfos = &DiagnosticInfoArray{}
+	return d.DiagnosticInfos.DecodeFromBytes(b[offset:])
+}
+
+// Serialize serializes DeleteSubscriptionsResponse into bytes.
+func (d *DeleteSubscriptionsResponse) Serialize() ([]byte, error) {
+	b := make([]byte, d.Len())
+	if err := d.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes DeleteSubscriptionsResponse into bytes.
+func (d *DeleteSubscriptionsResponse) SerializeTo(b []byte) error {
+	var offset = 0
+	if d.TypeID != nil {
+		if err := d.TypeID.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += d.TypeID.Len()
+	}
+
+	if d.ResponseHeader != nil {
+		if err := d.ResponseHeader.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += d.ResponseHeader.Len()
+	}
+
+	if d.Results != nil {
+		if err := d.Results.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += d.Results.Len()
+	}
+
+	if d.DiagnosticInfos != nil {
+		return d.DiagnosticInfos.SerializeTo(b[offset:])
+	}
+
+	return nil
+}
+
+// Len returns the actual length of DeleteSubscriptionsResponse in int.
+func (d *DeleteSubscriptionsResponse) Len() int {
+	l := 0
+	if d.TypeID != nil {
+		l += d.TypeID.Len()
+	}
+
+	if d.ResponseHeader != nil {
+		l += d.ResponseHeader.Len()
+	}
+
+	if d.Results != nil {
+		l += d.Results.Len()
+	}
+
+	if d.DiagnosticInfos != nil {
+		l += d.DiagnosticInfos.Len()
+	}
+
+	return l
+}
+
+// String returns DeleteSubscriptionsResponse in string.
+func (d *DeleteSubscriptionsResponse) String() string {
+	return fmt.Sprintf("%v, %v, %v, %v",
+		d.TypeID,
+		d.ResponseHeader,
+		d.Results,
+		d.DiagnosticInfos,
+	)
+}
+
+// ServiceType returns type of Service in uint16.
+func (d *DeleteSubscriptionsResponse) ServiceType() uint16 {
+	return ServiceTypeDeleteSubscriptionsResponse
+}
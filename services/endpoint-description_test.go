@@ -20,7 +20,7 @@ func TestEndpointDescription(t *testing.T) {
 					"app-uri", "prod-uri", "app-name", AppTypeServer,
 					"gw-uri", "prof-uri", []string{"discov-uri-1", "discov-uri-2"},
 				),
-				[]byte{},
+				nil,
 				SecModeNone,
 				"sec-uri",
 				NewUserTokenPolicyArray(
@@ -112,7 +112,7 @@ func TestEndpointDescriptionArray(t *testing.T) {
 							"app-uri", "prod-uri", "app-name", AppTypeServer,
 							"gw-uri", "prof-uri", []string{"discov-uri-1", "discov-uri-2"},
 						),
-						[]byte{},
+						nil,
 						SecModeNone,
 						"sec-uri",
 						NewUserTokenPolicyArray(
@@ -136,7 +136,7 @@ func TestEndpointDescriptionArray(t *testing.T) {
 							"app-uri", "prod-uri", "app-name", AppTypeServer,
 							"gw-uri", "prof-uri", []string{"discov-uri-1", "discov-uri-2"},
 						),
-						[]byte{},
+						nil,
 						SecModeNone,
 						"sec-uri",
 						NewUserTokenPolicyArray(
@@ -5,11 +5,11 @@
 package services
 
 import (
+	"encoding/binary"
 	"time"
 
 	"github.com/wmnsk/gopcua/datatypes"
 	"github.com/wmnsk/gopcua/errors"
-	"github.com/wmnsk/gopcua/utils"
 )
 
 // FindServersOnNetworkResponse returns the Servers known to a Server or Discovery Server. The behaviour of
@@ -62,7 +62,7 @@ func (f *FindServersOnNetworkResponse) DecodeFromBytes(b []byte) error {
 	}
 	offset += f.ResponseHeader.Len() - len(f.ResponseHeader.Payload)
 
-	f.LastCounterResetTime = utils.DecodeTimestamp(b[offset:])
+	f.LastCounterResetTime = datatypes.FromDateTime(int64(binary.LittleEndian.Uint64(b[offset:])))
 	offset += 8
 
 	f.Servers = &datatypes.ServersOnNetworkArray{}
@@ -96,7 +96,7 @@ func (f *FindServersOnNetworkResponse) SerializeTo(b []byte) error {
 		offset += f.ResponseHeader.Len() - len(f.Payload)
 	}
 
-	utils.EncodeTimestamp(b[offset:], f.LastCounterResetTime)
+	binary.LittleEndian.PutUint64(b[offset:], uint64(datatypes.ToDateTime(f.LastCounterResetTime)))
 	offset += 8
 
 	if f.Servers != nil {
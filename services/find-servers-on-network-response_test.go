@@ -31,7 +31,7 @@ func TestFindServersOnNetworkResponse(t *testing.T) {
 			),
 			Bytes: []byte{
 				// TypeID
-				0x01, 0x00, 0xb3, 0x2f,
+				0x01, 0x00, 0xb1, 0x2f,
 				// Timestamp
 				0x00, 0x98, 0x67, 0xdd, 0xfd, 0x30, 0xd4, 0x01,
 				// RequestHandle
@@ -87,7 +87,7 @@ func TestFindServersOnNetworkResponse(t *testing.T) {
 			),
 			Bytes: []byte{
 				// TypeID
-				0x01, 0x00, 0xb3, 0x2f,
+				0x01, 0x00, 0xb1, 0x2f,
 				// Timestamp
 				0x00, 0x98, 0x67, 0xdd, 0xfd, 0x30, 0xd4, 0x01,
 				// RequestHandle
@@ -26,7 +26,7 @@ func TestGetEndpointsResponse(t *testing.T) {
 						"app-uri", "prod-uri", "app-name", AppTypeServer,
 						"gw-uri", "prof-uri", []string{"discov-uri-1", "discov-uri-2"},
 					),
-					[]byte{},
+					nil,
 					SecModeNone,
 					"sec-uri",
 					NewUserTokenPolicyArray(
@@ -50,7 +50,7 @@ func TestGetEndpointsResponse(t *testing.T) {
 						"app-uri", "prod-uri", "app-name", AppTypeServer,
 						"gw-uri", "prof-uri", []string{"discov-uri-1", "discov-uri-2"},
 					),
-					[]byte{},
+					nil,
 					SecModeNone,
 					"sec-uri",
 					NewUserTokenPolicyArray(
@@ -0,0 +1,179 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package services
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/wmnsk/gopcua/datatypes"
+	"github.com/wmnsk/gopcua/errors"
+)
+
+// HistoryReadRequest is used to read historical values or Events of one or more Nodes.
+//
+// HistoryReadDetails is an ExtensionObject which specifies what to read, e.g.
+// ReadRawModifiedDetails to read the raw values. If ReleaseContinuationPoints is true,
+// the ContinuationPoints given in NodesToRead are released and no data is returned.
+//
+// Specification: Part 4, 5.10.3.2
+type HistoryReadRequest struct {
+	TypeID *datatypes.ExpandedNodeID
+	*RequestHeader
+	HistoryReadDetails        *datatypes.ExtensionObject
+	TimestampsToReturn        TimestampsToReturn
+	ReleaseContinuationPoints *datatypes.Boolean
+	NodesToRead               *datatypes.HistoryReadValueIDArray
+}
+
+// NewHistoryReadRequest creates a new HistoryReadRequest.
+func NewHistoryReadRequest(reqHeader *RequestHeader, details datatypes.ExtensionObjectValue, tsRet TimestampsToReturn, release bool, nodes ...*datatypes.HistoryReadValueID) *HistoryReadRequest {
+	return &HistoryReadRequest{
+		TypeID:                    datatypes.NewFourByteExpandedNodeID(0, ServiceTypeHistoryReadRequest),
+		RequestHeader:             reqHeader,
+		HistoryReadDetails:        datatypes.NewExtensionObject(0x01, details),
+		TimestampsToReturn:        tsRet,
+		ReleaseContinuationPoints: datatypes.NewBoolean(release),
+		NodesToRead:               datatypes.NewHistoryReadValueIDArray(nodes),
+	}
+}
+
+// DecodeHistoryReadRequest decodes given bytes into HistoryReadRequest.
+func DecodeHistoryReadRequest(b []byte) (*HistoryReadRequest, error) {
+	h := &HistoryReadRequest{}
+	if err := h.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return h, nil
+}
+
+// DecodeFromBytes decodes given bytes into HistoryReadRequest.
+func (h *HistoryReadRequest) DecodeFromBytes(b []byte) error {
+	var offset = 0
+	h.TypeID = &datatypes.ExpandedNodeID{}
+	if err := h.TypeID.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += h.TypeID.Len()
+
+	h.RequestHeader = &RequestHeader{}
+	if err := h.RequestHeader.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += h.RequestHeader.Len() - len(h.RequestHeader.Payload)
+
+	h.HistoryReadDetails = &datatypes.ExtensionObject{}
+	if err := h.HistoryReadDetails.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += h.HistoryReadDetails.Len()
+
+	if len(b[offset:]) < 4 {
+		return errors.NewErrTooShortToDecode(h, "should contain TimestampsToReturn")
+	}
+	h.TimestampsToReturn = TimestampsToReturn(binary.LittleEndian.Uint32(b[offset : offset+4]))
+	offset += 4
+
+	h.ReleaseContinuationPoints = &datatypes.Boolean{}
+	if err := h.ReleaseContinuationPoints.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += h.ReleaseContinuationPoints.Len()
+
+	h.NodesToRead = &datatypes.HistoryReadValueIDArray{}
+	return h.NodesToRead.DecodeFromBytes(b[offset:])
+}
+
+// Serialize serializes HistoryReadRequest into bytes.
+func (h *HistoryReadRequest) Serialize() ([]byte, error) {
+	b := make([]byte, h.Len())
+	if err := h.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes HistoryReadRequest into bytes.
+func (h *HistoryReadRequest) SerializeTo(b []byte) error {
+	var offset = 0
+	if h.TypeID != nil {
+		if err := h.TypeID.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += h.TypeID.Len()
+	}
+
+	if h.RequestHeader != nil {
+		if err := h.RequestHeader.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += h.RequestHeader.Len()
+	}
+
+	if h.HistoryReadDetails != nil {
+		if err := h.HistoryReadDetails.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += h.HistoryReadDetails.Len()
+	}
+
+	binary.LittleEndian.PutUint32(b[offset:offset+4], uint32(h.TimestampsToReturn))
+	offset += 4
+
+	if h.ReleaseContinuationPoints != nil {
+		if err := h.ReleaseContinuationPoints.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += h.ReleaseContinuationPoints.Len()
+	}
+
+	if h.NodesToRead != nil {
+		return h.NodesToRead.SerializeTo(b[offset:])
+	}
+
+	return nil
+}
+
+// Len returns the actual length of HistoryReadRequest in int.
+func (h *HistoryReadRequest) Len() int {
+	// timestamps to return
+	var l = 4
+	if h.TypeID != nil {
+		l += h.TypeID.Len()
+	}
+	if h.RequestHeader != nil {
+		l += h.RequestHeader.Len()
+	}
+	if h.HistoryReadDetails != nil {
+		l += h.HistoryReadDetails.Len()
+	}
+	if h.ReleaseContinuationPoints != nil {
+		l += h.ReleaseContinuationPoints.Len()
+	}
+	if h.NodesToRead != nil {
+		l += h.NodesToRead.Len()
+	}
+
+	return l
+}
+
+// String returns HistoryReadRequest in string.
+func (h *HistoryReadRequest) String() string {
+	return fmt.Sprintf("%v, %v, %v, %v, %v, %v",
+		h.TypeID,
+		h.RequestHeader,
+		h.HistoryReadDetails,
+		h.TimestampsToReturn,
+		h.ReleaseContinuationPoints,
+		h.NodesToRead,
+	)
+}
+
+// ServiceType returns type of Service in uint16.
+func (h *HistoryReadRequest) ServiceType() uint16 {
+	return ServiceTypeHistoryReadRequest
+}
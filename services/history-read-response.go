@@ -0,0 +1,142 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package services
+
+import (
+	"fmt"
+
+	"github.com/wmnsk/gopcua/datatypes"
+)
+
+// HistoryReadResponse is the response to HistoryReadRequest, which contains
+// a HistoryReadResult for each of the NodesToRead in the request.
+//
+// Specification: Part 4, 5.10.3.2
+type HistoryReadResponse struct {
+	TypeID *datatypes.ExpandedNodeID
+	*ResponseHeader
+	Results         *datatypes.HistoryReadResultArray
+	DiagnosticInfos *DiagnosticInfoArray
+}
+
+// NewHistoryReadResponse creates a new HistoryReadResponse.
+func NewHistoryReadResponse(resHeader *ResponseHeader, diags []*DiagnosticInfo, results ...*datatypes.HistoryReadResult) *HistoryReadResponse {
+	return &HistoryReadResponse{
+		TypeID:          datatypes.NewFourByteExpandedNodeID(0, ServiceTypeHistoryReadResponse),
+		ResponseHeader:  resHeader,
+		Results:         datatypes.NewHistoryReadResultArray(results),
+		DiagnosticInfos: NewDiagnosticInfoArray(diags),
+	}
+}
+
+// DecodeHistoryReadResponse decodes given bytes into HistoryReadResponse.
+func DecodeHistoryReadResponse(b []byte) (*HistoryReadResponse, error) {
+	h := &HistoryReadResponse{}
+	if err := h.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return h, nil
+}
+
+// DecodeFromBytes decodes given bytes into HistoryReadResponse.
+func (h *HistoryReadResponse) DecodeFromBytes(b []byte) error {
+	var offset = 0
+	h.TypeID = &datatypes.ExpandedNodeID{}
+	if err := h.TypeID.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += h.TypeID.Len()
+
+	h.ResponseHeader = &ResponseHeader{}
+	if err := h.ResponseHeader.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += h.ResponseHeader.Len() - len(h.ResponseHeader.Payload)
+
+	h.Results = &datatypes.HistoryReadResultArray{}
+	if err := h.Results.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += h.Results.Len()
+
+	h.DiagnosticInfos = &DiagnosticInfoArray{}
+	return h.DiagnosticInfos.DecodeFromBytes(b[offset:])
+}
+
+// Serialize serializes HistoryReadResponse into bytes.
+func (h *HistoryReadResponse) Serialize() ([]byte, error) {
+	b := make([]byte, h.Len())
+	if err := h.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes HistoryReadResponse into bytes.
+func (h *HistoryReadResponse) SerializeTo(b []byte) error {
+	var offset = 0
+	if h.TypeID != nil {
+		if err := h.TypeID.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += h.TypeID.Len()
+	}
+
+	if h.ResponseHeader != nil {
+		if err := h.ResponseHeader.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += h.ResponseHeader.Len()
+	}
+
+	if h.Results != nil {
+		if err := h.Results.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += h.Results.Len()
+	}
+
+	if h.DiagnosticInfos != nil {
+		return h.DiagnosticInfos.SerializeTo(b[offset:])
+	}
+
+	return nil
+}
+
+// Len returns the actual length of HistoryReadResponse in int.
+func (h *HistoryReadResponse) Len() int {
+	var l = 0
+	if h.TypeID != nil {
+		l += h.TypeID.Len()
+	}
+	if h.ResponseHeader != nil {
+		l += h.ResponseHeader.Len()
+	}
+	if h.Results != nil {
+		l += h.Results.Len()
+	}
+	if h.DiagnosticInfos != nil {
+		l += h.DiagnosticInfos.Len()
+	}
+
+	return l
+}
+
+// String returns HistoryReadResponse in string.
+func (h *HistoryReadResponse) String() string {
+	return fmt.Sprintf("%v, %v, %v, %v",
+		h.TypeID,
+		h.ResponseHeader,
+		h.Results,
+		h.DiagnosticInfos,
+	)
+}
+
+// ServiceType returns type of Service in uint16.
+func (h *HistoryReadResponse) ServiceType() uint16 {
+	return ServiceTypeHistoryReadResponse
+}
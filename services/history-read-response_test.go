@@ -0,0 +1,90 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/wmnsk/gopcua/datatypes"
+	"github.com/wmnsk/gopcua/utils/codectest"
+)
+
+func TestHistoryReadResponse(t *testing.T) {
+	cases := []codectest.Case{
+		{
+			Name: "with continuation point",
+			Struct: NewHistoryReadResponse(
+				NewResponseHeader(
+					time.Date(2018, time.August, 10, 23, 0, 0, 0, time.UTC),
+					1, 0, NewNullDiagnosticInfo(), []string{}, NewNullAdditionalHeader(), nil,
+				),
+				nil,
+				datatypes.NewHistoryReadResult(
+					0, []byte{0xde, 0xad},
+					datatypes.NewHistoryData(
+						datatypes.NewDataValue(
+							true, false, true, false, false, false,
+							datatypes.NewVariant(datatypes.NewFloat(2.50025)), 0,
+							time.Date(2018, time.August, 10, 23, 0, 0, 0, time.UTC), 0,
+							time.Time{}, 0,
+						),
+					),
+				),
+				datatypes.NewHistoryReadResult(0x80340000, nil, nil),
+			),
+			Bytes: []byte{
+				// TypeID
+				0x01, 0x00, 0x9b, 0x02,
+				// Timestamp
+				0x00, 0x98, 0x67, 0xdd, 0xfd, 0x30, 0xd4, 0x01,
+				// RequestHandle
+				0x01, 0x00, 0x00, 0x00,
+				// ServiceResult
+				0x00, 0x00, 0x00, 0x00,
+				// ServiceDiagnostics
+				0x00,
+				// StringTable
+				0x00, 0x00, 0x00, 0x00,
+				// AdditionalHeader
+				0x00, 0x00, 0x00,
+				// Results: ArraySize
+				0x02, 0x00, 0x00, 0x00,
+				// StatusCode
+				0x00, 0x00, 0x00, 0x00,
+				// ContinuationPoint
+				0x02, 0x00, 0x00, 0x00, 0xde, 0xad,
+				// HistoryData: TypeID, EncodingMask, Length
+				0x01, 0x00, 0x92, 0x02, 0x01, 0x12, 0x00, 0x00, 0x00,
+				// DataValues
+				0x01, 0x00, 0x00, 0x00, 0x05, 0x0a, 0x19, 0x04, 0x20, 0x40,
+				0x00, 0x98, 0x67, 0xdd, 0xfd, 0x30, 0xd4, 0x01,
+				// StatusCode
+				0x00, 0x00, 0x34, 0x80,
+				// ContinuationPoint
+				0xff, 0xff, 0xff, 0xff,
+				// HistoryData
+				0x00, 0x00, 0x00,
+				// DiagnosticInfos
+				0x00, 0x00, 0x00, 0x00,
+			},
+		},
+	}
+	codectest.Run(t, cases, func(b []byte) (codectest.S, error) {
+		v, err := DecodeHistoryReadResponse(b)
+		if err != nil {
+			return nil, err
+		}
+		v.Payload = nil
+		return v, nil
+	})
+
+	t.Run("service-id", func(t *testing.T) {
+		id := new(HistoryReadResponse).ServiceType()
+		if got, want := id, uint16(ServiceTypeHistoryReadResponse); got != want {
+			t.Fatalf("got %d want %d", got, want)
+		}
+	})
+}
@@ -0,0 +1,138 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package services
+
+import (
+	"encoding/binary"
+
+	"github.com/wmnsk/gopcua/datatypes"
+)
+
+// ModifyMonitoredItemsRequest is used to modify the parameters of one or more MonitoredItems
+// of a Subscription, e.g. to change the sampling interval at runtime.
+//
+// Specification: Part 4, 5.12.3.2
+type ModifyMonitoredItemsRequest struct {
+	TypeID *datatypes.ExpandedNodeID
+	*RequestHeader
+
+	// The Server-assigned identifier for the Subscription that contains the MonitoredItems.
+	SubscriptionID uint32
+
+	// The timestamp Attributes to be transmitted for each MonitoredItem.
+	TimestampsToReturn TimestampsToReturn
+
+	// A list of MonitoredItems to be modified with the parameters requested.
+	ItemsToModify *datatypes.MonitoredItemModifyRequestArray
+}
+
+// NewModifyMonitoredItemsRequest creates a new ModifyMonitoredItemsRequest.
+func NewModifyMonitoredItemsRequest(reqHeader *RequestHeader, subID uint32, ts TimestampsToReturn, items ...*datatypes.MonitoredItemModifyRequest) *ModifyMonitoredItemsRequest {
+	return &ModifyMonitoredItemsRequest{
+		TypeID:             datatypes.NewFourByteExpandedNodeID(0, ServiceTypeModifyMonitoredItemsRequest),
+		RequestHeader:      reqHeader,
+		SubscriptionID:     subID,
+		TimestampsToReturn: ts,
+		ItemsToModify:      datatypes.NewMonitoredItemModifyRequestArray(items),
+	}
+}
+
+// DecodeModifyMonitoredItemsRequest decodes given bytes into ModifyMonitoredItemsRequest.
+func DecodeModifyMonitoredItemsRequest(b []byte) (*ModifyMonitoredItemsRequest, error) {
+	c := &ModifyMonitoredItemsRequest{}
+	if err := c.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// DecodeFromBytes decodes given bytes into ModifyMonitoredItemsRequest.
+func (c *ModifyMonitoredItemsRequest) DecodeFromBytes(b []byte) error {
+	offset := 0
+	c.TypeID = &datatypes.ExpandedNodeID{}
+	if err := c.TypeID.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += c.TypeID.Len()
+
+	c.RequestHeader = &RequestHeader{}
+	if err := c.RequestHeader.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += c.RequestHeader.Len() - len(c.RequestHeader.Payload)
+
+	c.SubscriptionID = binary.LittleEndian.Uint32(b[offset : offset+4])
+	offset += 4
+
+	c.TimestampsToReturn = TimestampsToReturn(binary.LittleEndian.Uint32(b[offset : offset+4]))
+	offset += 4
+
+	c.ItemsToModify = &datatypes.MonitoredItemModifyRequestArray{}
+	return c.ItemsToModify.DecodeFromBytes(b[offset:])
+}
+
+// Serialize serializes ModifyMonitoredItemsRequest into bytes.
+func (c *ModifyMonitoredItemsRequest) Serialize() ([]byte, error) {
+	b := make([]byte, c.Len())
+	if err := c.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes ModifyMonitoredItemsRequest into bytes.
+func (c *ModifyMonitoredItemsRequest) SerializeTo(b []byte) error {
+	offset := 0
+	if c.TypeID != nil {
+		if err := c.TypeID.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += c.TypeID.Len()
+	}
+
+	if c.RequestHeader != nil {
+		if err := c.RequestHeader.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += c.RequestHeader.Len() - len(c.Payload)
+	}
+
+	binary.LittleEndian.PutUint32(b[offset:offset+4], c.SubscriptionID)
+	offset += 4
+
+	binary.LittleEndian.PutUint32(b[offset:offset+4], uint32(c.TimestampsToReturn))
+	offset += 4
+
+	if c.ItemsToModify != nil {
+		return c.ItemsToModify.SerializeTo(b[offset:])
+	}
+
+	return nil
+}
+
+// Len returns the actual length of ModifyMonitoredItemsRequest in int.
+func (c *ModifyMonitoredItemsRequest) Len() int {
+	l := 8
+	if c.TypeID != nil {
+		l += c.TypeID.Len()
+	}
+
+	if c.RequestHeader != nil {
+		l += c.RequestHeader.Len()
+	}
+
+	if c.ItemsToModify != nil {
+		l += c.ItemsToModify.Len()
+	}
+
+	return l
+}
+
+// ServiceType returns type of Service in uint16.
+func (c *ModifyMonitoredItemsRequest) ServiceType() uint16 {
+	return ServiceTypeModifyMonitoredItemsRequest
+}
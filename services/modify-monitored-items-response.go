@@ -0,0 +1,145 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package services
+
+import (
+	"fmt"
+
+	"github.com/wmnsk/gopcua/datatypes"
+)
+
+// ModifyMonitoredItemsResponse is returned by the Server for the ModifyMonitoredItemsRequest.
+// It has a MonitoredItemModifyResult for each item to modify.
+//
+// Specification: Part 4, 5.12.3.2
+type ModifyMonitoredItemsResponse struct {
+	TypeID *datatypes.ExpandedNodeID
+	*ResponseHeader
+	Results         *datatypes.MonitoredItemModifyResultArray
+	DiagnosticInfos *DiagnosticInfoArray
+}
+
+// NewModifyMonitoredItemsResponse creates a new ModifyMonitoredItemsResponse.
+func NewModifyMonitoredItemsResponse(resHeader *ResponseHeader, diags []*DiagnosticInfo, results ...*datatypes.MonitoredItemModifyResult) *ModifyMonitoredItemsResponse {
+	return &ModifyMonitoredItemsResponse{
+		TypeID:          datatypes.NewFourByteExpandedNodeID(0, ServiceTypeModifyMonitoredItemsResponse),
+		ResponseHeader:  resHeader,
+		Results:         datatypes.NewMonitoredItemModifyResultArray(results),
+		DiagnosticInfos: NewDiagnosticInfoArray(diags),
+	}
+}
+
+// DecodeModifyMonitoredItemsResponse decodes given bytes into ModifyMonitoredItemsResponse.
+func DecodeModifyMonitoredItemsResponse(b []byte) (*ModifyMonitoredItemsResponse, error) {
+	r := &ModifyMonitoredItemsResponse{}
+	if err := r.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// DecodeFromBytes decodes given bytes into ModifyMonitoredItemsResponse.
+func (r *ModifyMonitoredItemsResponse) DecodeFromBytes(b []byte) error {
+	var offset = 0
+	r.TypeID = &datatypes.ExpandedNodeID{}
+	if err := r.TypeID.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += r.TypeID.Len()
+
+	r.ResponseHeader = &ResponseHeader{}
+	if err := r.ResponseHeader.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += r.ResponseHeader.Len() - len(r.ResponseHeader.Payload)
+
+	r.Results = &datatypes.MonitoredItemModifyResultArray{}
+	if err := r.Results.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += r.Results.Len()
+
+	r.DiagnosticInfos = &DiagnosticInfoArray{}
+	return r.DiagnosticInfos.DecodeFromBytes(b[offset:])
+}
+
+// Serialize serializes ModifyMonitoredItemsResponse into bytes.
+func (r *ModifyMonitoredItemsResponse) Serialize() ([]byte, error) {
+	b := make([]byte, r.Len())
+	if err := r.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes ModifyMonitoredItemsResponse into bytes.
+func (r *ModifyMonitoredItemsResponse) SerializeTo(b []byte) error {
+	var offset = 0
+	if r.TypeID != nil {
+		if err := r.TypeID.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += r.TypeID.Len()
+	}
+
+	if r.ResponseHeader != nil {
+		if err := r.ResponseHeader.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += r.ResponseHeader.Len()
+	}
+
+	if r.Results != nil {
+		if err := r.Results.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += r.Results.Len()
+	}
+
+	if r.DiagnosticInfos != nil {
+		return r.DiagnosticInfos.SerializeTo(b[offset:])
+	}
+
+	return nil
+}
+
+// Len returns the actual length of ModifyMonitoredItemsResponse in int.
+func (r *ModifyMonitoredItemsResponse) Len() int {
+	l := 0
+	if r.TypeID != nil {
+		l += r.TypeID.Len()
+	}
+
+	if r.ResponseHeader != nil {
+		l += r.ResponseHeader.Len()
+	}
+
+	if r.Results != nil {
+		l += r.Results.Len()
+	}
+
+	if r.DiagnosticInfos != nil {
+		l += r.DiagnosticInfos.Len()
+	}
+
+	return l
+}
+
+// String returns ModifyMonitoredItemsResponse in string.
+func (r *ModifyMonitoredItemsResponse) String() string {
+	return fmt.Sprintf("%v, %v, %v, %v",
+		r.TypeID,
+		r.ResponseHeader,
+		r.Results,
+		r.DiagnosticInfos,
+	)
+}
+
+// ServiceType returns type of Service in uint16.
+func (r *ModifyMonitoredItemsResponse) ServiceType() uint16 {
+	return ServiceTypeModifyMonitoredItemsResponse
+}
@@ -0,0 +1,126 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package services
+
+import (
+	"fmt"
+
+	"github.com/wmnsk/gopcua/datatypes"
+)
+
+// PublishRequest is used to acknowledge the receipt of NotificationMessages and to
+// request the Server to return a NotificationMessage or a keep-alive message.
+//
+// PublishRequests are not directed to a certain Subscription, and the Client is expected
+// to keep enough of them outstanding so that the Server can send Notifications anytime.
+//
+// Specification: Part 4, 5.13.5
+type PublishRequest struct {
+	TypeID *datatypes.ExpandedNodeID
+	*RequestHeader
+	SubscriptionAcknowledgements *datatypes.SubscriptionAcknowledgementArray
+}
+
+// NewPublishRequest creates a new PublishRequest.
+func NewPublishRequest(reqHeader *RequestHeader, acks ...*datatypes.SubscriptionAcknowledgement) *PublishRequest {
+	return &PublishRequest{
+		TypeID:                       datatypes.NewFourByteExpandedNodeID(0, ServiceTypePublishRequest),
+		RequestHeader:                reqHeader,
+		SubscriptionAcknowledgements: datatypes.NewSubscriptionAcknowledgementArray(acks),
+	}
+}
+
+// DecodePublishRequest decodes given bytes into PublishRequest.
+func DecodePublishRequest(b []byte) (*PublishRequest, error) {
+	p := &PublishRequest{}
+	if err := p.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// DecodeFromBytes decodes given bytes into PublishRequest.
+func (p *PublishRequest) DecodeFromBytes(b []byte) error {
+	var offset = 0
+	p.TypeID = &datatypes.ExpandedNodeID{}
+	if err := p.TypeID.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += p.TypeID.Len()
+
+	p.RequestHeader = &RequestHeader{}
+	if err := p.RequestHeader.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += p.RequestHeader.Len() - len(p.RequestHeader.Payload)
+
+	p.SubscriptionAcknowledgements = &datatypes.SubscriptionAcknowledgementArray{}
+	return p.SubscriptionAcknowledgements.DecodeFromBytes(b[offset:])
+}
+
+// Serialize serializes PublishRequest into bytes.
+func (p *PublishRequest) Serialize() ([]byte, error) {
+	b := make([]byte, p.Len())
+	if err := p.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes PublishRequest into bytes.
+func (p *PublishRequest) SerializeTo(b []byte) error {
+	var offset = 0
+	if p.TypeID != nil {
+		if err := p.TypeID.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += p.TypeID.Len()
+	}
+
+	if p.RequestHeader != nil {
+		if err := p.RequestHeader.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += p.RequestHeader.Len()
+	}
+
+	if p.SubscriptionAcknowledgements != nil {
+		return p.SubscriptionAcknowledgements.SerializeTo(b[offset:])
+	}
+
+	return nil
+}
+
+// Len returns the actual length of PublishRequest in int.
+func (p *PublishRequest) Len() int {
+	var l = 0
+	if p.TypeID != nil {
+		l += p.TypeID.Len()
+	}
+	if p.RequestHeader != nil {
+		l += p.RequestHeader.Len()
+	}
+	if p.SubscriptionAcknowledgements != nil {
+		l += p.SubscriptionAcknowledgements.Len()
+	}
+
+	return l
+}
+
+// String returns PublishRequest in string.
+func (p *PublishRequest) String() string {
+	return fmt.Sprintf("%v, %v, %v",
+		p.TypeID,
+		p.RequestHeader,
+		p.SubscriptionAcknowledgements,
+	)
+}
+
+// ServiceType returns type of Service in uint16.
+func (p *PublishRequest) ServiceType() uint16 {
+	return ServiceTypePublishRequest
+}
@@ -0,0 +1,211 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package services
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/wmnsk/gopcua/datatypes"
+)
+
+// PublishResponse returns a NotificationMessage or a keep-alive message of the Subscription
+// identified by SubscriptionID, for one of the outstanding PublishRequests.
+//
+// Results are the results of the SubscriptionAcknowledgements in the PublishRequest.
+//
+// Specification: Part 4, 5.13.5
+type PublishResponse struct {
+	TypeID *datatypes.ExpandedNodeID
+	*ResponseHeader
+	SubscriptionID           uint32
+	AvailableSequenceNumbers *datatypes.Uint32Array
+	MoreNotifications        *datatypes.Boolean
+	NotificationMessage      *datatypes.NotificationMessage
+	Results                  *datatypes.Uint32Array
+	DiagnosticInfos          *DiagnosticInfoArray
+}
+
+// NewPublishResponse creates a new PublishResponse.
+func NewPublishResponse(resHeader *ResponseHeader, subID uint32, seqs []uint32, more bool, msg *datatypes.NotificationMessage, diags []*DiagnosticInfo, results ...uint32) *PublishResponse {
+	return &PublishResponse{
+		TypeID:                   datatypes.NewFourByteExpandedNodeID(0, ServiceTypePublishResponse),
+		ResponseHeader:           resHeader,
+		SubscriptionID:           subID,
+		AvailableSequenceNumbers: datatypes.NewUint32Array(seqs),
+		MoreNotifications:        datatypes.NewBoolean(more),
+		NotificationMessage:      msg,
+		Results:                  datatypes.NewUint32Array(results),
+		DiagnosticInfos:          NewDiagnosticInfoArray(diags),
+	}
+}
+
+// DecodePublishResponse decodes given bytes into PublishResponse.
+func DecodePublishResponse(b []byte) (*PublishResponse, error) {
+	p := &PublishResponse{}
+	if err := p.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// DecodeFromBytes decodes given bytes into PublishResponse.
+func (p *PublishResponse) DecodeFromBytes(b []byte) error {
+	var offset = 0
+	p.TypeID = &datatypes.ExpandedNodeID{}
+	if err := p.TypeID.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += p.TypeID.Len()
+
+	p.ResponseHeader = &ResponseHeader{}
+	if err := p.ResponseHeader.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += p.ResponseHeader.Len() - len(p.ResponseHeader.Payload)
+
+	p.SubscriptionID = binary.LittleEndian.Uint32(b[offset : offset+4])
+	offset += 4
+
+	p.AvailableSequenceNumbers = &datatypes.Uint32Array{}
+	if err := p.AvailableSequenceNumbers.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += p.AvailableSequenceNumbers.Len()
+
+	p.MoreNotifications = &datatypes.Boolean{}
+	if err := p.MoreNotifications.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += p.MoreNotifications.Len()
+
+	p.NotificationMessage = &datatypes.NotificationMessage{}
+	if err := p.NotificationMessage.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += p.NotificationMessage.Len()
+
+	p.Results = &datatypes.Uint32Array{}
+	if err := p.Results.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += p.Results.Len()
+
+	p.DiagnosticInfos = &DiagnosticInfoArray{}
+	return p.DiagnosticInfos.DecodeFromBytes(b[offset:])
+}
+
+// Serialize serializes PublishResponse into bytes.
+func (p *PublishResponse) Serialize() ([]byte, error) {
+	b := make([]byte, p.Len())
+	if err := p.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes PublishResponse into bytes.
+func (p *PublishResponse) SerializeTo(b []byte) error {
+	var offset = 0
+	if p.TypeID != nil {
+		if err := p.TypeID.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += p.TypeID.Len()
+	}
+
+	if p.ResponseHeader != nil {
+		if err := p.ResponseHeader.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += p.ResponseHeader.Len()
+	}
+
+	binary.LittleEndian.PutUint32(b[offset:offset+4], p.SubscriptionID)
+	offset += 4
+
+	if p.AvailableSequenceNumbers != nil {
+		if err := p.AvailableSequenceNumbers.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += p.AvailableSequenceNumbers.Len()
+	}
+
+	if p.MoreNotifications != nil {
+		if err := p.MoreNotifications.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += p.MoreNotifications.Len()
+	}
+
+	if p.NotificationMessage != nil {
+		if err := p.NotificationMessage.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += p.NotificationMessage.Len()
+	}
+
+	if p.Results != nil {
+		if err := p.Results.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += p.Results.Len()
+	}
+
+	if p.DiagnosticInfos != nil {
+		return p.DiagnosticInfos.SerializeTo(b[offset:])
+	}
+
+	return nil
+}
+
+// Len returns the actual length of PublishResponse in int.
+func (p *PublishResponse) Len() int {
+	var l = 4
+	if p.TypeID != nil {
+		l += p.TypeID.Len()
+	}
+	if p.ResponseHeader != nil {
+		l += p.ResponseHeader.Len()
+	}
+	if p.AvailableSequenceNumbers != nil {
+		l += p.AvailableSequenceNumbers.Len()
+	}
+	if p.MoreNotifications != nil {
+		l += p.MoreNotifications.Len()
+	}
+	if p.NotificationMessage != nil {
+		l += p.NotificationMessage.Len()
+	}
+	if p.Results != nil {
+		l += p.Results.Len()
+	}
+	if p.DiagnosticInfos != nil {
+		l += p.DiagnosticInfos.Len()
+	}
+
+	return l
+}
+
+// String returns PublishResponse in string.
+func (p *PublishResponse) String() string {
+	return fmt.Sprintf("%v, %v, %d, %v, %v, %v, %v, %v",
+		p.TypeID,
+		p.ResponseHeader,
+		p.SubscriptionID,
+		p.AvailableSequenceNumbers,
+		p.MoreNotifications,
+		p.NotificationMessage,
+		p.Results,
+		p.DiagnosticInfos,
+	)
+}
+
+// ServiceType returns type of Service in uint16.
+func (p *PublishResponse) ServiceType() uint16 {
+	return ServiceTypePublishResponse
+}
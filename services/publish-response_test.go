@@ -0,0 +1,132 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/wmnsk/gopcua/datatypes"
+	"github.com/wmnsk/gopcua/utils/codectest"
+)
+
+func TestPublishResponse(t *testing.T) {
+	cases := []codectest.Case{
+		{
+			Name: "data-change",
+			Struct: NewPublishResponse(
+				NewResponseHeader(
+					time.Date(2018, time.August, 10, 23, 0, 0, 0, time.UTC),
+					1, 0, NewNullDiagnosticInfo(), []string{}, NewNullAdditionalHeader(), nil,
+				),
+				1, []uint32{1}, false,
+				datatypes.NewNotificationMessage(
+					1, time.Date(2018, time.August, 10, 23, 0, 0, 0, time.UTC),
+					datatypes.NewDataChangeNotification(
+						datatypes.NewMonitoredItemNotification(
+							1, &datatypes.DataValue{EncodingMask: 0x01, Value: datatypes.NewVariant(datatypes.NewFloat(2.50025))},
+						),
+					),
+				),
+				nil,
+			),
+			Bytes: []byte{
+				// TypeID
+				0x01, 0x00, 0x3d, 0x03,
+				// Timestamp
+				0x00, 0x98, 0x67, 0xdd, 0xfd, 0x30, 0xd4, 0x01,
+				// RequestHandle
+				0x01, 0x00, 0x00, 0x00,
+				// ServiceResult
+				0x00, 0x00, 0x00, 0x00,
+				// ServiceDiagnostics
+				0x00,
+				// StringTable
+				0x00, 0x00, 0x00, 0x00,
+				// AdditionalHeader
+				0x00, 0x00, 0x00,
+				// SubscriptionID
+				0x01, 0x00, 0x00, 0x00,
+				// AvailableSequenceNumbers
+				0x01, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00,
+				// MoreNotifications
+				0x00,
+				// NotificationMessage: SequenceNumber
+				0x01, 0x00, 0x00, 0x00,
+				// PublishTime
+				0x00, 0x98, 0x67, 0xdd, 0xfd, 0x30, 0xd4, 0x01,
+				// NotificationData
+				0x01, 0x00, 0x00, 0x00,
+				0x01, 0x00, 0x2b, 0x03, 0x01, 0x12, 0x00, 0x00, 0x00,
+				0x01, 0x00, 0x00, 0x00,
+				0x01, 0x00, 0x00, 0x00,
+				0x01, 0x0a, 0x19, 0x04, 0x20, 0x40,
+				0x00, 0x00, 0x00, 0x00,
+				// Results
+				0x00, 0x00, 0x00, 0x00,
+				// DiagnosticInfos
+				0x00, 0x00, 0x00, 0x00,
+			},
+		},
+		{
+			Name: "keep-alive",
+			Struct: NewPublishResponse(
+				NewResponseHeader(
+					time.Date(2018, time.August, 10, 23, 0, 0, 0, time.UTC),
+					1, 0, NewNullDiagnosticInfo(), []string{}, NewNullAdditionalHeader(), nil,
+				),
+				1, nil, false,
+				datatypes.NewNotificationMessage(2, time.Date(2018, time.August, 10, 23, 0, 0, 0, time.UTC)),
+				nil,
+				0,
+			),
+			Bytes: []byte{
+				// TypeID
+				0x01, 0x00, 0x3d, 0x03,
+				// Timestamp
+				0x00, 0x98, 0x67, 0xdd, 0xfd, 0x30, 0xd4, 0x01,
+				// RequestHandle
+				0x01, 0x00, 0x00, 0x00,
+				// ServiceResult
+				0x00, 0x00, 0x00, 0x00,
+				// ServiceDiagnostics
+				0x00,
+				// StringTable
+				0x00, 0x00, 0x00, 0x00,
+				// AdditionalHeader
+				0x00, 0x00, 0x00,
+				// SubscriptionID
+				0x01, 0x00, 0x00, 0x00,
+				// AvailableSequenceNumbers
+				0x00, 0x00, 0x00, 0x00,
+				// MoreNotifications
+				0x00,
+				// NotificationMessage
+				0x02, 0x00, 0x00, 0x00,
+				0x00, 0x98, 0x67, 0xdd, 0xfd, 0x30, 0xd4, 0x01,
+				0x00, 0x00, 0x00, 0x00,
+				// Results
+				0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00,
+				// DiagnosticInfos
+				0x00, 0x00, 0x00, 0x00,
+			},
+		},
+	}
+	codectest.Run(t, cases, func(b []byte) (codectest.S, error) {
+		v, err := DecodePublishResponse(b)
+		if err != nil {
+			return nil, err
+		}
+		v.Payload = nil
+		return v, nil
+	})
+
+	t.Run("service-id", func(t *testing.T) {
+		id := new(PublishResponse).ServiceType()
+		if got, want := id, uint16(ServiceTypePublishResponse); got != want {
+			t.Fatalf("got %d want %d", got, want)
+		}
+	})
+}
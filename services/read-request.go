@@ -6,6 +6,7 @@ package services
 
 import (
 	"encoding/binary"
+	"math"
 
 	"github.com/wmnsk/gopcua/datatypes"
 )
@@ -71,7 +72,7 @@ type ReadRequest struct {
 	// a cached value.
 	//
 	// Negative values are invalid for maxAge.
-	MaxAge uint64
+	MaxAge float64
 
 	// An enumeration that specifies the Timestamps to be returned for each requested
 	// Variable Value Attribute.
@@ -83,7 +84,7 @@ type ReadRequest struct {
 }
 
 // NewReadRequest creates a new ReadRequest.
-func NewReadRequest(reqHeader *RequestHeader, maxAge uint64, tsRet TimestampsToReturn, nodes ...*datatypes.ReadValueID) *ReadRequest {
+func NewReadRequest(reqHeader *RequestHeader, maxAge float64, tsRet TimestampsToReturn, nodes ...*datatypes.ReadValueID) *ReadRequest {
 	return &ReadRequest{
 		TypeID:             datatypes.NewFourByteExpandedNodeID(0, ServiceTypeReadRequest),
 		RequestHeader:      reqHeader,
@@ -120,7 +121,7 @@ func (r *ReadRequest) DecodeFromBytes(b []byte) error {
 	offset += r.RequestHeader.Len() - len(r.RequestHeader.Payload)
 
 	// max age
-	r.MaxAge = binary.LittleEndian.Uint64(b[offset : offset+8])
+	r.MaxAge = math.Float64frombits(binary.LittleEndian.Uint64(b[offset : offset+8]))
 	offset += 8
 
 	// timestamps to return
@@ -163,7 +164,7 @@ func (r *ReadRequest) SerializeTo(b []byte) error {
 	offset += r.RequestHeader.Len()
 
 	// max age
-	binary.LittleEndian.PutUint64(b[offset:offset+8], r.MaxAge)
+	binary.LittleEndian.PutUint64(b[offset:offset+8], math.Float64bits(r.MaxAge))
 	offset += 8
 
 	// timestamps to return
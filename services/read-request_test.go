@@ -28,7 +28,7 @@ func TestReadRequest(t *testing.T) {
 				0, TimestampsToReturnBoth,
 				datatypes.NewReadValueID(
 					datatypes.NewFourByteNodeID(0, 2256),
-					datatypes.IntegerIDValue,
+					datatypes.AttributeIDValue,
 					"", 0, "",
 				),
 			),
@@ -61,6 +61,53 @@ func TestReadRequest(t *testing.T) {
 				0x00, 0x00, 0xff, 0xff, 0xff, 0xff,
 			},
 		},
+		{
+			Name: "max-age",
+			Struct: NewReadRequest(
+				NewRequestHeader(
+					datatypes.NewOpaqueNodeID(0x00, []byte{
+						0x08, 0x22, 0x87, 0x62, 0xba, 0x81, 0xe1, 0x11,
+						0xa6, 0x43, 0xf8, 0x77, 0x7b, 0xc6, 0x2f, 0xc8,
+					}),
+					time.Date(2018, time.August, 10, 23, 0, 0, 0, time.UTC),
+					1, 0, 0, "", NewNullAdditionalHeader(), nil,
+				),
+				500, TimestampsToReturnBoth,
+				datatypes.NewReadValueID(
+					datatypes.NewFourByteNodeID(0, 2256),
+					datatypes.AttributeIDValue,
+					"", 0, "",
+				),
+			),
+			Bytes: []byte{
+				// TypeID
+				0x01, 0x00, 0x77, 0x02,
+				// AuthenticationToken
+				0x05, 0x00, 0x00, 0x10, 0x00, 0x00, 0x00, 0x08,
+				0x22, 0x87, 0x62, 0xba, 0x81, 0xe1, 0x11, 0xa6,
+				0x43, 0xf8, 0x77, 0x7b, 0xc6, 0x2f, 0xc8,
+				// Timestamp
+				0x00, 0x98, 0x67, 0xdd, 0xfd, 0x30, 0xd4, 0x01,
+				// RequestHandle
+				0x01, 0x00, 0x00, 0x00,
+				// ReturnDiagnostics
+				0x00, 0x00, 0x00, 0x00,
+				// AuditEntryID
+				0xff, 0xff, 0xff, 0xff,
+				//TimeoutHint
+				0x00, 0x00, 0x00, 0x00,
+				// AdditionalHeader
+				0x00, 0x00, 0x00,
+				// MaxAge
+				0x00, 0x00, 0x00, 0x00, 0x00, 0x40, 0x7f, 0x40,
+				// TimestampToReturn
+				0x02, 0x00, 0x00, 0x00,
+				// NodesToRead
+				0x01, 0x00, 0x00, 0x00, 0x01, 0x00, 0xd0, 0x08,
+				0x0d, 0x00, 0x00, 0x00, 0xff, 0xff, 0xff, 0xff,
+				0x00, 0x00, 0xff, 0xff, 0xff, 0xff,
+			},
+		},
 	}
 	codectest.Run(t, cases, func(b []byte) (codectest.S, error) {
 		v, err := DecodeReadRequest(b)
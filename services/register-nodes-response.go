@@ -0,0 +1,123 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package services
+
+import (
+	"fmt"
+
+	"github.com/wmnsk/gopcua/datatypes"
+)
+
+// RegisterNodesResponse returns the NodeIDs to be used instead of the ones
+// in the RegisterNodesRequest, in the same order.
+//
+// Specification: Part 4, 5.8.5.2
+type RegisterNodesResponse struct {
+	TypeID *datatypes.ExpandedNodeID
+	*ResponseHeader
+	RegisteredNodeIDs *datatypes.NodeIDArray
+}
+
+// NewRegisterNodesResponse creates a new RegisterNodesResponse.
+func NewRegisterNodesResponse(resHeader *ResponseHeader, nodes ...*datatypes.NodeID) *RegisterNodesResponse {
+	return &RegisterNodesResponse{
+		TypeID:            datatypes.NewFourByteExpandedNodeID(0, ServiceTypeRegisterNodesResponse),
+		ResponseHeader:    resHeader,
+		RegisteredNodeIDs: datatypes.NewNodeIDArray(nodes),
+	}
+}
+
+// DecodeRegisterNodesResponse decodes given bytes into RegisterNodesResponse.
+func DecodeRegisterNodesResponse(b []byte) (*RegisterNodesResponse, error) {
+	r := &RegisterNodesResponse{}
+	if err := r.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// DecodeFromBytes decodes given bytes into RegisterNodesResponse.
+func (r *RegisterNodesResponse) DecodeFromBytes(b []byte) error {
+	var offset = 0
+	r.TypeID = &datatypes.ExpandedNodeID{}
+	if err := r.TypeID.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += r.TypeID.Len()
+
+	r.ResponseHeader = &ResponseHeader{}
+	if err := r.ResponseHeader.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += r.ResponseHeader.Len() - len(r.ResponseHeader.Payload)
+
+	r.RegisteredNodeIDs = &datatypes.NodeIDArray{}
+	return r.RegisteredNodeIDs.DecodeFromBytes(b[offset:])
+}
+
+// Serialize serializes RegisterNodesResponse into bytes.
+func (r *RegisterNodesResponse) Serialize() ([]byte, error) {
+	b := make([]byte, r.Len())
+	if err := r.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes RegisterNodesResponse into bytes.
+func (r *RegisterNodesResponse) SerializeTo(b []byte) error {
+	var offset = 0
+	if r.TypeID != nil {
+		if err := r.TypeID.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += r.TypeID.Len()
+	}
+
+	if r.ResponseHeader != nil {
+		if err := r.ResponseHeader.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += r.ResponseHeader.Len()
+	}
+
+	if r.RegisteredNodeIDs != nil {
+		return r.RegisteredNodeIDs.SerializeTo(b[offset:])
+	}
+
+	return nil
+}
+
+// Len returns the actual length of RegisterNodesResponse in int.
+func (r *RegisterNodesResponse) Len() int {
+	var l = 0
+	if r.TypeID != nil {
+		l += r.TypeID.Len()
+	}
+	if r.ResponseHeader != nil {
+		l += r.ResponseHeader.Len()
+	}
+	if r.RegisteredNodeIDs != nil {
+		l += r.RegisteredNodeIDs.Len()
+	}
+
+	return l
+}
+
+// String returns RegisterNodesResponse in string.
+func (r *RegisterNodesResponse) String() string {
+	return fmt.Sprintf("%v, %v, %v",
+		r.TypeID,
+		r.ResponseHeader,
+		r.RegisteredNodeIDs,
+	)
+}
+
+// ServiceType returns type of Service in uint16.
+func (r *RegisterNodesResponse) ServiceType() uint16 {
+	return ServiceTypeRegisterNodesResponse
+}
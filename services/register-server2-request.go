@@ -0,0 +1,154 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package services
+
+import (
+	"fmt"
+
+	"github.com/wmnsk/gopcua/datatypes"
+)
+
+// RegisterServer2Request is used by a Server to register itself to a Discovery Server,
+// with the DiscoveryConfigurations, e.g. MdnsDiscoveryConfiguration for the Local
+// Discovery Server with the multicast extension.
+//
+// The Server should call it periodically as the Discovery Server removes the Servers
+// which have not registered again for a while.
+//
+// Specification: Part 4, 5.4.6
+type RegisterServer2Request struct {
+	TypeID *datatypes.ExpandedNodeID
+	*RequestHeader
+	Server                 *datatypes.RegisteredServer
+	DiscoveryConfiguration *datatypes.ExtensionObjectArray
+}
+
+// NewRegisterServer2Request creates a new RegisterServer2Request.
+func NewRegisterServer2Request(reqHeader *RequestHeader, server *datatypes.RegisteredServer, configs ...datatypes.ExtensionObjectValue) *RegisterServer2Request {
+	var objs []*datatypes.ExtensionObject
+	for _, c := range configs {
+		objs = append(objs, datatypes.NewExtensionObject(datatypes.ExtensionObjectBinary, c))
+	}
+
+	return &RegisterServer2Request{
+		TypeID:                 datatypes.NewFourByteExpandedNodeID(0, ServiceTypeRegisterServer2Request),
+		RequestHeader:          reqHeader,
+		Server:                 server,
+		DiscoveryConfiguration: datatypes.NewExtensionObjectArray(objs),
+	}
+}
+
+// DecodeRegisterServer2Request decodes given bytes into RegisterServer2Request.
+func DecodeRegisterServer2Request(b []byte) (*RegisterServer2Request, error) {
+	r := &RegisterServer2Request{}
+	if err := r.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// DecodeFromBytes decodes given bytes into RegisterServer2Request.
+func (r *RegisterServer2Request) DecodeFromBytes(b []byte) error {
+	var offset = 0
+	r.TypeID = &datatypes.ExpandedNodeID{}
+	if err := r.TypeID.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += r.TypeID.Len()
+
+	r.RequestHeader = &RequestHeader{}
+	if err := r.RequestHeader.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += r.RequestHeader.Len() - len(r.RequestHeader.Payload)
+
+	r.Server = &datatypes.RegisteredServer{}
+	if err := r.Server.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += r.Server.Len()
+
+	r.DiscoveryConfiguration = &datatypes.ExtensionObjectArray{}
+	return r.DiscoveryConfiguration.DecodeFromBytes(b[offset:])
+}
+
+// Serialize serializes RegisterServer2Request into bytes.
+func (r *RegisterServer2Request) Serialize() ([]byte, error) {
+	b := make([]byte, r.Len())
+	if err := r.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes RegisterServer2Request into bytes.
+func (r *RegisterServer2Request) SerializeTo(b []byte) error {
+	var offset = 0
+	if r.TypeID != nil {
+		if err := r.TypeID.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += r.TypeID.Len()
+	}
+
+	if r.RequestHeader != nil {
+		if err := r.RequestHeader.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += r.RequestHeader.Len()
+	}
+
+	if r.Server != nil {
+		if err := r.Server.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += r.Server.Len()
+	}
+
+	if r.DiscoveryConfiguration != nil {
+		return r.DiscoveryConfiguration.SerializeTo(b[offset:])
+	}
+
+	return nil
+}
+
+// Len returns the actual length of RegisterServer2Request in int.
+func (r *RegisterServer2Request) Len() int {
+	l := 0
+	if r.TypeID != nil {
+		l += r.TypeID.Len()
+	}
+
+	if r.RequestHeader != nil {
+		l += r.RequestHeader.Len()
+	}
+
+	if r.Server != nil {
+		l += r.Server.Len()
+	}
+
+	if r.DiscoveryConfiguration != nil {
+		l += r.DiscoveryConfiguration.Len()
+	}
+
+	return l
+}
+
+// String returns RegisterServer2Request in string.
+func (r *RegisterServer2Request) String() string {
+	return fmt.Sprintf("%v, %v, %v, %v",
+		r.TypeID,
+		r.RequestHeader,
+		r.Server,
+		r.DiscoveryConfiguration,
+	)
+}
+
+// ServiceType returns type of Service in uint16.
+func (r *RegisterServer2Request) ServiceType() uint16 {
+	return ServiceTypeRegisterServer2Request
+}
@@ -0,0 +1,129 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/wmnsk/gopcua/datatypes"
+	"github.com/wmnsk/gopcua/utils/codectest"
+)
+
+func TestRegisterServer2Request(t *testing.T) {
+	header := []byte{
+		// AuthenticationToken
+		0x00, 0x00,
+		// Timestamp
+		0x00, 0x98, 0x67, 0xdd, 0xfd, 0x30, 0xd4, 0x01,
+		// RequestHandle
+		0x01, 0x00, 0x00, 0x00,
+		// ReturnDiagnostics
+		0x00, 0x00, 0x00, 0x00,
+		// AuditEntryID
+		0xff, 0xff, 0xff, 0xff,
+		// TimeoutHint
+		0x00, 0x00, 0x00, 0x00,
+		// AdditionalHeader
+		0x00, 0x00, 0x00,
+	}
+	server := []byte{
+		// ServerURI
+		0x07, 0x00, 0x00, 0x00,
+		0x75, 0x72, 0x6e, 0x3a, 0x73, 0x72, 0x76,
+		// ProductURI
+		0x08, 0x00, 0x00, 0x00,
+		0x75, 0x72, 0x6e, 0x3a, 0x70, 0x72, 0x6f, 0x64,
+		// ServerNames
+		0x01, 0x00, 0x00, 0x00,
+		0x03,
+		0x05, 0x00, 0x00, 0x00, 0x65, 0x6e, 0x2d, 0x55, 0x53,
+		0x03, 0x00, 0x00, 0x00, 0x73, 0x72, 0x76,
+		// ServerType
+		0x00, 0x00, 0x00, 0x00,
+		// GatewayServerURI
+		0xff, 0xff, 0xff, 0xff,
+		// DiscoveryURIs
+		0x01, 0x00, 0x00, 0x00,
+		0x0b, 0x00, 0x00, 0x00,
+		0x6f, 0x70, 0x63, 0x2e, 0x74, 0x63, 0x70, 0x3a, 0x2f, 0x2f, 0x61,
+		// SemaphoreFilePath
+		0xff, 0xff, 0xff, 0xff,
+		// IsOnline
+		0x01,
+	}
+	newRequest := func(configs ...datatypes.ExtensionObjectValue) *RegisterServer2Request {
+		return NewRegisterServer2Request(
+			NewRequestHeader(
+				datatypes.NewTwoByteNodeID(0),
+				time.Date(2018, time.August, 10, 23, 0, 0, 0, time.UTC),
+				1, 0, 0, "", NewNullAdditionalHeader(), nil,
+			),
+			datatypes.NewRegisteredServer("urn:srv", "urn:prod", "srv", AppTypeServer, []string{"opc.tcp://a"}, true),
+			configs...,
+		)
+	}
+	join := func(bs ...[]byte) []byte {
+		var b []byte
+		for _, x := range bs {
+			b = append(b, x...)
+		}
+		return b
+	}
+
+	cases := []codectest.Case{
+		{
+			Name:   "no-configuration",
+			Struct: newRequest(),
+			Bytes: join(
+				[]byte{0x01, 0x00, 0xb3, 0x2f}, header, server,
+				// DiscoveryConfiguration
+				[]byte{0x00, 0x00, 0x00, 0x00},
+			),
+		},
+		{
+			Name: "mdns-configurations",
+			Struct: newRequest(
+				datatypes.NewMdnsDiscoveryConfiguration("srv", "DA"),
+				datatypes.NewMdnsDiscoveryConfiguration("srv-2"),
+			),
+			Bytes: join(
+				[]byte{0x01, 0x00, 0xb3, 0x2f}, header, server,
+				[]byte{
+					// DiscoveryConfiguration
+					0x02, 0x00, 0x00, 0x00,
+					// TypeID, EncodingMask and Length
+					0x01, 0x00, 0x65, 0x32, 0x01, 0x11, 0x00, 0x00, 0x00,
+					// MdnsServerName
+					0x03, 0x00, 0x00, 0x00, 0x73, 0x72, 0x76,
+					// ServerCapabilities
+					0x01, 0x00, 0x00, 0x00,
+					0x02, 0x00, 0x00, 0x00, 0x44, 0x41,
+					// TypeID, EncodingMask and Length
+					0x01, 0x00, 0x65, 0x32, 0x01, 0x0d, 0x00, 0x00, 0x00,
+					// MdnsServerName
+					0x05, 0x00, 0x00, 0x00, 0x73, 0x72, 0x76, 0x2d, 0x32,
+					// ServerCapabilities
+					0x00, 0x00, 0x00, 0x00,
+				},
+			),
+		},
+	}
+	codectest.Run(t, cases, func(b []byte) (codectest.S, error) {
+		v, err := DecodeRegisterServer2Request(b)
+		if err != nil {
+			return nil, err
+		}
+		v.Payload = nil
+		return v, nil
+	})
+
+	t.Run("service-id", func(t *testing.T) {
+		id := new(RegisterServer2Request).ServiceType()
+		if got, want := id, uint16(ServiceTypeRegisterServer2Request); got != want {
+			t.Fatalf("got %d want %d", got, want)
+		}
+	})
+}
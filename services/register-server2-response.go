@@ -0,0 +1,145 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package services
+
+import (
+	"fmt"
+
+	"github.com/wmnsk/gopcua/datatypes"
+)
+
+// RegisterServer2Response returns the StatusCodes for the DiscoveryConfigurations
+// in the RegisterServer2Request, in the same order.
+//
+// Specification: Part 4, 5.4.6
+type RegisterServer2Response struct {
+	TypeID *datatypes.ExpandedNodeID
+	*ResponseHeader
+	ConfigurationResults *datatypes.Uint32Array
+	DiagnosticInfos      *DiagnosticInfoArray
+}
+
+// NewRegisterServer2Response creates a new RegisterServer2Response.
+func NewRegisterServer2Response(resHeader *ResponseHeader, diags []*DiagnosticInfo, results ...uint32) *RegisterServer2Response {
+	return &RegisterServer2Response{
+		TypeID:               datatypes.NewFourByteExpandedNodeID(0, ServiceTypeRegisterServer2Response),
+		ResponseHeader:       resHeader,
+		ConfigurationResults: datatypes.NewUint32Array(results),
+		DiagnosticInfos:      NewDiagnosticInfoArray(diags),
+	}
+}
+
+// DecodeRegisterServer2Response decodes given bytes into RegisterServer2Response.
+func DecodeRegisterServer2Response(b []byte) (*RegisterServer2Response, error) {
+	r := &RegisterServer2Response{}
+	if err := r.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// DecodeFromBytes decodes given bytes into RegisterServer2Response.
+func (r *RegisterServer2Response) DecodeFromBytes(b []byte) error {
+	var offset = 0
+	r.TypeID = &datatypes.ExpandedNodeID{}
+	if err := r.TypeID.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += r.TypeID.Len()
+
+	r.ResponseHeader = &ResponseHeader{}
+	if err := r.ResponseHeader.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += r.ResponseHeader.Len() - len(r.ResponseHeader.Payload)
+
+	r.ConfigurationResults = &datatypes.Uint32Array{}
+	if err := r.ConfigurationResults.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += r.ConfigurationResults.Len()
+
+	r.DiagnosticInfos = &DiagnosticInfoArray{}
+	return r.DiagnosticInfos.DecodeFromBytes(b[offset:])
+}
+
+// Serialize serializes RegisterServer2Response into bytes.
+func (r *RegisterServer2Response) Serialize() ([]byte, error) {
+	b := make([]byte, r.Len())
+	if err := r.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes RegisterServer2Response into bytes.
+func (r *RegisterServer2Response) SerializeTo(b []byte) error {
+	var offset = 0
+	if r.TypeID != nil {
+		if err := r.TypeID.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += r.TypeID.Len()
+	}
+
+	if r.ResponseHeader != nil {
+		if err := r.ResponseHeader.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += r.ResponseHeader.Len()
+	}
+
+	if r.ConfigurationResults != nil {
+		if err := r.ConfigurationResults.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += r.ConfigurationResults.Len()
+	}
+
+	if r.DiagnosticInfos != nil {
+		return r.DiagnosticInfos.SerializeTo(b[offset:])
+	}
+
+	return nil
+}
+
+// Len returns the actual length of RegisterServer2Response in int.
+func (r *RegisterServer2Response) Len() int {
+	l := 0
+	if r.TypeID != nil {
+		l += r.TypeID.Len()
+	}
+
+	if r.ResponseHeader != nil {
+		l += r.ResponseHeader.Len()
+	}
+
+	if r.ConfigurationResults != nil {
+		l += r.ConfigurationResults.Len()
+	}
+
+	if r.DiagnosticInfos != nil {
+		l += r.DiagnosticInfos.Len()
+	}
+
+	return l
+}
+
+// String returns RegisterServer2Response in string.
+func (r *RegisterServer2Response) String() string {
+	return fmt.Sprintf("%v, %v, %v, %v",
+		r.TypeID,
+		r.ResponseHeader,
+		r.ConfigurationResults,
+		r.DiagnosticInfos,
+	)
+}
+
+// ServiceType returns type of Service in uint16.
+func (r *RegisterServer2Response) ServiceType() uint16 {
+	return ServiceTypeRegisterServer2Response
+}
@@ -10,7 +10,16 @@ import (
 	"time"
 
 	"github.com/wmnsk/gopcua/datatypes"
-	"github.com/wmnsk/gopcua/utils"
+)
+
+// ReturnDiagnostics masks in RequestHeader, which ask for the DiagnosticInfos of the service
+// and the operations in the response. Each bit is set by the SetDiag methods of RequestHeader.
+//
+// Specification: Part 4, 7.28
+const (
+	ReturnDiagnosticsServiceLevel   = 0x001f
+	ReturnDiagnosticsOperationLevel = 0x03e0
+	ReturnDiagnosticsAll            = 0x03ff
 )
 
 // RequestHeader represents a Request Header in each services.
@@ -41,6 +50,14 @@ func NewRequestHeader(authToken *datatypes.NodeID, timestamp time.Time, handle,
 	}
 }
 
+// Header returns the RequestHeader itself.
+//
+// As RequestHeader is embedded in each request, this can be used to get
+// the RequestHeader of a Service without knowing its type.
+func (r *RequestHeader) Header() *RequestHeader {
+	return r
+}
+
 // DecodeRequestHeader decodes given bytes into RequestHeader.
 func DecodeRequestHeader(b []byte) (*RequestHeader, error) {
 	r := &RequestHeader{}
@@ -64,7 +81,7 @@ func (r *RequestHeader) DecodeFromBytes(b []byte) error {
 	}
 	offset += r.AuthenticationToken.Len()
 
-	r.Timestamp = utils.DecodeTimestamp(b[offset : offset+8])
+	r.Timestamp = datatypes.FromDateTime(int64(binary.LittleEndian.Uint64(b[offset : offset+8])))
 	offset += 8
 
 	r.RequestHandle = binary.LittleEndian.Uint32(b[offset : offset+4])
@@ -111,7 +128,7 @@ func (r *RequestHeader) SerializeTo(b []byte) error {
 	}
 	offset += r.AuthenticationToken.Len()
 
-	utils.EncodeTimestamp(b[offset:offset+8], r.Timestamp)
+	binary.LittleEndian.PutUint64(b[offset:offset+8], uint64(datatypes.ToDateTime(r.Timestamp)))
 	offset += 8
 	binary.LittleEndian.PutUint32(b[offset:offset+4], r.RequestHandle)
 	offset += 4
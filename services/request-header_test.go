@@ -61,3 +61,17 @@ func TestRequestHeader(t *testing.T) {
 		return DecodeRequestHeader(b)
 	})
 }
+
+func TestReturnDiagnostics(t *testing.T) {
+	r := &RequestHeader{}
+	r.SetDiagAll()
+	if r.ReturnDiagnostics != ReturnDiagnosticsAll {
+		t.Errorf("got 0x%x want 0x%x", r.ReturnDiagnostics, ReturnDiagnosticsAll)
+	}
+	if ReturnDiagnosticsServiceLevel|ReturnDiagnosticsOperationLevel != ReturnDiagnosticsAll {
+		t.Error("service and operation levels should make up all")
+	}
+	if ReturnDiagnosticsServiceLevel&ReturnDiagnosticsOperationLevel != 0 {
+		t.Error("service and operation levels should not overlap")
+	}
+}
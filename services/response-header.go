@@ -10,7 +10,6 @@ import (
 	"time"
 
 	"github.com/wmnsk/gopcua/datatypes"
-	"github.com/wmnsk/gopcua/utils"
 )
 
 // ResponseHeader represents a Response Header in each services.
@@ -44,6 +43,14 @@ func NewResponseHeader(timestamp time.Time, handle, code uint32, diag *Diagnosti
 	return r
 }
 
+// Header returns the ResponseHeader itself.
+//
+// As ResponseHeader is embedded in each response, this can be used to get
+// the ResponseHeader of a Service without knowing its type.
+func (r *ResponseHeader) Header() *ResponseHeader {
+	return r
+}
+
 // DecodeResponseHeader decodes given bytes into ResponseHeader.
 func DecodeResponseHeader(b []byte) (*ResponseHeader, error) {
 	r := &ResponseHeader{}
@@ -58,7 +65,7 @@ func DecodeResponseHeader(b []byte) (*ResponseHeader, error) {
 func (r *ResponseHeader) DecodeFromBytes(b []byte) error {
 	var offset = 0
 
-	r.Timestamp = utils.DecodeTimestamp(b[offset : offset+8])
+	r.Timestamp = datatypes.FromDateTime(int64(binary.LittleEndian.Uint64(b[offset : offset+8])))
 	offset += 8
 	r.RequestHandle = binary.LittleEndian.Uint32(b[offset : offset+4])
 	offset += 4
@@ -101,7 +108,7 @@ func (r *ResponseHeader) Serialize() ([]byte, error) {
 // SerializeTo serializes ResponseHeader into bytes.
 func (r *ResponseHeader) SerializeTo(b []byte) error {
 	var offset = 0
-	utils.EncodeTimestamp(b[offset:offset+8], r.Timestamp)
+	binary.LittleEndian.PutUint64(b[offset:offset+8], uint64(datatypes.ToDateTime(r.Timestamp)))
 	offset += 8
 	binary.LittleEndian.PutUint32(b[offset:offset+4], r.RequestHandle)
 	offset += 4
@@ -145,3 +152,16 @@ func (r *ResponseHeader) String() string {
 		r.Payload,
 	)
 }
+
+// ResolveDiagnosticInfo resolves the indices in the DiagnosticInfo given, which is
+// ServiceDiagnostics or one of the DiagnosticInfos in the response, with the StringTable.
+func (r *ResponseHeader) ResolveDiagnosticInfo(d *DiagnosticInfo) *ResolvedDiagnosticInfo {
+	var table []string
+	if r.StringTable != nil {
+		for _, s := range r.StringTable.Strings {
+			table = append(table, s.Get())
+		}
+	}
+
+	return d.Resolve(table)
+}
@@ -0,0 +1,103 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package services
+
+import (
+	"fmt"
+
+	"github.com/wmnsk/gopcua/datatypes"
+)
+
+// ServiceFault is returned instead of the response when the server rejects
+// the request as a whole, e.g. with Bad_TooManyOperations. The reason is in
+// the ServiceResult and the ServiceDiagnostics of the ResponseHeader.
+//
+// Specification: Part 4, 7.30
+type ServiceFault struct {
+	TypeID *datatypes.ExpandedNodeID
+	*ResponseHeader
+}
+
+// NewServiceFault creates a new ServiceFault.
+func NewServiceFault(resHeader *ResponseHeader) *ServiceFault {
+	return &ServiceFault{
+		TypeID:         datatypes.NewFourByteExpandedNodeID(0, ServiceTypeServiceFault),
+		ResponseHeader: resHeader,
+	}
+}
+
+// DecodeServiceFault decodes given bytes into ServiceFault.
+func DecodeServiceFault(b []byte) (*ServiceFault, error) {
+	s := &ServiceFault{}
+	if err := s.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// DecodeFromBytes decodes given bytes into ServiceFault.
+func (s *ServiceFault) DecodeFromBytes(b []byte) error {
+	s.TypeID = &datatypes.ExpandedNodeID{}
+	if err := s.TypeID.DecodeFromBytes(b); err != nil {
+		return err
+	}
+
+	s.ResponseHeader = &ResponseHeader{}
+	return s.ResponseHeader.DecodeFromBytes(b[s.TypeID.Len():])
+}
+
+// Serialize serializes ServiceFault into bytes.
+func (s *ServiceFault) Serialize() ([]byte, error) {
+	b := make([]byte, s.Len())
+	if err := s.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes ServiceFault into bytes.
+func (s *ServiceFault) SerializeTo(b []byte) error {
+	var offset = 0
+	if s.TypeID != nil {
+		if err := s.TypeID.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += s.TypeID.Len()
+	}
+
+	if s.ResponseHeader != nil {
+		return s.ResponseHeader.SerializeTo(b[offset:])
+	}
+
+	return nil
+}
+
+// Len returns the actual length of ServiceFault in int.
+func (s *ServiceFault) Len() int {
+	var l = 0
+	if s.TypeID != nil {
+		l += s.TypeID.Len()
+	}
+	if s.ResponseHeader != nil {
+		l += s.ResponseHeader.Len()
+	}
+
+	return l
+}
+
+// String returns ServiceFault in string.
+func (s *ServiceFault) String() string {
+	return fmt.Sprintf("%v, %v",
+		s.TypeID,
+		s.ResponseHeader,
+	)
+}
+
+// ServiceType returns type of Service in uint16.
+func (s *ServiceFault) ServiceType() uint16 {
+	return ServiceTypeServiceFault
+}
@@ -0,0 +1,84 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/wmnsk/gopcua/utils/codectest"
+)
+
+func TestServiceFault(t *testing.T) {
+	cases := []codectest.Case{
+		{
+			Name: "normal",
+			Struct: NewServiceFault(
+				NewResponseHeader(
+					time.Date(2018, time.August, 10, 23, 0, 0, 0, time.UTC),
+					1, 0x80100000, NewNullDiagnosticInfo(), []string{}, NewNullAdditionalHeader(), nil,
+				),
+			),
+			Bytes: []byte{
+				// TypeID
+				0x01, 0x00, 0x8d, 0x01,
+				// Timestamp
+				0x00, 0x98, 0x67, 0xdd, 0xfd, 0x30, 0xd4, 0x01,
+				// RequestHandle
+				0x01, 0x00, 0x00, 0x00,
+				// ServiceResult
+				0x00, 0x00, 0x10, 0x80,
+				// ServiceDiagnostics
+				0x00,
+				// StringTable
+				0x00, 0x00, 0x00, 0x00,
+				// AdditionalHeader
+				0x00, 0x00, 0x00,
+			},
+		},
+		{
+			Name: "with-diagnostics",
+			Struct: NewServiceFault(
+				NewResponseHeader(
+					time.Date(2018, time.August, 10, 23, 0, 0, 0, time.UTC),
+					1, 0x80100000,
+					NewDiagnosticInfo(false, false, true, false, false, false, false, 0, 0, 0, 0, nil, 0, nil),
+					[]string{"foo"}, NewNullAdditionalHeader(), nil,
+				),
+			),
+			Bytes: []byte{
+				// TypeID
+				0x01, 0x00, 0x8d, 0x01,
+				// Timestamp
+				0x00, 0x98, 0x67, 0xdd, 0xfd, 0x30, 0xd4, 0x01,
+				// RequestHandle
+				0x01, 0x00, 0x00, 0x00,
+				// ServiceResult
+				0x00, 0x00, 0x10, 0x80,
+				// ServiceDiagnostics
+				0x04, 0x00, 0x00, 0x00, 0x00,
+				// StringTable
+				0x01, 0x00, 0x00, 0x00, 0x03, 0x00, 0x00, 0x00, 0x66, 0x6f, 0x6f,
+				// AdditionalHeader
+				0x00, 0x00, 0x00,
+			},
+		},
+	}
+	codectest.Run(t, cases, func(b []byte) (codectest.S, error) {
+		v, err := DecodeServiceFault(b)
+		if err != nil {
+			return nil, err
+		}
+		v.Payload = nil
+		return v, nil
+	})
+
+	t.Run("service-id", func(t *testing.T) {
+		id := new(ServiceFault).ServiceType()
+		if got, want := id, uint16(ServiceTypeServiceFault); got != want {
+			t.Fatalf("got %d want %d", got, want)
+		}
+	})
+}
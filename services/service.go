@@ -11,29 +11,69 @@ import (
 
 // ServiceType definitions.
 const (
-	ServiceTypeFindServersRequest           uint16 = 422
-	ServiceTypeFindServersResponse          uint16 = 425
-	ServiceTypeGetEndpointsRequest          uint16 = 428
-	ServiceTypeGetEndpointsResponse         uint16 = 431
-	ServiceTypeOpenSecureChannelRequest     uint16 = 446
-	ServiceTypeOpenSecureChannelResponse    uint16 = 449
-	ServiceTypeCloseSecureChannelRequest    uint16 = 452
-	ServiceTypeCloseSecureChannelResponse   uint16 = 455
-	ServiceTypeCreateSessionRequest         uint16 = 461
-	ServiceTypeCreateSessionResponse        uint16 = 464
-	ServiceTypeActivateSessionRequest       uint16 = 467
-	ServiceTypeActivateSessionResponse      uint16 = 470
-	ServiceTypeCloseSessionRequest          uint16 = 473
-	ServiceTypeCloseSessionResponse         uint16 = 476
-	ServiceTypeCancelRequest                uint16 = 479
-	ServiceTypeCancelResponse               uint16 = 482
-	ServiceTypeReadRequest                  uint16 = 631
-	ServiceTypeReadResponse                 uint16 = 634
-	ServiceTypeWriteRequest                 uint16 = 673
-	ServiceTypeWriteResponse                uint16 = 676
-	ServiceTypeCreateSubscriptionRequest    uint16 = 787
-	ServiceTypeFindServersOnNetworkRequest  uint16 = 12208
-	ServiceTypeFindServersOnNetworkResponse uint16 = 12211
+	ServiceTypeServiceFault                          uint16 = 397
+	ServiceTypeFindServersRequest                    uint16 = 422
+	ServiceTypeFindServersResponse                   uint16 = 425
+	ServiceTypeGetEndpointsRequest                   uint16 = 428
+	ServiceTypeGetEndpointsResponse                  uint16 = 431
+	ServiceTypeOpenSecureChannelRequest              uint16 = 446
+	ServiceTypeOpenSecureChannelResponse             uint16 = 449
+	ServiceTypeCloseSecureChannelRequest             uint16 = 452
+	ServiceTypeCloseSecureChannelResponse            uint16 = 455
+	ServiceTypeCreateSessionRequest                  uint16 = 461
+	ServiceTypeCreateSessionResponse                 uint16 = 464
+	ServiceTypeActivateSessionRequest                uint16 = 467
+	ServiceTypeActivateSessionResponse               uint16 = 470
+	ServiceTypeCloseSessionRequest                   uint16 = 473
+	ServiceTypeCloseSessionResponse                  uint16 = 476
+	ServiceTypeCancelRequest                         uint16 = 479
+	ServiceTypeCancelResponse                        uint16 = 482
+	ServiceTypeAddNodesRequest                       uint16 = 488
+	ServiceTypeAddNodesResponse                      uint16 = 491
+	ServiceTypeAddReferencesRequest                  uint16 = 494
+	ServiceTypeAddReferencesResponse                 uint16 = 497
+	ServiceTypeDeleteNodesRequest                    uint16 = 500
+	ServiceTypeDeleteNodesResponse                   uint16 = 503
+	ServiceTypeDeleteReferencesRequest               uint16 = 506
+	ServiceTypeDeleteReferencesResponse              uint16 = 509
+	ServiceTypeBrowseRequest                         uint16 = 527
+	ServiceTypeBrowseResponse                        uint16 = 530
+	ServiceTypeBrowseNextRequest                     uint16 = 533
+	ServiceTypeBrowseNextResponse                    uint16 = 536
+	ServiceTypeTranslateBrowsePathsToNodeIdsRequest  uint16 = 554
+	ServiceTypeTranslateBrowsePathsToNodeIdsResponse uint16 = 557
+	ServiceTypeRegisterNodesRequest                  uint16 = 560
+	ServiceTypeRegisterNodesResponse                 uint16 = 563
+	ServiceTypeUnregisterNodesRequest                uint16 = 566
+	ServiceTypeUnregisterNodesResponse               uint16 = 569
+	ServiceTypeReadRequest                           uint16 = 631
+	ServiceTypeReadResponse                          uint16 = 634
+	ServiceTypeHistoryReadRequest                    uint16 = 664
+	ServiceTypeHistoryReadResponse                   uint16 = 667
+	ServiceTypeWriteRequest                          uint16 = 673
+	ServiceTypeWriteResponse                         uint16 = 676
+	ServiceTypeCallRequest                           uint16 = 712
+	ServiceTypeCallResponse                          uint16 = 715
+	ServiceTypeCreateMonitoredItemsRequest           uint16 = 751
+	ServiceTypeCreateMonitoredItemsResponse          uint16 = 754
+	ServiceTypeModifyMonitoredItemsRequest           uint16 = 763
+	ServiceTypeModifyMonitoredItemsResponse          uint16 = 766
+	ServiceTypeSetTriggeringRequest                  uint16 = 775
+	ServiceTypeSetTriggeringResponse                 uint16 = 778
+	ServiceTypeDeleteMonitoredItemsRequest           uint16 = 781
+	ServiceTypeDeleteMonitoredItemsResponse          uint16 = 784
+	ServiceTypeCreateSubscriptionRequest             uint16 = 787
+	ServiceTypeCreateSubscriptionResponse            uint16 = 790
+	ServiceTypePublishRequest                        uint16 = 826
+	ServiceTypePublishResponse                       uint16 = 829
+	ServiceTypeTransferSubscriptionsRequest          uint16 = 841
+	ServiceTypeTransferSubscriptionsResponse         uint16 = 844
+	ServiceTypeDeleteSubscriptionsRequest            uint16 = 847
+	ServiceTypeDeleteSubscriptionsResponse           uint16 = 850
+	ServiceTypeFindServersOnNetworkRequest           uint16 = 12208
+	ServiceTypeFindServersOnNetworkResponse          uint16 = 12209
+	ServiceTypeRegisterServer2Request                uint16 = 12211
+	ServiceTypeRegisterServer2Response               uint16 = 12212
 )
 
 // Service is an interface to handle any kind of OPC UA Services.
@@ -60,6 +100,8 @@ func Decode(b []byte) (Service, error) {
 
 	id := uint16(typeID.NodeID.IntID())
 	switch id {
+	case ServiceTypeServiceFault:
+		s = &ServiceFault{}
 	case ServiceTypeFindServersRequest:
 		s = &FindServersRequest{}
 	case ServiceTypeFindServersResponse:
@@ -92,20 +134,98 @@ func Decode(b []byte) (Service, error) {
 		s = &CancelRequest{}
 	case ServiceTypeCancelResponse:
 		s = &CancelResponse{}
+	case ServiceTypeAddNodesRequest:
+		s = &AddNodesRequest{}
+	case ServiceTypeAddNodesResponse:
+		s = &AddNodesResponse{}
+	case ServiceTypeAddReferencesRequest:
+		s = &AddReferencesRequest{}
+	case ServiceTypeAddReferencesResponse:
+		s = &AddReferencesResponse{}
+	case ServiceTypeDeleteNodesRequest:
+		s = &DeleteNodesRequest{}
+	case ServiceTypeDeleteNodesResponse:
+		s = &DeleteNodesResponse{}
+	case ServiceTypeDeleteReferencesRequest:
+		s = &DeleteReferencesRequest{}
+	case ServiceTypeDeleteReferencesResponse:
+		s = &DeleteReferencesResponse{}
+	case ServiceTypeBrowseRequest:
+		s = &BrowseRequest{}
+	case ServiceTypeBrowseResponse:
+		s = &BrowseResponse{}
+	case ServiceTypeBrowseNextRequest:
+		s = &BrowseNextRequest{}
+	case ServiceTypeBrowseNextResponse:
+		s = &BrowseNextResponse{}
+	case ServiceTypeTranslateBrowsePathsToNodeIdsRequest:
+		s = &TranslateBrowsePathsToNodeIdsRequest{}
+	case ServiceTypeTranslateBrowsePathsToNodeIdsResponse:
+		s = &TranslateBrowsePathsToNodeIdsResponse{}
+	case ServiceTypeRegisterNodesRequest:
+		s = &RegisterNodesRequest{}
+	case ServiceTypeRegisterNodesResponse:
+		s = &RegisterNodesResponse{}
+	case ServiceTypeUnregisterNodesRequest:
+		s = &UnregisterNodesRequest{}
+	case ServiceTypeUnregisterNodesResponse:
+		s = &UnregisterNodesResponse{}
 	case ServiceTypeReadRequest:
 		s = &ReadRequest{}
 	case ServiceTypeReadResponse:
 		s = &ReadResponse{}
+	case ServiceTypeHistoryReadRequest:
+		s = &HistoryReadRequest{}
+	case ServiceTypeHistoryReadResponse:
+		s = &HistoryReadResponse{}
 	case ServiceTypeWriteRequest:
 		s = &WriteRequest{}
 	case ServiceTypeWriteResponse:
 		s = &WriteResponse{}
+	case ServiceTypeCallRequest:
+		s = &CallRequest{}
+	case ServiceTypeCallResponse:
+		s = &CallResponse{}
+	case ServiceTypeCreateMonitoredItemsRequest:
+		s = &CreateMonitoredItemsRequest{}
+	case ServiceTypeCreateMonitoredItemsResponse:
+		s = &CreateMonitoredItemsResponse{}
+	case ServiceTypeModifyMonitoredItemsRequest:
+		s = &ModifyMonitoredItemsRequest{}
+	case ServiceTypeModifyMonitoredItemsResponse:
+		s = &ModifyMonitoredItemsResponse{}
+	case ServiceTypeSetTriggeringRequest:
+		s = &SetTriggeringRequest{}
+	case ServiceTypeSetTriggeringResponse:
+		s = &SetTriggeringResponse{}
+	case ServiceTypeDeleteMonitoredItemsRequest:
+		s = &DeleteMonitoredItemsRequest{}
+	case ServiceTypeDeleteMonitoredItemsResponse:
+		s = &DeleteMonitoredItemsResponse{}
 	case ServiceTypeCreateSubscriptionRequest:
 		s = &CreateSubscriptionRequest{}
+	case ServiceTypeCreateSubscriptionResponse:
+		s = &CreateSubscriptionResponse{}
+	case ServiceTypePublishRequest:
+		s = &PublishRequest{}
+	case ServiceTypePublishResponse:
+		s = &PublishResponse{}
+	case ServiceTypeTransferSubscriptionsRequest:
+		s = &TransferSubscriptionsRequest{}
+	case ServiceTypeTransferSubscriptionsResponse:
+		s = &TransferSubscriptionsResponse{}
+	case ServiceTypeDeleteSubscriptionsRequest:
+		s = &DeleteSubscriptionsRequest{}
+	case ServiceTypeDeleteSubscriptionsResponse:
+		s = &DeleteSubscriptionsResponse{}
 	case ServiceTypeFindServersOnNetworkRequest:
 		s = &FindServersOnNetworkRequest{}
 	case ServiceTypeFindServersOnNetworkResponse:
 		s = &FindServersOnNetworkResponse{}
+	case ServiceTypeRegisterServer2Request:
+		s = &RegisterServer2Request{}
+	case ServiceTypeRegisterServer2Response:
+		s = &RegisterServer2Response{}
 	default:
 		return nil, errors.NewErrUnsupported(id, "unsupported or not implemented yet.")
 	}
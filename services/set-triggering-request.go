@@ -0,0 +1,172 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package services
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/wmnsk/gopcua/datatypes"
+	"github.com/wmnsk/gopcua/errors"
+)
+
+// SetTriggeringRequest is used to create and delete the triggering links for a triggering item.
+// The MonitoredItems linked report their Notifications only when the triggering item reports.
+//
+// Specification: Part 4, 5.12.5
+type SetTriggeringRequest struct {
+	TypeID *datatypes.ExpandedNodeID
+	*RequestHeader
+
+	// The Server-assigned identifier for the Subscription that contains the triggering item
+	// and the items to report.
+	SubscriptionID uint32
+
+	// The Server-assigned identifier of the MonitoredItem used as the triggering item.
+	TriggeringItemID uint32
+
+	// The Server-assigned identifiers of the MonitoredItems to be linked to,
+	// and unlinked from the triggering item respectively.
+	LinksToAdd    *datatypes.Uint32Array
+	LinksToRemove *datatypes.Uint32Array
+}
+
+// NewSetTriggeringRequest creates a new SetTriggeringRequest.
+func NewSetTriggeringRequest(reqHeader *RequestHeader, subID, triggeringItemID uint32, linksToAdd, linksToRemove []uint32) *SetTriggeringRequest {
+	return &SetTriggeringRequest{
+		TypeID:           datatypes.NewFourByteExpandedNodeID(0, ServiceTypeSetTriggeringRequest),
+		RequestHeader:    reqHeader,
+		SubscriptionID:   subID,
+		TriggeringItemID: triggeringItemID,
+		LinksToAdd:       datatypes.NewUint32Array(linksToAdd),
+		LinksToRemove:    datatypes.NewUint32Array(linksToRemove),
+	}
+}
+
+// DecodeSetTriggeringRequest decodes given bytes into SetTriggeringRequest.
+func DecodeSetTriggeringRequest(b []byte) (*SetTriggeringRequest, error) {
+	s := &SetTriggeringRequest{}
+	if err := s.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// DecodeFromBytes decodes given bytes into SetTriggeringRequest.
+func (s *SetTriggeringRequest) DecodeFromBytes(b []byte) error {
+	offset := 0
+	s.TypeID = &datatypes.ExpandedNodeID{}
+	if err := s.TypeID.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += s.TypeID.Len()
+
+	s.RequestHeader = &RequestHeader{}
+	if err := s.RequestHeader.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += s.RequestHeader.Len() - len(s.RequestHeader.Payload)
+
+	if len(b[offset:]) < 8 {
+		return errors.NewErrTooShortToDecode(s, "should contain SubscriptionID and TriggeringItemID")
+	}
+	s.SubscriptionID = binary.LittleEndian.Uint32(b[offset : offset+4])
+	s.TriggeringItemID = binary.LittleEndian.Uint32(b[offset+4 : offset+8])
+	offset += 8
+
+	s.LinksToAdd = &datatypes.Uint32Array{}
+	if err := s.LinksToAdd.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += s.LinksToAdd.Len()
+
+	s.LinksToRemove = &datatypes.Uint32Array{}
+	return s.LinksToRemove.DecodeFromBytes(b[offset:])
+}
+
+// Serialize serializes SetTriggeringRequest into bytes.
+func (s *SetTriggeringRequest) Serialize() ([]byte, error) {
+	b := make([]byte, s.Len())
+	if err := s.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes SetTriggeringRequest into bytes.
+func (s *SetTriggeringRequest) SerializeTo(b []byte) error {
+	offset := 0
+	if s.TypeID != nil {
+		if err := s.TypeID.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += s.TypeID.Len()
+	}
+
+	if s.RequestHeader != nil {
+		if err := s.RequestHeader.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += s.RequestHeader.Len()
+	}
+
+	binary.LittleEndian.PutUint32(b[offset:offset+4], s.SubscriptionID)
+	binary.LittleEndian.PutUint32(b[offset+4:offset+8], s.TriggeringItemID)
+	offset += 8
+
+	if s.LinksToAdd != nil {
+		if err := s.LinksToAdd.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += s.LinksToAdd.Len()
+	}
+
+	if s.LinksToRemove != nil {
+		return s.LinksToRemove.SerializeTo(b[offset:])
+	}
+
+	return nil
+}
+
+// Len returns the actual length of SetTriggeringRequest in int.
+func (s *SetTriggeringRequest) Len() int {
+	l := 8
+	if s.TypeID != nil {
+		l += s.TypeID.Len()
+	}
+
+	if s.RequestHeader != nil {
+		l += s.RequestHeader.Len()
+	}
+
+	if s.LinksToAdd != nil {
+		l += s.LinksToAdd.Len()
+	}
+
+	if s.LinksToRemove != nil {
+		l += s.LinksToRemove.Len()
+	}
+
+	return l
+}
+
+// String returns SetTriggeringRequest in string.
+func (s *SetTriggeringRequest) String() string {
+	return fmt.Sprintf("%v, %v, %d, %d, %v, %v",
+		s.TypeID,
+		s.RequestHeader,
+		s.SubscriptionID,
+		s.TriggeringItemID,
+		s.LinksToAdd,
+		s.LinksToRemove,
+	)
+}
+
+// ServiceType returns type of Service in uint16.
+func (s *SetTriggeringRequest) ServiceType() uint16 {
+	return ServiceTypeSetTriggeringRequest
+}
@@ -0,0 +1,115 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/wmnsk/gopcua/datatypes"
+	"github.com/wmnsk/gopcua/utils/codectest"
+)
+
+func TestSetTriggeringRequest(t *testing.T) {
+	cases := []codectest.Case{
+		{
+			Name: "normal",
+			Struct: NewSetTriggeringRequest(
+				NewRequestHeader(
+					datatypes.NewOpaqueNodeID(0x00, []byte{
+						0x08, 0x22, 0x87, 0x62, 0xba, 0x81, 0xe1, 0x11,
+						0xa6, 0x43, 0xf8, 0x77, 0x7b, 0xc6, 0x2f, 0xc8,
+					}),
+					time.Date(2018, time.August, 10, 23, 0, 0, 0, time.UTC),
+					1, 0, 0, "", NewNullAdditionalHeader(), nil,
+				),
+				1, 2, []uint32{3, 4}, []uint32{5},
+			),
+			Bytes: []byte{
+				// TypeID
+				0x01, 0x00, 0x07, 0x03,
+				// AuthenticationToken
+				0x05, 0x00, 0x00, 0x10, 0x00, 0x00, 0x00, 0x08,
+				0x22, 0x87, 0x62, 0xba, 0x81, 0xe1, 0x11, 0xa6,
+				0x43, 0xf8, 0x77, 0x7b, 0xc6, 0x2f, 0xc8,
+				// Timestamp
+				0x00, 0x98, 0x67, 0xdd, 0xfd, 0x30, 0xd4, 0x01,
+				// RequestHandle
+				0x01, 0x00, 0x00, 0x00,
+				// ReturnDiagnostics
+				0x00, 0x00, 0x00, 0x00,
+				// AuditEntryID
+				0xff, 0xff, 0xff, 0xff,
+				// TimeoutHint
+				0x00, 0x00, 0x00, 0x00,
+				// AdditionalHeader
+				0x00, 0x00, 0x00,
+				// SubscriptionID
+				0x01, 0x00, 0x00, 0x00,
+				// TriggeringItemID
+				0x02, 0x00, 0x00, 0x00,
+				// LinksToAdd
+				0x02, 0x00, 0x00, 0x00,
+				0x03, 0x00, 0x00, 0x00, 0x04, 0x00, 0x00, 0x00,
+				// LinksToRemove
+				0x01, 0x00, 0x00, 0x00,
+				0x05, 0x00, 0x00, 0x00,
+			},
+		},
+		{
+			Name: "no-links-to-remove",
+			Struct: NewSetTriggeringRequest(
+				NewRequestHeader(
+					datatypes.NewTwoByteNodeID(0),
+					time.Date(2018, time.August, 10, 23, 0, 0, 0, time.UTC),
+					1, 0, 0, "", NewNullAdditionalHeader(), nil,
+				),
+				1, 2, []uint32{3}, nil,
+			),
+			Bytes: []byte{
+				// TypeID
+				0x01, 0x00, 0x07, 0x03,
+				// AuthenticationToken
+				0x00, 0x00,
+				// Timestamp
+				0x00, 0x98, 0x67, 0xdd, 0xfd, 0x30, 0xd4, 0x01,
+				// RequestHandle
+				0x01, 0x00, 0x00, 0x00,
+				// ReturnDiagnostics
+				0x00, 0x00, 0x00, 0x00,
+				// AuditEntryID
+				0xff, 0xff, 0xff, 0xff,
+				// TimeoutHint
+				0x00, 0x00, 0x00, 0x00,
+				// AdditionalHeader
+				0x00, 0x00, 0x00,
+				// SubscriptionID
+				0x01, 0x00, 0x00, 0x00,
+				// TriggeringItemID
+				0x02, 0x00, 0x00, 0x00,
+				// LinksToAdd
+				0x01, 0x00, 0x00, 0x00,
+				0x03, 0x00, 0x00, 0x00,
+				// LinksToRemove
+				0x00, 0x00, 0x00, 0x00,
+			},
+		},
+	}
+	codectest.Run(t, cases, func(b []byte) (codectest.S, error) {
+		v, err := DecodeSetTriggeringRequest(b)
+		if err != nil {
+			return nil, err
+		}
+		v.Payload = nil
+		return v, nil
+	})
+
+	t.Run("service-id", func(t *testing.T) {
+		id := new(SetTriggeringRequest).ServiceType()
+		if got, want := id, uint16(ServiceTypeSetTriggeringRequest); got != want {
+			t.Fatalf("got %d want %d", got, want)
+		}
+	})
+}
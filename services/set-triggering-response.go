@@ -0,0 +1,185 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package services
+
+import (
+	"fmt"
+
+	"github.com/wmnsk/gopcua/datatypes"
+)
+
+// SetTriggeringResponse returns the StatusCodes for the links to add and to remove
+// in the SetTriggeringRequest, in the same order.
+//
+// Specification: Part 4, 5.12.5
+type SetTriggeringResponse struct {
+	TypeID *datatypes.ExpandedNodeID
+	*ResponseHeader
+	AddResults            *datatypes.Uint32Array
+	AddDiagnosticInfos    *DiagnosticInfoArray
+	RemoveResults         *datatypes.Uint32Array
+	RemoveDiagnosticInfos *DiagnosticInfoArray
+}
+
+// NewSetTriggeringResponse creates a new SetTriggeringResponse.
+func NewSetTriggeringResponse(resHeader *ResponseHeader, addResults, removeResults []uint32, addDiags, removeDiags []*DiagnosticInfo) *SetTriggeringResponse {
+	return &SetTriggeringResponse{
+		TypeID:                datatypes.NewFourByteExpandedNodeID(0, ServiceTypeSetTriggeringResponse),
+		ResponseHeader:        resHeader,
+		AddResults:            datatypes.NewUint32Array(addResults),
+		AddDiagnosticInfos:    NewDiagnosticInfoArray(addDiags),
+		RemoveResults:         datatypes.NewUint32Array(removeResults),
+		RemoveDiagnosticInfos: NewDiagnosticInfoArray(removeDiags),
+	}
+}
+
+// DecodeSetTriggeringResponse decodes given bytes into SetTriggeringResponse.
+func DecodeSetTriggeringResponse(b []byte) (*SetTriggeringResponse, error) {
+	s := &SetTriggeringResponse{}
+	if err := s.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// DecodeFromBytes decodes given bytes into SetTriggeringResponse.
+func (s *SetTriggeringResponse) DecodeFromBytes(b []byte) error {
+	var offset = 0
+	s.TypeID = &datatypes.ExpandedNodeID{}
+	if err := s.TypeID.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += s.TypeID.Len()
+
+	s.ResponseHeader = &ResponseHeader{}
+	if err := s.ResponseHeader.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += s.ResponseHeader.Len() - len(s.ResponseHeader.Payload)
+
+	s.AddResults = &datatypes.Uint32Array{}
+	if err := s.AddResults.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += s.AddResults.Len()
+
+	s.AddDiagnosticInfos = &DiagnosticInfoArray{}
+	if err := s.AddDiagnosticInfos.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += s.AddDiagnosticInfos.Len()
+
+	s.RemoveResults = &datatypes.Uint32Array{}
+	if err := s.RemoveResults.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += s.RemoveResults.Len()
+
+	s.RemoveDiagnosticInfos = &DiagnosticInfoArray{}
+	return s.RemoveDiagnosticInfos.DecodeFromBytes(b[offset:])
+}
+
+// Serialize serializes SetTriggeringResponse into bytes.
+func (s *SetTriggeringResponse) Serialize() ([]byte, error) {
+	b := make([]byte, s.Len())
+	if err := s.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes SetTriggeringResponse into bytes.
+func (s *SetTriggeringResponse) SerializeTo(b []byte) error {
+	var offset = 0
+	if s.TypeID != nil {
+		if err := s.TypeID.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += s.TypeID.Len()
+	}
+
+	if s.ResponseHeader != nil {
+		if err := s.ResponseHeader.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += s.ResponseHeader.Len()
+	}
+
+	if s.AddResults != nil {
+		if err := s.AddResults.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += s.AddResults.Len()
+	}
+
+	if s.AddDiagnosticInfos != nil {
+		if err := s.AddDiagnosticInfos.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += s.AddDiagnosticInfos.Len()
+	}
+
+	if s.RemoveResults != nil {
+		if err := s.RemoveResults.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += s.RemoveResults.Len()
+	}
+
+	if s.RemoveDiagnosticInfos != nil {
+		return s.RemoveDiagnosticInfos.SerializeTo(b[offset:])
+	}
+
+	return nil
+}
+
+// Len returns the actual length of SetTriggeringResponse in int.
+func (s *SetTriggeringResponse) Len() int {
+	l := 0
+	if s.TypeID != nil {
+		l += s.TypeID.Len()
+	}
+
+	if s.ResponseHeader != nil {
+		l += s.ResponseHeader.Len()
+	}
+
+	if s.AddResults != nil {
+		l += s.AddResults.Len()
+	}
+
+	if s.AddDiagnosticInfos != nil {
+		l += s.AddDiagnosticInfos.Len()
+	}
+
+	if s.RemoveResults != nil {
+		l += s.RemoveResults.Len()
+	}
+
+	if s.RemoveDiagnosticInfos != nil {
+		l += s.RemoveDiagnosticInfos.Len()
+	}
+
+	return l
+}
+
+// String returns SetTriggeringResponse in string.
+func (s *SetTriggeringResponse) String() string {
+	return fmt.Sprintf("%v, %v, %v, %v, %v, %v",
+		s.TypeID,
+		s.ResponseHeader,
+		s.AddResults,
+		s.AddDiagnosticInfos,
+		s.RemoveResults,
+		s.RemoveDiagnosticInfos,
+	)
+}
+
+// ServiceType returns type of Service in uint16.
+func (s *SetTriggeringResponse) ServiceType() uint16 {
+	return ServiceTypeSetTriggeringResponse
+}
@@ -0,0 +1,145 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package services
+
+import (
+	"fmt"
+
+	"github.com/wmnsk/gopcua/datatypes"
+)
+
+// TransferSubscriptionsRequest is used to transfer the Subscriptions and their MonitoredItems
+// from one Session to another, e.g. when the Client creates a new Session after the old one is lost.
+//
+// If SendInitialValues is true, the Server sends the current values of all the MonitoredItems
+// in the Subscriptions transferred.
+//
+// Specification: Part 4, 5.13.7
+type TransferSubscriptionsRequest struct {
+	TypeID *datatypes.ExpandedNodeID
+	*RequestHeader
+	SubscriptionIDs   *datatypes.Uint32Array
+	SendInitialValues *datatypes.Boolean
+}
+
+// NewTransferSubscriptionsRequest creates a new TransferSubscriptionsRequest.
+func NewTransferSubscriptionsRequest(reqHeader *RequestHeader, initial bool, subIDs ...uint32) *TransferSubscriptionsRequest {
+	return &TransferSubscriptionsRequest{
+		TypeID:            datatypes.NewFourByteExpandedNodeID(0, ServiceTypeTransferSubscriptionsRequest),
+		RequestHeader:     reqHeader,
+		SubscriptionIDs:   datatypes.NewUint32Array(subIDs),
+		SendInitialValues: datatypes.NewBoolean(initial),
+	}
+}
+
+// DecodeTransferSubscriptionsRequest decodes given bytes into TransferSubscriptionsRequest.
+func DecodeTransferSubscriptionsRequest(b []byte) (*TransferSubscriptionsRequest, error) {
+	t := &TransferSubscriptionsRequest{}
+	if err := t.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// DecodeFromBytes decodes given bytes into TransferSubscriptionsRequest.
+func (t *TransferSubscriptionsRequest) DecodeFromBytes(b []byte) error {
+	var offset = 0
+	t.TypeID = &datatypes.ExpandedNodeID{}
+	if err := t.TypeID.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += t.TypeID.Len()
+
+	t.RequestHeader = &RequestHeader{}
+	if err := t.RequestHeader.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += t.RequestHeader.Len() - len(t.RequestHeader.Payload)
+
+	t.SubscriptionIDs = &datatypes.Uint32Array{}
+	if err := t.SubscriptionIDs.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += t.SubscriptionIDs.Len()
+
+	t.SendInitialValues = &datatypes.Boolean{}
+	return t.SendInitialValues.DecodeFromBytes(b[offset:])
+}
+
+// Serialize serializes TransferSubscriptionsRequest into bytes.
+func (t *TransferSubscriptionsRequest) Serialize() ([]byte, error) {
+	b := make([]byte, t.Len())
+	if err := t.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes TransferSubscriptionsRequest into bytes.
+func (t *TransferSubscriptionsRequest) SerializeTo(b []byte) error {
+	var offset = 0
+	if t.TypeID != nil {
+		if err := t.TypeID.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += t.TypeID.Len()
+	}
+
+	if t.RequestHeader != nil {
+		if err := t.RequestHeader.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += t.RequestHeader.Len()
+	}
+
+	if t.SubscriptionIDs != nil {
+		if err := t.SubscriptionIDs.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += t.SubscriptionIDs.Len()
+	}
+
+	if t.SendInitialValues != nil {
+		return t.SendInitialValues.SerializeTo(b[offset:])
+	}
+
+	return nil
+}
+
+// Len returns the actual length of TransferSubscriptionsRequest in int.
+func (t *TransferSubscriptionsRequest) Len() int {
+	var l = 0
+	if t.TypeID != nil {
+		l += t.TypeID.Len()
+	}
+	if t.RequestHeader != nil {
+		l += t.RequestHeader.Len()
+	}
+	if t.SubscriptionIDs != nil {
+		l += t.SubscriptionIDs.Len()
+	}
+	if t.SendInitialValues != nil {
+		l += t.SendInitialValues.Len()
+	}
+
+	return l
+}
+
+// String returns TransferSubscriptionsRequest in string.
+func (t *TransferSubscriptionsRequest) String() string {
+	return fmt.Sprintf("%v, %v, %v, %v",
+		t.TypeID,
+		t.RequestHeader,
+		t.SubscriptionIDs,
+		t.SendInitialValues,
+	)
+}
+
+// ServiceType returns type of Service in uint16.
+func (t *TransferSubscriptionsRequest) ServiceType() uint16 {
+	return ServiceTypeTransferSubscriptionsRequest
+}
@@ -0,0 +1,142 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package services
+
+import (
+	"fmt"
+
+	"github.com/wmnsk/gopcua/datatypes"
+)
+
+// TransferSubscriptionsResponse returns the TransferResults for the Subscriptions
+// in the TransferSubscriptionsRequest, in the same order.
+//
+// Specification: Part 4, 5.13.7
+type TransferSubscriptionsResponse struct {
+	TypeID *datatypes.ExpandedNodeID
+	*ResponseHeader
+	Results         *datatypes.TransferResultArray
+	DiagnosticInfos *DiagnosticInfoArray
+}
+
+// NewTransferSubscriptionsResponse creates a new TransferSubscriptionsResponse.
+func NewTransferSubscriptionsResponse(resHeader *ResponseHeader, diags []*DiagnosticInfo, results ...*datatypes.TransferResult) *TransferSubscriptionsResponse {
+	return &TransferSubscriptionsResponse{
+		TypeID:          datatypes.NewFourByteExpandedNodeID(0, ServiceTypeTransferSubscriptionsResponse),
+		ResponseHeader:  resHeader,
+		Results:         datatypes.NewTransferResultArray(results),
+		DiagnosticInfos: NewDiagnosticInfoArray(diags),
+	}
+}
+
+// DecodeTransferSubscriptionsResponse decodes given bytes into TransferSubscriptionsResponse.
+func DecodeTransferSubscriptionsResponse(b []byte) (*TransferSubscriptionsResponse, error) {
+	t := &TransferSubscriptionsResponse{}
+	if err := t.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// DecodeFromBytes decodes given bytes into TransferSubscriptionsResponse.
+func (t *TransferSubscriptionsResponse) DecodeFromBytes(b []byte) error {
+	var offset = 0
+	t.TypeID = &datatypes.ExpandedNodeID{}
+	if err := t.TypeID.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += t.TypeID.Len()
+
+	t.ResponseHeader = &ResponseHeader{}
+	if err := t.ResponseHeader.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += t.ResponseHeader.Len() - len(t.ResponseHeader.Payload)
+
+	t.Results = &datatypes.TransferResultArray{}
+	if err := t.Results.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += t.Results.Len()
+
+	t.DiagnosticInfos = &DiagnosticInfoArray{}
+	return t.DiagnosticInfos.DecodeFromBytes(b[offset:])
+}
+
+// Serialize serializes TransferSubscriptionsResponse into bytes.
+func (t *TransferSubscriptionsResponse) Serialize() ([]byte, error) {
+	b := make([]byte, t.Len())
+	if err := t.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes TransferSubscriptionsResponse into bytes.
+func (t *TransferSubscriptionsResponse) SerializeTo(b []byte) error {
+	var offset = 0
+	if t.TypeID != nil {
+		if err := t.TypeID.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += t.TypeID.Len()
+	}
+
+	if t.ResponseHeader != nil {
+		if err := t.ResponseHeader.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += t.ResponseHeader.Len()
+	}
+
+	if t.Results != nil {
+		if err := t.Results.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += t.Results.Len()
+	}
+
+	if t.DiagnosticInfos != nil {
+		return t.DiagnosticInfos.SerializeTo(b[offset:])
+	}
+
+	return nil
+}
+
+// Len returns the actual length of TransferSubscriptionsResponse in int.
+func (t *TransferSubscriptionsResponse) Len() int {
+	var l = 0
+	if t.TypeID != nil {
+		l += t.TypeID.Len()
+	}
+	if t.ResponseHeader != nil {
+		l += t.ResponseHeader.Len()
+	}
+	if t.Results != nil {
+		l += t.Results.Len()
+	}
+	if t.DiagnosticInfos != nil {
+		l += t.DiagnosticInfos.Len()
+	}
+
+	return l
+}
+
+// String returns TransferSubscriptionsResponse in string.
+func (t *TransferSubscriptionsResponse) String() string {
+	return fmt.Sprintf("%v, %v, %v, %v",
+		t.TypeID,
+		t.ResponseHeader,
+		t.Results,
+		t.DiagnosticInfos,
+	)
+}
+
+// ServiceType returns type of Service in uint16.
+func (t *TransferSubscriptionsResponse) ServiceType() uint16 {
+	return ServiceTypeTransferSubscriptionsResponse
+}
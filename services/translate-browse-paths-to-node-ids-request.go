@@ -0,0 +1,123 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package services
+
+import (
+	"fmt"
+
+	"github.com/wmnsk/gopcua/datatypes"
+)
+
+// TranslateBrowsePathsToNodeIdsRequest is used to translate the BrowsePaths into the NodeIDs,
+// following the References and BrowseNames in each RelativePath from its StartingNode.
+//
+// Specification: Part 4, 5.8.4.2
+type TranslateBrowsePathsToNodeIdsRequest struct {
+	TypeID *datatypes.ExpandedNodeID
+	*RequestHeader
+	BrowsePaths *datatypes.BrowsePathArray
+}
+
+// NewTranslateBrowsePathsToNodeIdsRequest creates a new TranslateBrowsePathsToNodeIdsRequest.
+func NewTranslateBrowsePathsToNodeIdsRequest(reqHeader *RequestHeader, paths ...*datatypes.BrowsePath) *TranslateBrowsePathsToNodeIdsRequest {
+	return &TranslateBrowsePathsToNodeIdsRequest{
+		TypeID:        datatypes.NewFourByteExpandedNodeID(0, ServiceTypeTranslateBrowsePathsToNodeIdsRequest),
+		RequestHeader: reqHeader,
+		BrowsePaths:   datatypes.NewBrowsePathArray(paths),
+	}
+}
+
+// DecodeTranslateBrowsePathsToNodeIdsRequest decodes given bytes into TranslateBrowsePathsToNodeIdsRequest.
+func DecodeTranslateBrowsePathsToNodeIdsRequest(b []byte) (*TranslateBrowsePathsToNodeIdsRequest, error) {
+	r := &TranslateBrowsePathsToNodeIdsRequest{}
+	if err := r.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// DecodeFromBytes decodes given bytes into TranslateBrowsePathsToNodeIdsRequest.
+func (r *TranslateBrowsePathsToNodeIdsRequest) DecodeFromBytes(b []byte) error {
+	var offset = 0
+	r.TypeID = &datatypes.ExpandedNodeID{}
+	if err := r.TypeID.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += r.TypeID.Len()
+
+	r.RequestHeader = &RequestHeader{}
+	if err := r.RequestHeader.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += r.RequestHeader.Len() - len(r.RequestHeader.Payload)
+
+	r.BrowsePaths = &datatypes.BrowsePathArray{}
+	return r.BrowsePaths.DecodeFromBytes(b[offset:])
+}
+
+// Serialize serializes TranslateBrowsePathsToNodeIdsRequest into bytes.
+func (r *TranslateBrowsePathsToNodeIdsRequest) Serialize() ([]byte, error) {
+	b := make([]byte, r.Len())
+	if err := r.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes TranslateBrowsePathsToNodeIdsRequest into bytes.
+func (r *TranslateBrowsePathsToNodeIdsRequest) SerializeTo(b []byte) error {
+	var offset = 0
+	if r.TypeID != nil {
+		if err := r.TypeID.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += r.TypeID.Len()
+	}
+
+	if r.RequestHeader != nil {
+		if err := r.RequestHeader.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += r.RequestHeader.Len()
+	}
+
+	if r.BrowsePaths != nil {
+		return r.BrowsePaths.SerializeTo(b[offset:])
+	}
+
+	return nil
+}
+
+// Len returns the actual length of TranslateBrowsePathsToNodeIdsRequest in int.
+func (r *TranslateBrowsePathsToNodeIdsRequest) Len() int {
+	var l = 0
+	if r.TypeID != nil {
+		l += r.TypeID.Len()
+	}
+	if r.RequestHeader != nil {
+		l += r.RequestHeader.Len()
+	}
+	if r.BrowsePaths != nil {
+		l += r.BrowsePaths.Len()
+	}
+
+	return l
+}
+
+// String returns TranslateBrowsePathsToNodeIdsRequest in string.
+func (r *TranslateBrowsePathsToNodeIdsRequest) String() string {
+	return fmt.Sprintf("%v, %v, %v",
+		r.TypeID,
+		r.RequestHeader,
+		r.BrowsePaths,
+	)
+}
+
+// ServiceType returns type of Service in uint16.
+func (r *TranslateBrowsePathsToNodeIdsRequest) ServiceType() uint16 {
+	return ServiceTypeTranslateBrowsePathsToNodeIdsRequest
+}
@@ -0,0 +1,142 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package services
+
+import (
+	"fmt"
+
+	"github.com/wmnsk/gopcua/datatypes"
+)
+
+// TranslateBrowsePathsToNodeIdsResponse returns the BrowsePathResults for the BrowsePaths
+// in the TranslateBrowsePathsToNodeIdsRequest, in the same order.
+//
+// Specification: Part 4, 5.8.4.2
+type TranslateBrowsePathsToNodeIdsResponse struct {
+	TypeID *datatypes.ExpandedNodeID
+	*ResponseHeader
+	Results         *datatypes.BrowsePathResultArray
+	DiagnosticInfos *DiagnosticInfoArray
+}
+
+// NewTranslateBrowsePathsToNodeIdsResponse creates a new TranslateBrowsePathsToNodeIdsResponse.
+func NewTranslateBrowsePathsToNodeIdsResponse(resHeader *ResponseHeader, diags []*DiagnosticInfo, results ...*datatypes.BrowsePathResult) *TranslateBrowsePathsToNodeIdsResponse {
+	return &TranslateBrowsePathsToNodeIdsResponse{
+		TypeID:          datatypes.NewFourByteExpandedNodeID(0, ServiceTypeTranslateBrowsePathsToNodeIdsResponse),
+		ResponseHeader:  resHeader,
+		Results:         datatypes.NewBrowsePathResultArray(results),
+		DiagnosticInfos: NewDiagnosticInfoArray(diags),
+	}
+}
+
+// DecodeTranslateBrowsePathsToNodeIdsResponse decodes given bytes into TranslateBrowsePathsToNodeIdsResponse.
+func DecodeTranslateBrowsePathsToNodeIdsResponse(b []byte) (*TranslateBrowsePathsToNodeIdsResponse, error) {
+	t := &TranslateBrowsePathsToNodeIdsResponse{}
+	if err := t.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return t, nil
+}
+
+// DecodeFromBytes decodes given bytes into TranslateBrowsePathsToNodeIdsResponse.
+func (t *TranslateBrowsePathsToNodeIdsResponse) DecodeFromBytes(b []byte) error {
+	var offset = 0
+	t.TypeID = &datatypes.ExpandedNodeID{}
+	if err := t.TypeID.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += t.TypeID.Len()
+
+	t.ResponseHeader = &ResponseHeader{}
+	if err := t.ResponseHeader.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += t.ResponseHeader.Len() - len(t.ResponseHeader.Payload)
+
+	t.Results = &datatypes.BrowsePathResultArray{}
+	if err := t.Results.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += t.Results.Len()
+
+	t.DiagnosticInfos = &DiagnosticInfoArray{}
+	return t.DiagnosticInfos.DecodeFromBytes(b[offset:])
+}
+
+// Serialize serializes TranslateBrowsePathsToNodeIdsResponse into bytes.
+func (t *TranslateBrowsePathsToNodeIdsResponse) Serialize() ([]byte, error) {
+	b := make([]byte, t.Len())
+	if err := t.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes TranslateBrowsePathsToNodeIdsResponse into bytes.
+func (t *TranslateBrowsePathsToNodeIdsResponse) SerializeTo(b []byte) error {
+	var offset = 0
+	if t.TypeID != nil {
+		if err := t.TypeID.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += t.TypeID.Len()
+	}
+
+	if t.ResponseHeader != nil {
+		if err := t.ResponseHeader.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += t.ResponseHeader.Len()
+	}
+
+	if t.Results != nil {
+		if err := t.Results.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += t.Results.Len()
+	}
+
+	if t.DiagnosticInfos != nil {
+		return t.DiagnosticInfos.SerializeTo(b[offset:])
+	}
+
+	return nil
+}
+
+// Len returns the actual length of TranslateBrowsePathsToNodeIdsResponse in int.
+func (t *TranslateBrowsePathsToNodeIdsResponse) Len() int {
+	var l = 0
+	if t.TypeID != nil {
+		l += t.TypeID.Len()
+	}
+	if t.ResponseHeader != nil {
+		l += t.ResponseHeader.Len()
+	}
+	if t.Results != nil {
+		l += t.Results.Len()
+	}
+	if t.DiagnosticInfos != nil {
+		l += t.DiagnosticInfos.Len()
+	}
+
+	return l
+}
+
+// String returns TranslateBrowsePathsToNodeIdsResponse in string.
+func (t *TranslateBrowsePathsToNodeIdsResponse) String() string {
+	return fmt.Sprintf("%v, %v, %v, %v",
+		t.TypeID,
+		t.ResponseHeader,
+		t.Results,
+		t.DiagnosticInfos,
+	)
+}
+
+// ServiceType returns type of Service in uint16.
+func (t *TranslateBrowsePathsToNodeIdsResponse) ServiceType() uint16 {
+	return ServiceTypeTranslateBrowsePathsToNodeIdsResponse
+}
@@ -0,0 +1,74 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/wmnsk/gopcua/datatypes"
+	"github.com/wmnsk/gopcua/utils/codectest"
+)
+
+func TestTranslateBrowsePathsToNodeIdsResponse(t *testing.T) {
+	cases := []codectest.Case{
+		{
+			Name: "normal",
+			Struct: NewTranslateBrowsePathsToNodeIdsResponse(
+				NewResponseHeader(
+					time.Date(2018, time.August, 10, 23, 0, 0, 0, time.UTC),
+					1, 0, NewNullDiagnosticInfo(), []string{}, NewNullAdditionalHeader(), nil,
+				),
+				nil,
+				datatypes.NewBrowsePathResult(
+					0, datatypes.NewBrowsePathTarget(datatypes.NewStringExpandedNodeID(1, "foo"), 0xffffffff),
+				),
+			),
+			Bytes: []byte{
+				// TypeID
+				0x01, 0x00, 0x2d, 0x02,
+				// Timestamp
+				0x00, 0x98, 0x67, 0xdd, 0xfd, 0x30, 0xd4, 0x01,
+				// RequestHandle
+				0x01, 0x00, 0x00, 0x00,
+				// ServiceResult
+				0x00, 0x00, 0x00, 0x00,
+				// ServiceDiagnostics
+				0x00,
+				// StringTable
+				0x00, 0x00, 0x00, 0x00,
+				// AdditionalHeader
+				0x00, 0x00, 0x00,
+				// Results: ArraySize
+				0x01, 0x00, 0x00, 0x00,
+				// StatusCode
+				0x00, 0x00, 0x00, 0x00,
+				// Targets: ArraySize
+				0x01, 0x00, 0x00, 0x00,
+				// TargetID
+				0x03, 0x01, 0x00, 0x03, 0x00, 0x00, 0x00, 0x66, 0x6f, 0x6f,
+				// RemainingPathIndex
+				0xff, 0xff, 0xff, 0xff,
+				// DiagnosticInfos
+				0x00, 0x00, 0x00, 0x00,
+			},
+		},
+	}
+	codectest.Run(t, cases, func(b []byte) (codectest.S, error) {
+		v, err := DecodeTranslateBrowsePathsToNodeIdsResponse(b)
+		if err != nil {
+			return nil, err
+		}
+		v.Payload = nil
+		return v, nil
+	})
+
+	t.Run("service-id", func(t *testing.T) {
+		id := new(TranslateBrowsePathsToNodeIdsResponse).ServiceType()
+		if got, want := id, uint16(ServiceTypeTranslateBrowsePathsToNodeIdsResponse); got != want {
+			t.Fatalf("got %d want %d", got, want)
+		}
+	})
+}
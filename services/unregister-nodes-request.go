@@ -0,0 +1,123 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package services
+
+import (
+	"fmt"
+
+	"github.com/wmnsk/gopcua/datatypes"
+)
+
+// UnregisterNodesRequest is used to unregister the Nodes registered with RegisterNodesRequest,
+// so that the server can release the resources for them.
+//
+// Specification: Part 4, 5.8.6.2
+type UnregisterNodesRequest struct {
+	TypeID *datatypes.ExpandedNodeID
+	*RequestHeader
+	NodesToUnregister *datatypes.NodeIDArray
+}
+
+// NewUnregisterNodesRequest creates a new UnregisterNodesRequest.
+func NewUnregisterNodesRequest(reqHeader *RequestHeader, nodes ...*datatypes.NodeID) *UnregisterNodesRequest {
+	return &UnregisterNodesRequest{
+		TypeID:            datatypes.NewFourByteExpandedNodeID(0, ServiceTypeUnregisterNodesRequest),
+		RequestHeader:     reqHeader,
+		NodesToUnregister: datatypes.NewNodeIDArray(nodes),
+	}
+}
+
+// DecodeUnregisterNodesRequest decodes given bytes into UnregisterNodesRequest.
+func DecodeUnregisterNodesRequest(b []byte) (*UnregisterNodesRequest, error) {
+	r := &UnregisterNodesRequest{}
+	if err := r.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// DecodeFromBytes decodes given bytes into UnregisterNodesRequest.
+func (r *UnregisterNodesRequest) DecodeFromBytes(b []byte) error {
+	var offset = 0
+	r.TypeID = &datatypes.ExpandedNodeID{}
+	if err := r.TypeID.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += r.TypeID.Len()
+
+	r.RequestHeader = &RequestHeader{}
+	if err := r.RequestHeader.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += r.RequestHeader.Len() - len(r.RequestHeader.Payload)
+
+	r.NodesToUnregister = &datatypes.NodeIDArray{}
+	return r.NodesToUnregister.DecodeFromBytes(b[offset:])
+}
+
+// Serialize serializes UnregisterNodesRequest into bytes.
+func (r *UnregisterNodesRequest) Serialize() ([]byte, error) {
+	b := make([]byte, r.Len())
+	if err := r.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes UnregisterNodesRequest into bytes.
+func (r *UnregisterNodesRequest) SerializeTo(b []byte) error {
+	var offset = 0
+	if r.TypeID != nil {
+		if err := r.TypeID.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += r.TypeID.Len()
+	}
+
+	if r.RequestHeader != nil {
+		if err := r.RequestHeader.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += r.RequestHeader.Len()
+	}
+
+	if r.NodesToUnregister != nil {
+		return r.NodesToUnregister.SerializeTo(b[offset:])
+	}
+
+	return nil
+}
+
+// Len returns the actual length of UnregisterNodesRequest in int.
+func (r *UnregisterNodesRequest) Len() int {
+	var l = 0
+	if r.TypeID != nil {
+		l += r.TypeID.Len()
+	}
+	if r.RequestHeader != nil {
+		l += r.RequestHeader.Len()
+	}
+	if r.NodesToUnregister != nil {
+		l += r.NodesToUnregister.Len()
+	}
+
+	return l
+}
+
+// String returns UnregisterNodesRequest in string.
+func (r *UnregisterNodesRequest) String() string {
+	return fmt.Sprintf("%v, %v, %v",
+		r.TypeID,
+		r.RequestHeader,
+		r.NodesToUnregister,
+	)
+}
+
+// ServiceType returns type of Service in uint16.
+func (r *UnregisterNodesRequest) ServiceType() uint16 {
+	return ServiceTypeUnregisterNodesRequest
+}
@@ -0,0 +1,104 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package services
+
+import (
+	"fmt"
+
+	"github.com/wmnsk/gopcua/datatypes"
+)
+
+// UnregisterNodesResponse is the response to UnregisterNodesRequest, which has no parameters
+// other than the ResponseHeader.
+//
+// Specification: Part 4, 5.8.6.2
+type UnregisterNodesResponse struct {
+	TypeID *datatypes.ExpandedNodeID
+	*ResponseHeader
+}
+
+// NewUnregisterNodesResponse creates a new UnregisterNodesResponse.
+func NewUnregisterNodesResponse(resHeader *ResponseHeader) *UnregisterNodesResponse {
+	return &UnregisterNodesResponse{
+		TypeID:         datatypes.NewFourByteExpandedNodeID(0, ServiceTypeUnregisterNodesResponse),
+		ResponseHeader: resHeader,
+	}
+}
+
+// DecodeUnregisterNodesResponse decodes given bytes into UnregisterNodesResponse.
+func DecodeUnregisterNodesResponse(b []byte) (*UnregisterNodesResponse, error) {
+	u := &UnregisterNodesResponse{}
+	if err := u.DecodeFromBytes(b); err != nil {
+		return nil, err
+	}
+
+	return u, nil
+}
+
+// DecodeFromBytes decodes given bytes into UnregisterNodesResponse.
+func (u *UnregisterNodesResponse) DecodeFromBytes(b []byte) error {
+	var offset = 0
+	u.TypeID = &datatypes.ExpandedNodeID{}
+	if err := u.TypeID.DecodeFromBytes(b[offset:]); err != nil {
+		return err
+	}
+	offset += u.TypeID.Len()
+
+	u.ResponseHeader = &ResponseHeader{}
+	return u.ResponseHeader.DecodeFromBytes(b[offset:])
+}
+
+// Serialize serializes UnregisterNodesResponse into bytes.
+func (u *UnregisterNodesResponse) Serialize() ([]byte, error) {
+	b := make([]byte, u.Len())
+	if err := u.SerializeTo(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// SerializeTo serializes UnregisterNodesResponse into bytes.
+func (u *UnregisterNodesResponse) SerializeTo(b []byte) error {
+	var offset = 0
+	if u.TypeID != nil {
+		if err := u.TypeID.SerializeTo(b[offset:]); err != nil {
+			return err
+		}
+		offset += u.TypeID.Len()
+	}
+
+	if u.ResponseHeader != nil {
+		return u.ResponseHeader.SerializeTo(b[offset:])
+	}
+
+	return nil
+}
+
+// Len returns the actual length of UnregisterNodesResponse in int.
+func (u *UnregisterNodesResponse) Len() int {
+	var l = 0
+	if u.TypeID != nil {
+		l += u.TypeID.Len()
+	}
+	if u.ResponseHeader != nil {
+		l += u.ResponseHeader.Len()
+	}
+
+	return l
+}
+
+// String returns UnregisterNodesResponse in string.
+func (u *UnregisterNodesResponse) String() string {
+	return fmt.Sprintf("%v, %v",
+		u.TypeID,
+		u.ResponseHeader,
+	)
+}
+
+// ServiceType returns type of Service in uint16.
+func (u *UnregisterNodesResponse) ServiceType() uint16 {
+	return ServiceTypeUnregisterNodesResponse
+}
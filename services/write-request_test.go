@@ -27,7 +27,7 @@ func TestWriteRequest(t *testing.T) {
 				),
 				datatypes.NewWriteValue(
 					datatypes.NewFourByteNodeID(0, 2256),
-					datatypes.IntegerIDValue,
+					datatypes.AttributeIDValue,
 					"",
 					datatypes.NewDataValue(
 						true, false, true, false, true, false,
@@ -88,7 +88,7 @@ func TestWriteRequest(t *testing.T) {
 				),
 				datatypes.NewWriteValue(
 					datatypes.NewFourByteNodeID(0, 2256),
-					datatypes.IntegerIDValue,
+					datatypes.AttributeIDValue,
 					"",
 					datatypes.NewDataValue(
 						true, false, true, false, true, false,
@@ -102,7 +102,7 @@ func TestWriteRequest(t *testing.T) {
 				),
 				datatypes.NewWriteValue(
 					datatypes.NewFourByteNodeID(0, 2256),
-					datatypes.IntegerIDValue,
+					datatypes.AttributeIDValue,
 					"",
 					datatypes.NewDataValue(
 						true, false, true, false, true, false,
@@ -0,0 +1,60 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package status
+
+import "fmt"
+
+// Good is the StatusCode of the operations completed successfully.
+const Good = 0
+
+// StatusCode is the result of a service or an operation.
+//
+// The top two bits are the severity, the next 14 bits are the code defined by
+// the constants in this package, and the lower 16 bits are the flags and
+// the additional information.
+//
+// Specification: Part 4, 7.34
+type StatusCode uint32
+
+// the bits of the severity and the code in StatusCode.
+const (
+	severityMask      StatusCode = 0xc0000000
+	severityUncertain StatusCode = 0x40000000
+	severityBad       StatusCode = 0x80000000
+	codeMask          StatusCode = 0xffff0000
+)
+
+// IsGood reports whether c has the severity Good.
+func (c StatusCode) IsGood() bool {
+	return c&severityMask == 0
+}
+
+// IsUncertain reports whether c has the severity Uncertain.
+func (c StatusCode) IsUncertain() bool {
+	return c&severityMask == severityUncertain
+}
+
+// IsBad reports whether c has the severity Bad.
+func (c StatusCode) IsBad() bool {
+	return c&severityBad != 0
+}
+
+// Name returns the symbolic name of c defined in the specification, such as "BadNodeIdUnknown".
+// The flags in the lower 16 bits are ignored. An empty string is returned if c is unknown.
+func (c StatusCode) Name() string {
+	code := c & codeMask
+	if code == Good {
+		return "Good"
+	}
+	return names[code]
+}
+
+// String returns the symbolic name of c with the value in hexadecimal.
+func (c StatusCode) String() string {
+	if name := c.Name(); name != "" {
+		return fmt.Sprintf("%s (0x%08X)", name, uint32(c))
+	}
+	return fmt.Sprintf("0x%08X", uint32(c))
+}
@@ -0,0 +1,45 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package status
+
+import "testing"
+
+func TestStatusCode(t *testing.T) {
+	cases := []struct {
+		code                 StatusCode
+		name, str            string
+		good, uncertain, bad bool
+	}{
+		{Good, "Good", "Good (0x00000000)", true, false, false},
+		{GoodClamped, "GoodClamped", "GoodClamped (0x00300000)", true, false, false},
+		{UncertainLastUsableValue, "UncertainLastUsableValue", "UncertainLastUsableValue (0x40900000)", false, true, false},
+		{BadNodeIdUnknown, "BadNodeIdUnknown", "BadNodeIdUnknown (0x80340000)", false, false, true},
+		{BadSubscriptionIdInvalid, "BadSubscriptionIdInvalid", "BadSubscriptionIdInvalid (0x80280000)", false, false, true},
+		{BadMaxConnectionsReached, "BadMaxConnectionsReached", "BadMaxConnectionsReached (0x80B70000)", false, false, true},
+		// the flags in the lower 16 bits are ignored in the name.
+		{BadTimeout | 0x0400, "BadTimeout", "BadTimeout (0x800A0400)", false, false, true},
+		{0x80FF0000, "", "0x80FF0000", false, false, true},
+		{0x40FF0000, "", "0x40FF0000", false, true, false},
+	}
+	for _, c := range cases {
+		t.Run(c.str, func(t *testing.T) {
+			if got := c.code.Name(); got != c.name {
+				t.Errorf("Name: got %q want %q", got, c.name)
+			}
+			if got := c.code.String(); got != c.str {
+				t.Errorf("String: got %q want %q", got, c.str)
+			}
+			if got := c.code.IsGood(); got != c.good {
+				t.Errorf("IsGood: got %v want %v", got, c.good)
+			}
+			if got := c.code.IsUncertain(); got != c.uncertain {
+				t.Errorf("IsUncertain: got %v want %v", got, c.uncertain)
+			}
+			if got := c.code.IsBad(); got != c.bad {
+				t.Errorf("IsBad: got %v want %v", got, c.bad)
+			}
+		})
+	}
+}
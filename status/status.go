@@ -240,3 +240,242 @@ const (
 	BadSyntaxError                          = 0x80B60000
 	BadMaxConnectionsReached                = 0x80B70000
 )
+
+// names is the symbolic names of the StatusCodes, generated automatically by cmd/status.
+var names = map[StatusCode]string{
+	BadUnexpectedError:                      "BadUnexpectedError",
+	BadInternalError:                        "BadInternalError",
+	BadOutOfMemory:                          "BadOutOfMemory",
+	BadResourceUnavailable:                  "BadResourceUnavailable",
+	BadCommunicationError:                   "BadCommunicationError",
+	BadEncodingError:                        "BadEncodingError",
+	BadDecodingError:                        "BadDecodingError",
+	BadEncodingLimitsExceeded:               "BadEncodingLimitsExceeded",
+	BadRequestTooLarge:                      "BadRequestTooLarge",
+	BadResponseTooLarge:                     "BadResponseTooLarge",
+	BadUnknownResponse:                      "BadUnknownResponse",
+	BadTimeout:                              "BadTimeout",
+	BadServiceUnsupported:                   "BadServiceUnsupported",
+	BadShutdown:                             "BadShutdown",
+	BadServerNotConnected:                   "BadServerNotConnected",
+	BadServerHalted:                         "BadServerHalted",
+	BadNothingToDo:                          "BadNothingToDo",
+	BadTooManyOperations:                    "BadTooManyOperations",
+	BadTooManyMonitoredItems:                "BadTooManyMonitoredItems",
+	BadDataTypeIdUnknown:                    "BadDataTypeIdUnknown",
+	BadCertificateInvalid:                   "BadCertificateInvalid",
+	BadSecurityChecksFailed:                 "BadSecurityChecksFailed",
+	BadCertificatePolicyCheckFailed:         "BadCertificatePolicyCheckFailed",
+	BadCertificateTimeInvalid:               "BadCertificateTimeInvalid",
+	BadCertificateIssuerTimeInvalid:         "BadCertificateIssuerTimeInvalid",
+	BadCertificateHostNameInvalid:           "BadCertificateHostNameInvalid",
+	BadCertificateUriInvalid:                "BadCertificateUriInvalid",
+	BadCertificateUseNotAllowed:             "BadCertificateUseNotAllowed",
+	BadCertificateIssuerUseNotAllowed:       "BadCertificateIssuerUseNotAllowed",
+	BadCertificateUntrusted:                 "BadCertificateUntrusted",
+	BadCertificateRevocationUnknown:         "BadCertificateRevocationUnknown",
+	BadCertificateIssuerRevocationUnknown:   "BadCertificateIssuerRevocationUnknown",
+	BadCertificateRevoked:                   "BadCertificateRevoked",
+	BadCertificateIssuerRevoked:             "BadCertificateIssuerRevoked",
+	BadCertificateChainIncomplete:           "BadCertificateChainIncomplete",
+	BadUserAccessDenied:                     "BadUserAccessDenied",
+	BadIdentityTokenInvalid:                 "BadIdentityTokenInvalid",
+	BadIdentityTokenRejected:                "BadIdentityTokenRejected",
+	BadSecureChannelIdInvalid:               "BadSecureChannelIdInvalid",
+	BadInvalidTimestamp:                     "BadInvalidTimestamp",
+	BadNonceInvalid:                         "BadNonceInvalid",
+	BadSessionIdInvalid:                     "BadSessionIdInvalid",
+	BadSessionClosed:                        "BadSessionClosed",
+	BadSessionNotActivated:                  "BadSessionNotActivated",
+	BadSubscriptionIdInvalid:                "BadSubscriptionIdInvalid",
+	BadRequestHeaderInvalid:                 "BadRequestHeaderInvalid",
+	BadTimestampsToReturnInvalid:            "BadTimestampsToReturnInvalid",
+	BadRequestCancelledByClient:             "BadRequestCancelledByClient",
+	BadTooManyArguments:                     "BadTooManyArguments",
+	BadLicenseExpired:                       "BadLicenseExpired",
+	BadLicenseLimitsExceeded:                "BadLicenseLimitsExceeded",
+	BadLicenseNotAvailable:                  "BadLicenseNotAvailable",
+	GoodSubscriptionTransferred:             "GoodSubscriptionTransferred",
+	GoodCompletesAsynchronously:             "GoodCompletesAsynchronously",
+	GoodOverload:                            "GoodOverload",
+	GoodClamped:                             "GoodClamped",
+	BadNoCommunication:                      "BadNoCommunication",
+	BadWaitingForInitialData:                "BadWaitingForInitialData",
+	BadNodeIdInvalid:                        "BadNodeIdInvalid",
+	BadNodeIdUnknown:                        "BadNodeIdUnknown",
+	BadAttributeIdInvalid:                   "BadAttributeIdInvalid",
+	BadIndexRangeInvalid:                    "BadIndexRangeInvalid",
+	BadIndexRangeNoData:                     "BadIndexRangeNoData",
+	BadDataEncodingInvalid:                  "BadDataEncodingInvalid",
+	BadDataEncodingUnsupported:              "BadDataEncodingUnsupported",
+	BadNotReadable:                          "BadNotReadable",
+	BadNotWritable:                          "BadNotWritable",
+	BadOutOfRange:                           "BadOutOfRange",
+	BadNotSupported:                         "BadNotSupported",
+	BadNotFound:                             "BadNotFound",
+	BadObjectDeleted:                        "BadObjectDeleted",
+	BadNotImplemented:                       "BadNotImplemented",
+	BadMonitoringModeInvalid:                "BadMonitoringModeInvalid",
+	BadMonitoredItemIdInvalid:               "BadMonitoredItemIdInvalid",
+	BadMonitoredItemFilterInvalid:           "BadMonitoredItemFilterInvalid",
+	BadMonitoredItemFilterUnsupported:       "BadMonitoredItemFilterUnsupported",
+	BadFilterNotAllowed:                     "BadFilterNotAllowed",
+	BadStructureMissing:                     "BadStructureMissing",
+	BadEventFilterInvalid:                   "BadEventFilterInvalid",
+	BadContentFilterInvalid:                 "BadContentFilterInvalid",
+	BadFilterOperatorInvalid:                "BadFilterOperatorInvalid",
+	BadFilterOperatorUnsupported:            "BadFilterOperatorUnsupported",
+	BadFilterOperandCountMismatch:           "BadFilterOperandCountMismatch",
+	BadFilterOperandInvalid:                 "BadFilterOperandInvalid",
+	BadFilterElementInvalid:                 "BadFilterElementInvalid",
+	BadFilterLiteralInvalid:                 "BadFilterLiteralInvalid",
+	BadContinuationPointInvalid:             "BadContinuationPointInvalid",
+	BadNoContinuationPoints:                 "BadNoContinuationPoints",
+	BadReferenceTypeIdInvalid:               "BadReferenceTypeIdInvalid",
+	BadBrowseDirectionInvalid:               "BadBrowseDirectionInvalid",
+	BadNodeNotInView:                        "BadNodeNotInView",
+	BadNumericOverflow:                      "BadNumericOverflow",
+	BadServerUriInvalid:                     "BadServerUriInvalid",
+	BadServerNameMissing:                    "BadServerNameMissing",
+	BadDiscoveryUrlMissing:                  "BadDiscoveryUrlMissing",
+	BadSempahoreFileMissing:                 "BadSempahoreFileMissing",
+	BadRequestTypeInvalid:                   "BadRequestTypeInvalid",
+	BadSecurityModeRejected:                 "BadSecurityModeRejected",
+	BadSecurityPolicyRejected:               "BadSecurityPolicyRejected",
+	BadTooManySessions:                      "BadTooManySessions",
+	BadUserSignatureInvalid:                 "BadUserSignatureInvalid",
+	BadApplicationSignatureInvalid:          "BadApplicationSignatureInvalid",
+	BadNoValidCertificates:                  "BadNoValidCertificates",
+	BadIdentityChangeNotSupported:           "BadIdentityChangeNotSupported",
+	BadRequestCancelledByRequest:            "BadRequestCancelledByRequest",
+	BadParentNodeIdInvalid:                  "BadParentNodeIdInvalid",
+	BadReferenceNotAllowed:                  "BadReferenceNotAllowed",
+	BadNodeIdRejected:                       "BadNodeIdRejected",
+	BadNodeIdExists:                         "BadNodeIdExists",
+	BadNodeClassInvalid:                     "BadNodeClassInvalid",
+	BadBrowseNameInvalid:                    "BadBrowseNameInvalid",
+	BadBrowseNameDuplicated:                 "BadBrowseNameDuplicated",
+	BadNodeAttributesInvalid:                "BadNodeAttributesInvalid",
+	BadTypeDefinitionInvalid:                "BadTypeDefinitionInvalid",
+	BadSourceNodeIdInvalid:                  "BadSourceNodeIdInvalid",
+	BadTargetNodeIdInvalid:                  "BadTargetNodeIdInvalid",
+	BadDuplicateReferenceNotAllowed:         "BadDuplicateReferenceNotAllowed",
+	BadInvalidSelfReference:                 "BadInvalidSelfReference",
+	BadReferenceLocalOnly:                   "BadReferenceLocalOnly",
+	BadNoDeleteRights:                       "BadNoDeleteRights",
+	UncertainReferenceNotDeleted:            "UncertainReferenceNotDeleted",
+	BadServerIndexInvalid:                   "BadServerIndexInvalid",
+	BadViewIdUnknown:                        "BadViewIdUnknown",
+	BadViewTimestampInvalid:                 "BadViewTimestampInvalid",
+	BadViewParameterMismatch:                "BadViewParameterMismatch",
+	BadViewVersionInvalid:                   "BadViewVersionInvalid",
+	UncertainNotAllNodesAvailable:           "UncertainNotAllNodesAvailable",
+	GoodResultsMayBeIncomplete:              "GoodResultsMayBeIncomplete",
+	BadNotTypeDefinition:                    "BadNotTypeDefinition",
+	UncertainReferenceOutOfServer:           "UncertainReferenceOutOfServer",
+	BadTooManyMatches:                       "BadTooManyMatches",
+	BadQueryTooComplex:                      "BadQueryTooComplex",
+	BadNoMatch:                              "BadNoMatch",
+	BadMaxAgeInvalid:                        "BadMaxAgeInvalid",
+	BadSecurityModeInsufficient:             "BadSecurityModeInsufficient",
+	BadHistoryOperationInvalid:              "BadHistoryOperationInvalid",
+	BadHistoryOperationUnsupported:          "BadHistoryOperationUnsupported",
+	BadInvalidTimestampArgument:             "BadInvalidTimestampArgument",
+	BadWriteNotSupported:                    "BadWriteNotSupported",
+	BadTypeMismatch:                         "BadTypeMismatch",
+	BadMethodInvalid:                        "BadMethodInvalid",
+	BadArgumentsMissing:                     "BadArgumentsMissing",
+	BadNotExecutable:                        "BadNotExecutable",
+	BadTooManySubscriptions:                 "BadTooManySubscriptions",
+	BadTooManyPublishRequests:               "BadTooManyPublishRequests",
+	BadNoSubscription:                       "BadNoSubscription",
+	BadSequenceNumberUnknown:                "BadSequenceNumberUnknown",
+	BadMessageNotAvailable:                  "BadMessageNotAvailable",
+	BadInsufficientClientProfile:            "BadInsufficientClientProfile",
+	BadStateNotActive:                       "BadStateNotActive",
+	BadAlreadyExists:                        "BadAlreadyExists",
+	BadTcpServerTooBusy:                     "BadTcpServerTooBusy",
+	BadTcpMessageTypeInvalid:                "BadTcpMessageTypeInvalid",
+	BadTcpSecureChannelUnknown:              "BadTcpSecureChannelUnknown",
+	BadTcpMessageTooLarge:                   "BadTcpMessageTooLarge",
+	BadTcpNotEnoughResources:                "BadTcpNotEnoughResources",
+	BadTcpInternalError:                     "BadTcpInternalError",
+	BadTcpEndpointUrlInvalid:                "BadTcpEndpointUrlInvalid",
+	BadRequestInterrupted:                   "BadRequestInterrupted",
+	BadRequestTimeout:                       "BadRequestTimeout",
+	BadSecureChannelClosed:                  "BadSecureChannelClosed",
+	BadSecureChannelTokenUnknown:            "BadSecureChannelTokenUnknown",
+	BadSequenceNumberInvalid:                "BadSequenceNumberInvalid",
+	BadProtocolVersionUnsupported:           "BadProtocolVersionUnsupported",
+	BadConfigurationError:                   "BadConfigurationError",
+	BadNotConnected:                         "BadNotConnected",
+	BadDeviceFailure:                        "BadDeviceFailure",
+	BadSensorFailure:                        "BadSensorFailure",
+	BadOutOfService:                         "BadOutOfService",
+	BadDeadbandFilterInvalid:                "BadDeadbandFilterInvalid",
+	UncertainNoCommunicationLastUsableValue: "UncertainNoCommunicationLastUsableValue",
+	UncertainLastUsableValue:                "UncertainLastUsableValue",
+	UncertainSubstituteValue:                "UncertainSubstituteValue",
+	UncertainInitialValue:                   "UncertainInitialValue",
+	UncertainSensorNotAccurate:              "UncertainSensorNotAccurate",
+	UncertainEngineeringUnitsExceeded:       "UncertainEngineeringUnitsExceeded",
+	UncertainSubNormal:                      "UncertainSubNormal",
+	GoodLocalOverride:                       "GoodLocalOverride",
+	BadRefreshInProgress:                    "BadRefreshInProgress",
+	BadConditionAlreadyDisabled:             "BadConditionAlreadyDisabled",
+	BadConditionAlreadyEnabled:              "BadConditionAlreadyEnabled",
+	BadConditionDisabled:                    "BadConditionDisabled",
+	BadEventIdUnknown:                       "BadEventIdUnknown",
+	BadEventNotAcknowledgeable:              "BadEventNotAcknowledgeable",
+	BadDialogNotActive:                      "BadDialogNotActive",
+	BadDialogResponseInvalid:                "BadDialogResponseInvalid",
+	BadConditionBranchAlreadyAcked:          "BadConditionBranchAlreadyAcked",
+	BadConditionBranchAlreadyConfirmed:      "BadConditionBranchAlreadyConfirmed",
+	BadConditionAlreadyShelved:              "BadConditionAlreadyShelved",
+	BadConditionNotShelved:                  "BadConditionNotShelved",
+	BadShelvingTimeOutOfRange:               "BadShelvingTimeOutOfRange",
+	BadNoData:                               "BadNoData",
+	BadBoundNotFound:                        "BadBoundNotFound",
+	BadBoundNotSupported:                    "BadBoundNotSupported",
+	BadDataLost:                             "BadDataLost",
+	BadDataUnavailable:                      "BadDataUnavailable",
+	BadEntryExists:                          "BadEntryExists",
+	BadNoEntryExists:                        "BadNoEntryExists",
+	BadTimestampNotSupported:                "BadTimestampNotSupported",
+	GoodEntryInserted:                       "GoodEntryInserted",
+	GoodEntryReplaced:                       "GoodEntryReplaced",
+	UncertainDataSubNormal:                  "UncertainDataSubNormal",
+	GoodNoData:                              "GoodNoData",
+	GoodMoreData:                            "GoodMoreData",
+	BadAggregateListMismatch:                "BadAggregateListMismatch",
+	BadAggregateNotSupported:                "BadAggregateNotSupported",
+	BadAggregateInvalidInputs:               "BadAggregateInvalidInputs",
+	BadAggregateConfigurationRejected:       "BadAggregateConfigurationRejected",
+	GoodDataIgnored:                         "GoodDataIgnored",
+	BadRequestNotAllowed:                    "BadRequestNotAllowed",
+	BadRequestNotComplete:                   "BadRequestNotComplete",
+	GoodEdited:                              "GoodEdited",
+	GoodPostActionFailed:                    "GoodPostActionFailed",
+	UncertainDominantValueChanged:           "UncertainDominantValueChanged",
+	GoodDependentValueChanged:               "GoodDependentValueChanged",
+	BadDominantValueChanged:                 "BadDominantValueChanged",
+	UncertainDependentValueChanged:          "UncertainDependentValueChanged",
+	BadDependentValueChanged:                "BadDependentValueChanged",
+	GoodCommunicationEvent:                  "GoodCommunicationEvent",
+	GoodShutdownEvent:                       "GoodShutdownEvent",
+	GoodCallAgain:                           "GoodCallAgain",
+	GoodNonCriticalTimeout:                  "GoodNonCriticalTimeout",
+	BadInvalidArgument:                      "BadInvalidArgument",
+	BadConnectionRejected:                   "BadConnectionRejected",
+	BadDisconnect:                           "BadDisconnect",
+	BadConnectionClosed:                     "BadConnectionClosed",
+	BadInvalidState:                         "BadInvalidState",
+	BadEndOfStream:                          "BadEndOfStream",
+	BadNoDataAvailable:                      "BadNoDataAvailable",
+	BadWaitingForResponse:                   "BadWaitingForResponse",
+	BadOperationAbandoned:                   "BadOperationAbandoned",
+	BadExpectedStreamToBlock:                "BadExpectedStreamToBlock",
+	BadWouldBlock:                           "BadWouldBlock",
+	BadSyntaxError:                          "BadSyntaxError",
+	BadMaxConnectionsReached:                "BadMaxConnectionsReached",
+}
@@ -0,0 +1,422 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package gopcua
+
+import (
+	"context"
+	"time"
+
+	"github.com/wmnsk/gopcua/datatypes"
+	"github.com/wmnsk/gopcua/errors"
+	"github.com/wmnsk/gopcua/services"
+	"github.com/wmnsk/gopcua/status"
+)
+
+// defaultPublishRequests is the number of PublishRequests kept outstanding by default.
+const defaultPublishRequests = 2
+
+// publishRetryDelay is the time to wait before sending the PublishRequest again
+// when the server rejects it with nothing outstanding.
+const publishRetryDelay = time.Second
+
+// Subscription is a Subscription created on the server by CreateSubscription.
+//
+// The NotificationMessages published for the Subscription are delivered to Notifs
+// in the order received. Keep-alive messages are not delivered.
+type Subscription struct {
+	ID                        uint32
+	RevisedPublishingInterval float64
+	RevisedLifetimeCount      uint32
+	RevisedMaxKeepAliveCount  uint32
+
+	notifs chan *datatypes.NotificationMessage
+}
+
+// Notifs returns the channel to which the NotificationMessages of the Subscription are delivered.
+// It is closed when the Client is closed, or the Subscription is lost with the connection
+// or reported by the server to exist no more.
+//
+// The Client stops reading the responses from the server while the channel is full,
+// so it should be drained as long as the Subscription is in use.
+func (s *Subscription) Notifs() <-chan *datatypes.NotificationMessage {
+	return s.notifs
+}
+
+// WithPublishRequests sets the number of PublishRequests the Client keeps outstanding
+// while it has Subscriptions. It should be large enough for the server to publish
+// the Notifications of all the Subscriptions without waiting for the next PublishRequest.
+func WithPublishRequests(n int) Option {
+	return func(c *Client) {
+		c.publishRequests = n
+	}
+}
+
+// CreateSubscription creates a Subscription with the parameters given
+// and starts sending PublishRequests for it.
+func (c *Client) CreateSubscription(interval float64, lifetime, keepAlive, maxNotifs uint32, priority byte) (*Subscription, error) {
+	return c.CreateSubscriptionWithContext(context.Background(), interval, lifetime, keepAlive, maxNotifs, priority)
+}
+
+// CreateSubscriptionWithContext is the same as CreateSubscription but returns ctx.Err()
+// if ctx is done before the CreateSubscriptionResponse arrives.
+func (c *Client) CreateSubscriptionWithContext(ctx context.Context, interval float64, lifetime, keepAlive, maxNotifs uint32, priority byte) (*Subscription, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.session == nil {
+		return nil, ErrNotConnected
+	}
+
+	h := c.requestHeader()
+	req := services.NewCreateSubscriptionRequest(h, interval, lifetime, keepAlive, maxNotifs, true, priority)
+	res, err := c.send(ctx, req, h.RequestHandle)
+	if err != nil {
+		return nil, err
+	}
+
+	r, ok := res.(*services.CreateSubscriptionResponse)
+	if !ok {
+		return nil, errors.NewErrInvalidType(res, "create subscription", "should be CreateSubscriptionResponse")
+	}
+
+	sub := &Subscription{
+		ID:                        r.SubscriptionID,
+		RevisedPublishingInterval: r.RevisedPublishingInterval,
+		RevisedLifetimeCount:      r.RevisedLifetimeCount,
+		RevisedMaxKeepAliveCount:  r.RevisedMaxKeepAliveCount,
+		notifs:                    make(chan *datatypes.NotificationMessage, 16),
+	}
+
+	c.subMu.Lock()
+	if c.subs == nil {
+		c.subs = map[uint32]*Subscription{}
+	}
+	c.subs[sub.ID] = sub
+	c.subMu.Unlock()
+
+	c.publish()
+	return sub, nil
+}
+
+// DeleteSubscriptions deletes the Subscriptions with the subIDs and their MonitoredItems
+// on the server, and returns the StatusCode for each of them in the same order.
+//
+// The channels of the Subscriptions deleted are closed, as no more NotificationMessages arrive.
+// The error is only returned if the DeleteSubscriptionsRequest fails as a whole.
+func (c *Client) DeleteSubscriptions(subIDs []uint32) ([]uint32, error) {
+	return c.DeleteSubscriptionsWithContext(context.Background(), subIDs)
+}
+
+// DeleteSubscriptionsWithContext is the same as DeleteSubscriptions but returns ctx.Err()
+// if ctx is done before the DeleteSubscriptionsResponse arrives.
+func (c *Client) DeleteSubscriptionsWithContext(ctx context.Context, subIDs []uint32) ([]uint32, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.session == nil {
+		return nil, ErrNotConnected
+	}
+	return c.deleteSubscriptions(ctx, subIDs)
+}
+
+// deleteSubscriptions sends a DeleteSubscriptionsRequest for subIDs.
+//
+// The Subscriptions are closed by monitor when the response arrives, so that their
+// channels are not closed while handlePublish may deliver to them.
+//
+// This should be called with c.mu held.
+func (c *Client) deleteSubscriptions(ctx context.Context, subIDs []uint32) ([]uint32, error) {
+	h := c.requestHeader()
+	c.subMu.Lock()
+	if c.deleting == nil {
+		c.deleting = map[uint32][]uint32{}
+	}
+	c.deleting[h.RequestHandle] = subIDs
+	c.subMu.Unlock()
+
+	// not retried, as the Subscriptions to close are looked up by the RequestHandle.
+	res, err := c.sendOnce(ctx, services.NewDeleteSubscriptionsRequest(h, subIDs...), h.RequestHandle)
+
+	c.subMu.Lock()
+	delete(c.deleting, h.RequestHandle)
+	c.subMu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	r, ok := res.(*services.DeleteSubscriptionsResponse)
+	if !ok {
+		return nil, errors.NewErrInvalidType(res, "delete subscriptions", "should be DeleteSubscriptionsResponse")
+	}
+	return r.Results.Values, nil
+}
+
+// handleDeleteSubscriptions closes the Subscriptions deleted by the DeleteSubscriptionsResponse.
+// The ones unknown to the server are also closed, as they are already deleted.
+//
+// It is called by monitor before the response is passed to send().
+func (c *Client) handleDeleteSubscriptions(res *services.DeleteSubscriptionsResponse) {
+	c.subMu.Lock()
+	defer c.subMu.Unlock()
+
+	subIDs, ok := c.deleting[res.RequestHandle]
+	if !ok || res.ServiceResult != 0 || res.Results == nil {
+		return
+	}
+	delete(c.deleting, res.RequestHandle)
+
+	for i, code := range res.Results.Values {
+		if i >= len(subIDs) || (code != 0 && code != status.BadSubscriptionIdInvalid) {
+			continue
+		}
+		if sub, ok := c.subs[subIDs[i]]; ok {
+			close(sub.notifs)
+			delete(c.subs, subIDs[i])
+		}
+	}
+}
+
+// subscriptionIDs returns the IDs of the Subscriptions created by the Client.
+func (c *Client) subscriptionIDs() []uint32 {
+	c.subMu.Lock()
+	defer c.subMu.Unlock()
+
+	var ids []uint32
+	for id := range c.subs {
+		ids = append(ids, id)
+	}
+	return ids
+}
+
+// CreateMonitoredItems creates the MonitoredItems in the Subscription with subID, e.g. the ones
+// created with datatypes.NewEventMonitoredItemCreateRequest to report the Events, and returns
+// the results with the parameters revised by the server for each of them in the same order.
+//
+// The error is only returned if the CreateMonitoredItemsRequest fails as a whole.
+func (c *Client) CreateMonitoredItems(subID uint32, ts services.TimestampsToReturn, items []*datatypes.MonitoredItemCreateRequest) ([]*datatypes.MonitoredItemCreateResult, error) {
+	return c.CreateMonitoredItemsWithContext(context.Background(), subID, ts, items)
+}
+
+// CreateMonitoredItemsWithContext is the same as CreateMonitoredItems but returns ctx.Err()
+// if ctx is done before the CreateMonitoredItemsResponse arrives.
+func (c *Client) CreateMonitoredItemsWithContext(ctx context.Context, subID uint32, ts services.TimestampsToReturn, items []*datatypes.MonitoredItemCreateRequest) ([]*datatypes.MonitoredItemCreateResult, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.session == nil {
+		return nil, ErrNotConnected
+	}
+
+	h := c.requestHeader()
+	res, err := c.send(ctx, services.NewCreateMonitoredItemsRequest(h, subID, ts, items...), h.RequestHandle)
+	if err != nil {
+		return nil, err
+	}
+
+	r, ok := res.(*services.CreateMonitoredItemsResponse)
+	if !ok {
+		return nil, errors.NewErrInvalidType(res, "create monitored items", "should be CreateMonitoredItemsResponse")
+	}
+	return r.Results.Results, nil
+}
+
+// DeleteMonitoredItems deletes the MonitoredItems with the itemIDs in the Subscription
+// with subID, and returns the StatusCode for each of them in the same order.
+//
+// The error is only returned if the DeleteMonitoredItemsRequest fails as a whole.
+func (c *Client) DeleteMonitoredItems(subID uint32, itemIDs []uint32) ([]uint32, error) {
+	return c.DeleteMonitoredItemsWithContext(context.Background(), subID, itemIDs)
+}
+
+// DeleteMonitoredItemsWithContext is the same as DeleteMonitoredItems but returns ctx.Err()
+// if ctx is done before the DeleteMonitoredItemsResponse arrives.
+func (c *Client) DeleteMonitoredItemsWithContext(ctx context.Context, subID uint32, itemIDs []uint32) ([]uint32, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.session == nil {
+		return nil, ErrNotConnected
+	}
+
+	h := c.requestHeader()
+	res, err := c.send(ctx, services.NewDeleteMonitoredItemsRequest(h, subID, itemIDs...), h.RequestHandle)
+	if err != nil {
+		return nil, err
+	}
+
+	r, ok := res.(*services.DeleteMonitoredItemsResponse)
+	if !ok {
+		return nil, errors.NewErrInvalidType(res, "delete monitored items", "should be DeleteMonitoredItemsResponse")
+	}
+	return r.Results.Values, nil
+}
+
+// ModifyMonitoredItems modifies the parameters of the MonitoredItems in the Subscription
+// with subID, e.g. the sampling interval and the queue size, and returns the results with
+// the parameters revised by the server for each of them in the same order.
+//
+// The Notifications of the MonitoredItems modified have both the source and the server timestamps.
+// The error is only returned if the ModifyMonitoredItemsRequest fails as a whole.
+func (c *Client) ModifyMonitoredItems(subID uint32, items []*datatypes.MonitoredItemModifyRequest) ([]*datatypes.MonitoredItemModifyResult, error) {
+	return c.ModifyMonitoredItemsWithContext(context.Background(), subID, items)
+}
+
+// ModifyMonitoredItemsWithContext is the same as ModifyMonitoredItems but returns ctx.Err()
+// if ctx is done before the ModifyMonitoredItemsResponse arrives.
+func (c *Client) ModifyMonitoredItemsWithContext(ctx context.Context, subID uint32, items []*datatypes.MonitoredItemModifyRequest) ([]*datatypes.MonitoredItemModifyResult, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.session == nil {
+		return nil, ErrNotConnected
+	}
+
+	h := c.requestHeader()
+	req := services.NewModifyMonitoredItemsRequest(h, subID, services.TimestampsToReturnBoth, items...)
+	res, err := c.send(ctx, req, h.RequestHandle)
+	if err != nil {
+		return nil, err
+	}
+
+	r, ok := res.(*services.ModifyMonitoredItemsResponse)
+	if !ok {
+		return nil, errors.NewErrInvalidType(res, "modify monitored items", "should be ModifyMonitoredItemsResponse")
+	}
+	return r.Results.Results, nil
+}
+
+// SetTriggering links the MonitoredItems with linksToAdd to the triggering item with
+// triggeringItemID in the Subscription with subID, and unlinks the ones with linksToRemove.
+// The items linked report their Notifications only when the triggering item reports,
+// even in the sampling mode.
+//
+// The StatusCode for each link is in the AddResults and the RemoveResults of the
+// SetTriggeringResponse in the same order. The error is only returned if the
+// SetTriggeringRequest fails as a whole.
+func (c *Client) SetTriggering(subID, triggeringItemID uint32, linksToAdd, linksToRemove []uint32) (*services.SetTriggeringResponse, error) {
+	return c.SetTriggeringWithContext(context.Background(), subID, triggeringItemID, linksToAdd, linksToRemove)
+}
+
+// SetTriggeringWithContext is the same as SetTriggering but returns ctx.Err()
+// if ctx is done before the SetTriggeringResponse arrives.
+func (c *Client) SetTriggeringWithContext(ctx context.Context, subID, triggeringItemID uint32, linksToAdd, linksToRemove []uint32) (*services.SetTriggeringResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.session == nil {
+		return nil, ErrNotConnected
+	}
+
+	h := c.requestHeader()
+	req := services.NewSetTriggeringRequest(h, subID, triggeringItemID, linksToAdd, linksToRemove)
+	res, err := c.send(ctx, req, h.RequestHandle)
+	if err != nil {
+		return nil, err
+	}
+
+	r, ok := res.(*services.SetTriggeringResponse)
+	if !ok {
+		return nil, errors.NewErrInvalidType(res, "set triggering", "should be SetTriggeringResponse")
+	}
+	return r, nil
+}
+
+// publish sends PublishRequests until the configured number of them are outstanding.
+// The NotificationMessages received since the last PublishRequest are acknowledged
+// in the first one.
+//
+// This should be called with c.mu held.
+func (c *Client) publish() {
+	c.subMu.Lock()
+	defer c.subMu.Unlock()
+
+	for len(c.subs) > 0 && c.outstanding < c.publishRequests {
+		// the PublishRequests are queued by the server until it has the notifications to
+		// send, which can take longer than any timeout of the Client. So they are sent
+		// with no TimeoutHint, and they are not timed out locally either.
+		h := c.requestHeader()
+		h.TimeoutHint = 0
+		b, err := services.NewPublishRequest(h, c.acks...).Serialize()
+		if err != nil {
+			return
+		}
+		if _, err := c.session.WriteService(b); err != nil {
+			c.log().Warn("failed to send PublishRequest", "error", err)
+			return
+		}
+		c.log().Debug("sent PublishRequest", "acknowledgements", len(c.acks))
+		c.acks = nil
+		c.outstanding++
+	}
+}
+
+// handlePublish delivers the NotificationMessage in the PublishResponse to its Subscription
+// and sends the PublishRequests to replace the one consumed.
+//
+// It is called by monitor, so the PublishRequests are sent in another goroutine
+// to avoid blocking on c.mu held by send() waiting for a response.
+func (c *Client) handlePublish(res *services.PublishResponse) {
+	c.subMu.Lock()
+	if c.outstanding > 0 {
+		c.outstanding--
+	}
+
+	var sub *Subscription
+	msg := res.NotificationMessage
+	c.log().Debug("received PublishResponse", "subscription_id", res.SubscriptionID, "status", res.ServiceResult)
+	if res.ServiceResult == 0 && msg != nil && !msg.IsKeepAlive() {
+		if sub = c.subs[res.SubscriptionID]; sub != nil {
+			c.acks = append(c.acks, datatypes.NewSubscriptionAcknowledgement(res.SubscriptionID, msg.SequenceNumber))
+		}
+	}
+
+	var delay time.Duration
+	switch res.ServiceResult {
+	// the server has no Subscription to publish anymore.
+	case status.BadNoSubscription:
+		for _, lost := range c.subs {
+			close(lost.notifs)
+		}
+		c.subs = nil
+	// the server cannot queue as many PublishRequests as configured,
+	// so the ones still outstanding are kept as the limit, which is at least one.
+	case status.BadTooManyPublishRequests:
+		c.publishRequests = c.outstanding
+		if c.publishRequests < 1 {
+			c.publishRequests = 1
+			// the server rejects even the only one, which is sent again after a while
+			// not to flood the server.
+			delay = publishRetryDelay
+		}
+	}
+	c.subMu.Unlock()
+
+	if sub != nil {
+		sub.notifs <- msg
+	}
+
+	go func() {
+		time.Sleep(delay)
+
+		c.mu.Lock()
+		defer c.mu.Unlock()
+
+		if c.session != nil {
+			c.publish()
+		}
+	}()
+}
+
+// closeSubscriptions closes the channels of all the Subscriptions when
+// the Session is closed, as no more NotificationMessages arrive.
+func (c *Client) closeSubscriptions() {
+	c.subMu.Lock()
+	defer c.subMu.Unlock()
+
+	for _, sub := range c.subs {
+		close(sub.notifs)
+	}
+	c.subs, c.acks, c.outstanding, c.deleting = nil, nil, 0, nil
+}
@@ -0,0 +1,431 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package gopcua
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/wmnsk/gopcua/datatypes"
+	"github.com/wmnsk/gopcua/id"
+	"github.com/wmnsk/gopcua/services"
+	"github.com/wmnsk/gopcua/status"
+)
+
+func newDataChangeResponse(handle, subID, seq uint32, v datatypes.Data) *services.PublishResponse {
+	msg := datatypes.NewNotificationMessage(
+		seq, time.Now(),
+		datatypes.NewDataChangeNotification(datatypes.NewMonitoredItemNotification(1, newValue(v))),
+	)
+	return services.NewPublishResponse(newResponseHeader(handle), subID, []uint32{seq}, false, msg, nil)
+}
+
+func TestClientSubscription(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// the server pushes two data changes with a keep-alive in between,
+	// and holds the PublishRequests after that.
+	const subID = 7
+	pubs := make(chan *services.PublishRequest, 16)
+	var published int
+	c, err := setUpClient(ctx, func(srv services.Service) services.Service {
+		switch req := srv.(type) {
+		case *services.CreateSubscriptionRequest:
+			return services.NewCreateSubscriptionResponse(newResponseHeader(req.RequestHandle), subID, 100, 60, 20)
+		case *services.PublishRequest:
+			pubs <- req
+			published++
+			switch published {
+			case 1:
+				return newDataChangeResponse(req.RequestHandle, subID, 1, datatypes.NewFloat(10))
+			case 2:
+				msg := datatypes.NewNotificationMessage(2, time.Now())
+				return services.NewPublishResponse(newResponseHeader(req.RequestHandle), subID, nil, false, msg, nil)
+			case 3:
+				return newDataChangeResponse(req.RequestHandle, subID, 2, datatypes.NewFloat(20))
+			}
+		}
+		return nil
+	}, WithPublishRequests(2))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	sub, err := c.CreateSubscription(100, 60, 20, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sub.ID != subID {
+		t.Errorf("got subscription %d want %d", sub.ID, subID)
+	}
+
+	for _, want := range []float32{10, 20} {
+		select {
+		case msg := <-sub.Notifs():
+			d, ok := msg.NotificationData.ExtensionObjects[0].Value.(*datatypes.DataChangeNotification)
+			if !ok {
+				t.Fatalf("got %T, want DataChangeNotification", msg.NotificationData.ExtensionObjects[0].Value)
+			}
+			got := d.MonitoredItems.Notifications[0].Value.Value.Value
+			if diff := cmp.Diff(got, datatypes.NewFloat(want)); diff != "" {
+				t.Error(diff)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("notification %v was not delivered", want)
+		}
+	}
+
+	// both sequence numbers are acknowledged, but not the keep-alive, and the
+	// PublishRequests are sent again as the server consumes them.
+	acked := map[uint32]int{}
+	for n := 0; acked[1] == 0 || acked[2] == 0; n++ {
+		select {
+		case req := <-pubs:
+			// the PublishRequests are held by the server as long as it needs.
+			if req.TimeoutHint != 0 {
+				t.Errorf("got TimeoutHint %d want 0", req.TimeoutHint)
+			}
+			for _, ack := range req.SubscriptionAcknowledgements.Acknowledgements {
+				if ack.SubscriptionID != subID {
+					t.Errorf("got ack for subscription %d want %d", ack.SubscriptionID, subID)
+				}
+				acked[ack.SequenceNumber]++
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("sequence numbers were not acknowledged after %d PublishRequests: %v", n, acked)
+		}
+	}
+	if acked[1] != 1 || acked[2] != 1 || len(acked) != 2 {
+		t.Errorf("got acks %v, want each of 1 and 2 once", acked)
+	}
+}
+
+func TestClientSubscriptionLost(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// the server reports it has no Subscription to publish.
+	const subID = 7
+	c, err := setUpClient(ctx, func(srv services.Service) services.Service {
+		switch req := srv.(type) {
+		case *services.CreateSubscriptionRequest:
+			return services.NewCreateSubscriptionResponse(newResponseHeader(req.RequestHandle), subID, 100, 60, 20)
+		case *services.PublishRequest:
+			h := newResponseHeader(req.RequestHandle)
+			h.ServiceResult = status.BadNoSubscription
+			return services.NewPublishResponse(h, 0, nil, false, datatypes.NewNotificationMessage(0, time.Now()), nil)
+		}
+		return nil
+	}, WithPublishRequests(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	sub, err := c.CreateSubscription(100, 60, 20, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case msg, ok := <-sub.Notifs():
+		if ok {
+			t.Errorf("got %v, want Notifs closed", msg)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Notifs were not closed")
+	}
+}
+
+func TestClientTooManyPublishRequests(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// the server rejects all the PublishRequests.
+	const subID = 7
+	pubs := make(chan struct{}, 1024)
+	c, err := setUpClient(ctx, func(srv services.Service) services.Service {
+		switch req := srv.(type) {
+		case *services.CreateSubscriptionRequest:
+			return services.NewCreateSubscriptionResponse(newResponseHeader(req.RequestHandle), subID, 100, 60, 20)
+		case *services.PublishRequest:
+			pubs <- struct{}{}
+			h := newResponseHeader(req.RequestHandle)
+			h.ServiceResult = status.BadTooManyPublishRequests
+			return services.NewPublishResponse(h, 0, nil, false, datatypes.NewNotificationMessage(0, time.Now()), nil)
+		}
+		return nil
+	}, WithPublishRequests(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	if _, err := c.CreateSubscription(100, 60, 20, 0, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	// the ones configured are sent first, and the others are sent after the delay.
+	time.Sleep(publishRetryDelay / 2)
+	if got := len(pubs); got > 3 {
+		t.Errorf("got %d PublishRequests sent, want at most 3", got)
+	}
+
+	c.subMu.Lock()
+	defer c.subMu.Unlock()
+	if c.publishRequests != 1 {
+		t.Errorf("got %d PublishRequests to keep outstanding, want 1", c.publishRequests)
+	}
+}
+
+func TestClientDeleteSubscriptions(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	const subID = 7
+	c, err := setUpClient(ctx, func(srv services.Service) services.Service {
+		switch req := srv.(type) {
+		case *services.CreateSubscriptionRequest:
+			return services.NewCreateSubscriptionResponse(newResponseHeader(req.RequestHandle), subID, 100, 60, 20)
+		case *services.DeleteSubscriptionsRequest:
+			return services.NewDeleteSubscriptionsResponse(newResponseHeader(req.RequestHandle), nil, 0, status.BadSubscriptionIdInvalid)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	sub, err := c.CreateSubscription(100, 60, 20, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results, err := c.DeleteSubscriptions([]uint32{subID, 8})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(results, []uint32{0, status.BadSubscriptionIdInvalid}); diff != "" {
+		t.Error(diff)
+	}
+
+	select {
+	case _, ok := <-sub.Notifs():
+		if ok {
+			t.Error("got notification want closed channel")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("channel of the deleted subscription was not closed")
+	}
+}
+
+func TestClientCloseDeletesSubscriptions(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	deleted := make(chan []uint32, 1)
+	c, err := setUpClient(ctx, func(srv services.Service) services.Service {
+		switch req := srv.(type) {
+		case *services.CreateSubscriptionRequest:
+			return services.NewCreateSubscriptionResponse(newResponseHeader(req.RequestHandle), 7, 100, 60, 20)
+		case *services.DeleteSubscriptionsRequest:
+			deleted <- req.SubscriptionIDs.Values
+			return services.NewDeleteSubscriptionsResponse(newResponseHeader(req.RequestHandle), nil, 0)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := c.CreateSubscription(100, 60, 20, 0, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case ids := <-deleted:
+		if diff := cmp.Diff(ids, []uint32{7}); diff != "" {
+			t.Error(diff)
+		}
+	default:
+		t.Fatal("Close did not delete the subscription")
+	}
+}
+
+func TestClientCreateMonitoredItems(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	reqs := make(chan *services.CreateMonitoredItemsRequest, 1)
+	c, err := setUpClient(ctx, func(srv services.Service) services.Service {
+		if req, ok := srv.(*services.CreateMonitoredItemsRequest); ok {
+			reqs <- req
+			return services.NewCreateMonitoredItemsResponse(
+				newResponseHeader(req.RequestHandle), nil,
+				datatypes.NewMonitoredItemCreateResult(0, 3, 0, 10, nil),
+			)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	filter := datatypes.NewEventFilter(nil, datatypes.NewEventFieldOperand("Message"), datatypes.NewEventFieldOperand("Severity"))
+	results, err := c.CreateMonitoredItems(7, services.TimestampsToReturnBoth, []*datatypes.MonitoredItemCreateRequest{
+		datatypes.NewEventMonitoredItemCreateRequest(datatypes.NewFourByteNodeID(0, id.Server), 1, filter, 10),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(results, []*datatypes.MonitoredItemCreateResult{
+		datatypes.NewMonitoredItemCreateResult(0, 3, 0, 10, nil),
+	}); diff != "" {
+		t.Error(diff)
+	}
+
+	req := <-reqs
+	if req.SubscriptionID != 7 {
+		t.Errorf("got SubscriptionID %d want 7", req.SubscriptionID)
+	}
+	item := req.ItemsToCreate.Items[0]
+	if got := item.ItemToMonitor.AttributeID; got != datatypes.AttributeIDEventNotifier {
+		t.Errorf("got AttributeID %d want EventNotifier", got)
+	}
+	if diff := cmp.Diff(item.RequestedParameters.Filter.Value, filter); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestClientDeleteMonitoredItems(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	reqs := make(chan *services.DeleteMonitoredItemsRequest, 1)
+	c, err := setUpClient(ctx, func(srv services.Service) services.Service {
+		if req, ok := srv.(*services.DeleteMonitoredItemsRequest); ok {
+			reqs <- req
+			return services.NewDeleteMonitoredItemsResponse(newResponseHeader(req.RequestHandle), nil, 0, status.BadMonitoredItemIdInvalid)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	results, err := c.DeleteMonitoredItems(7, []uint32{1, 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(results, []uint32{0, status.BadMonitoredItemIdInvalid}); diff != "" {
+		t.Error(diff)
+	}
+	got := <-reqs
+	if got.SubscriptionID != 7 {
+		t.Errorf("got SubscriptionID %d want 7", got.SubscriptionID)
+	}
+	if diff := cmp.Diff(got.MonitoredItemIDs.Values, []uint32{1, 2}); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestClientSetTriggering(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	reqs := make(chan *services.SetTriggeringRequest, 1)
+	c, err := setUpClient(ctx, func(srv services.Service) services.Service {
+		if req, ok := srv.(*services.SetTriggeringRequest); ok {
+			reqs <- req
+			return services.NewSetTriggeringResponse(
+				newResponseHeader(req.RequestHandle),
+				[]uint32{0, status.BadMonitoredItemIdInvalid}, []uint32{0}, nil, nil,
+			)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	res, err := c.SetTriggering(7, 1, []uint32{2, 3}, []uint32{4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(res.AddResults.Values, []uint32{0, status.BadMonitoredItemIdInvalid}); diff != "" {
+		t.Error(diff)
+	}
+	if diff := cmp.Diff(res.RemoveResults.Values, []uint32{0}); diff != "" {
+		t.Error(diff)
+	}
+
+	got := <-reqs
+	if got.SubscriptionID != 7 || got.TriggeringItemID != 1 {
+		t.Errorf("got SubscriptionID %d TriggeringItemID %d want 7, 1", got.SubscriptionID, got.TriggeringItemID)
+	}
+	if diff := cmp.Diff(got.LinksToAdd.Values, []uint32{2, 3}); diff != "" {
+		t.Error(diff)
+	}
+	if diff := cmp.Diff(got.LinksToRemove.Values, []uint32{4}); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestClientModifyMonitoredItems(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	reqs := make(chan *services.ModifyMonitoredItemsRequest, 1)
+	c, err := setUpClient(ctx, func(srv services.Service) services.Service {
+		if req, ok := srv.(*services.ModifyMonitoredItemsRequest); ok {
+			reqs <- req
+			return services.NewModifyMonitoredItemsResponse(
+				newResponseHeader(req.RequestHandle), nil,
+				datatypes.NewMonitoredItemModifyResult(0, 4000, 5, nil),
+			)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	results, err := c.ModifyMonitoredItems(7, []*datatypes.MonitoredItemModifyRequest{
+		datatypes.NewMonitoredItemModifyRequest(3, datatypes.NewMonitoringParameters(1, 5000, nil, 5, true)),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(results, []*datatypes.MonitoredItemModifyResult{
+		datatypes.NewMonitoredItemModifyResult(0, 4000, 5, nil),
+	}); diff != "" {
+		t.Error(diff)
+	}
+
+	req := <-reqs
+	if req.SubscriptionID != 7 {
+		t.Errorf("got SubscriptionID %d want 7", req.SubscriptionID)
+	}
+	item := req.ItemsToModify.Items[0]
+	if item.MonitoredItemID != 3 {
+		t.Errorf("got MonitoredItemID %d want 3", item.MonitoredItemID)
+	}
+	if got := item.RequestedParameters.SamplingInterval; got != 5000 {
+		t.Errorf("got SamplingInterval %v want 5000", got)
+	}
+}
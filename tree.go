@@ -0,0 +1,141 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package gopcua
+
+import (
+	"context"
+
+	"github.com/wmnsk/gopcua/datatypes"
+	"github.com/wmnsk/gopcua/errors"
+)
+
+// TreeNode is a Node in the snapshot of the address space returned by BrowseTree,
+// with its attributes and the children found by following the HierarchicalReferences.
+type TreeNode struct {
+	NodeID      *datatypes.NodeID
+	BrowseName  *datatypes.QualifiedName
+	DisplayName *datatypes.LocalizedText
+	NodeClass   datatypes.NodeClass
+	Children    []*TreeNode
+}
+
+// Child returns the child with the name of the BrowseName given, or nil if not found.
+func (n *TreeNode) Child(name string) *TreeNode {
+	for _, c := range n.Children {
+		if c.BrowseName != nil && c.BrowseName.Name != nil && c.BrowseName.Name.Get() == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// Walk calls fn for the TreeNode and all its descendants in depth-first order, with the
+// depth from the TreeNode, which is 0 for itself. It stops when fn returns an error.
+func (n *TreeNode) Walk(fn func(n *TreeNode, depth int) error) error {
+	return n.walk(fn, 0)
+}
+
+func (n *TreeNode) walk(fn func(n *TreeNode, depth int) error, depth int) error {
+	if err := fn(n, depth); err != nil {
+		return err
+	}
+	for _, c := range n.Children {
+		if err := c.walk(fn, depth+1); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BrowseTree browses the forward HierarchicalReferences from root breadth-first up to
+// maxDepth levels and returns the snapshot of the subtree. Only root is returned if
+// maxDepth is 0.
+//
+// A Node reachable by multiple paths, including the cycles back to its ancestors, appears
+// only once in the tree, as the child of the first one browsed at the shallowest depth.
+// The targets in other servers are skipped as they cannot be browsed with the Client.
+func (c *Client) BrowseTree(root *datatypes.NodeID, maxDepth int) (*TreeNode, error) {
+	return c.BrowseTreeWithContext(context.Background(), root, maxDepth)
+}
+
+// BrowseTreeWithContext is the same as BrowseTree but stops and returns ctx.Err() when ctx is done.
+func (c *Client) BrowseTreeWithContext(ctx context.Context, root *datatypes.NodeID, maxDepth int) (*TreeNode, error) {
+	tree, err := c.readTreeNode(ctx, root)
+	if err != nil {
+		return nil, err
+	}
+
+	visited := map[string]bool{root.String(): true}
+	level := []*TreeNode{tree}
+	for depth := 0; depth < maxDepth && len(level) > 0; depth++ {
+		var next []*TreeNode
+		for _, parent := range level {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+
+			refs, err := c.BrowseChildrenWithContext(ctx, parent.NodeID)
+			if err != nil {
+				return nil, err
+			}
+			for _, ref := range refs {
+				if ref.NodeID == nil || (ref.NodeID.HasServerIndex() && ref.NodeID.ServerIndex != 0) {
+					continue
+				}
+				id, err := c.ToNodeID(ref.NodeID)
+				if err != nil {
+					return nil, err
+				}
+				if visited[id.String()] {
+					continue
+				}
+				visited[id.String()] = true
+
+				child := &TreeNode{
+					NodeID:      id,
+					BrowseName:  ref.BrowseName,
+					DisplayName: ref.DisplayName,
+					NodeClass:   ref.NodeClass,
+				}
+				parent.Children = append(parent.Children, child)
+				next = append(next, child)
+			}
+		}
+		level = next
+	}
+
+	return tree, nil
+}
+
+// readTreeNode reads the attributes of the Node to be the root of BrowseTree.
+func (c *Client) readTreeNode(ctx context.Context, node *datatypes.NodeID) (*TreeNode, error) {
+	n := &TreeNode{NodeID: node}
+
+	v, err := c.readAttribute(ctx, node, datatypes.AttributeIDNodeClass)
+	if err != nil {
+		return nil, err
+	}
+	nc, ok := v.Int32()
+	if !ok {
+		return nil, errors.Errorf("read returned %T, not NodeClass", v.Value)
+	}
+	n.NodeClass = datatypes.NodeClass(nc)
+
+	if v, err = c.readAttribute(ctx, node, datatypes.AttributeIDBrowseName); err != nil {
+		return nil, err
+	}
+	if n.BrowseName, ok = v.QualifiedName(); !ok {
+		return nil, errors.Errorf("read returned %T, not QualifiedName", v.Value)
+	}
+
+	if v, err = c.readAttribute(ctx, node, datatypes.AttributeIDDisplayName); err != nil {
+		return nil, err
+	}
+	if n.DisplayName, ok = v.LocalizedText(); !ok {
+		return nil, errors.Errorf("read returned %T, not LocalizedText", v.Value)
+	}
+
+	return n, nil
+}
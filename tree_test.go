@@ -0,0 +1,144 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package gopcua
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/wmnsk/gopcua/datatypes"
+	"github.com/wmnsk/gopcua/services"
+)
+
+func TestClientBrowseTree(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	objects := datatypes.NewFourByteNodeID(0, 85)
+	folder := datatypes.NewStringNodeID(1, "Folder")
+	temp := datatypes.NewStringNodeID(1, "Temperature")
+
+	ref := func(n *datatypes.NodeID, name string, nc datatypes.NodeClass) *datatypes.ReferenceDescription {
+		return datatypes.NewReferenceDescription(
+			datatypes.NewFourByteNodeID(0, 35), true,
+			&datatypes.ExpandedNodeID{NodeID: n},
+			datatypes.NewQualifiedName(uint16(n.Namespace()), name),
+			datatypes.NewLocalizedText("", name),
+			nc, datatypes.NewFourByteExpandedNodeID(0, 61),
+		)
+	}
+	// the Folder and the Temperature refer to each other and back to the Objects.
+	graph := map[string][]*datatypes.ReferenceDescription{
+		objects.String(): {
+			ref(folder, "Folder", datatypes.NodeClassObject),
+		},
+		folder.String(): {
+			ref(temp, "Temperature", datatypes.NodeClassVariable),
+			ref(objects, "Objects", datatypes.NodeClassObject),
+			datatypes.NewReferenceDescription(
+				datatypes.NewFourByteNodeID(0, 35), true,
+				datatypes.NewExpandedNodeIDWithServer(datatypes.NewStringNodeID(1, "remote"), 1),
+				datatypes.NewQualifiedName(1, "Remote"),
+				datatypes.NewLocalizedText("", "Remote"),
+				datatypes.NodeClassObject,
+				datatypes.NewFourByteExpandedNodeID(0, 61),
+			),
+		},
+		temp.String(): {
+			ref(folder, "Folder", datatypes.NodeClassObject),
+		},
+	}
+
+	var mu sync.Mutex
+	var browsed []string
+	c, err := setUpClient(ctx, func(srv services.Service) services.Service {
+		mu.Lock()
+		defer mu.Unlock()
+
+		switch req := srv.(type) {
+		case *services.ReadRequest:
+			var v datatypes.Data
+			switch req.NodesToRead.ReadValueIDs[0].AttributeID {
+			case datatypes.AttributeIDNodeClass:
+				v = datatypes.NewInt32(int32(datatypes.NodeClassObject))
+			case datatypes.AttributeIDBrowseName:
+				v = datatypes.NewQualifiedName(0, "Objects")
+			case datatypes.AttributeIDDisplayName:
+				v = datatypes.NewLocalizedText("", "Objects")
+			}
+			return services.NewReadResponse(newResponseHeader(req.RequestHandle), nil, newValue(v))
+		case *services.BrowseRequest:
+			node := req.NodesToBrowse.BrowseDescriptions[0].NodeID.String()
+			browsed = append(browsed, node)
+			return services.NewBrowseResponse(newResponseHeader(req.RequestHandle), nil, datatypes.NewBrowseResult(
+				0, nil, graph[node]...,
+			))
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer c.Close()
+
+	t.Run("cyclic", func(t *testing.T) {
+		mu.Lock()
+		browsed = nil
+		mu.Unlock()
+
+		tree, err := c.BrowseTree(objects, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var got []string
+		tree.Walk(func(n *TreeNode, depth int) error {
+			got = append(got, n.NodeID.String())
+			return nil
+		})
+		want := []string{objects.String(), folder.String(), temp.String()}
+		if diff := cmp.Diff(got, want); diff != "" {
+			t.Error(diff)
+		}
+		if n := tree.Child("Folder").Child("Temperature"); n == nil || n.NodeClass != datatypes.NodeClassVariable {
+			t.Errorf("got %v want Temperature Variable", n)
+		}
+		if got, want := tree.DisplayName.Text.Get(), "Objects"; got != want {
+			t.Errorf("got DisplayName %s want %s", got, want)
+		}
+
+		// each Node is browsed only once.
+		mu.Lock()
+		defer mu.Unlock()
+		if diff := cmp.Diff(browsed, want); diff != "" {
+			t.Error(diff)
+		}
+	})
+
+	t.Run("max-depth", func(t *testing.T) {
+		tree, err := c.BrowseTree(objects, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		folder := tree.Child("Folder")
+		if folder == nil {
+			t.Fatal("Folder not found")
+		}
+		if len(folder.Children) != 0 {
+			t.Errorf("got %d children of Folder want 0", len(folder.Children))
+		}
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(ctx)
+		cancel()
+		if _, err := c.BrowseTreeWithContext(ctx, objects, 10); err != context.Canceled {
+			t.Errorf("got error %v want %v", err, context.Canceled)
+		}
+	})
+}
@@ -13,66 +13,194 @@ import (
 	"github.com/wmnsk/gopcua/utils"
 )
 
+// Dialer establishes the connection to the address on the network, e.g. "tcp" and
+// "plc.example.com:4840". It is satisfied by *net.Dialer and the golang.org/x/net/proxy.Dialer,
+// such as the one returned by proxy.SOCKS5.
+type Dialer interface {
+	Dial(network, addr string) (net.Conn, error)
+}
+
+// dialTCP connects to the address of endpoint with d, or net.Dial if d is nil.
+// The host is not resolved beforehand with d, so that the proxy can resolve it.
+// If d also has DialContext, e.g. *net.Dialer, it is used to give up connecting when ctx is done.
+func dialTCP(ctx context.Context, endpoint string, d Dialer) (net.Conn, error) {
+	if d == nil {
+		network, raddr, err := utils.ResolveEndpoint(endpoint)
+		if err != nil {
+			return nil, err
+		}
+		return net.Dial(network, raddr.String())
+	}
+
+	network, addr, err := utils.EndpointAddress(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if cd, ok := d.(interface {
+		DialContext(ctx context.Context, network, addr string) (net.Conn, error)
+	}); ok {
+		return cd.DialContext(ctx, network, addr)
+	}
+	return d.Dial(network, addr)
+}
+
 // Dial acts like net.Dial for OPC UA Connection Protocol network.
 //
-// Currently the endpoint can only be specified in "opc.tcp://<addr[:port]>/path" format.
+// The endpoint is specified in "opc.tcp://<addr[:port]>/path" format. With the scheme
+// "opc.wss" or "opc.ws", the connection is upgraded to WebSocket with and without TLS
+// respectively, on which the UACP messages are sent in the binary frames.
 //
 // The first param ctx is to be passed to monitor(), which monitors and handles
 // incoming messages automatically in another goroutine.
 //
-// If port is missing, ":4840" is automatically chosen.
+// If port is missing, ":4840" is automatically chosen, or ":443" and ":80" for WebSocket.
 // If laddr is nil, a local address is automatically chosen.
+//
+// The connection is established with DefaultLimits.
 func Dial(ctx context.Context, endpoint string) (*Conn, error) {
-	return dial(ctx, endpoint, 5*time.Second, 3)
+	return dial(ctx, endpoint, DefaultLimits, 5*time.Second, 3)
 }
 
 // DialTimeout is Dial with retransmission interval and max retransmission count.
 func DialTimeout(ctx context.Context, endpoint string, interval time.Duration, maxRetry int) (*Conn, error) {
-	return dial(ctx, endpoint, interval, maxRetry)
+	return dial(ctx, endpoint, DefaultLimits, interval, maxRetry)
+}
+
+// DialWithLimits is Dial with the local Limits sent in Hello.
+//
+// The buffers in the Acknowledge are clamped to limits, and the connection fails
+// with ErrInvalidLimits if they are larger than the ones in the Hello.
+func DialWithLimits(ctx context.Context, endpoint string, limits Limits) (*Conn, error) {
+	return dial(ctx, endpoint, limits, 5*time.Second, 3)
+}
+
+// DialConn is Dial on the lowerConn given instead of the one connected to the endpoint,
+// e.g. an in-process transport in tests. The lowerConn is closed if the handshake fails.
+func DialConn(ctx context.Context, lowerConn net.Conn, endpoint string) (*Conn, error) {
+	return dialConn(ctx, lowerConn, endpoint, DefaultLimits, 5*time.Second, 3)
+}
+
+// DialWithDialer is Dial on the TCP connection established by d, e.g. the one through
+// a SOCKS5 proxy. The WebSocket upgrade and the Hello handshake are done on it as usual.
+func DialWithDialer(ctx context.Context, endpoint string, d Dialer) (*Conn, error) {
+	return dialWithDialer(ctx, endpoint, d, DefaultLimits, 5*time.Second, 3)
+}
+
+func dial(ctx context.Context, endpoint string, limits Limits, interval time.Duration, maxRetry int) (*Conn, error) {
+	return dialWithDialer(ctx, endpoint, nil, limits, interval, maxRetry)
+}
+
+func dialWithDialer(ctx context.Context, endpoint string, d Dialer, limits Limits, interval time.Duration, maxRetry int) (*Conn, error) {
+	lowerConn, err := dialTransport(ctx, endpoint, d)
+	if err != nil {
+		return nil, err
+	}
+	return dialConn(ctx, lowerConn, endpoint, limits, interval, maxRetry)
 }
 
-func dial(ctx context.Context, endpoint string, interval time.Duration, maxRetry int) (*Conn, error) {
-	network, raddr, err := utils.ResolveEndpoint(endpoint)
+func dialConn(ctx context.Context, lowerConn net.Conn, endpoint string, limits Limits, interval time.Duration, maxRetry int) (*Conn, error) {
+	conn := newClientConn(endpoint)
+	conn.limits = limits
+	conn.lowerConn = lowerConn
+
+	if err := conn.handshake(ctx, interval, maxRetry); err != nil {
+		conn.lowerConn.Close()
+		return nil, err
+	}
+	return conn, nil
+}
+
+// DialReverse connects to the client listening on the endpoint with reverse connect,
+// and returns the new connection established as a server.
+//
+// The ReverseHello sent first has the serverURI and the local endpoint, to which
+// the client is expected to send Hello on the same connection.
+func DialReverse(ctx context.Context, endpoint, serverURI, local string) (*Conn, error) {
+	lowerConn, err := dialTransport(ctx, endpoint, nil)
 	if err != nil {
 		return nil, err
 	}
 
 	conn := &Conn{
 		mu:          new(sync.Mutex),
-		state:       cliStateClosed,
+		state:       srvStateClosed,
 		established: make(chan bool),
-		lenChan:     make(chan int),
+		rcvChan:     make(chan []byte),
+		closed:      make(chan struct{}),
+		broken:      make(chan struct{}),
 		errChan:     make(chan error),
-		rcvBuf:      make([]byte, 0xffff),
-		sndBuf:      make([]byte, 0xffff),
-		rep:         endpoint,
+		limits:      DefaultLimits,
+		lep:         local,
+		lowerConn:   lowerConn,
 	}
-	conn.lowerConn, err = net.Dial(network, raddr.String())
+
+	rhe, err := NewReverseHello(serverURI, local).Serialize()
 	if err != nil {
+		conn.lowerConn.Close()
+		return nil, err
+	}
+	if _, err := conn.lowerConn.Write(rhe); err != nil {
+		conn.lowerConn.Close()
 		return nil, err
 	}
 
-	if err := conn.Hello(); err != nil {
+	go conn.monitor(ctx)
+	select {
+	case ok := <-conn.established:
+		if ok {
+			return conn, nil
+		}
+	case err := <-conn.errChan:
+		conn.lowerConn.Close()
 		return nil, err
+	case <-ctx.Done():
+		conn.lowerConn.Close()
+		return nil, ctx.Err()
+	}
+
+	conn.lowerConn.Close()
+	return nil, ErrConnNotEstablished
+}
+
+// newClientConn creates a client side Conn for the endpoint, of which lowerConn is to be set.
+func newClientConn(endpoint string) *Conn {
+	return &Conn{
+		mu:          new(sync.Mutex),
+		state:       cliStateClosed,
+		established: make(chan bool),
+		rcvChan:     make(chan []byte),
+		closed:      make(chan struct{}),
+		broken:      make(chan struct{}),
+		errChan:     make(chan error),
+		limits:      DefaultLimits,
+		rep:         endpoint,
+	}
+}
+
+// handshake sends Hello on the lowerConn of the client side Conn and waits for Acknowledge,
+// retransmitting Hello at the interval up to maxRetry times.
+func (c *Conn) handshake(ctx context.Context, interval time.Duration, maxRetry int) error {
+	if err := c.Hello(); err != nil {
+		return err
 	}
 	sent := 1
 
-	go conn.monitor(ctx)
+	go c.monitor(ctx)
 	for {
 		if sent > maxRetry {
-			return nil, ErrTimeout
+			return ErrTimeout
 		}
 
 		select {
-		case ok := <-conn.established:
+		case ok := <-c.established:
 			if ok {
-				return conn, nil
+				return nil
 			}
-		case err := <-conn.errChan:
-			return nil, err
+		case err := <-c.errChan:
+			return err
 		case <-time.After(interval):
-			if err := conn.Hello(); err != nil {
-				return nil, err
+			if err := c.Hello(); err != nil {
+				return err
 			}
 			sent++
 		}
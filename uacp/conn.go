@@ -6,6 +6,7 @@ package uacp
 
 import (
 	"context"
+	"encoding/binary"
 	"fmt"
 	"io"
 	"net"
@@ -24,18 +25,28 @@ type Conn struct {
 	lowerConn net.Conn
 	// lep and rep are Local/Remote Endpoint.
 	lep, rep string
-	// rcvBuf and sndBuf are the buffers to read/send.
-	// XXX - sndBuf is not used in the current implementation.
-	rcvBuf, sndBuf []byte
+	// serverURI is the ServerURI in the ReverseHello received on AcceptReverse.
+	serverURI string
+	// limits are the local Limits sent in Hello or Acknowledge, and negotiated are
+	// the ones agreed with the peer, which are zero until the connection is established.
+	limits, negotiated Limits
 	// state represents the state of connection.
 	state state
 	// established is to notify parents(Dial() and Accept()) of
 	// the result of connection establishment.
 	established chan bool
-	// lenChan is to notify user the length of received packets.
-	lenChan chan int
+	// rcvChan is to pass the received messages to user.
+	rcvChan chan []byte
+	// closed is closed when the connection is closed, to unblock Read and monitor.
+	closed chan struct{}
+	// broken is closed when the monitor stops on an error in reading from lowerConn,
+	// and readErr is the error returned by Read after that.
+	broken  chan struct{}
+	readErr error
 	// errChan is to pass errors to parents(Dial() and Accept()).
 	errChan chan error
+	// pending is the message which did not fit in the buffer given to the last Read.
+	pending []byte
 	// readDeadline time.Time
 	// writeDeadline time.Time
 }
@@ -46,20 +57,28 @@ type Conn struct {
 //
 // If the data is one of UACP messages, it will be handled automatically.
 // In other words, the data is passed when it is NOT one of Hello, Acknowledge, Error, ReverseHello.
+//
+// If b is too small to hold the message, Read returns io.ErrShortBuffer with the length
+// required, and the message is returned by the next Read.
 func (c *Conn) Read(b []byte) (n int, err error) {
-	if !(c.state == cliStateEstablished || c.state == srvStateEstablished) {
+	if c.pending != nil {
+		msg := c.pending
+		c.pending = nil
+		return c.readTo(b, msg)
+	}
+
+	if !c.isEstablished() {
 		return 0, ErrConnNotEstablished
 	}
 
 	for {
 		select {
-		case n, ok := <-c.lenChan:
-			if !ok {
-				return 0, ErrConnNotEstablished
-			}
-
-			copy(b, c.rcvBuf[:n])
-			return n, nil
+		case msg := <-c.rcvChan:
+			return c.readTo(b, msg)
+		case <-c.closed:
+			return 0, ErrConnNotEstablished
+		case <-c.broken:
+			return 0, c.readErr
 			/*
 				case <-time.After(c.readDeadline):
 					return 0, ErrTimeout
@@ -68,17 +87,33 @@ func (c *Conn) Read(b []byte) (n int, err error) {
 	}
 }
 
+// readTo copies msg to b, or keeps it for the next Read if b is too small.
+func (c *Conn) readTo(b, msg []byte) (int, error) {
+	if len(b) < len(msg) {
+		c.pending = msg
+		return len(msg), io.ErrShortBuffer
+	}
+	return copy(b, msg), nil
+}
+
 // Write writes data to the connection.
 // Write can be made to time out and return an Error with Timeout() == true
 // after a fixed time limit; see SetDeadline and SetWriteDeadline.
 func (c *Conn) Write(b []byte) (n int, err error) {
-	if !(c.state == cliStateEstablished || c.state == srvStateEstablished) {
+	if !c.isEstablished() {
 		return 0, ErrConnNotEstablished
 	}
 
 	return c.lowerConn.Write(b)
 }
 
+// isEstablished reports whether the connection is established.
+func (c *Conn) isEstablished() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.state == cliStateEstablished || c.state == srvStateEstablished
+}
+
 // Close closes the connection.
 // Any blocked Read or Write operations will be unblocked and return errors.
 func (c *Conn) Close() error {
@@ -102,12 +137,12 @@ func (c *Conn) Close() error {
 func (c *Conn) close() {
 	c.rep = ""
 	c.lep = ""
-	c.rcvBuf = []byte{}
-	c.sndBuf = []byte{}
+	c.negotiated = Limits{}
 
 	close(c.errChan)
-	close(c.lenChan)
+	close(c.closed)
 	close(c.established)
+	c.lowerConn.Close()
 }
 
 // LocalAddr returns the local network address.
@@ -132,6 +167,12 @@ func (c *Conn) RemoteEndpoint() string {
 	return c.rep
 }
 
+// ServerURI returns the ServerURI of the remote server, which is only known
+// for the connection accepted by AcceptReverse.
+func (c *Conn) ServerURI() string {
+	return c.serverURI
+}
+
 // SetDeadline sets the read and write deadlines associated
 // with the connection. It is equivalent to calling both
 // SetReadDeadline and SetWriteDeadline.
@@ -167,9 +208,26 @@ func (c *Conn) SetWriteDeadline(t time.Time) error {
 	return c.lowerConn.SetWriteDeadline(t)
 }
 
+// Limits returns the local Limits of Conn, which the MessageChunks received are checked against.
+func (c *Conn) Limits() Limits {
+	return c.limits
+}
+
+// Negotiated returns the Limits agreed with the peer in the Hello/Acknowledge exchange.
+// MaxMessageSize and MaxChunkCount are the limits of the Messages to send.
+//
+// The zero Limits is returned until the connection is established.
+func (c *Conn) Negotiated() Limits {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.negotiated
+}
+
 // Hello sends UACP Hello message to Conn.
 func (c *Conn) Hello() error {
-	hel, err := NewHello(0, uint32(len(c.rcvBuf)), uint32(len(c.sndBuf)), 0, c.rep).Serialize()
+	h := NewHello(0, c.limits.ReceiveBufSize, c.limits.SendBufSize, c.limits.MaxMessageSize, c.rep)
+	h.MaxChunkCount = c.limits.MaxChunkCount
+	hel, err := h.Serialize()
 	if err != nil {
 		return err
 	}
@@ -181,9 +239,11 @@ func (c *Conn) Hello() error {
 	return nil
 }
 
-// Acknowledge sends Acknowledge message to Conn.
+// Acknowledge sends Acknowledge message to Conn, with the buffer sizes negotiated.
 func (c *Conn) Acknowledge() error {
-	ack, err := NewAcknowledge(0, uint32(len(c.rcvBuf)), uint32(len(c.sndBuf)), 0).Serialize()
+	a := NewAcknowledge(0, c.negotiated.ReceiveBufSize, c.negotiated.SendBufSize, c.limits.MaxMessageSize)
+	a.MaxChunkCount = c.limits.MaxChunkCount
+	ack, err := a.Serialize()
 	if err != nil {
 		return err
 	}
@@ -252,22 +312,20 @@ func (c *Conn) monitor(ctx context.Context) {
 			cancel()
 			return
 		default:
-			n, err := c.lowerConn.Read(c.rcvBuf)
+			b, err := c.readMessage()
 			if err != nil {
-				if err == io.EOF {
-					continue
-				}
+				// the connection cannot be used anymore once the stream is broken,
+				// including io.EOF when the remote has closed it.
+				c.readErr = err
+				close(c.broken)
 				cancel()
 				return
 			}
-			if n == 0 {
-				continue
-			}
 
-			msg, err := Decode(c.rcvBuf[:n])
+			msg, err := Decode(b)
 			if err != nil {
 				// pass to the user if msg is undecodable as UACP.
-				go c.notifyLength(childCtx, n)
+				c.notify(childCtx, b)
 				continue
 			}
 			switch m := msg.(type) {
@@ -281,18 +339,59 @@ func (c *Conn) monitor(ctx context.Context) {
 				c.handleMsgReverseHello(m)
 			default:
 				// pass to the user if type of msg is unknown.
-				go c.notifyLength(childCtx, n)
+				c.notify(childCtx, b)
 			}
 		}
 	}
 }
 
-func (c *Conn) notifyLength(ctx context.Context, n int) {
+// readMessage reads exactly one message from lowerConn, so that the messages
+// coalesced or split by the lower layer are passed to the user one by one.
+//
+// The message larger than the ReceiveBufSize or the MaxMessageSize is rejected with
+// ErrMessageTooLarge before reading its body, as the stream cannot be used after that.
+func (c *Conn) readMessage() ([]byte, error) {
+	h := make([]byte, 8)
+	if _, err := io.ReadFull(c.lowerConn, h); err != nil {
+		return nil, err
+	}
+
+	size := int(binary.LittleEndian.Uint32(h[4:8]))
+	if size < len(h) {
+		return nil, errors.NewErrInvalidLength(size, "should be longer than the header")
+	}
+	if max := c.maxChunkSize(); max != 0 && uint32(size) > max {
+		c.Error(BadTCPMessageTooLarge, fmt.Sprintf("MessageSize %d exceeds %d", size, max))
+		return nil, ErrMessageTooLarge
+	}
+
+	b := make([]byte, size)
+	copy(b, h)
+	if _, err := io.ReadFull(c.lowerConn, b[len(h):]); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// maxChunkSize returns the size of the largest message to receive.
+func (c *Conn) maxChunkSize() uint32 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	l := c.limits
+	if c.negotiated.ReceiveBufSize != 0 {
+		l.ReceiveBufSize = c.negotiated.ReceiveBufSize
+	}
+	return l.maxChunkSize()
+}
+
+// notify passes the message to Read. The messages are passed in the order received,
+// and the monitor waits for Read to take it rather than dropping it.
+func (c *Conn) notify(ctx context.Context, b []byte) {
 	select {
 	case <-ctx.Done():
-		return
-	case c.lenChan <- n:
-		return
+	case <-c.closed:
+	case c.rcvChan <- b:
 	}
 }
 
@@ -313,7 +412,12 @@ func (c *Conn) handleMsgHello(h *Hello) {
 			c.errChan <- ErrInvalidEndpoint
 		}
 
-		c.sndBuf = make([]byte, h.ReceiveBufSize)
+		c.negotiated = c.limits.negotiate(Limits{
+			ReceiveBufSize: h.ReceiveBufSize,
+			SendBufSize:    h.SendBufSize,
+			MaxMessageSize: h.MaxMessageSize,
+			MaxChunkCount:  h.MaxChunkCount,
+		})
 		if err := c.Acknowledge(); err != nil {
 			c.errChan <- err
 		}
@@ -341,7 +445,22 @@ func (c *Conn) handleMsgAcknowledge(a *Acknowledge) {
 	switch c.state {
 	// client accepts Acknowledge only after sending Hello.
 	case cliStateHelloSent:
-		c.rcvBuf = make([]byte, a.ReceiveBufSize)
+		// the buffers of the server should not be larger than the client's.
+		if a.ReceiveBufSize > c.limits.SendBufSize || a.SendBufSize > c.limits.ReceiveBufSize {
+			if err := c.Error(BadTCPMessageTooLarge, ""); err != nil {
+				c.errChan <- err
+				return
+			}
+			c.state = cliStateClosed
+			c.errChan <- ErrInvalidLimits
+			return
+		}
+		c.negotiated = c.limits.negotiate(Limits{
+			ReceiveBufSize: a.ReceiveBufSize,
+			SendBufSize:    a.SendBufSize,
+			MaxMessageSize: a.MaxMessageSize,
+			MaxChunkCount:  a.MaxChunkCount,
+		})
 		c.state = cliStateEstablished
 		c.established <- true
 	// if client conn is closed or established, just ignore Acknowledge.
@@ -437,4 +556,6 @@ var (
 	ErrTimeout            = errors.New("timed out")
 	ErrReceivedError      = errors.New("received Error message")
 	ErrConnNotEstablished = errors.New("connection not established")
+	ErrMessageTooLarge    = errors.New("message exceeds the limits")
+	ErrInvalidLimits      = errors.New("buffer sizes in Acknowledge exceed the ones in Hello")
 )
@@ -6,6 +6,9 @@ package uacp
 
 import (
 	"context"
+	"io"
+	"net"
+	"sync"
 	"testing"
 	"time"
 
@@ -63,9 +66,10 @@ func TestClientWrite(t *testing.T) {
 	done := make(chan int)
 	go func() {
 		defer ln.Close()
+		var err error
 		srvConn, err = ln.Accept(ctx)
 		if err != nil {
-			t.Fatal(err)
+			t.Error(err)
 		}
 		done <- 0
 	}()
@@ -76,7 +80,8 @@ func TestClientWrite(t *testing.T) {
 	}
 
 	buf := make([]byte, 1024)
-	expected := []byte{0xde, 0xad, 0xbe, 0xef}
+	// the message is framed by the MessageSize in its header.
+	expected := []byte{0x4d, 0x53, 0x47, 0x46, 0x0c, 0x00, 0x00, 0x00, 0xde, 0xad, 0xbe, 0xef}
 	for {
 		select {
 		case _, ok := <-done:
@@ -103,6 +108,27 @@ NEXT:
 	}
 }
 
+func TestConnReadShortBuffer(t *testing.T) {
+	c := &Conn{mu: new(sync.Mutex), state: cliStateEstablished, rcvChan: make(chan []byte, 1)}
+	expected := []byte{0x4d, 0x53, 0x47, 0x46, 0x0c, 0x00, 0x00, 0x00, 0xde, 0xad, 0xbe, 0xef}
+	c.rcvChan <- expected
+
+	// the message is kept for the next Read if it does not fit in the buffer.
+	n, err := c.Read(make([]byte, 8))
+	if err != io.ErrShortBuffer || n != len(expected) {
+		t.Fatalf("got %d, %v want %d, %v", n, err, len(expected), io.ErrShortBuffer)
+	}
+
+	buf := make([]byte, 1024)
+	n, err = c.Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(buf[:n], expected); diff != "" {
+		t.Error(diff)
+	}
+}
+
 func TestServerWrite(t *testing.T) {
 	ep := "opc.tcp://127.0.0.1:4840/foo/bar"
 	ln, err := Listen(ep, 0xffff)
@@ -119,9 +145,10 @@ func TestServerWrite(t *testing.T) {
 	done := make(chan int)
 	go func() {
 		defer ln.Close()
+		var err error
 		srvConn, err = ln.Accept(ctx)
 		if err != nil {
-			t.Fatal(err)
+			t.Error(err)
 		}
 		done <- 0
 	}()
@@ -132,7 +159,8 @@ func TestServerWrite(t *testing.T) {
 	}
 
 	buf := make([]byte, 1024)
-	expected := []byte{0xde, 0xad, 0xbe, 0xef}
+	// the message is framed by the MessageSize in its header.
+	expected := []byte{0x4d, 0x53, 0x47, 0x46, 0x0c, 0x00, 0x00, 0x00, 0xde, 0xad, 0xbe, 0xef}
 	for {
 		select {
 		case _, ok := <-done:
@@ -199,3 +227,117 @@ func TestConnGetState(t *testing.T) {
 		}
 	}
 }
+
+func TestReverseConn(t *testing.T) {
+	ep := "opc.tcp://127.0.0.1:4840/client"
+	srvEP := "opc.tcp://127.0.0.1:4850/foo/bar"
+	ln, err := Listen(ep, 0xffff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	srvConnChan := make(chan *Conn)
+	errChan := make(chan error, 1)
+	go func() {
+		srvConn, err := DialReverse(ctx, ep, "urn:gopcua:server", srvEP)
+		if err != nil {
+			errChan <- err
+			return
+		}
+		srvConnChan <- srvConn
+	}()
+
+	cliConn, err := ln.AcceptReverse(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := cliConn.RemoteEndpoint(), srvEP; got != want {
+		t.Errorf("got RemoteEndpoint %s want %s", got, want)
+	}
+	if got, want := cliConn.ServerURI(), "urn:gopcua:server"; got != want {
+		t.Errorf("got ServerURI %s want %s", got, want)
+	}
+
+	var srvConn *Conn
+	select {
+	case srvConn = <-srvConnChan:
+	case err := <-errChan:
+		t.Fatal(err)
+	case <-time.After(10 * time.Second):
+		t.Fatal("timed out")
+	}
+
+	// the connection accepted is used as the client side.
+	expected := []byte{0x4d, 0x53, 0x47, 0x46, 0x0c, 0x00, 0x00, 0x00, 0xde, 0xad, 0xbe, 0xef}
+	if _, err := cliConn.Write(expected); err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 1024)
+	n, err := srvConn.Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(buf[:n], expected); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestAcceptReverseUnexpectedMessage(t *testing.T) {
+	ep := "opc.tcp://127.0.0.1:4840/client"
+	ln, err := Listen(ep, 0xffff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Hello is sent instead of ReverseHello.
+	go Dial(ctx, ep)
+
+	if _, err := ln.AcceptReverse(ctx); err != ErrUnexpectedMessage {
+		t.Errorf("got error %v want %v", err, ErrUnexpectedMessage)
+	}
+}
+
+func TestServeReverseStalledConn(t *testing.T) {
+	ep := "opc.tcp://127.0.0.1:4840/client"
+	srvEP := "opc.tcp://127.0.0.1:4850/foo/bar"
+	ln, err := Listen(ep, 0xffff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cliConnChan := make(chan *Conn, 1)
+	go ln.ServeReverse(ctx, func(conn *Conn) {
+		cliConnChan <- conn
+	})
+
+	// the server connecting first never sends ReverseHello.
+	stalled, err := net.Dial("tcp", "127.0.0.1:4840")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stalled.Close()
+
+	go DialReverse(ctx, ep, "urn:gopcua:server", srvEP)
+
+	select {
+	case cliConn := <-cliConnChan:
+		defer cliConn.Close()
+		if got, want := cliConn.RemoteEndpoint(), srvEP; got != want {
+			t.Errorf("got RemoteEndpoint %s want %s", got, want)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatal("timed out")
+	}
+}
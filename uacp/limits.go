@@ -0,0 +1,65 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package uacp
+
+// Limits are the sizes of the MessageChunks and the Messages exchanged on a Conn,
+// which are negotiated with Hello and Acknowledge.
+//
+// Specification: Part 6, 7.1.2.3
+type Limits struct {
+	// ReceiveBufSize and SendBufSize are the largest MessageChunks to receive and send.
+	ReceiveBufSize uint32
+	SendBufSize    uint32
+	// MaxMessageSize and MaxChunkCount are the largest Message to receive and the number
+	// of the MessageChunks in it. The value 0 means no limit.
+	MaxMessageSize uint32
+	MaxChunkCount  uint32
+}
+
+// DefaultLimits are the Limits used by Dial and Listen.
+var DefaultLimits = Limits{
+	ReceiveBufSize: 0xffff,
+	SendBufSize:    0xffff,
+	MaxMessageSize: 16 << 20,
+	MaxChunkCount:  0,
+}
+
+// negotiate returns the Limits agreed from the local ones and the ones sent by the peer.
+//
+// The buffers are clamped, so that no MessageChunk is larger than the peer or the local
+// side can receive. MaxMessageSize and MaxChunkCount are the smaller ones of both, which
+// are applied to the Messages sent.
+func (l Limits) negotiate(peer Limits) Limits {
+	return Limits{
+		ReceiveBufSize: minSize(l.ReceiveBufSize, peer.SendBufSize),
+		SendBufSize:    minSize(l.SendBufSize, peer.ReceiveBufSize),
+		MaxMessageSize: minLimit(l.MaxMessageSize, peer.MaxMessageSize),
+		MaxChunkCount:  minLimit(l.MaxChunkCount, peer.MaxChunkCount),
+	}
+}
+
+// maxChunkSize returns the size of the largest MessageChunk to receive, which is
+// limited by MaxMessageSize as well as ReceiveBufSize.
+func (l Limits) maxChunkSize() uint32 {
+	return minLimit(l.ReceiveBufSize, l.MaxMessageSize)
+}
+
+func minSize(a, b uint32) uint32 {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// minLimit returns the smaller of a and b, where 0 means no limit.
+func minLimit(a, b uint32) uint32 {
+	if a == 0 {
+		return b
+	}
+	if b == 0 {
+		return a
+	}
+	return minSize(a, b)
+}
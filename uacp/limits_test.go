@@ -0,0 +1,214 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package uacp
+
+import (
+	"context"
+	"encoding/binary"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLimitsNegotiate(t *testing.T) {
+	cases := []struct {
+		name              string
+		local, peer, want Limits
+	}{
+		{
+			name:  "clamped",
+			local: Limits{ReceiveBufSize: 8192, SendBufSize: 8192, MaxMessageSize: 0x10000, MaxChunkCount: 4},
+			peer:  Limits{ReceiveBufSize: 0xffffffff, SendBufSize: 0xffffffff, MaxMessageSize: 0xffffffff, MaxChunkCount: 0xffffffff},
+			want:  Limits{ReceiveBufSize: 8192, SendBufSize: 8192, MaxMessageSize: 0x10000, MaxChunkCount: 4},
+		},
+		{
+			name:  "peer",
+			local: Limits{ReceiveBufSize: 0xffff, SendBufSize: 0x8000, MaxMessageSize: 0x10000, MaxChunkCount: 4},
+			peer:  Limits{ReceiveBufSize: 8192, SendBufSize: 0x4000, MaxMessageSize: 0x8000, MaxChunkCount: 2},
+			want:  Limits{ReceiveBufSize: 0x4000, SendBufSize: 8192, MaxMessageSize: 0x8000, MaxChunkCount: 2},
+		},
+		{
+			name:  "no-limit",
+			local: Limits{ReceiveBufSize: 0xffff, SendBufSize: 0xffff, MaxMessageSize: 0, MaxChunkCount: 4},
+			peer:  Limits{ReceiveBufSize: 0xffff, SendBufSize: 0xffff, MaxMessageSize: 0x10000, MaxChunkCount: 0},
+			want:  Limits{ReceiveBufSize: 0xffff, SendBufSize: 0xffff, MaxMessageSize: 0x10000, MaxChunkCount: 4},
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if diff := cmp.Diff(c.local.negotiate(c.peer), c.want); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+// readRaw reads a UACP message from the raw connection c.
+func readRaw(c net.Conn) (UACP, error) {
+	c.SetReadDeadline(time.Now().Add(5 * time.Second))
+	b, err := (&Conn{mu: new(sync.Mutex), lowerConn: c}).readMessage()
+	if err != nil {
+		return nil, err
+	}
+	return Decode(b)
+}
+
+func TestListenerLimits(t *testing.T) {
+	ep := "opc.tcp://127.0.0.1:4840/foo/bar"
+	limits := Limits{ReceiveBufSize: 8192, SendBufSize: 8192, MaxMessageSize: 0x10000, MaxChunkCount: 4}
+	ln, err := ListenWithLimits(ep, limits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	accepted := make(chan *Conn, 1)
+	go func() {
+		conn, err := ln.Accept(ctx)
+		if err != nil {
+			t.Error(err)
+		}
+		accepted <- conn
+	}()
+
+	raw, err := net.Dial("tcp", "127.0.0.1:4840")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer raw.Close()
+
+	// the client advertises the sizes far larger than the server can handle.
+	h := NewHello(0, 0xffffffff, 0xffffffff, 0xffffffff, ep)
+	h.MaxChunkCount = 0xffffffff
+	b, err := h.Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := raw.Write(b); err != nil {
+		t.Fatal(err)
+	}
+
+	m, err := readRaw(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ack, ok := m.(*Acknowledge)
+	if !ok {
+		t.Fatal("Acknowledge is not received")
+	}
+	got := Limits{ack.ReceiveBufSize, ack.SendBufSize, ack.MaxMessageSize, ack.MaxChunkCount}
+	if diff := cmp.Diff(got, limits); diff != "" {
+		t.Error(diff)
+	}
+
+	var conn *Conn
+	select {
+	case conn = <-accepted:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out")
+	}
+	if conn == nil {
+		t.Fatal("connection is not accepted")
+	}
+	if diff := cmp.Diff(conn.Negotiated(), limits); diff != "" {
+		t.Error(diff)
+	}
+
+	// the message larger than ReceiveBufSize is rejected without reading the body.
+	msg := []byte{0x4d, 0x53, 0x47, 0x46, 0x00, 0x00, 0x00, 0x00}
+	binary.LittleEndian.PutUint32(msg[4:8], 0x7fffffff)
+	if _, err := raw.Write(msg); err != nil {
+		t.Fatal(err)
+	}
+
+	if m, err = readRaw(raw); err != nil {
+		t.Fatal(err)
+	}
+	e, ok := m.(*Error)
+	if !ok {
+		t.Fatal("Error is not received")
+	}
+	if e.Error != BadTCPMessageTooLarge {
+		t.Errorf("got Error 0x%08x want 0x%08x", e.Error, BadTCPMessageTooLarge)
+	}
+	if _, err := conn.Read(make([]byte, 0xffff)); err != ErrMessageTooLarge {
+		t.Errorf("got error %v want %v", err, ErrMessageTooLarge)
+	}
+}
+
+func TestDialWithLimits(t *testing.T) {
+	ep := "opc.tcp://127.0.0.1:4840/foo/bar"
+	limits := Limits{ReceiveBufSize: 0xffff, SendBufSize: 0xffff, MaxMessageSize: 0x10000, MaxChunkCount: 4}
+
+	cases := []struct {
+		name string
+		ack  *Acknowledge
+		want Limits
+		err  error
+	}{
+		{
+			name: "clamped",
+			ack:  NewAcknowledge(0, 8192, 8192, 0xffffffff),
+			want: Limits{ReceiveBufSize: 8192, SendBufSize: 8192, MaxMessageSize: 0x10000, MaxChunkCount: 4},
+		},
+		{
+			name: "too-large",
+			ack:  NewAcknowledge(0, 0xffffffff, 0xffffffff, 0),
+			err:  ErrInvalidLimits,
+		},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ln, err := net.Listen("tcp", "127.0.0.1:4840")
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer ln.Close()
+
+			go func() {
+				raw, err := ln.Accept()
+				if err != nil {
+					return
+				}
+				defer raw.Close()
+
+				if _, err := readRaw(raw); err != nil {
+					t.Error(err)
+					return
+				}
+				b, err := c.ack.Serialize()
+				if err != nil {
+					t.Error(err)
+					return
+				}
+				raw.Write(b)
+				// waits for the client to close the connection.
+				raw.Read(make([]byte, 0xffff))
+			}()
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			conn, err := DialWithLimits(ctx, ep, limits)
+			if err != c.err {
+				t.Fatalf("got error %v want %v", err, c.err)
+			}
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+
+			if diff := cmp.Diff(conn.Negotiated(), c.want); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
@@ -8,33 +8,53 @@ import (
 	"context"
 	"net"
 	"sync"
+	"time"
 
+	"github.com/wmnsk/gopcua/errors"
 	"github.com/wmnsk/gopcua/utils"
 )
 
 // Listener is a OPC UA Connection Protocol network listener.
 type Listener struct {
-	lowerListener          net.Listener
-	endpoint               string
-	rcvBufSize, sndBufSize uint32
+	lowerListener net.Listener
+	endpoint      string
+	limits        Limits
+	// websocket is true if the connections are upgraded to WebSocket on Accept.
+	websocket bool
 }
 
 // Listen acts like net.Listen for OPC UA Connection Protocol networks.
 //
-// Currently the endpoint can only be specified in "opc.tcp://<addr[:port]>/path" format.
+// The endpoint is specified in "opc.tcp://<addr[:port]>/path" format, or "opc.ws://" to accept
+// the connections upgraded to WebSocket. "opc.wss://" is not supported as the Listener has
+// no certificate for TLS, which is expected to be terminated by a proxy in front of it.
 //
 // If the IP field of laddr is nil or an unspecified IP address, Listen listens on all available unicast and anycast IP addresses of the local system.
 // If the Port field of laddr is 0, a port number is automatically chosen.
+//
+// The connections are accepted with DefaultLimits, of which ReceiveBufSize is rcvBufSize.
 func Listen(endpoint string, rcvBufSize uint32) (*Listener, error) {
+	limits := DefaultLimits
+	limits.ReceiveBufSize = rcvBufSize
+	return ListenWithLimits(endpoint, limits)
+}
+
+// ListenWithLimits is Listen with the local Limits sent in Acknowledge.
+// The buffers in the Hello received are clamped to limits.
+func ListenWithLimits(endpoint string, limits Limits) (*Listener, error) {
 	network, laddr, err := utils.ResolveEndpoint(endpoint)
 	if err != nil {
 		return nil, err
 	}
+	ws, secure := isWebSocket(endpoint)
+	if secure {
+		return nil, errors.NewErrUnsupported(endpoint, "cannot listen with TLS.")
+	}
 
 	lis := &Listener{
-		endpoint:   endpoint,
-		rcvBufSize: rcvBufSize,
-		sndBufSize: 0xffff,
+		endpoint:  endpoint,
+		limits:    limits,
+		websocket: ws,
 	}
 	lis.lowerListener, err = net.Listen(network, laddr.String())
 	if err != nil {
@@ -49,20 +69,28 @@ func Listen(endpoint string, rcvBufSize uint32) (*Listener, error) {
 // The first param ctx is to be passed to monitor(), which monitors and handles
 // incoming messages automatically in another goroutine.
 func (l *Listener) Accept(ctx context.Context) (*Conn, error) {
-	var err error
+	lowerConn, err := l.accept()
+	if err != nil {
+		return nil, err
+	}
+	return AcceptConn(ctx, lowerConn, l.endpoint, l.limits)
+}
 
+// AcceptConn establishes the connection as a server with the limits on the lowerConn given
+// instead of the one accepted by a Listener, e.g. an in-process transport in tests.
+// The endpoint is the local EndpointURL of the connection.
+func AcceptConn(ctx context.Context, lowerConn net.Conn, endpoint string, limits Limits) (*Conn, error) {
 	conn := &Conn{
 		mu:          new(sync.Mutex),
 		state:       srvStateClosed,
 		established: make(chan bool),
-		lenChan:     make(chan int),
+		rcvChan:     make(chan []byte),
+		closed:      make(chan struct{}),
+		broken:      make(chan struct{}),
 		errChan:     make(chan error),
-		rcvBuf:      make([]byte, l.rcvBufSize),
-		lep:         l.endpoint,
-	}
-	conn.lowerConn, err = l.lowerListener.Accept()
-	if err != nil {
-		return nil, err
+		limits:      limits,
+		lep:         endpoint,
+		lowerConn:   lowerConn,
 	}
 
 	go conn.monitor(ctx)
@@ -73,11 +101,127 @@ func (l *Listener) Accept(ctx context.Context) (*Conn, error) {
 		}
 	case err := <-conn.errChan:
 		return nil, err
+	case <-ctx.Done():
+		return nil, ctx.Err()
 	}
 
 	return nil, nil
 }
 
+// reverseTimeout is the time for the server connecting with reverse connect to send
+// ReverseHello and complete the handshake after that.
+const reverseTimeout = 15 * time.Second
+
+// AcceptReverse accepts the next incoming call from a server using reverse connect,
+// and returns the new connection established as a client.
+//
+// The connection is established by sending Hello to the EndpointURL in the ReverseHello
+// the server sends first, which is returned by RemoteEndpoint along with the ServerURI
+// returned by ServerURI.
+//
+// The errors in accepting the call are returned as *net.OpError with Op "accept",
+// while the others are the ones in establishing the connection accepted.
+// Use ServeReverse not to wait for a server to establish the connection before accepting
+// the next call.
+func (l *Listener) AcceptReverse(ctx context.Context) (*Conn, error) {
+	lowerConn, err := l.lowerListener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	return l.reverse(ctx, lowerConn, l.limits)
+}
+
+// ServeReverse accepts the incoming calls from the servers using reverse connect until
+// accepting fails, e.g. the Listener is closed, and calls handler with each connection
+// established as AcceptReverse does.
+//
+// Each connection is established in its own goroutine, where handler is called, so that
+// the servers slow to send ReverseHello or to complete the handshake do not block the
+// others. The connections failed to be established are closed without calling handler.
+func (l *Listener) ServeReverse(ctx context.Context, handler func(*Conn)) error {
+	// the limits are cleared by Close while the connections are being established.
+	limits := l.limits
+	for {
+		lowerConn, err := l.lowerListener.Accept()
+		if err != nil {
+			return err
+		}
+		go func() {
+			conn, err := l.reverse(ctx, lowerConn, limits)
+			if err != nil {
+				return
+			}
+			handler(conn)
+		}()
+	}
+}
+
+// reverse establishes the connection with limits as a client on rawConn accepted from
+// a server using reverse connect, which should be completed within reverseTimeout.
+func (l *Listener) reverse(ctx context.Context, rawConn net.Conn, limits Limits) (*Conn, error) {
+	rawConn.SetDeadline(time.Now().Add(reverseTimeout))
+	lowerConn, err := l.upgrade(rawConn)
+	if err != nil {
+		return nil, err
+	}
+
+	conn := newClientConn("")
+	conn.limits = limits
+	conn.lowerConn = lowerConn
+
+	// the server is expected to send ReverseHello right after connecting.
+	b, err := conn.readMessage()
+	if err != nil {
+		lowerConn.Close()
+		return nil, err
+	}
+	msg, err := Decode(b)
+	if err != nil {
+		lowerConn.Close()
+		return nil, err
+	}
+	rhe, ok := msg.(*ReverseHello)
+	if !ok {
+		conn.Error(BadTCPMessageTypeInvalid, "")
+		lowerConn.Close()
+		return nil, ErrUnexpectedMessage
+	}
+	conn.rep = rhe.EndPointURL.Get()
+	conn.serverURI = rhe.ServerURI.Get()
+
+	if err := conn.handshake(ctx, 5*time.Second, 3); err != nil {
+		lowerConn.Close()
+		return nil, err
+	}
+	rawConn.SetDeadline(time.Time{})
+	return conn, nil
+}
+
+// accept accepts the next connection from the lowerListener to carry the UACP messages on,
+// upgrading it to WebSocket if the Listener is on the WebSocket transport.
+func (l *Listener) accept() (net.Conn, error) {
+	conn, err := l.lowerListener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	return l.upgrade(conn)
+}
+
+// upgrade upgrades conn accepted to WebSocket if the Listener is on the WebSocket transport.
+// conn is closed if it fails.
+func (l *Listener) upgrade(conn net.Conn) (net.Conn, error) {
+	if !l.websocket {
+		return conn, nil
+	}
+
+	wc, err := acceptWebSocket(conn)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return wc, nil
+}
+
 // Close closes the Listener.
 func (l *Listener) Close() error {
 	if err := l.lowerListener.Close(); err != nil {
@@ -85,8 +229,7 @@ func (l *Listener) Close() error {
 	}
 
 	l.endpoint = ""
-	l.rcvBufSize = 0
-	l.sndBufSize = 0
+	l.limits = Limits{}
 	return nil
 }
 
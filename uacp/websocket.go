@@ -0,0 +1,410 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package uacp
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/wmnsk/gopcua/errors"
+	"github.com/wmnsk/gopcua/utils"
+)
+
+// WebSocketProtocol is the WebSocket subprotocol which carries the UACP messages,
+// each of which is sent in a binary frame.
+//
+// Specification: Part 6, 7.5.2
+const WebSocketProtocol = "opcua+uacp"
+
+// wsAcceptGUID is appended to Sec-WebSocket-Key to compute Sec-WebSocket-Accept.
+//
+// Specification: RFC 6455, 1.3
+const wsAcceptGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// WebSocket frame opcodes.
+//
+// Specification: RFC 6455, 5.2
+const (
+	wsOpContinuation = 0x0
+	wsOpText         = 0x1
+	wsOpBinary       = 0x2
+	wsOpClose        = 0x8
+	wsOpPing         = 0x9
+	wsOpPong         = 0xa
+)
+
+// wsHandshakeTimeout is the time allowed for the HTTP upgrade.
+const wsHandshakeTimeout = 5 * time.Second
+
+// ErrWebSocketHandshake is returned when the HTTP upgrade to WebSocket fails.
+var ErrWebSocketHandshake = errors.New("websocket handshake failed")
+
+// wsConn is a net.Conn which carries the stream of UACP messages over WebSocket.
+//
+// Each Write is sent in a single binary frame, so that a UACP message written at once
+// is in one WebSocket message. Read returns the payloads of the binary frames received
+// as a stream, which is framed into the UACP messages by their headers as on TCP.
+// The control frames are handled in Read.
+type wsConn struct {
+	net.Conn
+	r *bufio.Reader
+	// client is true on the client side, which masks the frames sent.
+	client bool
+
+	// remaining is the number of the bytes left in the payload of the current frame,
+	// which are unmasked with mask from the offset pos.
+	remaining uint64
+	masked    bool
+	mask      [4]byte
+	pos       int
+
+	wmu    *sync.Mutex
+	closed bool
+}
+
+// isWebSocket reports whether the endpoint is on the WebSocket transport,
+// and whether it is secured with TLS.
+func isWebSocket(endpoint string) (ws, secure bool) {
+	switch {
+	case strings.HasPrefix(endpoint, "opc.wss://"):
+		return true, true
+	case strings.HasPrefix(endpoint, "opc.ws://"):
+		return true, false
+	}
+	return false, false
+}
+
+// dialTransport connects to the endpoint with d and returns the connection to carry the UACP
+// messages on, which is upgraded to WebSocket if the scheme of endpoint is "opc.ws" or "opc.wss".
+func dialTransport(ctx context.Context, endpoint string, d Dialer) (net.Conn, error) {
+	conn, err := dialTCP(ctx, endpoint, d)
+	if err != nil {
+		return nil, err
+	}
+
+	ws, secure := isWebSocket(endpoint)
+	if !ws {
+		return conn, nil
+	}
+	if secure {
+		host, _, err := net.SplitHostPort(wsHost(endpoint))
+		if err != nil {
+			host = wsHost(endpoint)
+		}
+		conn = tls.Client(conn, &tls.Config{ServerName: host})
+	}
+
+	wc, err := dialWebSocket(conn, endpoint)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return wc, nil
+}
+
+// wsHost returns the host[:port] in the endpoint.
+func wsHost(endpoint string) string {
+	elems := strings.Split(endpoint, "/")
+	if len(elems) < 3 {
+		return ""
+	}
+	return elems[2]
+}
+
+// dialWebSocket upgrades conn to WebSocket with the HTTP request to the path of endpoint.
+//
+// Specification: RFC 6455, 4.1
+func dialWebSocket(conn net.Conn, endpoint string) (*wsConn, error) {
+	path, err := utils.GetPath(endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	key := base64.StdEncoding.EncodeToString(nonce)
+
+	conn.SetDeadline(time.Now().Add(wsHandshakeTimeout))
+	defer conn.SetDeadline(time.Time{})
+
+	req := fmt.Sprintf("GET %s HTTP/1.1\r\n"+
+		"Host: %s\r\n"+
+		"Upgrade: websocket\r\n"+
+		"Connection: Upgrade\r\n"+
+		"Sec-WebSocket-Key: %s\r\n"+
+		"Sec-WebSocket-Version: 13\r\n"+
+		"Sec-WebSocket-Protocol: %s\r\n"+
+		"\r\n", path, wsHost(endpoint), key, WebSocketProtocol)
+	if _, err := io.WriteString(conn, req); err != nil {
+		return nil, err
+	}
+
+	r := bufio.NewReader(conn)
+	res, err := http.ReadResponse(r, nil)
+	if err != nil {
+		return nil, err
+	}
+	res.Body.Close()
+
+	switch {
+	case res.StatusCode != http.StatusSwitchingProtocols:
+		return nil, errors.Errorf("%s: got status %s", ErrWebSocketHandshake, res.Status)
+	case !strings.EqualFold(res.Header.Get("Upgrade"), "websocket"):
+		return nil, errors.Errorf("%s: got Upgrade %q", ErrWebSocketHandshake, res.Header.Get("Upgrade"))
+	case res.Header.Get("Sec-WebSocket-Accept") != wsAccept(key):
+		return nil, errors.Errorf("%s: got invalid Sec-WebSocket-Accept", ErrWebSocketHandshake)
+	case res.Header.Get("Sec-WebSocket-Protocol") != WebSocketProtocol:
+		return nil, errors.Errorf("%s: got subprotocol %q", ErrWebSocketHandshake, res.Header.Get("Sec-WebSocket-Protocol"))
+	}
+
+	return &wsConn{Conn: conn, r: r, client: true, wmu: new(sync.Mutex)}, nil
+}
+
+// acceptWebSocket upgrades conn accepted to WebSocket with the response to the HTTP
+// request received. The request is rejected if it does not offer WebSocketProtocol.
+//
+// Specification: RFC 6455, 4.2
+func acceptWebSocket(conn net.Conn) (*wsConn, error) {
+	conn.SetDeadline(time.Now().Add(wsHandshakeTimeout))
+	defer conn.SetDeadline(time.Time{})
+
+	r := bufio.NewReader(conn)
+	req, err := http.ReadRequest(r)
+	if err != nil {
+		return nil, err
+	}
+	req.Body.Close()
+
+	reject := func(reason string) (*wsConn, error) {
+		io.WriteString(conn, "HTTP/1.1 400 Bad Request\r\nConnection: close\r\n\r\n")
+		return nil, errors.Errorf("%s: %s", ErrWebSocketHandshake, reason)
+	}
+
+	key := req.Header.Get("Sec-WebSocket-Key")
+	switch {
+	case req.Method != http.MethodGet:
+		return reject("got method " + req.Method)
+	case !strings.EqualFold(req.Header.Get("Upgrade"), "websocket"):
+		return reject("got no Upgrade to websocket")
+	case !headerContains(req.Header, "Connection", "upgrade"):
+		return reject("got no Connection upgrade")
+	case req.Header.Get("Sec-WebSocket-Version") != "13":
+		return reject("got unsupported version " + req.Header.Get("Sec-WebSocket-Version"))
+	case key == "":
+		return reject("got no Sec-WebSocket-Key")
+	case !headerContains(req.Header, "Sec-WebSocket-Protocol", WebSocketProtocol):
+		return reject("got no subprotocol " + WebSocketProtocol)
+	}
+
+	res := fmt.Sprintf("HTTP/1.1 101 Switching Protocols\r\n"+
+		"Upgrade: websocket\r\n"+
+		"Connection: Upgrade\r\n"+
+		"Sec-WebSocket-Accept: %s\r\n"+
+		"Sec-WebSocket-Protocol: %s\r\n"+
+		"\r\n", wsAccept(key), WebSocketProtocol)
+	if _, err := io.WriteString(conn, res); err != nil {
+		return nil, err
+	}
+
+	return &wsConn{Conn: conn, r: r, wmu: new(sync.Mutex)}, nil
+}
+
+// wsAccept returns the Sec-WebSocket-Accept for the Sec-WebSocket-Key.
+func wsAccept(key string) string {
+	h := sha1.Sum([]byte(key + wsAcceptGUID))
+	return base64.StdEncoding.EncodeToString(h[:])
+}
+
+// headerContains reports whether any of the comma separated values of the header is v.
+func headerContains(h http.Header, name, v string) bool {
+	for _, line := range h[http.CanonicalHeaderKey(name)] {
+		for _, s := range strings.Split(line, ",") {
+			if strings.EqualFold(strings.TrimSpace(s), v) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Read reads the payloads of the binary frames received.
+// io.EOF is returned when the peer closes the WebSocket.
+func (c *wsConn) Read(b []byte) (int, error) {
+	for c.remaining == 0 {
+		if err := c.nextFrame(); err != nil {
+			return 0, err
+		}
+	}
+
+	if uint64(len(b)) > c.remaining {
+		b = b[:c.remaining]
+	}
+	n, err := c.r.Read(b)
+	if c.masked {
+		for i := 0; i < n; i++ {
+			b[i] ^= c.mask[c.pos%4]
+			c.pos++
+		}
+	}
+	c.remaining -= uint64(n)
+	return n, err
+}
+
+// nextFrame reads the header of the next frame, handling the control frames.
+// The payload of a binary or continuation frame is left to be read by Read.
+//
+// Specification: RFC 6455, 5.2
+func (c *wsConn) nextFrame() error {
+	h := make([]byte, 2)
+	if _, err := io.ReadFull(c.r, h); err != nil {
+		return err
+	}
+	op := h[0] & 0x0f
+	masked := h[1]&0x80 != 0
+	length := uint64(h[1] & 0x7f)
+
+	switch length {
+	case 126:
+		ext := make([]byte, 2)
+		if _, err := io.ReadFull(c.r, ext); err != nil {
+			return err
+		}
+		length = uint64(binary.BigEndian.Uint16(ext))
+	case 127:
+		ext := make([]byte, 8)
+		if _, err := io.ReadFull(c.r, ext); err != nil {
+			return err
+		}
+		length = binary.BigEndian.Uint64(ext)
+	}
+
+	// the frames from the client shall be masked, and the ones from the server shall not.
+	if masked == c.client {
+		c.writeClose(1002)
+		return errors.Errorf("websocket: got frame with invalid masking")
+	}
+	if masked {
+		if _, err := io.ReadFull(c.r, c.mask[:]); err != nil {
+			return err
+		}
+	}
+
+	switch op {
+	case wsOpBinary, wsOpContinuation:
+		c.remaining, c.masked, c.pos = length, masked, 0
+		return nil
+	case wsOpPing, wsOpPong, wsOpClose:
+		if length > 125 {
+			return errors.Errorf("websocket: got control frame longer than 125 bytes")
+		}
+		payload := make([]byte, length)
+		if _, err := io.ReadFull(c.r, payload); err != nil {
+			return err
+		}
+		if masked {
+			for i := range payload {
+				payload[i] ^= c.mask[i%4]
+			}
+		}
+
+		switch op {
+		case wsOpPing:
+			return c.writeFrame(wsOpPong, payload)
+		case wsOpClose:
+			c.writeClose(1000)
+			return io.EOF
+		}
+		return nil
+	case wsOpText:
+		c.writeClose(1003)
+		return errors.Errorf("websocket: got text frame")
+	default:
+		c.writeClose(1002)
+		return errors.Errorf("websocket: got unknown opcode 0x%x", op)
+	}
+}
+
+// Write sends b in a single binary frame.
+func (c *wsConn) Write(b []byte) (int, error) {
+	if err := c.writeFrame(wsOpBinary, b); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+// writeFrame sends the payload in a single final frame of op,
+// which is masked on the client side.
+func (c *wsConn) writeFrame(op byte, payload []byte) error {
+	c.wmu.Lock()
+	defer c.wmu.Unlock()
+	if c.closed {
+		return io.ErrClosedPipe
+	}
+
+	h := make([]byte, 2, 14)
+	h[0] = 0x80 | op
+	switch l := len(payload); {
+	case l < 126:
+		h[1] = byte(l)
+	case l <= 0xffff:
+		h[1] = 126
+		h = append(h, byte(l>>8), byte(l))
+	default:
+		h[1] = 127
+		h = h[:10]
+		binary.BigEndian.PutUint64(h[2:], uint64(l))
+	}
+
+	b := make([]byte, 0, len(h)+4+len(payload))
+	if !c.client {
+		b = append(append(b, h...), payload...)
+		_, err := c.Conn.Write(b)
+		return err
+	}
+
+	h[1] |= 0x80
+	var mask [4]byte
+	if _, err := rand.Read(mask[:]); err != nil {
+		return err
+	}
+	b = append(append(b, h...), mask[:]...)
+	for i, p := range payload {
+		b = append(b, p^mask[i%4])
+	}
+	_, err := c.Conn.Write(b)
+	return err
+}
+
+// writeClose sends the close frame with the status code, after which no frame is sent.
+func (c *wsConn) writeClose(code uint16) {
+	payload := make([]byte, 2)
+	binary.BigEndian.PutUint16(payload, code)
+	if err := c.writeFrame(wsOpClose, payload); err == nil {
+		c.wmu.Lock()
+		c.closed = true
+		c.wmu.Unlock()
+	}
+}
+
+// Close sends the close frame and closes the underlying connection.
+func (c *wsConn) Close() error {
+	c.Conn.SetWriteDeadline(time.Now().Add(time.Second))
+	c.writeClose(1000)
+	return c.Conn.Close()
+}
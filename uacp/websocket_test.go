@@ -0,0 +1,246 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package uacp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"io/ioutil"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// pipeListener is a net.Listener which accepts the server side of a net.Pipe.
+type pipeListener struct {
+	conns chan net.Conn
+}
+
+func (l *pipeListener) Accept() (net.Conn, error) {
+	c, ok := <-l.conns
+	if !ok {
+		return nil, io.EOF
+	}
+	return c, nil
+}
+
+func (l *pipeListener) Close() error   { return nil }
+func (l *pipeListener) Addr() net.Addr { return &net.TCPAddr{} }
+
+func TestWebSocketHandshake(t *testing.T) {
+	ep := "opc.ws://127.0.0.1/foo/bar"
+	cli, srv := net.Pipe()
+	defer cli.Close()
+	defer srv.Close()
+
+	ln := &Listener{
+		lowerListener: &pipeListener{conns: make(chan net.Conn, 1)},
+		endpoint:      ep,
+		limits:        DefaultLimits,
+		websocket:     true,
+	}
+	ln.lowerListener.(*pipeListener).conns <- srv
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	accepted := make(chan *Conn, 1)
+	go func() {
+		conn, err := ln.Accept(ctx)
+		if err != nil {
+			t.Error(err)
+		}
+		accepted <- conn
+	}()
+
+	wc, err := dialWebSocket(cli, ep)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn := newClientConn(ep)
+	conn.lowerConn = wc
+	if err := conn.handshake(ctx, time.Second, 3); err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(conn.Negotiated(), DefaultLimits); diff != "" {
+		t.Error(diff)
+	}
+
+	var srvConn *Conn
+	select {
+	case srvConn = <-accepted:
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out")
+	}
+	if srvConn == nil {
+		t.Fatal("connection is not accepted")
+	}
+
+	// a MessageChunk larger than the buffer of the pipe is passed in one frame.
+	msg := make([]byte, 0x1000)
+	copy(msg, []byte{0x4d, 0x53, 0x47, 0x46, 0x00, 0x10, 0x00, 0x00})
+	for i := 8; i < len(msg); i++ {
+		msg[i] = byte(i)
+	}
+	go conn.Write(msg)
+
+	b := make([]byte, 0xffff)
+	n, err := srvConn.Read(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(b[:n], msg); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestWebSocketDial(t *testing.T) {
+	ep := "opc.ws://127.0.0.1:4840/foo/bar"
+	ln, err := Listen(ep, 0xffff)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := ln.Accept(ctx)
+		done <- err
+	}()
+
+	conn, err := Dial(ctx, ep)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+	if _, ok := conn.lowerConn.(*wsConn); !ok {
+		t.Errorf("got lowerConn %T want *wsConn", conn.lowerConn)
+	}
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Error(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out")
+	}
+
+	if _, err := Listen("opc.wss://127.0.0.1:4840/foo/bar", 0xffff); err == nil {
+		t.Error("opc.wss should not be listened")
+	}
+}
+
+// maskedFrame returns a frame sent from the client.
+func maskedFrame(fin bool, op byte, payload []byte) []byte {
+	mask := []byte{0x01, 0x02, 0x03, 0x04}
+	b := []byte{op, 0x80 | byte(len(payload))}
+	if fin {
+		b[0] |= 0x80
+	}
+	b = append(b, mask...)
+	for i, p := range payload {
+		b = append(b, p^mask[i%4])
+	}
+	return b
+}
+
+func TestWebSocketFrames(t *testing.T) {
+	cli, srv := net.Pipe()
+	defer cli.Close()
+	defer srv.Close()
+	wc := &wsConn{Conn: srv, r: bufio.NewReader(srv), wmu: new(sync.Mutex)}
+
+	// the peer reads the frames sent by wc in background, as net.Pipe is synchronous.
+	sent := make(chan []byte, 10)
+	go func() {
+		for {
+			b := make([]byte, 0xff)
+			n, err := cli.Read(b)
+			if err != nil {
+				close(sent)
+				return
+			}
+			sent <- b[:n]
+		}
+	}()
+
+	go func() {
+		cli.Write(maskedFrame(false, wsOpBinary, []byte("foo")))
+		cli.Write(maskedFrame(true, wsOpPing, []byte("ping")))
+		cli.Write(maskedFrame(true, wsOpContinuation, []byte("bar")))
+		cli.Write(maskedFrame(true, wsOpClose, []byte{0x03, 0xe8}))
+	}()
+
+	got, err := ioutil.ReadAll(wc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(got, []byte("foobar")); diff != "" {
+		t.Error(diff)
+	}
+
+	want := [][]byte{
+		// pong with the payload of the ping.
+		{0x8a, 0x04, 0x70, 0x69, 0x6e, 0x67},
+		// close with the normal closure.
+		{0x88, 0x02, 0x03, 0xe8},
+	}
+	for _, w := range want {
+		select {
+		case b := <-sent:
+			if diff := cmp.Diff(b, w); diff != "" {
+				t.Error(diff)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("timed out")
+		}
+	}
+}
+
+func TestWebSocketReject(t *testing.T) {
+	cli, srv := net.Pipe()
+	defer cli.Close()
+	defer srv.Close()
+
+	go io.WriteString(cli, "GET /foo/bar HTTP/1.1\r\n"+
+		"Host: 127.0.0.1\r\n"+
+		"Upgrade: websocket\r\n"+
+		"Connection: Upgrade\r\n"+
+		"Sec-WebSocket-Key: dGhlIHNhbXBsZSBub25jZQ==\r\n"+
+		"Sec-WebSocket-Version: 13\r\n"+
+		"Sec-WebSocket-Protocol: opcua+uajson\r\n"+
+		"\r\n")
+	res := make(chan string, 1)
+	go func() {
+		var buf bytes.Buffer
+		io.Copy(&buf, cli)
+		res <- buf.String()
+	}()
+
+	if _, err := acceptWebSocket(srv); err == nil {
+		t.Error("the request without opcua+uacp should be rejected")
+	}
+	srv.Close()
+	if got := <-res; !strings.HasPrefix(got, "HTTP/1.1 400") {
+		t.Errorf("got response %q", got)
+	}
+}
+
+func TestWebSocketAccept(t *testing.T) {
+	// the example in RFC 6455, 1.3
+	if got, want := wsAccept("dGhlIHNhbXBsZSBub25jZQ=="), "s3pPLMBiTxaQ9kYGzzhZRbK+xOo="; got != want {
+		t.Errorf("got %s want %s", got, want)
+	}
+}
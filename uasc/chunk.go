@@ -0,0 +1,190 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package uasc
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+
+	"github.com/wmnsk/gopcua/datatypes"
+	"github.com/wmnsk/gopcua/status"
+	"github.com/wmnsk/gopcua/uacp"
+)
+
+// symmetricChunkHeaderLen is the length of the headers in the MessageChunk of MSG type,
+// which are Header, SymmetricSecurityHeader and SequenceHeader.
+const symmetricChunkHeaderLen = 12 + 4 + 8
+
+// AbortError is returned by Read and ReadService when the sender aborted the Message
+// by sending the MessageChunk with ChunkType=A.
+//
+// Specification: Part 6, 6.7.3
+//
+// The RequestHandle is the one of the request aborted if it is sent by the client,
+// which is 0 otherwise.
+type AbortError struct {
+	RequestID     uint32
+	RequestHandle uint32
+	StatusCode    uint32
+	Reason        string
+}
+
+// Error returns AbortError in string.
+func (e *AbortError) Error() string {
+	return fmt.Sprintf("message %d aborted with status %v: %s", e.RequestID, status.StatusCode(e.StatusCode), e.Reason)
+}
+
+// chunkAssembler assembles the MessageChunks of MSG type into a Message.
+//
+// The chunks are kept for each RequestID until the final chunk arrives.
+// The Message exceeding maxSize or maxChunks is rejected, and the rest of its
+// chunks are discarded. The value 0 means no limit.
+type chunkAssembler struct {
+	partial            map[uint32][]byte
+	count              map[uint32]uint32
+	rejected           map[uint32]bool
+	maxSize, maxChunks uint32
+}
+
+func newChunkAssembler(maxSize, maxChunks uint32) *chunkAssembler {
+	return &chunkAssembler{
+		partial:   map[uint32][]byte{},
+		count:     map[uint32]uint32{},
+		rejected:  map[uint32]bool{},
+		maxSize:   maxSize,
+		maxChunks: maxChunks,
+	}
+}
+
+// limiter is implemented by the lower connections which limit the Messages received,
+// such as *uacp.Conn.
+type limiter interface {
+	Limits() uacp.Limits
+}
+
+// newChunkAssemblerFor creates a chunkAssembler with the limits of conn if it has them.
+func newChunkAssemblerFor(conn net.Conn) *chunkAssembler {
+	if l, ok := conn.(limiter); ok {
+		limits := l.Limits()
+		return newChunkAssembler(limits.MaxMessageSize, limits.MaxChunkCount)
+	}
+	return newChunkAssembler(0, 0)
+}
+
+// negotiator is implemented by the lower connections which negotiate the sizes of the
+// MessageChunks with the peer, such as *uacp.Conn.
+type negotiator interface {
+	Negotiated() uacp.Limits
+}
+
+// defaultReceiveBufSize is the size of the buffer to read the MessageChunks from the lower
+// connections which do not negotiate it.
+const defaultReceiveBufSize = 0xffff
+
+// newReceiveBuf creates the buffer to read the MessageChunks from conn, which is as large as
+// the ReceiveBufSize negotiated on conn if it has one.
+func newReceiveBuf(conn net.Conn) []byte {
+	if n, ok := conn.(negotiator); ok {
+		if size := n.Negotiated().ReceiveBufSize; size != 0 {
+			return make([]byte, size)
+		}
+	}
+	return make([]byte, defaultReceiveBufSize)
+}
+
+// exceeds reports whether the Message of reqID gets over the limits with the chunk b.
+func (a *chunkAssembler) exceeds(reqID uint32, b []byte) bool {
+	if a.maxChunks != 0 && a.count[reqID]+1 > a.maxChunks {
+		return true
+	}
+	size := len(a.partial[reqID]) + len(b)
+	if len(a.partial[reqID]) != 0 {
+		size -= symmetricChunkHeaderLen
+	}
+	return a.maxSize != 0 && size > int(a.maxSize)
+}
+
+// reject discards the chunks of reqID and returns the error for the Message too large.
+// The chunks of it arriving later are discarded until the final one.
+func (a *chunkAssembler) reject(reqID uint32, final bool) error {
+	delete(a.partial, reqID)
+	delete(a.count, reqID)
+	if final {
+		delete(a.rejected, reqID)
+	} else {
+		a.rejected[reqID] = true
+	}
+	return &AbortError{
+		RequestID:  reqID,
+		StatusCode: status.BadTcpMessageTooLarge,
+		Reason:     "message exceeds the limits",
+	}
+}
+
+// add adds the MessageChunk given.
+//
+// It returns the whole Message when the final chunk is added, and nil for
+// the intermediate chunks. For the abort chunk, the chunks kept are discarded
+// and *AbortError is returned. The chunks other than MSG type, or the ones
+// too short to be a chunk, are returned as they are.
+func (a *chunkAssembler) add(b []byte) ([]byte, error) {
+	if len(b) < symmetricChunkHeaderLen || string(b[:3]) != MessageTypeMessage {
+		return b, nil
+	}
+
+	reqID := binary.LittleEndian.Uint32(b[20:24])
+	if a.rejected[reqID] {
+		// the error has already been returned for the Message.
+		if string(b[3]) != ChunkTypeIntermediate {
+			delete(a.rejected, reqID)
+		}
+		return nil, nil
+	}
+
+	switch string(b[3]) {
+	case ChunkTypeIntermediate:
+		if a.exceeds(reqID, b) {
+			return nil, a.reject(reqID, false)
+		}
+		a.count[reqID]++
+		if p, ok := a.partial[reqID]; ok {
+			a.partial[reqID] = append(p, b[symmetricChunkHeaderLen:]...)
+		} else {
+			a.partial[reqID] = append([]byte{}, b...)
+		}
+		return nil, nil
+	case ChunkTypeFinal:
+		if a.exceeds(reqID, b) {
+			return nil, a.reject(reqID, true)
+		}
+		p, ok := a.partial[reqID]
+		if !ok {
+			return b, nil
+		}
+		delete(a.partial, reqID)
+		delete(a.count, reqID)
+
+		msg := append(p, b[symmetricChunkHeaderLen:]...)
+		msg[3] = ChunkTypeFinal[0]
+		binary.LittleEndian.PutUint32(msg[4:8], uint32(len(msg)))
+		return msg, nil
+	case ChunkTypeError:
+		delete(a.partial, reqID)
+		delete(a.count, reqID)
+
+		e := &AbortError{RequestID: reqID}
+		body := b[symmetricChunkHeaderLen:]
+		if len(body) >= 4 {
+			e.StatusCode = binary.LittleEndian.Uint32(body[:4])
+			if reason, err := datatypes.DecodeString(body[4:]); err == nil {
+				e.Reason = reason.Get()
+			}
+		}
+		return nil, e
+	default:
+		return b, nil
+	}
+}
@@ -0,0 +1,221 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package uasc
+
+import (
+	"encoding/binary"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/wmnsk/gopcua/datatypes"
+	"github.com/wmnsk/gopcua/services"
+	"github.com/wmnsk/gopcua/uacp"
+)
+
+// splitMessage splits the MSG given into the chunks with body of size n at most.
+// The last chunk has ChunkType=F and the others have ChunkType=C.
+func splitMessage(t *testing.T, msg []byte, n int) [][]byte {
+	t.Helper()
+
+	var chunks [][]byte
+	body := msg[symmetricChunkHeaderLen:]
+	for len(body) > 0 {
+		l := n
+		if len(body) < l {
+			l = len(body)
+		}
+
+		c := append(append([]byte{}, msg[:symmetricChunkHeaderLen]...), body[:l]...)
+		c[3] = ChunkTypeIntermediate[0]
+		binary.LittleEndian.PutUint32(c[4:8], uint32(len(c)))
+		chunks = append(chunks, c)
+		body = body[l:]
+	}
+	chunks[len(chunks)-1][3] = ChunkTypeFinal[0]
+
+	return chunks
+}
+
+func newTestMessage(t *testing.T) []byte {
+	t.Helper()
+
+	msg, err := New(services.NewBrowseResponse(
+		services.NewResponseHeader(
+			time.Date(2018, time.August, 10, 23, 0, 0, 0, time.UTC),
+			1, 0, services.NewNullDiagnosticInfo(), []string{}, services.NewNullAdditionalHeader(), nil,
+		),
+		nil,
+		datatypes.NewBrowseResult(
+			0, nil,
+			datatypes.NewReferenceDescription(
+				datatypes.NewTwoByteNodeID(35), true,
+				datatypes.NewFourByteExpandedNodeID(0, 2253),
+				datatypes.NewQualifiedName(0, "Server"),
+				datatypes.NewLocalizedText("", "Server"),
+				datatypes.NodeClassObject,
+				datatypes.NewFourByteExpandedNodeID(0, 2004),
+			),
+		),
+	), NewConfig(1, policyURI, nil, nil, 1, 42, services.SecModeNone, 1, 3600000)).Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return msg
+}
+
+func TestChunkAssembler(t *testing.T) {
+	msg := newTestMessage(t)
+	chunks := splitMessage(t, msg, (len(msg)-symmetricChunkHeaderLen)/3+1)
+	if len(chunks) != 3 {
+		t.Fatalf("got %d chunks, want 3", len(chunks))
+	}
+
+	a := newChunkAssembler(0, 0)
+	for i, c := range chunks[:2] {
+		got, err := a.add(c)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != nil {
+			t.Fatalf("chunk %d: got message before the final chunk", i)
+		}
+	}
+	got, err := a.add(chunks[2])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(got, msg); diff != "" {
+		t.Error(diff)
+	}
+	if len(a.partial) != 0 {
+		t.Errorf("%d partial messages left", len(a.partial))
+	}
+
+	if _, err := Decode(got); err != nil {
+		t.Errorf("failed to decode assembled message: %s", err)
+	}
+}
+
+func TestChunkAssemblerSingleChunk(t *testing.T) {
+	msg := newTestMessage(t)
+
+	got, err := newChunkAssembler(0, 0).add(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(got, msg); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestChunkAssemblerAbort(t *testing.T) {
+	msg := newTestMessage(t)
+	chunks := splitMessage(t, msg, (len(msg)-symmetricChunkHeaderLen)/3+1)
+
+	abort := append([]byte{}, msg[:symmetricChunkHeaderLen]...)
+	abort[3] = ChunkTypeError[0]
+	abort = append(abort,
+		// Error: BadRequestTooLarge
+		0x00, 0x00, 0xb8, 0x80,
+		// Reason
+		0x03, 0x00, 0x00, 0x00, 0x66, 0x6f, 0x6f,
+	)
+	binary.LittleEndian.PutUint32(abort[4:8], uint32(len(abort)))
+
+	a := newChunkAssembler(0, 0)
+	if _, err := a.add(chunks[0]); err != nil {
+		t.Fatal(err)
+	}
+	_, err := a.add(abort)
+	want := &AbortError{RequestID: 42, StatusCode: 0x80b80000, Reason: "foo"}
+	if diff := cmp.Diff(err, want); diff != "" {
+		t.Error(diff)
+	}
+	if len(a.partial) != 0 {
+		t.Errorf("%d partial messages left after abort", len(a.partial))
+	}
+}
+
+func TestChunkAssemblerLimits(t *testing.T) {
+	msg := newTestMessage(t)
+	chunks := splitMessage(t, msg, (len(msg)-symmetricChunkHeaderLen)/3+1)
+	want := &AbortError{RequestID: 42, StatusCode: 0x80800000, Reason: "message exceeds the limits"}
+
+	cases := []struct {
+		name               string
+		maxSize, maxChunks uint32
+		// rejected is the index of the chunk which the error is returned for.
+		rejected int
+	}{
+		{"max-chunks", 0, 2, 2},
+		{"max-size", uint32(len(msg) - 1), 0, 2},
+		{"max-size-intermediate", uint32(len(chunks[0])), 0, 1},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			a := newChunkAssembler(c.maxSize, c.maxChunks)
+			for i, chunk := range chunks {
+				got, err := a.add(chunk)
+				if i == c.rejected {
+					if diff := cmp.Diff(err, want); diff != "" {
+						t.Error(diff)
+					}
+					continue
+				}
+				// the chunks after the rejected one are discarded.
+				if err != nil || got != nil {
+					t.Fatalf("chunk %d: got %v, %v want nil, nil", i, got, err)
+				}
+			}
+			if len(a.partial) != 0 || len(a.rejected) != 0 {
+				t.Errorf("%d partial and %d rejected messages left", len(a.partial), len(a.rejected))
+			}
+
+			// the next message within the limits is not affected.
+			next := append([]byte{}, chunks[0]...)
+			next[3] = ChunkTypeFinal[0]
+			got, err := a.add(next)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(got, next); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+// negotiatedConn is a net.Conn which has the Limits negotiated.
+type negotiatedConn struct {
+	net.Conn
+	limits uacp.Limits
+}
+
+func (c *negotiatedConn) Negotiated() uacp.Limits {
+	return c.limits
+}
+
+func TestNewReceiveBuf(t *testing.T) {
+	cases := []struct {
+		name string
+		conn net.Conn
+		want int
+	}{
+		{"negotiated", &negotiatedConn{limits: uacp.Limits{ReceiveBufSize: 8192}}, 8192},
+		{"not-negotiated", &negotiatedConn{}, defaultReceiveBufSize},
+		{"no-limits", &net.TCPConn{}, defaultReceiveBufSize},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := len(newReceiveBuf(c.conn)); got != c.want {
+				t.Errorf("got %d want %d", got, c.want)
+			}
+		})
+	}
+}
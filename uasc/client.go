@@ -16,7 +16,8 @@ import (
 
 // OpenSecureChannel acts like net.Dial for OPC UA Secure Conversation network.
 //
-// Currently security mode=None is only supported. If secMode is not set to
+// The SecurityPolicy None and Basic256Sha256 are supported. For Basic256Sha256, cfg should have
+// the Certificate and PrivateKey of the client and the RemoteCertificate of the server.
 //
 // The first param ctx is to be passed to monitor(), which monitors and handles
 // incoming messages automatically in another goroutine.
@@ -24,6 +25,10 @@ func OpenSecureChannel(ctx context.Context, transportConn net.Conn, cfg *Config,
 	if err := cfg.validate("client"); err != nil {
 		return nil, err
 	}
+	// the Config is updated by the SecureChannel, which should not be shared with the others
+	// opened with the same Config.
+	c := *cfg
+	cfg = &c
 
 	secChan := &SecureChannel{
 		mu:        new(sync.Mutex),
@@ -36,12 +41,28 @@ func OpenSecureChannel(ctx context.Context, transportConn net.Conn, cfg *Config,
 			time.Time{}, 0, 0, services.NewNullDiagnosticInfo(),
 			[]string{}, services.NewNullAdditionalHeader(), nil,
 		),
-		cfg:     cfg,
-		state:   cliStateSecureChannelClosed,
-		opened:  make(chan bool),
-		lenChan: make(chan int),
-		errChan: make(chan error),
-		rcvBuf:  make([]byte, 0xffff),
+		cfg:         cfg,
+		logger:      cfg.logger(),
+		state:       cliStateSecureChannelClosed,
+		opened:      make(chan bool),
+		rcvChan:     make(chan []byte),
+		closed:      make(chan struct{}),
+		broken:      make(chan struct{}),
+		errChan:     make(chan error),
+		abortChan:   make(chan error),
+		rcvBuf:      newReceiveBuf(transportConn),
+		outstanding: map[uint32]uint32{},
+	}
+
+	if !isSecurityPolicyNone(cfg.SecurityPolicyURI) {
+		if err := cfg.verifyRemote(cfg.RemoteCertificate); err != nil {
+			return nil, err
+		}
+		key, err := parsePublicKey(cfg.RemoteCertificate)
+		if err != nil {
+			return nil, err
+		}
+		secChan.remoteKey = key
 	}
 
 	if err := secChan.OpenSecureChannelRequest(); err != nil {
@@ -74,40 +95,93 @@ func OpenSecureChannel(ctx context.Context, transportConn net.Conn, cfg *Config,
 
 // CreateSession creates a session on top of SecureChannel.
 func CreateSession(ctx context.Context, secChan *SecureChannel, cfg *SessionConfig, maxRetry int, interval time.Duration) (*Session, error) {
-	session := &Session{
-		mu:        new(sync.Mutex),
-		secChan:   secChan,
-		cfg:       cfg,
-		state:     cliStateSessionClosed,
-		created:   make(chan bool),
-		activated: make(chan bool),
-		lenChan:   make(chan int),
-		errChan:   make(chan error),
-		rcvBuf:    make([]byte, 0xffff),
+	session := newClientSession(secChan, cfg, cliStateSessionClosed)
+	go session.monitor(ctx)
+	if err := session.create(maxRetry, interval); err != nil {
+		return nil, err
 	}
+	return session, nil
+}
 
-	if err := session.CreateSessionRequest(); err != nil {
-		return nil, err
+// ResumeSession activates the Session old on the new SecureChannel given, so that the Session
+// created on a SecureChannel which is lost can be used again without creating it from scratch.
+//
+// If the server rejects the Session, e.g. it has already been timed out, a new Session is
+// created with the SessionConfig of old and activated instead. resumed reports whether
+// the Session old is activated, or the returned one is a new Session.
+func ResumeSession(ctx context.Context, secChan *SecureChannel, old *Session, maxRetry int, interval time.Duration) (session *Session, resumed bool, err error) {
+	old.mu.Lock()
+	if old.cfg == nil {
+		// the Session is closed.
+		old.mu.Unlock()
+		return nil, false, ErrInvalidState
 	}
-	sent := 1
+	cfg := *old.cfg
+	old.mu.Unlock()
 
-	session.state = cliStateCreateSessionSent
+	secChan.reqHeader.AuthenticationToken = old.AuthenticationToken()
+	session = newClientSession(secChan, &cfg, cliStateSessionCreated)
 	go session.monitor(ctx)
+
+	err = session.Activate()
+	if err == nil {
+		return session, true, nil
+	}
+	if err != ErrRejected {
+		return nil, false, err
+	}
+
+	if err := session.create(maxRetry, interval); err != nil {
+		return nil, false, err
+	}
+	if err := session.Activate(); err != nil {
+		return nil, false, err
+	}
+	return session, false, nil
+}
+
+func newClientSession(secChan *SecureChannel, cfg *SessionConfig, state sessionState) *Session {
+	return &Session{
+		mu:            new(sync.Mutex),
+		secChan:       secChan,
+		cfg:           cfg,
+		state:         state,
+		created:       make(chan bool),
+		activated:     make(chan bool),
+		sessionClosed: make(chan struct{}),
+		rcvChan:       make(chan []byte),
+		closed:        make(chan struct{}),
+		broken:        make(chan struct{}),
+		errChan:       make(chan error),
+		abortChan:     make(chan error),
+		rcvBuf:        newReceiveBuf(secChan.lowerConn),
+	}
+}
+
+// create sends CreateSessionRequest and waits for the response, sending it again
+// every interval until maxRetry requests are sent.
+func (s *Session) create(maxRetry int, interval time.Duration) error {
+	s.setState(cliStateCreateSessionSent)
+	if err := s.CreateSessionRequest(); err != nil {
+		return err
+	}
+	sent := 1
+
 	for {
 		if sent > maxRetry {
-			return nil, ErrTimeout
+			return ErrTimeout
 		}
 
 		select {
-		case ok := <-session.created:
+		case ok := <-s.created:
 			if ok {
-				return session, nil
+				return nil
 			}
-		case err := <-session.errChan:
-			return nil, err
+		case err := <-s.errChan:
+			return err
 		case <-time.After(interval):
-			if err := session.CreateSessionRequest(); err != nil {
-				return nil, err
+			if err := s.CreateSessionRequest(); err != nil {
+				return err
 			}
 			sent++
 		}
@@ -116,12 +190,14 @@ func CreateSession(ctx context.Context, secChan *SecureChannel, cfg *SessionConf
 
 // Activate activates the session.
 func (s *Session) Activate() error {
+	// the state is updated before sending, as the response may be handled
+	// by monitor before ActivateSessionRequest returns.
+	s.setState(cliStateActivateSessionSent)
 	if err := s.ActivateSessionRequest(); err != nil {
 		return err
 	}
 	sent := 0
 
-	s.state = cliStateActivateSessionSent
 	for {
 		if sent > 3 {
 			return ErrTimeout
@@ -142,3 +218,9 @@ func (s *Session) Activate() error {
 		}
 	}
 }
+
+func (s *Session) setState(state sessionState) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.state = state
+}
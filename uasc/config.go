@@ -6,6 +6,7 @@ package uasc
 
 import (
 	"crypto/rand"
+	"crypto/rsa"
 	"encoding/binary"
 	"time"
 
@@ -45,6 +46,22 @@ type Config struct {
 	// This indicates what public key was used to encrypt the MessageChunk.
 	// This field shall be null if the Message is not encrypted.
 	Thumbprint []byte
+	// PrivateKey is the private key of the Certificate, which is used to sign and decrypt the
+	// OpenSecureChannel messages.
+	// This field is not required if the SecurityPolicy is None.
+	PrivateKey *rsa.PrivateKey
+	// RemoteCertificate is the DER encoded X.509 v3 Certificate of the Server, which is used to
+	// encrypt and verify the OpenSecureChannel messages when the SecureChannel works as client.
+	// Thumbprint is calculated from it if not set.
+	// Server gets the Certificate of the Client from the OpenSecureChannelRequest instead.
+	RemoteCertificate []byte
+	// TrustStore verifies the Certificate of the remote in OpenSecureChannel, which is the
+	// RemoteCertificate for client and the one in the OpenSecureChannelRequest for server.
+	// The Certificate is not verified if TrustStore is nil.
+	TrustStore *TrustStore
+	// RemoteApplicationURI is the ApplicationUri of the remote, which should be in the
+	// SubjectAltName of its Certificate. It is not checked if empty.
+	RemoteApplicationURI string
 	// SequenceNumber is a monotonically increasing sequence number assigned by the sender to each
 	// MessageChunk sent over the SecureChannel.
 	SequenceNumber uint32
@@ -68,8 +85,25 @@ type Config struct {
 	// Lifetime can also be the revised lifetime, the lifetime of the SecurityToken in milliseconds.
 	// The UTC expiration time for the token may be calculated by adding the lifetime to the createdAt time.
 	Lifetime uint32
+	// RenewalThreshold is the fraction of the revised lifetime of the SecurityToken after which the
+	// client renews the SecureChannel. It should be greater than 0 and less than 1, otherwise
+	// DefaultRenewalThreshold is used.
+	RenewalThreshold float64
+	// Logger logs the events in the SecureChannel and the Session. Nothing is logged if nil.
+	Logger Logger
 }
 
+// logger returns the Logger in Config, or the one discarding everything if not set.
+func (c *Config) logger() Logger {
+	if c.Logger == nil {
+		return nopLogger{}
+	}
+	return c.Logger
+}
+
+// DefaultRenewalThreshold is the RenewalThreshold used if it is not set in Config.
+const DefaultRenewalThreshold = 0.75
+
 // NewConfig creates a new Config.
 //
 // This contains all the parameter Config has, but the ones should be set depends on the application type.
@@ -112,25 +146,43 @@ func NewClientConfigSecurityNone(reqID, lifetime uint32) *Config {
 	)
 }
 
-/* XXX - to be uncommented when encryption is
+// NewClientConfigSecurity creates a new Config for Client with the SecurityPolicy and
+// the MessageSecurityMode given, which is Sign or SignAndEncrypt.
+//
+// The cert and key are of the Client, and the serverCert is the Certificate of the Server
+// which is available in the EndpointDescription.
+func NewClientConfigSecurity(policyURI string, secMode uint32, cert []byte, key *rsa.PrivateKey, serverCert []byte, reqID, lifetime uint32) *Config {
+	c := NewClientConfig(policyURI, cert, Thumbprint(serverCert), reqID, secMode, lifetime)
+	c.PrivateKey = key
+	c.RemoteCertificate = serverCert
+	return c
+}
+
 // NewClientConfigSignBasic256Sha256 creates a new Config for Client, with SecurityMode=Sign
 // and SecurityPolicy=Basic256Sha256.
-func NewClientConfigSignBasic256Sha256(cert, thumbprint []byte, reqID, lifetime uint32) *Config {
-	return NewClientConfig(
+//
+// The cert and key are of the Client, and the serverCert is the Certificate of the Server
+// which is available in the EndpointDescription.
+func NewClientConfigSignBasic256Sha256(cert []byte, key *rsa.PrivateKey, serverCert []byte, reqID, lifetime uint32) *Config {
+	return NewClientConfigSecurity(
 		"http://opcfoundation.org/UA/SecurityPolicy#Basic256Sha256",
-		cert, thumbprint, reqID, services.SecModeSign, lifetime,
+		services.SecModeSign, cert, key, serverCert, reqID, lifetime,
 	)
 }
 
 // NewClientConfigSignAndEncryptBasic256Sha256 creates a new Config for Client, with SecurityMode=SignAndEncrypt
 // and SecurityPolicy=Basic256Sha256.
-func NewClientConfigSignAndEncryptBasic256Sha256(cert, thumbprint []byte, reqID, lifetime uint32) *Config {
-	return NewClientConfig(
+//
+// The cert and key are of the Client, and the serverCert is the Certificate of the Server
+// which is available in the EndpointDescription.
+func NewClientConfigSignAndEncryptBasic256Sha256(cert []byte, key *rsa.PrivateKey, serverCert []byte, reqID, lifetime uint32) *Config {
+	return NewClientConfigSecurity(
 		"http://opcfoundation.org/UA/SecurityPolicy#Basic256Sha256",
-		cert, thumbprint, reqID, services.SecModeSignAndEncrypt, lifetime,
+		services.SecModeSignAndEncrypt, cert, key, serverCert, reqID, lifetime,
 	)
 }
 
+/* XXX - to be uncommented when encryption is
 // NewClientConfigSignAes128Sha256RsaOaep creates a new Config for Client, with SecurityMode=Sign
 // and SecurityPolicy=Aes128_Sha256_RsaOaep.
 func NewClientConfigSignAes128Sha256RsaOaep(cert, thumbprint []byte, reqID, lifetime uint32) *Config {
@@ -204,19 +256,46 @@ func NewServerConfig(policyURI string, cert, thumbprint []byte, chanID, secMode,
 
 // validate validates Config. This is just to avoid crash. Strange values would be accepted for flexibility.
 func (c *Config) validate(appType string) error {
+	if err := c.validateSecurityMode(); err != nil {
+		return err
+	}
+
 	switch appType {
 	case "client":
 		return c.validateClientConfig()
 	case "server":
-		return c.validateClientConfig()
+		return c.validateServerConfig()
 	default:
 		return errors.New("invalid type. should be client or server")
 	}
 }
 
+// validateSecurityMode checks the SecurityMode is the one of MessageSecurityMode and
+// consistent with the SecurityPolicy. The messages are not secured only if both are None.
+func (c *Config) validateSecurityMode() error {
+	switch c.SecurityMode {
+	case services.SecModeNone:
+		if !isSecurityPolicyNone(c.SecurityPolicyURI) {
+			return errors.Errorf("SecurityMode should be Sign or SignAndEncrypt for SecurityPolicy %s", c.SecurityPolicyURI)
+		}
+	case services.SecModeSign, services.SecModeSignAndEncrypt:
+		if isSecurityPolicyNone(c.SecurityPolicyURI) {
+			return errors.New("SecurityMode should be None for SecurityPolicy None")
+		}
+	default:
+		return errors.Errorf("SecurityMode %d is not MessageSecurityMode", c.SecurityMode)
+	}
+	return nil
+}
+
 func (c *Config) validateClientConfig() error {
-	if c.SecurityMode == services.SecModeSignAndEncrypt && (c.Certificate == nil || c.Thumbprint == nil) {
-		return errors.New("Certificate, Thumbprint is required when using SignAndEncrypt")
+	if !isSecurityPolicyNone(c.SecurityPolicyURI) {
+		if c.Certificate == nil || c.PrivateKey == nil || c.RemoteCertificate == nil {
+			return errors.New("Certificate, PrivateKey and RemoteCertificate are required when SecurityPolicy is not None")
+		}
+		if c.Thumbprint == nil {
+			c.Thumbprint = Thumbprint(c.RemoteCertificate)
+		}
 	}
 
 	if c.SecurityMode == services.SecModeNone {
@@ -226,7 +305,17 @@ func (c *Config) validateClientConfig() error {
 	return nil
 }
 
+func (c *Config) renewalThreshold() float64 {
+	if c.RenewalThreshold <= 0 || c.RenewalThreshold >= 1 {
+		return DefaultRenewalThreshold
+	}
+	return c.RenewalThreshold
+}
+
 func (c *Config) validateServerConfig() error {
+	if !isSecurityPolicyNone(c.SecurityPolicyURI) && (c.Certificate == nil || c.PrivateKey == nil) {
+		return errors.New("Certificate and PrivateKey are required when SecurityPolicy is not None")
+	}
 	if c.SecurityMode == services.SecModeNone {
 		c.Certificate = nil
 		c.Thumbprint = nil
@@ -275,6 +364,9 @@ type SessionConfig struct {
 	// The SignatureAlgorithm depends on the identity token type.
 	// The SignatureData type is defined in 7.32.
 	UserTokenSignature *services.SignatureData
+	// UserTokenPrivateKey is the private key of the certificate in X509IdentityToken, which is
+	// used to create the UserTokenSignature on ActivateSession instead of the one given above.
+	UserTokenPrivateKey *rsa.PrivateKey
 	// If Session works as a client, SessionTimeout is the requested maximum number of milliseconds
 	// that a Session should remain open without activity. If the Client fails to issue a Service
 	// request within this interval, then the Server shall automatically terminate the Client Session.
@@ -289,6 +381,9 @@ type SessionConfig struct {
 	// signatureToSend is the client/serverSignature defined in Part4, Table 15 and Table 17.
 	// This parameter is automatically calculated and kept temporarily until it is sent in next message.
 	signatureToSend *services.SignatureData
+	// serverNonce and serverCertificate are the ones given by the server in the last
+	// Create/ActivateSessionResponse, which are used to encrypt the password of UserNameIdentityToken.
+	serverNonce, serverCertificate []byte
 }
 
 // NewClientSessionConfig creates a SessionConfig for client.
@@ -20,6 +20,20 @@ var (
 	ErrSecureChannelNotOpened  = errors.New("secure channel not opened")
 	ErrSecurityModeUnsupported = errors.New("got request with unsupported SecurityMode")
 	ErrRejected                = errors.New("rejected by server")
+	ErrSecurityPolicyMismatch  = errors.New("got message with unexpected SecurityPolicy")
+	ErrUnknownSecurityToken    = errors.New("got message with unknown SecurityToken")
+	ErrInvalidMessageSignature = errors.New("signature of message is invalid")
+	ErrInvalidSequenceNumber   = errors.New("got message with invalid SequenceNumber")
+	ErrUnexpectedRequestID     = errors.New("got response with unexpected RequestID")
+	ErrInvalidNonce            = errors.New("got nonce with invalid length")
+)
+
+// Errors for Certificate validation.
+// XXX - to be integrated in errors package.
+var (
+	ErrUntrustedCertificate   = errors.New("certificate is not trusted")
+	ErrCertificateExpired     = errors.New("certificate is expired or not yet valid")
+	ErrCertificateURIMismatch = errors.New("certificate does not contain the ApplicationUri")
 )
 
 // Errors for Session handling.
@@ -29,4 +43,5 @@ var (
 	ErrSessionNotActivated        = errors.New("session is not activated")
 	ErrInvalidSignatureAlgorithm  = errors.New("algorithm in signature doesn't match")
 	ErrInvalidSignatureData       = errors.New("signature is invalid")
+	ErrNoUserTokenPolicy          = errors.New("no UserTokenPolicy for the UserIdentityToken")
 )
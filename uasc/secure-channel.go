@@ -6,12 +6,16 @@ package uasc
 
 import (
 	"context"
-	"crypto/rand"
+	"crypto/rsa"
+	"encoding/binary"
 	"io"
+	"math"
 	"net"
 	"sync"
 	"time"
 
+	"github.com/wmnsk/gopcua/datatypes"
+	"github.com/wmnsk/gopcua/securitypolicy"
 	"github.com/wmnsk/gopcua/services"
 	"github.com/wmnsk/gopcua/status"
 	"github.com/wmnsk/gopcua/uacp"
@@ -29,8 +33,41 @@ type SecureChannel struct {
 	rcvBuf, sndBuf []byte
 	state          secChanState
 	opened         chan bool
-	lenChan        chan int
+	rcvChan        chan []byte
+	closed         chan struct{}
 	errChan        chan error
+	abortChan      chan error
+	renewTimer     *time.Timer
+	logger         Logger
+
+	// broken is closed when the monitor stops on an error in reading from lowerConn,
+	// and readErr is the error returned by Read after that.
+	broken  chan struct{}
+	readErr error
+
+	// remoteKey is the public key of the remote used for OpenSecureChannel.
+	remoteKey *rsa.PublicKey
+	// localNonce and remoteNonce are the nonces exchanged in the last OpenSecureChannel.
+	localNonce, remoteNonce []byte
+	// keys are the symmetric algorithms derived from the nonces for each SecurityToken.
+	keys        map[uint32]*securitypolicy.EncryptionAlgorithm
+	lastTokenID uint32
+
+	// pending is the message which did not fit in the buffer given to the last Read.
+	pending []byte
+
+	// rcvSeqNum is the SequenceNumber of the last chunk received, valid if hasRcvSeqNum is true.
+	rcvSeqNum    uint32
+	hasRcvSeqNum bool
+	// idMu guards the SequenceNumber and the RequestID in cfg and outstanding, which are
+	// updated by writeMessage without s.mu as some of the requests are sent without it.
+	idMu sync.Mutex
+	// outstanding is the RequestHandles of the requests sent by the client and not responded yet
+	// for each RequestID.
+	outstanding map[uint32]uint32
+	// reqIDs is the RequestIDs of the requests received by the server for each RequestHandle,
+	// which are used for the responses to them.
+	reqIDs map[uint32]uint32
 }
 
 // Read reads data from the connection.
@@ -38,38 +75,66 @@ type SecureChannel struct {
 // after a fixed time limit; see SetDeadline and SetReadDeadline.
 //
 // If the data is one of OpenSecureChannel or CloseSecureChannel, it will be handled automatically.
+//
+// The Message sent in multiple MessageChunks is returned after all the chunks are received.
+// If b is too small to hold the Message, Read returns io.ErrShortBuffer with the length
+// required, and the Message is returned by the next Read.
 func (s *SecureChannel) Read(b []byte) (n int, err error) {
-	n, err = s.read(b)
-	copy(b, s.rcvBuf[:n])
+	msg, err := s.next()
+	if err != nil {
+		return 0, err
+	}
+	if len(b) < len(msg) {
+		s.pending = append([]byte{}, msg...)
+		return len(msg), io.ErrShortBuffer
+	}
 
-	return n, nil
+	return copy(b, msg), nil
 }
 
 // ReadService reads the payload(=Service) from the connection.
 // Which means the UASC Headers are omitted.
+//
+// Like Read, io.ErrShortBuffer is returned if b is too small to hold the payload.
 func (s *SecureChannel) ReadService(b []byte) (n int, err error) {
-	n, err = s.read(b)
+	msg, err := s.next()
+	if err != nil {
+		return 0, err
+	}
 
-	sc, err := Decode(s.rcvBuf[:n])
+	sc, err := Decode(msg)
 	if err != nil {
 		return 0, err
 	}
+	if len(b) < len(sc.SequenceHeader.Payload) {
+		s.pending = append([]byte{}, msg...)
+		return len(sc.SequenceHeader.Payload), io.ErrShortBuffer
+	}
 
-	copy(b, sc.SequenceHeader.Payload)
-	return int(sc.MessageSize), nil
+	return copy(b, sc.SequenceHeader.Payload), nil
 }
 
-func (s *SecureChannel) read(b []byte) (n int, err error) {
-	if !(s.state == cliStateSecureChannelOpened || s.state == srvStateSecureChannelOpened) {
-		return 0, ErrSecureChannelNotOpened
+// next returns the Message left by the last Read if any, or the next one received.
+func (s *SecureChannel) next() ([]byte, error) {
+	if s.pending != nil {
+		msg := s.pending
+		s.pending = nil
+		return msg, nil
+	}
+
+	if !s.isOpened() {
+		return nil, ErrSecureChannelNotOpened
 	}
 	for {
 		select {
-		case n, ok := <-s.lenChan:
-			if !ok {
-				return 0, ErrSecureChannelNotOpened
-			}
-			return n, nil
+		case msg := <-s.rcvChan:
+			return msg, nil
+		case <-s.closed:
+			return nil, ErrSecureChannelNotOpened
+		case <-s.broken:
+			return nil, s.readErr
+		case err := <-s.abortChan:
+			return nil, err
 		}
 	}
 }
@@ -78,11 +143,35 @@ func (s *SecureChannel) read(b []byte) (n int, err error) {
 // Write can be made to time out and return an Error with Timeout() == true
 // after a fixed time limit; see SetDeadline and SetWriteDeadline.
 func (s *SecureChannel) Write(b []byte) (n int, err error) {
-	if s == nil || !(s.state == cliStateSecureChannelOpened || s.state == srvStateSecureChannelOpened) {
+	if s == nil {
 		return 0, ErrSecureChannelNotOpened
 	}
 
-	return s.lowerConn.Write(b)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !(s.state == cliStateSecureChannelOpened || s.state == srvStateSecureChannelOpened) {
+		return 0, ErrSecureChannelNotOpened
+	}
+	return s.write(b)
+}
+
+// isOpened reports whether the SecureChannel is opened.
+func (s *SecureChannel) isOpened() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state == cliStateSecureChannelOpened || s.state == srvStateSecureChannelOpened
+}
+
+// write secures the MessageChunk b and writes it to the lower connection.
+//
+// This should be called with s.mu held.
+func (s *SecureChannel) write(b []byte) (n int, err error) {
+	secured, err := s.secure(b)
+	if err != nil {
+		return 0, err
+	}
+	s.logger.Debug("sending chunk", "type", chunkType(b), "size", len(secured))
+	return s.lowerConn.Write(secured)
 }
 
 // WriteService writes data to the connection.
@@ -90,24 +179,29 @@ func (s *SecureChannel) Write(b []byte) (n int, err error) {
 // while the UASC header is automatically set by the package.
 // This enables writing arbitrary Service even if the service is not implemented in the package.
 func (s *SecureChannel) WriteService(b []byte) (n int, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	if !(s.state == cliStateSecureChannelOpened || s.state == srvStateSecureChannelOpened) {
 		return 0, ErrSecureChannelNotOpened
 	}
-	s.cfg.SequenceNumber++
-
-	msg := New(nil, s.cfg)
-	msg.MessageSize += uint32(len(b))
-	serialized, err := msg.Serialize()
-	if err != nil {
-		return 0, err
-	}
-	serialized = append(serialized, b...)
+	s.setResponseID(b)
 
-	if _, err := s.lowerConn.Write(serialized); err != nil {
+	var size uint32
+	if _, err := s.writeMessage(idRequest, func(cfg *Config) ([]byte, error) {
+		msg := New(nil, cfg)
+		msg.MessageSize += uint32(len(b))
+		size = msg.MessageSize
+		h, err := msg.Serialize()
+		if err != nil {
+			return nil, err
+		}
+		return append(h, b...), nil
+	}); err != nil {
 		return 0, err
 	}
 
-	return int(msg.MessageSize), nil
+	return int(size), nil
 }
 
 // Close closes the connection.
@@ -135,14 +229,19 @@ func (s *SecureChannel) Close() error {
 }
 
 func (s *SecureChannel) close() {
+	if s.renewTimer != nil {
+		s.renewTimer.Stop()
+		s.renewTimer = nil
+	}
+
+	s.idMu.Lock()
 	s.cfg = nil
+	s.idMu.Unlock()
 	s.reqHeader = nil
 	s.resHeader = nil
-	s.rcvBuf = []byte{}
-	s.sndBuf = []byte{}
 
 	close(s.errChan)
-	close(s.lenChan)
+	close(s.closed)
 	close(s.opened)
 }
 
@@ -217,39 +316,106 @@ func (s *SecureChannel) SetWriteDeadline(t time.Time) error {
 
 func (s *SecureChannel) monitor(ctx context.Context) {
 	childCtx, cancel := context.WithCancel(ctx)
+	chunks := newChunkAssemblerFor(s.lowerConn)
 	for {
 		select {
 		case <-ctx.Done():
 			cancel()
 			return
 		default:
-			n, err := s.lowerConn.Read(s.rcvBuf)
+			buf := s.rcvBuf
+			n, err := s.lowerConn.Read(buf)
+			if err == io.ErrShortBuffer {
+				// the chunk is larger than rcvBuf.
+				// It is read again after extending rcvBuf.
+				s.rcvBuf = make([]byte, n)
+				continue
+			}
 			if err != nil {
-				if err == io.EOF {
-					continue
-				}
+				s.readErr = err
+				close(s.broken)
 				cancel()
 				return
 			}
 			if n == 0 {
 				continue
 			}
-			if len(s.rcvBuf) < n {
+			if len(buf) < n {
+				continue
+			}
+
+			s.logger.Debug("received chunk", "type", chunkType(buf[:n]), "size", n)
+			s.mu.Lock()
+			if s.cfg == nil {
+				// the SecureChannel is closed while reading.
+				s.mu.Unlock()
+				cancel()
+				return
+			}
+			chunk, err := s.unsecure(buf[:n])
+			s.mu.Unlock()
+			if err != nil {
+				// the tampered message is rejected rather than dropped silently,
+				// so that the user waiting for it knows the security checks failed.
+				if err == ErrInvalidMessageSignature {
+					s.logger.Warn("rejected chunk", "error", err)
+					s.reject(status.BadSecurityChecksFailed, err)
+					cancel()
+					return
+				}
+				s.logger.Warn("dropped chunk", "error", err)
+				continue
+			}
+
+			s.mu.Lock()
+			handle := s.outstandingHandle(chunk)
+			err = s.checkChunk(chunk)
+			s.mu.Unlock()
+			if err != nil {
+				s.logger.Warn("rejected chunk", "error", err)
+				code := uint32(status.BadSecurityChecksFailed)
+				if err == ErrInvalidSequenceNumber {
+					code = status.BadSequenceNumberInvalid
+				}
+				s.reject(code, err)
+				cancel()
+				return
+			}
+
+			// wait for the rest of chunks if the message is not complete.
+			b, err := chunks.add(chunk)
+			if err != nil {
+				if e, ok := err.(*AbortError); ok {
+					e.RequestHandle = handle
+				}
+				go s.notifyAbort(childCtx, err)
+				continue
+			}
+			if b == nil {
 				continue
 			}
+			// rcvBuf is reused by the next Read, while b is passed to the user as it is.
+			b = append([]byte{}, b...)
 
-			msg, err := Decode(s.rcvBuf[:n])
+			msg, err := Decode(b)
 			if err != nil {
 				// pass to the user if msg is undecodable as UASC.
-				go s.notifyLength(childCtx, n)
+				s.notify(childCtx, b)
 				continue
 			}
 
-			s.cfg.RequestID = msg.RequestID
+			s.mu.Lock()
+			s.addResponseID(msg)
+			s.mu.Unlock()
 			switch m := msg.Service.(type) {
 			case *services.OpenSecureChannelRequest:
 				go s.handleOpenSecureChannelRequest(m)
 			case *services.OpenSecureChannelResponse:
+				// the keys are installed here rather than in the handler, so that the
+				// messages secured with the new SecurityToken can be read right after this.
+				s.mu.Lock()
+				s.installResponseKeys(m)
+				s.mu.Unlock()
 				go s.handleOpenSecureChannelResponse(m)
 			case *services.CloseSecureChannelRequest:
 				go s.handleCloseSecureChannelRequest(m)
@@ -258,23 +424,269 @@ func (s *SecureChannel) monitor(ctx context.Context) {
 			default:
 				// pass to the user if type of msg is not
 				// related to SecureChannel establishment.
-				go s.notifyLength(childCtx, n)
+				s.notify(childCtx, b)
 			}
 		}
 	}
 }
 
-func (s *SecureChannel) notifyLength(ctx context.Context, n int) {
-	select {
-	case <-ctx.Done():
-		return
-	case s.lenChan <- n:
-		return
+// chunkType returns the MessageType and the ChunkType of the MessageChunk b, e.g. "MSGF".
+func chunkType(b []byte) string {
+	if len(b) < 4 {
+		return ""
+	}
+	return string(b[:4])
+}
+
+// maxSequenceNumber is the largest SequenceNumber before it wraps around.
+// The SequenceNumber next to the ones larger than this should be less than 1024.
+const maxSequenceNumber = 4294966271
+
+// checkChunk verifies the SequenceNumber and the RequestID of the chunk b received.
+//
+// The SequenceNumber should be the last one plus one, or less than 1024 when the last
+// one exceeds maxSequenceNumber. The RequestID of the response the client receives
+// should be the one of the request sent and not responded yet, and it is released
+// when the final or the abort chunk arrives.
+//
+// This should be called with s.mu held.
+func (s *SecureChannel) checkChunk(b []byte) error {
+	if len(b) < headerLen {
+		return nil
+	}
+
+	var n int
+	switch string(b[:3]) {
+	case MessageTypeMessage, MessageTypeCloseSecureChannel:
+		// the SequenceHeader comes after the TokenId.
+		n = headerLen + 4
+	case MessageTypeOpenSecureChannel:
+		l, err := asymmetricHeaderLen(b)
+		if err != nil {
+			return nil
+		}
+		n = l
 	default:
-		if !(s.state == cliStateSecureChannelOpened || s.state == srvStateSecureChannelOpened) {
-			return
+		return nil
+	}
+	if len(b) < n+8 {
+		return nil
+	}
+	seq := binary.LittleEndian.Uint32(b[n : n+4])
+	reqID := binary.LittleEndian.Uint32(b[n+4 : n+8])
+
+	if s.hasRcvSeqNum {
+		switch {
+		case seq == s.rcvSeqNum+1:
+		case s.rcvSeqNum > maxSequenceNumber && seq < 1024:
+		default:
+			return ErrInvalidSequenceNumber
 		}
 	}
+	s.rcvSeqNum = seq
+	s.hasRcvSeqNum = true
+
+	// the CloseSecureChannel is not responded, and the ones from the server are requests.
+	if s.outstanding == nil || string(b[:3]) == MessageTypeCloseSecureChannel {
+		return nil
+	}
+	s.idMu.Lock()
+	defer s.idMu.Unlock()
+	if _, ok := s.outstanding[reqID]; !ok {
+		return ErrUnexpectedRequestID
+	}
+	if string(b[3]) != ChunkTypeIntermediate {
+		delete(s.outstanding, reqID)
+	}
+	return nil
+}
+
+// outstandingHandle returns the RequestHandle of the request which the chunk b responds to,
+// or 0 if it is not the response to the request sent by the client.
+func (s *SecureChannel) outstandingHandle(b []byte) uint32 {
+	if s.outstanding == nil || len(b) < symmetricChunkHeaderLen || string(b[:3]) != MessageTypeMessage {
+		return 0
+	}
+	s.idMu.Lock()
+	defer s.idMu.Unlock()
+	return s.outstanding[binary.LittleEndian.Uint32(b[20:24])]
+}
+
+// idKind tells how the RequestID of the message sent with writeMessage is assigned.
+type idKind int
+
+const (
+	// idResponse keeps the RequestID in cfg, which is the one of the request responded.
+	idResponse idKind = iota
+	// idRequest assigns a new RequestID to the request the client sends, which is kept
+	// as outstanding until the response arrives. It is the same as idResponse for the server.
+	idRequest
+	// idClose assigns a new RequestID to CloseSecureChannelRequest, which is not responded.
+	idClose
+)
+
+// nextID returns the SequenceNumber or the RequestID next to n,
+// which wraps around to 1 as 0 is not used for either of them.
+func nextID(n uint32) uint32 {
+	if n == math.MaxUint32 {
+		return 1
+	}
+	return n + 1
+}
+
+// writeMessage writes the message serialized by serialize with the Config which has
+// the next SequenceNumber and the RequestID assigned as kind tells.
+//
+// The numbers are assigned and the message is written while holding s.idMu, so that
+// the messages sent concurrently are numbered and written in the same order, and the
+// numbers are consumed only if the message is written so that they have no gaps.
+func (s *SecureChannel) writeMessage(kind idKind, serialize func(cfg *Config) ([]byte, error)) (int, error) {
+	s.idMu.Lock()
+	defer s.idMu.Unlock()
+
+	if s.cfg == nil {
+		return 0, ErrSecureChannelNotOpened
+	}
+	cfg := *s.cfg
+	cfg.SequenceNumber = nextID(cfg.SequenceNumber)
+	request := kind == idClose || (kind == idRequest && s.outstanding != nil)
+	if request {
+		cfg.RequestID = nextID(cfg.RequestID)
+	}
+
+	// the RequestHandle taken by serialize is given back if the request is not sent,
+	// like the numbers above.
+	handle := s.reqHeader.RequestHandle
+	b, err := serialize(&cfg)
+	if err != nil {
+		s.reqHeader.RequestHandle = handle
+		return 0, err
+	}
+	n, err := s.write(b)
+	if err != nil {
+		s.reqHeader.RequestHandle = handle
+		return 0, err
+	}
+
+	s.cfg.SequenceNumber = cfg.SequenceNumber
+	if request {
+		s.cfg.RequestID = cfg.RequestID
+		if kind == idRequest {
+			s.outstanding[cfg.RequestID] = requestHandle(b)
+		}
+	}
+	return n, nil
+}
+
+// requestHandle returns the RequestHandle of the request in the Message b of MSG type,
+// or 0 if b is not.
+func requestHandle(b []byte) uint32 {
+	if len(b) < symmetricChunkHeaderLen || string(b[:3]) != MessageTypeMessage {
+		return 0
+	}
+	b = b[symmetricChunkHeaderLen:]
+	typeID, err := datatypes.DecodeExpandedNodeID(b)
+	if err != nil {
+		return 0
+	}
+	b = b[typeID.Len():]
+
+	// the RequestHandle comes after the AuthenticationToken and the Timestamp in the RequestHeader.
+	token, err := datatypes.DecodeNodeID(b)
+	if err != nil {
+		return 0
+	}
+	n := token.Len()
+	if len(b) < n+12 {
+		return 0
+	}
+	return binary.LittleEndian.Uint32(b[n+8 : n+12])
+}
+
+// addResponseID keeps the RequestID of the request msg the server receives, so that
+// the response to it is sent with the same RequestID. It does nothing for the client.
+//
+// This should be called with s.mu held.
+func (s *SecureChannel) addResponseID(msg *Message) {
+	if s.reqIDs == nil || s.cfg == nil {
+		return
+	}
+	s.idMu.Lock()
+	s.cfg.RequestID = msg.RequestID
+	s.idMu.Unlock()
+	// OpenSecureChannel and CloseSecureChannel are responded with the RequestID above.
+	if msg.MessageTypeValue() != MessageTypeMessage {
+		return
+	}
+	if r, ok := msg.Service.(interface {
+		Header() *services.RequestHeader
+	}); ok {
+		s.reqIDs[r.Header().RequestHandle] = msg.RequestID
+	}
+}
+
+// setResponseID sets the RequestID of the request responded by the serialized
+// response b the server sends. The RequestID of the last request received is used
+// if the RequestHandle in b is unknown. It does nothing for the client.
+//
+// This should be called with s.mu held.
+func (s *SecureChannel) setResponseID(b []byte) {
+	if s.reqIDs == nil {
+		return
+	}
+	typeID, err := datatypes.DecodeExpandedNodeID(b)
+	if err != nil {
+		return
+	}
+	// the RequestHandle comes after the Timestamp in the ResponseHeader.
+	n := typeID.Len()
+	if len(b) < n+12 {
+		return
+	}
+	handle := binary.LittleEndian.Uint32(b[n+8 : n+12])
+	if reqID, ok := s.reqIDs[handle]; ok {
+		s.idMu.Lock()
+		s.cfg.RequestID = reqID
+		s.idMu.Unlock()
+		delete(s.reqIDs, handle)
+	}
+}
+
+// notify passes the message to Read. The messages are passed in the order received,
+// and the monitor waits for Read to take it rather than dropping it.
+func (s *SecureChannel) notify(ctx context.Context, b []byte) {
+	select {
+	case <-ctx.Done():
+	case <-s.closed:
+	case s.rcvChan <- b:
+	}
+}
+
+// reject sends the Error message with code to the peer, as the SecureChannel can no
+// longer be trusted. Read returns err after that, and the connection should be closed.
+//
+// This should be called by monitor, which stops reading after that.
+func (s *SecureChannel) reject(code uint32, err error) {
+	if c, ok := s.lowerConn.(interface {
+		Error(code uint32, reason string) error
+	}); ok {
+		// the Error message should not be interleaved with the message being written.
+		s.idMu.Lock()
+		c.Error(code, err.Error())
+		s.idMu.Unlock()
+	}
+	s.readErr = err
+	close(s.broken)
+}
+
+// notifyAbort passes the error to Read. abortChan is never closed, as notifyAbort may
+// still be running after close, which stops it with closed instead.
+func (s *SecureChannel) notifyAbort(ctx context.Context, err error) {
+	select {
+	case <-ctx.Done():
+	case <-s.closed:
+	case s.abortChan <- err:
+	}
 }
 
 func (s *SecureChannel) handleOpenSecureChannelRequest(o *services.OpenSecureChannelRequest) {
@@ -285,12 +697,21 @@ func (s *SecureChannel) handleOpenSecureChannelRequest(o *services.OpenSecureCha
 	// if state is closed, server accepts OpenSecureChannelRequest.
 	case srvStateSecureChannelClosed:
 		switch o.MessageSecurityMode {
-		// accepts only if MessageSecurityMode is None.
-		case services.SecModeNone:
+		// accepts only if MessageSecurityMode is the one configured.
+		case s.cfg.SecurityMode:
+			if err := s.checkNonce(o.ClientNonce); err != nil {
+				if err := s.OpenSecureChannelResponse(status.BadNonceInvalid); err != nil {
+					s.errChan <- err
+				}
+				s.errChan <- err
+				return
+			}
+			s.remoteNonce = o.ClientNonce.Get()
 			s.resHeader.RequestHandle = o.RequestHandle
 			if err := s.OpenSecureChannelResponse(0); err != nil {
 				s.errChan <- err
 			}
+			s.logger.Info("secure channel opened", "channel_id", s.cfg.SecureChannelID, "token_id", s.cfg.SecurityTokenID)
 			s.state = srvStateSecureChannelOpened
 			s.opened <- true
 		// respond with BadSecurityModeRejected and notify server
@@ -300,8 +721,33 @@ func (s *SecureChannel) handleOpenSecureChannelRequest(o *services.OpenSecureCha
 			}
 			s.errChan <- ErrSecurityModeUnsupported
 		}
-	// if SecureChannel is already opened, respond with BadAlreadyExists.
-	case srvStateSecureChannelOpened, srvStateCloseSecureChannelSent:
+	// if SecureChannel is opened, issue a new SecurityToken on renewal.
+	case srvStateSecureChannelOpened:
+		if o.SecurityTokenRequestType != services.ReqTypeRenew {
+			if err := s.OpenSecureChannelResponse(status.BadAlreadyExists); err != nil {
+				s.errChan <- err
+			}
+			return
+		}
+
+		if err := s.checkNonce(o.ClientNonce); err != nil {
+			s.logger.Error("secure channel renewal rejected", "error", err)
+			if err := s.OpenSecureChannelResponse(status.BadNonceInvalid); err != nil {
+				s.errChan <- err
+			}
+			return
+		}
+
+		s.cfg.SecurityTokenID++
+		s.remoteNonce = o.ClientNonce.Get()
+		s.resHeader.RequestHandle = o.RequestHandle
+		if err := s.OpenSecureChannelResponse(0); err != nil {
+			s.cfg.SecurityTokenID--
+			return
+		}
+		s.logger.Info("secure channel renewed", "channel_id", s.cfg.SecureChannelID, "token_id", s.cfg.SecurityTokenID)
+	// if SecureChannel is being closed, respond with BadAlreadyExists.
+	case srvStateCloseSecureChannelSent:
 		if err := s.OpenSecureChannelResponse(status.BadAlreadyExists); err != nil {
 			s.errChan <- err
 		}
@@ -322,16 +768,38 @@ func (s *SecureChannel) handleOpenSecureChannelResponse(o *services.OpenSecureCh
 	case cliStateOpenSecureChannelSent:
 		switch o.ServiceResult {
 		case 0: // Good
+			if err := s.checkNonce(o.ServerNonce); err != nil {
+				s.logger.Error("secure channel rejected", "error", err)
+				s.state = cliStateSecureChannelClosed
+				s.errChan <- err
+				return
+			}
 			s.cfg.SecureChannelID = o.SecurityToken.ChannelID
 			s.cfg.SecurityTokenID = o.SecurityToken.TokenID
 			s.state = cliStateSecureChannelOpened
+			s.logger.Info("secure channel opened", "channel_id", s.cfg.SecureChannelID, "token_id", s.cfg.SecurityTokenID)
+			s.scheduleRenewal(o.SecurityToken.RevisedLifetime)
 			s.opened <- true
 		case status.BadSecurityModeRejected:
+			s.logger.Error("secure channel rejected", "status", o.ServiceResult)
 			s.state = cliStateSecureChannelClosed
 			s.errChan <- ErrRejected
 		}
-	// if client SecureChannel is closed or opened, just ignore OpenSecureChannelResponse.
-	case cliStateSecureChannelClosed, cliStateSecureChannelOpened, cliStateCloseSecureChannelSent:
+	// if client SecureChannel is opened, the response is for the renewal.
+	// The new SecurityToken is used for the messages sent after this.
+	case cliStateSecureChannelOpened:
+		if o.ServiceResult != 0 || o.SecurityToken.ChannelID != s.cfg.SecureChannelID {
+			return
+		}
+		if err := s.checkNonce(o.ServerNonce); err != nil {
+			s.logger.Error("secure channel renewal rejected", "error", err)
+			return
+		}
+		s.cfg.SecurityTokenID = o.SecurityToken.TokenID
+		s.logger.Info("secure channel renewed", "channel_id", s.cfg.SecureChannelID, "token_id", s.cfg.SecurityTokenID)
+		s.scheduleRenewal(o.SecurityToken.RevisedLifetime)
+	// if client SecureChannel is closed, just ignore OpenSecureChannelResponse.
+	case cliStateSecureChannelClosed, cliStateCloseSecureChannelSent:
 	// server never accept OpenSecureChannelResponse , just ignore it.
 	case srvStateSecureChannelClosed, srvStateSecureChannelOpened, srvStateCloseSecureChannelSent:
 	// invalid secChanState. SecureChannel should be closed in error handler.
@@ -386,185 +854,165 @@ func (s *SecureChannel) handleCloseSecureChannelResponse(c *services.CloseSecure
 	}
 }
 
+// scheduleRenewal schedules the renewal of SecureChannel after the fraction of
+// lifetime given by cfg.RenewalThreshold has elapsed.
+//
+// This should be called with s.mu held.
+func (s *SecureChannel) scheduleRenewal(lifetime uint32) {
+	if s.renewTimer != nil {
+		s.renewTimer.Stop()
+		s.renewTimer = nil
+	}
+	if lifetime == 0 {
+		return
+	}
+
+	d := time.Duration(float64(lifetime)*s.cfg.renewalThreshold()) * time.Millisecond
+	s.renewTimer = time.AfterFunc(d, func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		if s.state != cliStateSecureChannelOpened {
+			return
+		}
+		s.RenewSecureChannelRequest()
+	})
+}
+
 // OpenSecureChannelRequest sends OpenSecureChannelRequest on top of UASC to SecureChannel.
 func (s *SecureChannel) OpenSecureChannelRequest() error {
-	nonce := make([]byte, 32)
-	if _, err := rand.Read(nonce); err != nil {
-		return err
-	}
+	return s.openSecureChannelRequest(services.ReqTypeIssue)
+}
 
-	s.cfg.SequenceNumber++
-	s.reqHeader.RequestHandle++
-	s.reqHeader.Timestamp = time.Now()
-	osc, err := New(
-		services.NewOpenSecureChannelRequest(
-			s.reqHeader, 0, services.ReqTypeIssue, s.cfg.SecurityMode, s.cfg.Lifetime, nonce,
-		), s.cfg).Serialize()
-	if err != nil {
-		s.cfg.SequenceNumber--
-		s.reqHeader.RequestHandle--
-		return err
-	}
+// RenewSecureChannelRequest sends OpenSecureChannelRequest with RequestType=Renew
+// on top of UASC to SecureChannel.
+//
+// The SecureChannel is renewed automatically before the SecurityToken expires,
+// so this is usually not expected to be called by users.
+func (s *SecureChannel) RenewSecureChannelRequest() error {
+	return s.openSecureChannelRequest(services.ReqTypeRenew)
+}
 
-	if _, err := s.lowerConn.Write(osc); err != nil {
-		s.cfg.SequenceNumber--
-		s.reqHeader.RequestHandle--
-		return err
+func (s *SecureChannel) openSecureChannelRequest(reqType uint32) error {
+	// the same nonce is used when retrying to issue the SecurityToken,
+	// as the response may be for any of the requests.
+	if reqType == services.ReqTypeRenew || s.localNonce == nil {
+		nonce, err := s.newNonce()
+		if err != nil {
+			return err
+		}
+		s.localNonce = nonce
 	}
-	return nil
+	nonce := s.localNonce
+
+	_, err := s.writeMessage(idRequest, func(cfg *Config) ([]byte, error) {
+		s.reqHeader.RequestHandle++
+		s.reqHeader.Timestamp = time.Now()
+		return New(services.NewOpenSecureChannelRequest(
+			s.reqHeader, 0, reqType, cfg.SecurityMode, cfg.Lifetime, nonce,
+		), cfg).Serialize()
+	})
+	return err
 }
 
 // OpenSecureChannelResponse sends OpenSecureChannelResponse on top of UASC to SecureChannel.
+//
+// If code is Good, the symmetric keys for the SecurityToken in cfg are derived from
+// the new nonce and the one in the last OpenSecureChannelRequest.
 func (s *SecureChannel) OpenSecureChannelResponse(code uint32) error {
-	nonce := make([]byte, 32)
-	if _, err := rand.Read(nonce); err != nil {
-		return err
-	}
-
-	s.cfg.SequenceNumber++
-	s.resHeader.ServiceResult = code
-	s.resHeader.Timestamp = time.Now()
-	osc, err := New(services.NewOpenSecureChannelResponse(
-		s.resHeader, 0, services.NewChannelSecurityToken(
-			s.cfg.SecureChannelID, s.cfg.SecurityTokenID, time.Now(), s.cfg.Lifetime,
-		), nonce,
-	), s.cfg).Serialize()
+	nonce, err := s.newNonce()
 	if err != nil {
-		s.cfg.SequenceNumber--
-		return err
-	}
-
-	if _, err := s.lowerConn.Write(osc); err != nil {
-		s.cfg.SequenceNumber--
 		return err
 	}
-	return nil
+	if code == 0 {
+		if err := s.installKeys(s.cfg.SecurityTokenID, nonce, s.remoteNonce); err != nil {
+			return err
+		}
+		s.localNonce = nonce
+	}
+
+	_, err = s.writeMessage(idResponse, func(cfg *Config) ([]byte, error) {
+		s.resHeader.ServiceResult = code
+		s.resHeader.Timestamp = time.Now()
+		return New(services.NewOpenSecureChannelResponse(
+			s.resHeader, 0, services.NewChannelSecurityToken(
+				cfg.SecureChannelID, cfg.SecurityTokenID, time.Now(), cfg.Lifetime,
+			), nonce,
+		), cfg).Serialize()
+	})
+	return err
 }
 
 // CloseSecureChannelRequest sends CloseSecureChannelRequest on top of UASC to SecureChannel.
 func (s *SecureChannel) CloseSecureChannelRequest() error {
-	s.cfg.SequenceNumber++
-	s.reqHeader.RequestHandle++
-	s.reqHeader.Timestamp = time.Now()
-	csc, err := New(services.NewCloseSecureChannelRequest(
-		s.reqHeader, s.cfg.SecureChannelID,
-	), s.cfg).Serialize()
-	if err != nil {
-		s.cfg.SequenceNumber--
-		s.reqHeader.RequestHandle--
-		return err
-	}
-
-	if _, err := s.lowerConn.Write(csc); err != nil {
-		s.cfg.SequenceNumber--
-		s.reqHeader.RequestHandle--
-		return err
-	}
-	return nil
+	_, err := s.writeMessage(idClose, func(cfg *Config) ([]byte, error) {
+		s.reqHeader.RequestHandle++
+		s.reqHeader.Timestamp = time.Now()
+		return New(services.NewCloseSecureChannelRequest(
+			s.reqHeader, cfg.SecureChannelID,
+		), cfg).Serialize()
+	})
+	return err
 }
 
 // CloseSecureChannelResponse sends CloseSecureChannelResponse on top of UASC to SecureChannel.
 func (s *SecureChannel) CloseSecureChannelResponse(code uint32) error {
-	s.cfg.SequenceNumber++
-	s.resHeader.ServiceResult = code
-	s.resHeader.Timestamp = time.Now()
-	csc, err := New(services.NewCloseSecureChannelResponse(s.resHeader), s.cfg).Serialize()
-	if err != nil {
-		s.cfg.SequenceNumber--
-		return err
-	}
-
-	if _, err := s.lowerConn.Write(csc); err != nil {
-		s.cfg.SequenceNumber--
-		return err
-	}
-	return nil
+	_, err := s.writeMessage(idResponse, func(cfg *Config) ([]byte, error) {
+		s.resHeader.ServiceResult = code
+		s.resHeader.Timestamp = time.Now()
+		return New(services.NewCloseSecureChannelResponse(s.resHeader), cfg).Serialize()
+	})
+	return err
 }
 
 // GetEndpointsRequest sends GetEndpointsRequest on top of UASC to SecureChannel.
 func (s *SecureChannel) GetEndpointsRequest(locales, uris []string) error {
-	s.cfg.SequenceNumber++
-	s.reqHeader.RequestHandle++
-	s.reqHeader.Timestamp = time.Now()
-	gep, err := New(services.NewGetEndpointsRequest(
-		s.reqHeader, s.RemoteEndpoint(), locales, uris,
-	), s.cfg).Serialize()
-	if err != nil {
-		s.cfg.SequenceNumber--
-		s.reqHeader.RequestHandle--
-		return err
-	}
-
-	if _, err := s.lowerConn.Write(gep); err != nil {
-		s.cfg.SequenceNumber--
-		s.reqHeader.RequestHandle--
-		return err
-	}
-	return nil
+	_, err := s.writeMessage(idRequest, func(cfg *Config) ([]byte, error) {
+		s.reqHeader.RequestHandle++
+		s.reqHeader.Timestamp = time.Now()
+		return New(services.NewGetEndpointsRequest(
+			s.reqHeader, s.RemoteEndpoint(), locales, uris,
+		), cfg).Serialize()
+	})
+	return err
 }
 
 // GetEndpointsResponse sends GetEndpointsResponse on top of UASC to SecureChannel.
 //
 // XXX - This is to be improved with some external configuration to describe endpoints infomation in the future release.
 func (s *SecureChannel) GetEndpointsResponse(code uint32, endpoints ...*services.EndpointDescription) error {
-	s.cfg.SequenceNumber++
-	s.resHeader.ServiceResult = code
-	s.resHeader.Timestamp = time.Now()
-	gep, err := New(services.NewGetEndpointsResponse(
-		s.resHeader, endpoints...,
-	), s.cfg).Serialize()
-	if err != nil {
-		s.cfg.SequenceNumber--
-		return err
-	}
-
-	if _, err := s.lowerConn.Write(gep); err != nil {
-		s.cfg.SequenceNumber--
-		return err
-	}
-	return nil
+	_, err := s.writeMessage(idResponse, func(cfg *Config) ([]byte, error) {
+		s.resHeader.ServiceResult = code
+		s.resHeader.Timestamp = time.Now()
+		return New(services.NewGetEndpointsResponse(
+			s.resHeader, endpoints...,
+		), cfg).Serialize()
+	})
+	return err
 }
 
 // FindServersRequest sends FindServersRequest on top of UASC to SecureChannel.
 func (s *SecureChannel) FindServersRequest(locales []string, servers ...string) error {
-	s.cfg.SequenceNumber++
-	s.reqHeader.RequestHandle++
-	s.reqHeader.Timestamp = time.Now()
-	fsr, err := New(services.NewFindServersRequest(
-		s.reqHeader, s.RemoteEndpoint(), locales, servers...,
-	), s.cfg).Serialize()
-	if err != nil {
-		s.cfg.SequenceNumber--
-		s.reqHeader.RequestHandle--
-		return err
-	}
-
-	if _, err := s.lowerConn.Write(fsr); err != nil {
-		s.cfg.SequenceNumber--
-		s.reqHeader.RequestHandle--
-		return err
-	}
-	return nil
+	_, err := s.writeMessage(idRequest, func(cfg *Config) ([]byte, error) {
+		s.reqHeader.RequestHandle++
+		s.reqHeader.Timestamp = time.Now()
+		return New(services.NewFindServersRequest(
+			s.reqHeader, s.RemoteEndpoint(), locales, servers...,
+		), cfg).Serialize()
+	})
+	return err
 }
 
 // FindServersResponse sends FindServersResponse on top of UASC to SecureChannel.
 //
 // XXX - This is to be improved with some external configuration to describe application infomation in the future release.
 func (s *SecureChannel) FindServersResponse(code uint32, apps ...*services.ApplicationDescription) error {
-	s.cfg.SequenceNumber++
-	s.resHeader.ServiceResult = code
-	s.resHeader.Timestamp = time.Now()
-	fsr, err := New(services.NewFindServersResponse(
-		s.resHeader, apps...,
-	), s.cfg).Serialize()
-	if err != nil {
-		s.cfg.SequenceNumber--
-		return err
-	}
-
-	if _, err := s.lowerConn.Write(fsr); err != nil {
-		s.cfg.SequenceNumber--
-		return err
-	}
-	return nil
+	_, err := s.writeMessage(idResponse, func(cfg *Config) ([]byte, error) {
+		s.resHeader.ServiceResult = code
+		s.resHeader.Timestamp = time.Now()
+		return New(services.NewFindServersResponse(
+			s.resHeader, apps...,
+		), cfg).Serialize()
+	})
+	return err
 }
@@ -6,11 +6,15 @@ package uasc
 
 import (
 	"context"
+	"math"
+	"net"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/google/go-cmp/cmp"
 
+	"github.com/wmnsk/gopcua/datatypes"
 	"github.com/wmnsk/gopcua/errors"
 	"github.com/wmnsk/gopcua/services"
 	"github.com/wmnsk/gopcua/uacp"
@@ -21,10 +25,14 @@ var (
 	policyURI = "http://opcfoundation.org/UA/SecurityPolicy#None"
 	cliCfg    = NewClientConfig(policyURI, nil, nil, 3333, services.SecModeNone, 3600000)
 	srvCfg    = NewServerConfig(policyURI, nil, nil, 1111, services.SecModeNone, 2222, 3600000)
-	msg       = []byte{0xde, 0xad, 0xbe, 0xef}
+	msg       = []byte{0x4d, 0x53, 0x47, 0x46, 0x0c, 0x00, 0x00, 0x00, 0xde, 0xad, 0xbe, 0xef}
 )
 
 func setUpSecureChannel(ctx context.Context) (*SecureChannel, *SecureChannel, error) {
+	return setUpSecureChannelWithConfig(ctx, cliCfg, srvCfg)
+}
+
+func setUpSecureChannelWithConfig(ctx context.Context, cliCfg, srvCfg *Config) (*SecureChannel, *SecureChannel, error) {
 	ln, err := uacp.Listen(endpoint, 0xffff)
 	if err != nil {
 		return nil, nil, err
@@ -116,6 +124,253 @@ func TestServerWrite(t *testing.T) {
 	}
 }
 
+func TestSecureChannelRenewal(t *testing.T) {
+	ctx := context.Background()
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// a SecurityToken valid only for 200ms is renewed after 100ms.
+	cli := NewClientConfig(policyURI, nil, nil, 3333, services.SecModeNone, 200)
+	cli.RenewalThreshold = 0.5
+	srv := NewServerConfig(policyURI, nil, nil, 1111, services.SecModeNone, 2222, 200)
+	cliChan, srvChan, err := setUpSecureChannelWithConfig(ctx, cli, srv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cliChan.Close()
+
+	time.Sleep(500 * time.Millisecond)
+
+	cliChan.mu.Lock()
+	cliToken := cliChan.cfg.SecurityTokenID
+	cliChan.mu.Unlock()
+	srvChan.mu.Lock()
+	srvToken := srvChan.cfg.SecurityTokenID
+	srvChan.mu.Unlock()
+
+	if cliToken == 2222 {
+		t.Fatal("SecurityToken was not renewed")
+	}
+	if cliToken != srvToken {
+		t.Fatalf("client uses SecurityToken %d, server issued %d", cliToken, srvToken)
+	}
+
+	// SecureChannel should still be usable past the original expiry.
+	if _, err := cliChan.Write(msg); err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 1024)
+	n, err := srvChan.Read(buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(buf[:n], msg); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestSecureChannelReplayedSequenceNumber(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cliChan, srvChan, err := setUpSecureChannel(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := cliChan.GetEndpointsRequest(nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 1024)
+	if _, err := srvChan.ReadService(buf); err != nil {
+		t.Fatal(err)
+	}
+
+	// the next request is sent with the same SequenceNumber as the last one.
+	cliChan.cfg.SequenceNumber--
+	if err := cliChan.GetEndpointsRequest(nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := srvChan.ReadService(buf); err != ErrInvalidSequenceNumber {
+		t.Errorf("got error %v, want %v", err, ErrInvalidSequenceNumber)
+	}
+}
+
+func TestSecureChannelConcurrentRequests(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cliChan, srvChan, err := setUpSecureChannel(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req, err := services.NewGetEndpointsRequest(
+		services.NewRequestHeader(
+			datatypes.NewTwoByteNodeID(0), time.Now(), 1, 0, 0, "", services.NewNullAdditionalHeader(), nil,
+		), "opc.tcp://localhost:4840", nil, nil,
+	).Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const n = 50
+	errs := make(chan error, n)
+	for i := 0; i < n; i++ {
+		go func(i int) {
+			if i%2 == 0 {
+				errs <- cliChan.GetEndpointsRequest(nil, nil)
+				return
+			}
+			_, err := cliChan.WriteService(req)
+			errs <- err
+		}(i)
+	}
+	for i := 0; i < n; i++ {
+		if err := <-errs; err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the requests are received in the order of the SequenceNumbers without gaps,
+	// as the server rejects the others.
+	reqIDs := map[uint32]bool{}
+	var last uint32
+	buf := make([]byte, 1024)
+	for i := 0; i < n; i++ {
+		m, err := srvChan.Read(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		msg, err := Decode(buf[:m])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if seq := msg.SequenceNumber; last != 0 && seq != last+1 {
+			t.Errorf("got SequenceNumber %d after %d", seq, last)
+		}
+		last = msg.SequenceNumber
+		if reqIDs[msg.RequestID] {
+			t.Errorf("got duplicate RequestID %d", msg.RequestID)
+		}
+		reqIDs[msg.RequestID] = true
+	}
+}
+
+func TestNextID(t *testing.T) {
+	for _, c := range []struct{ n, want uint32 }{
+		{0, 1},
+		{1, 2},
+		{maxSequenceNumber, maxSequenceNumber + 1},
+		{math.MaxUint32 - 1, math.MaxUint32},
+		{math.MaxUint32, 1},
+	} {
+		if got := nextID(c.n); got != c.want {
+			t.Errorf("nextID(%d): got %d want %d", c.n, got, c.want)
+		}
+	}
+}
+
+func TestSecureChannelRequestHandleNotSent(t *testing.T) {
+	// the peer is gone, so that the requests cannot be written.
+	conn, peer := net.Pipe()
+	peer.Close()
+	defer conn.Close()
+
+	cfg := *cliCfg
+	s := &SecureChannel{
+		mu:        new(sync.Mutex),
+		lowerConn: conn,
+		cfg:       &cfg,
+		reqHeader: services.NewRequestHeader(
+			datatypes.NewTwoByteNodeID(0), time.Time{}, 10, 0, 0xffff, "", services.NewNullAdditionalHeader(), nil,
+		),
+		logger:      cfg.logger(),
+		outstanding: map[uint32]uint32{},
+	}
+
+	if err := s.GetEndpointsRequest(nil, nil); err == nil {
+		t.Fatal("expected error for the closed connection, got nil")
+	}
+	if err := s.CloseSecureChannelRequest(); err == nil {
+		t.Fatal("expected error for the closed connection, got nil")
+	}
+	if got := s.reqHeader.RequestHandle; got != 10 {
+		t.Errorf("got RequestHandle %d want 10", got)
+	}
+	if s.cfg.SequenceNumber != cliCfg.SequenceNumber || s.cfg.RequestID != cliCfg.RequestID {
+		t.Errorf("got SequenceNumber %d and RequestID %d consumed", s.cfg.SequenceNumber, s.cfg.RequestID)
+	}
+}
+
+func TestSecureChannelUnexpectedRequestID(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cliChan, srvChan, err := setUpSecureChannel(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := cliChan.GetEndpointsRequest(nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 1024)
+	if _, err := srvChan.ReadService(buf); err != nil {
+		t.Fatal(err)
+	}
+
+	// the response with the RequestID the client never sent is rejected.
+	srvChan.mu.Lock()
+	reqID := srvChan.cfg.RequestID
+	srvChan.cfg.RequestID = reqID + 100
+	err = srvChan.GetEndpointsResponse(0)
+	srvChan.cfg.RequestID = reqID
+	srvChan.mu.Unlock()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cliChan.ReadService(buf); err != ErrUnexpectedRequestID {
+		t.Errorf("got error %v, want %v", err, ErrUnexpectedRequestID)
+	}
+
+	// the SecureChannel is no longer usable.
+	if err := srvChan.GetEndpointsResponse(0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cliChan.ReadService(buf); err != ErrUnexpectedRequestID {
+		t.Errorf("got error %v after rejected, want %v", err, ErrUnexpectedRequestID)
+	}
+}
+
+func TestSecureChannelDuplicateResponse(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cliChan, srvChan, err := setUpSecureChannel(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := cliChan.GetEndpointsRequest(nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 1024)
+	if _, err := srvChan.ReadService(buf); err != nil {
+		t.Fatal(err)
+	}
+
+	// the response to the request is accepted only once.
+	for i, want := range []error{nil, ErrUnexpectedRequestID} {
+		if err := srvChan.GetEndpointsResponse(0); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := cliChan.ReadService(buf); err != want {
+			t.Errorf("response %d: got error %v, want %v", i, err, want)
+		}
+	}
+}
+
 func TestClientClose(t *testing.T) {
 	ctx := context.Background()
 	ctx, cancel := context.WithCancel(ctx)
@@ -131,6 +386,31 @@ func TestClientClose(t *testing.T) {
 	}
 }
 
+func TestSecureChannelAbortAfterClose(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cliChan, _, err := setUpSecureChannel(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := cliChan.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	// the notifier which is still running after close should not panic.
+	done := make(chan struct{})
+	go func() {
+		cliChan.notifyAbort(ctx, ErrInvalidMessageSignature)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("notifyAbort did not return after close")
+	}
+}
+
 func TestServerClose(t *testing.T) {
 	ctx := context.Background()
 	ctx, cancel := context.WithCancel(ctx)
@@ -185,6 +465,15 @@ func TestGetEndpointResponse(t *testing.T) {
 		t.Fatal(err)
 	}
 
+	// the response is accepted only for the request sent.
+	if err := cliChan.GetEndpointsRequest(nil, nil); err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 1024)
+	if _, err := srvChan.ReadService(buf); err != nil {
+		t.Fatal(err)
+	}
+
 	if err := srvChan.GetEndpointsResponse(0, services.NewEndpointDescription(
 		srvChan.LocalEndpoint(), services.NewApplicationDescription(
 			"", "", "", services.AppTypeServer, "", "", []string{""},
@@ -197,7 +486,6 @@ func TestGetEndpointResponse(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	buf := make([]byte, 1024)
 	n, err := cliChan.ReadService(buf)
 	if err != nil {
 		t.Fatal(err)
@@ -251,6 +539,15 @@ func TestFindServerResponse(t *testing.T) {
 		t.Fatal(err)
 	}
 
+	// the response is accepted only for the request sent.
+	if err := cliChan.FindServersRequest(nil); err != nil {
+		t.Fatal(err)
+	}
+	buf := make([]byte, 1024)
+	if _, err := srvChan.ReadService(buf); err != nil {
+		t.Fatal(err)
+	}
+
 	if err := srvChan.FindServersResponse(
 		0, services.NewApplicationDescription(
 			"", "", "", services.AppTypeServer, "", "", []string{""},
@@ -259,7 +556,6 @@ func TestFindServerResponse(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	buf := make([]byte, 1024)
 	n, err := cliChan.ReadService(buf)
 	if err != nil {
 		t.Fatal(err)
@@ -0,0 +1,367 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package uasc
+
+import (
+	"crypto/rsa"
+	"crypto/sha1"
+	"crypto/x509"
+	"encoding/binary"
+
+	"github.com/wmnsk/gopcua/datatypes"
+	"github.com/wmnsk/gopcua/errors"
+	"github.com/wmnsk/gopcua/securitypolicy"
+	"github.com/wmnsk/gopcua/services"
+)
+
+// SecurityPolicyURINone is the URI of the SecurityPolicy None.
+const SecurityPolicyURINone = "http://opcfoundation.org/UA/SecurityPolicy#None"
+
+// headerLen is the length of the Header which is common in all the MessageChunks.
+const headerLen = 12
+
+func isSecurityPolicyNone(uri string) bool {
+	return uri == "" || uri == SecurityPolicyURINone
+}
+
+// Thumbprint returns the thumbprint of the DER encoded certificate given,
+// which is used as the ReceiverCertificateThumbprint in AsymmetricSecurityHeader.
+func Thumbprint(cert []byte) []byte {
+	sum := sha1.Sum(cert)
+	return sum[:]
+}
+
+// parsePublicKey returns the RSA public key in the DER encoded certificate given.
+func parsePublicKey(cert []byte) (*rsa.PublicKey, error) {
+	c, err := x509.ParseCertificate(cert)
+	if err != nil {
+		return nil, err
+	}
+
+	key, ok := c.PublicKey.(*rsa.PublicKey)
+	if !ok {
+		return nil, errors.New("certificate does not contain RSA public key")
+	}
+	return key, nil
+}
+
+// secure signs and encrypts the MessageChunk b with the algorithms of the SecurityPolicy.
+//
+// OpenSecureChannel messages are always signed and encrypted with the asymmetric algorithms
+// unless the SecurityPolicy is None, while the other messages are secured with the symmetric
+// algorithms derived from the nonces as specified by the SecurityMode.
+//
+// This should be called with s.mu held.
+func (s *SecureChannel) secure(b []byte) ([]byte, error) {
+	if s.cfg == nil || isSecurityPolicyNone(s.cfg.SecurityPolicyURI) || len(b) < headerLen {
+		return b, nil
+	}
+
+	switch string(b[:3]) {
+	case MessageTypeOpenSecureChannel:
+		n, err := asymmetricHeaderLen(b)
+		if err != nil {
+			return nil, err
+		}
+		alg, err := s.asymmetric()
+		if err != nil {
+			return nil, err
+		}
+
+		plainBlock := s.remoteKey.Size() - alg.MinPadding()
+		return protect(b, n, alg, s.cfg.PrivateKey.Size(), plainBlock, s.remoteKey.Size(), true)
+	case MessageTypeMessage, MessageTypeCloseSecureChannel:
+		if s.cfg.SecurityMode == services.SecModeNone {
+			return b, nil
+		}
+		if len(b) < headerLen+4 {
+			return nil, errors.NewErrTooShortToDecode(b, "should contain SymmetricSecurityHeader")
+		}
+		alg, ok := s.keys[binary.LittleEndian.Uint32(b[headerLen:headerLen+4])]
+		if !ok {
+			return nil, ErrUnknownSecurityToken
+		}
+
+		encrypt := s.cfg.SecurityMode == services.SecModeSignAndEncrypt
+		return protect(b, headerLen+4, alg, alg.SignatureLength(), alg.BlockSize(), alg.BlockSize(), encrypt)
+	default:
+		return b, nil
+	}
+}
+
+// unsecure decrypts the MessageChunk b and verifies its signature. The MessageChunk is
+// returned without the padding and the signature so that it can be decoded as it is
+// not secured at all.
+//
+// This should be called with s.mu held.
+func (s *SecureChannel) unsecure(b []byte) ([]byte, error) {
+	if s.cfg == nil || len(b) < headerLen {
+		return b, nil
+	}
+
+	switch string(b[:3]) {
+	case MessageTypeOpenSecureChannel:
+		a := &AsymmetricSecurityHeader{}
+		if err := a.DecodeFromBytes(b[headerLen:]); err != nil {
+			return nil, err
+		}
+		uri := a.SecurityPolicyURI.Get()
+		if isSecurityPolicyNone(uri) && isSecurityPolicyNone(s.cfg.SecurityPolicyURI) {
+			return b, nil
+		}
+		if uri != s.cfg.SecurityPolicyURI {
+			return nil, ErrSecurityPolicyMismatch
+		}
+
+		// server gets the public key of client from the certificate in the request.
+		if s.remoteKey == nil {
+			cert := a.SenderCertificate.Get()
+			if err := s.cfg.verifyRemote(cert); err != nil {
+				return nil, err
+			}
+			key, err := parsePublicKey(cert)
+			if err != nil {
+				return nil, err
+			}
+			s.remoteKey = key
+			s.cfg.Thumbprint = Thumbprint(cert)
+		}
+
+		alg, err := s.asymmetric()
+		if err != nil {
+			return nil, err
+		}
+		n := headerLen + a.Len() - len(a.Payload)
+		return unprotect(b, n, alg, s.remoteKey.Size(), s.cfg.PrivateKey.Size(), true)
+	case MessageTypeMessage, MessageTypeCloseSecureChannel:
+		if isSecurityPolicyNone(s.cfg.SecurityPolicyURI) || s.cfg.SecurityMode == services.SecModeNone {
+			return b, nil
+		}
+		if len(b) < headerLen+4 {
+			return nil, errors.NewErrTooShortToDecode(b, "should contain SymmetricSecurityHeader")
+		}
+		alg, ok := s.keys[binary.LittleEndian.Uint32(b[headerLen:headerLen+4])]
+		if !ok {
+			return nil, ErrUnknownSecurityToken
+		}
+
+		decrypt := s.cfg.SecurityMode == services.SecModeSignAndEncrypt
+		return unprotect(b, headerLen+4, alg, alg.SignatureLength(), alg.BlockSize(), decrypt)
+	default:
+		return b, nil
+	}
+}
+
+// asymmetric returns the asymmetric algorithms used for OpenSecureChannel messages.
+func (s *SecureChannel) asymmetric() (*securitypolicy.EncryptionAlgorithm, error) {
+	if s.cfg.PrivateKey == nil {
+		return nil, errors.New("PrivateKey is required to secure OpenSecureChannel")
+	}
+	if s.remoteKey == nil {
+		return nil, errors.New("public key of the remote is unknown")
+	}
+	return securitypolicy.Asymmetric(s.cfg.SecurityPolicyURI, s.cfg.PrivateKey, s.remoteKey)
+}
+
+// newNonce returns a new nonce for the OpenSecureChannel of the length required
+// by the SecurityPolicy, or nil if the SecurityPolicy is None.
+func (s *SecureChannel) newNonce() ([]byte, error) {
+	if isSecurityPolicyNone(s.cfg.SecurityPolicyURI) {
+		return nil, nil
+	}
+	return securitypolicy.GenerateNonce(s.cfg.SecurityPolicyURI)
+}
+
+// checkNonce returns an error wrapping ErrInvalidNonce if the length of the nonce
+// received in the OpenSecureChannel is not the one required by the SecurityPolicy,
+// as the keys derived from a shorter nonce are weaker than expected.
+// Any nonce is accepted if the SecurityPolicy is None.
+func (s *SecureChannel) checkNonce(nonce *datatypes.ByteString) error {
+	if isSecurityPolicyNone(s.cfg.SecurityPolicyURI) {
+		return nil
+	}
+	n, err := securitypolicy.NonceLength(s.cfg.SecurityPolicyURI)
+	if err != nil {
+		return err
+	}
+	var l int
+	if nonce != nil {
+		l = len(nonce.Get())
+	}
+	if l != n {
+		return errors.Wrapf(ErrInvalidNonce, "got %d bytes, %d bytes required for %s", l, n, s.cfg.SecurityPolicyURI)
+	}
+	return nil
+}
+
+// installKeys derives the symmetric algorithms from the nonces exchanged in OpenSecureChannel
+// and associates them with the SecurityToken given. Only the signing keys are kept
+// if the SecurityMode is Sign.
+//
+// The algorithms for the previous SecurityToken are kept so that the messages sent
+// before the renewal completes can still be decrypted. The older ones are discarded.
+//
+// This should be called with s.mu held.
+func (s *SecureChannel) installKeys(tokenID uint32, localNonce, remoteNonce []byte) error {
+	if isSecurityPolicyNone(s.cfg.SecurityPolicyURI) {
+		return nil
+	}
+
+	// the encryption keys are kept only if the messages are encrypted.
+	derive := securitypolicy.Symmetric
+	if s.cfg.SecurityMode == services.SecModeSign {
+		derive = securitypolicy.SymmetricSign
+	}
+	alg, err := derive(s.cfg.SecurityPolicyURI, localNonce, remoteNonce)
+	if err != nil {
+		return err
+	}
+
+	if s.keys == nil {
+		s.keys = map[uint32]*securitypolicy.EncryptionAlgorithm{}
+	}
+	for id := range s.keys {
+		if id != tokenID && id != s.lastTokenID {
+			delete(s.keys, id)
+		}
+	}
+	s.keys[tokenID] = alg
+	s.lastTokenID = tokenID
+	return nil
+}
+
+// installResponseKeys installs the keys for the SecurityToken issued by the
+// OpenSecureChannelResponse, when the SecureChannel works as client.
+//
+// This should be called with s.mu held.
+func (s *SecureChannel) installResponseKeys(o *services.OpenSecureChannelResponse) {
+	if o.ServiceResult != 0 || o.SecurityToken == nil || s.checkNonce(o.ServerNonce) != nil {
+		return
+	}
+
+	switch s.state {
+	case cliStateOpenSecureChannelSent, cliStateSecureChannelOpened:
+		s.remoteNonce = o.ServerNonce.Get()
+		s.installKeys(o.SecurityToken.TokenID, s.localNonce, s.remoteNonce)
+	}
+}
+
+// asymmetricHeaderLen returns the length of the Header and the AsymmetricSecurityHeader in b.
+func asymmetricHeaderLen(b []byte) (int, error) {
+	a := &AsymmetricSecurityHeader{}
+	if err := a.DecodeFromBytes(b[headerLen:]); err != nil {
+		return 0, err
+	}
+	return headerLen + a.Len() - len(a.Payload), nil
+}
+
+// paddingOverhead returns the number of bytes used to encode the size of the padding.
+// ExtraPaddingSize is added if the key used for encryption is longer than 2048 bits.
+func paddingOverhead(cipherBlock int) int {
+	if cipherBlock > 256 {
+		return 2
+	}
+	return 1
+}
+
+// protect appends the padding and the signature to the MessageChunk b of which
+// the first n bytes are the headers, and encrypts the rest if encrypt is true.
+//
+// The MessageSize in the Header is updated to the length after encryption
+// before signing, as the signature covers the Header.
+func protect(b []byte, n int, alg *securitypolicy.EncryptionAlgorithm, sigLen, plainBlock, cipherBlock int, encrypt bool) ([]byte, error) {
+	body := b[n:]
+
+	var padding []byte
+	if encrypt {
+		overhead := paddingOverhead(cipherBlock)
+		size := (plainBlock - (len(body)+overhead+sigLen)%plainBlock) % plainBlock
+		padding = make([]byte, size+overhead)
+		for i := 0; i <= size; i++ {
+			padding[i] = byte(size)
+		}
+		if overhead == 2 {
+			padding[size+1] = byte(size >> 8)
+		}
+	}
+
+	l := len(body) + len(padding) + sigLen
+	msgSize := n + l
+	if encrypt {
+		msgSize = n + l/plainBlock*cipherBlock
+	}
+
+	msg := make([]byte, 0, n+l)
+	msg = append(msg, b[:n]...)
+	binary.LittleEndian.PutUint32(msg[4:8], uint32(msgSize))
+	msg = append(msg, body...)
+	msg = append(msg, padding...)
+
+	sig, err := alg.Signature(msg)
+	if err != nil {
+		return nil, err
+	}
+	if len(sig) != sigLen {
+		return nil, errors.Errorf("signature should be %d bytes, got %d bytes", sigLen, len(sig))
+	}
+	msg = append(msg, sig...)
+
+	if !encrypt {
+		return msg, nil
+	}
+
+	ciphertext, err := alg.Encrypt(msg[n:])
+	if err != nil {
+		return nil, err
+	}
+	return append(msg[:n:n], ciphertext...), nil
+}
+
+// unprotect decrypts the MessageChunk b of which the first n bytes are the headers
+// if decrypt is true, and verifies the signature at the end of it.
+//
+// The MessageChunk is returned without the padding and the signature, with
+// the MessageSize in the Header updated accordingly.
+func unprotect(b []byte, n int, alg *securitypolicy.EncryptionAlgorithm, sigLen, cipherBlock int, decrypt bool) ([]byte, error) {
+	msg := b
+	if decrypt {
+		plaintext, err := alg.Decrypt(b[n:])
+		if err != nil {
+			return nil, err
+		}
+		msg = make([]byte, 0, n+len(plaintext))
+		msg = append(msg, b[:n]...)
+		msg = append(msg, plaintext...)
+	}
+
+	if len(msg) < n+sigLen {
+		return nil, errors.NewErrTooShortToDecode(msg, "should contain signature")
+	}
+	signed, sig := msg[:len(msg)-sigLen], msg[len(msg)-sigLen:]
+	if err := alg.VerifySignature(signed, sig); err != nil {
+		return nil, ErrInvalidMessageSignature
+	}
+
+	end := len(signed)
+	if decrypt {
+		overhead := paddingOverhead(cipherBlock)
+		if end-overhead < n {
+			return nil, errors.NewErrTooShortToDecode(msg, "should contain padding")
+		}
+		size := int(signed[end-1])
+		if overhead == 2 {
+			size = int(signed[end-2]) | int(signed[end-1])<<8
+		}
+		end -= size + overhead
+		if end < n {
+			return nil, errors.New("padding is longer than the message")
+		}
+	}
+
+	out := make([]byte, end)
+	copy(out, signed[:end])
+	binary.LittleEndian.PutUint32(out[4:8], uint32(end))
+	return out, nil
+}
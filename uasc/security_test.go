@@ -0,0 +1,360 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package uasc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/wmnsk/gopcua/errors"
+	"github.com/wmnsk/gopcua/securitypolicy"
+	"github.com/wmnsk/gopcua/services"
+)
+
+const policyBasic256Sha256 = "http://opcfoundation.org/UA/SecurityPolicy#Basic256Sha256"
+
+// newCertificate generates a 2048 bits RSA key and a self-signed certificate of it.
+func newCertificate(t *testing.T, name string) ([]byte, *rsa.PrivateKey) {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: name},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	cert, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert, key
+}
+
+func TestSecureChannelBasic256Sha256(t *testing.T) {
+	cliCert, cliKey := newCertificate(t, "client")
+	srvCert, srvKey := newCertificate(t, "server")
+
+	cases := []struct {
+		name string
+		mode uint32
+	}{
+		{"sign", services.SecModeSign},
+		{"sign-and-encrypt", services.SecModeSignAndEncrypt},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			cliCfg := NewClientConfig(policyBasic256Sha256, cliCert, nil, 3333, c.mode, 3600000)
+			cliCfg.PrivateKey = cliKey
+			cliCfg.RemoteCertificate = srvCert
+			srvCfg := NewServerConfig(policyBasic256Sha256, srvCert, nil, 1111, c.mode, 2222, 3600000)
+			srvCfg.PrivateKey = srvKey
+
+			cliChan, srvChan, err := setUpSecureChannelWithConfig(ctx, cliCfg, srvCfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			req := newTestMessage(t)[symmetricChunkHeaderLen:]
+			if _, err := cliChan.WriteService(req); err != nil {
+				t.Fatal(err)
+			}
+
+			buf := make([]byte, 0xffff)
+			n, err := srvChan.ReadService(buf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(buf[:n], req); diff != "" {
+				t.Error(diff)
+			}
+
+			if _, err := srvChan.WriteService(req); err != nil {
+				t.Fatal(err)
+			}
+			n, err = cliChan.ReadService(buf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(buf[:n], req); diff != "" {
+				t.Error(diff)
+			}
+		})
+	}
+}
+
+func TestProtectSymmetric(t *testing.T) {
+	cliNonce := make([]byte, 32)
+	srvNonce := make([]byte, 32)
+	if _, err := rand.Read(cliNonce); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rand.Read(srvNonce); err != nil {
+		t.Fatal(err)
+	}
+
+	cli, err := securitypolicy.Symmetric(policyBasic256Sha256, cliNonce, srvNonce)
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv, err := securitypolicy.Symmetric(policyBasic256Sha256, srvNonce, cliNonce)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b := newTestMessage(t)
+
+	for _, encrypt := range []bool{false, true} {
+		secured, err := protect(b, headerLen+4, cli, cli.SignatureLength(), cli.BlockSize(), cli.BlockSize(), encrypt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if encrypt && (len(secured)-headerLen-4)%cli.BlockSize() != 0 {
+			t.Errorf("encrypted length %d is not a multiple of the block size", len(secured))
+		}
+
+		got, err := unprotect(secured, headerLen+4, srv, srv.SignatureLength(), srv.BlockSize(), encrypt)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if diff := cmp.Diff(got, b); diff != "" {
+			t.Error(diff)
+		}
+
+		// tampered message should be rejected.
+		secured[len(secured)-1] ^= 0xff
+		if _, err := unprotect(secured, headerLen+4, srv, srv.SignatureLength(), srv.BlockSize(), encrypt); err == nil {
+			t.Error("expected error for tampered message, got nil")
+		}
+	}
+}
+
+func TestSecureChannelTamperedChunk(t *testing.T) {
+	cliCert, cliKey := newCertificate(t, "client")
+	srvCert, srvKey := newCertificate(t, "server")
+
+	for _, mode := range []uint32{services.SecModeSign, services.SecModeSignAndEncrypt} {
+		ctx, cancel := context.WithCancel(context.Background())
+
+		cliCfg := NewClientConfig(policyBasic256Sha256, cliCert, nil, 3333, mode, 3600000)
+		cliCfg.PrivateKey = cliKey
+		cliCfg.RemoteCertificate = srvCert
+		srvCfg := NewServerConfig(policyBasic256Sha256, srvCert, nil, 1111, mode, 2222, 3600000)
+		srvCfg.PrivateKey = srvKey
+
+		cliChan, srvChan, err := setUpSecureChannelWithConfig(ctx, cliCfg, srvCfg)
+		if err != nil {
+			cancel()
+			t.Fatal(err)
+		}
+
+		// the chunk is secured as WriteService does, and its last byte is flipped on the wire.
+		req := newTestMessage(t)[symmetricChunkHeaderLen:]
+		cliChan.mu.Lock()
+		cliChan.cfg.SequenceNumber++
+		msg := New(nil, cliChan.cfg)
+		msg.MessageSize += uint32(len(req))
+		b, err := msg.Serialize()
+		if err != nil {
+			cliChan.mu.Unlock()
+			cancel()
+			t.Fatal(err)
+		}
+		secured, err := cliChan.secure(append(b, req...))
+		if err == nil {
+			secured[len(secured)-1] ^= 0xff
+			_, err = cliChan.lowerConn.Write(secured)
+		}
+		cliChan.mu.Unlock()
+		if err != nil {
+			cancel()
+			t.Fatal(err)
+		}
+
+		buf := make([]byte, 0xffff)
+		if _, err := srvChan.ReadService(buf); err != ErrInvalidMessageSignature {
+			t.Errorf("mode %d: got error %v, want %v", mode, err, ErrInvalidMessageSignature)
+		}
+		// the SecureChannel is no longer usable.
+		if _, err := srvChan.ReadService(buf); err != ErrInvalidMessageSignature {
+			t.Errorf("mode %d: got error %v after rejected, want %v", mode, err, ErrInvalidMessageSignature)
+		}
+		cancel()
+	}
+}
+
+func TestSecureChannelSignOnly(t *testing.T) {
+	cliCert, cliKey := newCertificate(t, "client")
+	srvCert, srvKey := newCertificate(t, "server")
+
+	for _, mode := range []uint32{services.SecModeSign, services.SecModeSignAndEncrypt} {
+		ctx, cancel := context.WithCancel(context.Background())
+
+		cliCfg := NewClientConfigSecurity(policyBasic256Sha256, mode, cliCert, cliKey, srvCert, 3333, 3600000)
+		srvCfg := NewServerConfig(policyBasic256Sha256, srvCert, nil, 1111, mode, 2222, 3600000)
+		srvCfg.PrivateKey = srvKey
+
+		cliChan, srvChan, err := setUpSecureChannelWithConfig(ctx, cliCfg, srvCfg)
+		if err != nil {
+			cancel()
+			t.Fatal(err)
+		}
+
+		// the chunk is secured as WriteService does.
+		req := newTestMessage(t)[symmetricChunkHeaderLen:]
+		cliChan.mu.Lock()
+		cliChan.cfg.SequenceNumber++
+		msg := New(nil, cliChan.cfg)
+		msg.MessageSize += uint32(len(req))
+		b, err := msg.Serialize()
+		if err != nil {
+			cliChan.mu.Unlock()
+			cancel()
+			t.Fatal(err)
+		}
+		b = append(b, req...)
+		secured, err := cliChan.secure(b)
+		alg := cliChan.keys[cliChan.cfg.SecurityTokenID]
+		cliChan.mu.Unlock()
+		srvChan.mu.Lock()
+		remote := srvChan.keys[cliChan.cfg.SecurityTokenID]
+		srvChan.mu.Unlock()
+		if err != nil {
+			cancel()
+			t.Fatal(err)
+		}
+
+		// the body follows the headers in the clear only if it is not encrypted,
+		// with the signature and without the padding.
+		clear := cmp.Diff(secured[headerLen:len(b)], b[headerLen:]) == ""
+		switch mode {
+		case services.SecModeSign:
+			if !clear {
+				t.Error("the body should not be encrypted in Sign mode")
+			}
+			if got, want := len(secured), len(b)+alg.SignatureLength(); got != want {
+				t.Errorf("got %d bytes want %d bytes", got, want)
+			}
+			if err := remote.VerifySignature(secured[:len(b)], secured[len(b):]); err != nil {
+				t.Errorf("the signature should be verified by the server: %v", err)
+			}
+		case services.SecModeSignAndEncrypt:
+			if clear {
+				t.Error("the body should be encrypted in SignAndEncrypt mode")
+			}
+		}
+		cancel()
+	}
+}
+
+func TestSecureChannelNonceLength(t *testing.T) {
+	t.Run("generated", func(t *testing.T) {
+		cliCert, cliKey := newCertificate(t, "client")
+		srvCert, srvKey := newCertificate(t, "server")
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		cliCfg := NewClientConfigSecurity(policyBasic256Sha256, services.SecModeSign, cliCert, cliKey, srvCert, 3333, 3600000)
+		srvCfg := NewServerConfig(policyBasic256Sha256, srvCert, nil, 1111, services.SecModeSign, 2222, 3600000)
+		srvCfg.PrivateKey = srvKey
+
+		cliChan, srvChan, err := setUpSecureChannelWithConfig(ctx, cliCfg, srvCfg)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		cliChan.mu.Lock()
+		cliNonce, remote := cliChan.localNonce, cliChan.remoteNonce
+		cliChan.mu.Unlock()
+		srvChan.mu.Lock()
+		srvNonce := srvChan.localNonce
+		srvChan.mu.Unlock()
+		if len(cliNonce) != 32 || len(srvNonce) != 32 {
+			t.Errorf("got %d and %d bytes nonces want 32", len(cliNonce), len(srvNonce))
+		}
+		if diff := cmp.Diff(remote, srvNonce); diff != "" {
+			t.Error(diff)
+		}
+	})
+
+	t.Run("short-server-nonce", func(t *testing.T) {
+		cfg := NewClientConfig(policyBasic256Sha256, nil, nil, 3333, services.SecModeSign, 3600000)
+		s := &SecureChannel{
+			mu:         new(sync.Mutex),
+			cfg:        cfg,
+			logger:     cfg.logger(),
+			state:      cliStateOpenSecureChannelSent,
+			errChan:    make(chan error),
+			localNonce: make([]byte, 32),
+		}
+		res := services.NewOpenSecureChannelResponse(
+			services.NewResponseHeader(time.Now(), 1, 0, services.NewNullDiagnosticInfo(), nil, services.NewNullAdditionalHeader(), nil),
+			0, services.NewChannelSecurityToken(1, 1, time.Now(), 3600000), make([]byte, 16),
+		)
+
+		s.mu.Lock()
+		s.installResponseKeys(res)
+		s.mu.Unlock()
+		if len(s.keys) != 0 {
+			t.Error("keys should not be derived from the short nonce")
+		}
+
+		go s.handleOpenSecureChannelResponse(res)
+		select {
+		case err := <-s.errChan:
+			if errors.Cause(err) != ErrInvalidNonce {
+				t.Errorf("got %v want %v", err, ErrInvalidNonce)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("the short nonce is not rejected")
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.state != cliStateSecureChannelClosed {
+			t.Errorf("got state %v want %v", s.state, cliStateSecureChannelClosed)
+		}
+	})
+}
+
+func TestConfigSecurityMode(t *testing.T) {
+	cases := []struct {
+		name      string
+		policyURI string
+		mode      uint32
+		ok        bool
+	}{
+		{"none", policyURI, services.SecModeNone, true},
+		{"sign", policyBasic256Sha256, services.SecModeSign, true},
+		{"sign-and-encrypt", policyBasic256Sha256, services.SecModeSignAndEncrypt, true},
+		{"none-with-policy", policyBasic256Sha256, services.SecModeNone, false},
+		{"sign-without-policy", policyURI, services.SecModeSign, false},
+		{"invalid", policyBasic256Sha256, services.SecModeInvalid, false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			cfg := NewConfig(1, c.policyURI, nil, nil, 0, 0, c.mode, 0, 0)
+			if err := cfg.validateSecurityMode(); (err == nil) != c.ok {
+				t.Errorf("got error %v", err)
+			}
+		})
+	}
+}
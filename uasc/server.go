@@ -19,6 +19,10 @@ func ListenAndAcceptSecureChannel(ctx context.Context, transport net.Conn, cfg *
 	if err := cfg.validate("server"); err != nil {
 		return nil, err
 	}
+	// the Config is updated by the SecureChannel, which should not be shared with the others
+	// accepted with the same Config.
+	c := *cfg
+	cfg = &c
 
 	secChan := &SecureChannel{
 		mu:        new(sync.Mutex),
@@ -31,12 +35,17 @@ func ListenAndAcceptSecureChannel(ctx context.Context, transport net.Conn, cfg *
 			time.Now(), 0, 0, services.NewNullDiagnosticInfo(),
 			[]string{}, services.NewNullAdditionalHeader(), nil,
 		),
-		cfg:     cfg,
-		state:   srvStateSecureChannelClosed,
-		opened:  make(chan bool),
-		lenChan: make(chan int),
-		errChan: make(chan error),
-		rcvBuf:  make([]byte, 0xffff),
+		cfg:       cfg,
+		logger:    cfg.logger(),
+		state:     srvStateSecureChannelClosed,
+		opened:    make(chan bool),
+		rcvChan:   make(chan []byte),
+		closed:    make(chan struct{}),
+		broken:    make(chan struct{}),
+		errChan:   make(chan error),
+		abortChan: make(chan error),
+		rcvBuf:    newReceiveBuf(transport),
+		reqIDs:    map[uint32]uint32{},
 	}
 
 	go secChan.monitor(ctx)
@@ -55,15 +64,19 @@ func ListenAndAcceptSecureChannel(ctx context.Context, transport net.Conn, cfg *
 // ListenAndAcceptSession starts UASC server on top of established transport connection.
 func ListenAndAcceptSession(ctx context.Context, secChan *SecureChannel, cfg *SessionConfig) (*Session, error) {
 	session := &Session{
-		mu:        new(sync.Mutex),
-		secChan:   secChan,
-		cfg:       cfg,
-		state:     srvStateSessionClosed,
-		created:   make(chan bool),
-		activated: make(chan bool),
-		lenChan:   make(chan int),
-		errChan:   make(chan error),
-		rcvBuf:    make([]byte, 0xffff),
+		mu:            new(sync.Mutex),
+		secChan:       secChan,
+		cfg:           cfg,
+		state:         srvStateSessionClosed,
+		created:       make(chan bool),
+		activated:     make(chan bool),
+		sessionClosed: make(chan struct{}),
+		rcvChan:       make(chan []byte),
+		closed:        make(chan struct{}),
+		broken:        make(chan struct{}),
+		errChan:       make(chan error),
+		abortChan:     make(chan error),
+		rcvBuf:        newReceiveBuf(secChan.lowerConn),
 	}
 
 	go session.monitor(ctx)
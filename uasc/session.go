@@ -15,21 +15,34 @@ import (
 
 	"github.com/wmnsk/gopcua/datatypes"
 	"github.com/wmnsk/gopcua/services"
+	"github.com/wmnsk/gopcua/status"
 )
 
 // Session is an implementation of the net.Conn interface for Session in OPC UA Secure Conversation.
 //
 // In UASC, there are two types of net.Conn: SecureChannel and Session. Each Conn is handled in different manner.
 type Session struct {
-	mu             *sync.Mutex
-	secChan        *SecureChannel
-	cfg            *SessionConfig
-	state          sessionState
-	created        chan bool
-	activated      chan bool
-	lenChan        chan int
+	mu        *sync.Mutex
+	secChan   *SecureChannel
+	cfg       *SessionConfig
+	state     sessionState
+	created   chan bool
+	activated chan bool
+	// sessionClosed is closed when the CloseSessionResponse arrives.
+	sessionClosed  chan struct{}
+	rcvChan        chan []byte
+	closed         chan struct{}
 	errChan        chan error
+	abortChan      chan error
 	sndBuf, rcvBuf []byte
+
+	// broken is closed when the monitor stops on an error in reading from secChan,
+	// and readErr is the error returned by Read after that.
+	broken  chan struct{}
+	readErr error
+
+	// pending is the message which did not fit in the buffer given to the last Read.
+	pending []byte
 }
 
 // Read reads data from the connection.
@@ -37,46 +50,65 @@ type Session struct {
 // after a fixed time limit; see SetDeadline and SetReadDeadline.
 //
 // If the data is one of OpenSecureChannel or CloseSecureChannel, it will be handled automatically.
+//
+// If b is too small to hold the Message, Read returns io.ErrShortBuffer with the length
+// required, and the Message is returned by the next Read.
 func (s *Session) Read(b []byte) (n int, err error) {
-	if !(s.state == cliStateSessionActivated || s.state == srvStateSessionActivated) {
-		return 0, ErrSessionNotActivated
+	msg, err := s.next()
+	if err != nil {
+		return 0, err
 	}
-	for {
-		select {
-		case n, ok := <-s.lenChan:
-			if !ok {
-				return 0, ErrSessionNotActivated
-			}
-
-			copy(b, s.rcvBuf[:n])
-			return n, nil
-			/*
-				case time.After(s.readDeadline):
-					return 0, ErrTimeout
-			*/
-		}
+	if len(b) < len(msg) {
+		s.pending = append([]byte{}, msg...)
+		return len(msg), io.ErrShortBuffer
 	}
+
+	return copy(b, msg), nil
 }
 
 // ReadService reads the payload(=Service) from the connection.
 // Which means the UASC Headers are omitted.
+//
+// Like Read, io.ErrShortBuffer is returned if b is too small to hold the payload.
 func (s *Session) ReadService(b []byte) (n int, err error) {
-	if !(s.state == cliStateSessionActivated || s.state == srvStateSessionActivated) {
-		return 0, ErrSessionNotActivated
+	msg, err := s.next()
+	if err != nil {
+		return 0, err
+	}
+
+	sc, err := Decode(msg)
+	if err != nil {
+		return 0, err
+	}
+	if len(b) < len(sc.SequenceHeader.Payload) {
+		s.pending = append([]byte{}, msg...)
+		return len(sc.SequenceHeader.Payload), io.ErrShortBuffer
+	}
+
+	return copy(b, sc.SequenceHeader.Payload), nil
+}
+
+// next returns the Message left by the last Read if any, or the next one received.
+func (s *Session) next() ([]byte, error) {
+	if s.pending != nil {
+		msg := s.pending
+		s.pending = nil
+		return msg, nil
+	}
+
+	if !s.isActivated() {
+		return nil, ErrSessionNotActivated
 	}
 	for {
 		select {
-		case n, ok := <-s.lenChan:
-			if !ok {
-				return 0, ErrSessionNotActivated
-			}
-
-			sc, err := Decode(s.rcvBuf[:n])
-			if err != nil {
-				return 0, err
-			}
-			copy(b, sc.SequenceHeader.Payload)
-			return int(sc.MessageSize), nil
+		case msg := <-s.rcvChan:
+			return msg, nil
+		case <-s.closed:
+			return nil, ErrSessionNotActivated
+		case <-s.broken:
+			return nil, s.readErr
+		case err := <-s.abortChan:
+			return nil, err
 			/*
 				case time.After(s.readDeadline):
 					return 0, ErrTimeout
@@ -89,7 +121,7 @@ func (s *Session) ReadService(b []byte) (n int, err error) {
 // Write can be made to time out and return an Error with Timeout() == true
 // after a fixed time limit; see SetDeadline and SetWriteDeadline.
 func (s *Session) Write(b []byte) (n int, err error) {
-	if s == nil || !(s.state == cliStateSessionActivated || s.state == srvStateSessionActivated) {
+	if s == nil || !s.isActivated() {
 		return 0, ErrSessionNotActivated
 	}
 
@@ -101,12 +133,19 @@ func (s *Session) Write(b []byte) (n int, err error) {
 // while the UASC header is automatically set by the package.
 // This enables writing arbitrary Service even if the service is not implemented in the package.
 func (s *Session) WriteService(b []byte) (n int, err error) {
-	if !(s.state == cliStateSessionActivated || s.state == srvStateSessionActivated) {
+	if !s.isActivated() {
 		return 0, ErrSessionNotActivated
 	}
 	return s.secChan.WriteService(b)
 }
 
+// isActivated reports whether the Session is activated.
+func (s *Session) isActivated() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.state == cliStateSessionActivated || s.state == srvStateSessionActivated
+}
+
 // Close closes the connection.
 // Any blocked Read or Write operations will be unblocked and return errors.
 //
@@ -116,9 +155,51 @@ func (s *Session) Close() error {
 	defer s.mu.Unlock()
 
 	err := s.CloseSessionRequest(true)
+	if cerr := s.shutdown(); cerr != nil {
+		return cerr
+	}
+	return err
+}
+
+// CloseWithContext is the same as Close but waits for the CloseSessionResponse
+// until ctx is done, so that the server has released the Session when it returns.
+//
+// The connection is closed even if ctx is done before the response arrives,
+// and ctx.Err() is returned in that case.
+func (s *Session) CloseWithContext(ctx context.Context) error {
+	s.mu.Lock()
+	err := s.CloseSessionRequest(true)
+	if err == nil {
+		switch s.state {
+		case cliStateSessionCreated, cliStateSessionActivated:
+			s.state = cliStateCloseSessionSent
+		}
+	}
+	waiting := s.state == cliStateCloseSessionSent
+	s.mu.Unlock()
 
+	if err == nil && waiting {
+		select {
+		case <-s.sessionClosed:
+		case <-ctx.Done():
+			err = ctx.Err()
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if cerr := s.shutdown(); cerr != nil {
+		return cerr
+	}
+	return err
+}
+
+// shutdown updates the state and closes the connection.
+//
+// This should be called with s.mu held.
+func (s *Session) shutdown() error {
 	switch s.state {
-	case cliStateCreateSessionSent, cliStateActivateSessionSent, cliStateCloseSessionSent, cliStateSessionCreated, cliStateSessionActivated:
+	case cliStateCreateSessionSent, cliStateActivateSessionSent, cliStateCloseSessionSent, cliStateSessionCreated, cliStateSessionActivated, cliStateSessionClosed:
 		s.state = cliStateSessionCreated
 	case srvStateSessionCreated, srvStateSessionActivated, srvStateSessionClosed:
 		s.state = srvStateSessionClosed
@@ -128,21 +209,30 @@ func (s *Session) Close() error {
 	}
 
 	s.close()
-	return err
+	return nil
 }
 
+// close releases the Session. secChan is kept, as the monitor and the other methods
+// may still be using it until they see closed.
+//
+// This should be called with s.mu held.
 func (s *Session) close() {
 	s.cfg = nil
-	s.rcvBuf = []byte{}
-	s.sndBuf = []byte{}
-	s.secChan = nil
 
 	close(s.errChan)
-	close(s.lenChan)
+	close(s.closed)
 	close(s.created)
 	close(s.activated)
 }
 
+// AuthenticationToken returns the AuthenticationToken which identifies
+// the Session in the RequestHeader of each request.
+//
+// This is expected to be called from client side of Session.
+func (s *Session) AuthenticationToken() *datatypes.NodeID {
+	return s.secChan.reqHeader.AuthenticationToken
+}
+
 // LocalAddr returns the local network address.
 func (s *Session) LocalAddr() net.Addr {
 	return s.secChan.LocalAddr()
@@ -215,9 +305,19 @@ func (s *Session) monitor(ctx context.Context) {
 		default:
 			n, err := s.secChan.Read(s.rcvBuf)
 			if err != nil {
-				if err == io.EOF {
+				if _, ok := err.(*AbortError); ok {
+					go s.notifyAbort(childCtx, err)
 					continue
 				}
+
+				if err == io.ErrShortBuffer {
+					// the message assembled from chunks is larger than rcvBuf.
+					// It is read again after extending rcvBuf.
+					s.rcvBuf = make([]byte, n)
+					continue
+				}
+				s.readErr = err
+				close(s.broken)
 				cancel()
 				return
 			}
@@ -228,10 +328,11 @@ func (s *Session) monitor(ctx context.Context) {
 				continue
 			}
 
-			msg, err := Decode(s.rcvBuf[:n])
+			b := append([]byte{}, s.rcvBuf[:n]...)
+			msg, err := Decode(b)
 			if err != nil {
 				// pass to the user if msg is undecodable as UASC.
-				go s.notifyLength(childCtx, n)
+				s.notify(childCtx, b)
 				continue
 			}
 
@@ -248,20 +349,37 @@ func (s *Session) monitor(ctx context.Context) {
 				go s.handleCloseSessionRequest(m)
 			case *services.CloseSessionResponse:
 				go s.handleCloseSessionResponse(m)
+			case *services.ServiceFault:
+				// the ServiceFault is passed to the user unless it is for the
+				// CreateSessionRequest or the ActivateSessionRequest.
+				if !s.handleServiceFault(m) {
+					s.notify(childCtx, b)
+				}
 			default:
 				// pass to the user if type of msg is unknown.
-				go s.notifyLength(childCtx, n)
+				s.notify(childCtx, b)
 			}
 		}
 	}
 }
 
-func (s *Session) notifyLength(ctx context.Context, n int) {
+// notify passes the message to Read. The messages are passed in the order received,
+// and the monitor waits for Read to take it rather than dropping it.
+func (s *Session) notify(ctx context.Context, b []byte) {
 	select {
 	case <-ctx.Done():
-		return
-	case s.lenChan <- n:
-		return
+	case <-s.closed:
+	case s.rcvChan <- b:
+	}
+}
+
+// notifyAbort passes the error to Read. abortChan is never closed, as notifyAbort may
+// still be running after close, which stops it with closed instead.
+func (s *Session) notifyAbort(ctx context.Context, err error) {
+	select {
+	case <-ctx.Done():
+	case <-s.closed:
+	case s.abortChan <- err:
 	}
 }
 
@@ -304,6 +422,8 @@ func (s *Session) handleCreateSessionResponse(cs *services.CreateSessionResponse
 			s.cfg.ServerEndpoints = cs.ServerEndpoints.EndpointDescriptions
 			s.cfg.SessionTimeout = cs.RevisedSessionTimeout
 			s.cfg.signatureToSend = services.NewSignatureDataFrom(cs.ServerCertificate.Get(), cs.ServerNonce.Get())
+			s.cfg.serverNonce = cs.ServerNonce.Get()
+			s.cfg.serverCertificate = cs.ServerCertificate.Get()
 			s.sndBuf = make([]byte, cs.MaxRequestMessageSize)
 
 			s.state = cliStateSessionCreated
@@ -316,6 +436,27 @@ func (s *Session) handleCreateSessionResponse(cs *services.CreateSessionResponse
 	}
 }
 
+// handleServiceFault fails the CreateSession or the ActivateSession in progress with
+// ErrRejected, and returns false if neither of them is in progress.
+func (s *Session) handleServiceFault(f *services.ServiceFault) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch s.state {
+	case cliStateCreateSessionSent:
+		s.secChan.logger.Warn("session creation rejected", "status", f.ServiceResult)
+		s.state = cliStateSessionClosed
+	case cliStateActivateSessionSent:
+		s.secChan.logger.Warn("session activation rejected", "status", f.ServiceResult)
+		// the Session can still be activated again, e.g. with another identity.
+		s.state = cliStateSessionCreated
+	default:
+		return false
+	}
+	go func() { s.errChan <- ErrRejected }()
+	return true
+}
+
 func (s *Session) handleActivateSessionRequest(as *services.ActivateSessionRequest) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -343,9 +484,18 @@ func (s *Session) handleActivateSessionRequest(as *services.ActivateSessionReque
 			s.errChan <- err
 			return
 		}
+		s.secChan.logger.Info("session activated")
 		s.state = srvStateSessionActivated
 		s.activated <- true
 		return
+	case srvStateSessionClosed:
+		// the Session is not created on this SecureChannel, which is the case when
+		// the client tries to resume the Session created on another SecureChannel.
+		// It is rejected so that the client creates a new Session instead.
+		if err := s.ActivateSessionFault(status.BadSessionIdInvalid); err != nil {
+			s.errChan <- err
+		}
+		return
 	default:
 		s.errChan <- ErrInvalidState
 	}
@@ -357,11 +507,25 @@ func (s *Session) handleActivateSessionResponse(as *services.ActivateSessionResp
 
 	switch s.state {
 	case cliStateActivateSessionSent:
-		for _, result := range as.Results.Values {
-			if result != 0 {
-				s.errChan <- ErrRejected
+		rejected := as.ResponseHeader.ServiceResult != 0
+		if as.Results != nil {
+			for _, result := range as.Results.Values {
+				if result != 0 {
+					rejected = true
+				}
 			}
 		}
+		if rejected {
+			s.secChan.logger.Warn("session activation rejected", "status", as.ResponseHeader.ServiceResult)
+			// the Session can still be activated again, e.g. with another identity.
+			s.state = cliStateSessionCreated
+			s.errChan <- ErrRejected
+			return
+		}
+		if as.ServerNonce != nil {
+			s.cfg.serverNonce = as.ServerNonce.Get()
+		}
+		s.secChan.logger.Info("session activated", "timeout", s.cfg.SessionTimeout)
 		s.state = cliStateSessionActivated
 		s.activated <- true
 		return
@@ -395,9 +559,17 @@ func (s *Session) handleCloseSessionResponse(cs *services.CloseSessionResponse)
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	// the late response after closing is ignored.
+	select {
+	case <-s.closed:
+		return
+	default:
+	}
+
 	switch s.state {
 	case cliStateCloseSessionSent:
 		s.state = cliStateSessionClosed
+		close(s.sessionClosed)
 		return
 	default:
 		s.errChan <- ErrInvalidState
@@ -464,12 +636,19 @@ func (s *Session) CreateSessionResponse() error {
 }
 
 // ActivateSessionRequest sends a ActivateSessionRequest.
+//
+// The password of UserNameIdentityToken is encrypted, and X509IdentityToken is signed,
+// as the UserTokenPolicy of the server requires.
 func (s *Session) ActivateSessionRequest() error {
+	token, sig, err := s.userIdentityToken()
+	if err != nil {
+		return err
+	}
+
 	s.secChan.reqHeader.RequestHandle++
 	s.secChan.reqHeader.Timestamp = time.Now()
 	asr, err := services.NewActivateSessionRequest(
-		s.secChan.reqHeader, s.cfg.signatureToSend, s.cfg.LocaleIDs, s.cfg.UserIdentityToken,
-		s.cfg.UserTokenSignature,
+		s.secChan.reqHeader, s.cfg.signatureToSend, s.cfg.LocaleIDs, token, sig,
 	).Serialize()
 	if err != nil {
 		s.secChan.reqHeader.RequestHandle--
@@ -506,6 +685,30 @@ func (s *Session) ActivateSessionResponse(results ...uint32) error {
 	return nil
 }
 
+// ActivateSessionFault sends a ServiceFault with the ServiceResult given
+// to reject the ActivateSessionRequest.
+func (s *Session) ActivateSessionFault(code uint32) error {
+	return s.ServiceFault(code)
+}
+
+// ServiceFault sends a ServiceFault with the ServiceResult given to reject the
+// last request received as a whole.
+func (s *Session) ServiceFault(code uint32) error {
+	s.secChan.resHeader.ServiceResult = code
+	s.secChan.resHeader.Timestamp = time.Now()
+	defer func() { s.secChan.resHeader.ServiceResult = 0 }()
+
+	b, err := services.NewServiceFault(s.secChan.resHeader).Serialize()
+	if err != nil {
+		return err
+	}
+
+	if _, err := s.secChan.WriteService(b); err != nil {
+		return err
+	}
+	return nil
+}
+
 // CloseSessionRequest sends a CloseSessionRequest.
 func (s *Session) CloseSessionRequest(delete bool) error {
 	s.secChan.reqHeader.RequestHandle++
@@ -540,7 +743,7 @@ func (s *Session) CloseSessionResponse() error {
 }
 
 // ReadRequest sends a ReadRequest.
-func (s *Session) ReadRequest(maxAge uint64, tsRet services.TimestampsToReturn, nodes ...*datatypes.ReadValueID) error {
+func (s *Session) ReadRequest(maxAge float64, tsRet services.TimestampsToReturn, nodes ...*datatypes.ReadValueID) error {
 	s.secChan.reqHeader.RequestHandle++
 	s.secChan.reqHeader.Timestamp = time.Now()
 	rdr, err := services.NewReadRequest(
@@ -0,0 +1,168 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package uasc
+
+import (
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/wmnsk/gopcua/errors"
+)
+
+// TrustStore is the list of the Certificates trusted to verify the Certificate of the remote
+// in OpenSecureChannel.
+//
+// The CA Certificates in it are the trust anchors of the chains, while the other ones are
+// trusted as they are, e.g. the self-signed Certificates of the applications.
+//
+// Specification: Part 4, 6.1.3
+type TrustStore struct {
+	roots *x509.CertPool
+	// peers is the Certificates trusted as they are, keyed by their thumbprints.
+	peers map[string]bool
+}
+
+// NewTrustStore creates a new TrustStore with the DER encoded Certificates given.
+func NewTrustStore(certs ...[]byte) (*TrustStore, error) {
+	t := &TrustStore{
+		roots: x509.NewCertPool(),
+		peers: map[string]bool{},
+	}
+	for _, cert := range certs {
+		if err := t.AddCertificate(cert); err != nil {
+			return nil, err
+		}
+	}
+
+	return t, nil
+}
+
+// LoadTrustStore creates a new TrustStore with the Certificates in the files with the extension
+// ".der", ".crt", ".cer" or ".pem" in the directory given. The files may be either DER or PEM
+// encoded, and a PEM encoded file may contain multiple Certificates.
+func LoadTrustStore(dir string) (*TrustStore, error) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	t, _ := NewTrustStore()
+	for _, f := range files {
+		if f.IsDir() {
+			continue
+		}
+		switch strings.ToLower(filepath.Ext(f.Name())) {
+		case ".der", ".crt", ".cer", ".pem":
+		default:
+			continue
+		}
+
+		path := filepath.Join(dir, f.Name())
+		b, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := t.addFile(b); err != nil {
+			return nil, errors.Wrap(err, path)
+		}
+	}
+
+	return t, nil
+}
+
+// addFile adds the Certificates in the DER or PEM encoded file.
+func (t *TrustStore) addFile(b []byte) error {
+	block, rest := pem.Decode(b)
+	if block == nil {
+		return t.AddCertificate(b)
+	}
+
+	for ; block != nil; block, rest = pem.Decode(rest) {
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		if err := t.AddCertificate(block.Bytes); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AddCertificate adds the DER encoded Certificate to TrustStore.
+func (t *TrustStore) AddCertificate(cert []byte) error {
+	c, err := x509.ParseCertificate(cert)
+	if err != nil {
+		return err
+	}
+
+	if c.IsCA {
+		t.roots.AddCert(c)
+		return nil
+	}
+	t.peers[hex.EncodeToString(Thumbprint(cert))] = true
+
+	return nil
+}
+
+// Verify verifies the DER encoded Certificate of the remote at the time now.
+//
+// It returns ErrCertificateExpired if now is out of the validity period of the Certificate,
+// ErrCertificateURIMismatch if the appURI is not empty and not in the URIs of its
+// SubjectAltName, and ErrUntrustedCertificate if it is neither in TrustStore nor
+// issued by the CAs in it.
+func (t *TrustStore) Verify(cert []byte, appURI string, now time.Time) error {
+	c, err := x509.ParseCertificate(cert)
+	if err != nil {
+		return errors.Wrap(ErrUntrustedCertificate, err.Error())
+	}
+
+	if now.Before(c.NotBefore) || now.After(c.NotAfter) {
+		return errors.Wrapf(ErrCertificateExpired, "valid from %s to %s",
+			c.NotBefore.Format(time.RFC3339), c.NotAfter.Format(time.RFC3339))
+	}
+
+	if appURI != "" && !hasURI(c, appURI) {
+		return errors.Wrapf(ErrCertificateURIMismatch, "want %s", appURI)
+	}
+
+	if t.peers[hex.EncodeToString(Thumbprint(cert))] {
+		return nil
+	}
+	opts := x509.VerifyOptions{
+		Roots:       t.roots,
+		CurrentTime: now,
+		KeyUsages:   []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	}
+	if _, err := c.Verify(opts); err != nil {
+		return errors.Wrap(ErrUntrustedCertificate, err.Error())
+	}
+
+	return nil
+}
+
+// hasURI reports whether the URI is in the SubjectAltName of the Certificate.
+func hasURI(c *x509.Certificate, uri string) bool {
+	for _, u := range c.URIs {
+		if u.String() == uri {
+			return true
+		}
+	}
+	return false
+}
+
+// verifyRemote verifies the DER encoded Certificate of the remote with TrustStore in Config.
+// Any Certificate is accepted if TrustStore is not set.
+func (c *Config) verifyRemote(cert []byte) error {
+	if c.TrustStore == nil {
+		return nil
+	}
+	return c.TrustStore.Verify(cert, c.RemoteApplicationURI, time.Now())
+}
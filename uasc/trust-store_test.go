@@ -0,0 +1,204 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package uasc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io/ioutil"
+	"math/big"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/wmnsk/gopcua/errors"
+	"github.com/wmnsk/gopcua/services"
+)
+
+// issueCertificate generates a 2048 bits RSA key and a certificate of it from the template,
+// signed by the parent, or self-signed if the parent is nil.
+func issueCertificate(t *testing.T, tmpl *x509.Certificate, parent []byte, parentKey *rsa.PrivateKey) ([]byte, *rsa.PrivateKey) {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	issuer, signer := tmpl, key
+	if parent != nil {
+		if issuer, err = x509.ParseCertificate(parent); err != nil {
+			t.Fatal(err)
+		}
+		signer = parentKey
+	}
+	cert, err := x509.CreateCertificate(rand.Reader, tmpl, issuer, &key.PublicKey, signer)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert, key
+}
+
+func TestTrustStoreVerify(t *testing.T) {
+	now := time.Now()
+	appURI, err := url.Parse("urn:gopcua:server")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	caCert, caKey := issueCertificate(t, &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "ca"},
+		NotBefore:             now.Add(-time.Hour),
+		NotAfter:              now.Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}, nil, nil)
+	issued, _ := issueCertificate(t, &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: "issued"},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(time.Hour),
+		URIs:         []*url.URL{appURI},
+	}, caCert, caKey)
+	peer, _ := issueCertificate(t, &x509.Certificate{
+		SerialNumber: big.NewInt(3),
+		Subject:      pkix.Name{CommonName: "peer"},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(time.Hour),
+		URIs:         []*url.URL{appURI},
+	}, nil, nil)
+	expired, _ := issueCertificate(t, &x509.Certificate{
+		SerialNumber: big.NewInt(4),
+		Subject:      pkix.Name{CommonName: "expired"},
+		NotBefore:    now.Add(-2 * time.Hour),
+		NotAfter:     now.Add(-time.Hour),
+	}, nil, nil)
+	untrusted, _ := issueCertificate(t, &x509.Certificate{
+		SerialNumber: big.NewInt(5),
+		Subject:      pkix.Name{CommonName: "untrusted"},
+		NotBefore:    now.Add(-time.Hour),
+		NotAfter:     now.Add(time.Hour),
+	}, nil, nil)
+
+	store, err := NewTrustStore(caCert, peer, expired)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name   string
+		cert   []byte
+		appURI string
+		err    error
+	}{
+		{"issued-by-ca", issued, "", nil},
+		{"issued-by-ca-with-uri", issued, "urn:gopcua:server", nil},
+		{"peer", peer, "urn:gopcua:server", nil},
+		{"expired", expired, "", ErrCertificateExpired},
+		{"untrusted", untrusted, "", ErrUntrustedCertificate},
+		{"uri-mismatch", peer, "urn:gopcua:other", ErrCertificateURIMismatch},
+		{"malformed", []byte{0xde, 0xad, 0xbe, 0xef}, "", ErrUntrustedCertificate},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			err := store.Verify(c.cert, c.appURI, now)
+			if got, want := errors.Cause(err), c.err; got != want {
+				t.Fatalf("got error %v want %v", err, want)
+			}
+		})
+	}
+}
+
+func TestLoadTrustStore(t *testing.T) {
+	trusted, _ := newCertificate(t, "trusted")
+	pemTrusted, _ := newCertificate(t, "pem")
+	ignored, _ := newCertificate(t, "ignored")
+
+	dir, err := ioutil.TempDir("", "gopcua")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string][]byte{
+		"trusted.der": trusted,
+		"pem.pem":     pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: pemTrusted}),
+		"ignored.txt": ignored,
+	}
+	for name, b := range files {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), b, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	store, err := LoadTrustStore(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, cert := range [][]byte{trusted, pemTrusted} {
+		if err := store.Verify(cert, "", time.Now()); err != nil {
+			t.Error(err)
+		}
+	}
+	if err := store.Verify(ignored, "", time.Now()); errors.Cause(err) != ErrUntrustedCertificate {
+		t.Errorf("got error %v want %v", err, ErrUntrustedCertificate)
+	}
+}
+
+func TestSecureChannelTrustStore(t *testing.T) {
+	cliCert, cliKey := newCertificate(t, "client")
+	srvCert, srvKey := newCertificate(t, "server")
+	otherCert, _ := newCertificate(t, "other")
+
+	t.Run("trusted", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		var err error
+		cliCfg := NewClientConfig(policyBasic256Sha256, cliCert, nil, 3333, services.SecModeSign, 3600000)
+		cliCfg.PrivateKey = cliKey
+		cliCfg.RemoteCertificate = srvCert
+		if cliCfg.TrustStore, err = NewTrustStore(srvCert); err != nil {
+			t.Fatal(err)
+		}
+		srvCfg := NewServerConfig(policyBasic256Sha256, srvCert, nil, 1111, services.SecModeSign, 2222, 3600000)
+		srvCfg.PrivateKey = srvKey
+		if srvCfg.TrustStore, err = NewTrustStore(cliCert); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, _, err := setUpSecureChannelWithConfig(ctx, cliCfg, srvCfg); err != nil {
+			t.Fatal(err)
+		}
+	})
+
+	t.Run("untrusted", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		var err error
+		cliCfg := NewClientConfig(policyBasic256Sha256, cliCert, nil, 3333, services.SecModeSign, 3600000)
+		cliCfg.PrivateKey = cliKey
+		cliCfg.RemoteCertificate = srvCert
+		if cliCfg.TrustStore, err = NewTrustStore(otherCert); err != nil {
+			t.Fatal(err)
+		}
+		srvCfg := NewServerConfig(policyBasic256Sha256, srvCert, nil, 1111, services.SecModeSign, 2222, 3600000)
+		srvCfg.PrivateKey = srvKey
+
+		_, _, err = setUpSecureChannelWithConfig(ctx, cliCfg, srvCfg)
+		if got, want := errors.Cause(err), ErrUntrustedCertificate; got != want {
+			t.Fatalf("got error %v want %v", err, want)
+		}
+	})
+}
@@ -0,0 +1,234 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package uasc
+
+import (
+	"crypto/rsa"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/wmnsk/gopcua/datatypes"
+	"github.com/wmnsk/gopcua/errors"
+	"github.com/wmnsk/gopcua/securitypolicy"
+	"github.com/wmnsk/gopcua/services"
+)
+
+// userIdentityToken returns the UserIdentityToken in the SessionConfig and the UserTokenSignature
+// to be sent in ActivateSessionRequest.
+//
+// The PolicyID is replaced with the one of the UserTokenPolicy for the type of the token
+// the Endpoint of the server provides, unless it is one of them, and ErrNoUserTokenPolicy is
+// returned if there is none of the type. The password of UserNameIdentityToken is encrypted
+// with the last nonce of the server as the SecurityPolicy of the UserTokenPolicy requires,
+// and X509IdentityToken is signed with the UserTokenPrivateKey in the same way.
+// The token in the SessionConfig is kept as it is, so that it can be sent again.
+func (s *Session) userIdentityToken() (datatypes.UserIdentityToken, *services.SignatureData, error) {
+	switch tok := s.cfg.UserIdentityToken.(type) {
+	case *datatypes.AnonymousIdentityToken:
+		t := *tok
+		p, err := s.userTokenPolicy(services.UserTokenAnonymous, tok.PolicyID.Get())
+		if err != nil {
+			return nil, nil, err
+		}
+		if p != nil {
+			t.PolicyID = p.PolicyID
+		}
+		return &t, s.cfg.UserTokenSignature, nil
+	case *datatypes.UserNameIdentityToken:
+		t := *tok
+		p, err := s.userTokenPolicy(services.UserTokenUsername, tok.PolicyID.Get())
+		if err != nil {
+			return nil, nil, err
+		}
+		policyURI := s.userTokenSecurityPolicy(p)
+		if p != nil {
+			t.PolicyID = p.PolicyID
+		}
+
+		password, alg, err := encryptUserPassword(policyURI, s.serverCertificate(), tok.Password.Get(), s.cfg.serverNonce)
+		if err != nil {
+			return nil, nil, err
+		}
+		t.Password = datatypes.NewByteString(password)
+		t.EncryptionAlgorithm = datatypes.NewString(alg)
+		return &t, s.cfg.UserTokenSignature, nil
+	case *datatypes.X509IdentityToken:
+		t := *tok
+		p, err := s.userTokenPolicy(services.UserTokenCertificate, tok.PolicyID.Get())
+		if err != nil {
+			return nil, nil, err
+		}
+		policyURI := s.userTokenSecurityPolicy(p)
+		if p != nil {
+			t.PolicyID = p.PolicyID
+		}
+		if s.cfg.UserTokenPrivateKey == nil {
+			return &t, s.cfg.UserTokenSignature, nil
+		}
+
+		sig, alg, err := signUserToken(policyURI, s.cfg.UserTokenPrivateKey, s.serverCertificate(), s.cfg.serverNonce)
+		if err != nil {
+			return nil, nil, err
+		}
+		return &t, services.NewSignatureData(alg, sig), nil
+	case *datatypes.IssuedIdentityToken:
+		t := *tok
+		p, err := s.userTokenPolicy(services.UserTokenIssuedToken, tok.PolicyID.Get())
+		if err != nil {
+			return nil, nil, err
+		}
+		if p != nil {
+			t.PolicyID = p.PolicyID
+		}
+		return &t, s.cfg.UserTokenSignature, nil
+	default:
+		return tok, s.cfg.UserTokenSignature, nil
+	}
+}
+
+// userTokenSecurityPolicy returns the URI of the SecurityPolicy of the UserTokenPolicy p,
+// or the one of the SecureChannel if p is nil or does not specify it.
+func (s *Session) userTokenSecurityPolicy(p *services.UserTokenPolicy) string {
+	if p != nil && p.SecurityPolicyURI != nil && p.SecurityPolicyURI.Get() != "" {
+		return p.SecurityPolicyURI.Get()
+	}
+	return s.secChan.cfg.SecurityPolicyURI
+}
+
+// userTokenPolicy returns the UserTokenPolicy of the tokenType in the Endpoint of the server,
+// preferring the one with the policyID.
+//
+// It returns nil if the Endpoint is not known, e.g. the server returns no Endpoints in
+// CreateSessionResponse, and ErrNoUserTokenPolicy if the Endpoint provides none of the type.
+func (s *Session) userTokenPolicy(tokenType uint32, policyID string) (*services.UserTokenPolicy, error) {
+	ep := s.serverEndpoint()
+	if ep == nil || ep.UserIdentityTokens == nil {
+		return nil, nil
+	}
+
+	var found *services.UserTokenPolicy
+	for _, p := range ep.UserIdentityTokens.UserTokenPolicies {
+		if p.TokenType != tokenType {
+			continue
+		}
+		if p.PolicyID.Get() == policyID {
+			return p, nil
+		}
+		if found == nil {
+			found = p
+		}
+	}
+	if found == nil {
+		return nil, errors.Wrapf(ErrNoUserTokenPolicy, "%s token in %s", userTokenTypeName(tokenType), ep.EndpointURL.Get())
+	}
+	return found, nil
+}
+
+// userTokenTypeName returns the name of the UserTokenType.
+func userTokenTypeName(tokenType uint32) string {
+	switch tokenType {
+	case services.UserTokenAnonymous:
+		return "Anonymous"
+	case services.UserTokenUsername:
+		return "UserName"
+	case services.UserTokenCertificate:
+		return "Certificate"
+	case services.UserTokenIssuedToken:
+		return "IssuedToken"
+	default:
+		return fmt.Sprintf("unknown(%d)", tokenType)
+	}
+}
+
+// serverEndpoint returns the Endpoint of the server which has the SecurityPolicy and the
+// SecurityMode of the SecureChannel, or the first one if there is no such Endpoint.
+func (s *Session) serverEndpoint() *services.EndpointDescription {
+	for _, ep := range s.cfg.ServerEndpoints {
+		if ep.SecurityPolicyURI.Get() == s.secChan.cfg.SecurityPolicyURI && ep.MessageSecurityMode == s.secChan.cfg.SecurityMode {
+			return ep
+		}
+	}
+	if len(s.cfg.ServerEndpoints) > 0 {
+		return s.cfg.ServerEndpoints[0]
+	}
+	return nil
+}
+
+// serverCertificate returns the Certificate of the server given in CreateSessionResponse,
+// or the one in its Endpoint if not given.
+func (s *Session) serverCertificate() []byte {
+	if len(s.cfg.serverCertificate) > 0 {
+		return s.cfg.serverCertificate
+	}
+	if ep := s.serverEndpoint(); ep != nil && ep.ServerCertificate != nil {
+		return ep.ServerCertificate.Get()
+	}
+	return nil
+}
+
+// encryptUserPassword encrypts the password of UserNameIdentityToken with the public key in
+// the DER encoded cert, using the asymmetric algorithm of the SecurityPolicy. It returns the
+// encrypted password and the URI of the algorithm.
+//
+// The data encrypted is the length of the rest, the password and the nonce of the server,
+// as described in Part4, 7.36.2.2. The password is returned as it is if the SecurityPolicy is None.
+func encryptUserPassword(policyURI string, cert, password, nonce []byte) ([]byte, string, error) {
+	if isSecurityPolicyNone(policyURI) {
+		return password, "", nil
+	}
+
+	key, err := parsePublicKey(cert)
+	if err != nil {
+		return nil, "", err
+	}
+	alg, err := securitypolicy.Asymmetric(policyURI, nil, key)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(nonce) == 0 {
+		return nil, "", errors.New("server nonce is required to encrypt the password")
+	}
+
+	b := make([]byte, 4+len(password)+len(nonce))
+	binary.LittleEndian.PutUint32(b[:4], uint32(len(password)+len(nonce)))
+	copy(b[4:], password)
+	copy(b[4+len(password):], nonce)
+
+	encrypted, err := alg.Encrypt(b)
+	if err != nil {
+		return nil, "", err
+	}
+	return encrypted, alg.EncryptionURI(), nil
+}
+
+// signUserToken signs the DER encoded cert and the nonce of the server with the private key
+// of X509IdentityToken, using the asymmetric algorithm of the SecurityPolicy. It returns the
+// signature and the URI of the algorithm.
+//
+// The data signed is the same as the clientSignature, as described in Part4, 7.36.5.
+// Nothing is signed if the SecurityPolicy is None.
+func signUserToken(policyURI string, key *rsa.PrivateKey, cert, nonce []byte) ([]byte, string, error) {
+	if isSecurityPolicyNone(policyURI) {
+		return nil, "", nil
+	}
+
+	alg, err := securitypolicy.Asymmetric(policyURI, key, nil)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(nonce) == 0 {
+		return nil, "", errors.New("server nonce is required to sign the user token")
+	}
+
+	b := make([]byte, 0, len(cert)+len(nonce))
+	b = append(b, cert...)
+	b = append(b, nonce...)
+
+	sig, err := alg.Signature(b)
+	if err != nil {
+		return nil, "", err
+	}
+	return sig, alg.SignatureURI(), nil
+}
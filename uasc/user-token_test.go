@@ -0,0 +1,274 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package uasc
+
+import (
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/wmnsk/gopcua/datatypes"
+	"github.com/wmnsk/gopcua/errors"
+	"github.com/wmnsk/gopcua/securitypolicy"
+	"github.com/wmnsk/gopcua/services"
+)
+
+func TestEncryptUserPassword(t *testing.T) {
+	cert, key := newCertificate(t, "server")
+	password := []byte("secret")
+	nonce := []byte{0x01, 0x02, 0x03, 0x04, 0x05, 0x06, 0x07, 0x08}
+
+	t.Run("basic256sha256", func(t *testing.T) {
+		encrypted, alg, err := encryptUserPassword(policyBasic256Sha256, cert, password, nonce)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := "http://www.w3.org/2001/04/xmlenc#rsa-oaep"; alg != want {
+			t.Errorf("got algorithm %s want %s", alg, want)
+		}
+
+		dec, err := securitypolicy.Asymmetric(policyBasic256Sha256, key, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, err := dec.Decrypt(encrypted)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got, want := binary.LittleEndian.Uint32(b[:4]), uint32(len(password)+len(nonce)); got != want {
+			t.Errorf("got length %d want %d", got, want)
+		}
+		if diff := cmp.Diff(b[4:4+len(password)+len(nonce)], append(append([]byte{}, password...), nonce...)); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("none", func(t *testing.T) {
+		encrypted, alg, err := encryptUserPassword("http://opcfoundation.org/UA/SecurityPolicy#None", nil, password, nonce)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if alg != "" {
+			t.Errorf("got algorithm %s want empty", alg)
+		}
+		if diff := cmp.Diff(encrypted, password); diff != "" {
+			t.Error(diff)
+		}
+	})
+	t.Run("no-nonce", func(t *testing.T) {
+		if _, _, err := encryptUserPassword(policyBasic256Sha256, cert, password, nil); err == nil {
+			t.Error("got nil want error")
+		}
+	})
+}
+
+func TestSessionUserIdentityToken(t *testing.T) {
+	cert, key := newCertificate(t, "server")
+	nonce := []byte{0x01, 0x02, 0x03, 0x04}
+
+	s := &Session{
+		secChan: &SecureChannel{
+			cfg: &Config{
+				SecurityPolicyURI: "http://opcfoundation.org/UA/SecurityPolicy#None",
+				SecurityMode:      services.SecModeNone,
+			},
+		},
+		cfg: &SessionConfig{
+			UserIdentityToken: datatypes.NewUserNameIdentityToken("", "user", []byte("secret"), ""),
+			ServerEndpoints: []*services.EndpointDescription{
+				services.NewEndpointDescription(
+					"opc.tcp://127.0.0.1:4840", nil, cert, services.SecModeNone,
+					"http://opcfoundation.org/UA/SecurityPolicy#None",
+					services.NewUserTokenPolicyArray([]*services.UserTokenPolicy{
+						services.NewUserTokenPolicy("anonymous", services.UserTokenAnonymous, "", "", ""),
+						services.NewUserTokenPolicy("username", services.UserTokenUsername, "", "", policyBasic256Sha256),
+					}),
+					"", 0,
+				),
+			},
+			serverNonce: nonce,
+		},
+	}
+
+	tok, _, err := s.userIdentityToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	u, ok := tok.(*datatypes.UserNameIdentityToken)
+	if !ok {
+		t.Fatalf("got %T want *datatypes.UserNameIdentityToken", tok)
+	}
+	if got, want := u.PolicyID.Get(), "username"; got != want {
+		t.Errorf("got PolicyID %s want %s", got, want)
+	}
+	if got, want := u.EncryptionAlgorithm.Get(), "http://www.w3.org/2001/04/xmlenc#rsa-oaep"; got != want {
+		t.Errorf("got EncryptionAlgorithm %s want %s", got, want)
+	}
+
+	dec, err := securitypolicy.Asymmetric(policyBasic256Sha256, key, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := dec.Decrypt(u.Password.Get())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(b[4:14], []byte("secret\x01\x02\x03\x04")); diff != "" {
+		t.Error(diff)
+	}
+
+	// the token in the config is kept in plaintext.
+	if got := string(s.cfg.UserIdentityToken.(*datatypes.UserNameIdentityToken).Password.Get()); got != "secret" {
+		t.Errorf("got password %q in config want %q", got, "secret")
+	}
+}
+
+func TestSessionUserTokenPolicy(t *testing.T) {
+	cert, _ := newCertificate(t, "server")
+	policyNone := "http://opcfoundation.org/UA/SecurityPolicy#None"
+
+	s := &Session{
+		secChan: &SecureChannel{
+			cfg: &Config{
+				SecurityPolicyURI: policyNone,
+				SecurityMode:      services.SecModeNone,
+			},
+		},
+		cfg: &SessionConfig{
+			ServerEndpoints: []*services.EndpointDescription{
+				// the other Endpoint, whose UserTokenPolicies should not be selected.
+				services.NewEndpointDescription(
+					"opc.tcp://127.0.0.1:4840", nil, cert, services.SecModeSignAndEncrypt, policyBasic256Sha256,
+					services.NewUserTokenPolicyArray([]*services.UserTokenPolicy{
+						services.NewUserTokenPolicy("issued", services.UserTokenIssuedToken, "", "", ""),
+					}),
+					"", 0,
+				),
+				services.NewEndpointDescription(
+					"opc.tcp://127.0.0.1:4840", nil, cert, services.SecModeNone, policyNone,
+					services.NewUserTokenPolicyArray([]*services.UserTokenPolicy{
+						services.NewUserTokenPolicy("anonymous", services.UserTokenAnonymous, "", "", ""),
+						services.NewUserTokenPolicy("username-none", services.UserTokenUsername, "", "", ""),
+						services.NewUserTokenPolicy("username-basic256sha256", services.UserTokenUsername, "", "", policyBasic256Sha256),
+						services.NewUserTokenPolicy("certificate", services.UserTokenCertificate, "", "", ""),
+					}),
+					"", 0,
+				),
+			},
+			serverNonce: []byte{0x01, 0x02, 0x03, 0x04},
+		},
+	}
+
+	cases := []struct {
+		name     string
+		token    datatypes.UserIdentityToken
+		policyID string
+		alg      string
+		err      error
+	}{
+		{"anonymous", datatypes.NewAnonymousIdentityToken(""), "anonymous", "", nil},
+		{"username", datatypes.NewUserNameIdentityToken("", "user", []byte("secret"), ""), "username-none", "", nil},
+		{
+			"username-policy-id",
+			datatypes.NewUserNameIdentityToken("username-basic256sha256", "user", []byte("secret"), ""),
+			"username-basic256sha256", "http://www.w3.org/2001/04/xmlenc#rsa-oaep", nil,
+		},
+		{"username-unknown-policy-id", datatypes.NewUserNameIdentityToken("foo", "user", []byte("secret"), ""), "username-none", "", nil},
+		{"certificate", datatypes.NewX509IdentityToken("", ""), "certificate", "", nil},
+		{"issued", datatypes.NewIssuedIdentityToken("", nil, ""), "", "", ErrNoUserTokenPolicy},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			s.cfg.UserIdentityToken = c.token
+			tok, _, err := s.userIdentityToken()
+			if got, want := errors.Cause(err), c.err; got != want {
+				t.Fatalf("got error %v want %v", got, want)
+			}
+			if err != nil {
+				return
+			}
+			if got, want := tok.(interface{ ID() string }).ID(), c.policyID; got != want {
+				t.Errorf("got PolicyID %s want %s", got, want)
+			}
+			if u, ok := tok.(*datatypes.UserNameIdentityToken); ok {
+				if got, want := u.EncryptionAlgorithm.Get(), c.alg; got != want {
+					t.Errorf("got EncryptionAlgorithm %s want %s", got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestSessionUserTokenSignature(t *testing.T) {
+	serverCert, _ := newCertificate(t, "server")
+	userCert, userKey := newCertificate(t, "user")
+	nonce := []byte{0x01, 0x02, 0x03, 0x04}
+
+	s := &Session{
+		secChan: &SecureChannel{
+			cfg: &Config{
+				SecurityPolicyURI: "http://opcfoundation.org/UA/SecurityPolicy#None",
+				SecurityMode:      services.SecModeNone,
+			},
+		},
+		cfg: &SessionConfig{
+			UserIdentityToken:   datatypes.NewX509IdentityToken("", string(userCert)),
+			UserTokenSignature:  services.NewSignatureData("", nil),
+			UserTokenPrivateKey: userKey,
+			ServerEndpoints: []*services.EndpointDescription{
+				services.NewEndpointDescription(
+					"opc.tcp://127.0.0.1:4840", nil, serverCert, services.SecModeNone,
+					"http://opcfoundation.org/UA/SecurityPolicy#None",
+					services.NewUserTokenPolicyArray([]*services.UserTokenPolicy{
+						services.NewUserTokenPolicy("anonymous", services.UserTokenAnonymous, "", "", ""),
+						services.NewUserTokenPolicy("certificate", services.UserTokenCertificate, "", "", policyBasic256Sha256),
+					}),
+					"", 0,
+				),
+			},
+			serverNonce: nonce,
+		},
+	}
+
+	tok, sig, err := s.userIdentityToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := tok.(*datatypes.X509IdentityToken).ID(), "certificate"; got != want {
+		t.Errorf("got PolicyID %s want %s", got, want)
+	}
+	if got, want := sig.Algorithm.Get(), "http://www.w3.org/2001/04/xmldsig-more#rsa-sha256"; got != want {
+		t.Errorf("got Algorithm %s want %s", got, want)
+	}
+
+	// the signature is over the certificate and the nonce of the server.
+	verifier, err := securitypolicy.Asymmetric(policyBasic256Sha256, nil, &userKey.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := verifier.VerifySignature(append(append([]byte{}, serverCert...), nonce...), sig.Signature.Get()); err != nil {
+		t.Errorf("invalid signature: %v", err)
+	}
+
+	// the token and the signature are sent in ActivateSessionRequest as they are.
+	b, err := services.NewActivateSessionRequest(
+		services.NewRequestHeader(datatypes.NewTwoByteNodeID(0), time.Time{}, 1, 0, 0, "", services.NewNullAdditionalHeader(), nil),
+		services.NewSignatureData("", nil), nil, tok, sig,
+	).Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	req, err := services.DecodeActivateSessionRequest(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(req.UserIdentityToken.Value, tok); diff != "" {
+		t.Error(diff)
+	}
+	if diff := cmp.Diff(req.UserTokenSignature, sig); diff != "" {
+		t.Error(diff)
+	}
+}
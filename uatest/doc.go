@@ -0,0 +1,23 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+/*
+Package uatest provides an in-process OPC UA server for testing the code using gopcua.Client
+without a real server.
+
+The Server responds to the Read, Write, Browse and the Subscription services with the values,
+the References and the StatusCodes programmed in it. The Client is connected to it with
+the in-process transport returned by Dial, which carries the UACP connection, the SecureChannel
+and the Session as the TCP connection does.
+
+	srv := uatest.NewServer()
+	defer srv.Close()
+	srv.SetValue(datatypes.NewStringNodeID(2, "Temperature"), datatypes.NewVariant(datatypes.NewFloat(21.5)))
+
+	c := gopcua.NewClient(uatest.Endpoint, gopcua.WithTransport(srv.Dial))
+	if err := c.Connect(ctx); err != nil {
+		// ...
+	}
+*/
+package uatest
@@ -0,0 +1,569 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package uatest
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/wmnsk/gopcua/datatypes"
+	"github.com/wmnsk/gopcua/id"
+	"github.com/wmnsk/gopcua/services"
+	"github.com/wmnsk/gopcua/status"
+	"github.com/wmnsk/gopcua/uacp"
+	"github.com/wmnsk/gopcua/uasc"
+)
+
+// Endpoint is the EndpointURL of the Server, which is not listened on the network.
+const Endpoint = "opc.tcp://uatest.invalid:4840/uatest"
+
+// Namespace is the NamespaceURI of the namespace 1 in the NamespaceArray of the Server.
+const Namespace = "urn:gopcua:uatest"
+
+// Server is an in-process OPC UA server which responds to the requests with the canned
+// responses programmed in it, for testing the clients deterministically.
+//
+// The attributes are read with the DataValues set by SetValue and SetAttribute, and
+// the others are BadNodeIdUnknown. The values written are Good and stored for the reads
+// unless SetWriteStatus says otherwise. The References set by SetReferences are returned
+// on Browse. The MonitoredItems created on the attributes are notified of the values
+// set or written, and Notify sends the DataChangeNotifications to the Subscriptions directly.
+//
+// The zero value is not usable. Use NewServer to create one.
+type Server struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mu       sync.Mutex
+	attrs    map[string]*datatypes.DataValue
+	writes   map[string]uint32
+	refs     map[string][]*datatypes.ReferenceDescription
+	results  map[uint16]uint32
+	handler  func(services.Service) services.Service
+	requests []services.Service
+
+	conns  map[net.Conn]bool
+	nextID uint32
+	subs   map[uint32]*session
+	items  []*monitoredItem
+}
+
+// session is a Session of a Client connected to the Server, with the PublishRequests
+// waiting for the notifications and the notifications waiting for the PublishRequests.
+//
+// The responses are queued and written by flush in order, so that the PublishResponses
+// sent while the Client is not reading do not block the Server nor get reordered.
+type session struct {
+	s       *uasc.Session
+	publish []uint32
+	pending []*services.PublishResponse
+	seqs    map[uint32]uint32
+
+	qmu   sync.Mutex
+	queue []services.Service
+	wake  chan struct{}
+}
+
+// monitoredItem is a MonitoredItem created on the Server.
+type monitoredItem struct {
+	sess   *session
+	subID  uint32
+	id     uint32
+	key    string
+	handle uint32
+}
+
+// NewServer creates a new Server with the NamespaceArray of the namespace 0 and Namespace.
+func NewServer() *Server {
+	ctx, cancel := context.WithCancel(context.Background())
+	s := &Server{
+		ctx:     ctx,
+		cancel:  cancel,
+		attrs:   map[string]*datatypes.DataValue{},
+		writes:  map[string]uint32{},
+		refs:    map[string][]*datatypes.ReferenceDescription{},
+		results: map[uint16]uint32{},
+		conns:   map[net.Conn]bool{},
+		subs:    map[uint32]*session{},
+	}
+	s.SetNamespaces("http://opcfoundation.org/UA/", Namespace)
+	return s
+}
+
+// Close disconnects all the Clients connected to the Server, without closing their Sessions.
+func (s *Server) Close() error {
+	s.cancel()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for conn := range s.conns {
+		conn.Close()
+	}
+	return nil
+}
+
+// Dial returns the in-process connection to the Server, which is given to the Client
+// with gopcua.WithTransport. The endpoint is not used.
+func (s *Server) Dial(ctx context.Context, endpoint string) (net.Conn, error) {
+	if err := s.ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	cli, srv := net.Pipe()
+	s.mu.Lock()
+	s.conns[srv] = true
+	s.mu.Unlock()
+	go s.serve(srv)
+	return cli, nil
+}
+
+// key returns the key of the attribute of the node in the Server.
+func key(node *datatypes.NodeID, attr datatypes.AttributeID) string {
+	return fmt.Sprintf("%s/%d", node, attr)
+}
+
+// SetNamespaces sets the NamespaceArray of the Server.
+func (s *Server) SetNamespaces(uris ...string) {
+	values := make([]datatypes.Data, len(uris))
+	for i, uri := range uris {
+		values[i] = datatypes.NewString(uri)
+	}
+	s.SetAttribute(datatypes.NewNumericNodeID(0, id.Server_NamespaceArray), datatypes.AttributeIDValue, NewDataValue(datatypes.NewArrayVariant(id.String, values...)))
+}
+
+// SetValue sets the Value attribute of the node, of which the MonitoredItems are notified.
+func (s *Server) SetValue(node *datatypes.NodeID, v *datatypes.Variant) {
+	s.SetAttribute(node, datatypes.AttributeIDValue, NewDataValue(v))
+}
+
+// SetStatus sets the StatusCode returned by the reads of the attribute of the node.
+func (s *Server) SetStatus(node *datatypes.NodeID, attr datatypes.AttributeID, code uint32) {
+	s.SetAttribute(node, attr, datatypes.NewDataValue(false, true, false, false, false, false, nil, code, time.Time{}, 0, time.Time{}, 0))
+}
+
+// SetAttribute sets the DataValue returned by the reads of the attribute of the node.
+// The MonitoredItems on it are notified of the DataValue.
+func (s *Server) SetAttribute(node *datatypes.NodeID, attr datatypes.AttributeID, dv *datatypes.DataValue) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	k := key(node, attr)
+	s.attrs[k] = dv
+	s.notify(k, dv)
+}
+
+// SetWriteStatus sets the StatusCode returned by the writes of the node,
+// which are not stored if the StatusCode is not Good.
+func (s *Server) SetWriteStatus(node *datatypes.NodeID, code uint32) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.writes[node.String()] = code
+}
+
+// SetReferences sets the References returned by the browse of the node.
+func (s *Server) SetReferences(node *datatypes.NodeID, refs ...*datatypes.ReferenceDescription) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.refs[node.String()] = refs
+}
+
+// SetServiceResult makes the Server respond to the requests of the type, e.g.
+// services.ServiceTypeReadRequest, with the ServiceResult code. Good clears it.
+func (s *Server) SetServiceResult(serviceType uint16, code uint32) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if code == status.Good {
+		delete(s.results, serviceType)
+		return
+	}
+	s.results[serviceType] = code
+}
+
+// Handle sets the handler which is given the requests first. The response it returns
+// is sent instead of the canned one, and nil makes the Server respond as usual.
+//
+// The handler is called without any lock held, so it can call the other methods of the
+// Server, e.g. SetValue.
+func (s *Server) Handle(handler func(services.Service) services.Service) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.handler = handler
+}
+
+// Requests returns the requests received by the Server in order.
+func (s *Server) Requests() []services.Service {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]services.Service{}, s.requests...)
+}
+
+// serve establishes the connection, the SecureChannel and the Session on conn
+// and responds to the requests until the Session is closed.
+func (s *Server) serve(conn net.Conn) {
+	defer func() {
+		conn.Close()
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+	}()
+
+	uconn, err := uacp.AcceptConn(s.ctx, conn, Endpoint, uacp.DefaultLimits)
+	if err != nil {
+		return
+	}
+	cfg := uasc.NewServerConfig(uasc.SecurityPolicyURINone, nil, nil, 1, services.SecModeNone, 1, 3600000)
+	secChan, err := uasc.ListenAndAcceptSecureChannel(s.ctx, uconn, cfg)
+	if err != nil {
+		return
+	}
+	defer secChan.Close()
+	us, err := uasc.ListenAndAcceptSession(s.ctx, secChan, uasc.NewServerSessionConfig(secChan))
+	if err != nil {
+		return
+	}
+
+	sess := &session{s: us, seqs: map[uint32]uint32{}, wake: make(chan struct{}, 1)}
+	defer s.remove(sess)
+	done := make(chan struct{})
+	defer close(done)
+	go sess.flush(done)
+
+	buf := make([]byte, 0xffff)
+	for {
+		n, err := us.ReadService(buf)
+		if err != nil {
+			return
+		}
+		req, err := services.Decode(buf[:n])
+		if err != nil {
+			continue
+		}
+		s.respond(sess, req)
+	}
+}
+
+// remove removes the MonitoredItems of the Session lost.
+func (s *Server) remove(sess *session) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var items []*monitoredItem
+	for _, item := range s.items {
+		if item.sess != sess {
+			items = append(items, item)
+		}
+	}
+	s.items = items
+	for subID, ss := range s.subs {
+		if ss == sess {
+			delete(s.subs, subID)
+		}
+	}
+}
+
+// send queues the response to be written by flush.
+func (sess *session) send(res services.Service) {
+	sess.qmu.Lock()
+	sess.queue = append(sess.queue, res)
+	sess.qmu.Unlock()
+
+	select {
+	case sess.wake <- struct{}{}:
+	default:
+	}
+}
+
+// flush writes the responses queued in order until done is closed or a write fails.
+func (sess *session) flush(done <-chan struct{}) {
+	for {
+		select {
+		case <-done:
+			return
+		case <-sess.wake:
+		}
+
+		for {
+			sess.qmu.Lock()
+			if len(sess.queue) == 0 {
+				sess.qmu.Unlock()
+				break
+			}
+			res := sess.queue[0]
+			sess.queue = sess.queue[1:]
+			sess.qmu.Unlock()
+
+			if err := sess.write(res); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// write sends the response on the Session.
+func (sess *session) write(res services.Service) error {
+	b, err := res.Serialize()
+	if err != nil {
+		return err
+	}
+	_, err = sess.s.WriteService(b)
+	return err
+}
+
+// respond queues the response to req, unless it is not to be responded now.
+//
+// The handler is called without s.mu held, as it may call the methods of the Server.
+// The canned responses are queued with s.mu held, so that the PublishResponses are
+// queued in the order of their SequenceNumbers.
+func (s *Server) respond(sess *session, req services.Service) {
+	s.mu.Lock()
+	s.requests = append(s.requests, req)
+	handler := s.handler
+	s.mu.Unlock()
+
+	header, ok := req.(interface {
+		Header() *services.RequestHeader
+	})
+	if !ok {
+		return
+	}
+	h := header.Header()
+
+	if handler != nil {
+		if res := handler(req); res != nil {
+			sess.send(res)
+			return
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	res := s.canned(sess, req, h.RequestHandle)
+	if res == nil {
+		return
+	}
+	if code, ok := s.results[req.ServiceType()]; ok {
+		if r, ok := res.(interface {
+			Header() *services.ResponseHeader
+		}); ok {
+			r.Header().ServiceResult = code
+		}
+	}
+	sess.send(res)
+}
+
+// canned returns the response to req programmed in the Server.
+//
+// This should be called with s.mu held.
+func (s *Server) canned(sess *session, req services.Service, handle uint32) services.Service {
+	switch r := req.(type) {
+	case *services.ReadRequest:
+		var results []*datatypes.DataValue
+		if r.NodesToRead != nil {
+			for _, v := range r.NodesToRead.ReadValueIDs {
+				results = append(results, s.read(v.NodeID, v.AttributeID))
+			}
+		}
+		return services.NewReadResponse(newResponseHeader(handle), nil, results...)
+
+	case *services.WriteRequest:
+		var results []uint32
+		if r.NodesToWrite != nil {
+			for _, v := range r.NodesToWrite.WriteValues {
+				results = append(results, s.write(v))
+			}
+		}
+		return services.NewWriteResponse(newResponseHeader(handle), nil, results...)
+
+	case *services.BrowseRequest:
+		var results []*datatypes.BrowseResult
+		if r.NodesToBrowse != nil {
+			for _, d := range r.NodesToBrowse.BrowseDescriptions {
+				refs, ok := s.refs[d.NodeID.String()]
+				if !ok {
+					results = append(results, datatypes.NewBrowseResult(status.BadNodeIdUnknown, nil))
+					continue
+				}
+				results = append(results, datatypes.NewBrowseResult(status.Good, nil, refs...))
+			}
+		}
+		return services.NewBrowseResponse(newResponseHeader(handle), nil, results...)
+
+	case *services.CreateSubscriptionRequest:
+		s.nextID++
+		s.subs[s.nextID] = sess
+		return services.NewCreateSubscriptionResponse(
+			newResponseHeader(handle), s.nextID,
+			r.RequestedPublishingInterval, r.RequestedLifetimeCount, r.RequestedMaxKeepAliveCount,
+		)
+
+	case *services.CreateMonitoredItemsRequest:
+		var results []*datatypes.MonitoredItemCreateResult
+		if r.ItemsToCreate != nil {
+			for _, item := range r.ItemsToCreate.Items {
+				s.nextID++
+				m := &monitoredItem{
+					sess:   sess,
+					subID:  r.SubscriptionID,
+					id:     s.nextID,
+					key:    key(item.ItemToMonitor.NodeID, item.ItemToMonitor.AttributeID),
+					handle: item.RequestedParameters.ClientHandle,
+				}
+				s.items = append(s.items, m)
+				results = append(results, datatypes.NewMonitoredItemCreateResult(
+					status.Good, m.id, item.RequestedParameters.SamplingInterval, item.RequestedParameters.QueueSize, nil,
+				))
+			}
+		}
+		return services.NewCreateMonitoredItemsResponse(newResponseHeader(handle), nil, results...)
+
+	case *services.DeleteMonitoredItemsRequest:
+		var results []uint32
+		if r.MonitoredItemIDs != nil {
+			for _, itemID := range r.MonitoredItemIDs.Values {
+				code := uint32(status.BadMonitoredItemIdInvalid)
+				for i, item := range s.items {
+					if item.sess == sess && item.subID == r.SubscriptionID && item.id == itemID {
+						s.items = append(s.items[:i], s.items[i+1:]...)
+						code = status.Good
+						break
+					}
+				}
+				results = append(results, code)
+			}
+		}
+		return services.NewDeleteMonitoredItemsResponse(newResponseHeader(handle), nil, results...)
+
+	case *services.DeleteSubscriptionsRequest:
+		var results []uint32
+		if r.SubscriptionIDs == nil {
+			return services.NewDeleteSubscriptionsResponse(newResponseHeader(handle), nil)
+		}
+		for _, subID := range r.SubscriptionIDs.Values {
+			var items []*monitoredItem
+			for _, item := range s.items {
+				if item.sess != sess || item.subID != subID {
+					items = append(items, item)
+				}
+			}
+			s.items = items
+			if s.subs[subID] != sess {
+				results = append(results, status.BadSubscriptionIdInvalid)
+				continue
+			}
+			delete(s.subs, subID)
+			results = append(results, status.Good)
+		}
+		return services.NewDeleteSubscriptionsResponse(newResponseHeader(handle), nil, results...)
+
+	case *services.PublishRequest:
+		// the PublishRequest is held until a notification is available.
+		sess.publish = append(sess.publish, handle)
+		if len(sess.pending) == 0 {
+			return nil
+		}
+		return sess.next()
+	}
+	return nil
+}
+
+// read returns the DataValue of the attribute of the node.
+//
+// This should be called with s.mu held.
+func (s *Server) read(node *datatypes.NodeID, attr datatypes.AttributeID) *datatypes.DataValue {
+	if dv, ok := s.attrs[key(node, attr)]; ok {
+		return dv
+	}
+	return datatypes.NewDataValue(false, true, false, false, false, false, nil, status.BadNodeIdUnknown, time.Time{}, 0, time.Time{}, 0)
+}
+
+// write stores the value written and returns its StatusCode.
+//
+// This should be called with s.mu held.
+func (s *Server) write(v *datatypes.WriteValue) uint32 {
+	if code, ok := s.writes[v.NodeID.String()]; ok && code != status.Good {
+		return code
+	}
+
+	k := key(v.NodeID, v.AttributeID)
+	s.attrs[k] = v.Value
+	s.notify(k, v.Value)
+	return status.Good
+}
+
+// notify sends the DataChangeNotifications of dv to the MonitoredItems on the attribute k.
+//
+// This should be called with s.mu held.
+func (s *Server) notify(k string, dv *datatypes.DataValue) {
+	for _, item := range s.items {
+		if item.key == k {
+			item.sess.publishData(item.subID, item.handle, dv)
+		}
+	}
+}
+
+// Notify sends the DataChangeNotification of dv with the ClientHandle to the Subscription,
+// as if it had the MonitoredItem with the ClientHandle. It is sent in response to the next
+// PublishRequest of the Session if none is outstanding.
+//
+// It returns false if the Subscription does not exist.
+func (s *Server) Notify(subID, handle uint32, dv *datatypes.DataValue) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sess, ok := s.subs[subID]
+	if !ok {
+		return false
+	}
+	sess.publishData(subID, handle, dv)
+	return true
+}
+
+// publishData sends the DataChangeNotification to the Subscription in the next PublishResponse.
+//
+// This should be called with s.mu of the Server held.
+func (sess *session) publishData(subID, handle uint32, dv *datatypes.DataValue) {
+	sess.seqs[subID]++
+	seq := sess.seqs[subID]
+	msg := datatypes.NewNotificationMessage(
+		seq, time.Now(),
+		datatypes.NewDataChangeNotification(datatypes.NewMonitoredItemNotification(handle, dv)),
+	)
+	sess.pending = append(sess.pending, services.NewPublishResponse(newResponseHeader(0), subID, []uint32{seq}, false, msg, nil))
+
+	if len(sess.publish) > 0 {
+		sess.send(sess.next())
+	}
+}
+
+// next returns the first notification pending as the response to the first PublishRequest held.
+func (sess *session) next() *services.PublishResponse {
+	res := sess.pending[0]
+	sess.pending = sess.pending[1:]
+	res.ResponseHeader.RequestHandle = sess.publish[0]
+	sess.publish = sess.publish[1:]
+	return res
+}
+
+// NewDataValue returns the Good DataValue of v, as set by SetValue.
+func NewDataValue(v *datatypes.Variant) *datatypes.DataValue {
+	return datatypes.NewDataValue(true, false, false, false, false, false, v, 0, time.Time{}, 0, time.Time{}, 0)
+}
+
+// newResponseHeader returns the Good ResponseHeader for the request with the handle.
+func newResponseHeader(handle uint32) *services.ResponseHeader {
+	return services.NewResponseHeader(
+		time.Now(), handle, 0, services.NewNullDiagnosticInfo(),
+		[]string{}, services.NewNullAdditionalHeader(), nil,
+	)
+}
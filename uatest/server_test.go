@@ -0,0 +1,264 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package uatest_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/wmnsk/gopcua"
+	"github.com/wmnsk/gopcua/datatypes"
+	"github.com/wmnsk/gopcua/services"
+	"github.com/wmnsk/gopcua/status"
+	"github.com/wmnsk/gopcua/uatest"
+)
+
+// connect returns the Client connected to srv, which monitors the connection until ctx is done.
+func connect(ctx context.Context, t *testing.T, srv *uatest.Server) *gopcua.Client {
+	t.Helper()
+
+	c := gopcua.NewClient(uatest.Endpoint, gopcua.WithTransport(srv.Dial))
+	if err := c.Connect(ctx); err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func TestServerRead(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	srv := uatest.NewServer()
+	defer srv.Close()
+
+	temp := datatypes.NewStringNodeID(2, "Temperature")
+	srv.SetValue(temp, datatypes.NewVariant(datatypes.NewFloat(21.5)))
+	pressure := datatypes.NewStringNodeID(2, "Pressure")
+	srv.SetStatus(pressure, datatypes.AttributeIDValue, status.BadNotReadable)
+
+	c := connect(ctx, t, srv)
+	defer c.Close()
+
+	if diff := cmp.Diff(c.Namespaces(), []string{"http://opcfoundation.org/UA/", uatest.Namespace}); diff != "" {
+		t.Error(diff)
+	}
+
+	res, err := c.Read(0, services.TimestampsToReturnNeither,
+		datatypes.NewReadValueID(temp, datatypes.AttributeIDValue, "", 0, ""),
+		datatypes.NewReadValueID(pressure, datatypes.AttributeIDValue, "", 0, ""),
+		datatypes.NewReadValueID(datatypes.NewStringNodeID(2, "Unknown"), datatypes.AttributeIDValue, "", 0, ""),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dvs := res.Results.DataValues
+	if len(dvs) != 3 {
+		t.Fatalf("got %d results want 3", len(dvs))
+	}
+	if diff := cmp.Diff(dvs[0].Value, datatypes.NewVariant(datatypes.NewFloat(21.5))); diff != "" {
+		t.Error(diff)
+	}
+	for i, want := range []uint32{status.Good, status.BadNotReadable, status.BadNodeIdUnknown} {
+		if got := dvs[i].Status; got != want {
+			t.Errorf("result %d: got status %v want %v", i, status.StatusCode(got), status.StatusCode(want))
+		}
+	}
+
+	// the requests are recorded in order.
+	reqs := srv.Requests()
+	if _, ok := reqs[len(reqs)-1].(*services.ReadRequest); !ok {
+		t.Errorf("got last request %T want *services.ReadRequest", reqs[len(reqs)-1])
+	}
+}
+
+func TestServerServiceResult(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	srv := uatest.NewServer()
+	defer srv.Close()
+
+	c := connect(ctx, t, srv)
+	defer c.Close()
+
+	srv.SetServiceResult(services.ServiceTypeReadRequest, status.BadTooManyOperations)
+	node := datatypes.NewReadValueID(datatypes.NewStringNodeID(2, "Temperature"), datatypes.AttributeIDValue, "", 0, "")
+	if _, err := c.Read(0, services.TimestampsToReturnNeither, node); err == nil {
+		t.Error("expected error for the ServiceResult, got nil")
+	}
+
+	srv.SetServiceResult(services.ServiceTypeReadRequest, status.Good)
+	if _, err := c.Read(0, services.TimestampsToReturnNeither, node); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestServerWrite(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	srv := uatest.NewServer()
+	defer srv.Close()
+
+	setpoint := datatypes.NewStringNodeID(2, "Setpoint")
+	locked := datatypes.NewStringNodeID(2, "Locked")
+	srv.SetWriteStatus(locked, status.BadNotWritable)
+
+	c := connect(ctx, t, srv)
+	defer c.Close()
+
+	if code, err := c.WriteValue(setpoint, datatypes.NewVariant(datatypes.NewFloat(25))); err != nil || code != status.Good {
+		t.Errorf("got %v, %v want Good", status.StatusCode(code), err)
+	}
+	if code, err := c.WriteValue(locked, datatypes.NewVariant(datatypes.NewFloat(25))); err != nil || code != status.BadNotWritable {
+		t.Errorf("got %v, %v want BadNotWritable", status.StatusCode(code), err)
+	}
+
+	// the value written is read back.
+	v, err := c.Node(setpoint).Value()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(v, datatypes.NewVariant(datatypes.NewFloat(25))); diff != "" {
+		t.Error(diff)
+	}
+}
+
+func TestServerBrowse(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	srv := uatest.NewServer()
+	defer srv.Close()
+
+	folder := datatypes.NewStringNodeID(2, "Folder")
+	ref := datatypes.NewReferenceDescription(
+		datatypes.NewFourByteNodeID(0, 35), true,
+		datatypes.NewExpandedNodeID(false, false, datatypes.NewStringNodeID(2, "Temperature"), "", 0),
+		datatypes.NewQualifiedName(2, "Temperature"), datatypes.NewLocalizedText("", "Temperature"),
+		datatypes.NodeClassVariable, datatypes.NewTwoByteExpandedNodeID(63),
+	)
+	srv.SetReferences(folder, ref)
+
+	c := connect(ctx, t, srv)
+	defer c.Close()
+
+	refs, err := c.BrowseChildren(folder)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(refs, []*datatypes.ReferenceDescription{ref}); diff != "" {
+		t.Error(diff)
+	}
+
+	if _, err := c.BrowseChildren(datatypes.NewStringNodeID(2, "Unknown")); err == nil {
+		t.Error("expected error for unknown node, got nil")
+	}
+}
+
+func TestServerSubscription(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	srv := uatest.NewServer()
+	defer srv.Close()
+
+	c := connect(ctx, t, srv)
+	defer c.Close()
+
+	sub, err := c.CreateSubscription(100, 60, 20, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if srv.Notify(sub.ID+1, 42, uatest.NewDataValue(datatypes.NewVariant(datatypes.NewFloat(0)))) {
+		t.Error("the unknown Subscription should not be notified")
+	}
+	if !srv.Notify(sub.ID, 42, uatest.NewDataValue(datatypes.NewVariant(datatypes.NewFloat(21.5)))) {
+		t.Fatal("the Subscription is not notified")
+	}
+
+	select {
+	case msg := <-sub.Notifs():
+		n, ok := msg.NotificationData.ExtensionObjects[0].Value.(*datatypes.DataChangeNotification)
+		if !ok {
+			t.Fatalf("got %T want *datatypes.DataChangeNotification", msg.NotificationData.ExtensionObjects[0].Value)
+		}
+		item := n.MonitoredItems.Notifications[0]
+		if item.ClientHandle != 42 {
+			t.Errorf("got ClientHandle %d want 42", item.ClientHandle)
+		}
+		if diff := cmp.Diff(item.Value.Value, datatypes.NewVariant(datatypes.NewFloat(21.5))); diff != "" {
+			t.Error(diff)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("timed out")
+	}
+}
+
+func TestServerSubscriptionOrder(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	srv := uatest.NewServer()
+	defer srv.Close()
+
+	c := connect(ctx, t, srv)
+	defer c.Close()
+
+	sub, err := c.CreateSubscription(100, 60, 20, 0, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the notifications are delivered in the order notified.
+	const n = 20
+	for i := 0; i < n; i++ {
+		if !srv.Notify(sub.ID, uint32(i), uatest.NewDataValue(datatypes.NewVariant(datatypes.NewFloat(float32(i))))) {
+			t.Fatal("the Subscription is not notified")
+		}
+	}
+	for i := 0; i < n; i++ {
+		select {
+		case msg := <-sub.Notifs():
+			if msg.SequenceNumber != uint32(i+1) {
+				t.Fatalf("got SequenceNumber %d want %d", msg.SequenceNumber, i+1)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timed out waiting for notification %d", i)
+		}
+	}
+}
+
+func TestServerHandle(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	srv := uatest.NewServer()
+	defer srv.Close()
+
+	// the handler can program the Server before the canned response is made.
+	temp := datatypes.NewStringNodeID(2, "Temperature")
+	srv.Handle(func(req services.Service) services.Service {
+		if _, ok := req.(*services.ReadRequest); ok {
+			srv.SetValue(temp, datatypes.NewVariant(datatypes.NewFloat(21.5)))
+		}
+		return nil
+	})
+
+	c := connect(ctx, t, srv)
+	defer c.Close()
+
+	res, err := c.Read(0, services.TimestampsToReturnNeither, datatypes.NewReadValueID(temp, datatypes.AttributeIDValue, "", 0, ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(res.Results.DataValues[0].Value, datatypes.NewVariant(datatypes.NewFloat(21.5))); diff != "" {
+		t.Error(diff)
+	}
+}
@@ -0,0 +1,49 @@
+package codectest
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/pascaldekloe/goe/verify"
+)
+
+// CheckSerialize tests that v serializes into exactly v.Len() bytes and
+// that the bytes decode back into a value equal to v.
+//
+// If v also implements SerializeTo, it is checked that SerializeTo does not
+// write beyond v.Len() bytes of a larger buffer.
+func CheckSerialize(t *testing.T, v S, decode DecoderFunc) {
+	t.Helper()
+
+	b, err := v.Serialize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := len(b), v.Len(); got != want {
+		t.Fatalf("serialized %d bytes but Len() is %d", got, want)
+	}
+
+	if s, ok := v.(interface {
+		SerializeTo([]byte) error
+	}); ok {
+		const pad = 8
+		buf := bytes.Repeat([]byte{0xff}, len(b)+pad)
+		if err := s.SerializeTo(buf); err != nil {
+			t.Fatal(err)
+		}
+		if got, want := buf[len(b):], bytes.Repeat([]byte{0xff}, pad); !bytes.Equal(got, want) {
+			t.Fatalf("SerializeTo wrote beyond Len(): %x", buf)
+		}
+		if got, want := buf[:len(b)], b; !bytes.Equal(got, want) {
+			t.Fatalf("SerializeTo got %x want %x", got, want)
+		}
+	}
+
+	got, err := decode(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !verify.Values(t, "", got, v) {
+		t.Fail()
+	}
+}
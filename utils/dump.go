@@ -0,0 +1,227 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/wmnsk/gopcua/datatypes"
+	"github.com/wmnsk/gopcua/status"
+)
+
+// maxDumpDepth is the depth of the nested values rendered by Dump,
+// which is more than enough for the messages defined in the specification.
+const maxDumpDepth = 32
+
+// Dump renders msg, typically a message decoded into a services.Service, in a human-readable
+// form with a field per line, to be logged when debugging.
+//
+// The NodeIDs, QualifiedNames, LocalizedTexts and the other built-in types are rendered
+// with their String methods, and the StatusCodes with their names, e.g. "BadNodeIdUnknown".
+// The arrays are rendered as the lists of their elements, and the ExtensionObjects as the
+// values decoded with their TypeIDs. The EncodingMasks are rendered in hexadecimal.
+// The raw bytes kept in the Payload of the headers are not rendered.
+func Dump(msg interface{}) string {
+	var buf bytes.Buffer
+	dump(&buf, reflect.ValueOf(msg), "", 0)
+	return buf.String()
+}
+
+// isStatusField reports whether the uint32 field name holds a StatusCode.
+func isStatusField(name string) bool {
+	switch name {
+	case "Status", "ServiceResult", "InnerStatusCode":
+		return true
+	}
+	return strings.HasSuffix(name, "StatusCode")
+}
+
+// isStatusArrayField reports whether the Uint32Array field name holds the StatusCodes,
+// e.g. the Results of WriteResponse.
+func isStatusArrayField(name string) bool {
+	return strings.HasSuffix(name, "Results")
+}
+
+// dump writes v, the value of the field name if any, to buf with the indent of depth.
+func dump(buf *bytes.Buffer, v reflect.Value, name string, depth int) {
+	if depth > maxDumpDepth {
+		buf.WriteString("...")
+		return
+	}
+	if !v.IsValid() {
+		buf.WriteString("nil")
+		return
+	}
+	if (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && v.IsNil() {
+		buf.WriteString("nil")
+		return
+	}
+	if v.Kind() == reflect.Interface {
+		v = v.Elem()
+	}
+
+	if v.CanInterface() {
+		switch x := v.Interface().(type) {
+		case *datatypes.NodeID, *datatypes.ExpandedNodeID, *datatypes.QualifiedName,
+			*datatypes.LocalizedText, *datatypes.String, *datatypes.GUID, datatypes.AttributeID,
+			status.StatusCode:
+			buf.WriteString(x.(fmt.Stringer).String())
+			return
+		case *datatypes.Boolean:
+			fmt.Fprintf(buf, "%t", x.Value != 0)
+			return
+		case time.Time:
+			if x.IsZero() {
+				buf.WriteString("0")
+				return
+			}
+			buf.WriteString(x.UTC().Format(time.RFC3339Nano))
+			return
+		case []byte:
+			if x == nil {
+				buf.WriteString("null")
+				return
+			}
+			fmt.Fprintf(buf, "0x%x", x)
+			return
+		case uint8:
+			if name == "EncodingMask" {
+				fmt.Fprintf(buf, "0x%02x", x)
+				return
+			}
+		case uint32:
+			if isStatusField(name) {
+				buf.WriteString(status.StatusCode(x).String())
+				return
+			}
+		case *datatypes.Uint32Array:
+			if isStatusArrayField(name) {
+				codes := make([]status.StatusCode, len(x.Values))
+				for i, c := range x.Values {
+					codes[i] = status.StatusCode(c)
+				}
+				dump(buf, reflect.ValueOf(codes), name, depth)
+				return
+			}
+		case *datatypes.Variant:
+			dumpVariant(buf, x, depth)
+			return
+		case *datatypes.ExtensionObject:
+			fmt.Fprintf(buf, "ExtensionObject(%s) ", x.TypeID)
+			dump(buf, reflect.ValueOf(x.Value), "", depth)
+			return
+		}
+	}
+
+	switch v.Kind() {
+	case reflect.Ptr:
+		dump(buf, v.Elem(), name, depth)
+	case reflect.Struct:
+		dumpStruct(buf, v, depth)
+	case reflect.Slice, reflect.Array:
+		if v.Len() == 0 {
+			buf.WriteString("[]")
+			return
+		}
+		buf.WriteString("[\n")
+		for i := 0; i < v.Len(); i++ {
+			indent(buf, depth+1)
+			fmt.Fprintf(buf, "%d: ", i)
+			dump(buf, v.Index(i), name, depth+1)
+			buf.WriteString("\n")
+		}
+		indent(buf, depth)
+		buf.WriteString("]")
+	case reflect.String:
+		fmt.Fprintf(buf, "%q", v.String())
+	default:
+		if v.CanInterface() {
+			fmt.Fprintf(buf, "%v", v.Interface())
+			return
+		}
+		fmt.Fprintf(buf, "%v", v)
+	}
+}
+
+// dumpStruct writes the exported fields of the struct v.
+//
+// The wrappers of a single value, such as Int32, are rendered as the value, and the
+// arrays which have the ArraySize and the elements, such as NodeIDArray, as the elements.
+func dumpStruct(buf *bytes.Buffer, v reflect.Value, depth int) {
+	t := v.Type()
+
+	var fields []int
+	var size, elems = -1, -1
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.PkgPath != "" || f.Name == "Payload" {
+			continue
+		}
+		fields = append(fields, i)
+		switch {
+		case f.Name == "ArraySize":
+			size = i
+		case f.Type.Kind() == reflect.Slice:
+			elems = i
+		}
+	}
+	if len(fields) == 1 && t.Field(fields[0]).Name == "Value" {
+		dump(buf, v.Field(fields[0]), "Value", depth)
+		return
+	}
+	if len(fields) == 2 && size >= 0 && elems >= 0 {
+		dump(buf, v.Field(elems), t.Field(elems).Name, depth)
+		return
+	}
+
+	fmt.Fprintf(buf, "%s{\n", t.Name())
+	for _, i := range fields {
+		indent(buf, depth+1)
+		fmt.Fprintf(buf, "%s: ", t.Field(i).Name)
+		dump(buf, v.Field(i), t.Field(i).Name, depth+1)
+		buf.WriteString("\n")
+	}
+	indent(buf, depth)
+	buf.WriteString("}")
+}
+
+// dumpVariant writes the value of the Variant v with its built-in type.
+func dumpVariant(buf *bytes.Buffer, v *datatypes.Variant, depth int) {
+	fmt.Fprintf(buf, "Variant(%s) ", typeName(v.Type()))
+	if v.HasArrayValues() {
+		dump(buf, reflect.ValueOf(v.Values), "", depth)
+		return
+	}
+	dump(buf, reflect.ValueOf(v.Value), "", depth)
+}
+
+// typeName returns the name of the built-in type typ, or the identifier if it is unknown.
+func typeName(typ uint8) string {
+	if name, ok := builtinTypeNames[typ]; ok {
+		return name
+	}
+	return fmt.Sprintf("%d", typ)
+}
+
+// builtinTypeNames are the names of the built-in types by their identifiers.
+//
+// Specification: Part 6, 5.1.2
+var builtinTypeNames = map[uint8]string{
+	0: "Null", 1: "Boolean", 2: "SByte", 3: "Byte", 4: "Int16", 5: "UInt16",
+	6: "Int32", 7: "UInt32", 8: "Int64", 9: "UInt64", 10: "Float", 11: "Double",
+	12: "String", 13: "DateTime", 14: "Guid", 15: "ByteString", 16: "XmlElement",
+	17: "NodeId", 18: "ExpandedNodeId", 19: "StatusCode", 20: "QualifiedName",
+	21: "LocalizedText", 22: "ExtensionObject", 23: "DataValue", 24: "Variant",
+	25: "DiagnosticInfo",
+}
+
+// indent writes the indent of depth.
+func indent(buf *bytes.Buffer, depth int) {
+	buf.WriteString(strings.Repeat("  ", depth))
+}
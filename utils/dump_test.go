@@ -0,0 +1,80 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package utils
+
+import (
+	"testing"
+	"time"
+
+	"github.com/wmnsk/gopcua/datatypes"
+	"github.com/wmnsk/gopcua/services"
+	"github.com/wmnsk/gopcua/status"
+)
+
+func TestDump(t *testing.T) {
+	res := services.NewReadResponse(
+		services.NewResponseHeader(
+			time.Date(2018, time.August, 10, 23, 0, 0, 0, time.UTC),
+			1, 0, services.NewNullDiagnosticInfo(), []string{}, services.NewNullAdditionalHeader(), nil,
+		),
+		nil,
+		datatypes.NewDataValue(
+			true, false, false, false, false, false,
+			datatypes.NewVariant(datatypes.NewInt32(42)), 0, time.Time{}, 0, time.Time{}, 0,
+		),
+		datatypes.NewDataValue(
+			false, true, false, false, false, false,
+			nil, status.BadNodeIdUnknown, time.Time{}, 0, time.Time{}, 0,
+		),
+	)
+
+	want := `ReadResponse{
+  TypeID: i=634
+  ResponseHeader: ResponseHeader{
+    Timestamp: 2018-08-10T23:00:00Z
+    RequestHandle: 1
+    ServiceResult: Good (0x00000000)
+    ServiceDiagnostics: DiagnosticInfo{
+      EncodingMask: 0x00
+      SymbolicID: 0
+      NamespaceURI: 0
+      Locale: 0
+      LocalizedText: 0
+      AdditionalInfo: nil
+      InnerStatusCode: Good (0x00000000)
+      InnerDiagnosticInfo: nil
+    }
+    StringTable: []
+    AdditionalHeader: AdditionalHeader{
+      TypeID: i=0
+      EncodingMask: 0x00
+    }
+  }
+  Results: [
+    0: DataValue{
+      EncodingMask: 0x01
+      Value: Variant(Int32) 42
+      Status: Good (0x00000000)
+      SourceTimestamp: 0
+      SourcePicoSeconds: 0
+      ServerTimestamp: 0
+      ServerPicoSeconds: 0
+    }
+    1: DataValue{
+      EncodingMask: 0x02
+      Value: nil
+      Status: BadNodeIdUnknown (0x80340000)
+      SourceTimestamp: 0
+      SourcePicoSeconds: 0
+      ServerTimestamp: 0
+      ServerPicoSeconds: 0
+    }
+  ]
+  DiagnosticInfos: []
+}`
+	if got := Dump(res); got != want {
+		t.Errorf("got:\n%s\nwant:\n%s", got, want)
+	}
+}
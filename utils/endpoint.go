@@ -14,19 +14,15 @@ import (
 
 // ResolveEndpoint returns network type, address, and error splitted from EndpointURL.
 //
-// Expected format of input is "opc.tcp://<addr[:port]/path/to/somewhere"
+// Expected format of input is "opc.tcp://<addr[:port]/path/to/somewhere".
+// The schemes "opc.ws" and "opc.wss" of the WebSocket transport are also accepted,
+// of which the default ports are 80 and 443 respectively instead of 4840.
 func ResolveEndpoint(endpoint string) (network string, addr *net.TCPAddr, err error) {
-	elems := strings.Split(endpoint, "/")
-	if elems[0] != "opc.tcp:" {
-		return "", nil, errors.NewErrUnsupported(elems[0], "should be in \"opc.tcp://<addr[:port]>/path/to/somewhere\" format.")
-	}
-
-	addrString := elems[2]
-	if !strings.Contains(addrString, ":") {
-		addrString += ":4840"
+	network, addrString, err := EndpointAddress(endpoint)
+	if err != nil {
+		return "", nil, err
 	}
 
-	network = "tcp"
 	addr, err = net.ResolveTCPAddr(network, addrString)
 	switch err.(type) {
 	case *net.DNSError:
@@ -35,6 +31,29 @@ func ResolveEndpoint(endpoint string) (network string, addr *net.TCPAddr, err er
 	return
 }
 
+// EndpointAddress is ResolveEndpoint but returns the address in "host:port" format
+// without resolving the host, e.g. to be resolved by the proxy it is connected through.
+func EndpointAddress(endpoint string) (network, addr string, err error) {
+	elems := strings.Split(endpoint, "/")
+	port, ok := defaultPorts[elems[0]]
+	if !ok || len(elems) < 3 {
+		return "", "", errors.NewErrUnsupported(elems[0], "should be in \"opc.tcp://<addr[:port]>/path/to/somewhere\" format.")
+	}
+
+	addr = elems[2]
+	if !strings.Contains(addr, ":") {
+		addr += port
+	}
+	return "tcp", addr, nil
+}
+
+// defaultPorts are the ports used when the EndpointURL has none, by its scheme.
+var defaultPorts = map[string]string{
+	"opc.tcp:": ":4840",
+	"opc.ws:":  ":80",
+	"opc.wss:": ":443",
+}
+
 // GetPath returns the path that follows after address[:port] in EndpointURL.
 //
 // Expected format of input is "opc.tcp://<addr[:port]/path/to/somewhere"
@@ -45,6 +45,24 @@ func TestResolveEndpoint(t *testing.T) {
 			},
 			"",
 		},
+		{ // Valid, WebSocket with port number omitted
+			"opc.wss://10.0.0.1/foo/bar",
+			"tcp",
+			&net.TCPAddr{
+				IP:   net.IP([]byte{0x0a, 0x00, 0x00, 0x01}),
+				Port: 443,
+			},
+			"",
+		},
+		{ // Valid, WebSocket without TLS
+			"opc.ws://10.0.0.1:8080/foo/bar",
+			"tcp",
+			&net.TCPAddr{
+				IP:   net.IP([]byte{0x0a, 0x00, 0x00, 0x01}),
+				Port: 8080,
+			},
+			"",
+		},
 		{ // Invalid, schema is not "opc.tcp://"
 			"tcp://10.0.0.1:4840/foo/bar",
 			"",
@@ -77,6 +95,34 @@ func TestResolveEndpoint(t *testing.T) {
 	}
 }
 
+func TestEndpointAddress(t *testing.T) {
+	cases := []struct {
+		input string
+		addr  string
+		err   bool
+	}{
+		{"opc.tcp://10.0.0.1:4841/foo/bar", "10.0.0.1:4841", false},
+		{"opc.tcp://plc.example.com/foo/bar", "plc.example.com:4840", false},
+		{"opc.wss://plc.example.com", "plc.example.com:443", false},
+		{"tcp://10.0.0.1:4840/foo/bar", "", true},
+		{"opc.tcp:", "", true},
+	}
+
+	for _, c := range cases {
+		network, addr, err := EndpointAddress(c.input)
+		if got, want := err != nil, c.err; got != want {
+			t.Errorf("%s: got error %v want error %v", c.input, err, want)
+			continue
+		}
+		if addr != c.addr {
+			t.Errorf("%s: got %s want %s", c.input, addr, c.addr)
+		}
+		if !c.err && network != "tcp" {
+			t.Errorf("%s: got network %s want tcp", c.input, network)
+		}
+	}
+}
+
 func TestGetPath(t *testing.T) {
 	cases := []struct {
 		input  string
@@ -7,17 +7,22 @@ package utils
 import (
 	"encoding/binary"
 	"time"
+
+	"github.com/wmnsk/gopcua/datatypes"
 )
 
 // EncodeTimestamp serializes time.Time into given bytes buffer
 // in "100 nanosecond intervals since January 1, 1601" manner.
+//
+// Deprecated: Use datatypes.ToDateTime, which EncodeTimestamp is the same as.
 func EncodeTimestamp(b []byte, t time.Time) {
-	binary.LittleEndian.PutUint64(b, uint64(t.UTC().UnixNano()/100+116444736000000000))
+	binary.LittleEndian.PutUint64(b, uint64(datatypes.ToDateTime(t)))
 }
 
 // DecodeTimestamp decodes given bytes into time.Time
 // in "100 nanosecond intervals since January 1, 1601" manner.
+//
+// Deprecated: Use datatypes.FromDateTime, which DecodeTimestamp is the same as.
 func DecodeTimestamp(b []byte) time.Time {
-	t := binary.LittleEndian.Uint64(b[:8])
-	return time.Unix(0, int64((t-116444736000000000)*100)).UTC()
+	return datatypes.FromDateTime(int64(binary.LittleEndian.Uint64(b[:8])))
 }
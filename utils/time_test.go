@@ -5,6 +5,7 @@
 package utils
 
 import (
+	"encoding/binary"
 	"testing"
 	"time"
 )
@@ -37,3 +38,32 @@ func TestEncodeTime(t *testing.T) {
 	}
 	t.Logf("%x", serialized)
 }
+
+func TestTimestampRoundTrip(t *testing.T) {
+	cases := []struct {
+		name string
+		ts   time.Time
+	}{
+		{"zero", time.Time{}},
+		{"before-unix-epoch", time.Date(1900, time.March, 1, 12, 30, 0, 123456700, time.UTC)},
+		{"after-unixnano-range", time.Date(2500, time.December, 31, 23, 59, 59, 999999900, time.UTC)},
+		{"100ns", time.Date(2018, time.August, 10, 23, 0, 0, 100, time.UTC)},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			b := make([]byte, 8)
+			EncodeTimestamp(b, c.ts)
+			if got := DecodeTimestamp(b); !got.Equal(c.ts) {
+				t.Errorf("got %v want %v", got, c.ts)
+			}
+		})
+	}
+
+	t.Run("before-1601", func(t *testing.T) {
+		b := make([]byte, 8)
+		EncodeTimestamp(b, time.Date(1600, time.December, 31, 0, 0, 0, 0, time.UTC))
+		if got := binary.LittleEndian.Uint64(b); got != 0 {
+			t.Errorf("got %d want 0", got)
+		}
+	})
+}
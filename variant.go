@@ -0,0 +1,14 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package gopcua
+
+import "github.com/wmnsk/gopcua/datatypes"
+
+// NewVariant creates a new Variant from the Go value v, inferring the built-in type,
+// to be written with WriteValue. It is the same as datatypes.NewVariantFrom, which is
+// named so as datatypes.NewVariant takes the Data of the built-in types.
+func NewVariant(v interface{}) (*datatypes.Variant, error) {
+	return datatypes.NewVariantFrom(v)
+}
@@ -0,0 +1,29 @@
+// Copyright 2018 gopcua authors. All rights reserved.
+// Use of this source code is governed by a MIT-style license that can be
+// found in the LICENSE file.
+
+package gopcua
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/wmnsk/gopcua/datatypes"
+)
+
+func TestNewVariant(t *testing.T) {
+	got, err := NewVariant([]float64{1.5, 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := datatypes.NewArrayVariant(datatypes.NewDouble(0).DataType(), datatypes.NewDouble(1.5), datatypes.NewDouble(2))
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Error(diff)
+	}
+
+	if _, err := NewVariant(time.Second); err == nil {
+		t.Error("expected error for time.Duration, got nil")
+	}
+}